@@ -0,0 +1,136 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buildinfo describes the build of the service the components run
+// in, so that they can identify it, e.g. in the user agent of the requests of
+// the exporters.
+package buildinfo
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/open-telemetry/opentelemetry-service/internal/version"
+)
+
+// BuildInfo is the build of the service.
+type BuildInfo struct {
+	// Command is the name of the executable, e.g. "otelsvc".
+	Command string
+
+	// Version of the distribution.
+	Version string
+
+	// GitHash of the source the distribution was built from.
+	GitHash string
+
+	// BuildDate is when the distribution was built, empty if unknown.
+	BuildDate string
+}
+
+// Default returns the build of otelsvc, set at link time by make.
+func Default() BuildInfo {
+	return BuildInfo{
+		Command:   "otelsvc",
+		Version:   version.Version,
+		GitHash:   version.GitHash,
+		BuildDate: version.BuildDate,
+	}
+}
+
+// UserAgent returns the user agent of the requests sent by the service, e.g.
+// "otelsvc/v0.2.0 (linux/amd64)".
+func (bi BuildInfo) UserAgent() string {
+	return fmt.Sprintf("%s/%s (%s/%s)", bi.Command, bi.Version, runtime.GOOS, runtime.GOARCH)
+}
+
+// Info returns a formatted string, with linebreaks, intended to be displayed
+// on stdout.
+func (bi BuildInfo) Info() string {
+	buf := new(bytes.Buffer)
+	rows := [][2]string{
+		{"Command", bi.Command},
+		{"Version", bi.Version},
+		{"GitHash", bi.GitHash},
+		{"BuildDate", bi.BuildDate},
+		{"Goversion", runtime.Version()},
+		{"OS", runtime.GOOS},
+		{"Architecture", runtime.GOARCH},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(buf, "%-12s %s\n", row[0], row[1])
+	}
+	return buf.String()
+}
+
+// User is a receiver, processor or exporter factory using the build of the
+// service. The service gives it the build before creating any component.
+type User interface {
+	SetBuildInfo(bi BuildInfo)
+}
+
+// Holder can be embedded in the factories to implement User. The build is
+// the default one until it is set.
+type Holder struct {
+	bi *BuildInfo
+}
+
+var _ User = (*Holder)(nil)
+
+// SetBuildInfo sets the build of the service.
+func (h *Holder) SetBuildInfo(bi BuildInfo) {
+	h.bi = &bi
+}
+
+// BuildInfo returns the build of the service, the default one if it was not
+// set.
+func (h *Holder) BuildInfo() BuildInfo {
+	if h.bi == nil {
+		return Default()
+	}
+	return *h.bi
+}
+
+// userAgentTransport sets the user agent of the requests that have none.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+// NewUserAgentTransport returns a transport setting the user agent of the
+// requests that have none before sending them with next, the default
+// transport if nil.
+func NewUserAgentTransport(userAgent string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &userAgentTransport{userAgent: userAgent, next: next}
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request, the headers are copied.
+	withUserAgent := new(http.Request)
+	*withUserAgent = *req
+	withUserAgent.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		withUserAgent.Header[k] = v
+	}
+	withUserAgent.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(withUserAgent)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildinfo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgent(t *testing.T) {
+	bi := BuildInfo{Command: "mysvc", Version: "1.2.3"}
+	assert.Equal(t, "mysvc/1.2.3 ("+runtime.GOOS+"/"+runtime.GOARCH+")", bi.UserAgent())
+}
+
+func TestInfo(t *testing.T) {
+	info := BuildInfo{Command: "mysvc", Version: "1.2.3", GitHash: "abcdef", BuildDate: "2019-10-01T00:00:00Z"}.Info()
+	assert.Contains(t, info, "Version      1.2.3\n")
+	assert.Contains(t, info, "GitHash      abcdef\n")
+	assert.Contains(t, info, "BuildDate    2019-10-01T00:00:00Z\n")
+}
+
+func TestHolder(t *testing.T) {
+	var h Holder
+	assert.Equal(t, Default(), h.BuildInfo())
+
+	bi := BuildInfo{Command: "mysvc", Version: "1.2.3"}
+	var user User = &h
+	user.SetBuildInfo(bi)
+	assert.Equal(t, bi, h.BuildInfo())
+}
+
+func TestUserAgentTransport(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewUserAgentTransport("mysvc/1.2.3", nil)}
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("data"))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	// The request is not modified.
+	assert.Empty(t, req.Header.Get("User-Agent"))
+
+	// The user agent of the request is kept.
+	req, err = http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "custom")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"mysvc/1.2.3", "custom"}, userAgents)
+}
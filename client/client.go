@@ -0,0 +1,126 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client stores in the context the information about the client that
+// sent the data to a receiver, for the processors that depend on it and the
+// exporters forwarding its metadata.
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type ctxKey struct{}
+
+// Client is the client that sent the data.
+type Client struct {
+	// IP is the IP address of the client, without the port.
+	IP string
+
+	// Metadata holds the metadata, e.g. the gRPC or HTTP headers, sent with
+	// the data. The keys are lower case.
+	Metadata map[string][]string
+}
+
+// NewContext returns a copy of the context carrying the client.
+func NewContext(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, ctxKey{}, c)
+}
+
+// FromContext returns the client stored in the context, if any.
+func FromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(ctxKey{}).(*Client)
+	return c, ok
+}
+
+// FromGRPC returns the client of the gRPC call of the context, with the
+// incoming metadata of the call.
+func FromGRPC(ctx context.Context) (*Client, bool) {
+	c := &Client{}
+	if p, ok := peer.FromContext(ctx); ok {
+		c.IP = ipFromAddr(p.Addr)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md) > 0 {
+		c.Metadata = md
+	}
+	if c.IP == "" && c.Metadata == nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// FromHTTP returns the client of the HTTP request, with the headers of the
+// request as metadata.
+func FromHTTP(r *http.Request) (*Client, bool) {
+	c := &Client{}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		c.IP = host
+	}
+	if len(r.Header) > 0 {
+		c.Metadata = make(map[string][]string, len(r.Header))
+		for k, v := range r.Header {
+			c.Metadata[strings.ToLower(k)] = v
+		}
+	}
+	if c.IP == "" && c.Metadata == nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// ForwardedMetadata returns the values of the given metadata keys of the
+// client stored in the context, for the exporters sending them as headers of
+// their requests. It returns nil if there is no client or none of the keys.
+func ForwardedMetadata(ctx context.Context, keys []string) map[string][]string {
+	c, ok := FromContext(ctx)
+	if !ok || len(keys) == 0 {
+		return nil
+	}
+	var md map[string][]string
+	for _, key := range keys {
+		key = strings.ToLower(key)
+		if values := c.Metadata[key]; len(values) > 0 {
+			if md == nil {
+				md = make(map[string][]string, len(keys))
+			}
+			md[key] = values
+		}
+	}
+	return md
+}
+
+// MetadataValue returns the first value of the metadata key, the key being
+// case insensitive.
+func (c *Client) MetadataValue(key string) string {
+	if values := c.Metadata[strings.ToLower(key)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func ipFromAddr(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.UDPAddr:
+		return a.IP.String()
+	}
+	return ""
+}
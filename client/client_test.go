@@ -0,0 +1,99 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	c := &Client{IP: "10.0.0.1"}
+	got, ok := FromContext(NewContext(context.Background(), c))
+	assert.True(t, ok)
+	assert.Equal(t, c, got)
+}
+
+func TestFromGRPC(t *testing.T) {
+	_, ok := FromGRPC(context.Background())
+	assert.False(t, ok)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 55678},
+	})
+	c, ok := FromGRPC(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, &Client{IP: "10.0.0.1"}, c)
+
+	// The clients connected through a Unix domain socket have no IP.
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.UnixAddr{Name: "/tmp/oc.sock", Net: "unix"},
+	})
+	_, ok = FromGRPC(ctx)
+	assert.False(t, ok)
+
+	// The metadata is kept without a peer address.
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("X-Tenant", "acme"))
+	c, ok = FromGRPC(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "", c.IP)
+	assert.Equal(t, "acme", c.MetadataValue("x-tenant"))
+	assert.Equal(t, "acme", c.MetadataValue("X-Tenant"))
+	assert.Equal(t, "", c.MetadataValue("x-other"))
+}
+
+func TestFromHTTP(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:9411", Header: http.Header{}}
+	c, ok := FromHTTP(r)
+	assert.True(t, ok)
+	assert.Equal(t, &Client{IP: "10.0.0.1"}, c)
+
+	r.Header.Set("X-Tenant", "acme")
+	c, ok = FromHTTP(r)
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", c.IP)
+	assert.Equal(t, "acme", c.MetadataValue("X-Tenant"))
+	assert.Equal(t, []string{"acme"}, c.Metadata["x-tenant"])
+
+	_, ok = FromHTTP(&http.Request{})
+	assert.False(t, ok)
+}
+
+func TestForwardedMetadata(t *testing.T) {
+	keys := []string{"X-Tenant", "authorization"}
+	assert.Nil(t, ForwardedMetadata(context.Background(), keys))
+
+	ctx := NewContext(context.Background(), &Client{
+		Metadata: map[string][]string{
+			"x-tenant":      {"acme"},
+			"authorization": {"Bearer token"},
+			"user-agent":    {"grpc-go"},
+		},
+	})
+	assert.Equal(t,
+		map[string][]string{"x-tenant": {"acme"}, "authorization": {"Bearer token"}},
+		ForwardedMetadata(ctx, keys))
+	assert.Nil(t, ForwardedMetadata(ctx, nil))
+	assert.Nil(t, ForwardedMetadata(ctx, []string{"x-other"}))
+}
@@ -33,7 +33,13 @@ func main() {
 	receivers, processors, exporters, err := defaults.Components()
 	handleErr(err)
 
-	svc := service.New(receivers, processors, exporters)
+	svc, err := service.New(service.Parameters{
+		ReceiverFactories:  receivers,
+		ProcessorFactories: processors,
+		ExporterFactories:  exporters,
+	})
+	handleErr(err)
+
 	err = svc.StartUnified()
 	handleErr(err)
 }
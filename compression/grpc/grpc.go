@@ -25,7 +25,9 @@ import (
 var (
 	// Map of opencensus compression types to grpc registered compression types
 	grpcCompressionKeyMap = map[string]string{
-		compression.Gzip: gzip.Name,
+		compression.Gzip:   gzip.Name,
+		compression.Snappy: snappyName,
+		compression.Zstd:   zstdName,
 	}
 )
 
@@ -15,8 +15,12 @@
 package grpc
 
 import (
+	"bytes"
+	"io/ioutil"
 	"testing"
 
+	"google.golang.org/grpc/encoding"
+
 	"github.com/open-telemetry/opentelemetry-service/compression"
 )
 
@@ -29,7 +33,54 @@ func TestGetGRPCCompressionKey(t *testing.T) {
 		t.Error("Capitalization of Gzip should not matter")
 	}
 
+	if GetGRPCCompressionKey("snappy") != compression.Snappy {
+		t.Error("snappy is marked as supported but returned unsupported")
+	}
+
+	if GetGRPCCompressionKey("zstd") != compression.Zstd {
+		t.Error("zstd is marked as supported but returned unsupported")
+	}
+
 	if GetGRPCCompressionKey("badType") != compression.Unsupported {
 		t.Error("badType is not supported but was returned as supported")
 	}
 }
+
+func TestCompressors(t *testing.T) {
+	msg := bytes.Repeat([]byte("opentelemetry "), 1000)
+	for _, name := range []string{compression.Gzip, compression.Snappy, compression.Zstd} {
+		c := encoding.GetCompressor(GetGRPCCompressionKey(name))
+		if c == nil {
+			t.Fatalf("%s compressor is not registered", name)
+		}
+		// Twice, to reuse the pooled encoders and decoders.
+		for i := 0; i < 2; i++ {
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			if err != nil {
+				t.Fatalf("%s: Compress() failed: %v", name, err)
+			}
+			if _, err := w.Write(msg); err != nil {
+				t.Fatalf("%s: Write() failed: %v", name, err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("%s: Close() failed: %v", name, err)
+			}
+			if buf.Len() >= len(msg) {
+				t.Errorf("%s: got %d compressed bytes, want less than %d", name, buf.Len(), len(msg))
+			}
+
+			r, err := c.Decompress(&buf)
+			if err != nil {
+				t.Fatalf("%s: Decompress() failed: %v", name, err)
+			}
+			got, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("%s: ReadAll() failed: %v", name, err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Errorf("%s: decompressed message differs from the original one", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+const snappyName = "snappy"
+
+func init() {
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// snappyCompressor is the grpc compressor of the snappy framing format.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string {
+	return snappyName
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
@@ -18,4 +18,6 @@ package compression
 const (
 	Unsupported = ""
 	Gzip        = "gzip"
+	Snappy      = "snappy"
+	Zstd        = "zstd"
 )
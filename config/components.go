@@ -0,0 +1,195 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// Components describes the components a build of the service supports: the
+// default configuration of each type of receiver, processor and exporter, as
+// written in a configuration file.
+type Components struct {
+	Receivers  map[string]interface{} `yaml:"receivers"`
+	Processors map[string]interface{} `yaml:"processors"`
+	Exporters  map[string]interface{} `yaml:"exporters"`
+}
+
+// DescribeComponents returns the Components of the given factories.
+func DescribeComponents(
+	receivers map[string]receiver.Factory,
+	processors map[string]processor.Factory,
+	exporters map[string]exporter.Factory,
+) *Components {
+	c := &Components{
+		Receivers:  make(map[string]interface{}),
+		Processors: make(map[string]interface{}),
+		Exporters:  make(map[string]interface{}),
+	}
+	for typeStr, f := range receivers {
+		c.Receivers[typeStr] = configValue(reflect.ValueOf(f.CreateDefaultConfig()))
+	}
+	for typeStr, f := range processors {
+		c.Processors[typeStr] = configValue(reflect.ValueOf(f.CreateDefaultConfig()))
+	}
+	for typeStr, f := range exporters {
+		c.Exporters[typeStr] = configValue(reflect.ValueOf(f.CreateDefaultConfig()))
+	}
+	return c
+}
+
+// WriteYAML writes the components in YAML, in the format of a configuration
+// file.
+func (c *Components) WriteYAML(w io.Writer) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// DescribeConfig returns the configuration cfg of a component as the maps,
+// slices and values it is unmarshaled from, as written in a configuration
+// file.
+func DescribeConfig(cfg interface{}) interface{} {
+	return configValue(reflect.ValueOf(cfg))
+}
+
+// UpdateProcessorConfig returns a new configuration of the processor created
+// by the factory, with the settings of cfg replaced by the ones read from r, in
+// YAML or JSON. The settings missing from r keep their value, the lists are
+// replaced as a whole.
+func UpdateProcessorConfig(
+	factory processor.Factory,
+	cfg configmodels.Processor,
+	r io.Reader,
+) (configmodels.Processor, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	current, _ := DescribeConfig(cfg).(map[string]interface{})
+	if err := v.MergeConfigMap(current); err != nil {
+		return nil, err
+	}
+	if err := v.MergeConfig(r); err != nil {
+		return nil, fmt.Errorf("error reading the settings: %v", err)
+	}
+
+	updated := factory.CreateDefaultConfig()
+	updated.SetType(cfg.Type())
+	updated.SetName(cfg.Name())
+	if err := v.UnmarshalExact(updated); err != nil {
+		return nil, fmt.Errorf("error reading settings for processor %q: %v", cfg.Name(), err)
+	}
+	return updated, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configValue converts a configuration to the maps, slices and values it is
+// unmarshaled from, keyed by the mapstructure tags of its fields.
+func configValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return configValue(v.Elem())
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		addStructFields(m, v)
+		return m
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			m[fmt.Sprint(key.Interface())] = configValue(v.MapIndex(key))
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = configValue(v.Index(i))
+		}
+		return s
+	}
+	if v.Type() == durationType {
+		return v.Interface().(time.Duration).String()
+	}
+	return v.Interface()
+}
+
+func addStructFields(m map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// Unexported.
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		squash, omitEmpty := false, false
+		for _, opt := range tag[1:] {
+			switch opt {
+			case "squash":
+				squash = true
+			case "omitempty":
+				omitEmpty = true
+			}
+		}
+		fv := v.Field(i)
+		if squash {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				addStructFields(m, fv)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if omitEmpty && isZero(fv) {
+			continue
+		}
+		m[name] = configValue(fv)
+	}
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
@@ -0,0 +1,134 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestDescribeComponents(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	require.NoError(t, err)
+
+	c := DescribeComponents(receivers, processors, exporters)
+	assert.Equal(t, map[string]interface{}{
+		"disabled": false,
+		"endpoint": "localhost:1000",
+		"extra":    "some string",
+	}, c.Receivers["examplereceiver"])
+	assert.Equal(t, map[string]interface{}{
+		"protocols": map[string]interface{}{
+			"http": map[string]interface{}{"disabled": false, "endpoint": "example.com:8888", "extra": "extra string 1"},
+			"tcp":  map[string]interface{}{"disabled": false, "endpoint": "omnition.com:9999", "extra": "extra string 2"},
+		},
+	}, c.Receivers["multireceiver"])
+	assert.Equal(t, map[string]interface{}{
+		"disabled": false,
+		"extra":    "some export string",
+	}, c.Exporters["exampleexporter"])
+	assert.Contains(t, c.Processors, "exampleprocessor")
+
+	// The YAML can be loaded back.
+	var buf bytes.Buffer
+	require.NoError(t, c.WriteYAML(&buf))
+	var loaded Components
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &loaded))
+	assert.Equal(t, len(c.Receivers), len(loaded.Receivers))
+	assert.Equal(t, len(c.Processors), len(loaded.Processors))
+	assert.Equal(t, len(c.Exporters), len(loaded.Exporters))
+}
+
+func TestConfigValue(t *testing.T) {
+	type nested struct {
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+	type cfg struct {
+		Name     string            `mapstructure:"name"`
+		Skipped  string            `mapstructure:"-"`
+		Empty    string            `mapstructure:"empty,omitempty"`
+		Nested   *nested           `mapstructure:"nested"`
+		Missing  *nested           `mapstructure:"missing"`
+		Labels   map[string]string `mapstructure:"labels"`
+		Paths    []string          `mapstructure:"paths"`
+		Untagged int
+		private  int
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "svc",
+		"nested":   map[string]interface{}{"timeout": "5s"},
+		"missing":  nil,
+		"labels":   map[string]interface{}{"a": "b"},
+		"paths":    []interface{}{"stderr"},
+		"untagged": 3,
+	}, configValue(reflect.ValueOf(&cfg{
+		Name:     "svc",
+		Skipped:  "skipped",
+		Nested:   &nested{Timeout: 5 * time.Second},
+		Labels:   map[string]string{"a": "b"},
+		Paths:    []string{"stderr"},
+		Untagged: 3,
+		private:  4,
+	})))
+}
+
+type listProcessor struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	Values                         []string      `mapstructure:"values"`
+	Timeout                        time.Duration `mapstructure:"timeout"`
+}
+
+type listProcessorFactory struct {
+	ExampleProcessorFactory
+}
+
+func (f *listProcessorFactory) CreateDefaultConfig() configmodels.Processor {
+	return &listProcessor{Timeout: time.Second}
+}
+
+func TestUpdateProcessorConfig(t *testing.T) {
+	factory := &listProcessorFactory{}
+	cfg := &listProcessor{Values: []string{"a", "b", "c"}, Timeout: 5 * time.Second}
+	cfg.SetType("list")
+	cfg.SetName("list/1")
+
+	updated, err := UpdateProcessorConfig(factory, cfg, strings.NewReader(`{"values": ["d"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "list/1", updated.Name())
+	assert.Equal(t, []string{"d"}, updated.(*listProcessor).Values)
+	assert.Equal(t, 5*time.Second, updated.(*listProcessor).Timeout)
+	// The current configuration is not modified.
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Values)
+
+	updated, err = UpdateProcessorConfig(factory, cfg, strings.NewReader("timeout: 10s\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, updated.(*listProcessor).Values)
+	assert.Equal(t, 10*time.Second, updated.(*listProcessor).Timeout)
+
+	_, err = UpdateProcessorConfig(factory, cfg, strings.NewReader(`{"unknown": 1}`))
+	assert.Error(t, err)
+	_, err = UpdateProcessorConfig(factory, cfg, strings.NewReader(`{"values": `))
+	assert.Error(t, err)
+}
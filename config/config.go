@@ -20,13 +20,19 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
 )
@@ -53,10 +59,21 @@ const (
 	errPipelineReceiverNotExists
 	errPipelineProcessorNotExists
 	errPipelineExporterNotExists
-	errMetricPipelineCannotHaveProcessors
 	errUnmarshalError
 	errMissingReceivers
 	errMissingExporters
+	errInvalidTelemetryLevel
+	errInvalidTelemetryTraces
+	errInvalidTelemetryLogs
+	errInvalidRuntime
+	errInvalidRestartPolicy
+	errPipelineConnectorDataType
+	errPipelineConnectorCycle
+	errAmbiguousPipelineReceiver
+	errInvalidReceiverRoute
+	errInvalidEnabled
+	errInvalidDebug
+	errInvalidTuning
 )
 
 type configError struct {
@@ -81,8 +98,58 @@ const (
 
 	// pipelinesKeyName is the configuration key name for pipelines section.
 	pipelinesKeyName = "pipelines"
+
+	// telemetryKeyName is the configuration key name for telemetry section.
+	telemetryKeyName = "telemetry"
+
+	// runtimeKeyName is the configuration key name for runtime section.
+	runtimeKeyName = "runtime"
+
+	// restartPolicyKeyName is the configuration key name for restart policy
+	// section.
+	restartPolicyKeyName = "restart-policy"
+
+	// debugKeyName is the configuration key name for debug section.
+	debugKeyName = "debug"
+
+	// storageKeyName is the configuration key name for storage section.
+	storageKeyName = "storage"
+
+	// statusKeyName is the configuration key name for status section.
+	statusKeyName = "status"
+
+	// tuningKeyName is the configuration key name for tuning section.
+	tuningKeyName = "tuning"
+)
+
+// Default values of the telemetry section.
+const (
+	defaultTelemetryAddress      = ":8888"
+	defaultTelemetryLevel        = "basic"
+	defaultTelemetrySamplingRate = 0.01
+
+	defaultTelemetryLogsLevel              = "info"
+	defaultTelemetryLogsEncoding           = "json"
+	defaultTelemetryLogsOutputPath         = "stderr"
+	defaultTelemetryLogsSamplingInitial    = 100
+	defaultTelemetryLogsSamplingThereafter = 100
+)
+
+// Default values of the restart policy section.
+const (
+	defaultRestartPolicyInitialInterval = time.Second
+	defaultRestartPolicyMaxInterval     = time.Minute
+	defaultRestartPolicyMaxRestarts     = 5
 )
 
+// defaultDebugBatches is the default number of batches kept per pipeline by
+// the debug endpoint.
+const defaultDebugBatches = 10
+
+// enabledKeyName is the configuration key name of the setting enabling a
+// receiver, exporter or processor.
+const enabledKeyName = "enabled"
+
 // typeAndNameSeparator is the separator that is used between type and name in type/name composite keys.
 const typeAndNameSeparator = "/"
 
@@ -123,6 +190,48 @@ func Load(
 	}
 	config.Pipelines = pipelines
 
+	tel, err := loadTelemetry(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Telemetry = tel
+
+	rt, err := loadRuntime(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Runtime = rt
+
+	policy, err := loadRestartPolicy(v)
+	if err != nil {
+		return nil, err
+	}
+	config.RestartPolicy = policy
+
+	debug, err := loadDebug(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Debug = debug
+
+	storage, err := loadStorage(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Storage = storage
+
+	status, err := loadStatus(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Status = status
+
+	tuning, err := loadTuning(v)
+	if err != nil {
+		return nil, err
+	}
+	config.Tuning = tuning
+
 	// Config is loaded. Now validate it.
 
 	if err := validateConfig(&config, logger); err != nil {
@@ -235,6 +344,13 @@ func loadReceivers(v *viper.Viper, factories map[string]receiver.Factory) (confi
 			}
 		}
 
+		if err := loadEnabled(subViper, key, receiverCfg); err != nil {
+			return nil, &configError{
+				code: errInvalidEnabled,
+				msg:  fmt.Sprintf("error reading settings for receiver %q: %v", fullName, err),
+			}
+		}
+
 		if receivers[fullName] != nil {
 			return nil, &configError{
 				code: errDuplicateReceiverName,
@@ -300,6 +416,13 @@ func loadExporters(v *viper.Viper, factories map[string]exporter.Factory) (confi
 			}
 		}
 
+		if err := loadEnabled(subViper, key, exporterCfg); err != nil {
+			return nil, &configError{
+				code: errInvalidEnabled,
+				msg:  fmt.Sprintf("error reading settings for exporter %q: %v", fullName, err),
+			}
+		}
+
 		if exporters[fullName] != nil {
 			return nil, &configError{
 				code: errDuplicateExporterName,
@@ -357,6 +480,13 @@ func loadProcessors(v *viper.Viper, factories map[string]processor.Factory) (con
 			}
 		}
 
+		if err := loadEnabled(subViper, key, processorCfg); err != nil {
+			return nil, &configError{
+				code: errInvalidEnabled,
+				msg:  fmt.Sprintf("error reading settings for processor %q: %v", fullName, err),
+			}
+		}
+
 		if processors[fullName] != nil {
 			return nil, &configError{
 				code: errDuplicateProcessorName,
@@ -370,6 +500,53 @@ func loadProcessors(v *viper.Viper, factories map[string]processor.Factory) (con
 	return processors, nil
 }
 
+// loadEnabled enables or disables the entity of the key according to its
+// "enabled" setting, if any. The setting can reference environment variables
+// as ${NAME} or ${NAME:-default}, which are expanded at load time so that a
+// config can have optional entities, an empty value disables the entity.
+func loadEnabled(subViper *viper.Viper, key string, cfg interface{}) error {
+	settings := subViper.GetStringMap(key)
+	value, ok := settings[enabledKeyName]
+	if !ok {
+		return nil
+	}
+	if _, ok := settings["disabled"]; ok {
+		return errors.New("only one of enabled and disabled can be set")
+	}
+	toggleable, ok := cfg.(configmodels.Toggleable)
+	if !ok {
+		return errors.New("enabled is not supported")
+	}
+
+	str := ""
+	if value != nil {
+		str = strings.TrimSpace(os.Expand(fmt.Sprint(value), expandEnv))
+	}
+	if str == "" {
+		toggleable.SetEnabled(false)
+		return nil
+	}
+	enabled, err := strconv.ParseBool(str)
+	if err != nil {
+		return fmt.Errorf("invalid enabled value %q, it must be a bool", str)
+	}
+	toggleable.SetEnabled(enabled)
+	return nil
+}
+
+// expandEnv returns the value of the environment variable of name, which can
+// be followed by ":-" and the default value used if it is unset or empty.
+func expandEnv(name string) string {
+	def := ""
+	if i := strings.Index(name, ":-"); i >= 0 {
+		name, def = name[:i], name[i+len(":-"):]
+	}
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
+
 func loadPipelines(v *viper.Viper) (configmodels.Pipelines, error) {
 	// Get the list of all "pipelines" sub vipers from config source.
 	subViper := v.Sub(pipelinesKeyName)
@@ -431,6 +608,123 @@ func loadPipelines(v *viper.Viper) (configmodels.Pipelines, error) {
 	return pipelines, nil
 }
 
+func loadTelemetry(v *viper.Viper) (configmodels.Telemetry, error) {
+	tel := configmodels.Telemetry{
+		Address: defaultTelemetryAddress,
+		Level:   defaultTelemetryLevel,
+		Traces: configmodels.TelemetryTraces{
+			SamplingRate: defaultTelemetrySamplingRate,
+		},
+		Logs: configmodels.TelemetryLogs{
+			Level:    defaultTelemetryLogsLevel,
+			Encoding: defaultTelemetryLogsEncoding,
+			Sampling: configmodels.TelemetryLogsSampling{
+				Initial:    defaultTelemetryLogsSamplingInitial,
+				Thereafter: defaultTelemetryLogsSamplingThereafter,
+			},
+		},
+	}
+	if err := v.UnmarshalKey(telemetryKeyName, &tel); err != nil {
+		return tel, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for telemetry: %v", err),
+		}
+	}
+	// Set after unmarshaling, the configured paths would only overwrite the
+	// first elements of a default slice.
+	if len(tel.Logs.OutputPaths) == 0 {
+		tel.Logs.OutputPaths = []string{defaultTelemetryLogsOutputPath}
+	}
+	return tel, nil
+}
+
+// LoadTelemetryLogs loads and validates the logs settings of the telemetry
+// section, so that the logger can be created before the rest of the
+// configuration is loaded. The levels of the components are validated with
+// the rest of the configuration by Load.
+func LoadTelemetryLogs(v *viper.Viper) (configmodels.TelemetryLogs, error) {
+	tel, err := loadTelemetry(v)
+	if err != nil {
+		return tel.Logs, err
+	}
+	return tel.Logs, validateTelemetryLogs(tel.Logs)
+}
+
+func loadRuntime(v *viper.Viper) (configmodels.Runtime, error) {
+	rt := configmodels.Runtime{
+		AutoMaxProcs: true,
+	}
+	if err := v.UnmarshalKey(runtimeKeyName, &rt); err != nil {
+		return rt, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for runtime: %v", err),
+		}
+	}
+	return rt, nil
+}
+
+func loadRestartPolicy(v *viper.Viper) (configmodels.RestartPolicy, error) {
+	policy := configmodels.RestartPolicy{
+		Action:          configmodels.RestartPolicyShutdown,
+		InitialInterval: defaultRestartPolicyInitialInterval,
+		MaxInterval:     defaultRestartPolicyMaxInterval,
+		MaxRestarts:     defaultRestartPolicyMaxRestarts,
+	}
+	if err := v.UnmarshalKey(restartPolicyKeyName, &policy); err != nil {
+		return policy, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for restart policy: %v", err),
+		}
+	}
+	return policy, nil
+}
+
+func loadDebug(v *viper.Viper) (configmodels.Debug, error) {
+	debug := configmodels.Debug{
+		Batches: defaultDebugBatches,
+	}
+	if err := v.UnmarshalKey(debugKeyName, &debug); err != nil {
+		return debug, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for debug: %v", err),
+		}
+	}
+	return debug, nil
+}
+
+func loadStorage(v *viper.Viper) (configmodels.Storage, error) {
+	var storage configmodels.Storage
+	if err := v.UnmarshalKey(storageKeyName, &storage); err != nil {
+		return storage, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for storage: %v", err),
+		}
+	}
+	return storage, nil
+}
+
+func loadStatus(v *viper.Viper) (configmodels.Status, error) {
+	var status configmodels.Status
+	if err := v.UnmarshalKey(statusKeyName, &status); err != nil {
+		return status, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for status: %v", err),
+		}
+	}
+	return status, nil
+}
+
+func loadTuning(v *viper.Viper) (configmodels.Tuning, error) {
+	var tuning configmodels.Tuning
+	if err := v.UnmarshalKey(tuningKeyName, &tuning); err != nil {
+		return tuning, &configError{
+			code: errUnmarshalError,
+			msg:  fmt.Sprintf("error reading settings for tuning: %v", err),
+		}
+	}
+	return tuning, nil
+}
+
 func validateConfig(cfg *configmodels.Config, logger *zap.Logger) error {
 	// This function performs basic validation of configuration. There may be more subtle
 	// invalid cases that we currently don't check for but which we may want to add in
@@ -448,9 +742,184 @@ func validateConfig(cfg *configmodels.Config, logger *zap.Logger) error {
 	}
 	validateProcessors(cfg)
 
+	if err := validateTelemetry(cfg); err != nil {
+		return err
+	}
+	if err := validateRuntime(cfg); err != nil {
+		return err
+	}
+	if err := validateRestartPolicy(cfg); err != nil {
+		return err
+	}
+	if err := validateDebug(cfg); err != nil {
+		return err
+	}
+	return validateTuning(cfg)
+}
+
+func validateRuntime(cfg *configmodels.Config) error {
+	if cfg.Runtime.BallastSizePercentage >= 100 {
+		return &configError{
+			code: errInvalidRuntime,
+			msg: fmt.Sprintf("runtime ballast-size-percentage %d must be lower than 100",
+				cfg.Runtime.BallastSizePercentage),
+		}
+	}
+	return nil
+}
+
+func validateTelemetry(cfg *configmodels.Config) error {
+	if _, err := telemetry.ParseLevel(cfg.Telemetry.Level); err != nil {
+		return &configError{
+			code: errInvalidTelemetryLevel,
+			msg:  fmt.Sprintf("invalid telemetry level: %v", err),
+		}
+	}
+
+	if err := validateTelemetryLogs(cfg.Telemetry.Logs); err != nil {
+		return err
+	}
+	if err := validateTelemetryLogsLevels(cfg); err != nil {
+		return err
+	}
+
+	traces := cfg.Telemetry.Traces
+	if traces.SamplingRate < 0 || traces.SamplingRate > 1 {
+		return &configError{
+			code: errInvalidTelemetryTraces,
+			msg:  fmt.Sprintf("telemetry traces sampling-rate %v must be between 0 and 1", traces.SamplingRate),
+		}
+	}
+	if traces.Pipeline == "" {
+		return nil
+	}
+	pipeline := cfg.Pipelines[traces.Pipeline]
+	if pipeline == nil {
+		return &configError{
+			code: errInvalidTelemetryTraces,
+			msg:  fmt.Sprintf("telemetry traces reference pipeline %q which does not exist", traces.Pipeline),
+		}
+	}
+	if pipeline.InputType != configmodels.TracesDataType {
+		return &configError{
+			code: errInvalidTelemetryTraces,
+			msg:  fmt.Sprintf("telemetry traces reference pipeline %q which is not a traces pipeline", traces.Pipeline),
+		}
+	}
+	return nil
+}
+
+func validateRestartPolicy(cfg *configmodels.Config) error {
+	policy := cfg.RestartPolicy
+	if policy.Action != configmodels.RestartPolicyShutdown && policy.Action != configmodels.RestartPolicyRestart {
+		return &configError{
+			code: errInvalidRestartPolicy,
+			msg: fmt.Sprintf("restart policy action %q must be %s or %s", policy.Action,
+				configmodels.RestartPolicyShutdown, configmodels.RestartPolicyRestart),
+		}
+	}
+	if policy.InitialInterval <= 0 || policy.MaxInterval < policy.InitialInterval {
+		return &configError{
+			code: errInvalidRestartPolicy,
+			msg: fmt.Sprintf("restart policy initial-interval %v must be positive and not greater than max-interval %v",
+				policy.InitialInterval, policy.MaxInterval),
+		}
+	}
+	if policy.MaxRestarts < 0 {
+		return &configError{
+			code: errInvalidRestartPolicy,
+			msg:  fmt.Sprintf("restart policy max-restarts %d must not be negative", policy.MaxRestarts),
+		}
+	}
 	return nil
 }
 
+func validateDebug(cfg *configmodels.Config) error {
+	if cfg.Debug.Batches <= 0 {
+		return &configError{
+			code: errInvalidDebug,
+			msg:  fmt.Sprintf("debug batches %d must be positive", cfg.Debug.Batches),
+		}
+	}
+	return nil
+}
+
+func validateTuning(cfg *configmodels.Config) error {
+	if cfg.Tuning.Endpoint != "" && cfg.Tuning.BearerToken == "" {
+		return &configError{
+			code: errInvalidTuning,
+			msg:  "tuning bearer-token must be set to enable the endpoint",
+		}
+	}
+	return nil
+}
+
+func validateTelemetryLogs(logs configmodels.TelemetryLogs) error {
+	if _, err := parseLogLevel(logs.Level); err != nil {
+		return err
+	}
+	if logs.Encoding != "json" && logs.Encoding != "console" {
+		return &configError{
+			code: errInvalidTelemetryLogs,
+			msg:  fmt.Sprintf("telemetry logs encoding %q must be json or console", logs.Encoding),
+		}
+	}
+	if logs.Sampling.Initial < 0 || logs.Sampling.Thereafter < 0 {
+		return &configError{
+			code: errInvalidTelemetryLogs,
+			msg:  "telemetry logs sampling initial and thereafter must not be negative",
+		}
+	}
+	return nil
+}
+
+func validateTelemetryLogsLevels(cfg *configmodels.Config) error {
+	levels := cfg.Telemetry.Logs.Levels
+	for name, level := range levels.Receivers {
+		if cfg.Receivers[name] == nil {
+			return unknownLogsComponentError("receiver", name)
+		}
+		if _, err := parseLogLevel(level); err != nil {
+			return err
+		}
+	}
+	for name, level := range levels.Processors {
+		if cfg.Processors[name] == nil {
+			return unknownLogsComponentError("processor", name)
+		}
+		if _, err := parseLogLevel(level); err != nil {
+			return err
+		}
+	}
+	for name, level := range levels.Exporters {
+		if cfg.Exporters[name] == nil {
+			return unknownLogsComponentError("exporter", name)
+		}
+		if _, err := parseLogLevel(level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unknownLogsComponentError(kind, name string) error {
+	return &configError{
+		code: errInvalidTelemetryLogs,
+		msg:  fmt.Sprintf("telemetry logs levels reference %s %q which does not exist", kind, name),
+	}
+}
+
+func parseLogLevel(level string) (zapcore.Level, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, &configError{
+			code: errInvalidTelemetryLogs,
+			msg:  fmt.Sprintf("invalid telemetry logs level %q", level),
+		}
+	}
+	return l, nil
+}
+
 func validatePipelines(cfg *configmodels.Config, logger *zap.Logger) error {
 	// Must have at least one pipeline.
 	if len(cfg.Pipelines) < 1 {
@@ -463,6 +932,76 @@ func validatePipelines(cfg *configmodels.Config, logger *zap.Logger) error {
 			return err
 		}
 	}
+	return validateConnectorCycles(cfg)
+}
+
+// connectorConfig returns the config of the exporter of the given name if it
+// is a connector, nil otherwise.
+func connectorConfig(cfg *configmodels.Config, name string) exporter.ConnectorConfig {
+	connector, _ := cfg.Exporters[name].(exporter.ConnectorConfig)
+	return connector
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDataType(dataTypes []configmodels.DataType, dataType configmodels.DataType) bool {
+	for _, dt := range dataTypes {
+		if dt == dataType {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConnectorCycles checks that the data sent by a pipeline to a
+// connector never comes back to it, through any number of pipelines.
+func validateConnectorCycles(cfg *configmodels.Config) error {
+	// visiting holds the pipelines of the current path, visited the ones
+	// known to be out of any cycle.
+	visiting := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var visit func(pipeline *configmodels.Pipeline) error
+	visit = func(pipeline *configmodels.Pipeline) error {
+		if visited[pipeline.Name] {
+			return nil
+		}
+		if visiting[pipeline.Name] {
+			return &configError{
+				code: errPipelineConnectorCycle,
+				msg:  fmt.Sprintf("pipeline %q sends data back to itself through connectors", pipeline.Name),
+			}
+		}
+		visiting[pipeline.Name] = true
+		for _, exp := range pipeline.Exporters {
+			if connectorConfig(cfg, exp) == nil {
+				continue
+			}
+			for _, next := range cfg.Pipelines {
+				if containsString(next.Receivers, exp) {
+					if err := visit(next); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		visiting[pipeline.Name] = false
+		visited[pipeline.Name] = true
+		return nil
+	}
+
+	for _, pipeline := range cfg.Pipelines {
+		if err := visit(pipeline); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -491,7 +1030,8 @@ func validatePipelineReceivers(
 	pipeline *configmodels.Pipeline,
 	logger *zap.Logger,
 ) error {
-	if len(pipeline.Receivers) == 0 {
+	// The pipeline receiving the spans of the service does not need receivers.
+	if len(pipeline.Receivers) == 0 && pipeline.Name != cfg.Telemetry.Traces.Pipeline {
 		return &configError{
 			code: errPipelineMustHaveReceiver,
 			msg:  fmt.Sprintf("pipeline %q must have at least one receiver", pipeline.Name),
@@ -500,19 +1040,39 @@ func validatePipelineReceivers(
 
 	// Validate pipeline receiver name references.
 	for _, ref := range pipeline.Receivers {
-		// Check that the name referenced in the pipeline's Receivers exists in the top-level Receivers
-		if cfg.Receivers[ref] == nil {
+		// Check that the name referenced in the pipeline's Receivers exists in the top-level Receivers,
+		// or is an exporter connecting another pipeline to this one.
+		connector := connectorConfig(cfg, ref)
+		if cfg.Receivers[ref] != nil && connector != nil {
+			return &configError{
+				code: errAmbiguousPipelineReceiver,
+				msg: fmt.Sprintf("pipeline %q references receiver %q which is both a receiver and a connector",
+					pipeline.Name, ref),
+			}
+		}
+		if cfg.Receivers[ref] == nil && connector == nil {
 			return &configError{
 				code: errPipelineReceiverNotExists,
 				msg:  fmt.Sprintf("pipeline %q references receiver %q which does not exists", pipeline.Name, ref),
 			}
 		}
+		if connector != nil && !containsDataType(connector.ConnectorDataTypes(), pipeline.InputType) {
+			return &configError{
+				code: errPipelineConnectorDataType,
+				msg: fmt.Sprintf("pipeline %q references connector %q which does not send %s",
+					pipeline.Name, ref, pipeline.InputType.GetString()),
+			}
+		}
 	}
 
 	// Remove disabled receivers.
 	rs := pipeline.Receivers[:0]
 	for _, ref := range pipeline.Receivers {
-		rcv := cfg.Receivers[ref]
+		var rcv configmodels.Receiver = cfg.Receivers[ref]
+		if rcv == nil {
+			// The receiver is a connector, enabled with its exporter.
+			rcv = cfg.Exporters[ref]
+		}
 		if rcv.IsEnabled() {
 			// The receiver is enabled. Keep it in the pipeline.
 			rs = append(rs, ref)
@@ -582,14 +1142,6 @@ func validatePipelineProcessors(
 				msg:  fmt.Sprintf("pipeline %q must have at least one processor", pipeline.Name),
 			}
 		}
-	} else if pipeline.InputType == configmodels.MetricsDataType {
-		// Metrics pipeline cannot have processors.
-		if len(pipeline.Processors) > 0 {
-			return &configError{
-				code: errMetricPipelineCannotHaveProcessors,
-				msg:  fmt.Sprintf("metrics pipeline %q cannot have processors", pipeline.Name),
-			}
-		}
 	}
 
 	// Validate pipeline processor name references
@@ -637,6 +1189,46 @@ func validateReceivers(cfg *configmodels.Config) error {
 			msg:  "no enabled receivers specified in config",
 		}
 	}
+	return validateReceiverRoutes(cfg)
+}
+
+func validateReceiverRoutes(cfg *configmodels.Config) error {
+	for name, rcv := range cfg.Receivers {
+		routed, ok := rcv.(configmodels.RoutedReceiver)
+		if !ok {
+			continue
+		}
+		for i, route := range routed.ReceiverRoutes() {
+			if err := validateReceiverRoute(cfg, name, route); err != nil {
+				return &configError{
+					code: errInvalidReceiverRoute,
+					msg:  fmt.Sprintf("receiver %q route %d: %v", name, i, err),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateReceiverRoute(cfg *configmodels.Config, receiver string, route configmodels.ReceiverRoute) error {
+	if route.Attribute == "" {
+		return errors.New("attribute must be set")
+	}
+	if _, err := regexp.Compile(route.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %v", err)
+	}
+	if len(route.Pipelines) == 0 {
+		return errors.New("pipelines must be set")
+	}
+	for _, name := range route.Pipelines {
+		pipeline := cfg.Pipelines[name]
+		if pipeline == nil {
+			return fmt.Errorf("pipeline %q does not exist", name)
+		}
+		if !containsString(pipeline.Receivers, receiver) {
+			return fmt.Errorf("pipeline %q does not have the receiver", name)
+		}
+	}
 	return nil
 }
 
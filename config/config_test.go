@@ -15,8 +15,11 @@
 package config
 
 import (
+	"os"
 	"path"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -114,6 +117,68 @@ func TestDecodeConfig(t *testing.T) {
 		},
 		config.Pipelines["traces"],
 		"Did not load pipeline config correctly")
+
+	// Verify Telemetry
+	assert.Equal(t,
+		configmodels.Telemetry{
+			Address: "localhost:8889",
+			Level:   "detailed",
+			Labels:  map[string]string{"instance": "collector-1"},
+			Traces:  configmodels.TelemetryTraces{SamplingRate: 0.01},
+			Logs: configmodels.TelemetryLogs{
+				Level:       "debug",
+				Encoding:    "console",
+				OutputPaths: []string{"stdout", "/var/log/otelsvc.log"},
+				Sampling:    configmodels.TelemetryLogsSampling{Initial: 10, Thereafter: 50},
+				Levels: configmodels.TelemetryLogsLevels{
+					Receivers: map[string]string{"examplereceiver": "warn"},
+					Exporters: map[string]string{"exampleexporter": "debug"},
+				},
+			},
+		},
+		config.Telemetry,
+		"Did not load telemetry config correctly")
+
+	// Verify Runtime
+	assert.Equal(t,
+		configmodels.Runtime{BallastSizePercentage: 30},
+		config.Runtime,
+		"Did not load runtime config correctly")
+
+	// Verify RestartPolicy
+	assert.Equal(t,
+		configmodels.RestartPolicy{
+			Action:          configmodels.RestartPolicyRestart,
+			InitialInterval: 2 * time.Second,
+			MaxInterval:     30 * time.Second,
+			MaxRestarts:     3,
+		},
+		config.RestartPolicy,
+		"Did not load restart policy config correctly")
+
+	// Verify Debug
+	assert.Equal(t,
+		configmodels.Debug{Endpoint: "localhost:55690", Batches: 5, Recording: true},
+		config.Debug,
+		"Did not load debug config correctly")
+
+	// Verify Storage
+	assert.Equal(t,
+		configmodels.Storage{Directory: "/var/lib/otelsvc"},
+		config.Storage,
+		"Did not load storage config correctly")
+
+	// Verify Status
+	assert.Equal(t,
+		configmodels.Status{Endpoint: "localhost:55691"},
+		config.Status,
+		"Did not load status config correctly")
+
+	// Verify Tuning
+	assert.Equal(t,
+		configmodels.Tuning{Endpoint: "localhost:55692", BearerToken: "some-token"},
+		config.Tuning,
+		"Did not load tuning config correctly")
 }
 
 func TestDecodeConfig_MultiProto(t *testing.T) {
@@ -166,6 +231,196 @@ func TestDecodeConfig_MultiProto(t *testing.T) {
 		},
 		config.Receivers["multireceiver/myreceiver"],
 		"Did not load receiver config correctly")
+
+	// Verify the default telemetry settings.
+	assert.Equal(t,
+		configmodels.Telemetry{
+			Address: ":8888",
+			Level:   "basic",
+			Traces:  configmodels.TelemetryTraces{SamplingRate: 0.01},
+			Logs: configmodels.TelemetryLogs{
+				Level:       "info",
+				Encoding:    "json",
+				OutputPaths: []string{"stderr"},
+				Sampling:    configmodels.TelemetryLogsSampling{Initial: 100, Thereafter: 100},
+			},
+		},
+		config.Telemetry,
+		"Did not load default telemetry config correctly")
+
+	// Verify the default runtime settings.
+	assert.Equal(t,
+		configmodels.Runtime{AutoMaxProcs: true},
+		config.Runtime,
+		"Did not load default runtime config correctly")
+
+	// Verify the default restart policy.
+	assert.Equal(t,
+		configmodels.RestartPolicy{
+			Action:          configmodels.RestartPolicyShutdown,
+			InitialInterval: time.Second,
+			MaxInterval:     time.Minute,
+			MaxRestarts:     5,
+		},
+		config.RestartPolicy,
+		"Did not load default restart policy config correctly")
+
+	// Verify the default debug settings.
+	assert.Equal(t,
+		configmodels.Debug{Batches: 10},
+		config.Debug,
+		"Did not load default debug config correctly")
+
+	// Verify the default storage settings.
+	assert.Equal(t,
+		configmodels.Storage{},
+		config.Storage,
+		"Did not load default storage config correctly")
+
+	// Verify the default status settings.
+	assert.Equal(t,
+		configmodels.Status{},
+		config.Status,
+		"Did not load default status config correctly")
+
+	// Verify the default tuning settings.
+	assert.Equal(t,
+		configmodels.Tuning{},
+		config.Tuning,
+		"Did not load default tuning config correctly")
+}
+
+func TestDecodeConfig_TelemetryTraces(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	config, err := LoadConfigFile(
+		t, path.Join(".", "testdata", "telemetry-traces.yaml"), receivers, processors, exporters,
+	)
+	if err != nil {
+		t.Fatalf("unable to load config, %v", err)
+	}
+
+	assert.Equal(t,
+		configmodels.TelemetryTraces{Pipeline: "traces/self", SamplingRate: 0.5},
+		config.Telemetry.Traces,
+		"Did not load telemetry traces config correctly")
+
+	// The pipeline receiving the spans of the service needs no receivers.
+	assert.Equal(t, 0, len(config.Pipelines["traces/self"].Receivers))
+}
+
+func TestDecodeConfig_MetricsPipelineProcessors(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	config, err := LoadConfigFile(
+		t, path.Join(".", "testdata", "metrics-pipeline-processors.yaml"), receivers, processors, exporters,
+	)
+	if err != nil {
+		t.Fatalf("unable to load config, %v", err)
+	}
+
+	assert.Equal(t, []string{"exampleprocessor"}, config.Pipelines["metrics"].Processors)
+}
+
+func TestDecodeConfig_Connectors(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	config, err := LoadConfigFile(
+		t, path.Join(".", "testdata", "connectors.yaml"), receivers, processors, exporters,
+	)
+	if err != nil {
+		t.Fatalf("unable to load config, %v", err)
+	}
+
+	// The connector is an exporter of a pipeline and a receiver of another.
+	assert.Equal(t, []string{"exampleconnector"}, config.Pipelines["traces"].Exporters)
+	assert.Equal(t, []string{"exampleconnector"}, config.Pipelines["traces/connected"].Receivers)
+	assert.NotContains(t, config.Receivers, "exampleconnector")
+}
+
+func TestDecodeConfig_ReceiverRoutes(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	config, err := LoadConfigFile(
+		t, path.Join(".", "testdata", "receiver-routes.yaml"), receivers, processors, exporters,
+	)
+	if err != nil {
+		t.Fatalf("unable to load config, %v", err)
+	}
+
+	assert.Equal(t,
+		[]configmodels.ReceiverRoute{
+			{
+				Attribute: "service.name",
+				Pattern:   "frontend-.*",
+				Pipelines: []string{"traces/frontend", "metrics"},
+			},
+		},
+		config.Receivers["examplereceiver"].(configmodels.RoutedReceiver).ReceiverRoutes())
+}
+
+func TestDecodeConfig_Enabled(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	os.Setenv("OTELSVC_TEST_ENABLE_EXPORTER", "true")
+	defer os.Unsetenv("OTELSVC_TEST_ENABLE_EXPORTER")
+
+	for _, enable := range []string{"1", "false", ""} {
+		t.Run(enable, func(t *testing.T) {
+			os.Setenv("OTELSVC_TEST_ENABLE_RECEIVER", enable)
+			defer os.Unsetenv("OTELSVC_TEST_ENABLE_RECEIVER")
+
+			config, err := LoadConfigFile(
+				t, path.Join(".", "testdata", "enabled.yaml"), receivers, processors, exporters,
+			)
+			if err != nil {
+				t.Fatalf("unable to load config, %v", err)
+			}
+
+			// Disabled entities are removed from the config.
+			_, ok := config.Receivers["examplereceiver"]
+			assert.Equal(t, enable == "1", ok)
+			assert.Contains(t, config.Receivers, "examplereceiver/default")
+			assert.NotContains(t, config.Receivers, "examplereceiver/unset")
+			assert.NotContains(t, config.Processors, "exampleprocessor")
+			assert.Contains(t, config.Exporters, "exampleexporter")
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("OTELSVC_TEST_SET", "set")
+	defer os.Unsetenv("OTELSVC_TEST_SET")
+
+	assert.Equal(t, "set", expandEnv("OTELSVC_TEST_SET"))
+	assert.Equal(t, "set", expandEnv("OTELSVC_TEST_SET:-default"))
+	assert.Equal(t, "", expandEnv("OTELSVC_TEST_UNSET"))
+	assert.Equal(t, "default", expandEnv("OTELSVC_TEST_UNSET:-default"))
+}
+
+func TestCheckDefaultConfigs(t *testing.T) {
+	receivers, processors, exporters, err := ExampleComponents()
+	assert.Nil(t, err)
+
+	CheckDefaultConfigs(t, receivers, processors, exporters)
+}
+
+func TestCheckConfigStruct(t *testing.T) {
+	type nested struct {
+		Field string
+	}
+	type incomplete struct {
+		configmodels.ProcessorSettings `mapstructure:",squash"`
+		Nested                         nested `mapstructure:"nested"`
+	}
+	assert.NoError(t, checkConfigStruct(reflect.TypeOf(&ExampleProcessor{}), map[reflect.Type]bool{}))
+	assert.EqualError(t, checkConfigStruct(reflect.TypeOf(&incomplete{}), map[reflect.Type]bool{}),
+		"field nested.Field has no mapstructure tag")
 }
 
 func TestDecodeConfig_Invalid(t *testing.T) {
@@ -193,7 +448,6 @@ func TestDecodeConfig_Invalid(t *testing.T) {
 		{name: "pipeline-exporter-not-exists", expected: errPipelineExporterNotExists},
 		{name: "pipeline-processor-not-exists", expected: errPipelineProcessorNotExists},
 		{name: "pipeline-must-have-processors", expected: errPipelineMustHaveProcessors},
-		{name: "metric-pipeline-cannot-have-processors", expected: errMetricPipelineCannotHaveProcessors},
 		{name: "unknown-receiver-type", expected: errUnknownReceiverType},
 		{name: "unknown-exporter-type", expected: errUnknownExporterType},
 		{name: "unknown-processor-type", expected: errUnknownProcessorType},
@@ -201,12 +455,28 @@ func TestDecodeConfig_Invalid(t *testing.T) {
 		{name: "invalid-sequence-value", expected: errUnmarshalError},
 		{name: "invalid-disabled-bool-value", expected: errUnmarshalError},
 		{name: "invalid-disabled-bool-value2", expected: errUnmarshalError},
+		{name: "invalid-enabled-value", expected: errInvalidEnabled},
+		{name: "invalid-enabled-and-disabled", expected: errInvalidEnabled},
 		{name: "invalid-pipeline-type", expected: errInvalidPipelineType},
 		{name: "invalid-pipeline-type-and-name", expected: errInvalidTypeAndNameKey},
 		{name: "duplicate-receiver", expected: errDuplicateReceiverName},
 		{name: "duplicate-exporter", expected: errDuplicateExporterName},
 		{name: "duplicate-processor", expected: errDuplicateProcessorName},
 		{name: "duplicate-pipeline", expected: errDuplicatePipelineName},
+		{name: "invalid-telemetry-level", expected: errInvalidTelemetryLevel},
+		{name: "invalid-telemetry-traces-pipeline", expected: errInvalidTelemetryTraces},
+		{name: "invalid-telemetry-sampling-rate", expected: errInvalidTelemetryTraces},
+		{name: "invalid-telemetry-logs-encoding", expected: errInvalidTelemetryLogs},
+		{name: "invalid-telemetry-logs-component", expected: errInvalidTelemetryLogs},
+		{name: "invalid-runtime-ballast-percentage", expected: errInvalidRuntime},
+		{name: "invalid-restart-policy-action", expected: errInvalidRestartPolicy},
+		{name: "invalid-restart-policy-interval", expected: errInvalidRestartPolicy},
+		{name: "invalid-debug-batches", expected: errInvalidDebug},
+		{name: "invalid-tuning-token", expected: errInvalidTuning},
+		{name: "pipeline-receiver-not-connector", expected: errPipelineReceiverNotExists},
+		{name: "pipeline-connector-cycle", expected: errPipelineConnectorCycle},
+		{name: "invalid-receiver-route-pattern", expected: errInvalidReceiverRoute},
+		{name: "invalid-receiver-route-pipeline", expected: errInvalidReceiverRoute},
 	}
 
 	receivers, processors, exporters, err := ExampleComponents()
@@ -0,0 +1,142 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configauth implements the authentication settings shared by
+// receivers and the middlewares rejecting unauthenticated requests.
+package configauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrUnauthenticated is returned when a request doesn't carry valid credentials.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator checks the credentials of the requests received by a receiver.
+type Authenticator interface {
+	// Authenticate returns an error if the request, described by its headers
+	// (gRPC metadata or HTTP headers) with lower case names, is not allowed.
+	Authenticate(ctx context.Context, headers map[string][]string) error
+}
+
+// Settings configures how the receivers authenticate the requests. A request
+// is accepted if it matches any of the configured methods.
+type Settings struct {
+	// BearerTokens are the static tokens accepted in the
+	// "Authorization: Bearer <token>" header.
+	BearerTokens []string `mapstructure:"bearer-tokens,omitempty"`
+
+	// APIKeyHeader is the name of the header carrying the API key, e.g. "X-API-Key".
+	APIKeyHeader string `mapstructure:"api-key-header,omitempty"`
+
+	// APIKeys are the accepted API keys, requires APIKeyHeader.
+	APIKeys []string `mapstructure:"api-keys,omitempty"`
+
+	// JWT validates the JSON Web Tokens received in the "Authorization: Bearer
+	// <token>" header, e.g. issued by an OpenID Connect provider.
+	JWT *JWTSettings `mapstructure:"jwt,omitempty"`
+}
+
+// JWTSettings configures the validation of JSON Web Tokens.
+type JWTSettings struct {
+	// PublicKeyFile is the path to the PEM encoded public key, or certificate,
+	// verifying the signature of the tokens. RSA (RS256, RS384, RS512) and
+	// ECDSA (ES256, ES384, ES512) keys are supported.
+	PublicKeyFile string `mapstructure:"public-key-file"`
+
+	// Issuer, if set, must match the "iss" claim of the tokens.
+	Issuer string `mapstructure:"issuer,omitempty"`
+
+	// Audience, if set, must be one of the "aud" claim of the tokens.
+	Audience string `mapstructure:"audience,omitempty"`
+}
+
+// NewAuthenticator returns the Authenticator implementing the settings.
+func (s Settings) NewAuthenticator() (Authenticator, error) {
+	if len(s.APIKeys) > 0 && s.APIKeyHeader == "" {
+		return nil, errors.New("api-keys require api-key-header")
+	}
+	if len(s.BearerTokens) == 0 && len(s.APIKeys) == 0 && s.JWT == nil {
+		return nil, errors.New("no authentication method is configured")
+	}
+
+	a := &authenticator{
+		bearerTokens: s.BearerTokens,
+		apiKeyHeader: strings.ToLower(s.APIKeyHeader),
+		apiKeys:      s.APIKeys,
+	}
+	if s.JWT != nil {
+		var err error
+		if a.jwt, err = newJWTVerifier(*s.JWT); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+type authenticator struct {
+	bearerTokens []string
+	apiKeyHeader string
+	apiKeys      []string
+	jwt          *jwtVerifier
+}
+
+var _ Authenticator = (*authenticator)(nil)
+
+func (a *authenticator) Authenticate(ctx context.Context, headers map[string][]string) error {
+	for _, value := range headers["authorization"] {
+		token, ok := bearerToken(value)
+		if !ok {
+			continue
+		}
+		if matchAny(token, a.bearerTokens) {
+			return nil
+		}
+		if a.jwt != nil && a.jwt.verify(token, time.Now()) == nil {
+			return nil
+		}
+	}
+	if a.apiKeyHeader != "" {
+		for _, value := range headers[a.apiKeyHeader] {
+			if matchAny(value, a.apiKeys) {
+				return nil
+			}
+		}
+	}
+	return ErrUnauthenticated
+}
+
+func bearerToken(authorization string) (string, bool) {
+	const prefix = "bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
+
+// matchAny compares the value in constant time to avoid leaking the
+// accepted secrets.
+func matchAny(value string, secrets []string) bool {
+	matched := false
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(secret)) == 1 {
+			matched = true
+		}
+	}
+	return matched
+}
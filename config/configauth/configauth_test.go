@@ -0,0 +1,108 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	_, err := Settings{}.NewAuthenticator()
+	assert.Error(t, err)
+
+	_, err = Settings{APIKeys: []string{"key"}}.NewAuthenticator()
+	assert.Error(t, err)
+
+	_, err = Settings{JWT: &JWTSettings{PublicKeyFile: "testdata/missing.pem"}}.NewAuthenticator()
+	assert.Error(t, err)
+}
+
+func TestAuthenticate(t *testing.T) {
+	a, err := Settings{
+		BearerTokens: []string{"token1", "token2"},
+		APIKeyHeader: "X-API-Key",
+		APIKeys:      []string{"key1"},
+	}.NewAuthenticator()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		allowed bool
+	}{
+		{name: "no_credentials"},
+		{name: "bearer", headers: map[string][]string{"authorization": {"Bearer token2"}}, allowed: true},
+		{name: "bearer_lower_case", headers: map[string][]string{"authorization": {"bearer token1"}}, allowed: true},
+		{name: "wrong_bearer", headers: map[string][]string{"authorization": {"Bearer token3"}}},
+		{name: "basic", headers: map[string][]string{"authorization": {"Basic token1"}}},
+		{name: "api_key", headers: map[string][]string{"x-api-key": {"key1"}}, allowed: true},
+		{name: "wrong_api_key", headers: map[string][]string{"x-api-key": {"token1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.Authenticate(context.Background(), tt.headers)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, ErrUnauthenticated, err)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	a, err := Settings{BearerTokens: []string{"token"}}.NewAuthenticator()
+	require.NoError(t, err)
+	handler := HTTPHandler(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/v2/spans", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req.Header.Set("Authorization", "Bearer token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a, err := Settings{APIKeyHeader: "X-API-Key", APIKeys: []string{"key"}}.NewAuthenticator()
+	require.NoError(t, err)
+	interceptor := UnaryServerInterceptor(a)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-API-Key", "key"))
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
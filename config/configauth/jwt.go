@@ -0,0 +1,188 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // Register the SHA-256 hash used by RS256 and ES256.
+	_ "crypto/sha512" // Register the SHA-384 and SHA-512 hashes.
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var jwtAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtVerifier verifies the signature and the claims of JSON Web Tokens.
+type jwtVerifier struct {
+	key      crypto.PublicKey
+	issuer   string
+	audience string
+}
+
+func newJWTVerifier(s JWTSettings) (*jwtVerifier, error) {
+	data, err := ioutil.ReadFile(s.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the JWT public key file %q: %v", s.PublicKeyFile, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode the JWT public key file %q", s.PublicKeyFile)
+	}
+
+	var key crypto.PublicKey
+	if block.Type == "CERTIFICATE" {
+		cert, cerr := x509.ParseCertificate(block.Bytes)
+		if cerr != nil {
+			return nil, fmt.Errorf("failed to parse the JWT certificate %q: %v", s.PublicKeyFile, cerr)
+		}
+		key = cert.PublicKey
+	} else if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return nil, fmt.Errorf("failed to parse the JWT public key %q: %v", s.PublicKeyFile, err)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported JWT public key type %T", key)
+	}
+	return &jwtVerifier{key: key, issuer: s.Issuer, audience: s.Audience}, nil
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+}
+
+func (v *jwtVerifier) verify(token string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	hash, ok := jwtAlgorithms[header.Algorithm]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if err := v.verifySignature(header.Algorithm, hash, hasher.Sum(nil), signature); err != nil {
+		return err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return err
+	}
+	return v.verifyClaims(claims, now)
+}
+
+func (v *jwtVerifier) verifySignature(alg string, hash crypto.Hash, digest, signature []byte) error {
+	switch key := v.key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %q doesn't match the RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %q doesn't match the ECDSA key", alg)
+		}
+		size := (key.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature size")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported key")
+}
+
+func (v *jwtVerifier) verifyClaims(claims jwtClaims, now time.Time) error {
+	if claims.ExpiresAt == nil || now.Unix() >= *claims.ExpiresAt {
+		return errors.New("token is expired")
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return errors.New("token is not valid yet")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if v.audience != "" && !hasAudience(claims.Audience, v.audience) {
+		return errors.New("unexpected audience")
+	}
+	return nil
+}
+
+// hasAudience returns whether the "aud" claim, a string or an array of
+// strings, contains the audience.
+func hasAudience(raw json.RawMessage, audience string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var multiple []string
+	if err := json.Unmarshal(raw, &multiple); err != nil {
+		return false
+	}
+	for _, aud := range multiple {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
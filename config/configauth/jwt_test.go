@@ -0,0 +1,140 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePublicKey(t *testing.T, dir string, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	file := path.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return file
+}
+
+func signJWT(t *testing.T, alg string, key crypto.Signer, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hasher := jwtAlgorithms[alg].New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, jwtAlgorithms[alg], digest)
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		require.NoError(t, err)
+		size := (k.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[size-len(rBytes):size], rBytes)
+		copy(signature[2*size-len(sBytes):], sBytes)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWT(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configauth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := map[string]interface{}{"iss": "https://issuer", "aud": []string{"collector"}, "exp": exp}
+
+	tests := []struct {
+		name    string
+		alg     string
+		key     crypto.Signer
+		claims  map[string]interface{}
+		allowed bool
+	}{
+		{name: "rs256", alg: "RS256", key: rsaKey, claims: valid, allowed: true},
+		{name: "rs512", alg: "RS512", key: rsaKey, claims: valid, allowed: true},
+		{name: "single_audience", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://issuer", "aud": "collector", "exp": exp}, allowed: true},
+		{name: "other_key", alg: "RS256", key: otherKey, claims: valid},
+		{name: "expired", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://issuer", "aud": "collector", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{name: "no_expiration", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://issuer", "aud": "collector"}},
+		{name: "not_valid_yet", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://issuer", "aud": "collector", "exp": exp, "nbf": exp}},
+		{name: "other_issuer", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://other", "aud": "collector", "exp": exp}},
+		{name: "other_audience", alg: "RS256", key: rsaKey,
+			claims: map[string]interface{}{"iss": "https://issuer", "aud": "other", "exp": exp}},
+	}
+	a, err := Settings{JWT: &JWTSettings{
+		PublicKeyFile: writePublicKey(t, dir, rsaKey.Public()),
+		Issuer:        "https://issuer",
+		Audience:      "collector",
+	}}.NewAuthenticator()
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signJWT(t, tt.alg, tt.key, tt.claims)
+			err := a.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + token}})
+			assert.Equal(t, tt.allowed, err == nil)
+		})
+	}
+
+	// The algorithm must match the type of the key.
+	ecAuth, err := Settings{JWT: &JWTSettings{PublicKeyFile: writePublicKey(t, dir, ecKey.Public())}}.NewAuthenticator()
+	require.NoError(t, err)
+	token := signJWT(t, "ES256", ecKey, valid)
+	assert.NoError(t, ecAuth.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + token}}))
+	token = signJWT(t, "RS256", rsaKey, valid)
+	assert.Error(t, ecAuth.Authenticate(context.Background(), map[string][]string{"authorization": {"Bearer " + token}}))
+}
+
+func TestJWT_Malformed(t *testing.T) {
+	v := &jwtVerifier{}
+	for _, token := range []string{"", "a.b", "a.b.c", "eyJhbGciOiJub25lIn0.e30."} {
+		assert.Error(t, v.verify(token, time.Now()), token)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a gRPC interceptor rejecting the unary calls
+// that the Authenticator doesn't allow.
+func UnaryServerInterceptor(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authenticateGRPC(ctx, a); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor rejecting the streams
+// that the Authenticator doesn't allow.
+func StreamServerInterceptor(a Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticateGRPC(ss.Context(), a); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authenticateGRPC(ctx context.Context, a Authenticator) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if err := a.Authenticate(ctx, md); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// HTTPHandler returns a http.Handler responding 401 Unauthorized to the
+// requests that the Authenticator doesn't allow, and passing the other ones
+// to the given handler.
+func HTTPHandler(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers := make(map[string][]string, len(r.Header))
+		for name, values := range r.Header {
+			headers[strings.ToLower(name)] = values
+		}
+		if err := a.Authenticate(r.Context(), headers); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/grpc/credentials"
+)
+
+// defaultGoogleScope is the scope of the Google credentials when none is
+// configured.
+const defaultGoogleScope = "https://www.googleapis.com/auth/cloud-platform"
+
+var (
+	errNoCredentials        = errors.New("auth requires one of bearer-token, oauth2 or google")
+	errMultipleCredentials  = errors.New("auth accepts only one of bearer-token, oauth2 or google")
+	errInvalidOAuth2        = errors.New("auth oauth2 requires client-id and token-url")
+	errAuthRequiresSecurity = errors.New("auth requires TLS, the credentials would be sent in clear text")
+)
+
+// PerRPCAuthSettings configures the credentials sent with every call, in the
+// authorization header. Exactly one kind of credentials must be set, and the
+// connection must use TLS.
+type PerRPCAuthSettings struct {
+	// BearerToken is a static token.
+	BearerToken string `mapstructure:"bearer-token,omitempty"`
+
+	// OAuth2 gets the tokens with the OAuth2 client credentials flow, they
+	// are refreshed before they expire.
+	OAuth2 *OAuth2ClientCredentials `mapstructure:"oauth2,omitempty"`
+
+	// Google gets the tokens from the Google application default credentials,
+	// see https://cloud.google.com/docs/authentication/production.
+	Google *GoogleCredentials `mapstructure:"google,omitempty"`
+}
+
+// OAuth2ClientCredentials configures the OAuth2 client credentials flow.
+type OAuth2ClientCredentials struct {
+	ClientID     string   `mapstructure:"client-id"`
+	ClientSecret string   `mapstructure:"client-secret"`
+	TokenURL     string   `mapstructure:"token-url"`
+	Scopes       []string `mapstructure:"scopes,omitempty"`
+
+	// EndpointParams are additional parameters of the token requests, e.g.
+	// the audience.
+	EndpointParams map[string]string `mapstructure:"endpoint-params,omitempty"`
+}
+
+// GoogleCredentials configures the Google application default credentials.
+type GoogleCredentials struct {
+	// Scopes default to https://www.googleapis.com/auth/cloud-platform.
+	Scopes []string `mapstructure:"scopes,omitempty"`
+}
+
+// PerRPCCredentials returns the credentials of the settings, see
+// grpc.WithPerRPCCredentials.
+func (s *PerRPCAuthSettings) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
+	set := 0
+	if s.BearerToken != "" {
+		set++
+	}
+	if s.OAuth2 != nil {
+		set++
+	}
+	if s.Google != nil {
+		set++
+	}
+	switch {
+	case set == 0:
+		return nil, errNoCredentials
+	case set > 1:
+		return nil, errMultipleCredentials
+	}
+
+	switch {
+	case s.OAuth2 != nil:
+		if s.OAuth2.ClientID == "" || s.OAuth2.TokenURL == "" {
+			return nil, errInvalidOAuth2
+		}
+		cfg := &clientcredentials.Config{
+			ClientID:     s.OAuth2.ClientID,
+			ClientSecret: s.OAuth2.ClientSecret,
+			TokenURL:     s.OAuth2.TokenURL,
+			Scopes:       s.OAuth2.Scopes,
+		}
+		if len(s.OAuth2.EndpointParams) > 0 {
+			cfg.EndpointParams = url.Values{}
+			for k, v := range s.OAuth2.EndpointParams {
+				cfg.EndpointParams.Set(k, v)
+			}
+		}
+		// The token source outlives the call creating it, it refreshes the
+		// tokens in the background of the calls.
+		return tokenCredentials{cfg.TokenSource(context.Background())}, nil
+	case s.Google != nil:
+		scopes := s.Google.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{defaultGoogleScope}
+		}
+		ts, err := google.DefaultTokenSource(context.Background(), scopes...)
+		if err != nil {
+			return nil, err
+		}
+		return tokenCredentials{ts}, nil
+	default:
+		return tokenCredentials{oauth2.StaticTokenSource(&oauth2.Token{AccessToken: s.BearerToken})}, nil
+	}
+}
+
+// tokenCredentials sends the tokens of an oauth2.TokenSource, which caches
+// them until they expire.
+type tokenCredentials struct {
+	source oauth2.TokenSource
+}
+
+var _ credentials.PerRPCCredentials = tokenCredentials{}
+
+func (tc tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := tc.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": token.Type() + " " + token.AccessToken}, nil
+}
+
+func (tc tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+)
+
+func TestPerRPCCredentialsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings PerRPCAuthSettings
+	}{
+		{name: "none"},
+		{
+			name: "multiple",
+			settings: PerRPCAuthSettings{
+				BearerToken: "token",
+				Google:      &GoogleCredentials{},
+			},
+		},
+		{
+			name: "oauth2 without token url",
+			settings: PerRPCAuthSettings{
+				OAuth2: &OAuth2ClientCredentials{ClientID: "otelsvc"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.settings.PerRPCCredentials()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAuthRequiresTLS(t *testing.T) {
+	settings := GRPCClientSettings{
+		Endpoint: "localhost:14250",
+		Auth:     &PerRPCAuthSettings{BearerToken: "token"},
+	}
+	_, _, err := settings.ToDialOptions()
+	assert.Equal(t, errAuthRequiresSecurity, err)
+}
+
+func TestAuth(t *testing.T) {
+	var tokens int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "collector.example.com", r.Form.Get("audience"))
+		id, secret, _ := r.BasicAuth()
+		assert.Equal(t, "otelsvc", id)
+		assert.Equal(t, "secret", secret)
+		n := atomic.AddInt32(&tokens, 1)
+		w.Header().Set("Content-Type", "application/json")
+		// The token expires right away, it is refreshed on every call.
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":1}`, n)
+	}))
+	defer tokenServer.Close()
+
+	serverCfg, err := configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: serverCertFile, KeyFile: serverKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan []string, 2)
+	srv := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(serverCfg)),
+		grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			received <- md.Get("authorization")
+			if err := stream.RecvMsg(&empty.Empty{}); err != nil {
+				return err
+			}
+			return stream.SendMsg(&empty.Empty{})
+		}))
+	defer srv.Stop()
+	go srv.Serve(ln)
+
+	tlsSetting := &configtls.TLSClientSetting{
+		TLSSetting:         configtls.TLSSetting{CAFile: caFile},
+		ServerNameOverride: "server.example.com",
+	}
+	// invoke makes two calls and returns their authorization headers.
+	invoke := func(auth *PerRPCAuthSettings) []string {
+		settings := GRPCClientSettings{Endpoint: ln.Addr().String(), TLSSetting: tlsSetting, Auth: auth}
+		target, opts, err := settings.ToDialOptions()
+		require.NoError(t, err)
+		conn, err := grpc.Dial(target, opts...)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var headers []string
+		for i := 0; i < 2; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			require.NoError(t, conn.Invoke(ctx, "/test.Service/Method", &empty.Empty{}, &empty.Empty{}))
+			cancel()
+			headers = append(headers, <-received...)
+		}
+		return headers
+	}
+
+	assert.Equal(t, []string{"Bearer static", "Bearer static"}, invoke(&PerRPCAuthSettings{BearerToken: "static"}))
+
+	oauth2 := &PerRPCAuthSettings{
+		OAuth2: &OAuth2ClientCredentials{
+			ClientID:       "otelsvc",
+			ClientSecret:   "secret",
+			TokenURL:       tokenServer.URL,
+			EndpointParams: map[string]string{"audience": "collector.example.com"},
+		},
+	}
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, invoke(oauth2))
+}
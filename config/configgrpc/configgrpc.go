@@ -0,0 +1,205 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configgrpc implements the gRPC client settings shared by the
+// exporters, and the interceptor chaining shared by the receivers.
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/compression"
+	compressiongrpc "github.com/open-telemetry/opentelemetry-service/compression/grpc"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+)
+
+// KeepaliveClientConfig exposes the keepalive.ClientParameters of the
+// connection, see grpc.WithKeepaliveParams.
+type KeepaliveClientConfig struct {
+	Time                time.Duration `mapstructure:"time,omitempty"`
+	Timeout             time.Duration `mapstructure:"timeout,omitempty"`
+	PermitWithoutStream bool          `mapstructure:"permit-without-stream,omitempty"`
+}
+
+// DialOption returns the dial option sending the keepalive pings.
+func (kc *KeepaliveClientConfig) DialOption() grpc.DialOption {
+	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                kc.Time,
+		Timeout:             kc.Timeout,
+		PermitWithoutStream: kc.PermitWithoutStream,
+	})
+}
+
+// BalancerDialOption returns the dial option spreading the calls of a
+// connection across the addresses resolved for its target with the given
+// load balancing policy, e.g. "round_robin". The default policy of gRPC,
+// "pick_first", sends all the calls to the first address.
+func BalancerDialOption(balancerName string) (grpc.DialOption, error) {
+	if balancer.Get(balancerName) == nil {
+		return nil, fmt.Errorf("unsupported balancer %q", balancerName)
+	}
+	return grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingPolicy":%q}`, balancerName)), nil
+}
+
+// GRPCClientSettings defines the connection of a gRPC client, e.g. an
+// exporter, to its server.
+type GRPCClientSettings struct {
+	// Endpoint is the target of the connection, the valid syntax is described
+	// at https://github.com/grpc/grpc/blob/master/doc/naming.md, or
+	// "unix:///path/to/socket" for a Unix domain socket.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLSSetting configures the TLS of the connection, if not set the
+	// connection is not secure.
+	TLSSetting *configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Compression is the compression of the requests, "gzip", "snappy" or
+	// "zstd". If empty the requests are not compressed.
+	Compression string `mapstructure:"compression,omitempty"`
+
+	// Keepalive configures the keepalive pings of the connection.
+	Keepalive *KeepaliveClientConfig `mapstructure:"keepalive,omitempty"`
+
+	// BalancerName is the load balancing policy spreading the calls across
+	// the addresses of the endpoint, e.g. "round_robin" with an endpoint like
+	// "dns:///collector.example.com:55678". If empty the calls are sent to
+	// the first address.
+	BalancerName string `mapstructure:"balancer-name,omitempty"`
+
+	// WaitForReady makes the calls wait for the connection to be ready,
+	// within their deadline, instead of failing right away while it is down.
+	WaitForReady bool `mapstructure:"wait-for-ready"`
+
+	// ForwardHeaders are the metadata keys, e.g. the gRPC or HTTP headers,
+	// received with the data that are sent as headers of the calls.
+	ForwardHeaders []string `mapstructure:"forward-headers,omitempty"`
+
+	// Auth configures the credentials sent with every call, it requires TLS.
+	Auth *PerRPCAuthSettings `mapstructure:"auth,omitempty"`
+
+	// UserAgent is the user agent of the calls, it is set by the factories
+	// from the build of the service, see buildinfo.
+	UserAgent string `mapstructure:"-"`
+}
+
+// ToDialOptions returns the target and the dial options of the connection.
+func (gcs *GRPCClientSettings) ToDialOptions() (string, []grpc.DialOption, error) {
+	target, opts := confignet.GRPCDialOptions(gcs.Endpoint)
+
+	secure := false
+	if gcs.TLSSetting != nil {
+		tlsCfg, err := gcs.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid TLS configuration: %v", err)
+		}
+		if tlsCfg != nil {
+			opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+			secure = true
+		}
+	}
+	if !secure {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if gcs.Auth != nil {
+		if !secure {
+			return "", nil, errAuthRequiresSecurity
+		}
+		creds, err := gcs.Auth.PerRPCCredentials()
+		if err != nil {
+			return "", nil, err
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(creds))
+	}
+
+	if gcs.UserAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(gcs.UserAgent))
+	}
+
+	if gcs.Compression != "" {
+		compressionKey := compressiongrpc.GetGRPCCompressionKey(gcs.Compression)
+		if compressionKey == compression.Unsupported {
+			return "", nil, fmt.Errorf("unsupported compression type %q", gcs.Compression)
+		}
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(compressionKey)))
+	}
+
+	if gcs.Keepalive != nil {
+		opts = append(opts, gcs.Keepalive.DialOption())
+	}
+
+	if gcs.BalancerName != "" {
+		balancerOpt, err := BalancerDialOption(gcs.BalancerName)
+		if err != nil {
+			return "", nil, err
+		}
+		opts = append(opts, balancerOpt)
+	}
+
+	if gcs.WaitForReady {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	}
+
+	if len(gcs.ForwardHeaders) > 0 {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(gcs.forwardUnaryHeaders),
+			grpc.WithStreamInterceptor(gcs.forwardStreamHeaders))
+	}
+	return target, opts, nil
+}
+
+// forwardedContext returns the context of a call with the forwarded metadata
+// of the client of the data as outgoing metadata.
+func (gcs *GRPCClientSettings) forwardedContext(ctx context.Context) context.Context {
+	md := client.ForwardedMetadata(ctx, gcs.ForwardHeaders)
+	if md == nil {
+		return ctx
+	}
+	if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(outgoing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func (gcs *GRPCClientSettings) forwardUnaryHeaders(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	return invoker(gcs.forwardedContext(ctx), method, req, reply, cc, opts...)
+}
+
+func (gcs *GRPCClientSettings) forwardStreamHeaders(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return streamer(gcs.forwardedContext(ctx), desc, cc, method, opts...)
+}
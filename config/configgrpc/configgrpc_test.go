@@ -0,0 +1,330 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+)
+
+const (
+	caFile         = "../configtls/testdata/ca.crt"
+	serverCertFile = "../configtls/testdata/server.crt"
+	serverKeyFile  = "../configtls/testdata/server.key"
+)
+
+func TestToDialOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings GRPCClientSettings
+		wantOpts int
+		mustFail bool
+	}{
+		{
+			name:     "insecure",
+			settings: GRPCClientSettings{Endpoint: "localhost:14250"},
+			wantOpts: 1,
+		},
+		{
+			name: "tls",
+			settings: GRPCClientSettings{
+				Endpoint:   "localhost:14250",
+				TLSSetting: &configtls.TLSClientSetting{ServerNameOverride: "collector.example.com"},
+			},
+			wantOpts: 1,
+		},
+		{
+			name: "all",
+			settings: GRPCClientSettings{
+				Endpoint:     "unix:///var/run/jaeger.sock",
+				TLSSetting:   &configtls.TLSClientSetting{Insecure: true},
+				Compression:  "zstd",
+				Keepalive:    &KeepaliveClientConfig{Time: time.Minute, Timeout: 10 * time.Second},
+				BalancerName: "round_robin",
+				WaitForReady: true,
+				UserAgent:    "otelsvc/latest",
+			},
+			// The dialer and authority of the Unix domain socket, the
+			// insecure credentials, user agent, compression, keepalive,
+			// balancer and wait for ready.
+			wantOpts: 8,
+		},
+		{
+			name: "invalid compression",
+			settings: GRPCClientSettings{
+				Endpoint:    "localhost:14250",
+				Compression: "lz4",
+			},
+			mustFail: true,
+		},
+		{
+			name: "invalid balancer",
+			settings: GRPCClientSettings{
+				Endpoint:     "dns:///localhost:14250",
+				BalancerName: "random",
+			},
+			mustFail: true,
+		},
+		{
+			name: "invalid tls",
+			settings: GRPCClientSettings{
+				Endpoint:   "localhost:14250",
+				TLSSetting: &configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"}},
+			},
+			mustFail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, opts, err := tt.settings.ToDialOptions()
+			if tt.mustFail {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, tt.wantOpts)
+		})
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	// Nothing listens on the endpoint.
+	endpoint := testutils.GetAvailableLocalAddress(t)
+	invoke := func(waitForReady bool) error {
+		settings := GRPCClientSettings{Endpoint: endpoint, WaitForReady: waitForReady}
+		target, opts, err := settings.ToDialOptions()
+		require.NoError(t, err)
+		conn, err := grpc.Dial(target, opts...)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		return conn.Invoke(ctx, "/test.Service/Method", &empty.Empty{}, &empty.Empty{})
+	}
+
+	// The call fails right away while the connection is down, or waits for
+	// it until its deadline.
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(false)))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(invoke(true)))
+}
+
+func TestBalancer(t *testing.T) {
+	var addrs []resolver.Address
+	var counts []*int32
+	for i := 0; i < 2; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		count := new(int32)
+		srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			atomic.AddInt32(count, 1)
+			return stream.SendMsg(&empty.Empty{})
+		}))
+		defer srv.Stop()
+		go srv.Serve(ln)
+		addrs = append(addrs, resolver.Address{Addr: ln.Addr().String()})
+		counts = append(counts, count)
+	}
+
+	// The target resolves to the addresses of both servers.
+	r := manual.NewBuilderWithScheme("balancer-test")
+	r.InitialState(resolver.State{Addresses: addrs})
+	resolver.Register(r)
+
+	settings := GRPCClientSettings{Endpoint: "balancer-test:///collector", BalancerName: "round_robin"}
+	target, opts, err := settings.ToDialOptions()
+	require.NoError(t, err)
+	conn, err := grpc.Dial(target, append(opts, grpc.WithBlock())...)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = conn.Invoke(ctx, "/test.Service/Method", &empty.Empty{}, &empty.Empty{}, grpc.WaitForReady(true))
+		cancel()
+		require.NoError(t, err)
+	}
+	// Once both connections are ready the calls alternate between them.
+	assert.True(t, atomic.LoadInt32(counts[0]) > 0)
+	assert.True(t, atomic.LoadInt32(counts[1]) > 0)
+	assert.EqualValues(t, 10, atomic.LoadInt32(counts[0])+atomic.LoadInt32(counts[1]))
+}
+
+func TestTLS(t *testing.T) {
+	serverCfg, err := configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: serverCertFile, KeyFile: serverKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverCfg)))
+	defer srv.Stop()
+	go srv.Serve(ln)
+
+	invoke := func(tlsSetting *configtls.TLSClientSetting) error {
+		settings := GRPCClientSettings{Endpoint: ln.Addr().String(), TLSSetting: tlsSetting}
+		target, opts, err := settings.ToDialOptions()
+		require.NoError(t, err)
+		conn, err := grpc.Dial(target, opts...)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return conn.Invoke(ctx, "/test.Service/Method", &empty.Empty{}, &empty.Empty{})
+	}
+
+	// The server has no such method, the call reaching it means the handshake
+	// succeeded.
+	assert.Equal(t, codes.Unimplemented, status.Code(invoke(&configtls.TLSClientSetting{
+		TLSSetting:         configtls.TLSSetting{CAFile: caFile},
+		ServerNameOverride: "server.example.com",
+	})))
+	// The server certificate is not valid for that name.
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(&configtls.TLSClientSetting{
+		TLSSetting:         configtls.TLSSetting{CAFile: caFile},
+		ServerNameOverride: "other.example.com",
+	})))
+	// The server certificate is not signed by a system root CA.
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(&configtls.TLSClientSetting{
+		ServerNameOverride: "server.example.com",
+	})))
+	// The client does not use TLS.
+	assert.Equal(t, codes.Unavailable, status.Code(invoke(nil)))
+}
+
+func TestCompression(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	cln := &countingListener{Listener: ln}
+	received := make(chan int, 1)
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		msg := &wrappers.StringValue{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return err
+		}
+		received <- len(msg.Value)
+		return stream.SendMsg(&empty.Empty{})
+	}))
+	defer srv.Stop()
+	go srv.Serve(cln)
+
+	msg := &wrappers.StringValue{Value: strings.Repeat("opentelemetry ", 10000)}
+	for _, compression := range []string{"gzip", "snappy", "zstd"} {
+		settings := GRPCClientSettings{Endpoint: ln.Addr().String(), Compression: compression}
+		target, opts, err := settings.ToDialOptions()
+		require.NoError(t, err)
+		conn, err := grpc.Dial(target, opts...)
+		require.NoError(t, err)
+
+		before := atomic.LoadInt64(&cln.read)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = conn.Invoke(ctx, "/test.Service/Method", msg, &empty.Empty{})
+		cancel()
+		conn.Close()
+		require.NoError(t, err, compression)
+		assert.Equal(t, len(msg.Value), <-received, compression)
+		// The request was compressed on the wire.
+		assert.True(t, atomic.LoadInt64(&cln.read)-before < int64(len(msg.Value)/10), compression)
+	}
+}
+
+func TestForwardHeaders(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan metadata.MD, 1)
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		received <- md
+		if err := stream.RecvMsg(&empty.Empty{}); err != nil {
+			return err
+		}
+		return stream.SendMsg(&empty.Empty{})
+	}))
+	defer srv.Stop()
+	go srv.Serve(ln)
+
+	settings := GRPCClientSettings{
+		Endpoint:       ln.Addr().String(),
+		ForwardHeaders: []string{"X-Tenant", "authorization"},
+	}
+	target, opts, err := settings.ToDialOptions()
+	require.NoError(t, err)
+	conn, err := grpc.Dial(target, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := client.NewContext(context.Background(), &client.Client{
+		Metadata: map[string][]string{
+			"x-tenant":   {"acme"},
+			"user-agent": {"test"},
+		},
+	})
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-static", "value")
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, conn.Invoke(ctx, "/test.Service/Method", &empty.Empty{}, &empty.Empty{}))
+
+	md := <-received
+	assert.Equal(t, []string{"acme"}, md.Get("x-tenant"))
+	assert.Equal(t, []string{"value"}, md.Get("x-static"))
+	assert.Empty(t, md.Get("authorization"))
+	assert.NotContains(t, md.Get("user-agent"), "test")
+}
+
+// countingListener counts the bytes read from its connections.
+type countingListener struct {
+	net.Listener
+	read int64
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, read: &l.read}, nil
+}
+
+type countingConn struct {
+	net.Conn
+	read *int64
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddInt64(c.read, int64(n))
+	return n, err
+}
@@ -0,0 +1,58 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestChainUnaryServerInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	chain := ChainUnaryServerInterceptors(interceptor("first"), interceptor("second"))
+	resp, err := chain(context.Background(), "req", &grpc.UnaryServerInfo{}, func(_ context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "req", resp)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestChainStreamServerInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.StreamServerInterceptor {
+		return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			calls = append(calls, name)
+			return handler(srv, ss)
+		}
+	}
+	chain := ChainStreamServerInterceptors(interceptor("first"), interceptor("second"))
+	err := chain(nil, nil, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
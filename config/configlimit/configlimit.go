@@ -0,0 +1,306 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configlimit implements the limits shared by receivers, protecting
+// the service from clients sending more data than it can handle, and the
+// middlewares enforcing them.
+package configlimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+)
+
+var (
+	// ErrRateLimited is returned when a request exceeds the allowed rate.
+	ErrRateLimited = errors.New("too many requests")
+
+	// ErrBatchTooLarge is returned when a request contains too many items.
+	ErrBatchTooLarge = errors.New("too many items in the request")
+
+	// ErrRequestTooLarge is returned when the body of a request is too large.
+	ErrRequestTooLarge = errors.New("request too large")
+
+	// ErrAttributeTooLong is returned when a request contains a too long
+	// attribute or label value.
+	ErrAttributeTooLong = errors.New("attribute value too long")
+)
+
+// Settings configures the limits of a receiver. A zero value disables the
+// corresponding limit.
+type Settings struct {
+	// MaxConnections is the maximum number of concurrent connections, the
+	// connections beyond it are closed as soon as they are accepted.
+	MaxConnections int `mapstructure:"max-connections,omitempty"`
+
+	// MaxRequestsPerSecond is the maximum rate of requests, or messages of
+	// the gRPC streams, of all the clients. Bursts of up to one second worth
+	// of requests are allowed.
+	MaxRequestsPerSecond float64 `mapstructure:"max-requests-per-second,omitempty"`
+
+	// MaxBatchSize is the maximum number of spans or metrics in a request.
+	MaxBatchSize int `mapstructure:"max-batch-size,omitempty"`
+
+	// MaxRequestSize is the maximum size in bytes of the body of a request,
+	// or of a gRPC message. The limit also applies to the uncompressed body of
+	// the receivers decompressing it themselves.
+	MaxRequestSize int `mapstructure:"max-request-size,omitempty"`
+
+	// MaxAttributeLength is the maximum length of the string attribute values
+	// of the spans, including their annotations and links, and of the label
+	// values of the metrics in a request.
+	MaxAttributeLength int `mapstructure:"max-attribute-length,omitempty"`
+}
+
+// NewLimiter returns the Limiter implementing the settings for the receiver
+// with the given name, the requests it refuses are recorded with this name.
+func (s Settings) NewLimiter(receiver string) (*Limiter, error) {
+	if s.MaxConnections < 0 || s.MaxRequestsPerSecond < 0 || s.MaxBatchSize < 0 ||
+		s.MaxRequestSize < 0 || s.MaxAttributeLength < 0 {
+		return nil, fmt.Errorf("negative limits are invalid: %+v", s)
+	}
+
+	l := &Limiter{
+		receiver:           receiver,
+		maxConnections:     s.MaxConnections,
+		maxBatchSize:       s.MaxBatchSize,
+		maxRequestSize:     s.MaxRequestSize,
+		maxAttributeLength: s.MaxAttributeLength,
+		rate:               s.MaxRequestsPerSecond,
+		burst:              math.Max(1, math.Ceil(s.MaxRequestsPerSecond)),
+		now:                time.Now,
+	}
+	l.tokens = l.burst
+	l.last = l.now()
+	return l, nil
+}
+
+// Limiter enforces the limits of a receiver.
+type Limiter struct {
+	receiver           string
+	maxConnections     int
+	maxBatchSize       int
+	maxRequestSize     int
+	maxAttributeLength int
+
+	// The requests are rate limited with a token bucket, holding up to burst
+	// tokens and refilled at rate tokens per second.
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// Allow returns ErrRateLimited if a new request exceeds the allowed rate.
+func (l *Limiter) Allow() error {
+	if l.rate == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		return ErrRateLimited
+	}
+	l.tokens--
+	return nil
+}
+
+// CheckRate returns ErrRateLimited like Allow, recording the refused request
+// with the transport of the context, see obsreport.WithTransport.
+func (l *Limiter) CheckRate(ctx context.Context) error {
+	if err := l.Allow(); err != nil {
+		l.refuse(ctx, obsreport.RefusedReasonRate)
+		return err
+	}
+	return nil
+}
+
+// CheckBatchSize returns ErrBatchTooLarge if a request with the given number
+// of spans or metrics exceeds the maximum batch size.
+func (l *Limiter) CheckBatchSize(items int) error {
+	if l.maxBatchSize > 0 && items > l.maxBatchSize {
+		return ErrBatchTooLarge
+	}
+	return nil
+}
+
+// CheckSpans returns ErrBatchTooLarge or ErrAttributeTooLong if the spans of
+// a request exceed the limits, and records the refused request with the
+// transport of the context, see obsreport.WithTransport.
+func (l *Limiter) CheckSpans(ctx context.Context, spans []*tracepb.Span) error {
+	if err := l.CheckBatchSize(len(spans)); err != nil {
+		l.refuse(ctx, obsreport.RefusedReasonBatchSize)
+		return err
+	}
+	if l.maxAttributeLength > 0 && !spansAttributesFit(spans, l.maxAttributeLength) {
+		l.refuse(ctx, obsreport.RefusedReasonAttributeLength)
+		return ErrAttributeTooLong
+	}
+	return nil
+}
+
+// CheckMetrics returns ErrBatchTooLarge or ErrAttributeTooLong if the metrics
+// of a request exceed the limits, and records the refused request with the
+// transport of the context, see obsreport.WithTransport.
+func (l *Limiter) CheckMetrics(ctx context.Context, metrics []*metricspb.Metric) error {
+	if err := l.CheckBatchSize(len(metrics)); err != nil {
+		l.refuse(ctx, obsreport.RefusedReasonBatchSize)
+		return err
+	}
+	if l.maxAttributeLength > 0 && !labelValuesFit(metrics, l.maxAttributeLength) {
+		l.refuse(ctx, obsreport.RefusedReasonAttributeLength)
+		return ErrAttributeTooLong
+	}
+	return nil
+}
+
+// ReadBody reads the body of a request, returning ErrRequestTooLarge and
+// recording the refused request if it is larger than the maximum request
+// size.
+func (l *Limiter) ReadBody(ctx context.Context, body io.Reader) ([]byte, error) {
+	if l.maxRequestSize == 0 {
+		return ioutil.ReadAll(body)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(body, int64(l.maxRequestSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > l.maxRequestSize {
+		l.refuse(ctx, obsreport.RefusedReasonRequestSize)
+		return nil, ErrRequestTooLarge
+	}
+	return data, nil
+}
+
+// refuse records a request refused for the given reason.
+func (l *Limiter) refuse(ctx context.Context, reason string) {
+	obsreport.RecordReceiverRefusedRequest(obsreport.ReceiverContext(ctx, l.receiver), reason)
+}
+
+func spansAttributesFit(spans []*tracepb.Span, maxLength int) bool {
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		if !attributesFit(span.Attributes, maxLength) {
+			return false
+		}
+		if span.TimeEvents != nil {
+			for _, te := range span.TimeEvents.TimeEvent {
+				if a := te.GetAnnotation(); a != nil && !attributesFit(a.Attributes, maxLength) {
+					return false
+				}
+			}
+		}
+		if span.Links != nil {
+			for _, link := range span.Links.Link {
+				if link != nil && !attributesFit(link.Attributes, maxLength) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func attributesFit(attrs *tracepb.Span_Attributes, maxLength int) bool {
+	if attrs == nil {
+		return true
+	}
+	for _, v := range attrs.AttributeMap {
+		if s := v.GetStringValue(); s != nil && len(s.Value) > maxLength {
+			return false
+		}
+	}
+	return true
+}
+
+func labelValuesFit(metrics []*metricspb.Metric, maxLength int) bool {
+	for _, metric := range metrics {
+		if metric == nil {
+			continue
+		}
+		for _, ts := range metric.Timeseries {
+			for _, lv := range ts.GetLabelValues() {
+				if len(lv.GetValue()) > maxLength {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Listener returns a listener closing the connections accepted beyond the
+// maximum number of concurrent connections.
+func (l *Limiter) Listener(ln net.Listener) net.Listener {
+	if l.maxConnections == 0 {
+		return ln
+	}
+	return &limitListener{Listener: ln, limiter: l, sem: make(chan struct{}, l.maxConnections)}
+}
+
+type limitListener struct {
+	net.Listener
+	limiter *Limiter
+	sem     chan struct{}
+}
+
+func (ln *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := ln.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case ln.sem <- struct{}{}:
+			return &limitConn{Conn: conn, release: func() { <-ln.sem }}, nil
+		default:
+			_ = conn.Close()
+			ln.limiter.refuse(context.Background(), obsreport.RefusedReasonConnections)
+		}
+	}
+}
+
+type limitConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -0,0 +1,371 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configlimit
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+)
+
+func TestNewLimiter(t *testing.T) {
+	_, err := Settings{}.NewLimiter("test")
+	assert.NoError(t, err)
+	_, err = Settings{MaxConnections: -1}.NewLimiter("test")
+	assert.Error(t, err)
+	_, err = Settings{MaxRequestsPerSecond: -1}.NewLimiter("test")
+	assert.Error(t, err)
+	_, err = Settings{MaxBatchSize: -1}.NewLimiter("test")
+	assert.Error(t, err)
+	_, err = Settings{MaxRequestSize: -1}.NewLimiter("test")
+	assert.Error(t, err)
+	_, err = Settings{MaxAttributeLength: -1}.NewLimiter("test")
+	assert.Error(t, err)
+}
+
+func TestAllow(t *testing.T) {
+	l, err := Settings{MaxRequestsPerSecond: 2}.NewLimiter("test")
+	require.NoError(t, err)
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	// A burst of one second worth of requests.
+	assert.NoError(t, l.Allow())
+	assert.NoError(t, l.Allow())
+	assert.Equal(t, ErrRateLimited, l.Allow())
+
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, l.Allow())
+	assert.Equal(t, ErrRateLimited, l.Allow())
+
+	// The tokens don't accumulate beyond the burst.
+	now = now.Add(time.Hour)
+	assert.NoError(t, l.Allow())
+	assert.NoError(t, l.Allow())
+	assert.Equal(t, ErrRateLimited, l.Allow())
+
+	unlimited, err := Settings{}.NewLimiter("test")
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, unlimited.Allow())
+	}
+}
+
+func TestCheckBatchSize(t *testing.T) {
+	l, err := Settings{MaxBatchSize: 2}.NewLimiter("test")
+	require.NoError(t, err)
+	assert.NoError(t, l.CheckBatchSize(2))
+	assert.Equal(t, ErrBatchTooLarge, l.CheckBatchSize(3))
+
+	unlimited, err := Settings{}.NewLimiter("test")
+	require.NoError(t, err)
+	assert.NoError(t, unlimited.CheckBatchSize(1000000))
+}
+
+func stringAttributes(value string) *tracepb.Span_Attributes {
+	return &tracepb.Span_Attributes{
+		AttributeMap: map[string]*tracepb.AttributeValue{
+			"key": {Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: value}}},
+		},
+	}
+}
+
+func TestCheckSpans(t *testing.T) {
+	views := obsreport.Views(telemetry.Basic)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	l, err := Settings{MaxBatchSize: 2, MaxAttributeLength: 3}.NewLimiter("test")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, l.CheckSpans(ctx, []*tracepb.Span{{Attributes: stringAttributes("abc")}, nil}))
+	assert.Equal(t, ErrBatchTooLarge, l.CheckSpans(ctx, []*tracepb.Span{{}, {}, {}}))
+	assert.Equal(t, ErrAttributeTooLong, l.CheckSpans(ctx, []*tracepb.Span{{Attributes: stringAttributes("abcd")}}))
+	assert.Equal(t, ErrAttributeTooLong, l.CheckSpans(ctx, []*tracepb.Span{{
+		TimeEvents: &tracepb.Span_TimeEvents{TimeEvent: []*tracepb.Span_TimeEvent{{
+			Value: &tracepb.Span_TimeEvent_Annotation_{Annotation: &tracepb.Span_TimeEvent_Annotation{
+				Attributes: stringAttributes("abcd"),
+			}},
+		}}},
+	}}))
+	assert.Equal(t, ErrAttributeTooLong, l.CheckSpans(ctx, []*tracepb.Span{{
+		Links: &tracepb.Span_Links{Link: []*tracepb.Span_Link{{Attributes: stringAttributes("abcd")}}},
+	}}))
+
+	rows, err := view.RetrieveData("receiver/refused_requests")
+	require.NoError(t, err)
+	refused := make(map[string]float64)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == obsreport.TagKeyReason {
+				refused[tag.Value] = row.Data.(*view.SumData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		obsreport.RefusedReasonBatchSize:       1,
+		obsreport.RefusedReasonAttributeLength: 3,
+	}, refused)
+
+	unlimited, err := Settings{}.NewLimiter("test")
+	require.NoError(t, err)
+	assert.NoError(t, unlimited.CheckSpans(ctx, []*tracepb.Span{{Attributes: stringAttributes("abcd")}}))
+}
+
+func TestCheckMetrics(t *testing.T) {
+	l, err := Settings{MaxBatchSize: 1, MaxAttributeLength: 3}.NewLimiter("test")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	metric := func(value string) *metricspb.Metric {
+		return &metricspb.Metric{Timeseries: []*metricspb.TimeSeries{{
+			LabelValues: []*metricspb.LabelValue{{Value: value, HasValue: true}},
+		}}}
+	}
+	assert.NoError(t, l.CheckMetrics(ctx, []*metricspb.Metric{metric("abc")}))
+	assert.Equal(t, ErrBatchTooLarge, l.CheckMetrics(ctx, []*metricspb.Metric{metric("a"), metric("b")}))
+	assert.Equal(t, ErrAttributeTooLong, l.CheckMetrics(ctx, []*metricspb.Metric{metric("abcd")}))
+}
+
+func TestReadBody(t *testing.T) {
+	l, err := Settings{MaxRequestSize: 3}.NewLimiter("test")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	body, err := l.ReadBody(ctx, strings.NewReader("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), body)
+	_, err = l.ReadBody(ctx, strings.NewReader("abcd"))
+	assert.Equal(t, ErrRequestTooLarge, err)
+
+	unlimited, err := Settings{}.NewLimiter("test")
+	require.NoError(t, err)
+	body, err = unlimited.ReadBody(ctx, strings.NewReader("abcd"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abcd"), body)
+}
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(GRPCError(ErrAttributeTooLong)))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(GRPCError(ErrBatchTooLarge)))
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(ErrAttributeTooLong))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, HTTPStatus(ErrRequestTooLarge))
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatus(ErrBatchTooLarge))
+}
+
+func TestListener(t *testing.T) {
+	l, err := Settings{MaxConnections: 1}.NewLimiter("test")
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ln = l.Listener(ln)
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	first, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	serverConn := <-accepted
+
+	// The second connection is closed by the server.
+	second, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = second.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	// A new connection is accepted once the first one is closed.
+	require.NoError(t, serverConn.Close())
+	third, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(2 * time.Second):
+		t.Fatal("the connection wasn't accepted")
+	}
+}
+
+func TestGRPCInterceptors(t *testing.T) {
+	l, err := Settings{MaxRequestsPerSecond: 3, MaxBatchSize: 1, MaxAttributeLength: 3}.NewLimiter("test")
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(l)),
+		grpc.StreamInterceptor(StreamServerInterceptor(l)))
+	agenttracepb.RegisterTraceServiceServer(server, &traceServer{})
+	go server.Serve(ln)
+	defer server.Stop()
+
+	cc, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+
+	span := &tracepb.Span{}
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{Spans: []*tracepb.Span{span}}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// Too many spans.
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{Spans: []*tracepb.Span{span, span}}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Too long attribute value, the previous error ended the stream.
+	stream, err = agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{
+		Spans: []*tracepb.Span{{Attributes: stringAttributes("abcd")}},
+	}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Too many requests, the tokens were used by the previous streams.
+	stream, err = agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{Spans: []*tracepb.Span{span}}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// traceServer acknowledges each message of the Export streams with an empty
+// response.
+type traceServer struct{}
+
+func (ts *traceServer) Config(agenttracepb.TraceService_ConfigServer) error {
+	return status.Error(codes.Unimplemented, "")
+}
+
+func (ts *traceServer) Export(stream agenttracepb.TraceService_ExportServer) error {
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return err
+		}
+		if err := stream.Send(&agenttracepb.ExportTraceServiceResponse{}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	l, err := Settings{MaxRequestsPerSecond: 1}.NewLimiter("test")
+	require.NoError(t, err)
+	handler := HTTPHandler(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestGRPCMaxRequestSize(t *testing.T) {
+	l, err := Settings{MaxRequestSize: 100}.NewLimiter("test")
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	opts := append(l.GRPCServerOptions(), grpc.StreamInterceptor(StreamServerInterceptor(l)))
+	server := grpc.NewServer(opts...)
+	agenttracepb.RegisterTraceServiceServer(server, &traceServer{})
+	go server.Serve(ln)
+	defer server.Stop()
+
+	cc, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{Spans: []*tracepb.Span{{}}}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{
+		Spans: []*tracepb.Span{{Attributes: stringAttributes(strings.Repeat("a", 200))}},
+	}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	unlimited, err := Settings{}.NewLimiter("test")
+	require.NoError(t, err)
+	assert.Empty(t, unlimited.GRPCServerOptions())
+}
+
+func TestHTTPHandlerMaxRequestSize(t *testing.T) {
+	l, err := Settings{MaxRequestSize: 3}.NewLimiter("test")
+	require.NoError(t, err)
+	handler := HTTPHandler(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "abc", string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader("abc")))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader("abcd")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// A body of unknown length.
+	req := httptest.NewRequest("POST", "/", ioutil.NopCloser(strings.NewReader("abcd")))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
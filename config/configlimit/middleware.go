@@ -0,0 +1,161 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configlimit
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+)
+
+// GRPCServerOptions returns the options of the gRPC server enforcing the
+// limits that can't be enforced by the interceptors, i.e. the maximum size of
+// the messages.
+func (l *Limiter) GRPCServerOptions() []grpc.ServerOption {
+	if l.maxRequestSize == 0 {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.MaxRecvMsgSize(l.maxRequestSize)}
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor failing with
+// ResourceExhausted the unary calls that exceed the limits, or InvalidArgument
+// if they contain a too long attribute value.
+func UnaryServerInterceptor(l *Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.checkMessage(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor ending with
+// ResourceExhausted the streams receiving a message that exceeds the limits,
+// or InvalidArgument if it contains a too long attribute value.
+func StreamServerInterceptor(l *Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &limitServerStream{ServerStream: ss, limiter: l})
+	}
+}
+
+type limitServerStream struct {
+	grpc.ServerStream
+	limiter *Limiter
+}
+
+func (ss *limitServerStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		// The messages larger than the maximum size are refused by gRPC.
+		if ss.limiter.maxRequestSize > 0 && status.Code(err) == codes.ResourceExhausted {
+			ss.limiter.refuse(grpcContext(ss.Context()), obsreport.RefusedReasonRequestSize)
+		}
+		return err
+	}
+	return ss.limiter.checkMessage(ss.Context(), m)
+}
+
+// checkMessage checks a gRPC message against the rate limit and, for the
+// messages carrying spans or metrics, the maximum batch size and attribute
+// length.
+func (l *Limiter) checkMessage(ctx context.Context, m interface{}) error {
+	ctx = grpcContext(ctx)
+	if err := l.CheckRate(ctx); err != nil {
+		return GRPCError(err)
+	}
+	var err error
+	switch msg := m.(type) {
+	case interface{ GetSpans() []*tracepb.Span }:
+		err = l.CheckSpans(ctx, msg.GetSpans())
+	case interface{ GetMetrics() []*metricspb.Metric }:
+		err = l.CheckMetrics(ctx, msg.GetMetrics())
+	}
+	if err != nil {
+		return GRPCError(err)
+	}
+	return nil
+}
+
+func grpcContext(ctx context.Context) context.Context {
+	return obsreport.WithTransport(ctx, "grpc")
+}
+
+// GRPCError returns the gRPC status error of an error of the Limiter:
+// InvalidArgument for ErrAttributeTooLong, ResourceExhausted otherwise.
+func GRPCError(err error) error {
+	if err == ErrAttributeTooLong {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.ResourceExhausted, err.Error())
+}
+
+// HTTPStatus returns the HTTP status code of an error of the Limiter: 400 Bad
+// Request for ErrAttributeTooLong, 413 Request Entity Too Large for
+// ErrRequestTooLarge, 429 Too Many Requests otherwise.
+func HTTPStatus(err error) int {
+	switch err {
+	case ErrAttributeTooLong:
+		return http.StatusBadRequest
+	case ErrRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusTooManyRequests
+	}
+}
+
+// HTTPHandler returns a http.Handler responding 429 Too Many Requests to the
+// requests exceeding the allowed rate and 413 Request Entity Too Large to the
+// requests whose body exceeds the maximum size, and passing the other ones to
+// the given handler. The body is read before calling the handler if the
+// maximum size is set.
+func HTTPHandler(l *Limiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := obsreport.WithTransport(r.Context(), "http")
+		if err := l.CheckRate(ctx); err != nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		if l.maxRequestSize > 0 {
+			if r.ContentLength > int64(l.maxRequestSize) {
+				l.refuse(ctx, obsreport.RefusedReasonRequestSize)
+				http.Error(w, ErrRequestTooLarge.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			// The length of the body may be unknown, e.g. chunked.
+			body, err := l.ReadBody(ctx, r.Body)
+			r.Body.Close()
+			if err == ErrRequestTooLarge {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
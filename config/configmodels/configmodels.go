@@ -17,6 +17,12 @@
 // Config (the top-level structure), Receivers, Exporters, Processors, Pipelines.
 package configmodels
 
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+)
+
 /*
 Receivers, Exporters and Processors typically have common configuration settings, however
 sometimes specific implementations will have extra configuration settings.
@@ -33,10 +39,17 @@ the corresponding common settings struct (the easiest approach is to embed the c
 
 // Config defines the configuration V2 for the various elements of collector or agent.
 type Config struct {
-	Receivers  Receivers
-	Exporters  Exporters
-	Processors Processors
-	Pipelines  Pipelines
+	Receivers     Receivers
+	Exporters     Exporters
+	Processors    Processors
+	Pipelines     Pipelines
+	Telemetry     Telemetry
+	Runtime       Runtime
+	RestartPolicy RestartPolicy
+	Debug         Debug
+	Storage       Storage
+	Status        Status
+	Tuning        Tuning
 }
 
 // NamedEntity is a configuration entity that has a name.
@@ -125,6 +138,147 @@ type Pipeline struct {
 // Pipelines is a map of names to Pipelines.
 type Pipelines map[string]*Pipeline
 
+// Telemetry defines the configuration of the own metrics of the collector,
+// exposed on a Prometheus endpoint.
+type Telemetry struct {
+	// Address is the host:port of the Prometheus endpoint.
+	Address string `mapstructure:"address"`
+	// Level is the level of detail of the metrics, "none", "basic", "normal"
+	// or "detailed". The endpoint is disabled if the level is "none".
+	Level string `mapstructure:"level"`
+	// Labels are constant labels added to all the metrics, e.g. to identify
+	// the collector instance.
+	Labels map[string]string `mapstructure:"labels"`
+	// Traces configures the tracing of the collector itself.
+	Traces TelemetryTraces `mapstructure:"traces"`
+	// Logs configures the logs of the collector.
+	Logs TelemetryLogs `mapstructure:"logs"`
+}
+
+// TelemetryTraces defines the configuration of the spans of the collector
+// itself, tracing the data from the receivers to the exporters.
+type TelemetryTraces struct {
+	// Pipeline is the name of the traces pipeline the spans are sent to, the
+	// spans are not sent if not set. The pipeline does not need receivers.
+	Pipeline string `mapstructure:"pipeline"`
+	// SamplingRate is the probability, between 0 and 1, that a batch of data
+	// is traced when the spans are sent to a pipeline.
+	SamplingRate float64 `mapstructure:"sampling-rate"`
+}
+
+// TelemetryLogs defines the configuration of the logs of the collector.
+type TelemetryLogs struct {
+	// Level is the minimum level of the logs, "debug", "info", "warn",
+	// "error", "dpanic", "panic" or "fatal".
+	Level string `mapstructure:"level"`
+	// Encoding is the encoding of the logs, "json" or "console".
+	Encoding string `mapstructure:"encoding"`
+	// OutputPaths are the files, or "stdout" and "stderr", the logs are
+	// written to.
+	OutputPaths []string `mapstructure:"output-paths"`
+	// Sampling limits the logs of the same level and message per second.
+	Sampling TelemetryLogsSampling `mapstructure:"sampling"`
+	// Levels overrides the level of the logs of some components.
+	Levels TelemetryLogsLevels `mapstructure:"levels"`
+}
+
+// TelemetryLogsSampling defines the sampling of the logs: the first Initial
+// logs with the same level and message in a second are written, then every
+// Thereafter-th one. The logs are not sampled if Initial is 0.
+type TelemetryLogsSampling struct {
+	Initial    int `mapstructure:"initial"`
+	Thereafter int `mapstructure:"thereafter"`
+}
+
+// TelemetryLogsLevels defines the level of the logs of the components, by
+// name of the component in the configuration.
+type TelemetryLogsLevels struct {
+	Receivers  map[string]string `mapstructure:"receivers"`
+	Processors map[string]string `mapstructure:"processors"`
+	Exporters  map[string]string `mapstructure:"exporters"`
+}
+
+// Runtime defines the settings of the Go runtime applied when the collector
+// starts.
+type Runtime struct {
+	// BallastSizeMiB is the size of the memory ballast, a large allocation
+	// making the garbage collector run less often. No ballast is allocated if
+	// 0 and BallastSizePercentage is 0.
+	BallastSizeMiB uint64 `mapstructure:"ballast-size-mib"`
+	// BallastSizePercentage is the size of the memory ballast in percentage
+	// of the total memory, or of the memory limit of the cgroup of the
+	// collector if lower. Ignored if BallastSizeMiB is set.
+	BallastSizePercentage uint64 `mapstructure:"ballast-size-percentage"`
+	// AutoMaxProcs sets GOMAXPROCS to the CPU quota of the cgroup of the
+	// collector, if any.
+	AutoMaxProcs bool `mapstructure:"auto-max-procs"`
+}
+
+// Actions of a RestartPolicy.
+const (
+	// RestartPolicyShutdown shuts the collector down.
+	RestartPolicyShutdown = "shutdown"
+	// RestartPolicyRestart restarts the failed component.
+	RestartPolicyRestart = "restart"
+)
+
+// RestartPolicy defines what the collector does when a receiver or an
+// exporter reports a fatal error after it started.
+type RestartPolicy struct {
+	// Action is RestartPolicyShutdown or RestartPolicyRestart.
+	Action string `mapstructure:"action"`
+	// InitialInterval is the time waited before the first restart of a
+	// component, it doubles after every restart up to MaxInterval.
+	InitialInterval time.Duration `mapstructure:"initial-interval"`
+	// MaxInterval is the maximum time waited before restarting a component.
+	MaxInterval time.Duration `mapstructure:"max-interval"`
+	// MaxRestarts is the number of restarts of a component after which the
+	// collector shuts down if it fails again, 0 for no limit.
+	MaxRestarts int `mapstructure:"max-restarts"`
+}
+
+// Debug defines the debug endpoint serving the last batches of data received
+// by each pipeline, to inspect the data flowing through the collector.
+type Debug struct {
+	// Endpoint is the host:port of the debug HTTP server, it is disabled if
+	// empty. It should only listen on a local interface.
+	Endpoint string `mapstructure:"endpoint"`
+	// Batches is the number of batches kept per pipeline.
+	Batches int `mapstructure:"batches"`
+	// Recording records the batches from the start of the collector, the
+	// recording can also be turned on and off on the endpoint.
+	Recording bool `mapstructure:"recording"`
+}
+
+// Storage defines where the receivers, processors and exporters persist their
+// state across restarts of the service.
+type Storage struct {
+	// Directory is the directory of the files the state is stored in, the
+	// state is only kept in memory if empty.
+	Directory string `mapstructure:"directory"`
+}
+
+// Status defines the endpoint serving the status of the collector in JSON: the
+// pipelines, the state of their components and the data they processed.
+type Status struct {
+	// Endpoint is the host:port of the status HTTP server, it is disabled if
+	// empty.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// Tuning defines the endpoint changing the configuration of the tunable
+// processors, e.g. the sampling processors, while the collector runs.
+type Tuning struct {
+	// Endpoint is the host:port of the tuning HTTP server, it is disabled if
+	// empty.
+	Endpoint string `mapstructure:"endpoint"`
+	// BearerToken is the token the requests must carry in their Authorization
+	// header, it is required if the endpoint is enabled.
+	BearerToken string `mapstructure:"bearer-token"`
+	// TLSCredentials serves the endpoint over TLS if set.
+	TLSCredentials *configtls.TLSServerSetting `mapstructure:"tls-credentials,omitempty"`
+}
+
 // Below are common setting structs for Receivers, Exporters and Processors.
 // These are helper structs which you can embed when implementing your specific
 // receiver/exporter/processor config storage.
@@ -138,9 +292,73 @@ type ReceiverSettings struct {
 	// The default value is false(meaning the receiver is enabled by default), and it is expected that receivers
 	// continue to use the default value of false.
 	Disabled bool `mapstructure:"disabled"`
-	// Configures the endpoint in the format 'address:port' for the receiver.
+	// Configures the endpoint in the format 'address:port' for the receiver, or
+	// 'unix:///path/to/socket' for a Unix domain socket.
 	// The default value is set by the receiver populating the struct.
 	Endpoint string `mapstructure:"endpoint"`
+	// Configures the octal permissions, e.g. "0660", of the Unix domain socket
+	// of the receiver. By default they are set by the umask of the process.
+	SocketPermissions string `mapstructure:"socket-permissions,omitempty"`
+	// Routes send the data matching them to some of the pipelines of the
+	// receiver only, the data matching no route is sent to the pipelines
+	// that are not in any route.
+	Routes []ReceiverRoute `mapstructure:"routes,omitempty"`
+	// Attributes are set as labels on the resource of all the data of the
+	// receiver, e.g. the tenant or the ingest endpoint, overriding the labels
+	// sent by the clients. They are set before the data is routed.
+	Attributes map[string]string `mapstructure:"attributes,omitempty"`
+}
+
+// ReceiverRoute sends the data of a receiver whose attribute matches a
+// pattern to some of the pipelines of the receiver.
+type ReceiverRoute struct {
+	// Attribute is "service.name", the name of the service of the node that
+	// sent the data, or the name of a label of its resource.
+	Attribute string `mapstructure:"attribute"`
+	// Pattern is the regular expression the whole value must match.
+	Pattern string `mapstructure:"pattern"`
+	// Pipelines are the pipelines the matching data is sent to, they must
+	// have the receiver.
+	Pipelines []string `mapstructure:"pipelines"`
+}
+
+// RoutedReceiver is the configuration of a receiver that can have routes.
+type RoutedReceiver interface {
+	ReceiverRoutes() []ReceiverRoute
+}
+
+// AttributedReceiver is the configuration of a receiver that can set static
+// attributes on its data.
+type AttributedReceiver interface {
+	ReceiverAttributes() map[string]string
+}
+
+// Toggleable is the configuration of an entity that can be enabled or
+// disabled by the "enabled" setting of the config file, which is evaluated
+// at load time.
+type Toggleable interface {
+	SetEnabled(enabled bool)
+}
+
+var (
+	_ Toggleable = (*ReceiverSettings)(nil)
+	_ Toggleable = (*ExporterSettings)(nil)
+	_ Toggleable = (*ProcessorSettings)(nil)
+)
+
+// ListeningReceiver is the configuration of a receiver listening on
+// endpoints, it is used to tell which receivers conflict when one can't
+// listen on its endpoint.
+type ListeningReceiver interface {
+	ListenEndpoints() []string
+}
+
+// ListenEndpoints returns the endpoint of the receiver, if set.
+func (rs *ReceiverSettings) ListenEndpoints() []string {
+	if rs.Endpoint == "" || !rs.IsEnabled() {
+		return nil
+	}
+	return []string{rs.Endpoint}
 }
 
 // Name gets the receiver name.
@@ -170,6 +388,25 @@ func (rs *ReceiverSettings) IsEnabled() bool {
 	return !rs.Disabled
 }
 
+// SetEnabled enables or disables the entity.
+func (rs *ReceiverSettings) SetEnabled(enabled bool) {
+	rs.Disabled = !enabled
+}
+
+// ReceiverRoutes returns the routes of the receiver.
+func (rs *ReceiverSettings) ReceiverRoutes() []ReceiverRoute {
+	return rs.Routes
+}
+
+var _ RoutedReceiver = (*ReceiverSettings)(nil)
+
+// ReceiverAttributes returns the static attributes of the receiver.
+func (rs *ReceiverSettings) ReceiverAttributes() map[string]string {
+	return rs.Attributes
+}
+
+var _ AttributedReceiver = (*ReceiverSettings)(nil)
+
 // ExporterSettings defines common settings for an exporter configuration.
 // Specific exporters can embed this struct and extend it with more fields if needed.
 type ExporterSettings struct {
@@ -205,6 +442,11 @@ func (es *ExporterSettings) IsEnabled() bool {
 	return !es.Disabled
 }
 
+// SetEnabled enables or disables the entity.
+func (es *ExporterSettings) SetEnabled(enabled bool) {
+	es.Disabled = !enabled
+}
+
 // ProcessorSettings defines common settings for a processor configuration.
 // Specific processors can embed this struct and extend it with more fields if needed.
 type ProcessorSettings struct {
@@ -238,4 +480,9 @@ func (proc *ProcessorSettings) IsEnabled() bool {
 	return !proc.Disabled
 }
 
+// SetEnabled enables or disables the entity.
+func (proc *ProcessorSettings) SetEnabled(enabled bool) {
+	proc.Disabled = !enabled
+}
+
 var _ Processor = (*ProcessorSettings)(nil)
@@ -0,0 +1,125 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confignet implements the endpoints shared by receivers and
+// exporters: TCP addresses, e.g. "localhost:55678", or Unix domain sockets,
+// e.g. "unix:///var/run/otelsvc.sock".
+package confignet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnixScheme prefixes the endpoints of Unix domain sockets.
+const UnixScheme = "unix://"
+
+// SplitEndpoint returns the network, "tcp" or "unix", and the address of the
+// endpoint.
+func SplitEndpoint(endpoint string) (network, address string) {
+	if strings.HasPrefix(endpoint, UnixScheme) {
+		return "unix", strings.TrimPrefix(endpoint, UnixScheme)
+	}
+	return "tcp", endpoint
+}
+
+// EndpointOf returns the endpoint of the address of a listener.
+func EndpointOf(addr net.Addr) string {
+	if addr.Network() == "unix" {
+		return UnixScheme + addr.String()
+	}
+	return addr.String()
+}
+
+// ParsePermissions parses the octal permissions of a socket, e.g. "0660". An
+// empty string returns 0, keeping the permissions set by the umask.
+func ParsePermissions(perm string) (os.FileMode, error) {
+	if perm == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(perm, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid socket permissions %q, expected octal permissions like \"0660\"", perm)
+	}
+	return os.FileMode(mode), nil
+}
+
+// Listen listens on the endpoint. A TCP address can be listened on again
+// while the listener is open, with SO_REUSEPORT on linux, so that a receiver
+// replacing another one starts accepting connections before the other one
+// stops. For a Unix domain socket the file of a previous process that didn't
+// clean it up is removed, the permissions of the socket are set to perm if
+// not 0, and the file is removed when the listener is closed.
+func Listen(endpoint string, perm os.FileMode) (net.Listener, error) {
+	network, address := SplitEndpoint(endpoint)
+	if network != "unix" {
+		lc := net.ListenConfig{Control: reusePort}
+		return lc.Listen(context.Background(), network, address)
+	}
+
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if perm != 0 {
+		if err := os.Chmod(address, perm); err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("failed to set the permissions of the socket %q: %v", address, err)
+		}
+	}
+	return ln, nil
+}
+
+// removeStaleSocket removes the socket file at path if no process is
+// listening on it anymore. Other kinds of files are left untouched, the
+// listener then fails to bind.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("the socket %q is already in use", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove the stale socket %q: %v", path, err)
+	}
+	return nil
+}
+
+// GRPCDialOptions returns the target and the dial options connecting a gRPC
+// client to the endpoint.
+func GRPCDialOptions(endpoint string) (string, []grpc.DialOption) {
+	network, address := SplitEndpoint(endpoint)
+	if network != "unix" {
+		return endpoint, nil
+	}
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, address)
+	}
+	// The path of the socket isn't a valid authority.
+	return address, []grpc.DialOption{grpc.WithContextDialer(dialer), grpc.WithAuthority("localhost")}
+}
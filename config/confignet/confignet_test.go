@@ -0,0 +1,172 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confignet
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestSplitEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint    string
+		wantNetwork string
+		wantAddress string
+	}{
+		{endpoint: "localhost:55678", wantNetwork: "tcp", wantAddress: "localhost:55678"},
+		{endpoint: ":9411", wantNetwork: "tcp", wantAddress: ":9411"},
+		{endpoint: "unix:///var/run/otelsvc.sock", wantNetwork: "unix", wantAddress: "/var/run/otelsvc.sock"},
+		{endpoint: "unix://otelsvc.sock", wantNetwork: "unix", wantAddress: "otelsvc.sock"},
+	}
+	for _, tt := range tests {
+		network, address := SplitEndpoint(tt.endpoint)
+		assert.Equal(t, tt.wantNetwork, network, tt.endpoint)
+		assert.Equal(t, tt.wantAddress, address, tt.endpoint)
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		perm    string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{perm: "", want: 0},
+		{perm: "0660", want: 0660},
+		{perm: "600", want: 0600},
+		{perm: "0777", want: 0777},
+		{perm: "1777", wantErr: true},
+		{perm: "0680", wantErr: true},
+		{perm: "rw-rw----", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePermissions(tt.perm)
+		if tt.wantErr {
+			assert.Error(t, err, tt.perm)
+			continue
+		}
+		assert.NoError(t, err, tt.perm)
+		assert.Equal(t, tt.want, got, tt.perm)
+	}
+}
+
+func TestListen_TCP(t *testing.T) {
+	ln, err := Listen("127.0.0.1:0", 0600)
+	require.NoError(t, err)
+	defer ln.Close()
+	assert.Equal(t, "tcp", ln.Addr().Network())
+	assert.Equal(t, ln.Addr().String(), EndpointOf(ln.Addr()))
+}
+
+func TestListen_TCPTwice(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT is only set on linux")
+	}
+	ln, err := Listen("127.0.0.1:0", 0)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	next, err := Listen(ln.Addr().String(), 0)
+	require.NoError(t, err)
+	defer next.Close()
+
+	// The connections are accepted by the new listener once the first one is
+	// closed.
+	ln.Close()
+	go func() {
+		conn, err := net.Dial("tcp", next.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := next.Accept()
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestListen_Unix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "confignet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "otelsvc.sock")
+	endpoint := UnixScheme + socket
+
+	ln, err := Listen(endpoint, 0600)
+	require.NoError(t, err)
+	assert.Equal(t, endpoint, EndpointOf(ln.Addr()))
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The socket is in use.
+	_, err = Listen(endpoint, 0)
+	assert.Error(t, err)
+
+	// The socket file is removed when the listener is closed.
+	require.NoError(t, ln.Close())
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+
+	// A socket file left behind by a previous process is replaced.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+	ln, err = Listen(endpoint, 0)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	// Other files are never removed.
+	require.NoError(t, ioutil.WriteFile(socket, []byte("data"), 0600))
+	_, err = Listen(endpoint, 0)
+	assert.Error(t, err)
+	data, err := ioutil.ReadFile(socket)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestGRPCDialOptions(t *testing.T) {
+	target, opts := GRPCDialOptions("localhost:14250")
+	assert.Equal(t, "localhost:14250", target)
+	assert.Empty(t, opts)
+
+	dir, err := ioutil.TempDir("", "confignet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	endpoint := UnixScheme + path.Join(dir, "otelsvc.sock")
+
+	ln, err := Listen(endpoint, 0)
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go server.Serve(ln)
+	defer server.Stop()
+
+	target, opts = GRPCDialOptions(endpoint)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, target, append(opts, grpc.WithInsecure(), grpc.WithBlock())...)
+	require.NoError(t, err)
+	conn.Close()
+}
@@ -0,0 +1,34 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !mips,!mipsle,!mips64,!mips64le
+
+package confignet
+
+import "syscall"
+
+// soReusePort is SO_REUSEPORT, missing from syscall on linux.
+const soReusePort = 0xf
+
+// reusePort lets other sockets of the process, e.g. of a receiver replacing
+// the one listening, listen on the same TCP address.
+func reusePort(network, address string, c syscall.RawConn) error {
+	var err error
+	if cerr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	}); cerr != nil {
+		return cerr
+	}
+	return err
+}
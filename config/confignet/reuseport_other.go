@@ -12,6 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package consumer
+// +build !linux mips mipsle mips64 mips64le
 
-// Package with interface only.
+package confignet
+
+import "syscall"
+
+// reusePort does nothing, the TCP addresses cannot be listened on twice.
+func reusePort(network, address string, c syscall.RawConn) error {
+	return nil
+}
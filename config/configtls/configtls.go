@@ -0,0 +1,303 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configtls implements the TLS settings shared by receivers and
+// exporters.
+package configtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSSetting exposes the common TLS settings of clients and servers.
+type TLSSetting struct {
+	// CAFile is the path to the PEM encoded CA certificates. For a client it
+	// verifies the server certificate, if empty the system root CAs are used.
+	// For a server it verifies the client certificates, which are then required.
+	CAFile string `mapstructure:"ca-file,omitempty"`
+
+	// CertFile is the path to the PEM encoded certificate.
+	CertFile string `mapstructure:"cert-file,omitempty"`
+
+	// KeyFile is the path to the PEM encoded key of the certificate.
+	KeyFile string `mapstructure:"key-file,omitempty"`
+
+	// MinVersion is the minimum TLS version accepted, "1.0", "1.1", "1.2" or
+	// "1.3". If empty the crypto/tls default is used.
+	MinVersion string `mapstructure:"min-version,omitempty"`
+
+	// MaxVersion is the maximum TLS version accepted, see MinVersion.
+	MaxVersion string `mapstructure:"max-version,omitempty"`
+
+	// ReloadOnChange reloads the certificate and key when the files are
+	// modified, so they can be rotated without restarting the process.
+	ReloadOnChange bool `mapstructure:"reload-on-change,omitempty"`
+}
+
+// TLSClientSetting contains the TLS settings used by clients, e.g. exporters.
+type TLSClientSetting struct {
+	TLSSetting `mapstructure:",squash"`
+
+	// Insecure disables TLS. It is ignored when a CA or certificate file is set.
+	Insecure bool `mapstructure:"insecure,omitempty"`
+
+	// ServerNameOverride is the name used to verify the server certificate
+	// instead of the host of the endpoint.
+	ServerNameOverride string `mapstructure:"server-name-override,omitempty"`
+}
+
+// TLSServerSetting contains the TLS settings used by servers, e.g. receivers.
+type TLSServerSetting struct {
+	TLSSetting `mapstructure:",squash"`
+
+	// ClientAllowedSANs restricts the clients to those with a certificate
+	// having one of these subject alternative names (DNS name, IP address,
+	// email address or URI). Requires CAFile.
+	ClientAllowedSANs []string `mapstructure:"client-allowed-sans,omitempty"`
+
+	// ClientAllowedOUs restricts the clients to those with a certificate
+	// having one of these organizational units. Requires CAFile. A client
+	// matching either ClientAllowedSANs or ClientAllowedOUs is allowed.
+	ClientAllowedOUs []string `mapstructure:"client-allowed-ous,omitempty"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// LoadTLSConfig returns the tls.Config for a client, or nil if TLS is disabled.
+func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
+	if c.Insecure && c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" {
+		return nil, nil
+	}
+
+	cfg, err := c.loadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.ServerName = c.ServerNameOverride
+	if c.CAFile != "" {
+		cfg.RootCAs, err = loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.CertFile != "" {
+		reloader, err := newCertReloader(c.CertFile, c.KeyFile, c.ReloadOnChange)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.getCertificate()
+		}
+	}
+	return cfg, nil
+}
+
+// LoadTLSConfig returns the tls.Config for a server, or nil if no certificate
+// is configured.
+func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
+	if c.CertFile == "" && c.KeyFile == "" {
+		if c.CAFile != "" {
+			return nil, errors.New("the CA file requires a certificate and a key file")
+		}
+		if len(c.ClientAllowedSANs) > 0 || len(c.ClientAllowedOUs) > 0 {
+			return nil, errors.New("the allowed client SANs and OUs require a certificate and a key file")
+		}
+		return nil, nil
+	}
+	if c.CAFile == "" && (len(c.ClientAllowedSANs) > 0 || len(c.ClientAllowedOUs) > 0) {
+		return nil, errors.New("the allowed client SANs and OUs require a CA file")
+	}
+
+	cfg, err := c.loadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	reloader, err := newCertReloader(c.CertFile, c.KeyFile, c.ReloadOnChange)
+	if err != nil {
+		return nil, err
+	}
+	cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return reloader.getCertificate()
+	}
+	if c.CAFile != "" {
+		cfg.ClientCAs, err = loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if len(c.ClientAllowedSANs) > 0 || len(c.ClientAllowedOUs) > 0 {
+		cfg.VerifyPeerCertificate = newClientVerifier(c.ClientAllowedSANs, c.ClientAllowedOUs)
+	}
+	return cfg, nil
+}
+
+// newClientVerifier returns a tls.Config.VerifyPeerCertificate function that
+// rejects the clients whose verified certificate has none of the allowed
+// subject alternative names or organizational units.
+func newClientVerifier(allowedSANs, allowedOUs []string) func([][]byte, [][]*x509.Certificate) error {
+	sans := make(map[string]bool, len(allowedSANs))
+	for _, san := range allowedSANs {
+		sans[san] = true
+	}
+	ous := make(map[string]bool, len(allowedOUs))
+	for _, ou := range allowedOUs {
+		ous[ou] = true
+	}
+
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+			return errors.New("no verified client certificate")
+		}
+		cert := verifiedChains[0][0]
+		for _, name := range certificateSANs(cert) {
+			if sans[name] {
+				return nil
+			}
+		}
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if ous[ou] {
+				return nil
+			}
+		}
+		return fmt.Errorf("client certificate %q is not allowed", cert.Subject.CommonName)
+	}
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return names
+}
+
+func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("both the certificate and the key file must be set")
+	}
+	minVersion, err := parseVersion(c.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid min-version: %v", err)
+	}
+	maxVersion, err := parseVersion(c.MaxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max-version: %v", err)
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return nil, fmt.Errorf("min-version %q is greater than max-version %q", c.MinVersion, c.MaxVersion)
+	}
+	return &tls.Config{MinVersion: minVersion, MaxVersion: maxVersion}, nil
+}
+
+func parseVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version %q", version)
+	}
+	return v, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA file %q: %v", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("failed to parse the CA file %q", caFile)
+	}
+	return pool, nil
+}
+
+// certReloader holds a certificate and, if enabled, reloads it when the
+// certificate or the key file is modified.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	reload   bool
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func newCertReloader(certFile, keyFile string, reload bool) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, reload: reload}
+	modTime, err := r.lastModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) getCertificate() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reload {
+		// Keep serving the previous certificate if the files are being
+		// replaced and can't be read or parsed yet.
+		if modTime, err := r.lastModTime(); err == nil && modTime.After(r.modTime) {
+			_ = r.load(modTime)
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the certificate %q and key %q: %v", r.certFile, r.keyFile, err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+func (r *certReloader) lastModTime() (time.Time, error) {
+	var last time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return last, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	caFile         = "testdata/ca.crt"
+	serverCertFile = "testdata/server.crt"
+	serverKeyFile  = "testdata/server.key"
+	clientCertFile = "testdata/client.crt"
+	clientKeyFile  = "testdata/client.key"
+)
+
+func TestTLSClientSetting_LoadTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting TLSClientSetting
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:    "insecure",
+			setting: TLSClientSetting{Insecure: true},
+			wantNil: true,
+		},
+		{
+			name:    "system_ca",
+			setting: TLSClientSetting{},
+		},
+		{
+			name: "insecure_ignored_with_ca",
+			setting: TLSClientSetting{
+				TLSSetting: TLSSetting{CAFile: caFile},
+				Insecure:   true,
+			},
+		},
+		{
+			name: "client_certificate",
+			setting: TLSClientSetting{
+				TLSSetting: TLSSetting{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile},
+			},
+		},
+		{
+			name:    "missing_ca",
+			setting: TLSClientSetting{TLSSetting: TLSSetting{CAFile: "testdata/missing.crt"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_ca",
+			setting: TLSClientSetting{TLSSetting: TLSSetting{CAFile: clientKeyFile}},
+			wantErr: true,
+		},
+		{
+			name:    "cert_without_key",
+			setting: TLSClientSetting{TLSSetting: TLSSetting{CertFile: clientCertFile}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_version",
+			setting: TLSClientSetting{TLSSetting: TLSSetting{MinVersion: "1.4"}},
+			wantErr: true,
+		},
+		{
+			name:    "min_greater_than_max",
+			setting: TLSClientSetting{TLSSetting: TLSSetting{MinVersion: "1.3", MaxVersion: "1.2"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.setting.LoadTLSConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, cfg == nil)
+		})
+	}
+}
+
+func TestTLSClientSetting_Options(t *testing.T) {
+	setting := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			CAFile:     caFile,
+			MinVersion: "1.2",
+			MaxVersion: "1.3",
+		},
+		ServerNameOverride: "server.example.com",
+	}
+	cfg, err := setting.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "server.example.com", cfg.ServerName)
+	assert.EqualValues(t, tls.VersionTLS12, cfg.MinVersion)
+	assert.EqualValues(t, tls.VersionTLS13, cfg.MaxVersion)
+	assert.NotNil(t, cfg.RootCAs)
+}
+
+func TestTLSServerSetting_LoadTLSConfig(t *testing.T) {
+	cfg, err := TLSServerSetting{}.LoadTLSConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = TLSServerSetting{TLSSetting: TLSSetting{CAFile: caFile}}.LoadTLSConfig()
+	assert.Error(t, err)
+
+	_, err = TLSServerSetting{TLSSetting: TLSSetting{CertFile: serverCertFile, KeyFile: "testdata/missing.key"}}.LoadTLSConfig()
+	assert.Error(t, err)
+
+	cfg, err = TLSServerSetting{TLSSetting: TLSSetting{CertFile: serverCertFile, KeyFile: serverKeyFile}}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, cfg.ClientAuth)
+
+	cfg, err = TLSServerSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, CertFile: serverCertFile, KeyFile: serverKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+	assert.NotNil(t, cfg.ClientCAs)
+}
+
+func TestTLSServerSetting_ClientAllowList(t *testing.T) {
+	_, err := TLSServerSetting{
+		TLSSetting:       TLSSetting{CertFile: serverCertFile, KeyFile: serverKeyFile},
+		ClientAllowedOUs: []string{"client"},
+	}.LoadTLSConfig()
+	assert.Error(t, err)
+
+	_, err = TLSServerSetting{ClientAllowedSANs: []string{"client.example.com"}}.LoadTLSConfig()
+	assert.Error(t, err)
+
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		sans    []string
+		ous     []string
+		allowed bool
+	}{
+		{name: "san", sans: []string{"client.example.com"}, allowed: true},
+		{name: "ip_san", sans: []string{"127.0.0.1"}, allowed: true},
+		{name: "ou", ous: []string{"client"}, allowed: true},
+		{name: "other_san", sans: []string{"server.example.com"}},
+		{name: "other_ou", ous: []string{"server"}},
+		{name: "other_san_and_ou", sans: []string{"other.example.com"}, ous: []string{"client"}, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverCfg, err := TLSServerSetting{
+				TLSSetting:        TLSSetting{CAFile: caFile, CertFile: serverCertFile, KeyFile: serverKeyFile},
+				ClientAllowedSANs: tt.sans,
+				ClientAllowedOUs:  tt.ous,
+			}.LoadTLSConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, handshake(t, serverCfg, clientCfg) == nil)
+		})
+	}
+}
+
+// handshake connects a client to a server with the given configs and returns
+// the error seen by the client, if any.
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) error {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// With TLS 1.3 the client only sees the rejection of its certificate
+	// when reading from the connection.
+	_, err = conn.Read(make([]byte, 1))
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, CertFile: serverCertFile, KeyFile: serverKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	withCert, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	conn, err := tls.Dial("tcp", ln.Addr().String(), withCert)
+	require.NoError(t, err)
+	require.NoError(t, conn.Handshake())
+	conn.Close()
+
+	// The server requires a client certificate, the handshake must fail, at
+	// the latest when reading from the connection.
+	withoutCert, err := TLSClientSetting{TLSSetting: TLSSetting{CAFile: caFile}}.LoadTLSConfig()
+	require.NoError(t, err)
+	conn, err = tls.Dial("tcp", ln.Addr().String(), withoutCert)
+	if err == nil {
+		_, err = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+	assert.Error(t, err)
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configtls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := path.Join(dir, "tls.crt")
+	keyFile := path.Join(dir, "tls.key")
+	copyFile(t, serverCertFile, certFile)
+	copyFile(t, serverKeyFile, keyFile)
+
+	for _, reload := range []bool{false, true} {
+		r, err := newCertReloader(certFile, keyFile, reload)
+		require.NoError(t, err)
+		before, err := r.getCertificate()
+		require.NoError(t, err)
+
+		copyFile(t, clientCertFile, certFile)
+		copyFile(t, clientKeyFile, keyFile)
+		future := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(certFile, future, future))
+		require.NoError(t, os.Chtimes(keyFile, future, future))
+
+		after, err := r.getCertificate()
+		require.NoError(t, err)
+		assert.Equal(t, !reload, before == after, "reload-on-change: %v", reload)
+
+		copyFile(t, serverCertFile, certFile)
+		copyFile(t, serverKeyFile, keyFile)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	data, err := ioutil.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(dst, data, 0600))
+}
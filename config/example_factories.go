@@ -288,6 +288,79 @@ func (exp *ExampleExporterConsumer) ConsumeMetricsData(ctx context.Context, md c
 	return nil
 }
 
+// Capabilities returns the consumer.Capabilities of the exporter, it only
+// keeps the data it receives.
+func (exp *ExampleExporterConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// ExampleConnector is for testing purposes. We are defining an example config and factory
+// for "exampleconnector" exporter type, connecting pipelines of the same data type.
+type ExampleConnector struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+}
+
+var _ exporter.ConnectorConfig = (*ExampleConnector)(nil)
+
+// ConnectorDataTypes returns the data types sent by the connector.
+func (c *ExampleConnector) ConnectorDataTypes() []configmodels.DataType {
+	return []configmodels.DataType{configmodels.TracesDataType, configmodels.MetricsDataType}
+}
+
+// ExampleConnectorFactory is factory for ExampleConnector.
+type ExampleConnectorFactory struct {
+}
+
+// Type gets the type of the Exporter config created by this factory.
+func (f *ExampleConnectorFactory) Type() string {
+	return "exampleconnector"
+}
+
+// CreateDefaultConfig creates the default configuration for the Exporter.
+func (f *ExampleConnectorFactory) CreateDefaultConfig() configmodels.Exporter {
+	return &ExampleConnector{}
+}
+
+// CreateTraceExporter creates a trace exporter based on this config.
+func (f *ExampleConnectorFactory) CreateTraceExporter(logger *zap.Logger, cfg configmodels.Exporter) (consumer.TraceConsumer, exporter.StopFunc, error) {
+	return &ExampleConnectorConsumer{}, nil, nil
+}
+
+// CreateMetricsExporter creates a metrics exporter based on this config.
+func (f *ExampleConnectorFactory) CreateMetricsExporter(logger *zap.Logger, cfg configmodels.Exporter) (consumer.MetricsConsumer, exporter.StopFunc, error) {
+	return &ExampleConnectorConsumer{}, nil, nil
+}
+
+// ExampleConnectorConsumer sends the traces and metrics it consumes, as is, to
+// the pipelines having it as a receiver.
+type ExampleConnectorConsumer struct {
+	TraceConsumer   consumer.TraceConsumer
+	MetricsConsumer consumer.MetricsConsumer
+}
+
+var _ exporter.TraceConnector = (*ExampleConnectorConsumer)(nil)
+var _ exporter.MetricsConnector = (*ExampleConnectorConsumer)(nil)
+
+// SetTraceConsumer sets the consumer the traces are sent to.
+func (exp *ExampleConnectorConsumer) SetTraceConsumer(tc consumer.TraceConsumer) {
+	exp.TraceConsumer = tc
+}
+
+// SetMetricsConsumer sets the consumer the metrics are sent to.
+func (exp *ExampleConnectorConsumer) SetMetricsConsumer(mc consumer.MetricsConsumer) {
+	exp.MetricsConsumer = mc
+}
+
+// ConsumeTraceData sends the traces to the connected pipelines.
+func (exp *ExampleConnectorConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return exp.TraceConsumer.ConsumeTraceData(ctx, td)
+}
+
+// ConsumeMetricsData sends the metrics to the connected pipelines.
+func (exp *ExampleConnectorConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	return exp.MetricsConsumer.ConsumeMetricsData(ctx, md)
+}
+
 // ExampleProcessor is for testing purposes. We are defining an example config and factory
 // for "exampleprocessor" processor type.
 type ExampleProcessor struct {
@@ -345,7 +418,7 @@ func ExampleComponents() (
 		return
 	}
 
-	exporters, err = exporter.Build(&ExampleExporterFactory{})
+	exporters, err = exporter.Build(&ExampleExporterFactory{}, &ExampleConnectorFactory{})
 	if err != nil {
 		return
 	}
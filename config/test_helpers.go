@@ -15,11 +15,17 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
@@ -54,3 +60,105 @@ func LoadConfigFile(
 	// Load the config from viper
 	return Load(v, receivers, processors, exporters, zap.NewNop())
 }
+
+// CheckDefaultConfigs checks the default config of each factory: all the
+// exported fields of its structs must have a mapstructure tag, so that they
+// can be set in the config file and are described by DescribeComponents, and
+// the config unmarshaled from its description must be the default config.
+func CheckDefaultConfigs(
+	t *testing.T,
+	receivers map[string]receiver.Factory,
+	processors map[string]processor.Factory,
+	exporters map[string]exporter.Factory,
+) {
+	for typeStr, factory := range receivers {
+		checkDefaultConfig(t, "receiver", typeStr, factory.CreateDefaultConfig(), factory.CustomUnmarshaler())
+	}
+	for typeStr, factory := range processors {
+		checkDefaultConfig(t, "processor", typeStr, factory.CreateDefaultConfig(), nil)
+	}
+	for typeStr, factory := range exporters {
+		checkDefaultConfig(t, "exporter", typeStr, factory.CreateDefaultConfig(), nil)
+	}
+}
+
+func checkDefaultConfig(
+	t *testing.T,
+	kind string,
+	typeStr string,
+	cfg interface{},
+	customUnmarshaler receiver.CustomUnmarshaler,
+) {
+	if err := checkConfigStruct(reflect.TypeOf(cfg), map[reflect.Type]bool{}); err != nil {
+		t.Errorf("the default config of the %s %q is incomplete: %v", kind, typeStr, err)
+	}
+
+	want := configValue(reflect.ValueOf(cfg))
+	blob, err := yaml.Marshal(map[string]interface{}{typeStr: want})
+	if err != nil {
+		t.Errorf("cannot marshal the default config of the %s %q: %v", kind, typeStr, err)
+		return
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(blob)); err != nil {
+		t.Errorf("cannot read the default config of the %s %q: %v", kind, typeStr, err)
+		return
+	}
+
+	// The fields that are not unmarshaled, e.g. the type and the name, are
+	// not described either: the descriptions are compared.
+	got := reflect.New(reflect.TypeOf(cfg).Elem()).Interface()
+	if customUnmarshaler != nil {
+		err = customUnmarshaler(v, typeStr, got)
+	} else {
+		err = v.UnmarshalKey(typeStr, got)
+	}
+	if err != nil {
+		t.Errorf("cannot unmarshal the default config of the %s %q: %v", kind, typeStr, err)
+		return
+	}
+	assert.Equal(t, want, configValue(reflect.ValueOf(got)),
+		"the default config of the %s %q does not round-trip:\n%s", kind, typeStr, blob)
+}
+
+// checkConfigStruct returns an error if an exported field of a struct of
+// this repository reachable from typ has no mapstructure tag. The types of
+// other packages, e.g. of client libraries, are decoded with their own
+// conventions.
+func checkConfigStruct(typ reflect.Type, seen map[reflect.Type]bool) error {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return checkConfigStruct(typ.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if seen[typ] || !strings.HasPrefix(typ.PkgPath(), "github.com/open-telemetry/opentelemetry-service") {
+		return nil
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			// Unexported.
+			continue
+		}
+		tag, ok := field.Tag.Lookup("mapstructure")
+		if !ok {
+			return fmt.Errorf("field %s.%s has no mapstructure tag", typ.Name(), field.Name)
+		}
+		opts := strings.Split(tag, ",")
+		if opts[0] == "-" {
+			continue
+		}
+		if opts[0] == "" && !containsString(opts[1:], "squash") {
+			return fmt.Errorf("field %s.%s has no name in its mapstructure tag", typ.Name(), field.Name)
+		}
+		if err := checkConfigStruct(field.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
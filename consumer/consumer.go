@@ -42,3 +42,27 @@ type DataConsumer interface {
 	TraceConsumer
 	MetricsConsumer
 }
+
+// Capabilities describes how a consumer uses the data it receives.
+type Capabilities struct {
+	// MutatesConsumedData is set to true if the consumer modifies the data it
+	// receives, or keeps it and modifies it after the Consume call returned.
+	// Consumers that only read the data can share it with other consumers,
+	// the others must be given their own copy.
+	MutatesConsumedData bool
+}
+
+// Capable is an optional interface implemented by the consumers declaring
+// their Capabilities.
+type Capable interface {
+	Capabilities() Capabilities
+}
+
+// GetCapabilities returns the Capabilities declared by the consumer c. The
+// consumers not implementing Capable are assumed to mutate the data.
+func GetCapabilities(c interface{}) Capabilities {
+	if capable, ok := c.(Capable); ok {
+		return capable.Capabilities()
+	}
+	return Capabilities{MutatesConsumedData: true}
+}
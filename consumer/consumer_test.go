@@ -0,0 +1,45 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+type nopConsumer struct{}
+
+func (nopConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return nil
+}
+
+type readOnlyConsumer struct {
+	nopConsumer
+}
+
+func (readOnlyConsumer) Capabilities() Capabilities {
+	return Capabilities{MutatesConsumedData: false}
+}
+
+func TestGetCapabilities(t *testing.T) {
+	if !GetCapabilities(nopConsumer{}).MutatesConsumedData {
+		t.Fatalf("GetCapabilities: want mutating for a consumer without capabilities")
+	}
+	if GetCapabilities(readOnlyConsumer{}).MutatesConsumedData {
+		t.Fatalf("GetCapabilities: want read-only for a read-only consumer")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// overloaded is a retryable error returned by a consumer that cannot accept
+// more data for now, e.g. because its queue is full or the memory usage is
+// too high.
+type overloaded struct {
+	error
+	retryAfter time.Duration
+}
+
+// Overloaded wraps an error to indicate that the consumer is overloaded and
+// that the same input can be sent again after the given delay, zero if
+// unknown. The receivers translate it to a response asking their clients to
+// slow down, see ToGRPC and WriteHTTPOverloaded.
+func Overloaded(err error, retryAfter time.Duration) error {
+	return overloaded{error: err, retryAfter: retryAfter}
+}
+
+// IsOverloaded checks if an error was wrapped with the Overloaded function
+// and returns the delay after which the input can be sent again.
+func IsOverloaded(err error) (time.Duration, bool) {
+	if err != nil {
+		o, isOverloaded := err.(overloaded)
+		return o.retryAfter, isOverloaded
+	}
+	return 0, false
+}
+
+// ToGRPC returns the error a gRPC receiver returns for an error of its next
+// consumer. Overloaded errors become ResourceExhausted status errors, with a
+// RetryInfo detail if the delay is known. Other errors are returned unchanged.
+func ToGRPC(err error) error {
+	retryAfter, ok := IsOverloaded(err)
+	if !ok {
+		return err
+	}
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if retryAfter > 0 {
+		if withInfo, detailsErr := st.WithDetails(&errdetails.RetryInfo{
+			RetryDelay: ptypes.DurationProto(retryAfter),
+		}); detailsErr == nil {
+			st = withInfo
+		}
+	}
+	return st.Err()
+}
+
+// WriteHTTPOverloaded responds to an HTTP request with 429 Too Many Requests,
+// and a Retry-After header if the delay is known, when err is an overloaded
+// error. It returns false without writing anything for other errors.
+func WriteHTTPOverloaded(w http.ResponseWriter, err error) bool {
+	retryAfter, ok := IsOverloaded(err)
+	if !ok {
+		return false
+	}
+	if retryAfter > 0 {
+		// Retry-After is in whole seconds, round up.
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+	return true
+}
@@ -0,0 +1,104 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOverloaded(t *testing.T) {
+	err := errors.New("testError")
+	if _, ok := IsOverloaded(err); ok {
+		t.Fatalf("IsOverloaded() = true, want false")
+	}
+	if _, ok := IsOverloaded(nil); ok {
+		t.Fatalf("IsOverloaded(nil) = true, want false")
+	}
+	err = Overloaded(err, time.Second)
+	retryAfter, ok := IsOverloaded(err)
+	if !ok {
+		t.Fatalf("IsOverloaded() = false, want true")
+	}
+	if retryAfter != time.Second {
+		t.Fatalf("IsOverloaded() delay = %v, want %v", retryAfter, time.Second)
+	}
+	if !IsRetryable(err) {
+		t.Fatalf("IsRetryable() = false, want true")
+	}
+}
+
+func TestToGRPC(t *testing.T) {
+	if ToGRPC(nil) != nil {
+		t.Fatalf("ToGRPC(nil) != nil")
+	}
+	err := errors.New("testError")
+	if got := ToGRPC(err); got != err {
+		t.Fatalf("ToGRPC() = %v, want the error unchanged", got)
+	}
+
+	got := ToGRPC(Overloaded(err, 0))
+	if status.Code(got) != codes.ResourceExhausted {
+		t.Fatalf("ToGRPC() code = %v, want %v", status.Code(got), codes.ResourceExhausted)
+	}
+	if len(status.Convert(got).Details()) != 0 {
+		t.Fatalf("ToGRPC() has a RetryInfo detail without a delay")
+	}
+
+	// The exporters of a gateway throttle the agents answering with the
+	// retry delay.
+	got = ToGRPC(Overloaded(err, 3*time.Second))
+	delay, ok := IsThrottle(FromGRPC(got))
+	if !ok || delay != 3*time.Second {
+		t.Fatalf("IsThrottle(FromGRPC(ToGRPC())) = %v, %v, want %v, true", delay, ok, 3*time.Second)
+	}
+}
+
+func TestWriteHTTPOverloaded(t *testing.T) {
+	w := httptest.NewRecorder()
+	if WriteHTTPOverloaded(w, errors.New("testError")) {
+		t.Fatalf("WriteHTTPOverloaded() = true, want false")
+	}
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Fatalf("WriteHTTPOverloaded() wrote the response of a regular error")
+	}
+
+	tests := []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{retryAfter: 0, want: ""},
+		{retryAfter: 2 * time.Second, want: "2"},
+		{retryAfter: 1500 * time.Millisecond, want: "2"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		if !WriteHTTPOverloaded(w, Overloaded(errors.New("testError"), tt.retryAfter)) {
+			t.Fatalf("WriteHTTPOverloaded() = false, want true")
+		}
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("WriteHTTPOverloaded() status = %d, want %d", w.Code, http.StatusTooManyRequests)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("WriteHTTPOverloaded() Retry-After = %q, want %q", got, tt.want)
+		}
+	}
+}
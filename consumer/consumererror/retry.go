@@ -0,0 +1,145 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// throttleRetry is a retryable error that carries a hint from the backend
+// about the minimum time to wait before retrying.
+type throttleRetry struct {
+	error
+	delay time.Duration
+}
+
+// Throttle wraps an error to indicate that the backend is throttling requests
+// and that the same input can be retried after at least the given delay.
+func Throttle(err error, delay time.Duration) error {
+	return throttleRetry{error: err, delay: delay}
+}
+
+// IsThrottle checks if an error was wrapped with the Throttle function and
+// returns the minimum delay before retrying.
+func IsThrottle(err error) (time.Duration, bool) {
+	if err != nil {
+		tr, isThrottle := err.(throttleRetry)
+		return tr.delay, isThrottle
+	}
+	return 0, false
+}
+
+// IsRetryable checks if an error is transient, i.e.: the same input may
+// succeed if retried. Every non-nil error that was not wrapped with the
+// Permanent function is considered retryable.
+func IsRetryable(err error) bool {
+	return err != nil && !IsPermanent(err)
+}
+
+// FromGRPC classifies an error returned by a gRPC call. Codes that indicate
+// a fault of the client (e.g. InvalidArgument or Unauthenticated) are
+// permanent. ResourceExhausted or Unavailable errors carrying a RetryInfo
+// detail are throttled with the given delay. All the other codes, including
+// Unknown and Internal that gRPC also uses for transport failures, are
+// retryable. Errors that are not gRPC status errors are returned unchanged.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.Unauthenticated,
+		codes.FailedPrecondition,
+		codes.Unimplemented:
+		return Permanent(err)
+	case codes.ResourceExhausted, codes.Unavailable:
+		if delay, ok := grpcRetryDelay(st); ok {
+			return Throttle(err, delay)
+		}
+		return err
+	default:
+		return err
+	}
+}
+
+func grpcRetryDelay(st *status.Status) (time.Duration, bool) {
+	for _, detail := range st.Details() {
+		if ri, ok := detail.(*errdetails.RetryInfo); ok && ri.RetryDelay != nil {
+			delay, err := ptypes.Duration(ri.RetryDelay)
+			if err == nil {
+				return delay, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FromHTTPStatus classifies an error caused by a failed HTTP response.
+// Status codes 429 and 503 are throttled if the response has a valid
+// Retry-After header, 408, 429 and 5xx are retryable and the other 4xx
+// status codes are permanent.
+func FromHTTPStatus(err error, resp *http.Response) error {
+	if err == nil || resp == nil {
+		return err
+	}
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable:
+		if delay, ok := httpRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return Throttle(err, delay)
+		}
+		return err
+	case code == http.StatusRequestTimeout || code >= http.StatusInternalServerError:
+		return err
+	case code >= http.StatusBadRequest:
+		return Permanent(err)
+	default:
+		return err
+	}
+}
+
+// httpRetryAfter parses the value of a Retry-After header which is either a
+// number of seconds or an HTTP date.
+func httpRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
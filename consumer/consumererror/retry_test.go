@@ -0,0 +1,135 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestThrottle(t *testing.T) {
+	err := errors.New("testError")
+	if _, ok := IsThrottle(err); ok {
+		t.Fatalf("IsThrottle() = true, want false")
+	}
+	err = Throttle(err, time.Second)
+	delay, ok := IsThrottle(err)
+	if !ok {
+		t.Fatalf("IsThrottle() = false, want true")
+	}
+	if delay != time.Second {
+		t.Fatalf("IsThrottle() delay = %v, want %v", delay, time.Second)
+	}
+	if !IsRetryable(err) {
+		t.Fatalf("IsRetryable() = false, want true")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if IsRetryable(nil) {
+		t.Fatalf("IsRetryable(nil) = true, want false")
+	}
+	if !IsRetryable(errors.New("testError")) {
+		t.Fatalf("IsRetryable() = false, want true")
+	}
+	if IsRetryable(Permanent(errors.New("testError"))) {
+		t.Fatalf("IsRetryable() = true, want false")
+	}
+}
+
+func TestFromGRPC(t *testing.T) {
+	if FromGRPC(nil) != nil {
+		t.Fatalf("FromGRPC(nil) != nil")
+	}
+
+	plain := errors.New("testError")
+	if got := FromGRPC(plain); got != plain {
+		t.Fatalf("FromGRPC() = %v, want %v", got, plain)
+	}
+
+	for _, code := range []codes.Code{
+		codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.Unauthenticated,
+		codes.FailedPrecondition,
+		codes.Unimplemented,
+	} {
+		if err := FromGRPC(status.Error(code, "bad")); !IsPermanent(err) {
+			t.Fatalf("%v: IsPermanent() = false, want true", code)
+		}
+	}
+	for _, code := range []codes.Code{
+		codes.Canceled,
+		codes.Unknown,
+		codes.DeadlineExceeded,
+		codes.ResourceExhausted,
+		codes.Aborted,
+		codes.OutOfRange,
+		codes.Internal,
+		codes.Unavailable,
+		codes.DataLoss,
+	} {
+		if err := FromGRPC(status.Error(code, "transient")); !IsRetryable(err) {
+			t.Fatalf("%v: IsRetryable() = false, want true", code)
+		}
+	}
+
+	st, err := status.New(codes.ResourceExhausted, "slow down").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(3 * time.Second)})
+	if err != nil {
+		t.Fatalf("WithDetails() = %v", err)
+	}
+	delay, ok := IsThrottle(FromGRPC(st.Err()))
+	if !ok || delay != 3*time.Second {
+		t.Fatalf("IsThrottle() = (%v, %t), want (%v, true)", delay, ok, 3*time.Second)
+	}
+}
+
+func TestFromHTTPStatus(t *testing.T) {
+	err := errors.New("testError")
+	newResp := func(code int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: code, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	if got := FromHTTPStatus(err, newResp(http.StatusBadRequest, "")); !IsPermanent(got) {
+		t.Fatalf("400: IsPermanent() = false, want true")
+	}
+	if got := FromHTTPStatus(err, newResp(http.StatusBadGateway, "")); !IsRetryable(got) {
+		t.Fatalf("502: IsRetryable() = false, want true")
+	}
+	if got := FromHTTPStatus(err, newResp(http.StatusTooManyRequests, "")); !IsRetryable(got) {
+		t.Fatalf("429: IsRetryable() = false, want true")
+	}
+	delay, ok := IsThrottle(FromHTTPStatus(err, newResp(http.StatusTooManyRequests, "7")))
+	if !ok || delay != 7*time.Second {
+		t.Fatalf("IsThrottle() = (%v, %t), want (%v, true)", delay, ok, 7*time.Second)
+	}
+	if _, ok := IsThrottle(FromHTTPStatus(err, newResp(http.StatusServiceUnavailable, "invalid"))); ok {
+		t.Fatalf("IsThrottle() = true, want false")
+	}
+}
@@ -17,24 +17,47 @@ package defaults
 
 import (
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/failoverexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/jaeger/jaegergrpcexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/jaeger/jaegerthrifthttpexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/loadbalancingexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/loggingexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/mirrorexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/opencensusexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/prometheusexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/spanmetricsconnector"
 	"github.com/open-telemetry/opentelemetry-service/exporter/zipkinexporter"
 	"github.com/open-telemetry/opentelemetry-service/oterr"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/processor/addattributesprocessor"
 	"github.com/open-telemetry/opentelemetry-service/processor/attributekeyprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/cumulativetodeltaprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/datalimiter"
+	"github.com/open-telemetry/opentelemetry-service/processor/deltatorateprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filterprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/groupbytrace"
+	"github.com/open-telemetry/opentelemetry-service/processor/k8sprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/memorylimiter"
 	"github.com/open-telemetry/opentelemetry-service/processor/nodebatcher"
+	"github.com/open-telemetry/opentelemetry-service/processor/probabilisticsampler"
 	"github.com/open-telemetry/opentelemetry-service/processor/queued"
+	"github.com/open-telemetry/opentelemetry-service/processor/resourcedetection"
+	"github.com/open-telemetry/opentelemetry-service/processor/resourceprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/routingprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/spanprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/tailsampling"
+	"github.com/open-telemetry/opentelemetry-service/processor/truncateprocessor"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/carbonreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/jaegerreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/jmxreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/prometheusreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/redisreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/vmmetricsreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver/zipkinkafkareceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver/zipkinscribereceiver"
 )
 
 // Components returns the default set of components used by the
@@ -49,9 +72,14 @@ func Components() (
 	receivers, err := receiver.Build(
 		&jaegerreceiver.Factory{},
 		&zipkinreceiver.Factory{},
+		&zipkinkafkareceiver.Factory{},
+		&zipkinscribereceiver.Factory{},
 		&prometheusreceiver.Factory{},
 		&opencensusreceiver.Factory{},
 		&vmmetricsreceiver.Factory{},
+		&carbonreceiver.Factory{},
+		&jmxreceiver.Factory{},
+		&redisreceiver.Factory{},
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -64,6 +92,10 @@ func Components() (
 		&zipkinexporter.Factory{},
 		&jaegergrpcexporter.Factory{},
 		&jaegerthrifthttpexporter.Factory{},
+		&loadbalancingexporter.Factory{},
+		&failoverexporter.Factory{},
+		&mirrorexporter.Factory{},
+		&spanmetricsconnector.Factory{},
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -74,6 +106,20 @@ func Components() (
 		&attributekeyprocessor.Factory{},
 		&queued.Factory{},
 		&nodebatcher.Factory{},
+		&memorylimiter.Factory{},
+		&filterprocessor.Factory{},
+		&probabilisticsampler.Factory{},
+		&tailsampling.Factory{},
+		&groupbytrace.Factory{},
+		&spanprocessor.Factory{},
+		&resourceprocessor.Factory{},
+		&resourcedetection.Factory{},
+		&k8sprocessor.Factory{},
+		&cumulativetodeltaprocessor.Factory{},
+		&deltatorateprocessor.Factory{},
+		&routingprocessor.Factory{},
+		&datalimiter.Factory{},
+		&truncateprocessor.Factory{},
 	)
 	if err != nil {
 		errs = append(errs, err)
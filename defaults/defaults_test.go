@@ -22,39 +22,82 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/failoverexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/jaeger/jaegergrpcexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/jaeger/jaegerthrifthttpexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/loadbalancingexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/loggingexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/mirrorexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/opencensusexporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/prometheusexporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/spanmetricsconnector"
 	"github.com/open-telemetry/opentelemetry-service/exporter/zipkinexporter"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/processor/addattributesprocessor"
 	"github.com/open-telemetry/opentelemetry-service/processor/attributekeyprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/cumulativetodeltaprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/datalimiter"
+	"github.com/open-telemetry/opentelemetry-service/processor/deltatorateprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filterprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/groupbytrace"
+	"github.com/open-telemetry/opentelemetry-service/processor/k8sprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/memorylimiter"
 	"github.com/open-telemetry/opentelemetry-service/processor/nodebatcher"
+	"github.com/open-telemetry/opentelemetry-service/processor/probabilisticsampler"
 	"github.com/open-telemetry/opentelemetry-service/processor/queued"
+	"github.com/open-telemetry/opentelemetry-service/processor/resourcedetection"
+	"github.com/open-telemetry/opentelemetry-service/processor/resourceprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/routingprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/spanprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/tailsampling"
+	"github.com/open-telemetry/opentelemetry-service/processor/truncateprocessor"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/carbonreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/jaegerreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/jmxreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/prometheusreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/redisreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/vmmetricsreceiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver/zipkinkafkareceiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver/zipkinscribereceiver"
 )
 
 func TestDefaultComponents(t *testing.T) {
 	expectedReceivers := map[string]receiver.Factory{
-		"jaeger":     &jaegerreceiver.Factory{},
-		"zipkin":     &zipkinreceiver.Factory{},
-		"prometheus": &prometheusreceiver.Factory{},
-		"opencensus": &opencensusreceiver.Factory{},
-		"vmmetrics":  &vmmetricsreceiver.Factory{},
+		"jaeger":        &jaegerreceiver.Factory{},
+		"zipkin":        &zipkinreceiver.Factory{},
+		"zipkin-kafka":  &zipkinkafkareceiver.Factory{},
+		"zipkin-scribe": &zipkinscribereceiver.Factory{},
+		"prometheus":    &prometheusreceiver.Factory{},
+		"opencensus":    &opencensusreceiver.Factory{},
+		"vmmetrics":     &vmmetricsreceiver.Factory{},
+		"carbon":        &carbonreceiver.Factory{},
+		"jmx":           &jmxreceiver.Factory{},
+		"redis":         &redisreceiver.Factory{},
 	}
 	expectedProcessors := map[string]processor.Factory{
-		"add-attributes": &addattributesprocessor.Factory{},
-		"attribute-key":  &attributekeyprocessor.Factory{},
-		"queued-retry":   &queued.Factory{},
-		"batch":          &nodebatcher.Factory{},
+		"add-attributes":        &addattributesprocessor.Factory{},
+		"attribute-key":         &attributekeyprocessor.Factory{},
+		"queued-retry":          &queued.Factory{},
+		"batch":                 &nodebatcher.Factory{},
+		"memory-limiter":        &memorylimiter.Factory{},
+		"filter":                &filterprocessor.Factory{},
+		"probabilistic-sampler": &probabilisticsampler.Factory{},
+		"tail-sampling":         &tailsampling.Factory{},
+		"group-by-trace":        &groupbytrace.Factory{},
+		"span":                  &spanprocessor.Factory{},
+		"resource":              &resourceprocessor.Factory{},
+		"resource-detection":    &resourcedetection.Factory{},
+		"k8s-attributes":        &k8sprocessor.Factory{},
+		"cumulative-to-delta":   &cumulativetodeltaprocessor.Factory{},
+		"delta-to-rate":         &deltatorateprocessor.Factory{},
+		"routing":               &routingprocessor.Factory{},
+		"data-limiter":          &datalimiter.Factory{},
+		"truncate":              &truncateprocessor.Factory{},
 	}
 	expectedExporters := map[string]exporter.Factory{
 		"opencensus":         &opencensusexporter.Factory{},
@@ -63,6 +106,10 @@ func TestDefaultComponents(t *testing.T) {
 		"zipkin":             &zipkinexporter.Factory{},
 		"jaeger-grpc":        &jaegergrpcexporter.Factory{},
 		"jaeger-thrift-http": &jaegerthrifthttpexporter.Factory{},
+		"loadbalancing":      &loadbalancingexporter.Factory{},
+		"failover":           &failoverexporter.Factory{},
+		"mirror":             &mirrorexporter.Factory{},
+		"span-metrics":       &spanmetricsconnector.Factory{},
 	}
 
 	receivers, processors, exporters, err := Components()
@@ -72,3 +119,10 @@ func TestDefaultComponents(t *testing.T) {
 	assert.Equal(t, expectedProcessors, processors)
 	assert.Equal(t, expectedExporters, exporters)
 }
+
+func TestDefaultConfigs(t *testing.T) {
+	receivers, processors, exporters, err := Components()
+	assert.Nil(t, err)
+
+	config.CheckDefaultConfigs(t, receivers, processors, exporters)
+}
@@ -16,6 +16,9 @@
 package exporter
 
 import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 )
 
@@ -25,6 +28,9 @@ type TraceExporter interface {
 
 	// Name gets the name of the trace exporter.
 	Name() string
+
+	// Shutdown stops the exporter and releases its resources.
+	Shutdown() error
 }
 
 // MetricsExporter composes MetricsConsumer with some additional exporter-specific functions.
@@ -33,4 +39,106 @@ type MetricsExporter interface {
 
 	// Name gets the name of the metrics exporter.
 	Name() string
+
+	// Shutdown stops the exporter and releases its resources.
+	Shutdown() error
+}
+
+// Host represents the entity where the exporter is being hosted. It is used to
+// allow communication between the exporter and its host.
+type Host interface {
+	// Context returns a context provided by the host to be used on the exporter
+	// operations.
+	Context() context.Context
+
+	// ReportFatalError is used to report to the host that the exporter
+	// encountered a fatal error (i.e.: an error that the instance can't recover
+	// from) after its Start function has already returned.
+	ReportFatalError(err error)
+}
+
+// Starter is implemented by the exporters that work in the background once
+// created, e.g. a server scraped by the backend, and may fail after that.
+type Starter interface {
+	// Start starts the background work of the exporter. It is called again to
+	// restart the exporter after it reported a fatal error to the host, if the
+	// restart policy of the service says so.
+	Start(host Host) error
+}
+
+// Drainer is implemented by the exporters that queue data before sending it,
+// so that it isn't lost when the service shuts down.
+type Drainer interface {
+	// Drain waits until all the queued data is sent, or dropped, or the
+	// context is done, in which case it returns an error.
+	Drain(ctx context.Context) error
+
+	// InFlight returns the number of items (spans or metrics) queued or
+	// being sent.
+	InFlight() int
+}
+
+// WrappingConfig is implemented by the configs of the exporters that send the
+// data to other exporters, e.g. to fail over between them. The exporters
+// wrapped are built for the data types of the wrapping exporter.
+type WrappingConfig interface {
+	// WrappedExporters returns the names of the exporters wrapped, in order.
+	WrappedExporters() []string
+}
+
+// SynchronousWrappingConfig is implemented by the WrappingConfigs whose
+// exporters need the errors of some of the exporters they wrap, e.g. to fail
+// over on them. These exporters can't queue the data.
+type SynchronousWrappingConfig interface {
+	WrappingConfig
+
+	// SynchronousExporters returns the names of the exporters wrapped whose
+	// errors are needed.
+	SynchronousExporters() []string
+}
+
+// QueuedConfig is implemented by the configs of the exporters that can queue
+// the data and send it in the background, their errors are then not
+// returned to the caller.
+type QueuedConfig interface {
+	// QueueEnabled returns whether the data is queued.
+	QueueEnabled() bool
+}
+
+// TraceWrapper is implemented by the trace exporters of a WrappingConfig.
+// The exporters wrapped are set once all the exporters are built.
+type TraceWrapper interface {
+	// SetTraceExporters sets the exporters of WrappedExporters, in order.
+	SetTraceExporters(exporters []consumer.TraceConsumer)
+}
+
+// MetricsWrapper is the equivalent of TraceWrapper for the metrics exporters.
+type MetricsWrapper interface {
+	// SetMetricsExporters sets the exporters of WrappedExporters, in order.
+	SetMetricsExporters(exporters []consumer.MetricsConsumer)
+}
+
+// ConnectorConfig is implemented by the configs of the exporters that connect
+// pipelines: the data they consume in the pipelines having them as an exporter
+// is sent, e.g. spans aggregated in metrics, to the pipelines having them as a
+// receiver.
+type ConnectorConfig interface {
+	// ConnectorDataTypes returns the data types the connector sends, i.e. the
+	// types of the pipelines that can have it as a receiver.
+	ConnectorDataTypes() []configmodels.DataType
+}
+
+// TraceConnector is implemented by the exporters of a ConnectorConfig
+// sending traces. The first consumer of the pipelines is set once all the
+// pipelines are built.
+type TraceConnector interface {
+	// SetTraceConsumer sets the consumer the traces are sent to.
+	SetTraceConsumer(tc consumer.TraceConsumer)
+}
+
+// MetricsConnector is the equivalent of TraceConnector for the exporters
+// sending metrics.
+type MetricsConnector interface {
+	// SetMetricsConsumer sets the consumer the metrics are sent to.
+	SetMetricsConsumer(mc consumer.MetricsConsumer)
 }
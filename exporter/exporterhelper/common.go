@@ -15,7 +15,14 @@
 package exporterhelper
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"go.opencensus.io/trace"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 )
 
 var (
@@ -24,14 +31,34 @@ var (
 
 // ExporterOptions contains options concerning how an Exporter is configured.
 type ExporterOptions struct {
-	// TODO: Retry logic must be in the same place as metrics recording because
-	// if a request is retried we should not record metrics otherwise number of
-	// spans received + dropped will be different than the number of received spans
-	// in the receiver.
-	recordMetrics bool
-	spanName      string
+	recordMetrics   bool
+	spanName        string
+	queueSettings   QueueSettings
+	retrySettings   RetrySettings
+	timeoutSettings TimeoutSettings
+	splitSettings   SplitSettings
+	shutdown        Shutdown
+	capabilities    consumer.Capabilities
+}
+
+// TimeoutSettings defines configuration for bounding every request sent to the backend.
+type TimeoutSettings struct {
+	// Timeout is the maximum amount of time a single attempt to send a request
+	// can take. Zero means no timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// CreateDefaultTimeoutSettings returns the default settings for TimeoutSettings.
+func CreateDefaultTimeoutSettings() TimeoutSettings {
+	return TimeoutSettings{
+		Timeout: 5 * time.Second,
+	}
 }
 
+// Shutdown is called when the exporter is shutting down, after the queue
+// (if any) was stopped.
+type Shutdown func() error
+
 // ExporterOption apply changes to ExporterOptions.
 type ExporterOption func(*ExporterOptions)
 
@@ -49,15 +76,81 @@ func WithSpanName(spanName string) ExporterOption {
 	}
 }
 
+// WithQueue makes new Exporter to enqueue every request in a bounded in-memory
+// queue and send them out asynchronously using the configured number of workers.
+func WithQueue(queueSettings QueueSettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.queueSettings = queueSettings
+	}
+}
+
+// WithRetry makes new Exporter to retry failed requests using exponential
+// backoff with jitter. Errors marked as permanent are never retried.
+func WithRetry(retrySettings RetrySettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.retrySettings = retrySettings
+	}
+}
+
+// WithTimeout makes new Exporter to bound every attempt to send a request by
+// the given timeout, propagated as a deadline through the context. A request
+// that times out fails with a retryable error.
+func WithTimeout(timeoutSettings TimeoutSettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.timeoutSettings = timeoutSettings
+	}
+}
+
+// WithSplit makes new Exporter to split every batch exceeding the given
+// limits into several requests, each of them queued, retried and bounded by
+// the timeout on its own.
+func WithSplit(splitSettings SplitSettings) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.splitSettings = splitSettings
+	}
+}
+
+// WithCapabilities makes new Exporter to declare the given Capabilities, see
+// consumer.Capable. By default exporters are assumed to mutate the data they
+// receive; the ones only reading it should declare it to avoid copying the
+// data when it is sent to several exporters.
+func WithCapabilities(capabilities consumer.Capabilities) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.capabilities = capabilities
+	}
+}
+
+// WithShutdown makes new Exporter to call the given function when Shutdown is called.
+func WithShutdown(shutdown Shutdown) ExporterOption {
+	return func(o *ExporterOptions) {
+		o.shutdown = shutdown
+	}
+}
+
 // Construct the ExporterOptions from multiple ExporterOption.
 func newExporterOptions(options ...ExporterOption) ExporterOptions {
-	var opts ExporterOptions
+	opts := ExporterOptions{
+		capabilities: consumer.Capabilities{MutatesConsumedData: true},
+	}
 	for _, op := range options {
 		op(&opts)
 	}
 	return opts
 }
 
+// timeoutError returns the error reported when a request timed out, or the
+// given error if the request did not time out. Errors already classified as
+// permanent or throttled are returned unchanged to keep their classification.
+func timeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	if _, isThrottle := consumererror.IsThrottle(err); isThrottle || consumererror.IsPermanent(err) {
+		return err
+	}
+	return fmt.Errorf("request timed out after %v: %v", timeout, err)
+}
+
 func errToStatus(err error) trace.Status {
 	if err != nil {
 		return trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()}
@@ -16,6 +16,8 @@ package exporterhelper
 
 import (
 	"errors"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 )
 
 var (
@@ -25,6 +27,11 @@ var (
 	errNilPushTraceData = errors.New("nil pushTraceData")
 	// errNilPushMetricsData is returned when a nil pushMetricsData is given.
 	errNilPushMetricsData = errors.New("nil pushMetricsData")
+	// errQueueIsFull is returned when a request cannot be added to the queue.
+	errQueueIsFull = consumererror.Overloaded(errors.New("sending queue is full"), 0)
+	// errMemoryBudgetExceeded is returned when a request cannot be added to the
+	// queue because the memory budget shared by all components is exhausted.
+	errMemoryBudgetExceeded = consumererror.Overloaded(errors.New("memory budget exceeded"), 0)
 )
 
 const (
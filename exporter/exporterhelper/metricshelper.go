@@ -16,10 +16,13 @@ package exporterhelper
 
 import (
 	"context"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/open-telemetry/opentelemetry-service/observability"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 )
@@ -31,9 +34,15 @@ type PushMetricsData func(ctx context.Context, td consumerdata.MetricsData) (dro
 type metricsExporter struct {
 	exporterName    string
 	pushMetricsData PushMetricsData
+	sender          *queuedRetrySender
+	shutdown        Shutdown
+	splitSettings   SplitSettings
+	capabilities    consumer.Capabilities
 }
 
 var _ (exporter.MetricsExporter) = (*metricsExporter)(nil)
+var _ (exporter.Drainer) = (*metricsExporter)(nil)
+var _ (consumer.Capable) = (*metricsExporter)(nil)
 
 func (me *metricsExporter) Name() string {
 	return me.exporterName
@@ -41,14 +50,62 @@ func (me *metricsExporter) Name() string {
 
 func (me *metricsExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
 	exporterCtx := observability.ContextWithExporterName(ctx, me.exporterName)
-	_, err := me.pushMetricsData(exporterCtx, md)
+	var errs []error
+	for _, chunk := range splitMetricsData(md, me.splitSettings) {
+		if err := me.sendMetricsData(exporterCtx, chunk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return combineChunkErrors(errs)
+}
+
+func (me *metricsExporter) sendMetricsData(exporterCtx context.Context, md consumerdata.MetricsData) error {
+	_, err := me.sender.send(&request{
+		ctx: exporterCtx,
+		export: func(ctx context.Context) (int, error) {
+			return me.pushMetricsData(ctx, md)
+		},
+		count: len(md.Metrics),
+		size: func() int64 {
+			var size int
+			for _, metric := range md.Metrics {
+				size += proto.Size(metric)
+			}
+			return int64(size)
+		},
+	})
 	return err
 }
 
+// Drain waits until the queued requests are sent, see exporter.Drainer.
+func (me *metricsExporter) Drain(ctx context.Context) error {
+	return me.sender.drain(ctx)
+}
+
+// InFlight returns the number of items queued or being sent.
+func (me *metricsExporter) InFlight() int {
+	return me.sender.inFlightItems()
+}
+
+// Capabilities returns the Capabilities given with WithCapabilities, see
+// consumer.Capable.
+func (me *metricsExporter) Capabilities() consumer.Capabilities {
+	return me.capabilities
+}
+
+func (me *metricsExporter) Shutdown() error {
+	me.sender.shutdown()
+	if me.shutdown != nil {
+		return me.shutdown()
+	}
+	return nil
+}
+
 // NewMetricsExporter creates an MetricsExporter that can record metrics and can wrap every request with a Span.
+// It can also split big batches, queue requests, bound and retry failed ones, see WithSplit, WithQueue,
+// WithTimeout and WithRetry.
 // If no options are passed it just adds the exporter format as a tag in the Context.
 // TODO: Add support for recordMetrics.
-// TODO: Add support for retries.
 func NewMetricsExporter(exporterName string, pushMetricsData PushMetricsData, options ...ExporterOption) (exporter.MetricsExporter, error) {
 	if exporterName == "" {
 		return nil, errEmptyExporterName
@@ -59,17 +116,47 @@ func NewMetricsExporter(exporterName string, pushMetricsData PushMetricsData, op
 	}
 
 	opts := newExporterOptions(options...)
+	sender := newQueuedRetrySender(exporterName, opts.queueSettings, opts.retrySettings)
+
+	if opts.timeoutSettings.Timeout > 0 {
+		pushMetricsData = pushMetricsDataWithTimeout(pushMetricsData, opts.timeoutSettings.Timeout)
+	}
 
 	if opts.spanName != "" {
 		pushMetricsData = pushMetricsDataWithSpan(pushMetricsData, opts.spanName)
 	}
 
+	if opts.retrySettings.Enabled {
+		pushMetricsData = pushMetricsDataWithRetry(pushMetricsData, sender)
+	}
+
 	return &metricsExporter{
 		exporterName:    exporterName,
 		pushMetricsData: pushMetricsData,
+		sender:          sender,
+		shutdown:        opts.shutdown,
+		splitSettings:   opts.splitSettings,
+		capabilities:    opts.capabilities,
 	}, nil
 }
 
+func pushMetricsDataWithTimeout(next PushMetricsData, timeout time.Duration) PushMetricsData {
+	return func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		dropped, err := next(ctx, md)
+		return dropped, timeoutError(ctx, timeout, err)
+	}
+}
+
+func pushMetricsDataWithRetry(next PushMetricsData, sender *queuedRetrySender) PushMetricsData {
+	return func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
+		return sender.exportWithRetry(ctx, func(ctx context.Context) (int, error) {
+			return next(ctx, md)
+		})
+	}
+}
+
 func pushMetricsDataWithSpan(next PushMetricsData, spanName string) PushMetricsData {
 	return func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
 		ctx, span := trace.StartSpan(ctx, spanName)
@@ -16,11 +16,14 @@ package exporterhelper
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 )
@@ -50,6 +53,27 @@ func TestMetricsExporter_Default(t *testing.T) {
 	}
 }
 
+func TestMetricsExporter_WithCapabilities(t *testing.T) {
+	te, err := NewMetricsExporter(fakeExporterName, newPushMetricsData(0, nil))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter returns: Want nil Got %v", err)
+	}
+	if !consumer.GetCapabilities(te).MutatesConsumedData {
+		t.Fatalf("Capabilities returns: Want mutating by default Got read-only")
+	}
+
+	te, err = NewMetricsExporter(
+		fakeExporterName,
+		newPushMetricsData(0, nil),
+		WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter returns: Want nil Got %v", err)
+	}
+	if consumer.GetCapabilities(te).MutatesConsumedData {
+		t.Fatalf("Capabilities returns: Want read-only Got mutating")
+	}
+}
+
 func TestMetricsExporter_Default_ReturnError(t *testing.T) {
 	td := consumerdata.MetricsData{}
 	want := errors.New("my_error")
@@ -87,6 +111,34 @@ func TestMetricsExporter_WithSpan_ReturnError(t *testing.T) {
 	checkWrapSpanForMetricsExporter(t, te, want, 0)
 }
 
+func TestMetricsExporter_WithTimeout_RetriesTimedOutRequest(t *testing.T) {
+	var calls int32
+	push := func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Simulate a slow backend that honors the context deadline.
+			<-ctx.Done()
+			return len(md.Metrics), ctx.Err()
+		}
+		return 0, nil
+	}
+	me, err := NewMetricsExporter(
+		fakeExporterName,
+		push,
+		WithTimeout(TimeoutSettings{Timeout: 10 * time.Millisecond}),
+		WithRetry(fastRetrySettings()))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter returns: Want nil Got %v", err)
+	}
+	defer me.Shutdown()
+
+	if err := me.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{}); err != nil {
+		t.Fatalf("ConsumeMetricsData returns: Want nil Got %v", err)
+	}
+	if g, w := atomic.LoadInt32(&calls), int32(2); g != w {
+		t.Fatalf("Push calls: Want %d Got %d", w, g)
+	}
+}
+
 func newPushMetricsData(droppedSpans int, retError error) PushMetricsData {
 	return func(ctx context.Context, td consumerdata.MetricsData) (int, error) {
 		return droppedSpans, retError
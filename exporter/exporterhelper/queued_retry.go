@@ -0,0 +1,330 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/queue"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
+	"github.com/open-telemetry/opentelemetry-service/internal/contextutils"
+	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+)
+
+// QueueSettings defines configuration for queueing requests before sending them to the backend.
+type QueueSettings struct {
+	// Enabled indicates whether requests are queued before being sent.
+	Enabled bool `mapstructure:"enabled"`
+	// NumWorkers is the number of workers that dequeue requests and send them out.
+	NumWorkers int `mapstructure:"num-workers"`
+	// QueueSize is the maximum number of requests allowed in the queue at a given time.
+	QueueSize int `mapstructure:"queue-size"`
+}
+
+// CreateDefaultQueueSettings returns the default settings for QueueSettings.
+func CreateDefaultQueueSettings() QueueSettings {
+	return QueueSettings{
+		Enabled:    true,
+		NumWorkers: 10,
+		QueueSize:  5000,
+	}
+}
+
+// RetrySettings defines configuration for retrying failed requests with exponential backoff.
+type RetrySettings struct {
+	// Enabled indicates whether failed requests are retried.
+	Enabled bool `mapstructure:"enabled"`
+	// InitialInterval is the time to wait after the first failure before retrying.
+	InitialInterval time.Duration `mapstructure:"initial-interval"`
+	// MaxInterval is the upper bound on the backoff interval between consecutive retries.
+	MaxInterval time.Duration `mapstructure:"max-interval"`
+	// MaxElapsedTime is the maximum amount of time spent trying to send a request,
+	// after which the request is dropped. Zero means no limit.
+	MaxElapsedTime time.Duration `mapstructure:"max-elapsed-time"`
+	// Multiplier is the factor by which the backoff interval grows after every retry.
+	Multiplier float64 `mapstructure:"multiplier"`
+	// RandomizationFactor is the jitter applied to every backoff interval, e.g. 0.5
+	// means the actual interval is randomly chosen in [0.5*interval, 1.5*interval].
+	RandomizationFactor float64 `mapstructure:"randomization-factor"`
+}
+
+// CreateDefaultRetrySettings returns the default settings for RetrySettings.
+func CreateDefaultRetrySettings() RetrySettings {
+	return RetrySettings{
+		Enabled:             true,
+		InitialInterval:     5 * time.Second,
+		MaxInterval:         30 * time.Second,
+		MaxElapsedTime:      5 * time.Minute,
+		Multiplier:          1.5,
+		RandomizationFactor: 0.5,
+	}
+}
+
+// request is a unit of work that can be queued by the queuedRetrySender.
+type request struct {
+	// ctx is the context.Context of the original call.
+	ctx context.Context
+	// export sends the request and returns the number of dropped items.
+	export func(ctx context.Context) (int, error)
+	// count is the number of items (spans or metrics) in the request.
+	count int
+	// size estimates the number of bytes of the request, used to account it
+	// in the memory budget while it is queued.
+	size func() int64
+	// bytes is the number of bytes reserved in the memory budget.
+	bytes int64
+}
+
+type queuedRetrySender struct {
+	queueSettings QueueSettings
+	retrySettings RetrySettings
+	queue         *queue.BoundedQueue
+	account       *memorybudget.Account
+	stopCh        chan struct{}
+	stopOnce      sync.Once
+	// inFlight is the number of items queued or being sent.
+	inFlight int64
+	// ctx is used to record metrics about the queue itself.
+	ctx context.Context
+	// obsCtx is used to record the obsreport metrics of the exporter.
+	obsCtx context.Context
+}
+
+func newQueuedRetrySender(exporterName string, qs QueueSettings, rs RetrySettings) *queuedRetrySender {
+	qrs := &queuedRetrySender{
+		queueSettings: qs,
+		retrySettings: rs,
+		stopCh:        make(chan struct{}),
+		ctx:           observability.ContextWithExporterName(context.Background(), exporterName),
+		obsCtx:        obsreport.ExporterContext(context.Background(), exporterName),
+	}
+	if !qs.Enabled {
+		return qrs
+	}
+
+	qrs.account = memorybudget.Default().Register("exporter/" + exporterName)
+	qrs.queue = queue.NewBoundedQueue(qs.QueueSize, func(item interface{}) {})
+	qrs.queue.StartConsumers(qs.NumWorkers, func(item interface{}) {
+		req := item.(*request)
+		defer atomic.AddInt64(&qrs.inFlight, -int64(req.count))
+		defer qrs.account.Release(req.bytes)
+		// The original call already returned so its deadline and cancellation
+		// no longer apply, only keep the values (tags, spans) attached to it.
+		// The export is aborted if the exporter shuts down meanwhile.
+		if _, err := qrs.export(contextutils.Detach(req.ctx, qrs.stopCh), req); err != nil {
+			// Nobody is waiting for the result anymore, record the failure so
+			// that dropped requests are visible.
+			observability.RecordExporterSendFailed(qrs.ctx)
+		}
+	})
+
+	// Start a timer to report the queue length.
+	ticker := time.NewTicker(1 * time.Second)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-qrs.stopCh:
+				return
+			case <-ticker.C:
+				observability.RecordExporterQueueLength(qrs.ctx, qrs.queue.Size())
+				obsreport.RecordExporterQueueSize(qrs.obsCtx, qrs.queue.Size())
+				obsreport.RecordExporterQueueCapacity(qrs.obsCtx, qs.QueueSize)
+			}
+		}
+	}()
+	return qrs
+}
+
+// send either adds the request to the queue, or, if queueing is disabled,
+// sends it directly, aborted if the context of the request is done, e.g. the
+// client of the receiver canceled it, or the exporter shuts down.
+func (qrs *queuedRetrySender) send(req *request) (int, error) {
+	if !qrs.queueSettings.Enabled {
+		atomic.AddInt64(&qrs.inFlight, int64(req.count))
+		defer atomic.AddInt64(&qrs.inFlight, -int64(req.count))
+		ctx, cancel := contextutils.WithStop(req.ctx, qrs.stopCh)
+		defer cancel()
+		return qrs.export(ctx, req)
+	}
+
+	if qrs.account.Enabled() && req.size != nil {
+		bytes := req.size()
+		if !qrs.account.Reserve(bytes) {
+			observability.RecordExporterQueueFullDrop(qrs.ctx)
+			return req.count, errMemoryBudgetExceeded
+		}
+		req.bytes = bytes
+	}
+
+	atomic.AddInt64(&qrs.inFlight, int64(req.count))
+	if !qrs.queue.Produce(req) {
+		atomic.AddInt64(&qrs.inFlight, -int64(req.count))
+		qrs.account.Release(req.bytes)
+		observability.RecordExporterQueueFullDrop(qrs.ctx)
+		return req.count, errQueueIsFull
+	}
+	return 0, nil
+}
+
+// drainPollInterval is how often drain checks whether the queue is empty.
+const drainPollInterval = 10 * time.Millisecond
+
+// drain waits until all the queued requests are sent, or dropped, or the
+// context is done. Retries are not interrupted, call shutdown for that.
+func (qrs *queuedRetrySender) drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		inFlight := qrs.inFlightItems()
+		if inFlight == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d items still in flight: %v", inFlight, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// inFlightItems returns the number of items queued or being sent.
+func (qrs *queuedRetrySender) inFlightItems() int {
+	return int(atomic.LoadInt64(&qrs.inFlight))
+}
+
+// export sends the request and records it if it failed with a permanent
+// error, whether or not retries are enabled.
+func (qrs *queuedRetrySender) export(ctx context.Context, req *request) (int, error) {
+	droppedItems, err := req.export(ctx)
+	if consumererror.IsPermanent(err) {
+		observability.RecordExporterPermanentError(qrs.ctx)
+	}
+	return droppedItems, err
+}
+
+// exportWithRetry calls export until it succeeds, returns a permanent error,
+// the retry settings are exhausted, the context is done, or the sender is shut
+// down. Throttled errors are retried no sooner than the delay requested by the
+// backend. The request is not retried if the deadline of the context would be
+// exceeded before the next attempt.
+func (qrs *queuedRetrySender) exportWithRetry(ctx context.Context, export func(ctx context.Context) (int, error)) (int, error) {
+	bo := newExponentialBackoff(qrs.retrySettings)
+	for {
+		droppedItems, err := export(ctx)
+		if err == nil {
+			return droppedItems, nil
+		}
+
+		if !consumererror.IsRetryable(err) {
+			return droppedItems, err
+		}
+
+		delay, ok := bo.next()
+		if ok {
+			// The backend may ask to wait longer than the backoff, which must
+			// still fit in the time left to retry the request.
+			if throttleDelay, isThrottle := consumererror.IsThrottle(err); isThrottle && throttleDelay > delay {
+				delay = throttleDelay
+				ok = bo.allows(delay)
+			}
+		}
+		if !ok {
+			observability.RecordExporterRetriesExceeded(qrs.ctx)
+			return droppedItems, err
+		}
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < delay {
+			return droppedItems, err
+		}
+
+		select {
+		case <-qrs.stopCh:
+			return droppedItems, err
+		case <-ctx.Done():
+			return droppedItems, err
+		case <-time.After(delay):
+		}
+
+		observability.RecordExporterRetry(qrs.ctx)
+	}
+}
+
+// shutdown stops the workers and any pending retry. Requests still in the
+// queue are dropped and their bytes returned to the memory budget.
+func (qrs *queuedRetrySender) shutdown() {
+	qrs.stopOnce.Do(func() {
+		close(qrs.stopCh)
+		if qrs.queue != nil {
+			qrs.queue.Stop()
+			qrs.account.Unregister()
+		}
+	})
+}
+
+// exponentialBackoff computes randomized, exponentially growing intervals
+// between retries until MaxElapsedTime is exceeded.
+type exponentialBackoff struct {
+	settings        RetrySettings
+	currentInterval time.Duration
+	startTime       time.Time
+}
+
+func newExponentialBackoff(rs RetrySettings) *exponentialBackoff {
+	return &exponentialBackoff{
+		settings:        rs,
+		currentInterval: rs.InitialInterval,
+		startTime:       time.Now(),
+	}
+}
+
+// next returns the delay before the next retry, or false if no more retries
+// should be attempted.
+func (bo *exponentialBackoff) next() (time.Duration, bool) {
+	delay := randomizedInterval(bo.settings.RandomizationFactor, bo.currentInterval)
+	if !bo.allows(delay) {
+		return 0, false
+	}
+
+	next := time.Duration(float64(bo.currentInterval) * bo.settings.Multiplier)
+	if bo.settings.MaxInterval > 0 && next > bo.settings.MaxInterval {
+		next = bo.settings.MaxInterval
+	}
+	bo.currentInterval = next
+	return delay, true
+}
+
+// allows returns whether waiting for the given delay before the next retry
+// stays within MaxElapsedTime.
+func (bo *exponentialBackoff) allows(delay time.Duration) bool {
+	return bo.settings.MaxElapsedTime <= 0 || time.Since(bo.startTime)+delay <= bo.settings.MaxElapsedTime
+}
+
+func randomizedInterval(randomizationFactor float64, interval time.Duration) time.Duration {
+	if randomizationFactor <= 0 {
+		return interval
+	}
+	delta := randomizationFactor * float64(interval)
+	minInterval := float64(interval) - delta
+	maxInterval := float64(interval) + delta
+	return time.Duration(minInterval + rand.Float64()*(maxInterval-minInterval+1))
+}
@@ -0,0 +1,367 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
+	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/observability/observabilitytest"
+)
+
+func fastRetrySettings() RetrySettings {
+	return RetrySettings{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		MaxElapsedTime:  time.Second,
+		Multiplier:      2,
+	}
+}
+
+// newFailingPushTraceData returns a PushTraceData that fails the first
+// numFailures calls with the given error.
+func newFailingPushTraceData(numFailures int32, retErr error, calls *int32) PushTraceData {
+	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		if atomic.AddInt32(calls, 1) <= numFailures {
+			return len(td.Spans), retErr
+		}
+		return 0, nil
+	}
+}
+
+func TestQueuedRetry_RetryUntilSuccess(t *testing.T) {
+	var calls int32
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(2, errors.New("transient"), &calls),
+		WithRetry(fastRetrySettings()))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}
+	assert.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestQueuedRetry_PermanentErrorIsNotRetried(t *testing.T) {
+	var calls int32
+	want := consumererror.Permanent(errors.New("bad data"))
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(2, want, &calls),
+		WithRetry(fastRetrySettings()))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	assert.Equal(t, want, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestQueuedRetry_MaxElapsedTime(t *testing.T) {
+	var calls int32
+	want := errors.New("transient")
+	rs := fastRetrySettings()
+	rs.MaxElapsedTime = 20 * time.Millisecond
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(1000, want, &calls),
+		WithRetry(rs))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	assert.Equal(t, want, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.True(t, atomic.LoadInt32(&calls) > 1)
+}
+
+func TestQueuedRetry_QueueSendsAsynchronously(t *testing.T) {
+	var calls int32
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(1, errors.New("transient"), &calls),
+		WithQueue(QueueSettings{Enabled: true, NumWorkers: 1, QueueSize: 10}),
+		WithRetry(fastRetrySettings()))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	assert.NoError(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func TestQueuedRetry_QueueIsFull(t *testing.T) {
+	blockCh := make(chan struct{})
+	push := func(ctx context.Context, td consumerdata.MetricsData) (int, error) {
+		<-blockCh
+		return 0, nil
+	}
+	me, err := NewMetricsExporter(
+		fakeExporterName,
+		push,
+		WithQueue(QueueSettings{Enabled: true, NumWorkers: 1, QueueSize: 1}))
+	require.NoError(t, err)
+	defer me.Shutdown()
+	defer close(blockCh)
+
+	// With one blocked worker and a queue of size one, at most two requests
+	// are accepted before the queue reports that it is full.
+	var gotErr error
+	for i := 0; i < 3; i++ {
+		if err := me.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{}); err != nil {
+			gotErr = err
+		}
+	}
+	assert.Equal(t, errQueueIsFull, gotErr)
+}
+
+func TestQueuedRetry_MemoryBudgetExceeded(t *testing.T) {
+	span := &tracepb.Span{Name: &tracepb.TruncatableString{Value: "operation"}}
+	budget := memorybudget.Default()
+	budget.SetLimit(int64(proto.Size(span)))
+	defer budget.SetLimit(0)
+
+	blockCh := make(chan struct{})
+	push := func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		<-blockCh
+		return 0, nil
+	}
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		push,
+		WithQueue(QueueSettings{Enabled: true, NumWorkers: 1, QueueSize: 10}))
+	require.NoError(t, err)
+
+	td := consumerdata.TraceData{Spans: []*tracepb.Span{span}}
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	// The first request holds the whole budget until it is exported.
+	assert.Equal(t, errMemoryBudgetExceeded, te.ConsumeTraceData(context.Background(), td))
+	assert.EqualValues(t, proto.Size(span), budget.Used())
+
+	close(blockCh)
+	require.NoError(t, te.Shutdown())
+	assert.EqualValues(t, 0, budget.Used())
+}
+
+func TestQueuedRetry_Drain(t *testing.T) {
+	blockCh := make(chan struct{})
+	push := func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		<-blockCh
+		return 0, nil
+	}
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		push,
+		WithQueue(QueueSettings{Enabled: true, NumWorkers: 1, QueueSize: 10}))
+	require.NoError(t, err)
+	defer te.Shutdown()
+	drainer := te.(exporter.Drainer)
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	assert.Equal(t, 6, drainer.InFlight())
+
+	// The requests are blocked, drain gives up when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, drainer.Drain(ctx))
+
+	close(blockCh)
+	assert.NoError(t, drainer.Drain(context.Background()))
+	assert.Equal(t, 0, drainer.InFlight())
+}
+
+func TestQueuedRetry_ShutdownCallsShutdownFunc(t *testing.T) {
+	called := false
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newPushTraceData(0, nil),
+		WithQueue(CreateDefaultQueueSettings()),
+		WithShutdown(func() error {
+			called = true
+			return nil
+		}))
+	require.NoError(t, err)
+	assert.NoError(t, te.Shutdown())
+	assert.True(t, called)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	bo := newExponentialBackoff(RetrySettings{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     4 * time.Millisecond,
+		Multiplier:      2,
+	})
+	for _, want := range []time.Duration{1, 2, 4, 4} {
+		delay, ok := bo.next()
+		require.True(t, ok)
+		assert.Equal(t, want*time.Millisecond, delay)
+	}
+}
+
+func TestQueuedRetry_ThrottleDelay(t *testing.T) {
+	var calls int32
+	throttleDelay := 30 * time.Millisecond
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(1, consumererror.Throttle(errors.New("slow down"), throttleDelay), &calls),
+		WithRetry(fastRetrySettings()))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	start := time.Now()
+	assert.NoError(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.True(t, time.Since(start) >= throttleDelay)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func TestQueuedRetry_ThrottleDelayExceedsMaxElapsedTime(t *testing.T) {
+	var calls int32
+	want := consumererror.Throttle(errors.New("slow down"), time.Hour)
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(1, want, &calls),
+		WithRetry(fastRetrySettings()))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	// The backend asks to wait longer than MaxElapsedTime, so the request is
+	// dropped right away instead of being retried after the budget.
+	start := time.Now()
+	assert.Equal(t, want, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.True(t, time.Since(start) < time.Second)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestQueuedRetry_PermanentErrorRecordedWithoutRetry(t *testing.T) {
+	doneFn := observabilitytest.SetupRecordedMetricsTest()
+	defer doneFn()
+
+	const exporterName = "permanent_error_exporter"
+	var calls int32
+	te, err := NewTraceExporter(
+		exporterName,
+		newFailingPushTraceData(1, consumererror.Permanent(errors.New("bad data")), &calls))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	assert.Error(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+
+	rows, err := view.RetrieveData(observability.ViewExporterPermanentErrors.Name)
+	require.NoError(t, err)
+	var permanentErrors float64
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == observability.TagKeyExporter && tg.Value == exporterName {
+				permanentErrors += row.Data.(*view.SumData).Value
+			}
+		}
+	}
+	assert.EqualValues(t, 1, permanentErrors)
+}
+
+func TestQueuedRetry_DeadlineBeforeNextRetry(t *testing.T) {
+	var calls int32
+	want := errors.New("transient")
+	rs := fastRetrySettings()
+	rs.InitialInterval = time.Hour
+	rs.MaxInterval = time.Hour
+	rs.MaxElapsedTime = 0
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		newFailingPushTraceData(1, want, &calls),
+		WithRetry(rs))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	// The next attempt would be after the deadline of the request.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, want, te.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+// blockingPushTraceData blocks until the context is done and returns its error.
+func blockingPushTraceData(started chan<- struct{}) PushTraceData {
+	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return len(td.Spans), ctx.Err()
+	}
+}
+
+func TestQueuedRetry_CanceledRequestAbortsExport(t *testing.T) {
+	started := make(chan struct{}, 1)
+	te, err := NewTraceExporter(fakeExporterName, blockingPushTraceData(started))
+	require.NoError(t, err)
+	defer te.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() { errCh <- te.ConsumeTraceData(ctx, consumerdata.TraceData{}) }()
+	<-started
+	cancel()
+	assert.Equal(t, context.Canceled, <-errCh)
+}
+
+func TestQueuedRetry_ShutdownAbortsExport(t *testing.T) {
+	started := make(chan struct{}, 1)
+	te, err := NewTraceExporter(fakeExporterName, blockingPushTraceData(started))
+	require.NoError(t, err)
+
+	errCh := make(chan error)
+	go func() { errCh <- te.ConsumeTraceData(context.Background(), consumerdata.TraceData{}) }()
+	<-started
+	require.NoError(t, te.Shutdown())
+	assert.Equal(t, context.Canceled, <-errCh)
+}
+
+func TestQueuedRetry_QueuedExportIsDetachedUntilShutdown(t *testing.T) {
+	started := make(chan struct{}, 1)
+	te, err := NewTraceExporter(
+		fakeExporterName,
+		blockingPushTraceData(started),
+		WithQueue(QueueSettings{Enabled: true, NumWorkers: 1, QueueSize: 10}))
+	require.NoError(t, err)
+
+	// The queued request is still sent once the call returned and its context
+	// is canceled, until the exporter shuts down.
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, te.ConsumeTraceData(ctx, consumerdata.TraceData{Spans: make([]*tracepb.Span, 2)}))
+	cancel()
+	<-started
+	drainer := te.(exporter.Drainer)
+	assert.Equal(t, 2, drainer.InFlight())
+
+	require.NoError(t, te.Shutdown())
+	assert.NoError(t, drainer.Drain(context.Background()))
+}
@@ -0,0 +1,113 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/oterr"
+)
+
+// SplitSettings defines configuration for splitting the batches exceeding the
+// limits of the backend into several requests, e.g. the 4MiB default maximum
+// message size of gRPC servers.
+type SplitSettings struct {
+	// MaxItems is the maximum number of spans or metrics of a request, zero
+	// means no limit.
+	MaxItems int `mapstructure:"max-items"`
+	// MaxBytes is the maximum estimated size in bytes of the protobuf
+	// encoding of the spans or metrics of a request, zero means no limit. A
+	// single span or metric bigger than MaxBytes is sent in its own request.
+	MaxBytes int `mapstructure:"max-bytes"`
+}
+
+// enabled returns whether the batches can be split.
+func (ss SplitSettings) enabled() bool {
+	return ss.MaxItems > 0 || ss.MaxBytes > 0
+}
+
+// splitBounds returns the end index of every chunk of the n items such that
+// no chunk exceeds the limits, size returns the size in bytes of the item i.
+func (ss SplitSettings) splitBounds(n int, size func(i int) int) []int {
+	var ends []int
+	start, bytes := 0, 0
+	for i := 0; i < n; i++ {
+		itemBytes := 0
+		if ss.MaxBytes > 0 {
+			itemBytes = size(i)
+		}
+		if i > start &&
+			((ss.MaxItems > 0 && i-start >= ss.MaxItems) ||
+				(ss.MaxBytes > 0 && bytes+itemBytes > ss.MaxBytes)) {
+			ends = append(ends, i)
+			start, bytes = i, 0
+		}
+		bytes += itemBytes
+	}
+	return append(ends, n)
+}
+
+// splitTraceData splits the spans of td in chunks respecting the limits, all
+// the chunks keep the node, resource and source format of td.
+func splitTraceData(td consumerdata.TraceData, ss SplitSettings) []consumerdata.TraceData {
+	if !ss.enabled() {
+		return []consumerdata.TraceData{td}
+	}
+	ends := ss.splitBounds(len(td.Spans), func(i int) int { return proto.Size(td.Spans[i]) })
+	chunks := make([]consumerdata.TraceData, 0, len(ends))
+	start := 0
+	for _, end := range ends {
+		chunk := td
+		chunk.Spans = td.Spans[start:end]
+		chunks = append(chunks, chunk)
+		start = end
+	}
+	return chunks
+}
+
+// splitMetricsData splits the metrics of md in chunks respecting the limits,
+// all the chunks keep the node and resource of md.
+func splitMetricsData(md consumerdata.MetricsData, ss SplitSettings) []consumerdata.MetricsData {
+	if !ss.enabled() {
+		return []consumerdata.MetricsData{md}
+	}
+	ends := ss.splitBounds(len(md.Metrics), func(i int) int { return proto.Size(md.Metrics[i]) })
+	chunks := make([]consumerdata.MetricsData, 0, len(ends))
+	start := 0
+	for _, end := range ends {
+		chunk := md
+		chunk.Metrics = md.Metrics[start:end]
+		chunks = append(chunks, chunk)
+		start = end
+	}
+	return chunks
+}
+
+// combineChunkErrors combines the errors of the chunks of a batch. The
+// combined error is permanent only if all of them are, otherwise retrying the
+// batch may succeed.
+func combineChunkErrors(errs []error) error {
+	if len(errs) <= 1 {
+		return oterr.CombineErrors(errs)
+	}
+	for _, err := range errs {
+		if !consumererror.IsPermanent(err) {
+			return oterr.CombineErrors(errs)
+		}
+	}
+	return consumererror.Permanent(oterr.CombineErrors(errs))
+}
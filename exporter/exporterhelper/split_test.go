@@ -0,0 +1,155 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+)
+
+func TestSplitBounds(t *testing.T) {
+	sizes := []int{10, 10, 30, 5, 5, 5}
+	size := func(i int) int { return sizes[i] }
+	tests := []struct {
+		name     string
+		settings SplitSettings
+		want     []int
+	}{
+		{name: "no_limit", settings: SplitSettings{}, want: []int{6}},
+		{name: "max_items", settings: SplitSettings{MaxItems: 4}, want: []int{4, 6}},
+		{name: "max_bytes", settings: SplitSettings{MaxBytes: 20}, want: []int{2, 3, 6}},
+		{name: "item_bigger_than_max_bytes", settings: SplitSettings{MaxBytes: 15}, want: []int{1, 2, 3, 6}},
+		{name: "both", settings: SplitSettings{MaxItems: 2, MaxBytes: 40}, want: []int{2, 4, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.splitBounds(len(sizes), size); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitBounds: Want %v Got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitTraceData(t *testing.T) {
+	td := consumerdata.TraceData{SourceFormat: "test", Spans: make([]*tracepb.Span, 5)}
+	for i := range td.Spans {
+		td.Spans[i] = &tracepb.Span{Name: &tracepb.TruncatableString{Value: "span"}}
+	}
+
+	chunks := splitTraceData(td, SplitSettings{MaxItems: 2})
+	if len(chunks) != 3 {
+		t.Fatalf("Chunks: Want 3 Got %d", len(chunks))
+	}
+	var spans []*tracepb.Span
+	for _, chunk := range chunks {
+		if chunk.SourceFormat != td.SourceFormat {
+			t.Fatalf("SourceFormat: Want %q Got %q", td.SourceFormat, chunk.SourceFormat)
+		}
+		spans = append(spans, chunk.Spans...)
+	}
+	if !reflect.DeepEqual(spans, td.Spans) {
+		t.Fatalf("Spans: Want %v Got %v", td.Spans, spans)
+	}
+
+	if chunks := splitTraceData(td, SplitSettings{}); len(chunks) != 1 || len(chunks[0].Spans) != 5 {
+		t.Fatalf("Chunks without limits: Want the batch Got %v", chunks)
+	}
+}
+
+func TestSplitMetricsData(t *testing.T) {
+	md := consumerdata.MetricsData{Metrics: make([]*metricspb.Metric, 4)}
+	for i := range md.Metrics {
+		md.Metrics[i] = &metricspb.Metric{MetricDescriptor: &metricspb.MetricDescriptor{Name: "metric"}}
+	}
+	size := proto.Size(md.Metrics[0])
+
+	chunks := splitMetricsData(md, SplitSettings{MaxBytes: 3 * size})
+	if len(chunks) != 2 || len(chunks[0].Metrics) != 3 || len(chunks[1].Metrics) != 1 {
+		t.Fatalf("Chunks: Want 3 and 1 metrics Got %v", chunks)
+	}
+}
+
+func TestCombineChunkErrors(t *testing.T) {
+	if err := combineChunkErrors(nil); err != nil {
+		t.Fatalf("combineChunkErrors: Want nil Got %v", err)
+	}
+
+	retryable := errors.New("retryable")
+	permanent := consumererror.Permanent(errors.New("permanent"))
+	if err := combineChunkErrors([]error{retryable}); err != retryable {
+		t.Fatalf("combineChunkErrors: Want %v Got %v", retryable, err)
+	}
+	if err := combineChunkErrors([]error{permanent, retryable}); err == nil || consumererror.IsPermanent(err) {
+		t.Fatalf("combineChunkErrors: Want retryable error Got %v", err)
+	}
+	if err := combineChunkErrors([]error{permanent, permanent}); !consumererror.IsPermanent(err) {
+		t.Fatalf("combineChunkErrors: Want permanent error Got %v", err)
+	}
+}
+
+func TestTraceExporter_WithSplit(t *testing.T) {
+	var pushed []int
+	push := func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		pushed = append(pushed, len(td.Spans))
+		return 0, nil
+	}
+	te, err := NewTraceExporter(fakeExporterName, push, WithSplit(SplitSettings{MaxItems: 2}))
+	if err != nil {
+		t.Fatalf("NewTraceExporter returns: Want nil Got %v", err)
+	}
+	defer te.Shutdown()
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 5)}
+	if err := te.ConsumeTraceData(context.Background(), td); err != nil {
+		t.Fatalf("ConsumeTraceData returns: Want nil Got %v", err)
+	}
+	if want := []int{2, 2, 1}; !reflect.DeepEqual(pushed, want) {
+		t.Fatalf("Pushed spans: Want %v Got %v", want, pushed)
+	}
+}
+
+func TestMetricsExporter_WithSplit_ReturnError(t *testing.T) {
+	want := errors.New("my_error")
+	calls := 0
+	push := func(ctx context.Context, md consumerdata.MetricsData) (int, error) {
+		calls++
+		if calls == 2 {
+			return len(md.Metrics), want
+		}
+		return 0, nil
+	}
+	me, err := NewMetricsExporter(fakeExporterName, push, WithSplit(SplitSettings{MaxItems: 1}))
+	if err != nil {
+		t.Fatalf("NewMetricsExporter returns: Want nil Got %v", err)
+	}
+	defer me.Shutdown()
+
+	md := consumerdata.MetricsData{Metrics: make([]*metricspb.Metric, 3)}
+	if err := me.ConsumeMetricsData(context.Background(), md); err != want {
+		t.Fatalf("ConsumeMetricsData returns: Want %v Got %v", want, err)
+	}
+	if calls != 3 {
+		t.Fatalf("Pushed chunks: Want 3 Got %d", calls)
+	}
+}
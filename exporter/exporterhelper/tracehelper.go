@@ -16,9 +16,12 @@ package exporterhelper
 
 import (
 	"context"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/observability"
@@ -31,13 +34,47 @@ type PushTraceData func(ctx context.Context, td consumerdata.TraceData) (dropped
 type traceExporter struct {
 	exporterName  string
 	pushTraceData PushTraceData
+	sender        *queuedRetrySender
+	shutdown      Shutdown
+	recordMetrics bool
+	splitSettings SplitSettings
+	capabilities  consumer.Capabilities
 }
 
 var _ (exporter.TraceExporter) = (*traceExporter)(nil)
+var _ (exporter.Drainer) = (*traceExporter)(nil)
+var _ (consumer.Capable) = (*traceExporter)(nil)
 
 func (te *traceExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
 	exporterCtx := observability.ContextWithExporterName(ctx, te.exporterName)
-	_, err := te.pushTraceData(exporterCtx, td)
+	var errs []error
+	for _, chunk := range splitTraceData(td, te.splitSettings) {
+		if err := te.sendTraceData(exporterCtx, chunk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return combineChunkErrors(errs)
+}
+
+func (te *traceExporter) sendTraceData(exporterCtx context.Context, td consumerdata.TraceData) error {
+	_, err := te.sender.send(&request{
+		ctx: exporterCtx,
+		export: func(ctx context.Context) (int, error) {
+			return te.pushTraceData(ctx, td)
+		},
+		count: len(td.Spans),
+		size: func() int64 {
+			var size int
+			for _, span := range td.Spans {
+				size += proto.Size(span)
+			}
+			return int64(size)
+		},
+	})
+	if (err == errQueueIsFull || err == errMemoryBudgetExceeded) && te.recordMetrics {
+		// The request never reached pushTraceData, record all its spans as dropped.
+		observability.RecordTraceExporterMetrics(exporterCtx, len(td.Spans), len(td.Spans))
+	}
 	return err
 }
 
@@ -45,9 +82,34 @@ func (te *traceExporter) Name() string {
 	return te.exporterName
 }
 
+// Drain waits until the queued requests are sent, see exporter.Drainer.
+func (te *traceExporter) Drain(ctx context.Context) error {
+	return te.sender.drain(ctx)
+}
+
+// InFlight returns the number of items queued or being sent.
+func (te *traceExporter) InFlight() int {
+	return te.sender.inFlightItems()
+}
+
+// Capabilities returns the Capabilities given with WithCapabilities, see
+// consumer.Capable.
+func (te *traceExporter) Capabilities() consumer.Capabilities {
+	return te.capabilities
+}
+
+func (te *traceExporter) Shutdown() error {
+	te.sender.shutdown()
+	if te.shutdown != nil {
+		return te.shutdown()
+	}
+	return nil
+}
+
 // NewTraceExporter creates an TraceExporter that can record metrics and can wrap every request with a Span.
+// It can also split big batches, queue requests, bound and retry failed ones, see WithSplit, WithQueue,
+// WithTimeout and WithRetry.
 // If no options are passed it just adds the exporter format as a tag in the Context.
-// TODO: Add support for retries.
 func NewTraceExporter(exporterName string, pushTraceData PushTraceData, options ...ExporterOption) (exporter.TraceExporter, error) {
 	if exporterName == "" {
 		return nil, errEmptyExporterName
@@ -58,23 +120,58 @@ func NewTraceExporter(exporterName string, pushTraceData PushTraceData, options
 	}
 
 	opts := newExporterOptions(options...)
-	if opts.recordMetrics {
-		pushTraceData = pushTraceDataWithMetrics(pushTraceData)
+	sender := newQueuedRetrySender(exporterName, opts.queueSettings, opts.retrySettings)
+
+	// Every attempt is bounded by the timeout and wrapped with a Span.
+	if opts.timeoutSettings.Timeout > 0 {
+		pushTraceData = pushTraceDataWithTimeout(pushTraceData, opts.timeoutSettings.Timeout)
 	}
 
 	if opts.spanName != "" {
 		pushTraceData = pushTraceDataWithSpan(pushTraceData, opts.spanName)
 	}
 
+	if opts.retrySettings.Enabled {
+		pushTraceData = pushTraceDataWithRetry(pushTraceData, sender)
+	}
+
+	// Metrics are recorded after all the retries, otherwise the number of
+	// spans received + dropped will be different than the number of received
+	// spans in the receiver.
+	if opts.recordMetrics {
+		pushTraceData = pushTraceDataWithMetrics(pushTraceData)
+	}
+
 	return &traceExporter{
 		exporterName:  exporterName,
 		pushTraceData: pushTraceData,
+		sender:        sender,
+		shutdown:      opts.shutdown,
+		recordMetrics: opts.recordMetrics,
+		splitSettings: opts.splitSettings,
+		capabilities:  opts.capabilities,
 	}, nil
 }
 
+func pushTraceDataWithTimeout(next PushTraceData, timeout time.Duration) PushTraceData {
+	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		dropped, err := next(ctx, td)
+		return dropped, timeoutError(ctx, timeout, err)
+	}
+}
+
+func pushTraceDataWithRetry(next PushTraceData, sender *queuedRetrySender) PushTraceData {
+	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		return sender.exportWithRetry(ctx, func(ctx context.Context) (int, error) {
+			return next(ctx, td)
+		})
+	}
+}
+
 func pushTraceDataWithMetrics(next PushTraceData) PushTraceData {
 	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
-		// TODO: Add retry logic here if we want to support because we need to record special metrics.
 		droppedSpans, err := next(ctx, td)
 		// TODO: How to record the reason of dropping?
 		observability.RecordTraceExporterMetrics(ctx, len(td.Spans), droppedSpans)
@@ -18,11 +18,14 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/observability"
 	"github.com/open-telemetry/opentelemetry-service/observability/observabilitytest"
@@ -60,6 +63,27 @@ func TestTraceExporter_Default(t *testing.T) {
 	}
 }
 
+func TestTraceExporter_WithCapabilities(t *testing.T) {
+	te, err := NewTraceExporter(fakeExporterName, newPushTraceData(0, nil))
+	if err != nil {
+		t.Fatalf("NewTraceExporter returns: Want nil Got %v", err)
+	}
+	if !consumer.GetCapabilities(te).MutatesConsumedData {
+		t.Fatalf("Capabilities returns: Want mutating by default Got read-only")
+	}
+
+	te, err = NewTraceExporter(
+		fakeExporterName,
+		newPushTraceData(0, nil),
+		WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}))
+	if err != nil {
+		t.Fatalf("NewTraceExporter returns: Want nil Got %v", err)
+	}
+	if consumer.GetCapabilities(te).MutatesConsumedData {
+		t.Fatalf("Capabilities returns: Want read-only Got mutating")
+	}
+}
+
 func TestTraceExporter_Default_ReturnError(t *testing.T) {
 	td := consumerdata.TraceData{}
 	want := errors.New("my_error")
@@ -122,6 +146,43 @@ func TestTraceExporter_WithSpan_ReturnError(t *testing.T) {
 	checkWrapSpanForTraceExporter(t, te, want, 0)
 }
 
+func TestTraceExporter_WithTimeout(t *testing.T) {
+	push := func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		<-ctx.Done()
+		return len(td.Spans), ctx.Err()
+	}
+	te, err := NewTraceExporter(fakeExporterName, push, WithTimeout(TimeoutSettings{Timeout: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewTraceExporter returns: Want nil Got %v", err)
+	}
+	defer te.Shutdown()
+
+	err = te.ConsumeTraceData(context.Background(), consumerdata.TraceData{})
+	if err == nil {
+		t.Fatal("ConsumeTraceData returns: Want timeout error Got nil")
+	}
+	if consumererror.IsPermanent(err) {
+		t.Fatalf("ConsumeTraceData returns: Want retryable error Got %v", err)
+	}
+}
+
+func TestTraceExporter_WithTimeout_KeepsPermanentError(t *testing.T) {
+	push := func(ctx context.Context, td consumerdata.TraceData) (int, error) {
+		<-ctx.Done()
+		return len(td.Spans), consumererror.Permanent(ctx.Err())
+	}
+	te, err := NewTraceExporter(fakeExporterName, push, WithTimeout(TimeoutSettings{Timeout: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewTraceExporter returns: Want nil Got %v", err)
+	}
+	defer te.Shutdown()
+
+	err = te.ConsumeTraceData(context.Background(), consumerdata.TraceData{})
+	if !consumererror.IsPermanent(err) {
+		t.Fatalf("ConsumeTraceData returns: Want permanent error Got %v", err)
+	}
+}
+
 func newPushTraceData(droppedSpans int, retError error) PushTraceData {
 	return func(ctx context.Context, td consumerdata.TraceData) (int, error) {
 		return droppedSpans, retError
@@ -49,6 +49,10 @@ func (ne *nopExporter) Name() string {
 	return ne.name
 }
 
+func (ne *nopExporter) Shutdown() error {
+	return nil
+}
+
 // NewNopTraceExporter creates an TraceExporter that just drops the received data.
 func NewNopTraceExporter(options ...NopExporterOption) exporter.TraceExporter {
 	return newNopTraceExporter(options...)
@@ -22,10 +22,12 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 )
 
-// SinkTraceExporter acts as a trace receiver for use in tests.
+// SinkTraceExporter acts as a trace receiver for use in tests. It stores all
+// the trace data it receives, it can be used concurrently.
 type SinkTraceExporter struct {
-	mu     sync.Mutex
-	traces []consumerdata.TraceData
+	mu         sync.Mutex
+	traces     []consumerdata.TraceData
+	spansCount int
 }
 
 var _ exporter.TraceExporter = (*SinkTraceExporter)(nil)
@@ -36,6 +38,7 @@ func (ste *SinkTraceExporter) ConsumeTraceData(ctx context.Context, td consumerd
 	defer ste.mu.Unlock()
 
 	ste.traces = append(ste.traces, td)
+	ste.spansCount += len(td.Spans)
 
 	return nil
 }
@@ -50,28 +53,57 @@ func (ste *SinkTraceExporter) Name() string {
 	return sinkTraceExportFormat
 }
 
+// Shutdown stops the exporter and is invoked during shutdown.
+func (ste *SinkTraceExporter) Shutdown() error {
+	return nil
+}
+
 // AllTraces returns the traces sent to the test sink.
 func (ste *SinkTraceExporter) AllTraces() []consumerdata.TraceData {
 	ste.mu.Lock()
 	defer ste.mu.Unlock()
 
-	return ste.traces[:]
+	return append([]consumerdata.TraceData(nil), ste.traces...)
+}
+
+// SpansCount returns the number of spans sent to the test sink.
+func (ste *SinkTraceExporter) SpansCount() int {
+	ste.mu.Lock()
+	defer ste.mu.Unlock()
+
+	return ste.spansCount
 }
 
-// SinkMetricsExporter acts as a metrics receiver for use in tests.
+// Reset deletes the traces sent to the test sink.
+func (ste *SinkTraceExporter) Reset() {
+	ste.mu.Lock()
+	defer ste.mu.Unlock()
+
+	ste.traces = nil
+	ste.spansCount = 0
+}
+
+// SinkMetricsExporter acts as a metrics receiver for use in tests. It stores
+// all the metrics data it receives, it can be used concurrently.
 type SinkMetricsExporter struct {
-	mu      sync.Mutex
-	metrics []consumerdata.MetricsData
+	mu              sync.Mutex
+	metrics         []consumerdata.MetricsData
+	metricsCount    int
+	timeseriesCount int
 }
 
 var _ exporter.MetricsExporter = (*SinkMetricsExporter)(nil)
 
-// ConsumeMetricsData stores traces for tests.
+// ConsumeMetricsData stores metrics for tests.
 func (sme *SinkMetricsExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
 	sme.mu.Lock()
 	defer sme.mu.Unlock()
 
 	sme.metrics = append(sme.metrics, md)
+	sme.metricsCount += len(md.Metrics)
+	for _, metric := range md.Metrics {
+		sme.timeseriesCount += len(metric.GetTimeseries())
+	}
 
 	return nil
 }
@@ -81,10 +113,42 @@ func (sme *SinkMetricsExporter) Name() string {
 	return sinkMetricsExportFormat
 }
 
+// Shutdown stops the exporter and is invoked during shutdown.
+func (sme *SinkMetricsExporter) Shutdown() error {
+	return nil
+}
+
 // AllMetrics returns the metrics sent to the test sink.
 func (sme *SinkMetricsExporter) AllMetrics() []consumerdata.MetricsData {
 	sme.mu.Lock()
 	defer sme.mu.Unlock()
 
-	return sme.metrics[:]
+	return append([]consumerdata.MetricsData(nil), sme.metrics...)
+}
+
+// MetricsCount returns the number of metrics sent to the test sink.
+func (sme *SinkMetricsExporter) MetricsCount() int {
+	sme.mu.Lock()
+	defer sme.mu.Unlock()
+
+	return sme.metricsCount
+}
+
+// TimeSeriesCount returns the number of time series of the metrics sent to
+// the test sink.
+func (sme *SinkMetricsExporter) TimeSeriesCount() int {
+	sme.mu.Lock()
+	defer sme.mu.Unlock()
+
+	return sme.timeseriesCount
+}
+
+// Reset deletes the metrics sent to the test sink.
+func (sme *SinkMetricsExporter) Reset() {
+	sme.mu.Lock()
+	defer sme.mu.Unlock()
+
+	sme.metrics = nil
+	sme.metricsCount = 0
+	sme.timeseriesCount = 0
 }
@@ -16,6 +16,7 @@ package exportertest
 import (
 	"context"
 	"reflect"
+	"sync"
 	"testing"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
@@ -42,12 +43,45 @@ func TestSinkTraceExporter(t *testing.T) {
 	if sink.Name() != "sink_trace" {
 		t.Errorf("Wanted sink_trace got %s", sink.Name())
 	}
+	if sink.SpansCount() != 49 {
+		t.Errorf("Wanted 49 spans got %d", sink.SpansCount())
+	}
+
+	sink.Reset()
+	if len(sink.AllTraces()) != 0 || sink.SpansCount() != 0 {
+		t.Errorf("Wanted no traces after reset")
+	}
+}
+
+func TestSinkTraceExporter_concurrent(t *testing.T) {
+	sink := new(SinkTraceExporter)
+	td := consumerdata.TraceData{
+		Spans: make([]*tracepb.Span, 3),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_ = sink.ConsumeTraceData(context.Background(), td)
+				_ = sink.AllTraces()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(sink.AllTraces()) != 100 || sink.SpansCount() != 300 {
+		t.Errorf("Wanted 100 traces and 300 spans got %d and %d", len(sink.AllTraces()), sink.SpansCount())
+	}
 }
 
 func TestSinkMetricsExporter(t *testing.T) {
 	sink := new(SinkMetricsExporter)
 	md := consumerdata.MetricsData{
-		Metrics: make([]*metricspb.Metric, 7),
+		Metrics: []*metricspb.Metric{
+			{Timeseries: make([]*metricspb.TimeSeries, 2)},
+			{Timeseries: make([]*metricspb.TimeSeries, 1)},
+		},
 	}
 	want := make([]consumerdata.MetricsData, 0, 7)
 	for i := 0; i < 7; i++ {
@@ -63,4 +97,12 @@ func TestSinkMetricsExporter(t *testing.T) {
 	if sink.Name() != "sink_metrics" {
 		t.Errorf("Wanted sink_metrics got %s", sink.Name())
 	}
+	if sink.MetricsCount() != 14 || sink.TimeSeriesCount() != 21 {
+		t.Errorf("Wanted 14 metrics and 21 time series got %d and %d", sink.MetricsCount(), sink.TimeSeriesCount())
+	}
+
+	sink.Reset()
+	if len(sink.AllMetrics()) != 0 || sink.MetricsCount() != 0 || sink.TimeSeriesCount() != 0 {
+		t.Errorf("Wanted no metrics after reset")
+	}
 }
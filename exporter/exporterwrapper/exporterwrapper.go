@@ -25,6 +25,7 @@ import (
 	"go.opencensus.io/trace"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
@@ -48,6 +49,7 @@ func NewExporterWrapper(exporterName string, spanName string, ocExporter trace.E
 		},
 		exporterhelper.WithSpanName(spanName),
 		exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
 	)
 }
 
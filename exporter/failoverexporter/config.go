@@ -0,0 +1,51 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failoverexporter
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+)
+
+// Config defines configuration for the failover exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Exporters are the names of the exporters the data is sent to, by
+	// decreasing priority.
+	Exporters []string `mapstructure:"exporters"`
+
+	// MaxConsecutiveErrors is the number of consecutive errors after which an
+	// exporter is unhealthy and the data is sent to the next one.
+	MaxConsecutiveErrors int `mapstructure:"max-consecutive-errors"`
+
+	// Probation is the time after which an unhealthy exporter is tried again.
+	Probation time.Duration `mapstructure:"probation"`
+}
+
+var _ exporter.SynchronousWrappingConfig = (*Config)(nil)
+
+// WrappedExporters returns the exporters the data is sent to.
+func (cfg *Config) WrappedExporters() []string {
+	return cfg.Exporters
+}
+
+// SynchronousExporters returns all the exporters, their errors make them
+// unhealthy.
+func (cfg *Config) SynchronousExporters() []string {
+	return cfg.Exporters
+}
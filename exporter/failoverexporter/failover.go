@@ -0,0 +1,178 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package failoverexporter implements an exporter that sends the data to the
+// first healthy exporter of an ordered list, failing over to the next ones
+// on consecutive errors and failing back after a probation period.
+package failoverexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/oterr"
+	"github.com/open-telemetry/opentelemetry-service/processor/multiconsumer"
+)
+
+// health is the health of one of the exporters.
+type health struct {
+	consecutiveErrors int
+	// unhealthyUntil is the end of the probation of the exporter, zero when
+	// it is healthy.
+	unhealthyUntil time.Time
+}
+
+// failover chooses the exporters the data is sent to, it is shared by the
+// trace and metrics exporters.
+type failover struct {
+	logger               *zap.Logger
+	names                []string
+	maxConsecutiveErrors int
+	probation            time.Duration
+	now                  func() time.Time
+
+	mu     sync.Mutex
+	health []health
+}
+
+func newFailover(logger *zap.Logger, cfg Config) (*failover, error) {
+	if len(cfg.Exporters) == 0 {
+		return nil, errors.New("exporters must list the exporters to send the data to")
+	}
+	if cfg.MaxConsecutiveErrors <= 0 {
+		return nil, errors.New("max-consecutive-errors must be positive")
+	}
+	return &failover{
+		logger:               logger,
+		names:                cfg.Exporters,
+		maxConsecutiveErrors: cfg.MaxConsecutiveErrors,
+		probation:            cfg.Probation,
+		now:                  time.Now,
+		health:               make([]health, len(cfg.Exporters)),
+	}, nil
+}
+
+// candidates returns the indexes of the exporters to try in order: the
+// healthy ones and the ones whose probation ended, or all of them if none
+// is.
+func (fo *failover) candidates() []int {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+
+	now := fo.now()
+	var result []int
+	for i, h := range fo.health {
+		if !now.Before(h.unhealthyUntil) {
+			result = append(result, i)
+		}
+	}
+	if len(result) == 0 {
+		for i := range fo.health {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// record updates the health of the exporter with the result of a send.
+func (fo *failover) record(i int, err error) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+
+	h := &fo.health[i]
+	if err == nil {
+		if !h.unhealthyUntil.IsZero() {
+			fo.logger.Info("Exporter is healthy again", zap.String("exporter", fo.names[i]))
+		}
+		*h = health{}
+		return
+	}
+
+	h.consecutiveErrors++
+	if h.consecutiveErrors >= fo.maxConsecutiveErrors {
+		if h.unhealthyUntil.IsZero() {
+			fo.logger.Warn("Exporter is unhealthy, failing over to the next one",
+				zap.String("exporter", fo.names[i]), zap.Error(err))
+		}
+		h.unhealthyUntil = fo.now().Add(fo.probation)
+	}
+}
+
+// send sends the data with the first candidate exporter that succeeds.
+func (fo *failover) send(sendTo func(i int) error) error {
+	var errs []error
+	for _, i := range fo.candidates() {
+		err := sendTo(i)
+		fo.record(i, err)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("failed to send the data to %q: %v", fo.names[i], err))
+	}
+	return oterr.CombineErrors(errs)
+}
+
+type traceExporter struct {
+	*failover
+	exporters []consumer.TraceConsumer
+}
+
+var _ consumer.TraceConsumer = (*traceExporter)(nil)
+var _ exporter.TraceWrapper = (*traceExporter)(nil)
+
+func (te *traceExporter) SetTraceExporters(exporters []consumer.TraceConsumer) {
+	te.exporters = exporters
+}
+
+func (te *traceExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return te.send(func(i int) error {
+		// The next exporters tried must get the data unmodified.
+		data := td
+		if consumer.GetCapabilities(te.exporters[i]).MutatesConsumedData {
+			data = multiconsumer.CloneTraceData(td)
+		}
+		return te.exporters[i].ConsumeTraceData(ctx, data)
+	})
+}
+
+type metricsExporter struct {
+	*failover
+	exporters []consumer.MetricsConsumer
+}
+
+var _ consumer.MetricsConsumer = (*metricsExporter)(nil)
+var _ exporter.MetricsWrapper = (*metricsExporter)(nil)
+
+func (me *metricsExporter) SetMetricsExporters(exporters []consumer.MetricsConsumer) {
+	me.exporters = exporters
+}
+
+func (me *metricsExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	return me.send(func(i int) error {
+		// The next exporters tried must get the data unmodified.
+		data := md
+		if consumer.GetCapabilities(me.exporters[i]).MutatesConsumedData {
+			data = multiconsumer.CloneMetricsData(md)
+		}
+		return me.exporters[i].ConsumeMetricsData(ctx, data)
+	})
+}
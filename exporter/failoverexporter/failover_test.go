@@ -0,0 +1,163 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failoverexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+// testExporter counts the data received and fails when told to.
+type testExporter struct {
+	received int
+	fail     bool
+}
+
+func (te *testExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if te.fail {
+		return errors.New("backend unavailable")
+	}
+	te.received++
+	return nil
+}
+
+func (te *testExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if te.fail {
+		return errors.New("backend unavailable")
+	}
+	te.received++
+	return nil
+}
+
+func newTestFailover(t *testing.T) (*failover, *time.Time) {
+	fo, err := newFailover(zap.NewNop(), Config{
+		Exporters:            []string{"primary", "secondary"},
+		MaxConsecutiveErrors: 2,
+		Probation:            time.Minute,
+	})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	fo.now = func() time.Time { return now }
+	return fo, &now
+}
+
+func TestTraceExporterFailover(t *testing.T) {
+	fo, now := newTestFailover(t)
+	primary, secondary := &testExporter{}, &testExporter{}
+	te := &traceExporter{failover: fo}
+	te.SetTraceExporters([]consumer.TraceConsumer{primary, secondary})
+	send := func() error {
+		return te.ConsumeTraceData(context.Background(), consumerdata.TraceData{})
+	}
+
+	require.NoError(t, send())
+	assert.Equal(t, 1, primary.received)
+
+	// The data is sent to the secondary when the primary fails, the primary
+	// is tried until it fails max-consecutive-errors times.
+	primary.fail = true
+	require.NoError(t, send())
+	require.NoError(t, send())
+	assert.Equal(t, 2, secondary.received)
+	primary.fail = false
+	require.NoError(t, send())
+	assert.Equal(t, 1, primary.received)
+	assert.Equal(t, 3, secondary.received)
+
+	// The primary is tried again after the probation.
+	*now = now.Add(time.Minute)
+	require.NoError(t, send())
+	assert.Equal(t, 2, primary.received)
+	assert.Equal(t, 3, secondary.received)
+}
+
+func TestTraceExporterProbationFailure(t *testing.T) {
+	fo, now := newTestFailover(t)
+	primary, secondary := &testExporter{fail: true}, &testExporter{}
+	te := &traceExporter{failover: fo}
+	te.SetTraceExporters([]consumer.TraceConsumer{primary, secondary})
+	send := func() error {
+		return te.ConsumeTraceData(context.Background(), consumerdata.TraceData{})
+	}
+
+	require.NoError(t, send())
+	require.NoError(t, send())
+	// A single error at the end of the probation starts a new one.
+	*now = now.Add(time.Minute)
+	require.NoError(t, send())
+	*now = now.Add(time.Second)
+	primary.fail = false
+	require.NoError(t, send())
+	assert.Equal(t, 0, primary.received)
+	assert.Equal(t, 4, secondary.received)
+}
+
+func TestMetricsExporterAllFailing(t *testing.T) {
+	fo, _ := newTestFailover(t)
+	primary, secondary := &testExporter{fail: true}, &testExporter{fail: true}
+	me := &metricsExporter{failover: fo}
+	me.SetMetricsExporters([]consumer.MetricsConsumer{primary, secondary})
+	send := func() error {
+		return me.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{})
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Error(t, send())
+	}
+	// Both are unhealthy, both are still tried rather than dropping the data.
+	secondary.fail = false
+	require.NoError(t, send())
+	assert.Equal(t, 1, secondary.received)
+}
+
+// renamingExporter renames the spans it receives before failing.
+type renamingExporter struct {
+	testExporter
+}
+
+func (re *renamingExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	for _, span := range td.Spans {
+		span.Name = &tracepb.TruncatableString{Value: "renamed"}
+	}
+	return re.testExporter.ConsumeTraceData(ctx, td)
+}
+
+func TestTraceExporterMutatingExporter(t *testing.T) {
+	fo, _ := newTestFailover(t)
+	primary := &renamingExporter{testExporter{fail: true}}
+	secondary := &exportertest.SinkTraceExporter{}
+	te := &traceExporter{failover: fo}
+	te.SetTraceExporters([]consumer.TraceConsumer{primary, secondary})
+
+	// The primary may modify the data, the secondary gets a copy of it
+	// unmodified.
+	td := consumerdata.TraceData{Spans: []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}}}}
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	assert.Equal(t, "span", td.Spans[0].Name.Value)
+	received := secondary.AllTraces()
+	require.Len(t, received, 1)
+	assert.Equal(t, "span", received[0].Spans[0].Name.Value)
+}
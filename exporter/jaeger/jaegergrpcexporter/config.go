@@ -15,11 +15,38 @@
 package jaegergrpcexporter
 
 import (
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 // Config defines configuration for Jaeger gRPC exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
-	Endpoint                      string                   `mapstructure:"endpoint"`
+
+	// GRPCClientSettings configures the connection to the collector: its
+	// endpoint, TLS, keepalive and whether the requests wait for it to be
+	// ready.
+	configgrpc.GRPCClientSettings `mapstructure:",squash"`
+
+	// TimeoutSettings bounds every attempt to send a batch to the collector.
+	exporterhelper.TimeoutSettings `mapstructure:",squash"`
+
+	// QueueSettings configures the queue used to send the requests asynchronously.
+	QueueSettings exporterhelper.QueueSettings `mapstructure:"sending-queue"`
+
+	// RetrySettings configures how failed requests are retried.
+	RetrySettings exporterhelper.RetrySettings `mapstructure:"retry-on-failure"`
+
+	// SplitSettings configures the maximum size of the requests, bigger
+	// batches are split into several requests.
+	SplitSettings exporterhelper.SplitSettings `mapstructure:"split-batch"`
+}
+
+var _ exporter.QueuedConfig = (*Config)(nil)
+
+// QueueEnabled returns whether the requests are queued.
+func (cfg *Config) QueueEnabled() bool {
+	return cfg.QueueSettings.Enabled
 }
@@ -17,12 +17,16 @@ package jaegergrpcexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -48,6 +52,34 @@ func TestLoadConfig(t *testing.T) {
 	e1 := cfg.Exporters["jaeger-grpc/2"]
 	assert.Equal(t, "jaeger-grpc/2", e1.(*Config).Name())
 	assert.Equal(t, "a.new.target:1234", e1.(*Config).Endpoint)
+	assert.Equal(t, 10*time.Second, e1.(*Config).Timeout)
+	assert.Equal(t, "zstd", e1.(*Config).Compression)
+	assert.True(t, e1.(*Config).WaitForReady)
+	assert.Equal(t, []string{"x-tenant"}, e1.(*Config).ForwardHeaders)
+	assert.Equal(t,
+		&configgrpc.KeepaliveClientConfig{Time: time.Minute, Timeout: 10 * time.Second},
+		e1.(*Config).Keepalive)
+	assert.Equal(t,
+		exporterhelper.QueueSettings{
+			Enabled:    true,
+			NumWorkers: 2,
+			QueueSize:  10,
+		},
+		e1.(*Config).QueueSettings)
+	wantRetry := exporterhelper.CreateDefaultRetrySettings()
+	wantRetry.Enabled = false
+	assert.Equal(t, wantRetry, e1.(*Config).RetrySettings)
 	_, _, err = factory.CreateTraceExporter(zap.NewNop(), e1)
 	require.NoError(t, err)
+
+	e2 := cfg.Exporters["jaeger-grpc/tls"].(*Config)
+	assert.Equal(t,
+		&configtls.TLSClientSetting{
+			TLSSetting:         configtls.TLSSetting{CAFile: "/var/lib/ca.pem"},
+			ServerNameOverride: "collector.example.com",
+		},
+		e2.TLSSetting)
+	// The CA file doesn't exist.
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), e2)
+	require.Error(t, err)
 }
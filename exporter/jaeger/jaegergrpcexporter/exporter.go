@@ -20,6 +20,8 @@ import (
 	jaegerproto "github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"google.golang.org/grpc"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
@@ -29,9 +31,20 @@ import (
 
 // New returns a new Jaeger gRPC exporter.
 // The exporter name is the name to be used in the observability of the exporter.
-// The collectorEndpoint should be of the form "hostname:14250" (a gRPC target).
-func New(exporterName, collectorEndpoint string) (exporter.TraceExporter, error) {
-	client, err := grpc.Dial(collectorEndpoint, grpc.WithInsecure())
+// The settings configure the connection to the collector, its endpoint should
+// be of the form "hostname:14250" (a gRPC target), or "unix:///path/to/socket"
+// for a Unix domain socket.
+// The options are passed to exporterhelper, e.g. to enable queueing and retries.
+func New(
+	exporterName string,
+	settings configgrpc.GRPCClientSettings,
+	options ...exporterhelper.ExporterOption,
+) (exporter.TraceExporter, error) {
+	target, dialOpts, err := settings.ToDialOptions()
+	if err != nil {
+		return nil, err
+	}
+	client, err := grpc.Dial(target, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +54,16 @@ func New(exporterName, collectorEndpoint string) (exporter.TraceExporter, error)
 		client: collectorServiceClient,
 	}
 
+	opts := []exporterhelper.ExporterOption{
+		exporterhelper.WithSpanName("otelsvc.exporter." + exporterName + ".ConsumeTraceData"),
+		exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
+		exporterhelper.WithShutdown(client.Close),
+	}
 	exp, err := exporterhelper.NewTraceExporter(
 		exporterName,
 		s.pushTraceData,
-		exporterhelper.WithSpanName("otelsvc.exporter."+exporterName+".ConsumeTraceData"),
-		exporterhelper.WithRecordMetrics(true))
+		append(opts, options...)...)
 
 	return exp, err
 }
@@ -67,12 +85,12 @@ func (s *protoGRPCSender) pushTraceData(
 	}
 
 	_, err = s.client.PostSpans(
-		context.Background(),
+		ctx,
 		&jaegerproto.PostSpansRequest{Batch: *protoBatch})
 
 	if err != nil {
 		droppedSpans = len(protoBatch.Spans)
 	}
 
-	return droppedSpans, err
+	return droppedSpans, consumererror.FromGRPC(err)
 }
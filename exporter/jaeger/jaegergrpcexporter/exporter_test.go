@@ -16,17 +16,28 @@ package jaegergrpcexporter
 
 import (
 	"context"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
+	jaegerproto "github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 func TestNew(t *testing.T) {
 	type args struct {
-		exporterName      string
-		collectorEndpoint string
+		exporterName string
+		settings     configgrpc.GRPCClientSettings
 	}
 	tests := []struct {
 		name    string
@@ -36,21 +47,44 @@ func TestNew(t *testing.T) {
 		{
 			name: "empty_exporterName",
 			args: args{
-				collectorEndpoint: "127.0.0.1:55678",
+				settings: configgrpc.GRPCClientSettings{Endpoint: "127.0.0.1:55678"},
 			},
 			wantErr: true,
 		},
 		{
 			name: "createExporter",
 			args: args{
-				exporterName:      typeStr,
-				collectorEndpoint: "some.non.existent:55678",
+				exporterName: typeStr,
+				settings:     configgrpc.GRPCClientSettings{Endpoint: "some.non.existent:55678"},
 			},
 		},
+		{
+			name: "createSecureExporter",
+			args: args{
+				exporterName: typeStr,
+				settings: configgrpc.GRPCClientSettings{
+					Endpoint:   "some.non.existent:55678",
+					TLSSetting: &configtls.TLSClientSetting{ServerNameOverride: "some.non.existent"},
+				},
+			},
+		},
+		{
+			name: "invalidTLS",
+			args: args{
+				exporterName: typeStr,
+				settings: configgrpc.GRPCClientSettings{
+					Endpoint: "some.non.existent:55678",
+					TLSSetting: &configtls.TLSClientSetting{
+						TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := New(tt.args.exporterName, tt.args.collectorEndpoint)
+			got, err := New(tt.args.exporterName, tt.args.settings)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -65,3 +99,59 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+// flakyCollector is a Jaeger collector failing the first requests it
+// receives with the given errors.
+type flakyCollector struct {
+	mu       sync.Mutex
+	errs     []error
+	requests int
+}
+
+var _ jaegerproto.CollectorServiceServer = (*flakyCollector)(nil)
+
+func (fc *flakyCollector) PostSpans(ctx context.Context, r *jaegerproto.PostSpansRequest) (*jaegerproto.PostSpansResponse, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.requests++
+	if len(fc.errs) > 0 {
+		err := fc.errs[0]
+		fc.errs = fc.errs[1:]
+		return nil, err
+	}
+	return &jaegerproto.PostSpansResponse{}, nil
+}
+
+func TestRetryOnTransientErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	collector := &flakyCollector{errs: []error{
+		status.Error(codes.Unavailable, "collector restarting"),
+		status.Error(codes.DeadlineExceeded, "collector overloaded"),
+	}}
+	srv := grpc.NewServer()
+	jaegerproto.RegisterCollectorServiceServer(srv, collector)
+	go srv.Serve(ln)
+	defer srv.Stop()
+
+	retry := exporterhelper.CreateDefaultRetrySettings()
+	retry.Enabled = true
+	retry.InitialInterval = 10 * time.Millisecond
+	exp, err := New(
+		typeStr,
+		configgrpc.GRPCClientSettings{Endpoint: ln.Addr().String(), WaitForReady: true},
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 5 * time.Second}),
+		exporterhelper.WithRetry(retry))
+	require.NoError(t, err)
+	defer exp.Shutdown()
+
+	require.NoError(t, exp.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.Equal(t, 3, collector.requests)
+
+	// The errors that can't be fixed by retrying are returned right away.
+	collector.mu.Lock()
+	collector.errs = []error{status.Error(codes.InvalidArgument, "invalid batch")}
+	collector.mu.Unlock()
+	assert.Error(t, exp.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+	assert.Equal(t, 4, collector.requests)
+}
@@ -19,10 +19,12 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 const (
@@ -32,6 +34,9 @@ const (
 
 // Factory is the factory for Jaeger gRPC exporter.
 type Factory struct {
+	// Holder gives the factory the build of the service, sent as the user
+	// agent of the requests.
+	buildinfo.Holder
 }
 
 // Type gets the type of the Exporter config created by this factory.
@@ -46,6 +51,9 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		TimeoutSettings: exporterhelper.CreateDefaultTimeoutSettings(),
+		QueueSettings:   exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings:   exporterhelper.CreateDefaultRetrySettings(),
 	}
 }
 
@@ -64,12 +72,20 @@ func (f *Factory) CreateTraceExporter(
 		return nil, nil, err
 	}
 
-	exp, err := New(expCfg.Name(), expCfg.Endpoint)
+	settings := expCfg.GRPCClientSettings
+	settings.UserAgent = f.BuildInfo().UserAgent()
+	exp, err := New(
+		expCfg.Name(),
+		settings,
+		exporterhelper.WithTimeout(expCfg.TimeoutSettings),
+		exporterhelper.WithSplit(expCfg.SplitSettings),
+		exporterhelper.WithQueue(expCfg.QueueSettings),
+		exporterhelper.WithRetry(expCfg.RetrySettings))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return exp, nil, nil
+	return exp, exp.Shutdown, nil
 }
 
 // CreateMetricsExporter creates a metrics exporter based on this config.
@@ -59,7 +59,6 @@ func TestCreateInstanceViaFactory(t *testing.T) {
 		cfg)
 	assert.NoError(t, err)
 	assert.NotNil(t, exp)
-
-	// Currently this exporter doesn't have a stop function.
-	assert.Nil(t, expStopFn)
+	assert.NotNil(t, expStopFn)
+	assert.NoError(t, expStopFn())
 }
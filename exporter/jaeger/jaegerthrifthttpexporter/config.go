@@ -18,6 +18,8 @@ import (
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 // Config defines configuration for Jaeger Thrift over HTTP exporter.
@@ -35,4 +37,28 @@ type Config struct {
 	// Headers are a set of headers to be added to the HTTP request sending
 	// trace data.
 	Headers map[string]string `mapstructure:"headers"`
+
+	// ForwardHeaders are the metadata keys, e.g. the gRPC or HTTP headers,
+	// received with the trace data that are added to the HTTP request sending
+	// it.
+	ForwardHeaders []string `mapstructure:"forward-headers"`
+
+	// QueueSettings configures the queue used to send the requests
+	// asynchronously, each of its workers reuses its own connection to the
+	// collector.
+	QueueSettings exporterhelper.QueueSettings `mapstructure:"sending-queue"`
+
+	// RetrySettings configures how failed requests are retried.
+	RetrySettings exporterhelper.RetrySettings `mapstructure:"retry-on-failure"`
+
+	// SplitSettings configures the maximum size of the requests, bigger
+	// batches are split into several requests.
+	SplitSettings exporterhelper.SplitSettings `mapstructure:"split-batch"`
+}
+
+var _ exporter.QueuedConfig = (*Config)(nil)
+
+// QueueEnabled returns whether the requests are queued.
+func (cfg *Config) QueueEnabled() bool {
+	return cfg.QueueSettings.Enabled
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -60,7 +61,14 @@ func TestLoadConfig(t *testing.T) {
 			"added-entry": "added value",
 			"dot.test":    "test",
 		},
-		Timeout: 2 * time.Second,
+		ForwardHeaders: []string{"x-tenant"},
+		Timeout:        2 * time.Second,
+		QueueSettings: exporterhelper.QueueSettings{
+			Enabled:    true,
+			NumWorkers: 4,
+			QueueSize:  5000,
+		},
+		RetrySettings: exporterhelper.CreateDefaultRetrySettings(),
 	}
 	assert.Equal(t, &expectedCfg, e1)
 
@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
@@ -41,30 +44,64 @@ const defaultHTTPTimeout = time.Second * 5
 // typically something like: http://hostname:14268/api/traces.
 // The headers parameter is used to add entries to the POST message set to the
 // collector.
+// The forwardHeaders are the metadata keys received with the trace data that
+// are also added to the POST message, see client.ForwardedMetadata.
 // The timeout is used to set the timeout for the HTTP requests, if the
 // value is equal or smaller than zero the defaulf of 5 seconds is used.
+// The maxIdleConns is the number of connections to the collector kept open
+// between requests, it should be the number of workers sending them so that
+// each worker reuses a connection. If zero the default of net/http is used.
+// The options are passed to exporterhelper, e.g. to enable queueing and retries.
 func New(
 	exporterName string,
 	httpAddress string,
 	headers map[string]string,
+	forwardHeaders []string,
 	timeout time.Duration,
+	maxIdleConns int,
+	options ...exporterhelper.ExporterOption,
 ) (exporter.TraceExporter, error) {
 
 	clientTimeout := defaultHTTPTimeout
 	if timeout != 0 {
 		clientTimeout = timeout
 	}
+	// The settings of http.DefaultTransport, except the idle connections.
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   maxIdleConns,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if maxIdleConns > transport.MaxIdleConns {
+		transport.MaxIdleConns = maxIdleConns
+	}
 	s := &jaegerThriftHTTPSender{
-		url:     httpAddress,
-		headers: headers,
-		client:  &http.Client{Timeout: clientTimeout},
+		url:            httpAddress,
+		headers:        headers,
+		forwardHeaders: forwardHeaders,
+		client:         &http.Client{Timeout: clientTimeout, Transport: transport},
 	}
 
+	opts := []exporterhelper.ExporterOption{
+		exporterhelper.WithSpanName("otelsvc.exporter." + exporterName + ".ConsumeTraceData"),
+		exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
+		exporterhelper.WithShutdown(func() error {
+			transport.CloseIdleConnections()
+			return nil
+		}),
+	}
 	exp, err := exporterhelper.NewTraceExporter(
 		exporterName,
 		s.pushTraceData,
-		exporterhelper.WithSpanName("otelsvc.exporter."+exporterName+".ConsumeTraceData"),
-		exporterhelper.WithRecordMetrics(true))
+		append(opts, options...)...)
 
 	return exp, err
 }
@@ -72,9 +109,10 @@ func New(
 // jaegerThriftHTTPSender forwards spans encoded in the jaeger thrift
 // format to a http server.
 type jaegerThriftHTTPSender struct {
-	url     string
-	headers map[string]string
-	client  *http.Client
+	url            string
+	headers        map[string]string
+	forwardHeaders []string
+	client         *http.Client
 }
 
 func (s *jaegerThriftHTTPSender) pushTraceData(
@@ -96,6 +134,7 @@ func (s *jaegerThriftHTTPSender) pushTraceData(
 	if err != nil {
 		return len(td.Spans), err
 	}
+	req = req.WithContext(ctx)
 
 	req.Header.Set("Content-Type", "application/x-thrift")
 	if s.headers != nil {
@@ -103,6 +142,11 @@ func (s *jaegerThriftHTTPSender) pushTraceData(
 			req.Header.Set(k, v)
 		}
 	}
+	for k, values := range client.ForwardedMetadata(ctx, s.forwardHeaders) {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -117,7 +161,7 @@ func (s *jaegerThriftHTTPSender) pushTraceData(
 			"HTTP %d %q",
 			resp.StatusCode,
 			http.StatusText(resp.StatusCode))
-		return len(td.Spans), err
+		return len(td.Spans), consumererror.FromHTTPStatus(err, resp)
 	}
 
 	return 0, nil
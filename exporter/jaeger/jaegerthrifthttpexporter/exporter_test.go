@@ -16,11 +16,15 @@ package jaegerthrifthttpexporter
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/open-telemetry/opentelemetry-service/client"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 )
 
@@ -57,7 +61,7 @@ func TestNew(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := New(tt.args.exporterName, tt.args.httpAddress, tt.args.headers, tt.args.timeout)
+			got, err := New(tt.args.exporterName, tt.args.httpAddress, tt.args.headers, nil, tt.args.timeout, 0)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -73,3 +77,29 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardHeaders(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	exp, err := New(typeStr, srv.URL, map[string]string{"X-Static": "value"}, []string{"X-Tenant", "Authorization"}, time.Second, 0)
+	require.NoError(t, err)
+
+	ctx := client.NewContext(context.Background(), &client.Client{
+		Metadata: map[string][]string{
+			"x-tenant":   {"acme"},
+			"user-agent": {"test"},
+		},
+	})
+	require.NoError(t, exp.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+
+	got := <-headers
+	assert.Equal(t, "acme", got.Get("X-Tenant"))
+	assert.Equal(t, "value", got.Get("X-Static"))
+	assert.Empty(t, got.Get("Authorization"))
+	assert.NotEqual(t, "test", got.Get("User-Agent"))
+}
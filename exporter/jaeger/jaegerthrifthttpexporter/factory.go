@@ -16,14 +16,17 @@ package jaegerthrifthttpexporter
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 const (
@@ -33,6 +36,9 @@ const (
 
 // Factory is the factory for Jaeger Thrift over HTTP exporter.
 type Factory struct {
+	// Holder gives the factory the build of the service, sent as the user
+	// agent of the requests.
+	buildinfo.Holder
 }
 
 // Type gets the type of the Exporter config created by this factory.
@@ -47,7 +53,9 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Timeout: defaultHTTPTimeout,
+		Timeout:       defaultHTTPTimeout,
+		QueueSettings: exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings: exporterhelper.CreateDefaultRetrySettings(),
 	}
 }
 
@@ -75,16 +83,34 @@ func (f *Factory) CreateTraceExporter(
 		return nil, nil, err
 	}
 
+	// Without a queue the requests are sent by the goroutines of the
+	// pipelines, keep the default number of idle connections.
+	var maxIdleConns int
+	if expCfg.QueueSettings.Enabled {
+		maxIdleConns = expCfg.QueueSettings.NumWorkers
+	}
+
+	// The user agent is the build of the service unless it is configured.
+	headers := map[string]string{"User-Agent": f.BuildInfo().UserAgent()}
+	for k, v := range expCfg.Headers {
+		headers[http.CanonicalHeaderKey(k)] = v
+	}
+
 	exp, err := New(
 		expCfg.Name(),
 		expCfg.URL,
-		expCfg.Headers,
-		expCfg.Timeout)
+		headers,
+		expCfg.ForwardHeaders,
+		expCfg.Timeout,
+		maxIdleConns,
+		exporterhelper.WithSplit(expCfg.SplitSettings),
+		exporterhelper.WithQueue(expCfg.QueueSettings),
+		exporterhelper.WithRetry(expCfg.RetrySettings))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return exp, nil, nil
+	return exp, exp.Shutdown, nil
 }
 
 // CreateMetricsExporter creates a metrics exporter based on this config.
@@ -62,8 +62,9 @@ func TestCreateInstanceViaFactory(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, exp)
 
-	// Currently this exporter doesn't have a stop function.
-	assert.Nil(t, expStopFn)
+	// The stop function stops the sending queue.
+	assert.NotNil(t, expStopFn)
+	assert.NoError(t, expStopFn())
 }
 
 func TestFactory_CreateTraceExporter(t *testing.T) {
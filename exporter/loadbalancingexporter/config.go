@@ -0,0 +1,60 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter/opencensusexporter"
+)
+
+// Config defines configuration for the load balancing exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// OpenCensus configures the exporters to the backends, the endpoint is
+	// set to the one of each backend.
+	OpenCensus opencensusexporter.Config `mapstructure:"opencensus"`
+
+	// Resolver finds the endpoints of the backends, exactly one of its
+	// settings must be set.
+	Resolver ResolverSettings `mapstructure:"resolver"`
+}
+
+// ResolverSettings configures how the endpoints of the backends are found.
+type ResolverSettings struct {
+	// Static is a fixed list of endpoints.
+	Static *StaticResolver `mapstructure:"static"`
+	// DNS resolves the endpoints from a hostname, periodically.
+	DNS *DNSResolver `mapstructure:"dns"`
+}
+
+// StaticResolver is a fixed list of endpoints.
+type StaticResolver struct {
+	// Endpoints are the gRPC targets of the backends, e.g. "collector-1:55678".
+	Endpoints []string `mapstructure:"endpoints"`
+}
+
+// DNSResolver resolves the endpoints from the IP addresses of a hostname,
+// e.g. of a Kubernetes headless service.
+type DNSResolver struct {
+	// Hostname is the hostname resolved.
+	Hostname string `mapstructure:"hostname"`
+	// Port is the port of the backends, 55678 by default.
+	Port string `mapstructure:"port"`
+	// Interval is the time between two resolutions, 30s by default.
+	Interval time.Duration `mapstructure:"interval"`
+}
@@ -0,0 +1,58 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	exporters[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["loadbalancing"]
+	assert.Equal(t, factory.CreateDefaultConfig(), e0)
+
+	e1 := cfg.Exporters["loadbalancing/static"].(*Config)
+	assert.Equal(t, "gzip", e1.OpenCensus.Compression)
+	assert.Equal(t, &StaticResolver{Endpoints: []string{"collector-1:55678", "collector-2:55678"}}, e1.Resolver.Static)
+	assert.Nil(t, e1.Resolver.DNS)
+
+	e2 := cfg.Exporters["loadbalancing/dns"].(*Config)
+	assert.Nil(t, e2.Resolver.Static)
+	assert.Equal(t,
+		&DNSResolver{
+			Hostname: "collectors.observability.svc.cluster.local",
+			Port:     "55680",
+			Interval: 10 * time.Second,
+		},
+		e2.Resolver.DNS)
+}
@@ -0,0 +1,95 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/opencensusexporter"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "loadbalancing"
+)
+
+// Factory is the factory for the load balancing exporter.
+type Factory struct {
+}
+
+// Type gets the type of the Exporter config created by this factory.
+func (f *Factory) Type() string {
+	return typeStr
+}
+
+// CreateDefaultConfig creates the default configuration for exporter.
+func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
+	ocFactory := &opencensusexporter.Factory{}
+	return &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		OpenCensus: *ocFactory.CreateDefaultConfig().(*opencensusexporter.Config),
+	}
+}
+
+// CreateTraceExporter creates a trace exporter based on this config.
+func (f *Factory) CreateTraceExporter(logger *zap.Logger, config configmodels.Exporter) (consumer.TraceConsumer, exporter.StopFunc, error) {
+	lbc := config.(*Config)
+	res, err := newResolver(logger, lbc.Resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ocFactory := &opencensusexporter.Factory{}
+	newBackend := func(endpoint string) (consumer.TraceConsumer, exporter.StopFunc, error) {
+		ocCfg := lbc.OpenCensus
+		ocCfg.Endpoint = endpoint
+		return ocFactory.CreateTraceExporter(logger, &ocCfg)
+	}
+
+	lb := newTraceExporter(logger, res, newBackend)
+	return lb, lb.stop, nil
+}
+
+// CreateMetricsExporter creates a metrics exporter based on this config.
+func (f *Factory) CreateMetricsExporter(logger *zap.Logger, config configmodels.Exporter) (consumer.MetricsConsumer, exporter.StopFunc, error) {
+	return nil, nil, configerror.ErrDataTypeIsNotSupported
+}
+
+func newResolver(logger *zap.Logger, cfg ResolverSettings) (resolver, error) {
+	switch {
+	case cfg.Static != nil && cfg.DNS != nil:
+		return nil, errors.New("only one of the static and dns resolvers can be set")
+	case cfg.Static != nil:
+		if len(cfg.Static.Endpoints) == 0 {
+			return nil, errors.New("the static resolver requires endpoints")
+		}
+		return &staticResolver{endpoints: cfg.Static.Endpoints}, nil
+	case cfg.DNS != nil:
+		if cfg.DNS.Hostname == "" {
+			return nil, errors.New("the dns resolver requires a hostname")
+		}
+		return newDNSResolver(logger, *cfg.DNS), nil
+	}
+	return nil, errors.New("a static or dns resolver must be set")
+}
@@ -0,0 +1,92 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateMetricsExporter(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+
+	_, _, err := factory.CreateMetricsExporter(zap.NewNop(), cfg)
+	assert.Equal(t, configerror.ErrDataTypeIsNotSupported, err)
+}
+
+func TestCreateTraceExporter(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	// A resolver is required.
+	_, _, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+
+	cfg.Resolver.Static = &StaticResolver{Endpoints: []string{testutils.GetAvailableLocalAddress(t)}}
+	exp, stop, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, exp)
+	assert.NotNil(t, stop)
+	assert.Len(t, exp.(*traceExporter).backends, 1)
+	// The backend isn't running, only check that the exporter stops.
+	_ = stop()
+	assert.Len(t, exp.(*traceExporter).backends, 0)
+}
+
+func TestNewResolver(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      ResolverSettings
+		mustFail bool
+	}{
+		{name: "none", mustFail: true},
+		{
+			name: "both",
+			cfg: ResolverSettings{
+				Static: &StaticResolver{Endpoints: []string{"collector:55678"}},
+				DNS:    &DNSResolver{Hostname: "collectors"},
+			},
+			mustFail: true,
+		},
+		{name: "static without endpoints", cfg: ResolverSettings{Static: &StaticResolver{}}, mustFail: true},
+		{name: "dns without hostname", cfg: ResolverSettings{DNS: &DNSResolver{}}, mustFail: true},
+		{name: "static", cfg: ResolverSettings{Static: &StaticResolver{Endpoints: []string{"collector:55678"}}}},
+		{name: "dns", cfg: ResolverSettings{DNS: &DNSResolver{Hostname: "collectors"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := newResolver(zap.NewNop(), tt.cfg)
+			if tt.mustFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, res)
+		})
+	}
+}
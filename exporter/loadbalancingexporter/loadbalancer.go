@@ -0,0 +1,211 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loadbalancingexporter implements an exporter that sends the spans
+// of each trace to the same backend, among a set of backends found by a
+// resolver, so that the backends e.g. sample whole traces.
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/oterr"
+)
+
+// virtualNodes is the number of points of each endpoint on the hash ring,
+// for the traces to be evenly distributed.
+const virtualNodes = 100
+
+var errNoBackends = errors.New("no backends to send the traces to")
+
+// hashRing assigns keys to endpoints by consistent hashing: when an endpoint
+// is added or removed only the keys of that endpoint move.
+type hashRing struct {
+	hashes    []uint32
+	endpoints map[uint32]string
+}
+
+func newHashRing(endpoints []string) *hashRing {
+	r := &hashRing{endpoints: make(map[uint32]string, len(endpoints)*virtualNodes)}
+	for _, endpoint := range endpoints {
+		for i := 0; i < virtualNodes; i++ {
+			hash := crc32.ChecksumIEEE([]byte(endpoint + "-" + strconv.Itoa(i)))
+			if _, ok := r.endpoints[hash]; ok {
+				// On a collision the smallest endpoint keeps the point,
+				// whatever the order of the endpoints.
+				if r.endpoints[hash] < endpoint {
+					continue
+				}
+			} else {
+				r.hashes = append(r.hashes, hash)
+			}
+			r.endpoints[hash] = endpoint
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// endpoint returns the endpoint of the key, the one of the first point
+// following the hash of the key on the ring.
+func (r *hashRing) endpoint(key []byte) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := crc32.ChecksumIEEE(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.endpoints[r.hashes[i]]
+}
+
+// backend is the exporter to one endpoint.
+type backend struct {
+	tc   consumer.TraceConsumer
+	stop exporter.StopFunc
+}
+
+// newBackendFunc creates the exporter to an endpoint.
+type newBackendFunc func(endpoint string) (consumer.TraceConsumer, exporter.StopFunc, error)
+
+type traceExporter struct {
+	logger     *zap.Logger
+	resolver   resolver
+	newBackend newBackendFunc
+
+	mu       sync.RWMutex
+	ring     *hashRing
+	backends map[string]*backend
+}
+
+var _ consumer.TraceConsumer = (*traceExporter)(nil)
+
+// newTraceExporter returns an exporter sending the spans of each trace to the
+// backend of its trace ID, among the endpoints found by the resolver. The
+// backends are created, and stopped, as the endpoints change.
+func newTraceExporter(logger *zap.Logger, res resolver, newBackend newBackendFunc) *traceExporter {
+	lb := &traceExporter{
+		logger:     logger,
+		resolver:   res,
+		newBackend: newBackend,
+		ring:       newHashRing(nil),
+		backends:   make(map[string]*backend),
+	}
+	res.start(lb.onMembershipChange)
+	return lb
+}
+
+// onMembershipChange creates the backends of the new endpoints and stops the
+// ones of the endpoints removed, then rebalances the traces on the ring.
+func (lb *traceExporter) onMembershipChange(endpoints []string) {
+	lb.mu.Lock()
+	backends := make(map[string]*backend, len(endpoints))
+	var ringEndpoints []string
+	for _, endpoint := range endpoints {
+		if b, ok := lb.backends[endpoint]; ok {
+			backends[endpoint] = b
+			ringEndpoints = append(ringEndpoints, endpoint)
+			continue
+		}
+		tc, stop, err := lb.newBackend(endpoint)
+		if err != nil {
+			lb.logger.Error("Failed to create the exporter to a backend, its traces go to the others",
+				zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		backends[endpoint] = &backend{tc: tc, stop: stop}
+		ringEndpoints = append(ringEndpoints, endpoint)
+	}
+
+	var removed []*backend
+	for endpoint, b := range lb.backends {
+		if _, ok := backends[endpoint]; !ok {
+			removed = append(removed, b)
+		}
+	}
+	lb.backends = backends
+	lb.ring = newHashRing(ringEndpoints)
+	lb.mu.Unlock()
+
+	lb.logger.Info("Backends changed", zap.Strings("endpoints", ringEndpoints))
+	for _, b := range removed {
+		if err := b.stop(); err != nil {
+			lb.logger.Warn("Failed to stop the exporter to a removed backend", zap.Error(err))
+		}
+	}
+}
+
+// ConsumeTraceData splits the spans by backend and sends each backend its
+// spans, with the node and resource of the batch.
+func (lb *traceExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	if len(lb.backends) == 0 {
+		return errNoBackends
+	}
+
+	spansByEndpoint := make(map[string][]*tracepb.Span)
+	for _, span := range td.Spans {
+		if span == nil {
+			continue
+		}
+		endpoint := lb.ring.endpoint(span.TraceId)
+		spansByEndpoint[endpoint] = append(spansByEndpoint[endpoint], span)
+	}
+
+	var errs []error
+	for endpoint, spans := range spansByEndpoint {
+		err := lb.backends[endpoint].tc.ConsumeTraceData(ctx, consumerdata.TraceData{
+			Node:         td.Node,
+			Resource:     td.Resource,
+			Spans:        spans,
+			SourceFormat: td.SourceFormat,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send the traces to %q: %v", endpoint, err))
+		}
+	}
+	return oterr.CombineErrors(errs)
+}
+
+// stop stops the resolver, then the exporters of the backends.
+func (lb *traceExporter) stop() error {
+	lb.resolver.stop()
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	var errs []error
+	for _, b := range lb.backends {
+		if err := b.stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	lb.backends = make(map[string]*backend)
+	lb.ring = newHashRing(nil)
+	return oterr.CombineErrors(errs)
+}
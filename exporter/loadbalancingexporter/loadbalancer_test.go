@@ -0,0 +1,160 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func traceID(i int) []byte {
+	return []byte(fmt.Sprintf("trace-%010d", i))
+}
+
+func TestHashRing(t *testing.T) {
+	assert.Equal(t, "", newHashRing(nil).endpoint(traceID(0)))
+
+	endpoints := []string{"a:55678", "b:55678", "c:55678"}
+	ring := newHashRing(endpoints)
+	// The order of the endpoints doesn't matter.
+	reversed := newHashRing([]string{"c:55678", "b:55678", "a:55678"})
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		endpoint := ring.endpoint(traceID(i))
+		assert.Equal(t, endpoint, reversed.endpoint(traceID(i)))
+		counts[endpoint]++
+	}
+	for _, endpoint := range endpoints {
+		// Roughly a third of the traces each.
+		assert.InDelta(t, 1000, counts[endpoint], 400, endpoint)
+	}
+
+	// Only the traces of the endpoint removed move.
+	smaller := newHashRing([]string{"a:55678", "b:55678"})
+	for i := 0; i < 3000; i++ {
+		if endpoint := ring.endpoint(traceID(i)); endpoint != "c:55678" {
+			assert.Equal(t, endpoint, smaller.endpoint(traceID(i)))
+		}
+	}
+}
+
+// testResolver lets the tests change the endpoints.
+type testResolver struct {
+	endpoints []string
+	onChange  func(endpoints []string)
+	stopped   bool
+}
+
+func (r *testResolver) start(onChange func(endpoints []string)) {
+	r.onChange = onChange
+	onChange(r.endpoints)
+}
+
+func (r *testResolver) stop() {
+	r.stopped = true
+}
+
+// testBackends creates a sink per endpoint and records the stopped ones.
+type testBackends struct {
+	sinks   map[string]*exportertest.SinkTraceExporter
+	stopped map[string]bool
+}
+
+func newTestBackends() *testBackends {
+	return &testBackends{
+		sinks:   make(map[string]*exportertest.SinkTraceExporter),
+		stopped: make(map[string]bool),
+	}
+}
+
+func (tb *testBackends) newBackend(endpoint string) (consumer.TraceConsumer, exporter.StopFunc, error) {
+	if endpoint == "invalid" {
+		return nil, nil, errors.New("invalid endpoint")
+	}
+	sink := &exportertest.SinkTraceExporter{}
+	tb.sinks[endpoint] = sink
+	return sink, func() error {
+		tb.stopped[endpoint] = true
+		return nil
+	}, nil
+}
+
+// traceIDs returns the trace IDs of the spans received by each endpoint.
+func (tb *testBackends) traceIDs() map[string]map[string]bool {
+	result := make(map[string]map[string]bool)
+	for endpoint, sink := range tb.sinks {
+		result[endpoint] = make(map[string]bool)
+		for _, td := range sink.AllTraces() {
+			for _, span := range td.Spans {
+				result[endpoint][string(span.TraceId)] = true
+			}
+		}
+	}
+	return result
+}
+
+func newTestTraceData(traces int) consumerdata.TraceData {
+	td := consumerdata.TraceData{SourceFormat: "test"}
+	for i := 0; i < traces; i++ {
+		// Two spans per trace.
+		td.Spans = append(td.Spans, &tracepb.Span{TraceId: traceID(i)}, &tracepb.Span{TraceId: traceID(i)})
+	}
+	return td
+}
+
+func TestTraceExporter(t *testing.T) {
+	res := &testResolver{endpoints: []string{"a:55678", "b:55678", "invalid"}}
+	backends := newTestBackends()
+	lb := newTraceExporter(zap.NewNop(), res, backends.newBackend)
+
+	require.NoError(t, lb.ConsumeTraceData(context.Background(), newTestTraceData(100)))
+	traceIDs := backends.traceIDs()
+	require.Len(t, traceIDs, 2)
+	// Every trace went to a single backend.
+	assert.Equal(t, 100, len(traceIDs["a:55678"])+len(traceIDs["b:55678"]))
+	for _, sink := range backends.sinks {
+		for _, td := range sink.AllTraces() {
+			assert.Equal(t, "test", td.SourceFormat)
+		}
+	}
+
+	// The backend removed is stopped, the one added is created.
+	res.onChange([]string{"b:55678", "c:55678"})
+	assert.True(t, backends.stopped["a:55678"])
+	assert.False(t, backends.stopped["b:55678"])
+	require.Contains(t, backends.sinks, "c:55678")
+
+	require.NoError(t, lb.ConsumeTraceData(context.Background(), newTestTraceData(100)))
+	assert.Equal(t, 1, len(backends.sinks["a:55678"].AllTraces()))
+
+	require.NoError(t, lb.stop())
+	assert.True(t, res.stopped)
+	assert.True(t, backends.stopped["b:55678"])
+	assert.True(t, backends.stopped["c:55678"])
+	assert.Equal(t, errNoBackends, lb.ConsumeTraceData(context.Background(), newTestTraceData(1)))
+}
@@ -0,0 +1,142 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDNSPort     = "55678"
+	defaultDNSInterval = 30 * time.Second
+)
+
+// resolver finds the endpoints of the backends.
+type resolver interface {
+	// start calls onChange with the endpoints, then every time they change,
+	// until stop is called.
+	start(onChange func(endpoints []string))
+	stop()
+}
+
+// staticResolver returns a fixed list of endpoints.
+type staticResolver struct {
+	endpoints []string
+}
+
+func (r *staticResolver) start(onChange func(endpoints []string)) {
+	onChange(r.endpoints)
+}
+
+func (r *staticResolver) stop() {}
+
+// lookupIPAddr resolves the hostname of the dns resolver, replaced in the
+// tests.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// dnsResolver returns the IP addresses of a hostname, with a port, resolving
+// them again at every interval.
+type dnsResolver struct {
+	logger   *zap.Logger
+	hostname string
+	port     string
+	interval time.Duration
+	done     chan struct{}
+}
+
+func newDNSResolver(logger *zap.Logger, cfg DNSResolver) *dnsResolver {
+	r := &dnsResolver{
+		logger:   logger,
+		hostname: cfg.Hostname,
+		port:     cfg.Port,
+		interval: cfg.Interval,
+		done:     make(chan struct{}),
+	}
+	if r.port == "" {
+		r.port = defaultDNSPort
+	}
+	if r.interval <= 0 {
+		r.interval = defaultDNSInterval
+	}
+	return r
+}
+
+func (r *dnsResolver) start(onChange func(endpoints []string)) {
+	var last []string
+	resolve := func() {
+		endpoints, err := r.resolve()
+		if err != nil {
+			// Keep the last endpoints, the resolution is retried at the next
+			// interval.
+			r.logger.Warn("Failed to resolve the backends", zap.String("hostname", r.hostname), zap.Error(err))
+			return
+		}
+		if !equalStrings(endpoints, last) {
+			last = endpoints
+			onChange(endpoints)
+		}
+	}
+
+	resolve()
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				resolve()
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+func (r *dnsResolver) stop() {
+	close(r.done)
+}
+
+func (r *dnsResolver) resolve() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+	defer cancel()
+	addrs, err := lookupIPAddr(ctx, r.hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, net.JoinHostPort(addr.IP.String(), r.port))
+	}
+	sort.Strings(endpoints)
+	return endpoints, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestStaticResolver(t *testing.T) {
+	res := &staticResolver{endpoints: []string{"a:55678", "b:55678"}}
+	var got []string
+	res.start(func(endpoints []string) { got = endpoints })
+	res.stop()
+	assert.Equal(t, []string{"a:55678", "b:55678"}, got)
+}
+
+func TestDNSResolver(t *testing.T) {
+	var mu sync.Mutex
+	ips := []string{"10.0.0.2", "10.0.0.1"}
+	var lookupErr error
+	defer func(f func(ctx context.Context, host string) ([]net.IPAddr, error)) { lookupIPAddr = f }(lookupIPAddr)
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "collectors", host)
+		var addrs []net.IPAddr
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		return addrs, lookupErr
+	}
+
+	res := newDNSResolver(zap.NewNop(), DNSResolver{Hostname: "collectors", Interval: 10 * time.Millisecond})
+	changes := make(chan []string, 10)
+	res.start(func(endpoints []string) { changes <- endpoints })
+	defer res.stop()
+
+	// The endpoints are resolved right away, with the default port.
+	assert.Equal(t, []string{"10.0.0.1:55678", "10.0.0.2:55678"}, <-changes)
+
+	// The failed resolutions keep the last endpoints.
+	mu.Lock()
+	lookupErr = errors.New("no such host")
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case endpoints := <-changes:
+		t.Fatalf("Got endpoints %v after a failed resolution", endpoints)
+	default:
+	}
+
+	mu.Lock()
+	lookupErr = nil
+	ips = []string{"10.0.0.3"}
+	mu.Unlock()
+	select {
+	case endpoints := <-changes:
+		assert.Equal(t, []string{"10.0.0.3:55678"}, endpoints)
+	case <-time.After(2 * time.Second):
+		t.Fatal("The endpoints weren't resolved again")
+	}
+}
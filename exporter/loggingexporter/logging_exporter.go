@@ -19,6 +19,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
@@ -35,6 +36,7 @@ func NewTraceExporter(exporterName string, logger *zap.Logger) (exporter.TraceEx
 			return 0, nil
 		},
 		exporterhelper.WithSpanName(exporterName+".ConsumeTraceData"), exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
 	)
 }
 
@@ -49,5 +51,6 @@ func NewMetricsExporter(exporterName string, logger *zap.Logger) (exporter.Metri
 			return 0, nil
 		},
 		exporterhelper.WithSpanName(exporterName+".ConsumeMetricsData"), exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
 	)
 }
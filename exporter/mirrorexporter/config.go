@@ -0,0 +1,55 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirrorexporter
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+)
+
+// Config defines configuration for the mirror exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Primary is the name of the exporter whose result is the result of the
+	// mirror exporter.
+	Primary string `mapstructure:"primary"`
+
+	// Shadow is the name of the exporter the data is mirrored to, its errors
+	// are ignored.
+	Shadow string `mapstructure:"shadow"`
+
+	// SamplingPercentage is the percentage of the traces and of the metrics
+	// mirrored to the shadow exporter.
+	SamplingPercentage float64 `mapstructure:"sampling-percentage"`
+
+	// MaxConcurrentShadowRequests is the maximum number of requests sent to
+	// the shadow exporter at the same time, the data beyond it is not
+	// mirrored.
+	MaxConcurrentShadowRequests int `mapstructure:"max-concurrent-shadow-requests"`
+}
+
+var _ exporter.SynchronousWrappingConfig = (*Config)(nil)
+
+// WrappedExporters returns the primary and the shadow exporters.
+func (cfg *Config) WrappedExporters() []string {
+	return []string{cfg.Primary, cfg.Shadow}
+}
+
+// SynchronousExporters returns the primary exporter, its errors are the ones
+// of the mirror exporter.
+func (cfg *Config) SynchronousExporters() []string {
+	return []string{cfg.Primary}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirrorexporter
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	exporters[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["mirror"]
+	assert.Equal(t, factory.CreateDefaultConfig(), e0)
+
+	e1 := cfg.Exporters["mirror/2"]
+	assert.Equal(t,
+		&Config{
+			ExporterSettings: configmodels.ExporterSettings{
+				NameVal: "mirror/2",
+				TypeVal: "mirror",
+			},
+			Primary:                     "exampleexporter/primary",
+			Shadow:                      "exampleexporter/shadow",
+			SamplingPercentage:          25,
+			MaxConcurrentShadowRequests: 4,
+		},
+		e1)
+	assert.Equal(t, []string{"exampleexporter/primary", "exampleexporter/shadow"}, e1.(*Config).WrappedExporters())
+	// The errors of the shadow are ignored, it may queue the data.
+	assert.Equal(t, []string{"exampleexporter/primary"}, e1.(*Config).SynchronousExporters())
+}
@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirrorexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateExporter(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	// The primary and the shadow are required.
+	_, _, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+	_, _, err = factory.CreateMetricsExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+
+	cfg.Primary = "opencensus/primary"
+	cfg.Shadow = "opencensus/shadow"
+	te, stop, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, te)
+	assert.NoError(t, stop())
+	me, stop, err := factory.CreateMetricsExporter(zap.NewNop(), cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, me)
+	assert.NoError(t, stop())
+
+	cfg.SamplingPercentage = 101
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+
+	cfg.SamplingPercentage = 100
+	cfg.MaxConcurrentShadowRequests = 0
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+
+	cfg.MaxConcurrentShadowRequests = 1
+	cfg.Shadow = cfg.Primary
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+}
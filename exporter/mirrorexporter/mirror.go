@@ -0,0 +1,180 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mirrorexporter implements an exporter that sends the data to a
+// primary exporter and mirrors a sample of it to a shadow exporter, e.g. to
+// evaluate a new backend with production traffic without risking delivery.
+package mirrorexporter
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+	"sync"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/contextutils"
+	"github.com/open-telemetry/opentelemetry-service/processor/multiconsumer"
+)
+
+// mirror sends the data to the shadow exporter in the background of the
+// requests to the primary one, it is shared by the trace and metrics
+// exporters.
+type mirror struct {
+	logger     *zap.Logger
+	shadowName string
+	// threshold is the upper bound of the hashes of the sampled traces and
+	// metrics.
+	threshold uint32
+	// slots limits the number of concurrent requests to the shadow exporter.
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newMirror(logger *zap.Logger, cfg Config) (*mirror, error) {
+	if cfg.Primary == "" || cfg.Shadow == "" {
+		return nil, errors.New("primary and shadow must name the exporters to send the data to")
+	}
+	if cfg.Primary == cfg.Shadow {
+		return nil, errors.New("primary and shadow must be different exporters")
+	}
+	if cfg.SamplingPercentage < 0 || cfg.SamplingPercentage > 100 {
+		return nil, errors.New("sampling-percentage must be between 0 and 100")
+	}
+	if cfg.MaxConcurrentShadowRequests <= 0 {
+		return nil, errors.New("max-concurrent-shadow-requests must be positive")
+	}
+	return &mirror{
+		logger:     logger,
+		shadowName: cfg.Shadow,
+		threshold:  uint32(math.Round(cfg.SamplingPercentage / 100 * math.MaxUint32)),
+		slots:      make(chan struct{}, cfg.MaxConcurrentShadowRequests),
+	}, nil
+}
+
+// sampled returns whether the data with the given key, a trace ID or a metric
+// name, is mirrored. The same keys are always sampled together.
+func (m *mirror) sampled(key []byte) bool {
+	if m.threshold == math.MaxUint32 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32() < m.threshold
+}
+
+// send sends the data to the shadow exporter in the background, unless
+// too many requests to it are in flight. The values of the context are kept,
+// but not its deadline and cancellation which belong to the primary request.
+func (m *mirror) send(ctx context.Context, export func(ctx context.Context) error) {
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		m.logger.Debug("Too many requests to the shadow exporter, the data is not mirrored",
+			zap.String("exporter", m.shadowName))
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() { <-m.slots }()
+		if err := export(contextutils.Detach(ctx, nil)); err != nil {
+			m.logger.Debug("Failed to mirror the data to the shadow exporter",
+				zap.String("exporter", m.shadowName), zap.Error(err))
+		}
+	}()
+}
+
+// stop waits for the requests to the shadow exporter in flight.
+func (m *mirror) stop() error {
+	m.wg.Wait()
+	return nil
+}
+
+type traceExporter struct {
+	*mirror
+	primary consumer.TraceConsumer
+	shadow  consumer.TraceConsumer
+}
+
+var _ consumer.TraceConsumer = (*traceExporter)(nil)
+var _ exporter.TraceWrapper = (*traceExporter)(nil)
+
+func (te *traceExporter) SetTraceExporters(exporters []consumer.TraceConsumer) {
+	te.primary, te.shadow = exporters[0], exporters[1]
+}
+
+func (te *traceExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	var spans []*tracepb.Span
+	for _, span := range td.Spans {
+		if span != nil && te.sampled(span.TraceId) {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) > 0 {
+		mirrored := td
+		mirrored.Spans = spans
+		// The shadow exporter is sent the data concurrently with the
+		// primary one, neither may modify the data the other one reads.
+		if consumer.GetCapabilities(te.primary).MutatesConsumedData ||
+			consumer.GetCapabilities(te.shadow).MutatesConsumedData {
+			mirrored = multiconsumer.CloneTraceData(mirrored)
+		}
+		te.send(ctx, func(ctx context.Context) error {
+			return te.shadow.ConsumeTraceData(ctx, mirrored)
+		})
+	}
+	return te.primary.ConsumeTraceData(ctx, td)
+}
+
+type metricsExporter struct {
+	*mirror
+	primary consumer.MetricsConsumer
+	shadow  consumer.MetricsConsumer
+}
+
+var _ consumer.MetricsConsumer = (*metricsExporter)(nil)
+var _ exporter.MetricsWrapper = (*metricsExporter)(nil)
+
+func (me *metricsExporter) SetMetricsExporters(exporters []consumer.MetricsConsumer) {
+	me.primary, me.shadow = exporters[0], exporters[1]
+}
+
+func (me *metricsExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	var metrics []*metricspb.Metric
+	for _, metric := range md.Metrics {
+		if metric != nil && me.sampled([]byte(metric.GetMetricDescriptor().GetName())) {
+			metrics = append(metrics, metric)
+		}
+	}
+	if len(metrics) > 0 {
+		mirrored := md
+		mirrored.Metrics = metrics
+		if consumer.GetCapabilities(me.primary).MutatesConsumedData ||
+			consumer.GetCapabilities(me.shadow).MutatesConsumedData {
+			mirrored = multiconsumer.CloneMetricsData(mirrored)
+		}
+		me.send(ctx, func(ctx context.Context) error {
+			return me.shadow.ConsumeMetricsData(ctx, mirrored)
+		})
+	}
+	return me.primary.ConsumeMetricsData(ctx, md)
+}
@@ -0,0 +1,215 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mirrorexporter
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+// testExporter records the data received and fails when told to, it blocks
+// while release is set and not closed.
+type testExporter struct {
+	mu      sync.Mutex
+	spans   int
+	metrics int
+	fail    bool
+	release chan struct{}
+	ctxErr  error
+}
+
+func (te *testExporter) wait(ctx context.Context) {
+	if te.release != nil {
+		<-te.release
+	}
+	te.ctxErr = ctx.Err()
+}
+
+func (te *testExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.wait(ctx)
+	if te.fail {
+		return errors.New("backend unavailable")
+	}
+	te.spans += len(td.Spans)
+	return nil
+}
+
+func (te *testExporter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	te.wait(ctx)
+	if te.fail {
+		return errors.New("backend unavailable")
+	}
+	te.metrics += len(md.Metrics)
+	return nil
+}
+
+func newTestTraceExporter(t *testing.T, percentage float64, concurrency int) (*traceExporter, *testExporter, *testExporter) {
+	m, err := newMirror(zap.NewNop(), Config{
+		Primary:                     "primary",
+		Shadow:                      "shadow",
+		SamplingPercentage:          percentage,
+		MaxConcurrentShadowRequests: concurrency,
+	})
+	require.NoError(t, err)
+	primary, shadow := &testExporter{}, &testExporter{}
+	te := &traceExporter{mirror: m}
+	te.SetTraceExporters([]consumer.TraceConsumer{primary, shadow})
+	return te, primary, shadow
+}
+
+func testSpans(n int) []*tracepb.Span {
+	spans := make([]*tracepb.Span, n)
+	for i := range spans {
+		traceID := make([]byte, 16)
+		binary.BigEndian.PutUint64(traceID[8:], uint64(i))
+		spans[i] = &tracepb.Span{TraceId: traceID}
+	}
+	return spans
+}
+
+func TestTraceExporterMirrors(t *testing.T) {
+	te, primary, shadow := newTestTraceExporter(t, 100, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, te.ConsumeTraceData(ctx, consumerdata.TraceData{Spans: testSpans(10)}))
+	// The request to the shadow outlives the one to the primary.
+	cancel()
+	require.NoError(t, te.stop())
+	assert.Equal(t, 10, primary.spans)
+	assert.Equal(t, 10, shadow.spans)
+	assert.NoError(t, shadow.ctxErr)
+}
+
+func TestTraceExporterShadowErrorsIgnored(t *testing.T) {
+	te, primary, shadow := newTestTraceExporter(t, 100, 10)
+	shadow.fail = true
+	require.NoError(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: testSpans(1)}))
+	require.NoError(t, te.stop())
+	assert.Equal(t, 1, primary.spans)
+
+	// The result is the one of the primary.
+	shadow.fail = false
+	primary.fail = true
+	assert.Error(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: testSpans(1)}))
+	require.NoError(t, te.stop())
+	assert.Equal(t, 1, shadow.spans)
+}
+
+func TestTraceExporterSampling(t *testing.T) {
+	te, primary, shadow := newTestTraceExporter(t, 25, 10)
+	td := consumerdata.TraceData{Spans: testSpans(1000)}
+	// The spans of a trace are mirrored together.
+	td.Spans = append(td.Spans, testSpans(1000)...)
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	require.NoError(t, te.stop())
+	assert.Equal(t, 2000, primary.spans)
+	assert.Equal(t, 2000, len(td.Spans), "the data of the primary must not be modified")
+	assert.InDelta(t, 500, shadow.spans, 100)
+	assert.Equal(t, 0, shadow.spans%2)
+
+	te, _, shadow = newTestTraceExporter(t, 0, 10)
+	require.NoError(t, te.ConsumeTraceData(context.Background(), td))
+	require.NoError(t, te.stop())
+	assert.Equal(t, 0, shadow.spans)
+}
+
+func TestTraceExporterShadowConcurrency(t *testing.T) {
+	te, primary, shadow := newTestTraceExporter(t, 100, 1)
+	shadow.release = make(chan struct{})
+	for i := 0; i < 3; i++ {
+		require.NoError(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: testSpans(1)}))
+	}
+	close(shadow.release)
+	require.NoError(t, te.stop())
+	// The data beyond the concurrent requests is not mirrored.
+	assert.Equal(t, 3, primary.spans)
+	assert.Equal(t, 1, shadow.spans)
+}
+
+// renamingExporter renames the spans it receives.
+type renamingExporter struct{}
+
+func (re *renamingExporter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	for _, span := range td.Spans {
+		span.Name = &tracepb.TruncatableString{Value: "renamed"}
+	}
+	return nil
+}
+
+func TestTraceExporterMutatingPrimary(t *testing.T) {
+	m, err := newMirror(zap.NewNop(), Config{
+		Primary:                     "primary",
+		Shadow:                      "shadow",
+		SamplingPercentage:          100,
+		MaxConcurrentShadowRequests: 10,
+	})
+	require.NoError(t, err)
+	shadow := &exportertest.SinkTraceExporter{}
+	te := &traceExporter{mirror: m}
+	te.SetTraceExporters([]consumer.TraceConsumer{&renamingExporter{}, shadow})
+
+	// The shadow gets a copy of the data the primary modifies.
+	spans := testSpans(1)
+	spans[0].Name = &tracepb.TruncatableString{Value: "span"}
+	require.NoError(t, te.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: spans}))
+	require.NoError(t, te.stop())
+	received := shadow.AllTraces()
+	require.Len(t, received, 1)
+	assert.Equal(t, "span", received[0].Spans[0].Name.Value)
+}
+
+func TestMetricsExporterSampling(t *testing.T) {
+	m, err := newMirror(zap.NewNop(), Config{
+		Primary:                     "primary",
+		Shadow:                      "shadow",
+		SamplingPercentage:          50,
+		MaxConcurrentShadowRequests: 10,
+	})
+	require.NoError(t, err)
+	primary, shadow := &testExporter{}, &testExporter{}
+	me := &metricsExporter{mirror: m}
+	me.SetMetricsExporters([]consumer.MetricsConsumer{primary, shadow})
+
+	var metrics []*metricspb.Metric
+	for i := 0; i < 1000; i++ {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{Name: fmt.Sprintf("metric_%d", i)},
+		})
+	}
+	require.NoError(t, me.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Metrics: metrics}))
+	require.NoError(t, me.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Metrics: metrics}))
+	require.NoError(t, me.stop())
+	assert.Equal(t, 2000, primary.metrics)
+	// The same metrics are sampled in every request.
+	assert.InDelta(t, 1000, shadow.metrics, 200)
+	assert.Equal(t, 0, shadow.metrics%2)
+}
@@ -17,7 +17,11 @@ package opencensusexporter
 import (
 	"time"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 // Config defines configuration for OpenCensus exporter.
@@ -26,31 +30,84 @@ type Config struct {
 
 	// The target to which the exporter is going to send traces or metrics,
 	// using the gRPC protocol. The valid syntax is described at
-	// https://github.com/grpc/grpc/blob/master/doc/naming.md.
+	// https://github.com/grpc/grpc/blob/master/doc/naming.md, or
+	// "unix:///path/to/socket" for a Unix domain socket.
 	Endpoint string `mapstructure:"endpoint"`
 
 	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// collector, `gzip`, `snappy` or `zstd`.
 	Compression string `mapstructure:"compression"`
 
 	// The headers associated with gRPC requests.
 	Headers map[string]string `mapstructure:"headers"`
 
+	// The number of gRPC connections shared by the trace and metrics exporters
+	// created from this configuration.
+	NumConnections int `mapstructure:"num-connections"`
+
 	// The number of workers that send the gRPC requests.
+	//
+	// Deprecated: use NumConnections, it is used when NumConnections is not set.
 	NumWorkers int `mapstructure:"num-workers"`
 
+	// The maximum number of trace requests sent at the same time, it defaults
+	// to the number of connections.
+	MaxConcurrentTraceRequests int `mapstructure:"max-concurrent-trace-requests"`
+
+	// The maximum number of metrics requests sent at the same time, it
+	// defaults to the number of connections.
+	MaxConcurrentMetricsRequests int `mapstructure:"max-concurrent-metrics-requests"`
+
 	// certificate file for TLS credentials of gRPC client. Should
-	// only be used if `secure` is set to true.
+	// only be used if `secure` is set to true. Ignored if TLSSetting is set.
 	CertPemFile string `mapstructure:"cert-pem-file"`
 
 	// Whether to enable client transport security for the exporter's gRPC
 	// connection. See [grpc.WithInsecure()](https://godoc.org/google.golang.org/grpc#WithInsecure).
+	// Ignored if TLSSetting is set.
 	UseSecure bool `mapstructure:"secure,omitempty"`
 
+	// TLSSetting configures the TLS of the gRPC connection, it takes precedence
+	// over cert-pem-file and secure.
+	TLSSetting *configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Auth configures the credentials sent with every request: a static
+	// bearer token, OAuth2 client credentials or the Google application
+	// default credentials. It requires TLS.
+	Auth *configgrpc.PerRPCAuthSettings `mapstructure:"auth,omitempty"`
+
 	// The time period between each reconnection performed by the exporter.
+	// It is also the initial delay before replacing a failed connection of
+	// the pool, the delay doubles on every consecutive failure.
 	ReconnectionDelay time.Duration `mapstructure:"reconnection-delay,omitempty"`
 
+	// The maximum delay before replacing a failed connection of the pool.
+	MaxReconnectionDelay time.Duration `mapstructure:"max-reconnection-delay,omitempty"`
+
 	// The keepalive parameters for client gRPC. See grpc.WithKeepaliveParams
 	// (https://godoc.org/google.golang.org/grpc#WithKeepaliveParams).
-	KeepaliveParameters *KeepaliveConfig `mapstructure:"keepalive,omitempty"`
+	KeepaliveParameters *configgrpc.KeepaliveClientConfig `mapstructure:"keepalive,omitempty"`
+
+	// BalancerName is the load balancing policy spreading the requests of
+	// every connection across the addresses of the endpoint, e.g.
+	// "round_robin" with an endpoint like "dns:///collector.example.com:55678".
+	// If empty the requests are sent to the first address.
+	BalancerName string `mapstructure:"balancer-name,omitempty"`
+
+	// QueueSettings configures the queue used to send the requests asynchronously.
+	QueueSettings exporterhelper.QueueSettings `mapstructure:"sending-queue"`
+
+	// RetrySettings configures how failed requests are retried.
+	RetrySettings exporterhelper.RetrySettings `mapstructure:"retry-on-failure"`
+
+	// SplitSettings configures the maximum size of the requests, bigger
+	// batches are split into several requests.
+	SplitSettings exporterhelper.SplitSettings `mapstructure:"split-batch"`
+}
+
+var _ exporter.QueuedConfig = (*Config)(nil)
+
+// QueueEnabled returns whether the requests are queued.
+func (cfg *Config) QueueEnabled() bool {
+	return cfg.QueueSettings.Enabled
 }
@@ -17,12 +17,16 @@ package opencensusexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -53,16 +57,62 @@ func TestLoadConfig(t *testing.T) {
 				"header1":                "234",
 				"another":                "somevalue",
 			},
-			Endpoint:          "1.2.3.4:1234",
-			Compression:       "on",
-			NumWorkers:        123,
-			CertPemFile:       "/var/lib/mycert.pem",
-			UseSecure:         true,
-			ReconnectionDelay: 15,
+			Endpoint:                     "1.2.3.4:1234",
+			Compression:                  "on",
+			NumConnections:               123,
+			MaxConcurrentTraceRequests:   200,
+			MaxConcurrentMetricsRequests: 50,
+			CertPemFile:                  "/var/lib/mycert.pem",
+			UseSecure:                    true,
+			ReconnectionDelay:            15,
+			MaxReconnectionDelay:         time.Minute,
 			KeepaliveParameters: &KeepaliveConfig{
 				Time:                20,
 				PermitWithoutStream: true,
 				Timeout:             30,
 			},
+			BalancerName: "round_robin",
+			QueueSettings: exporterhelper.QueueSettings{
+				Enabled:    false,
+				NumWorkers: 2,
+				QueueSize:  10,
+			},
+			RetrySettings: exporterhelper.RetrySettings{
+				Enabled:             true,
+				InitialInterval:     10 * time.Second,
+				MaxInterval:         1 * time.Minute,
+				MaxElapsedTime:      10 * time.Minute,
+				Multiplier:          2,
+				RandomizationFactor: 0.2,
+			},
+			SplitSettings: exporterhelper.SplitSettings{
+				MaxItems: 1000,
+				MaxBytes: 4000000,
+			},
+		})
+
+	e2 := cfg.Exporters["opencensus/tls"].(*Config)
+	assert.Equal(t, "1.2.3.4:1234", e2.Endpoint)
+	assert.Equal(t, e2.TLSSetting,
+		&configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{
+				CAFile:     "/var/lib/ca.pem",
+				CertFile:   "/var/lib/client.pem",
+				KeyFile:    "/var/lib/client.key",
+				MinVersion: "1.2",
+			},
+			ServerNameOverride: "collector.example.com",
+		})
+
+	e3 := cfg.Exporters["opencensus/auth"].(*Config)
+	assert.Equal(t, e3.Auth,
+		&configgrpc.PerRPCAuthSettings{
+			OAuth2: &configgrpc.OAuth2ClientCredentials{
+				ClientID:       "otelsvc",
+				ClientSecret:   "secret",
+				TokenURL:       "https://auth.example.com/oauth2/token",
+				Scopes:         []string{"traces.write"},
+				EndpointParams: map[string]string{"audience": "collector.example.com"},
+			},
 		})
 }
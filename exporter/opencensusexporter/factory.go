@@ -22,11 +22,13 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/keepalive"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/compression"
 	compressiongrpc "github.com/open-telemetry/opentelemetry-service/compression/grpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exporterhelper"
@@ -39,6 +41,9 @@ const (
 
 // Factory is the factory for OpenCensus exporter.
 type Factory struct {
+	// Holder gives the factory the build of the service, sent as the user
+	// agent of the requests.
+	buildinfo.Holder
 }
 
 // Type gets the type of the Exporter config created by this factory.
@@ -53,7 +58,9 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Headers: map[string]string{},
+		Headers:       map[string]string{},
+		QueueSettings: exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings: exporterhelper.CreateDefaultRetrySettings(),
 	}
 }
 
@@ -72,31 +79,34 @@ func (f *Factory) CreateTraceExporter(logger *zap.Logger, config configmodels.Ex
 		"oc_trace",
 		oce.PushTraceData,
 		exporterhelper.WithSpanName("ocservice.exporter.OpenCensus.ConsumeTraceData"),
-		exporterhelper.WithRecordMetrics(true))
+		exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
+		exporterhelper.WithSplit(ocac.SplitSettings),
+		exporterhelper.WithQueue(ocac.QueueSettings),
+		exporterhelper.WithRetry(ocac.RetrySettings),
+		exporterhelper.WithShutdown(oce.stop))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return oexp, oce.stop, nil
+	return oexp, oexp.Shutdown, nil
 }
 
-// createOCAgentExporter takes ocagent exporter options and create an OC exporter
+// createOCAgentExporter takes ocagent exporter options and create an OC
+// exporter using the connection pool shared by the exporters of the config.
 func (f *Factory) createOCAgentExporter(logger *zap.Logger, ocac *Config, opts []ocagent.ExporterOption) (*ocagentExporter, error) {
-	numWorkers := defaultNumWorkers
-	if ocac.NumWorkers > 0 {
-		numWorkers = ocac.NumWorkers
-	}
-
-	exportersChan := make(chan *ocagent.Exporter, numWorkers)
-	for exporterIndex := 0; exporterIndex < numWorkers; exporterIndex++ {
-		exporter, serr := ocagent.NewExporter(opts...)
-		if serr != nil {
-			return nil, fmt.Errorf("cannot configure OpenCensus exporter: %v", serr)
+	dial := func() (ocagentConn, error) {
+		exporter, err := ocagent.NewExporter(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot configure OpenCensus exporter: %v", err)
 		}
-		exportersChan <- exporter
+		return exporter, nil
 	}
-	oce := &ocagentExporter{exporters: exportersChan}
-	return oce, nil
+	pool, err := acquirePool(logger, ocac, dial)
+	if err != nil {
+		return nil, err
+	}
+	return &ocagentExporter{cfg: ocac, pool: pool}, nil
 }
 
 // OCAgentOptions takes the oc exporter Config and generates ocagent Options
@@ -107,7 +117,10 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 			msg:  "OpenCensus exporter config requires an Endpoint",
 		}
 	}
-	opts := []ocagent.ExporterOption{ocagent.WithAddress(ocac.Endpoint)}
+	// The gRPC dial options are set at once, ocagent.WithGRPCDialOption
+	// replaces the previous ones.
+	target, dialOpts := confignet.GRPCDialOptions(ocac.Endpoint)
+	opts := []ocagent.ExporterOption{ocagent.WithAddress(target)}
 	if ocac.Compression != "" {
 		if compressionKey := compressiongrpc.GetGRPCCompressionKey(ocac.Compression); compressionKey != compression.Unsupported {
 			opts = append(opts, ocagent.UseCompressor(compressionKey))
@@ -118,7 +131,22 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 			}
 		}
 	}
-	if ocac.CertPemFile != "" {
+	secure := false
+	if ocac.TLSSetting != nil {
+		tlsCfg, err := ocac.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, &ocExporterError{
+				code: errUnableToGetTLSCreds,
+				msg:  fmt.Sprintf("OpenCensus exporter unable to load the TLS configuration: %v", err),
+			}
+		}
+		if tlsCfg != nil {
+			opts = append(opts, ocagent.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+			secure = true
+		} else {
+			opts = append(opts, ocagent.WithInsecure())
+		}
+	} else if ocac.CertPemFile != "" {
 		creds, err := credentials.NewClientTLSFromFile(ocac.CertPemFile, "")
 		if err != nil {
 			return nil, &ocExporterError{
@@ -127,6 +155,7 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 			}
 		}
 		opts = append(opts, ocagent.WithTLSCredentials(creds))
+		secure = true
 	} else if ocac.UseSecure {
 		certPool, err := x509.SystemCertPool()
 		if err != nil {
@@ -138,9 +167,26 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 		}
 		creds := credentials.NewClientTLSFromCert(certPool, "")
 		opts = append(opts, ocagent.WithTLSCredentials(creds))
+		secure = true
 	} else {
 		opts = append(opts, ocagent.WithInsecure())
 	}
+	if ocac.Auth != nil {
+		if !secure {
+			return nil, &ocExporterError{
+				code: errInvalidAuth,
+				msg:  "OpenCensus exporter auth requires TLS, the credentials would be sent in clear text",
+			}
+		}
+		creds, err := ocac.Auth.PerRPCCredentials()
+		if err != nil {
+			return nil, &ocExporterError{
+				code: errInvalidAuth,
+				msg:  fmt.Sprintf("OpenCensus exporter unable to create the auth credentials: %v", err),
+			}
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(creds))
+	}
 	if len(ocac.Headers) > 0 {
 		opts = append(opts, ocagent.WithHeaders(ocac.Headers))
 	}
@@ -148,12 +194,20 @@ func (f *Factory) OCAgentOptions(logger *zap.Logger, ocac *Config) ([]ocagent.Ex
 		opts = append(opts, ocagent.WithReconnectionPeriod(ocac.ReconnectionDelay))
 	}
 	if ocac.KeepaliveParameters != nil {
-		opts = append(opts, ocagent.WithGRPCDialOption(grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                ocac.KeepaliveParameters.Time,
-			Timeout:             ocac.KeepaliveParameters.Timeout,
-			PermitWithoutStream: ocac.KeepaliveParameters.PermitWithoutStream,
-		})))
+		dialOpts = append(dialOpts, ocac.KeepaliveParameters.DialOption())
+	}
+	if ocac.BalancerName != "" {
+		balancerOpt, err := configgrpc.BalancerDialOption(ocac.BalancerName)
+		if err != nil {
+			return nil, &ocExporterError{
+				code: errUnsupportedBalancer,
+				msg:  fmt.Sprintf("OpenCensus exporter %v", err),
+			}
+		}
+		dialOpts = append(dialOpts, balancerOpt)
 	}
+	dialOpts = append(dialOpts, grpc.WithUserAgent(f.BuildInfo().UserAgent()))
+	opts = append(opts, ocagent.WithGRPCDialOption(dialOpts...))
 	return opts, nil
 }
 
@@ -172,11 +226,16 @@ func (f *Factory) CreateMetricsExporter(logger *zap.Logger, config configmodels.
 		"oc_metrics",
 		oce.PushMetricsData,
 		exporterhelper.WithSpanName("ocservice.exporter.OpenCensus.ConsumeMetricsData"),
-		exporterhelper.WithRecordMetrics(true))
+		exporterhelper.WithRecordMetrics(true),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesConsumedData: false}),
+		exporterhelper.WithSplit(ocac.SplitSettings),
+		exporterhelper.WithQueue(ocac.QueueSettings),
+		exporterhelper.WithRetry(ocac.RetrySettings),
+		exporterhelper.WithShutdown(oce.stop))
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return oexp, oce.stop, nil
+	return oexp, oexp.Shutdown, nil
 }
@@ -24,6 +24,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/compression"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
 	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver"
@@ -128,6 +130,21 @@ func TestCreateTraceExporter(t *testing.T) {
 				NumWorkers: 3,
 			},
 		},
+		{
+			name: "Balancer",
+			config: Config{
+				Endpoint:     "dns:///" + rcvCfg.Endpoint,
+				BalancerName: "round_robin",
+			},
+		},
+		{
+			name: "BalancerError",
+			config: Config{
+				Endpoint:     rcvCfg.Endpoint,
+				BalancerName: "random",
+			},
+			mustFail: true,
+		},
 		{
 			name: "CompressionError",
 			config: Config{
@@ -143,6 +160,57 @@ func TestCreateTraceExporter(t *testing.T) {
 				CertPemFile: "testdata/test_cert.pem",
 			},
 		},
+		{
+			name: "TLSSetting",
+			config: Config{
+				Endpoint: rcvCfg.Endpoint,
+				TLSSetting: &configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{CAFile: "testdata/test_cert.pem"},
+				},
+			},
+		},
+		{
+			name: "TLSSettingInsecure",
+			config: Config{
+				Endpoint:   rcvCfg.Endpoint,
+				TLSSetting: &configtls.TLSClientSetting{Insecure: true},
+			},
+		},
+		{
+			name: "TLSSettingError",
+			config: Config{
+				Endpoint: rcvCfg.Endpoint,
+				TLSSetting: &configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{CAFile: "nosuchfile"},
+				},
+			},
+			mustFail: true,
+		},
+		{
+			name: "AuthBearerToken",
+			config: Config{
+				Endpoint:  rcvCfg.Endpoint,
+				UseSecure: true,
+				Auth:      &configgrpc.PerRPCAuthSettings{BearerToken: "token"},
+			},
+		},
+		{
+			name: "AuthInsecureError",
+			config: Config{
+				Endpoint: rcvCfg.Endpoint,
+				Auth:     &configgrpc.PerRPCAuthSettings{BearerToken: "token"},
+			},
+			mustFail: true,
+		},
+		{
+			name: "AuthError",
+			config: Config{
+				Endpoint:  rcvCfg.Endpoint,
+				UseSecure: true,
+				Auth:      &configgrpc.PerRPCAuthSettings{},
+			},
+			mustFail: true,
+		},
 		{
 			name: "CertPemFileError",
 			config: Config{
@@ -16,28 +16,26 @@ package opencensusexporter
 
 import (
 	"context"
-	"fmt"
-	"sync"
-	"time"
 
-	"contrib.go.opencensus.io/exporter/ocagent"
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
-	"github.com/open-telemetry/opentelemetry-service/oterr"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 )
 
 // KeepaliveConfig exposes the keepalive.ClientParameters to be used by the exporter.
-// Refer to the original data-structure for the meaning of each parameter.
-type KeepaliveConfig struct {
-	Time                time.Duration `mapstructure:"time,omitempty"`
-	Timeout             time.Duration `mapstructure:"timeout,omitempty"`
-	PermitWithoutStream bool          `mapstructure:"permit-without-stream,omitempty"`
-}
+//
+// Deprecated: use configgrpc.KeepaliveClientConfig, shared by the gRPC
+// exporters.
+type KeepaliveConfig = configgrpc.KeepaliveClientConfig
 
+// ocagentExporter sends the data of one exporter through the connection pool
+// of its configuration.
 type ocagentExporter struct {
-	exporters chan *ocagent.Exporter
+	cfg  *Config
+	pool *connPool
 }
 
 type ocExporterErrorCode int
@@ -53,8 +51,6 @@ func (e *ocExporterError) Error() string {
 }
 
 const (
-	defaultNumWorkers int = 2
-
 	_ ocExporterErrorCode = iota // skip 0
 	// errEndpointRequired indicates that this exporter was not provided with an endpoint in its config.
 	errEndpointRequired
@@ -64,82 +60,49 @@ const (
 	errUnableToGetTLSCreds
 	// errAlreadyStopped indicates that the exporter was already stopped.
 	errAlreadyStopped
+	// errUnsupportedBalancer indicates that this exporter was provided with a load balancing policy gRPC does not support.
+	errUnsupportedBalancer
+	// errInvalidAuth indicates that this exporter could not create the per-RPC credentials of its auth config.
+	errInvalidAuth
 )
 
 func (oce *ocagentExporter) stop() error {
-	wg := &sync.WaitGroup{}
-	var errors []error
-	var errorsMu sync.Mutex
-	visitedCnt := 0
-	for currExporter := range oce.exporters {
-		wg.Add(1)
-		go func(exporter *ocagent.Exporter) {
-			defer wg.Done()
-			err := exporter.Stop()
-			if err != nil {
-				errorsMu.Lock()
-				errors = append(errors, err)
-				errorsMu.Unlock()
-			}
-		}(currExporter)
-		visitedCnt++
-		if visitedCnt == cap(oce.exporters) {
-			// Visited and started Stop on all exporters, just wait for the stop to finish.
-			break
-		}
-	}
-
-	wg.Wait()
-	close(oce.exporters)
-
-	return oterr.CombineErrors(errors)
+	return releasePool(oce.cfg, oce.pool)
 }
 
 func (oce *ocagentExporter) PushTraceData(ctx context.Context, td consumerdata.TraceData) (int, error) {
-	// Get first available exporter.
-	exporter, ok := <-oce.exporters
-	if !ok {
-		err := &ocExporterError{
-			code: errAlreadyStopped,
-			msg:  fmt.Sprintf("OpenCensus exporter was already stopped."),
-		}
-		return len(td.Spans), err
-	}
-
-	err := exporter.ExportTraceServiceRequest(
+	err := oce.pool.exportTraces(ctx,
 		&agenttracepb.ExportTraceServiceRequest{
 			Spans:    td.Spans,
 			Resource: td.Resource,
 			Node:     td.Node,
 		},
 	)
-	oce.exporters <- exporter
 	if err != nil {
-		return len(td.Spans), err
+		return len(td.Spans), exportError(err)
 	}
 	return 0, nil
 }
 
 func (oce *ocagentExporter) PushMetricsData(ctx context.Context, md consumerdata.MetricsData) (int, error) {
-	// Get first available exporter.
-	exporter, ok := <-oce.exporters
-	if !ok {
-		err := &ocExporterError{
-			code: errAlreadyStopped,
-			msg:  fmt.Sprintf("OpenCensus exporter was already stopped."),
-		}
-		return len(md.Metrics), err
-	}
-
 	req := &agentmetricspb.ExportMetricsServiceRequest{
 		Metrics:  md.Metrics,
 		Resource: md.Resource,
 		Node:     md.Node,
 	}
-	err := exporter.ExportMetricsServiceRequest(req)
-	oce.exporters <- exporter
+	err := oce.pool.exportMetrics(ctx, req)
 	if err != nil {
-		return len(md.Metrics), err
+		return len(md.Metrics), exportError(err)
 	}
 	return 0, nil
 }
+
+func exportError(err error) error {
+	if err == errPoolStopped {
+		return consumererror.Permanent(&ocExporterError{
+			code: errAlreadyStopped,
+			msg:  "OpenCensus exporter was already stopped.",
+		})
+	}
+	return consumererror.FromGRPC(err)
+}
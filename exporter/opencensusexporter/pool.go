@@ -0,0 +1,357 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-service/oterr"
+)
+
+const (
+	defaultNumConnections       = 2
+	defaultReconnectionDelay    = time.Second
+	defaultMaxReconnectionDelay = 30 * time.Second
+)
+
+var (
+	errPoolStopped  = errors.New("OpenCensus exporter was already stopped")
+	errNoConnection = status.Error(codes.Unavailable, "no OpenCensus exporter connection is available")
+)
+
+// ocagentConn is the part of ocagent.Exporter used by the pool, one instance
+// is one connection to the OpenCensus receiver.
+type ocagentConn interface {
+	ExportTraceServiceRequest(*agenttracepb.ExportTraceServiceRequest) error
+	ExportMetricsServiceRequest(*agentmetricspb.ExportMetricsServiceRequest) error
+	Stop() error
+}
+
+// poolSettings are the settings of a connPool.
+type poolSettings struct {
+	numConnections       int
+	maxTraceRequests     int
+	maxMetricsRequests   int
+	reconnectionDelay    time.Duration
+	maxReconnectionDelay time.Duration
+}
+
+func newPoolSettings(cfg *Config) poolSettings {
+	ps := poolSettings{
+		numConnections:       cfg.NumConnections,
+		maxTraceRequests:     cfg.MaxConcurrentTraceRequests,
+		maxMetricsRequests:   cfg.MaxConcurrentMetricsRequests,
+		reconnectionDelay:    cfg.ReconnectionDelay,
+		maxReconnectionDelay: cfg.MaxReconnectionDelay,
+	}
+	if ps.numConnections <= 0 {
+		ps.numConnections = cfg.NumWorkers
+	}
+	if ps.numConnections <= 0 {
+		ps.numConnections = defaultNumConnections
+	}
+	if ps.maxTraceRequests <= 0 {
+		ps.maxTraceRequests = ps.numConnections
+	}
+	if ps.maxMetricsRequests <= 0 {
+		ps.maxMetricsRequests = ps.numConnections
+	}
+	if ps.reconnectionDelay <= 0 {
+		ps.reconnectionDelay = defaultReconnectionDelay
+	}
+	if ps.maxReconnectionDelay <= 0 {
+		ps.maxReconnectionDelay = defaultMaxReconnectionDelay
+	}
+	if ps.maxReconnectionDelay < ps.reconnectionDelay {
+		ps.maxReconnectionDelay = ps.reconnectionDelay
+	}
+	return ps
+}
+
+// connPool is the set of connections used by the trace and metrics exporters
+// created from the same configuration. Every request is sent on the next
+// available connection, the number of requests sent at the same time is
+// limited per data type. A connection failing to send is replaced by a new
+// one after a jittered exponential backoff, so that the exporter doesn't keep
+// using a connection to a backend that went away, e.g. behind a load balancer.
+type connPool struct {
+	logger   *zap.Logger
+	settings poolSettings
+	dial     func() (ocagentConn, error)
+
+	conns      []*pooledConn
+	next       uint32
+	traceSem   chan struct{}
+	metricsSem chan struct{}
+
+	// mu guards stopped and the start of the goroutines replacing the
+	// connections, tracked by wg.
+	mu       sync.Mutex
+	stopped  bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	// refs is the number of exporters using the pool, guarded by poolsMu.
+	refs int
+}
+
+// pooledConn is a slot of the pool, its connection is nil while it is being
+// replaced.
+type pooledConn struct {
+	mu       sync.Mutex
+	conn     ocagentConn
+	failures int
+}
+
+func newConnPool(logger *zap.Logger, settings poolSettings, dial func() (ocagentConn, error)) (*connPool, error) {
+	p := &connPool{
+		logger:     logger,
+		settings:   settings,
+		dial:       dial,
+		conns:      make([]*pooledConn, settings.numConnections),
+		traceSem:   make(chan struct{}, settings.maxTraceRequests),
+		metricsSem: make(chan struct{}, settings.maxMetricsRequests),
+		stopCh:     make(chan struct{}),
+	}
+	for i := range p.conns {
+		conn, err := dial()
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+		p.conns[i] = &pooledConn{conn: conn}
+	}
+	return p, nil
+}
+
+func (p *connPool) exportTraces(ctx context.Context, req *agenttracepb.ExportTraceServiceRequest) error {
+	return p.send(ctx, p.traceSem, func(conn ocagentConn) error {
+		return conn.ExportTraceServiceRequest(req)
+	})
+}
+
+func (p *connPool) exportMetrics(ctx context.Context, req *agentmetricspb.ExportMetricsServiceRequest) error {
+	return p.send(ctx, p.metricsSem, func(conn ocagentConn) error {
+		return conn.ExportMetricsServiceRequest(req)
+	})
+}
+
+func (p *connPool) send(ctx context.Context, sem chan struct{}, export func(ocagentConn) error) error {
+	select {
+	case <-p.stopCh:
+		return errPoolStopped
+	default:
+	}
+	select {
+	case <-p.stopCh:
+		return errPoolStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	case sem <- struct{}{}:
+	}
+	defer func() { <-sem }()
+
+	pc, conn := p.pick()
+	if conn == nil {
+		return errNoConnection
+	}
+	err := export(conn)
+	p.report(pc, conn, err)
+	return err
+}
+
+// pick returns the next slot having a connection, in round robin.
+func (p *connPool) pick() (*pooledConn, ocagentConn) {
+	start := atomic.AddUint32(&p.next, 1)
+	for i := range p.conns {
+		pc := p.conns[(int(start)+i)%len(p.conns)]
+		pc.mu.Lock()
+		conn := pc.conn
+		pc.mu.Unlock()
+		if conn != nil {
+			return pc, conn
+		}
+	}
+	return nil, nil
+}
+
+// report records the result of a request sent on the given connection of the
+// slot, the connection is replaced if it failed.
+func (p *connPool) report(pc *pooledConn, conn ocagentConn, err error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if err == nil {
+		pc.failures = 0
+		return
+	}
+	if !isConnectionError(err) || pc.conn != conn {
+		// The request was rejected or the connection is already replaced.
+		return
+	}
+	pc.failures++
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		// The connection is stopped with the pool.
+		return
+	}
+	pc.conn = nil
+	p.wg.Add(1)
+	go p.replace(pc, conn, pc.failures)
+}
+
+// replace stops the failed connection of the slot and dials a new one after
+// the backoff delay.
+func (p *connPool) replace(pc *pooledConn, failed ocagentConn, failures int) {
+	defer p.wg.Done()
+
+	if err := failed.Stop(); err != nil {
+		p.logger.Debug("Error stopping the failed OpenCensus exporter connection", zap.Error(err))
+	}
+	for {
+		timer := time.NewTimer(p.backoff(failures))
+		select {
+		case <-p.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		conn, err := p.dial()
+		if err == nil {
+			pc.mu.Lock()
+			pc.conn = conn
+			pc.mu.Unlock()
+			return
+		}
+		p.logger.Warn("Cannot create a new OpenCensus exporter connection", zap.Error(err))
+		failures++
+	}
+}
+
+// backoff returns the delay before replacing a connection after the given
+// number of consecutive failures, doubling from the reconnection delay up to
+// the maximum delay, then picking a random value between half of it and it.
+func (p *connPool) backoff(failures int) time.Duration {
+	delay := p.settings.reconnectionDelay
+	for i := 1; i < failures && delay < p.settings.maxReconnectionDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.settings.maxReconnectionDelay {
+		delay = p.settings.maxReconnectionDelay
+	}
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// stop stops all the connections of the pool, the pool can't be used after.
+func (p *connPool) stop() error {
+	var errs []error
+	p.stopOnce.Do(func() {
+		p.mu.Lock()
+		p.stopped = true
+		close(p.stopCh)
+		p.mu.Unlock()
+		p.wg.Wait()
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, pc := range p.conns {
+			if pc == nil {
+				continue
+			}
+			pc.mu.Lock()
+			conn := pc.conn
+			pc.mu.Unlock()
+			if conn == nil {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := conn.Stop(); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+	})
+	return oterr.CombineErrors(errs)
+}
+
+// isConnectionError returns whether the error is caused by the connection
+// rather than the data, e.g. the receiver went away or is unreachable.
+func isConnectionError(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.ResourceExhausted, codes.PermissionDenied, codes.Unauthenticated:
+		return false
+	}
+	return true
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[*Config]*connPool{}
+)
+
+// acquirePool returns the pool of the configuration, creating it for the
+// first exporter. Every acquired pool must be released.
+func acquirePool(logger *zap.Logger, cfg *Config, dial func() (ocagentConn, error)) (*connPool, error) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	p, ok := pools[cfg]
+	if !ok {
+		var err error
+		if p, err = newConnPool(logger, newPoolSettings(cfg), dial); err != nil {
+			return nil, err
+		}
+		pools[cfg] = p
+	}
+	p.refs++
+	return p, nil
+}
+
+// releasePool stops the pool of the configuration when its last exporter
+// releases it.
+func releasePool(cfg *Config, p *connPool) error {
+	poolsMu.Lock()
+	p.refs--
+	last := p.refs == 0
+	if last && pools[cfg] == p {
+		delete(pools, cfg)
+	}
+	poolsMu.Unlock()
+
+	if !last {
+		return nil
+	}
+	return p.stop()
+}
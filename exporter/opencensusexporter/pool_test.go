@@ -0,0 +1,270 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeConn struct {
+	id      int
+	err     error
+	block   chan struct{}
+	sent    int32
+	stopped int32
+}
+
+func (fc *fakeConn) export() error {
+	atomic.AddInt32(&fc.sent, 1)
+	if fc.block != nil {
+		<-fc.block
+	}
+	return fc.err
+}
+
+func (fc *fakeConn) ExportTraceServiceRequest(*agenttracepb.ExportTraceServiceRequest) error {
+	return fc.export()
+}
+
+func (fc *fakeConn) ExportMetricsServiceRequest(*agentmetricspb.ExportMetricsServiceRequest) error {
+	return fc.export()
+}
+
+func (fc *fakeConn) Stop() error {
+	atomic.AddInt32(&fc.stopped, 1)
+	return nil
+}
+
+// fakeDialer creates the connections returned by newConn.
+type fakeDialer struct {
+	mu      sync.Mutex
+	conns   []*fakeConn
+	newConn func(id int) *fakeConn
+}
+
+func (fd *fakeDialer) dial() (ocagentConn, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fc := &fakeConn{id: len(fd.conns)}
+	if fd.newConn != nil {
+		fc = fd.newConn(len(fd.conns))
+	}
+	fd.conns = append(fd.conns, fc)
+	return fc, nil
+}
+
+func (fd *fakeDialer) count() int {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	return len(fd.conns)
+}
+
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 500; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	return false
+}
+
+func testPoolSettings(numConnections int) poolSettings {
+	return newPoolSettings(&Config{
+		NumConnections:       numConnections,
+		ReconnectionDelay:    time.Millisecond,
+		MaxReconnectionDelay: 4 * time.Millisecond,
+	})
+}
+
+func TestNewPoolSettings(t *testing.T) {
+	assert.Equal(t, poolSettings{
+		numConnections:       defaultNumConnections,
+		maxTraceRequests:     defaultNumConnections,
+		maxMetricsRequests:   defaultNumConnections,
+		reconnectionDelay:    defaultReconnectionDelay,
+		maxReconnectionDelay: defaultMaxReconnectionDelay,
+	}, newPoolSettings(&Config{}))
+
+	assert.Equal(t, poolSettings{
+		numConnections:       3,
+		maxTraceRequests:     3,
+		maxMetricsRequests:   7,
+		reconnectionDelay:    time.Minute,
+		maxReconnectionDelay: time.Minute,
+	}, newPoolSettings(&Config{
+		NumWorkers:                   3,
+		MaxConcurrentMetricsRequests: 7,
+		ReconnectionDelay:            time.Minute,
+	}))
+}
+
+func TestConnPool_RoundRobin(t *testing.T) {
+	fd := &fakeDialer{}
+	p, err := newConnPool(zap.NewNop(), testPoolSettings(3), fd.dial)
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{}))
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.exportMetrics(context.Background(), &agentmetricspb.ExportMetricsServiceRequest{}))
+	}
+	require.Equal(t, 3, fd.count())
+	for _, fc := range fd.conns {
+		assert.EqualValues(t, 3, atomic.LoadInt32(&fc.sent))
+	}
+
+	require.NoError(t, p.stop())
+	for _, fc := range fd.conns {
+		assert.EqualValues(t, 1, atomic.LoadInt32(&fc.stopped))
+	}
+	assert.Equal(t, errPoolStopped, p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{}))
+}
+
+func TestConnPool_ConcurrencyPerDataType(t *testing.T) {
+	block := make(chan struct{})
+	fd := &fakeDialer{newConn: func(id int) *fakeConn {
+		return &fakeConn{id: id, block: block}
+	}}
+	settings := testPoolSettings(2)
+	settings.maxTraceRequests = 1
+	p, err := newConnPool(zap.NewNop(), settings, fd.dial)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{})
+	}()
+	require.True(t, waitFor(func() bool { return len(p.traceSem) == 1 }))
+
+	// The trace limit is reached but metrics can still be sent.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.exportTraces(ctx, &agenttracepb.ExportTraceServiceRequest{}))
+	go func() {
+		done <- p.exportMetrics(context.Background(), &agentmetricspb.ExportMetricsServiceRequest{})
+	}()
+	require.True(t, waitFor(func() bool { return len(p.metricsSem) == 1 }))
+
+	close(block)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+	require.NoError(t, p.stop())
+}
+
+func TestConnPool_ReplaceFailedConnection(t *testing.T) {
+	fd := &fakeDialer{newConn: func(id int) *fakeConn {
+		if id == 0 {
+			return &fakeConn{id: id, err: errors.New("no active connection")}
+		}
+		return &fakeConn{id: id}
+	}}
+	p, err := newConnPool(zap.NewNop(), testPoolSettings(1), fd.dial)
+	require.NoError(t, err)
+
+	assert.Error(t, p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{}))
+	// The connection is being replaced.
+	err = p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{})
+	if err != nil {
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+
+	require.True(t, waitFor(func() bool {
+		return p.exportTraces(context.Background(), &agenttracepb.ExportTraceServiceRequest{}) == nil
+	}))
+	require.Equal(t, 2, fd.count())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fd.conns[0].stopped))
+
+	require.NoError(t, p.stop())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fd.conns[0].stopped))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fd.conns[1].stopped))
+}
+
+func TestConnPool_KeepConnectionOnRejectedData(t *testing.T) {
+	fd := &fakeDialer{newConn: func(id int) *fakeConn {
+		return &fakeConn{id: id, err: status.Error(codes.InvalidArgument, "bad data")}
+	}}
+	p, err := newConnPool(zap.NewNop(), testPoolSettings(1), fd.dial)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, codes.InvalidArgument, status.Code(
+			p.exportMetrics(context.Background(), &agentmetricspb.ExportMetricsServiceRequest{})))
+	}
+	assert.Equal(t, 1, fd.count())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&fd.conns[0].sent))
+	require.NoError(t, p.stop())
+}
+
+func TestConnPool_Backoff(t *testing.T) {
+	p := &connPool{settings: poolSettings{
+		reconnectionDelay:    100 * time.Millisecond,
+		maxReconnectionDelay: time.Second,
+	}}
+	for failures, max := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		50: time.Second,
+	} {
+		for i := 0; i < 10; i++ {
+			delay := p.backoff(failures)
+			assert.True(t, delay >= max/2 && delay <= max, "failures %d: %v", failures, delay)
+		}
+	}
+}
+
+func TestAcquirePool_SharedByConfig(t *testing.T) {
+	fd := &fakeDialer{}
+	cfg := &Config{NumConnections: 2}
+
+	trace, err := acquirePool(zap.NewNop(), cfg, fd.dial)
+	require.NoError(t, err)
+	metrics, err := acquirePool(zap.NewNop(), cfg, fd.dial)
+	require.NoError(t, err)
+	assert.True(t, trace == metrics)
+	assert.Equal(t, 2, fd.count())
+
+	otherCfg := &Config{NumConnections: 2}
+	other, err := acquirePool(zap.NewNop(), otherCfg, fd.dial)
+	require.NoError(t, err)
+	assert.False(t, other == trace)
+	require.NoError(t, releasePool(otherCfg, other))
+
+	require.NoError(t, releasePool(cfg, trace))
+	assert.EqualValues(t, 0, atomic.LoadInt32(&fd.conns[0].stopped))
+	require.NoError(t, releasePool(cfg, metrics))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fd.conns[0].stopped))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fd.conns[1].stopped))
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	assert.Empty(t, pools)
+}
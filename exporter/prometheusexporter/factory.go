@@ -18,6 +18,7 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -76,6 +77,8 @@ func (f *Factory) CreateMetricsExporter(logger *zap.Logger, cfg configmodels.Exp
 		return nil, nil, err
 	}
 
+	// Listen right away so that an address already in use is reported when
+	// the exporter is built.
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, nil, err
@@ -86,12 +89,70 @@ func (f *Factory) CreateMetricsExporter(logger *zap.Logger, cfg configmodels.Exp
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", pe)
 
-	srv := &http.Server{Handler: mux}
-	go func() {
-		_ = srv.Serve(ln)
-	}()
+	pexp := &serverExporter{
+		prometheusExporter: prometheusExporter{exporter: pe},
+		addr:               addr,
+		handler:            mux,
+		ln:                 ln,
+	}
+
+	return pexp, pexp.stop, nil
+}
+
+// serverExporter is the exporter created by the factory. Its server is
+// started by the service, which is told when it fails.
+type serverExporter struct {
+	prometheusExporter
+
+	addr    string
+	handler http.Handler
 
-	pexp := &prometheusExporter{exporter: pe}
+	mu sync.Mutex
+	// ln is the listener of the server, nil once stopped or failed.
+	ln net.Listener
+}
+
+var _ exporter.Starter = (*serverExporter)(nil)
+
+// Start serves the metrics, listening again if the server failed before.
+func (se *serverExporter) Start(host exporter.Host) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	if se.ln == nil {
+		ln, err := net.Listen("tcp", se.addr)
+		if err != nil {
+			return err
+		}
+		se.ln = ln
+	}
+	go se.serve(se.ln, host)
+	return nil
+}
 
-	return pexp, ln.Close, nil
+func (se *serverExporter) serve(ln net.Listener, host exporter.Host) {
+	err := http.Serve(ln, se.handler)
+
+	se.mu.Lock()
+	stopped := se.ln != ln
+	if !stopped {
+		se.ln = nil
+		ln.Close()
+	}
+	se.mu.Unlock()
+
+	if !stopped {
+		host.ReportFatalError(err)
+	}
+}
+
+func (se *serverExporter) stop() error {
+	se.mu.Lock()
+	ln := se.ln
+	se.ln = nil
+	se.mu.Unlock()
+
+	if ln == nil {
+		return nil
+	}
+	return ln.Close()
 }
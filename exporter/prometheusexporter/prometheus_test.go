@@ -20,15 +20,19 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/internal/config/viperutils"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
 )
 
 func TestPrometheusExporter(t *testing.T) {
@@ -107,6 +111,7 @@ func TestPrometheusExporter_endToEnd(t *testing.T) {
 	factory := Factory{}
 	consumer, stopFunc, err := factory.CreateMetricsExporter(zap.NewNop(), config)
 	assert.Nil(t, err)
+	assert.Nil(t, consumer.(exporter.Starter).Start(receivertest.NewMockHost()))
 
 	defer stopFunc()
 
@@ -165,3 +170,51 @@ test_this_one_there_where_{arch="x86",code="one",foo="bar",os="windows"} 99
 		t.Errorf("Response mismatch\nGot:\n%s\n\nWant:\n%s", got, want)
 	}
 }
+
+// errorsHost records the fatal errors reported by the exporter.
+type errorsHost struct {
+	errs chan error
+}
+
+func (h *errorsHost) Context() context.Context {
+	return context.Background()
+}
+
+func (h *errorsHost) ReportFatalError(err error) {
+	h.errs <- err
+}
+
+func TestPrometheusExporter_restart(t *testing.T) {
+	config := &Config{Endpoint: "localhost:7778"}
+
+	factory := Factory{}
+	consumer, stopFunc, err := factory.CreateMetricsExporter(zap.NewNop(), config)
+	require.NoError(t, err)
+	defer stopFunc()
+
+	se := consumer.(*serverExporter)
+	host := &errorsHost{errs: make(chan error, 1)}
+	require.NoError(t, se.Start(host))
+
+	// The server fails when its listener is closed by something else than
+	// the stop function.
+	se.mu.Lock()
+	se.ln.Close()
+	se.mu.Unlock()
+	assert.Error(t, <-host.errs)
+
+	// Starting it again listens again.
+	require.NoError(t, se.Start(host))
+	res, err := http.Get("http://localhost:7778/metrics")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res.Body.Close()
+
+	// Stopping it is not reported.
+	require.NoError(t, stopFunc())
+	select {
+	case err := <-host.errs:
+		t.Fatalf("Unexpected fatal error: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+)
+
+// Config defines configuration for the span metrics connector.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Interval is the time between two emissions of the metrics.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// LatencyBuckets are the bounds of the buckets of the latency histograms.
+	LatencyBuckets []time.Duration `mapstructure:"latency-buckets"`
+}
+
+var _ exporter.ConnectorConfig = (*Config)(nil)
+
+// ConnectorDataTypes returns the data types sent by the connector, metrics.
+func (cfg *Config) ConnectorDataTypes() []configmodels.DataType {
+	return []configmodels.DataType{configmodels.MetricsDataType}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	exporters[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["span-metrics"]
+	assert.Equal(t, factory.CreateDefaultConfig(), e0)
+
+	e1 := cfg.Exporters["span-metrics/2"]
+	assert.Equal(t,
+		&Config{
+			ExporterSettings: configmodels.ExporterSettings{
+				NameVal: "span-metrics/2",
+				TypeVal: "span-metrics",
+			},
+			Interval:       time.Minute,
+			LatencyBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+		},
+		e1)
+	assert.Equal(t, []string{"span-metrics/2"}, cfg.Pipelines["metrics"].Receivers)
+}
+
+func TestLoadConfig_TracesPipeline(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	exporters[typeStr] = &Factory{}
+	_, err = config.LoadConfigFile(
+		t, path.Join(".", "testdata", "traces-pipeline.yaml"), receivers, processors, exporters,
+	)
+
+	// The connector only sends metrics.
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanmetricsconnector implements a connector aggregating the spans
+// exported by trace pipelines in request, error and latency metrics, per
+// service, operation and status, sent to the metrics pipelines having it as a
+// receiver.
+package spanmetricsconnector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/spanmetrics"
+)
+
+type connector struct {
+	logger     *zap.Logger
+	aggregator *spanmetrics.Aggregator
+	stopCh     chan struct{}
+	stopOnce   sync.Once
+
+	mu      sync.Mutex
+	metrics consumer.MetricsConsumer
+}
+
+var _ consumer.TraceConsumer = (*connector)(nil)
+var _ exporter.MetricsConnector = (*connector)(nil)
+
+func newConnector(logger *zap.Logger, cfg Config) (*connector, error) {
+	if cfg.Interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	aggregator, err := spanmetrics.NewAggregator(cfg.LatencyBuckets)
+	if err != nil {
+		return nil, err
+	}
+	return &connector{
+		logger:     logger,
+		aggregator: aggregator,
+		stopCh:     make(chan struct{}),
+	}, nil
+}
+
+func (c *connector) SetMetricsConsumer(mc consumer.MetricsConsumer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = mc
+}
+
+func (c *connector) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	c.aggregator.Add(td)
+	return nil
+}
+
+func (c *connector) emitEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.emit(now)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// emit sends the cumulative metrics to the connected metrics pipelines.
+func (c *connector) emit(now time.Time) {
+	c.mu.Lock()
+	mc := c.metrics
+	c.mu.Unlock()
+
+	if mc == nil {
+		return
+	}
+	md := c.aggregator.MetricsData(now)
+	if len(md.Metrics) == 0 {
+		return
+	}
+	if err := mc.ConsumeMetricsData(context.Background(), md); err != nil {
+		c.logger.Warn("Failed to emit the span metrics", zap.Error(err))
+	}
+}
+
+func (c *connector) stop() error {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+	return nil
+}
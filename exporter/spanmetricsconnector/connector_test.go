@@ -0,0 +1,65 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/internal"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/spanmetrics"
+)
+
+func TestConnector(t *testing.T) {
+	c, err := newConnector(zap.NewNop(), Config{Interval: time.Minute})
+	require.NoError(t, err)
+
+	// Nothing is emitted before the metrics pipelines are connected.
+	now := time.Unix(1000, 0)
+	td := consumerdata.TraceData{
+		Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}},
+		Spans: []*tracepb.Span{{
+			Name:      &tracepb.TruncatableString{Value: "GET /"},
+			StartTime: internal.TimeToTimestamp(now),
+			EndTime:   internal.TimeToTimestamp(now.Add(5 * time.Millisecond)),
+		}},
+	}
+	require.NoError(t, c.ConsumeTraceData(context.Background(), td))
+	c.emit(now)
+
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	c.SetMetricsConsumer(metricsSink)
+	c.emit(now)
+	got := metricsSink.AllMetrics()
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Metrics, 3)
+	requests := got[0].Metrics[0]
+	assert.Equal(t, spanmetrics.MetricRequests, requests.MetricDescriptor.Name)
+	require.Len(t, requests.Timeseries, 1)
+	assert.Equal(t, int64(1), requests.Timeseries[0].Points[0].GetInt64Value())
+
+	assert.NoError(t, c.stop())
+	// Stopping twice is fine.
+	assert.NoError(t, c.stop())
+}
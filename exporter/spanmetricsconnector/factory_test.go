@@ -0,0 +1,55 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateExporter(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	te, stop, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.Implements(t, (*exporter.MetricsConnector)(nil), te)
+	assert.NoError(t, stop())
+
+	_, _, err = factory.CreateMetricsExporter(zap.NewNop(), cfg)
+	assert.Equal(t, configerror.ErrDataTypeIsNotSupported, err)
+
+	cfg.Interval = 0
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+
+	cfg.Interval = time.Second
+	cfg.LatencyBuckets = []time.Duration{time.Second, time.Millisecond}
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+}
@@ -25,4 +25,15 @@ type Config struct {
 	// The URL to send the Zipkin trace data to (e.g.:
 	// http://some.url:9411/api/v2/spans).
 	URL string `mapstructure:"url"`
+
+	// ErrorTag is the value of the "error" tag of the spans with an error
+	// status: "code", the canonical code name of the status, e.g.
+	// "NOT_FOUND", or "message", the message of the status as set by most
+	// Zipkin instrumentations.
+	ErrorTag string `mapstructure:"error-tag"`
+
+	// StripStatusTags removes the "status.code", "status.message" and
+	// "census.status_*" tags duplicating the status of the spans, and the
+	// "opencensus.status_description" tag if the "error" tag is the message.
+	StripStatusTags bool `mapstructure:"strip-status-tags"`
 }
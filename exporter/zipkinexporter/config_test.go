@@ -48,6 +48,8 @@ func TestLoadConfig(t *testing.T) {
 	e1 := cfg.Exporters["zipkin/2"]
 	assert.Equal(t, "zipkin/2", e1.(*Config).Name())
 	assert.Equal(t, "https://somedest:1234/api/v2/spans", e1.(*Config).URL)
+	assert.Equal(t, errorTagMessage, e1.(*Config).ErrorTag)
+	assert.True(t, e1.(*Config).StripStatusTags)
 	_, _, err = factory.CreateTraceExporter(zap.NewNop(), e1)
 	require.NoError(t, err)
 }
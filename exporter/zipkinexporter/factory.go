@@ -16,9 +16,11 @@ package zipkinexporter
 
 import (
 	"errors"
+	"fmt"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
@@ -32,6 +34,9 @@ const (
 
 // Factory is the factory for OpenCensus exporter.
 type Factory struct {
+	// Holder gives the factory the build of the service, sent as the user
+	// agent of the requests.
+	buildinfo.Holder
 }
 
 // Type gets the type of the Exporter config created by this factory.
@@ -46,6 +51,7 @@ func (f *Factory) CreateDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		ErrorTag: errorTagCode,
 	}
 }
 
@@ -57,10 +63,19 @@ func (f *Factory) CreateTraceExporter(logger *zap.Logger, config configmodels.Ex
 		return nil, nil, errors.New("exporter config requires a non-empty 'url'") // TODO: better error
 	}
 
-	ze, err := newZipkinExporter(cfg.URL, "<missing service name>", 0)
+	switch cfg.ErrorTag {
+	case "", errorTagCode, errorTagMessage:
+	default:
+		return nil, nil, fmt.Errorf("exporter %q has invalid error-tag %q, it must be %q or %q",
+			cfg.Name(), cfg.ErrorTag, errorTagCode, errorTagMessage)
+	}
+
+	ze, err := newZipkinExporter(cfg.URL, "<missing service name>", 0, f.BuildInfo().UserAgent())
 	if err != nil {
 		return nil, nil, err
 	}
+	ze.errorTag = cfg.ErrorTag
+	ze.stripStatusTags = cfg.StripStatusTags
 
 	return ze, ze.stop, nil
 }
@@ -29,6 +29,21 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NotNil(t, cfg, "failed to create default config")
 }
 
+func TestCreateTraceExporterErrorTag(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.URL = "http://some.location.org:9411/api/v2/spans"
+
+	cfg.ErrorTag = errorTagMessage
+	_, stopFn, err := factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, stopFn())
+
+	cfg.ErrorTag = "bool"
+	_, _, err = factory.CreateTraceExporter(zap.NewNop(), cfg)
+	assert.Error(t, err)
+}
+
 func TestCreateMetricsExporter(t *testing.T) {
 	factory := Factory{}
 	cfg := factory.CreateDefaultConfig()
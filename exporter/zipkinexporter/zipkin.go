@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/spf13/viper"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
@@ -55,6 +57,12 @@ type zipkinExporter struct {
 
 	defaultServiceName string
 
+	// errorTag is the value of the "error" tag of the spans with an error
+	// status, errorTagCode or errorTagMessage.
+	errorTag string
+	// stripStatusTags removes the tags of the spans duplicating their status.
+	stripStatusTags bool
+
 	reporter zipkinreporter.Reporter
 }
 
@@ -98,7 +106,7 @@ func ZipkinExportersFromViper(v *viper.Viper) (tps []consumer.TraceConsumer, mps
 	if zc.UploadPeriod != nil && *zc.UploadPeriod > 0 {
 		uploadPeriod = *zc.UploadPeriod
 	}
-	zle, err := newZipkinExporter(endpoint, serviceName, uploadPeriod)
+	zle, err := newZipkinExporter(endpoint, serviceName, uploadPeriod, buildinfo.Default().UserAgent())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("cannot configure Zipkin exporter: %v", err)
 	}
@@ -107,8 +115,10 @@ func ZipkinExportersFromViper(v *viper.Viper) (tps []consumer.TraceConsumer, mps
 	return
 }
 
-func newZipkinExporter(finalEndpointURI, defaultServiceName string, uploadPeriod time.Duration) (*zipkinExporter, error) {
-	var opts []zipkinhttp.ReporterOption
+func newZipkinExporter(finalEndpointURI, defaultServiceName string, uploadPeriod time.Duration, userAgent string) (*zipkinExporter, error) {
+	opts := []zipkinhttp.ReporterOption{
+		zipkinhttp.Client(&http.Client{Transport: buildinfo.NewUserAgentTransport(userAgent, nil)}),
+	}
 	if uploadPeriod > 0 {
 		opts = append(opts, zipkinhttp.BatchInterval(uploadPeriod))
 	}
@@ -216,6 +226,11 @@ func (ze *zipkinExporter) ConsumeTraceData(ctx context.Context, td consumerdata.
 	return nil
 }
 
+// Capabilities returns the consumer.Capabilities of the exporter, the spans are only converted to Zipkin spans.
+func (ze *zipkinExporter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
 // This code from down below is mostly copied from
 // https://github.com/census-instrumentation/opencensus-go/blob/96e75b88df843315da521168a0e3b11792088728/exporter/zipkin/zipkin.go#L57-L194
 // but that is because the Zipkin Go exporter requires process to change
@@ -235,6 +250,24 @@ const (
 	statusDescriptionTagKey = "opencensus.status_description"
 )
 
+// Values of the error-tag setting.
+const (
+	// errorTagCode is the canonical code name of the status, e.g. "NOT_FOUND".
+	errorTagCode = "code"
+	// errorTagMessage is the message of the status, or the canonical code
+	// name if it has no message.
+	errorTagMessage = "message"
+)
+
+// statusTagKeys are the keys of the tags set by the Zipkin instrumentations
+// that duplicate the status of the spans.
+var statusTagKeys = []string{
+	tracetranslator.TagStatusCode,
+	tracetranslator.TagStatusMsg,
+	tracetranslator.TagZipkinCensusCode,
+	tracetranslator.TagZipkinCensusMsg,
+}
+
 var (
 	sampledTrue    = true
 	canonicalCodes = [...]string{
@@ -265,6 +298,15 @@ func canonicalCodeString(code int32) string {
 	return canonicalCodes[code]
 }
 
+// errorTagValue returns the value of the "error" tag of a span with an
+// error status.
+func (ze *zipkinExporter) errorTagValue(status trace.Status) string {
+	if ze.errorTag == errorTagMessage && status.Message != "" {
+		return status.Message
+	}
+	return canonicalCodeString(status.Code)
+}
+
 func convertTraceID(t trace.TraceID) zipkinmodel.TraceID {
 	h, l, _ := tracetranslator.BytesToUInt64TraceID(t[:])
 	return zipkinmodel.TraceID{High: h, Low: l}
@@ -363,10 +405,17 @@ func (ze *zipkinExporter) zipkinSpan(node *commonpb.Node, s *trace.SpanData) (zc
 		if z.Tags == nil {
 			z.Tags = make(map[string]string, 2)
 		}
+		if ze.stripStatusTags {
+			for _, key := range statusTagKeys {
+				delete(z.Tags, key)
+			}
+		}
+		messageInErrorTag := false
 		if s.Status.Code != 0 {
-			z.Tags[statusCodeTagKey] = canonicalCodeString(s.Status.Code)
+			z.Tags[statusCodeTagKey] = ze.errorTagValue(s.Status)
+			messageInErrorTag = ze.errorTag == errorTagMessage
 		}
-		if s.Status.Message != "" {
+		if s.Status.Message != "" && !(ze.stripStatusTags && messageInErrorTag) {
 			z.Tags[statusDescriptionTagKey] = s.Status.Message
 		}
 	}
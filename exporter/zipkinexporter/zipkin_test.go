@@ -30,6 +30,7 @@ import (
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	zipkinreporter "github.com/openzipkin/zipkin-go/reporter"
+	"go.opencensus.io/trace"
 
 	"github.com/open-telemetry/opentelemetry-service/internal/config/viperutils"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
@@ -115,6 +116,59 @@ func TestZipkinEndpointFromNode(t *testing.T) {
 	}
 }
 
+func TestZipkinSpanStatusTags(t *testing.T) {
+	sd := &trace.SpanData{
+		Attributes: map[string]interface{}{
+			"status.code":      int64(5),
+			"http.status_code": int64(404),
+		},
+		Status: trace.Status{Code: trace.StatusCodeNotFound, Message: "no such user"},
+	}
+
+	tests := []struct {
+		name string
+		ze   *zipkinExporter
+		want map[string]string
+	}{
+		{
+			name: "code",
+			ze:   &zipkinExporter{errorTag: errorTagCode},
+			want: map[string]string{
+				"status.code":                   "5",
+				"http.status_code":              "404",
+				"error":                         "NOT_FOUND",
+				"opencensus.status_description": "no such user",
+			},
+		},
+		{
+			name: "message",
+			ze:   &zipkinExporter{errorTag: errorTagMessage},
+			want: map[string]string{
+				"status.code":                   "5",
+				"http.status_code":              "404",
+				"error":                         "no such user",
+				"opencensus.status_description": "no such user",
+			},
+		},
+		{
+			name: "message stripped",
+			ze:   &zipkinExporter{errorTag: errorTagMessage, stripStatusTags: true},
+			want: map[string]string{
+				"http.status_code": "404",
+				"error":            "no such user",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z := tt.ze.zipkinSpan(nil, sd)
+			if !reflect.DeepEqual(z.Tags, tt.want) {
+				t.Errorf("got tags %v, want %v", z.Tags, tt.want)
+			}
+		})
+	}
+}
+
 // This function tests that Zipkin spans that are received then processed roundtrip
 // back to almost the same JSON with differences:
 // a) Go's net.IP.String intentional shortens 0s with "::" but also converts to hex values
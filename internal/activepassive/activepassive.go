@@ -0,0 +1,245 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package activepassive coordinates a pair of collectors where only the active
+// one runs its receivers, the passive one is a warm standby that takes over
+// when the active one fails.
+//
+// The active collector accepts TCP connections on its heartbeat endpoint. The
+// passive collector connects to it periodically and becomes active once the
+// peer could not be reached during the failover timeout. A collector never
+// becomes active while its peer is, so the original active collector restarts
+// as passive after a failover.
+package activepassive
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	roleCfg              = "ha-role"
+	listenEndpointCfg    = "ha-listen-endpoint"
+	peerEndpointCfg      = "ha-peer-endpoint"
+	heartbeatIntervalCfg = "ha-heartbeat-interval"
+	failoverTimeoutCfg   = "ha-failover-timeout"
+
+	// RoleActive is the role of the collector that starts its receivers
+	// unless its peer is already active.
+	RoleActive = "active"
+	// RolePassive is the role of the collector that starts its receivers
+	// only after the active one fails.
+	RolePassive = "passive"
+
+	defaultHeartbeatInterval = time.Second
+	defaultFailoverTimeout   = 5 * time.Second
+)
+
+// AddFlags adds the command-line flags used to configure the active/passive
+// pair to the given flag set.
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(
+		roleCfg,
+		RoleActive,
+		"Role of the collector in an active/passive pair, \""+RoleActive+"\" or \""+RolePassive+"\".")
+	flags.String(
+		listenEndpointCfg,
+		"",
+		"Endpoint (host:port) where the collector accepts heartbeats from its peer while it is active.")
+	flags.String(
+		peerEndpointCfg,
+		"",
+		"Heartbeat endpoint (host:port) of the peer collector, the active/passive pair is disabled if not specified.")
+	flags.Duration(
+		heartbeatIntervalCfg,
+		defaultHeartbeatInterval,
+		"Interval between the heartbeats sent by the passive collector to the active one.")
+	flags.Duration(
+		failoverTimeoutCfg,
+		defaultFailoverTimeout,
+		"Time without successful heartbeat after which the passive collector becomes active.")
+}
+
+// Config is the configuration of a collector in an active/passive pair.
+type Config struct {
+	Role              string
+	ListenEndpoint    string
+	PeerEndpoint      string
+	HeartbeatInterval time.Duration
+	FailoverTimeout   time.Duration
+}
+
+// Coordinator decides when the collector becomes active.
+type Coordinator struct {
+	config Config
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	ln       net.Listener
+	active   bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewFromViper creates a Coordinator according to the configuration in the
+// given viper, or returns nil if the active/passive pair is not configured.
+func NewFromViper(v *viper.Viper, logger *zap.Logger) (*Coordinator, error) {
+	if v.GetString(peerEndpointCfg) == "" {
+		return nil, nil
+	}
+	return New(Config{
+		Role:              v.GetString(roleCfg),
+		ListenEndpoint:    v.GetString(listenEndpointCfg),
+		PeerEndpoint:      v.GetString(peerEndpointCfg),
+		HeartbeatInterval: v.GetDuration(heartbeatIntervalCfg),
+		FailoverTimeout:   v.GetDuration(failoverTimeoutCfg),
+	}, logger)
+}
+
+// New creates a Coordinator with the given configuration.
+func New(config Config, logger *zap.Logger) (*Coordinator, error) {
+	if config.Role != RoleActive && config.Role != RolePassive {
+		return nil, fmt.Errorf("invalid %s %q, must be %q or %q", roleCfg, config.Role, RoleActive, RolePassive)
+	}
+	if config.ListenEndpoint == "" || config.PeerEndpoint == "" {
+		return nil, fmt.Errorf("both %s and %s must be specified", listenEndpointCfg, peerEndpointCfg)
+	}
+	if config.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("%s must be positive", heartbeatIntervalCfg)
+	}
+	if config.FailoverTimeout < config.HeartbeatInterval {
+		return nil, fmt.Errorf("%s must be greater than %s", failoverTimeoutCfg, heartbeatIntervalCfg)
+	}
+	return &Coordinator{
+		config: config,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start calls activate, e.g. to start the receivers, once the collector
+// becomes active. It doesn't wait for the collector to be active, errors
+// encountered later are reported to asyncErrorChannel.
+func (c *Coordinator) Start(asyncErrorChannel chan<- error, activate func() error) error {
+	if c.config.Role == RoleActive && !c.peerAlive() {
+		return c.becomeActive(activate)
+	}
+
+	c.logger.Info("Collector is passive, waiting for the active peer to fail",
+		zap.String("peer", c.config.PeerEndpoint))
+	go func() {
+		if err := c.waitForPeerFailure(); err != nil {
+			return
+		}
+		if err := c.becomeActive(activate); err != nil {
+			asyncErrorChannel <- err
+		}
+	}()
+	return nil
+}
+
+// Active returns whether the collector is active.
+func (c *Coordinator) Active() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.active
+}
+
+// Stop stops the heartbeats, the peer can then become active.
+func (c *Coordinator) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+		c.mu.Lock()
+		if c.ln != nil {
+			_ = c.ln.Close()
+		}
+		c.mu.Unlock()
+	})
+}
+
+var errStopped = errors.New("active/passive coordinator stopped")
+
+// waitForPeerFailure returns once the peer could not be reached during the
+// failover timeout.
+func (c *Coordinator) waitForPeerFailure() error {
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	lastSeen := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			if c.peerAlive() {
+				lastSeen = time.Now()
+			} else if time.Since(lastSeen) >= c.config.FailoverTimeout {
+				c.logger.Warn("Active peer is not reachable, failing over",
+					zap.String("peer", c.config.PeerEndpoint),
+					zap.Duration("since", time.Since(lastSeen)))
+				return nil
+			}
+		case <-c.stopCh:
+			return errStopped
+		}
+	}
+}
+
+func (c *Coordinator) becomeActive(activate func() error) error {
+	c.mu.Lock()
+	select {
+	case <-c.stopCh:
+		c.mu.Unlock()
+		return errStopped
+	default:
+	}
+	ln, err := net.Listen("tcp", c.config.ListenEndpoint)
+	if err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("failed to listen for heartbeats on %q: %v", c.config.ListenEndpoint, err)
+	}
+	c.ln = ln
+	c.active = true
+	c.mu.Unlock()
+
+	go acceptHeartbeats(ln)
+	c.logger.Info("Collector is active", zap.String("heartbeat-endpoint", c.config.ListenEndpoint))
+	return activate()
+}
+
+// acceptHeartbeats accepts the connections of the peer until the listener is
+// closed. A successful connection is the heartbeat, nothing is exchanged.
+func acceptHeartbeats(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	}
+}
+
+func (c *Coordinator) peerAlive() bool {
+	conn, err := net.DialTimeout("tcp", c.config.PeerEndpoint, c.config.HeartbeatInterval)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
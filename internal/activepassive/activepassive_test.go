@@ -0,0 +1,121 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activepassive
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+)
+
+func TestNew(t *testing.T) {
+	valid := Config{
+		Role:              RolePassive,
+		ListenEndpoint:    "localhost:1",
+		PeerEndpoint:      "localhost:2",
+		HeartbeatInterval: time.Second,
+		FailoverTimeout:   2 * time.Second,
+	}
+	_, err := New(valid, zap.NewNop())
+	assert.NoError(t, err)
+
+	invalid := []func(*Config){
+		func(c *Config) { c.Role = "standby" },
+		func(c *Config) { c.ListenEndpoint = "" },
+		func(c *Config) { c.PeerEndpoint = "" },
+		func(c *Config) { c.HeartbeatInterval = 0 },
+		func(c *Config) { c.FailoverTimeout = time.Millisecond },
+	}
+	for i, mutate := range invalid {
+		cfg := valid
+		mutate(&cfg)
+		_, err := New(cfg, zap.NewNop())
+		assert.Error(t, err, "config %d", i)
+	}
+}
+
+type testCollector struct {
+	coordinator *Coordinator
+	activated   int32
+}
+
+func startCoordinator(t *testing.T, role, listen, peer string) *testCollector {
+	c, err := New(Config{
+		Role:              role,
+		ListenEndpoint:    listen,
+		PeerEndpoint:      peer,
+		HeartbeatInterval: 10 * time.Millisecond,
+		FailoverTimeout:   50 * time.Millisecond,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	p := &testCollector{coordinator: c}
+	errCh := make(chan error, 1)
+	require.NoError(t, c.Start(errCh, func() error {
+		atomic.AddInt32(&p.activated, 1)
+		return nil
+	}))
+	return p
+}
+
+func (p *testCollector) waitActivated(t *testing.T) {
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&p.activated) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&p.activated))
+	require.True(t, p.coordinator.Active())
+}
+
+func TestFailover(t *testing.T) {
+	addrA := testutils.GetAvailableLocalAddress(t)
+	addrB := testutils.GetAvailableLocalAddress(t)
+
+	a := startCoordinator(t, RoleActive, addrA, addrB)
+	a.waitActivated(t)
+
+	b := startCoordinator(t, RolePassive, addrB, addrA)
+	defer b.coordinator.Stop()
+	// The passive collector stays passive while the active one is alive.
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&b.activated))
+	assert.False(t, b.coordinator.Active())
+
+	a.coordinator.Stop()
+	b.waitActivated(t)
+
+	// Restarting the original active collector doesn't take over again.
+	a = startCoordinator(t, RoleActive, addrA, addrB)
+	defer a.coordinator.Stop()
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&a.activated))
+	assert.False(t, a.coordinator.Active())
+}
+
+func TestStopWhilePassive(t *testing.T) {
+	addrA := testutils.GetAvailableLocalAddress(t)
+	addrB := testutils.GetAvailableLocalAddress(t)
+
+	b := startCoordinator(t, RolePassive, addrB, addrA)
+	b.coordinator.Stop()
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&b.activated))
+}
@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memorybudget implements a memory budget shared by the components
+// that buffer data (queues, batches, traces waiting for a sampling decision),
+// so that together they stay under a single limit.
+package memorybudget
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Budget is the number of bytes that can be buffered by all the Accounts
+// registered with it.
+type Budget struct {
+	limit int64
+	used  int64
+
+	mu       sync.Mutex
+	accounts map[*Account]struct{}
+}
+
+// NewBudget creates a Budget with the given limit in bytes. A limit <= 0
+// means the budget is unlimited.
+func NewBudget(limitBytes int64) *Budget {
+	return &Budget{
+		limit:    limitBytes,
+		accounts: make(map[*Account]struct{}),
+	}
+}
+
+var defaultBudget = NewBudget(0)
+
+// Default returns the process wide Budget, unlimited unless SetLimit is called.
+func Default() *Budget {
+	return defaultBudget
+}
+
+// SetLimit changes the limit in bytes of the Budget, see NewBudget. Bytes
+// already reserved are kept even if they exceed the new limit.
+func (b *Budget) SetLimit(limitBytes int64) {
+	atomic.StoreInt64(&b.limit, limitBytes)
+}
+
+// Limit returns the limit in bytes of the Budget, <= 0 if unlimited.
+func (b *Budget) Limit() int64 {
+	return atomic.LoadInt64(&b.limit)
+}
+
+// Used returns the number of bytes reserved by all the Accounts.
+func (b *Budget) Used() int64 {
+	return atomic.LoadInt64(&b.used)
+}
+
+// Register creates an Account for the component with the given name.
+func (b *Budget) Register(name string) *Account {
+	a := &Account{budget: b, name: name}
+	b.mu.Lock()
+	b.accounts[a] = struct{}{}
+	b.mu.Unlock()
+	return a
+}
+
+// Usage returns the number of bytes reserved by each registered component.
+func (b *Budget) Usage() map[string]int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	usage := make(map[string]int64, len(b.accounts))
+	for a := range b.accounts {
+		usage[a.name] += a.Used()
+	}
+	return usage
+}
+
+func (b *Budget) reserve(bytes int64) bool {
+	for {
+		used := atomic.LoadInt64(&b.used)
+		limit := b.Limit()
+		if limit > 0 && used+bytes > limit {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&b.used, used, used+bytes) {
+			return true
+		}
+	}
+}
+
+// Account tracks the bytes buffered by a single component.
+type Account struct {
+	budget *Budget
+	name   string
+	used   int64
+}
+
+// Enabled returns whether the budget is limited. Components can use it to
+// skip estimating the size of the data when there is no limit.
+func (a *Account) Enabled() bool {
+	return a.budget.Limit() > 0
+}
+
+// Reserve reserves the given number of bytes. It returns false, without
+// reserving anything, if the budget would be exceeded; the component should
+// then drop or flush the data instead of buffering it.
+func (a *Account) Reserve(bytes int64) bool {
+	if !a.budget.reserve(bytes) {
+		return false
+	}
+	atomic.AddInt64(&a.used, bytes)
+	return true
+}
+
+// Release returns to the budget bytes previously reserved.
+func (a *Account) Release(bytes int64) {
+	atomic.AddInt64(&a.used, -bytes)
+	atomic.AddInt64(&a.budget.used, -bytes)
+}
+
+// Used returns the number of bytes reserved by the Account.
+func (a *Account) Used() int64 {
+	return atomic.LoadInt64(&a.used)
+}
+
+// Unregister releases all the bytes reserved by the Account and removes it
+// from the budget, e.g. when the component is shut down with data still
+// buffered.
+func (a *Account) Unregister() {
+	used := atomic.SwapInt64(&a.used, 0)
+	atomic.AddInt64(&a.budget.used, -used)
+	a.budget.mu.Lock()
+	delete(a.budget.accounts, a)
+	a.budget.mu.Unlock()
+}
@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorybudget
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(100)
+	queue := b.Register("queue")
+	batch := b.Register("batch")
+	assert.True(t, queue.Enabled())
+
+	assert.True(t, queue.Reserve(60))
+	assert.True(t, batch.Reserve(40))
+	// Both accounts share the same limit.
+	assert.False(t, batch.Reserve(1))
+	assert.False(t, queue.Reserve(1))
+	assert.Equal(t, int64(100), b.Used())
+	assert.Equal(t, map[string]int64{"queue": 60, "batch": 40}, b.Usage())
+
+	queue.Release(10)
+	assert.True(t, batch.Reserve(10))
+	assert.Equal(t, int64(50), queue.Used())
+	assert.Equal(t, int64(50), batch.Used())
+
+	queue.Unregister()
+	assert.Equal(t, int64(50), b.Used())
+	assert.Equal(t, map[string]int64{"batch": 50}, b.Usage())
+}
+
+func TestBudget_Unlimited(t *testing.T) {
+	b := NewBudget(0)
+	a := b.Register("queue")
+	assert.False(t, a.Enabled())
+	assert.True(t, a.Reserve(1<<40))
+
+	b.SetLimit(10)
+	assert.True(t, a.Enabled())
+	assert.False(t, a.Reserve(1))
+	a.Release(1 << 40)
+	assert.True(t, a.Reserve(10))
+}
+
+func TestBudget_Concurrent(t *testing.T) {
+	b := NewBudget(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := b.Register("worker")
+			for j := 0; j < 1000; j++ {
+				if a.Reserve(7) {
+					a.Release(7)
+				}
+			}
+			assert.True(t, a.Reserve(100))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1000), b.Used())
+}
@@ -0,0 +1,152 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runtimelimits reads the memory and CPU available to the process,
+// taking the limits of its cgroup into account, to size the memory ballast
+// and GOMAXPROCS.
+package runtimelimits
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// The files the limits are read from, variables to be changed by the tests.
+var (
+	memInfoPath           = "/proc/meminfo"
+	cgroupV1MemoryPath    = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+	cgroupV2MemoryPath    = "/sys/fs/cgroup/memory.max"
+	cgroupV1CPUQuotaPath  = "/sys/fs/cgroup/cpu/cpu.cfs_quota_us"
+	cgroupV1CPUPeriodPath = "/sys/fs/cgroup/cpu/cpu.cfs_period_us"
+	cgroupV2CPUPath       = "/sys/fs/cgroup/cpu.max"
+)
+
+// TotalMemory returns the memory available to the process in bytes: the
+// total memory of the host, or the memory limit of the cgroup of the process
+// if lower.
+func TotalMemory() (uint64, error) {
+	total, err := hostMemory()
+	if err != nil {
+		return 0, err
+	}
+	if limit, ok := cgroupMemoryLimit(); ok && limit < total {
+		return limit, nil
+	}
+	return total, nil
+}
+
+func hostMemory() (uint64, error) {
+	f, err := os.Open(memInfoPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// E.g. "MemTotal:       16316412 kB".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse MemTotal of %s: %v", memInfoPath, err)
+		}
+		return kb * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no MemTotal in %s", memInfoPath)
+}
+
+// cgroupMemoryLimit returns the memory limit of the cgroup of the process,
+// false if there is none.
+func cgroupMemoryLimit() (uint64, bool) {
+	for _, path := range []string{cgroupV2MemoryPath, cgroupV1MemoryPath} {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// The cgroup v1 file holds a very large number when there is no
+		// limit, higher than the memory of the host.
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			// "max" in the cgroup v2 file.
+			return 0, false
+		}
+		return limit, true
+	}
+	return 0, false
+}
+
+// MaxProcs returns the number of CPUs matching the CPU quota of the cgroup
+// of the process, rounded down, at least 1 and at most the number of CPUs of
+// the host. It returns false if there is no quota.
+func MaxProcs() (int, bool) {
+	quota, period, ok := cgroupCPUQuota()
+	if !ok {
+		return 0, false
+	}
+	procs := int(quota / period)
+	if procs < 1 {
+		procs = 1
+	}
+	if procs > runtime.NumCPU() {
+		procs = runtime.NumCPU()
+	}
+	return procs, true
+}
+
+// cgroupCPUQuota returns the CPU time the cgroup of the process can use
+// every period, false if there is no quota.
+func cgroupCPUQuota() (quota, period int64, ok bool) {
+	if b, err := ioutil.ReadFile(cgroupV2CPUPath); err == nil {
+		// E.g. "200000 100000", or "max 100000" if there is no quota.
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 {
+			return 0, 0, false
+		}
+		return parseQuota(fields[0], fields[1])
+	}
+
+	q, err := ioutil.ReadFile(cgroupV1CPUQuotaPath)
+	if err != nil {
+		return 0, 0, false
+	}
+	p, err := ioutil.ReadFile(cgroupV1CPUPeriodPath)
+	if err != nil {
+		return 0, 0, false
+	}
+	// The quota is -1 if there is none.
+	return parseQuota(strings.TrimSpace(string(q)), strings.TrimSpace(string(p)))
+}
+
+func parseQuota(q, p string) (quota, period int64, ok bool) {
+	quota, err := strconv.ParseInt(q, 10, 64)
+	if err != nil || quota <= 0 {
+		return 0, 0, false
+	}
+	period, err = strconv.ParseInt(p, 10, 64)
+	if err != nil || period <= 0 {
+		return 0, 0, false
+	}
+	return quota, period, true
+}
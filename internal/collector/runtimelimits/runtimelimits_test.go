@@ -0,0 +1,113 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimelimits
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useDir makes the limits be read from files of dir, the returned function
+// restores the paths.
+func useDir(dir string) func() {
+	saved := []string{memInfoPath, cgroupV1MemoryPath, cgroupV2MemoryPath,
+		cgroupV1CPUQuotaPath, cgroupV1CPUPeriodPath, cgroupV2CPUPath}
+	memInfoPath = filepath.Join(dir, "meminfo")
+	cgroupV1MemoryPath = filepath.Join(dir, "memory.limit_in_bytes")
+	cgroupV2MemoryPath = filepath.Join(dir, "memory.max")
+	cgroupV1CPUQuotaPath = filepath.Join(dir, "cpu.cfs_quota_us")
+	cgroupV1CPUPeriodPath = filepath.Join(dir, "cpu.cfs_period_us")
+	cgroupV2CPUPath = filepath.Join(dir, "cpu.max")
+	return func() {
+		memInfoPath, cgroupV1MemoryPath, cgroupV2MemoryPath = saved[0], saved[1], saved[2]
+		cgroupV1CPUQuotaPath, cgroupV1CPUPeriodPath, cgroupV2CPUPath = saved[3], saved[4], saved[5]
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func TestTotalMemory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runtimelimits")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer useDir(dir)()
+
+	_, err = TotalMemory()
+	assert.Error(t, err)
+
+	writeFile(t, memInfoPath, "MemTotal:        2048 kB\nMemFree:          1024 kB\n")
+	total, err := TotalMemory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2048*1024), total)
+
+	// No limit.
+	writeFile(t, cgroupV1MemoryPath, "9223372036854771712\n")
+	total, err = TotalMemory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2048*1024), total)
+
+	writeFile(t, cgroupV1MemoryPath, "1048576\n")
+	total, err = TotalMemory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1048576), total)
+
+	// The cgroup v2 file takes precedence.
+	writeFile(t, cgroupV2MemoryPath, "max\n")
+	total, err = TotalMemory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2048*1024), total)
+
+	writeFile(t, cgroupV2MemoryPath, "524288\n")
+	total, err = TotalMemory()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(524288), total)
+}
+
+func TestMaxProcs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runtimelimits")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer useDir(dir)()
+
+	_, ok := MaxProcs()
+	assert.False(t, ok)
+
+	writeFile(t, cgroupV1CPUQuotaPath, "-1\n")
+	writeFile(t, cgroupV1CPUPeriodPath, "100000\n")
+	_, ok = MaxProcs()
+	assert.False(t, ok)
+
+	writeFile(t, cgroupV1CPUQuotaPath, "50000\n")
+	procs, ok := MaxProcs()
+	assert.True(t, ok)
+	assert.Equal(t, 1, procs)
+
+	writeFile(t, cgroupV2CPUPath, "max 100000\n")
+	_, ok = MaxProcs()
+	assert.False(t, ok)
+
+	writeFile(t, cgroupV2CPUPath, "100000000 100000\n")
+	procs, ok = MaxProcs()
+	assert.True(t, ok)
+	assert.Equal(t, runtime.NumCPU(), procs)
+}
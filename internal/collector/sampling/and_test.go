@@ -0,0 +1,81 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+func TestAndEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		subPolicies []PolicyEvaluator
+		want        Decision
+		wantErr     bool
+	}{
+		{
+			name:        "all_sampled",
+			subPolicies: []PolicyEvaluator{NewAlwaysSample(), &fixedDecision{decision: Sampled}},
+			want:        Sampled,
+		},
+		{
+			name:        "one_not_sampled",
+			subPolicies: []PolicyEvaluator{&fixedDecision{decision: NotSampled}, NewAlwaysSample()},
+			want:        NotSampled,
+		},
+		{
+			name:        "error",
+			subPolicies: []PolicyEvaluator{NewAlwaysSample(), &fixedDecision{err: errors.New("test")}},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAnd(tt.subPolicies).Evaluate(nil, &TraceData{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fixedDecision is a policy evaluator always returning the same decision and
+// error, and counting its evaluations.
+type fixedDecision struct {
+	decision    Decision
+	err         error
+	evaluations int
+}
+
+var _ PolicyEvaluator = (*fixedDecision)(nil)
+
+func (fd *fixedDecision) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+	return nil
+}
+
+func (fd *fixedDecision) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	fd.evaluations++
+	return fd.decision, fd.err
+}
+
+func (fd *fixedDecision) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	return NotSampled, nil
+}
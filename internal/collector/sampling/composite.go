@@ -0,0 +1,116 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync/atomic"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+// SubPolicyEvalParams defines the evaluator of a sub-policy of a composite
+// policy and its share of the spans per second budget.
+type SubPolicyEvalParams struct {
+	Evaluator PolicyEvaluator
+	// MaxSpansPerSecond limits the spans sampled each second by the
+	// sub-policy, zero means that it is only limited by the total budget.
+	MaxSpansPerSecond int64
+}
+
+type subPolicy struct {
+	SubPolicyEvalParams
+	spansInCurrentSecond int64
+}
+
+type composite struct {
+	subPolicies               []*subPolicy
+	maxTotalSpansPerSecond    int64
+	currentSecond             int64
+	totalSpansInCurrentSecond int64
+	now                       func() time.Time
+}
+
+var _ PolicyEvaluator = (*composite)(nil)
+
+// NewComposite creates a policy evaluator that samples the traces sampled by
+// the first of the sub-policies, in the given order, whose spans per second
+// budget isn't exhausted. The sub-policies share the maxTotalSpansPerSecond
+// budget, the traces are not sampled once it is exhausted.
+func NewComposite(maxTotalSpansPerSecond int64, subPolicyParams []SubPolicyEvalParams) PolicyEvaluator {
+	subPolicies := make([]*subPolicy, 0, len(subPolicyParams))
+	for _, params := range subPolicyParams {
+		subPolicies = append(subPolicies, &subPolicy{SubPolicyEvalParams: params})
+	}
+	return &composite{
+		subPolicies:            subPolicies,
+		maxTotalSpansPerSecond: maxTotalSpansPerSecond,
+		now:                    time.Now,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (c *composite) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+	for _, sub := range c.subPolicies {
+		if err := sub.Evaluator.OnLateArrivingSpans(earlyDecision, spans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (c *composite) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	currSecond := c.now().Unix()
+	if c.currentSecond != currSecond {
+		c.currentSecond = currSecond
+		c.totalSpansInCurrentSecond = 0
+		for _, sub := range c.subPolicies {
+			sub.spansInCurrentSecond = 0
+		}
+	}
+
+	spanCount := atomic.LoadInt64(&trace.SpanCount)
+	if c.totalSpansInCurrentSecond+spanCount > c.maxTotalSpansPerSecond {
+		return NotSampled, nil
+	}
+	for _, sub := range c.subPolicies {
+		decision, err := sub.Evaluator.Evaluate(traceID, trace)
+		if err != nil {
+			return Unspecified, err
+		}
+		if decision != Sampled {
+			continue
+		}
+		if sub.MaxSpansPerSecond > 0 && sub.spansInCurrentSecond+spanCount > sub.MaxSpansPerSecond {
+			// The budget of the sub-policy is exhausted, the next ones may
+			// still sample the trace.
+			continue
+		}
+		sub.spansInCurrentSecond += spanCount
+		c.totalSpansInCurrentSecond += spanCount
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (c *composite) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	return NotSampled, nil
+}
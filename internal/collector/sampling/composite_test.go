@@ -0,0 +1,73 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompositeEvaluate(t *testing.T) {
+	errors := &fixedDecision{decision: Sampled}
+	debug := &fixedDecision{decision: NotSampled}
+	always := &fixedDecision{decision: Sampled}
+	c := NewComposite(10, []SubPolicyEvalParams{
+		{Evaluator: errors, MaxSpansPerSecond: 4},
+		{Evaluator: debug},
+		{Evaluator: always, MaxSpansPerSecond: 5},
+	}).(*composite)
+	now := time.Unix(100, 0)
+	c.now = func() time.Time { return now }
+
+	evaluate := func(spanCount int64) Decision {
+		decision, err := c.Evaluate(nil, &TraceData{SpanCount: spanCount})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		return decision
+	}
+
+	// Sampled by the first sub-policy, the next ones aren't evaluated.
+	if got := evaluate(3); got != Sampled {
+		t.Errorf("Evaluate() = %v, want %v", got, Sampled)
+	}
+	if debug.evaluations != 0 || always.evaluations != 0 {
+		t.Errorf("lower priority sub-policies evaluated after a sampling decision")
+	}
+	// The first sub-policy exceeds its budget, the last one samples the trace.
+	if got := evaluate(3); got != Sampled {
+		t.Errorf("Evaluate() = %v, want %v", got, Sampled)
+	}
+	if always.evaluations != 1 {
+		t.Errorf("got %d evaluations of the last sub-policy, want 1", always.evaluations)
+	}
+	// Both sampling sub-policies exceed their budget.
+	if got := evaluate(3); got != NotSampled {
+		t.Errorf("Evaluate() = %v, want %v", got, NotSampled)
+	}
+	// The total budget is exhausted, no sub-policy is evaluated.
+	if got := evaluate(5); got != NotSampled {
+		t.Errorf("Evaluate() = %v, want %v", got, NotSampled)
+	}
+	if errors.evaluations != 3 {
+		t.Errorf("got %d evaluations of the first sub-policy, want 3", errors.evaluations)
+	}
+
+	// The budgets are reset every second.
+	now = now.Add(time.Second)
+	if got := evaluate(4); got != Sampled {
+		t.Errorf("Evaluate() = %v, want %v", got, Sampled)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+type invert struct {
+	policy PolicyEvaluator
+}
+
+var _ PolicyEvaluator = (*invert)(nil)
+
+// NewInvert creates a policy evaluator that samples the traces not sampled by
+// the given policy, and the other way around.
+func NewInvert(policy PolicyEvaluator) PolicyEvaluator {
+	return &invert{
+		policy: policy,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (inv *invert) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+	return inv.policy.OnLateArrivingSpans(invertDecision(earlyDecision), spans)
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (inv *invert) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	decision, err := inv.policy.Evaluate(traceID, trace)
+	if err != nil {
+		return Unspecified, err
+	}
+	return invertDecision(decision), nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (inv *invert) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	return NotSampled, nil
+}
+
+func invertDecision(decision Decision) Decision {
+	switch decision {
+	case Sampled:
+		return NotSampled
+	case NotSampled:
+		return Sampled
+	}
+	return decision
+}
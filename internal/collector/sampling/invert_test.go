@@ -0,0 +1,33 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import "testing"
+
+func TestInvertEvaluate(t *testing.T) {
+	for decision, want := range map[Decision]Decision{
+		Sampled:    NotSampled,
+		NotSampled: Sampled,
+		Pending:    Pending,
+	} {
+		got, err := NewInvert(&fixedDecision{decision: decision}).Evaluate(nil, &TraceData{})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Evaluate() of inverted %v = %v, want %v", decision, got, want)
+		}
+	}
+}
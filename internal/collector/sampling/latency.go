@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+type latency struct {
+	threshold time.Duration
+}
+
+var _ PolicyEvaluator = (*latency)(nil)
+
+// NewLatency creates a policy evaluator that samples the traces lasting at
+// least the given threshold, from the start of their first span to the end of
+// their last span.
+func NewLatency(threshold time.Duration) PolicyEvaluator {
+	return &latency{
+		threshold: threshold,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (l *latency) OnLateArrivingSpans(earlyDecision Decision, spans []*tracepb.Span) error {
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (l *latency) Evaluate(traceID []byte, trace *TraceData) (Decision, error) {
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+
+	var minStart, maxEnd time.Time
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			if span == nil || span.StartTime == nil || span.EndTime == nil {
+				continue
+			}
+			start := time.Unix(span.StartTime.Seconds, int64(span.StartTime.Nanos))
+			end := time.Unix(span.EndTime.Seconds, int64(span.EndTime.Nanos))
+			if minStart.IsZero() || start.Before(minStart) {
+				minStart = start
+			}
+			if maxEnd.IsZero() || end.After(maxEnd) {
+				maxEnd = end
+			}
+		}
+	}
+
+	if !minStart.IsZero() && maxEnd.Sub(minStart) >= l.threshold {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+// OnDroppedSpans is called when the trace needs to be dropped, due to memory
+// pressure, before the decision_wait time has been reached.
+func (l *latency) OnDroppedSpans(traceID []byte, trace *TraceData) (Decision, error) {
+	return NotSampled, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+func TestLatencyEvaluate(t *testing.T) {
+	newSpan := func(startSec, endSec int64) *tracepb.Span {
+		return &tracepb.Span{
+			StartTime: &timestamp.Timestamp{Seconds: startSec},
+			EndTime:   &timestamp.Timestamp{Seconds: endSec},
+		}
+	}
+	tests := []struct {
+		name  string
+		spans []*tracepb.Span
+		want  Decision
+	}{
+		{
+			name: "no_spans",
+			want: NotSampled,
+		},
+		{
+			name:  "short_span",
+			spans: []*tracepb.Span{newSpan(10, 12)},
+			want:  NotSampled,
+		},
+		{
+			name:  "long_span",
+			spans: []*tracepb.Span{newSpan(10, 15)},
+			want:  Sampled,
+		},
+		{
+			name:  "short_spans_long_trace",
+			spans: []*tracepb.Span{newSpan(10, 12), {}, newSpan(13, 15)},
+			want:  Sampled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := &TraceData{
+				ReceivedBatches: []consumerdata.TraceData{{Spans: tt.spans}},
+			}
+			got, err := NewLatency(5*time.Second).Evaluate(nil, trace)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
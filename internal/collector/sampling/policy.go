@@ -27,7 +27,12 @@ import (
 type TraceData struct {
 	sync.Mutex
 	// Decision gives the current status of the sampling decision for each policy.
+	// It is replaced under the lock when the trace is evaluated, never
+	// modified in place.
 	Decision []Decision
+	// FinalDecision is the decision for the whole trace: it is sampled if any
+	// of the policies sampled it.
+	FinalDecision Decision
 	// Arrival time the first span for the trace was received.
 	ArrivalTime time.Time
 	// Decisiontime time when sampling decision was taken.
@@ -36,6 +41,9 @@ type TraceData struct {
 	SpanCount int64
 	// ReceivedBatches stores all the batches received for the trace.
 	ReceivedBatches []consumerdata.TraceData
+	// ReservedBytes is the number of bytes of ReceivedBatches reserved in the
+	// memory budget.
+	ReservedBytes int64
 }
 
 // Decision gives the status of sampling decision.
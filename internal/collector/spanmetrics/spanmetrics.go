@@ -0,0 +1,259 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanmetrics aggregates spans in request, error and latency metrics,
+// per service, operation and status. It is shared by the span-metrics
+// processor and connector.
+package spanmetrics
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+)
+
+// Names of the metrics emitted.
+const (
+	MetricRequests = "spanmetrics/requests"
+	MetricErrors   = "spanmetrics/errors"
+	MetricLatency  = "spanmetrics/latency"
+)
+
+// DefaultLatencyBuckets are the default bounds of the latency histograms.
+var DefaultLatencyBuckets = []time.Duration{
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+var labelKeys = []*metricspb.LabelKey{{Key: "service"}, {Key: "operation"}, {Key: "status"}}
+
+type seriesKey struct {
+	service   string
+	operation string
+	status    string
+}
+
+// series holds the cumulative values of the metrics of a key.
+type series struct {
+	requests int64
+	errors   int64
+
+	latencyCount int64
+	latencySum   float64
+	latencySumSq float64
+	buckets      []int64
+}
+
+// Aggregator holds the cumulative metrics of the spans added since it was
+// created. It is safe for concurrent use.
+type Aggregator struct {
+	// bounds are the bounds of the latency buckets in milliseconds.
+	bounds    []float64
+	startTime time.Time
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+// NewAggregator returns an Aggregator with the given bounds of the latency
+// buckets, DefaultLatencyBuckets if empty.
+func NewAggregator(latencyBuckets []time.Duration) (*Aggregator, error) {
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = DefaultLatencyBuckets
+	}
+	bounds := make([]float64, len(latencyBuckets))
+	for i, bucket := range latencyBuckets {
+		bounds[i] = float64(bucket) / float64(time.Millisecond)
+		if i > 0 && bounds[i] <= bounds[i-1] {
+			return nil, errors.New("latency-buckets must be increasing")
+		}
+	}
+
+	return &Aggregator{
+		bounds:    bounds,
+		startTime: time.Now(),
+		series:    make(map[seriesKey]*series),
+	}, nil
+}
+
+// StartTime returns the start time of the cumulative metrics.
+func (a *Aggregator) StartTime() time.Time {
+	return a.startTime
+}
+
+// Add adds the spans of the batch to the metrics.
+func (a *Aggregator) Add(td consumerdata.TraceData) {
+	batchService := td.Node.GetServiceInfo().GetName()
+	if name, ok := td.Resource.GetLabels()[resourcetranslator.LabelServiceName]; ok {
+		batchService = name
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, span := range td.Spans {
+		if span == nil {
+			continue
+		}
+		service := batchService
+		if name, ok := span.Resource.GetLabels()[resourcetranslator.LabelServiceName]; ok {
+			service = name
+		}
+		a.aggregate(service, span)
+	}
+}
+
+// aggregate adds the span to its series, the lock must be held.
+func (a *Aggregator) aggregate(service string, span *tracepb.Span) {
+	code := span.GetStatus().GetCode()
+	key := seriesKey{
+		service:   service,
+		operation: span.GetName().GetValue(),
+		status:    codes.Code(code).String(),
+	}
+	s, ok := a.series[key]
+	if !ok {
+		s = &series{buckets: make([]int64, len(a.bounds)+1)}
+		a.series[key] = s
+	}
+
+	s.requests++
+	if code != 0 {
+		s.errors++
+	}
+	if span.StartTime == nil || span.EndTime == nil {
+		return
+	}
+	latency := float64(span.EndTime.Seconds-span.StartTime.Seconds)*1e3 +
+		float64(span.EndTime.Nanos-span.StartTime.Nanos)/1e6
+	if latency < 0 {
+		return
+	}
+	s.latencyCount++
+	s.latencySum += latency
+	s.latencySumSq += latency * latency
+	// The bucket i holds the values in [bounds[i-1], bounds[i]).
+	s.buckets[sort.Search(len(a.bounds), func(i int) bool { return a.bounds[i] > latency })]++
+}
+
+// MetricsData returns the metrics of all the series at the given time, no
+// metrics if no spans were added.
+func (a *Aggregator) MetricsData(now time.Time) consumerdata.MetricsData {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.series) == 0 {
+		return consumerdata.MetricsData{}
+	}
+
+	keys := make([]seriesKey, 0, len(a.series))
+	for key := range a.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i], keys[j]
+		if ki.service != kj.service {
+			return ki.service < kj.service
+		}
+		if ki.operation != kj.operation {
+			return ki.operation < kj.operation
+		}
+		return ki.status < kj.status
+	})
+
+	start, ts := internal.TimeToTimestamp(a.startTime), internal.TimeToTimestamp(now)
+	requests := newMetric(MetricRequests, "Number of spans", "1", metricspb.MetricDescriptor_CUMULATIVE_INT64)
+	errs := newMetric(MetricErrors, "Number of spans with an error status", "1", metricspb.MetricDescriptor_CUMULATIVE_INT64)
+	latency := newMetric(MetricLatency, "Duration of the spans", "ms", metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION)
+	for _, key := range keys {
+		s := a.series[key]
+		labelValues := []*metricspb.LabelValue{
+			{Value: key.service, HasValue: true},
+			{Value: key.operation, HasValue: true},
+			{Value: key.status, HasValue: true},
+		}
+		timeSeries := func(point *metricspb.Point) *metricspb.TimeSeries {
+			point.Timestamp = ts
+			return &metricspb.TimeSeries{
+				StartTimestamp: start,
+				LabelValues:    labelValues,
+				Points:         []*metricspb.Point{point},
+			}
+		}
+
+		requests.Timeseries = append(requests.Timeseries, timeSeries(&metricspb.Point{
+			Value: &metricspb.Point_Int64Value{Int64Value: s.requests},
+		}))
+		errs.Timeseries = append(errs.Timeseries, timeSeries(&metricspb.Point{
+			Value: &metricspb.Point_Int64Value{Int64Value: s.errors},
+		}))
+		latency.Timeseries = append(latency.Timeseries, timeSeries(&metricspb.Point{
+			Value: &metricspb.Point_DistributionValue{DistributionValue: a.distribution(s)},
+		}))
+	}
+	return consumerdata.MetricsData{Metrics: []*metricspb.Metric{requests, errs, latency}}
+}
+
+func (a *Aggregator) distribution(s *series) *metricspb.DistributionValue {
+	buckets := make([]*metricspb.DistributionValue_Bucket, len(s.buckets))
+	for i, count := range s.buckets {
+		buckets[i] = &metricspb.DistributionValue_Bucket{Count: count}
+	}
+	var sumOfSquaredDeviation float64
+	if s.latencyCount > 0 {
+		sumOfSquaredDeviation = s.latencySumSq - s.latencySum*s.latencySum/float64(s.latencyCount)
+	}
+	return &metricspb.DistributionValue{
+		Count:                 s.latencyCount,
+		Sum:                   s.latencySum,
+		SumOfSquaredDeviation: sumOfSquaredDeviation,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: a.bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
+
+func newMetric(name, description, unit string, metricType metricspb.MetricDescriptor_Type) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Type:        metricType,
+			LabelKeys:   labelKeys,
+		},
+	}
+}
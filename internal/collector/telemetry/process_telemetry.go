@@ -68,13 +68,106 @@ var viewCPUSeconds = &view.View{
 	TagKeys:     nil,
 }
 
+var mUptime = stats.Float64("oc.io/process/uptime", "Time since the process started", "s")
+var viewUptime = &view.View{
+	Name:        mUptime.Name(),
+	Description: mUptime.Description(),
+	Measure:     mUptime,
+	Aggregation: view.LastValue(),
+	TagKeys:     nil,
+}
+
+var mRSSMemory = stats.Int64("oc.io/process/rss_memory", "Resident set size of the process", "By")
+var viewRSSMemory = &view.View{
+	Name:        mRSSMemory.Name(),
+	Description: mRSSMemory.Description(),
+	Measure:     mRSSMemory,
+	Aggregation: view.LastValue(),
+	TagKeys:     nil,
+}
+
+var mOpenFDs = stats.Int64("oc.io/process/open_fds", "Number of file descriptors open by the process", "1")
+var viewOpenFDs = &view.View{
+	Name:        mOpenFDs.Name(),
+	Description: mOpenFDs.Description(),
+	Measure:     mOpenFDs,
+	Aggregation: view.LastValue(),
+	TagKeys:     nil,
+}
+
+var mGCCount = stats.Int64("oc.io/process/gc_count", "Number of completed garbage collections", "1")
+var viewGCCount = &view.View{
+	Name:        mGCCount.Name(),
+	Description: mGCCount.Description(),
+	Measure:     mGCCount,
+	Aggregation: view.LastValue(),
+	TagKeys:     nil,
+}
+
+var mGCPauseTotal = stats.Float64("oc.io/process/gc_pause_total", "Total time the garbage collections stopped the process", "s")
+var viewGCPauseTotal = &view.View{
+	Name:        mGCPauseTotal.Name(),
+	Description: mGCPauseTotal.Description(),
+	Measure:     mGCPauseTotal,
+	Aggregation: view.LastValue(),
+	TagKeys:     nil,
+}
+
+// processStartTime approximates the start time of the process, the package is
+// initialized when it starts.
+var processStartTime = time.Now()
+
+// ProcessStats are the resource usage statistics of the process.
+type ProcessStats struct {
+	Uptime       time.Duration
+	NumGC        uint32
+	GCPauseTotal time.Duration
+
+	// CPUSeconds, RSSBytes and OpenFDs are read from procfs, they are only
+	// set if ProcfsOK is true.
+	ProcfsOK   bool
+	CPUSeconds float64
+	RSSBytes   int64
+	OpenFDs    int64
+}
+
+// ReadProcessStats returns the statistics of the process from its memory
+// statistics and its procfs entry, see procfs.FS.NewProc. The procfs error, if
+// any, is returned with the other statistics.
+func ReadProcessStats(ms *runtime.MemStats, proc procfs.Proc, procErr error) (ProcessStats, error) {
+	ps := ProcessStats{
+		Uptime:       time.Since(processStartTime),
+		NumGC:        ms.NumGC,
+		GCPauseTotal: time.Duration(ms.PauseTotalNs),
+	}
+	if procErr != nil {
+		return ps, procErr
+	}
+	procStat, err := proc.NewStat()
+	if err != nil {
+		return ps, err
+	}
+	fds, err := proc.FileDescriptorsLen()
+	if err != nil {
+		return ps, err
+	}
+	ps.ProcfsOK = true
+	ps.CPUSeconds = procStat.CPUTime()
+	ps.RSSBytes = int64(procStat.ResidentMemory())
+	ps.OpenFDs = int64(fds)
+	return ps, nil
+}
+
 // NewProcessMetricsViews creates a new set of ProcessMetrics (mem, cpu) that can be used to measure
 // basic information about this process.
 func NewProcessMetricsViews(ballastSizeBytes uint64) *ProcessMetricsViews {
 	return &ProcessMetricsViews{
 		ballastSizeBytes: ballastSizeBytes,
-		views:            []*view.View{viewAllocMem, viewTotalAllocMem, viewSysMem, viewCPUSeconds},
-		done:             make(chan struct{}),
+		views: []*view.View{
+			viewAllocMem, viewTotalAllocMem, viewSysMem, viewCPUSeconds,
+			viewUptime, viewRSSMemory, viewOpenFDs, viewGCCount, viewGCPauseTotal,
+		},
+		done: make(chan struct{}),
 	}
 }
 
@@ -110,12 +203,17 @@ func (pmv *ProcessMetricsViews) updateViews() {
 	stats.Record(context.Background(), mRuntimeTotalAllocMem.M(int64(ms.TotalAlloc)))
 	stats.Record(context.Background(), mRuntimeSysMem.M(int64(ms.Sys)))
 
-	pid := os.Getpid()
-	proc, err := procfs.NewProc(pid)
-	if err == nil {
-		if procStat, err := proc.NewStat(); err == nil {
-			stats.Record(context.Background(), mCPUSeconds.M(int64(procStat.CPUTime())))
-		}
+	proc, err := procfs.NewProc(os.Getpid())
+	ps, _ := ReadProcessStats(ms, proc, err)
+	stats.Record(context.Background(),
+		mUptime.M(ps.Uptime.Seconds()),
+		mGCCount.M(int64(ps.NumGC)),
+		mGCPauseTotal.M(ps.GCPauseTotal.Seconds()))
+	if ps.ProcfsOK {
+		stats.Record(context.Background(),
+			mCPUSeconds.M(int64(ps.CPUSeconds)),
+			mRSSMemory.M(ps.RSSBytes),
+			mOpenFDs.M(ps.OpenFDs))
 	}
 }
 
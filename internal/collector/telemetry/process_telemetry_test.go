@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+)
+
+func TestReadProcessStats(t *testing.T) {
+	runtime.GC()
+	ms := &runtime.MemStats{}
+	runtime.ReadMemStats(ms)
+
+	proc, err := procfs.NewProc(os.Getpid())
+	if err != nil {
+		t.Skipf("procfs is not available: %v", err)
+	}
+	ps, err := ReadProcessStats(ms, proc, nil)
+	require.NoError(t, err)
+	assert.True(t, ps.Uptime > 0)
+	assert.True(t, ps.NumGC > 0)
+	assert.True(t, ps.ProcfsOK)
+	assert.True(t, ps.RSSBytes > 0)
+	assert.True(t, ps.OpenFDs > 0)
+
+	procErr := errors.New("no procfs")
+	ps, err = ReadProcessStats(ms, procfs.Proc{}, procErr)
+	assert.Equal(t, procErr, err)
+	assert.False(t, ps.ProcfsOK)
+	assert.True(t, ps.NumGC > 0)
+}
+
+func TestProcessMetricsViews(t *testing.T) {
+	pmv := NewProcessMetricsViews(0)
+	require.NoError(t, view.Register(pmv.Views()...))
+	defer view.Unregister(pmv.Views()...)
+
+	pmv.updateViews()
+	for _, name := range []string{"oc.io/process/uptime", "oc.io/process/gc_count", "oc.io/process/gc_pause_total"} {
+		rows, err := view.RetrieveData(name)
+		require.NoError(t, err, name)
+		assert.Len(t, rows, 1, name)
+	}
+}
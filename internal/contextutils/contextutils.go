@@ -0,0 +1,66 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contextutils derives the contexts of the data going through the
+// pipelines, so that the deadline and cancellation of the requests of the
+// receivers, and the shutdown of the service, reach the exporters.
+package contextutils
+
+import (
+	"context"
+	"time"
+)
+
+// Detach returns a context keeping the values of the parent, e.g. the tags
+// and the client of the request, but not its deadline and cancellation, for
+// the work continuing after the call that received the parent returned, e.g.
+// a queued export. It is canceled when the stop channel is closed, never if
+// the channel is nil.
+func Detach(parent context.Context, stop <-chan struct{}) context.Context {
+	return detachedContext{parent: parent, stop: stop}
+}
+
+type detachedContext struct {
+	parent context.Context
+	stop   <-chan struct{}
+}
+
+func (dc detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (dc detachedContext) Done() <-chan struct{}       { return dc.stop }
+
+func (dc detachedContext) Err() error {
+	select {
+	case <-dc.stop:
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
+func (dc detachedContext) Value(key interface{}) interface{} { return dc.parent.Value(key) }
+
+// WithStop returns a copy of the parent which is also canceled when the stop
+// channel is closed, e.g. when the component is shut down. The cancel function
+// must be called once the work is done.
+func WithStop(parent context.Context, stop <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,68 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contextutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type key struct{}
+
+func TestDetach(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.WithValue(context.Background(), key{}, "value"), time.Hour)
+	stop := make(chan struct{})
+	ctx := Detach(parent, stop)
+	cancel()
+
+	assert.Equal(t, "value", ctx.Value(key{}))
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.NoError(t, ctx.Err())
+
+	close(stop)
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestDetach_NilStop(t *testing.T) {
+	ctx := Detach(context.Background(), nil)
+	assert.Nil(t, ctx.Done())
+	assert.NoError(t, ctx.Err())
+}
+
+func TestWithStop(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	defer cancelParent()
+
+	stop := make(chan struct{})
+	ctx, cancel := WithStop(parent, stop)
+	defer cancel()
+	assert.Equal(t, "value", ctx.Value(key{}))
+	assert.NoError(t, ctx.Err())
+	close(stop)
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+
+	// The parent cancels it too.
+	ctx, cancel = WithStop(parent, make(chan struct{}))
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
@@ -0,0 +1,222 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debugbatches keeps the last batches of data received by each
+// pipeline and serves them over HTTP, to inspect the data flowing through the
+// collector.
+package debugbatches
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// Batch is a batch of data recorded by a Recorder. The data is a copy of the
+// batch, as an OpenCensus agent export request, which is not modified by the
+// pipeline.
+type Batch struct {
+	// Time is the time the batch was received by the pipeline.
+	Time time.Time
+	// SourceFormat is the format the spans were received in, empty for
+	// metrics.
+	SourceFormat string
+	// Traces is the batch of a traces pipeline.
+	Traces *agenttracepb.ExportTraceServiceRequest
+	// Metrics is the batch of a metrics pipeline.
+	Metrics *agentmetricspb.ExportMetricsServiceRequest
+}
+
+// Recorder records the last batches received by each pipeline, when its
+// recording is on.
+type Recorder struct {
+	size int
+	// recording is 1 when the batches are recorded, accessed atomically.
+	recording int32
+
+	mu        sync.Mutex
+	pipelines map[string]*ring
+}
+
+// NewRecorder creates a Recorder keeping the last size batches of each
+// pipeline, recording from the start if recording is true.
+func NewRecorder(size int, recording bool) *Recorder {
+	r := &Recorder{
+		size:      size,
+		pipelines: make(map[string]*ring),
+	}
+	r.SetRecording(recording)
+	return r
+}
+
+// SetRecording turns the recording on or off. The batches already recorded
+// are kept when it is turned off.
+func (r *Recorder) SetRecording(recording bool) {
+	var v int32
+	if recording {
+		v = 1
+	}
+	atomic.StoreInt32(&r.recording, v)
+}
+
+// Recording returns true if the batches are recorded.
+func (r *Recorder) Recording() bool {
+	return atomic.LoadInt32(&r.recording) == 1
+}
+
+// Pipelines returns the names of the pipelines recorded by the Recorder with
+// their number of batches, whether the recording is on or not.
+func (r *Recorder) Pipelines() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pipelines := make(map[string]int, len(r.pipelines))
+	for name, rg := range r.pipelines {
+		pipelines[name] = rg.count
+	}
+	return pipelines
+}
+
+// Batches returns the batches recorded for the pipeline, oldest first, and
+// false if the pipeline is not recorded.
+func (r *Recorder) Batches(pipeline string) ([]Batch, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rg, ok := r.pipelines[pipeline]
+	if !ok {
+		return nil, false
+	}
+	return rg.batches(), true
+}
+
+// Clear removes the recorded batches of all the pipelines.
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := range r.pipelines {
+		r.pipelines[name] = newRing(r.size)
+	}
+}
+
+// WrapTraceConsumer returns a consumer recording the batches of the pipeline
+// before sending them to next.
+func (r *Recorder) WrapTraceConsumer(pipeline string, next consumer.TraceConsumer) consumer.TraceConsumer {
+	r.register(pipeline)
+	return &traceRecorder{recorder: r, pipeline: pipeline, next: next}
+}
+
+// WrapMetricsConsumer returns a consumer recording the batches of the
+// pipeline before sending them to next.
+func (r *Recorder) WrapMetricsConsumer(pipeline string, next consumer.MetricsConsumer) consumer.MetricsConsumer {
+	r.register(pipeline)
+	return &metricsRecorder{recorder: r, pipeline: pipeline, next: next}
+}
+
+func (r *Recorder) register(pipeline string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pipelines[pipeline]; !ok {
+		r.pipelines[pipeline] = newRing(r.size)
+	}
+}
+
+func (r *Recorder) record(pipeline string, batch Batch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pipelines[pipeline].add(batch)
+}
+
+type traceRecorder struct {
+	recorder *Recorder
+	pipeline string
+	next     consumer.TraceConsumer
+}
+
+var _ consumer.TraceConsumer = (*traceRecorder)(nil)
+
+func (tr *traceRecorder) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if tr.recorder.Recording() {
+		// Copy the batch, the processors can modify it.
+		req := &agenttracepb.ExportTraceServiceRequest{
+			Node:     td.Node,
+			Resource: td.Resource,
+			Spans:    td.Spans,
+		}
+		tr.recorder.record(tr.pipeline, Batch{
+			Time:         time.Now(),
+			SourceFormat: td.SourceFormat,
+			Traces:       proto.Clone(req).(*agenttracepb.ExportTraceServiceRequest),
+		})
+	}
+	return tr.next.ConsumeTraceData(ctx, td)
+}
+
+type metricsRecorder struct {
+	recorder *Recorder
+	pipeline string
+	next     consumer.MetricsConsumer
+}
+
+var _ consumer.MetricsConsumer = (*metricsRecorder)(nil)
+
+func (mr *metricsRecorder) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if mr.recorder.Recording() {
+		req := &agentmetricspb.ExportMetricsServiceRequest{
+			Node:     md.Node,
+			Resource: md.Resource,
+			Metrics:  md.Metrics,
+		}
+		mr.recorder.record(mr.pipeline, Batch{
+			Time:    time.Now(),
+			Metrics: proto.Clone(req).(*agentmetricspb.ExportMetricsServiceRequest),
+		})
+	}
+	return mr.next.ConsumeMetricsData(ctx, md)
+}
+
+// ring keeps the last batches added to it.
+type ring struct {
+	buf   []Batch
+	next  int
+	count int
+}
+
+func newRing(size int) *ring {
+	return &ring{buf: make([]Batch, size)}
+}
+
+func (rg *ring) add(batch Batch) {
+	rg.buf[rg.next] = batch
+	rg.next = (rg.next + 1) % len(rg.buf)
+	if rg.count < len(rg.buf) {
+		rg.count++
+	}
+}
+
+// batches returns the batches of the ring, oldest first.
+func (rg *ring) batches() []Batch {
+	batches := make([]Batch, 0, rg.count)
+	start := (rg.next - rg.count + len(rg.buf)) % len(rg.buf)
+	for i := 0; i < rg.count; i++ {
+		batches = append(batches, rg.buf[(start+i)%len(rg.buf)])
+	}
+	return batches
+}
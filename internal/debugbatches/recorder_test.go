@@ -0,0 +1,99 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugbatches
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func spanNamed(name string) *tracepb.Span {
+	return &tracepb.Span{Name: &tracepb.TruncatableString{Value: name}}
+}
+
+func TestRecorderTraces(t *testing.T) {
+	r := NewRecorder(2, false)
+	sink := new(exportertest.SinkTraceExporter)
+	tc := r.WrapTraceConsumer("traces", sink)
+	assert.Equal(t, map[string]int{"traces": 0}, r.Pipelines())
+
+	// Nothing is recorded until the recording is turned on.
+	td := consumerdata.TraceData{Spans: []*tracepb.Span{spanNamed("0")}, SourceFormat: "zipkin"}
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), td))
+	batches, ok := r.Batches("traces")
+	require.True(t, ok)
+	assert.Empty(t, batches)
+
+	r.SetRecording(true)
+	for _, name := range []string{"1", "2", "3"} {
+		td := consumerdata.TraceData{Spans: []*tracepb.Span{spanNamed(name)}, SourceFormat: "zipkin"}
+		require.NoError(t, tc.ConsumeTraceData(context.Background(), td))
+	}
+	assert.Len(t, sink.AllTraces(), 4)
+
+	// Only the last batches are kept, oldest first.
+	batches, ok = r.Batches("traces")
+	require.True(t, ok)
+	require.Len(t, batches, 2)
+	assert.Equal(t, "2", batches[0].Traces.Spans[0].Name.Value)
+	assert.Equal(t, "3", batches[1].Traces.Spans[0].Name.Value)
+	assert.Equal(t, "zipkin", batches[0].SourceFormat)
+	assert.Nil(t, batches[0].Metrics)
+
+	// The batches are copies of the data.
+	sink.AllTraces()[3].Spans[0].Name.Value = "modified"
+	batches, _ = r.Batches("traces")
+	assert.Equal(t, "3", batches[1].Traces.Spans[0].Name.Value)
+
+	r.SetRecording(false)
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), td))
+	batches, _ = r.Batches("traces")
+	assert.Len(t, batches, 2)
+
+	r.Clear()
+	batches, ok = r.Batches("traces")
+	assert.True(t, ok)
+	assert.Empty(t, batches)
+
+	_, ok = r.Batches("unknown")
+	assert.False(t, ok)
+}
+
+func TestRecorderMetrics(t *testing.T) {
+	r := NewRecorder(3, true)
+	sink := new(exportertest.SinkMetricsExporter)
+	mc := r.WrapMetricsConsumer("metrics", sink)
+
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{{MetricDescriptor: &metricspb.MetricDescriptor{Name: "m"}}},
+	}
+	require.NoError(t, mc.ConsumeMetricsData(context.Background(), md))
+	assert.Len(t, sink.AllMetrics(), 1)
+
+	batches, ok := r.Batches("metrics")
+	require.True(t, ok)
+	require.Len(t, batches, 1)
+	assert.Nil(t, batches[0].Traces)
+	assert.Equal(t, "m", batches[0].Metrics.Metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, map[string]int{"metrics": 1}, r.Pipelines())
+}
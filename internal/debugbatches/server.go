@@ -0,0 +1,164 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugbatches
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// Path is the path under which the batches are served.
+const Path = "/debug/batches"
+
+// Handler returns the HTTP handler serving the batches of the Recorder:
+//
+//	GET    /debug/batches                            the recording state and the pipelines
+//	GET    /debug/batches/<pipeline>[?format=text]   the batches of the pipeline, in JSON or protobuf text
+//	POST   /debug/batches?recording=<true|false>     turns the recording on or off
+//	DELETE /debug/batches                            removes the recorded batches
+func (r *Recorder) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, r.serveIndex)
+	mux.HandleFunc(Path+"/", r.servePipeline)
+	return mux
+}
+
+// index is the JSON document served on Path.
+type index struct {
+	Recording bool           `json:"recording"`
+	Pipelines map[string]int `json:"pipelines"`
+}
+
+func (r *Recorder) serveIndex(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		recording, err := strconv.ParseBool(req.URL.Query().Get("recording"))
+		if err != nil {
+			http.Error(w, "the recording parameter must be true or false", http.StatusBadRequest)
+			return
+		}
+		r.SetRecording(recording)
+	case http.MethodDelete:
+		r.Clear()
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(index{
+		Recording: r.Recording(),
+		Pipelines: r.Pipelines(),
+	})
+}
+
+// batchJSON is the JSON representation of a Batch, the data is the JSON
+// mapping of the OpenCensus agent export request.
+type batchJSON struct {
+	Time         time.Time       `json:"time"`
+	SourceFormat string          `json:"source-format,omitempty"`
+	Traces       json.RawMessage `json:"traces,omitempty"`
+	Metrics      json.RawMessage `json:"metrics,omitempty"`
+}
+
+func (r *Recorder) servePipeline(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The names of the pipelines can contain slashes.
+	pipeline := strings.TrimPrefix(req.URL.Path, Path+"/")
+	batches, ok := r.Batches(pipeline)
+	if !ok {
+		http.Error(w, fmt.Sprintf("pipeline %q is not recorded", pipeline), http.StatusNotFound)
+		return
+	}
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "json":
+		writeJSON(w, batches)
+	case "text":
+		writeText(w, batches)
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q, must be json or text", format), http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, batches []Batch) {
+	marshaler := jsonpb.Marshaler{OrigName: true}
+	out := make([]batchJSON, 0, len(batches))
+	for _, batch := range batches {
+		b := batchJSON{Time: batch.Time, SourceFormat: batch.SourceFormat}
+		var msg proto.Message = batch.Metrics
+		dst := &b.Metrics
+		if batch.Traces != nil {
+			msg = batch.Traces
+			dst = &b.Traces
+		}
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, msg); err != nil {
+			http.Error(w, fmt.Sprintf("failed to marshal the batches: %v", err), http.StatusInternalServerError)
+			return
+		}
+		*dst = buf.Bytes()
+		out = append(out, b)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+func writeText(w http.ResponseWriter, batches []Batch) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for i, batch := range batches {
+		fmt.Fprintf(w, "# batch %d received at %s", i, batch.Time.Format(time.RFC3339Nano))
+		if batch.SourceFormat != "" {
+			fmt.Fprintf(w, " in %s format", batch.SourceFormat)
+		}
+		fmt.Fprintln(w)
+		if batch.Traces != nil {
+			proto.MarshalText(w, batch.Traces)
+		} else {
+			proto.MarshalText(w, batch.Metrics)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// Run serves the batches of the Recorder on the given endpoint.
+func Run(asyncErrorChannel chan<- error, endpoint string, r *Recorder) (closeFn func() error, err error) {
+	ln, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to run the debug batches endpoint on %q: %v", endpoint, err)
+	}
+
+	srv := http.Server{Handler: r.Handler()}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			asyncErrorChannel <- fmt.Errorf("failed to serve the debug batches: %v", err)
+		}
+	}()
+
+	return srv.Close, nil
+}
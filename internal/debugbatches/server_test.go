@@ -0,0 +1,111 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugbatches
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+)
+
+func doRequest(t *testing.T, h http.Handler, method, target string) (int, string) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder(5, false)
+	tc := r.WrapTraceConsumer("traces/1", new(exportertest.SinkTraceExporter))
+	h := r.Handler()
+
+	code, body := doRequest(t, h, http.MethodGet, "/debug/batches")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"recording": false, "pipelines": {"traces/1": 0}}`, body)
+
+	code, _ = doRequest(t, h, http.MethodPost, "/debug/batches?recording=maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, body = doRequest(t, h, http.MethodPost, "/debug/batches?recording=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"recording": true, "pipelines": {"traces/1": 0}}`, body)
+	assert.True(t, r.Recording())
+
+	td := consumerdata.TraceData{
+		Spans:        []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}, SpanId: []byte{1, 2, 3, 4, 5, 6, 7, 8}}},
+		SourceFormat: "jaeger",
+	}
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), td))
+
+	code, body = doRequest(t, h, http.MethodGet, "/debug/batches/traces/1")
+	assert.Equal(t, http.StatusOK, code)
+	var batches []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &batches))
+	require.Len(t, batches, 1)
+	assert.Equal(t, "jaeger", batches[0]["source-format"])
+	assert.Equal(t,
+		map[string]interface{}{"spans": []interface{}{map[string]interface{}{
+			"name":    map[string]interface{}{"value": "span"},
+			"span_id": "AQIDBAUGBwg=",
+		}}},
+		batches[0]["traces"])
+	assert.NotContains(t, batches[0], "metrics")
+
+	code, body = doRequest(t, h, http.MethodGet, "/debug/batches/traces/1?format=text")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, strings.HasPrefix(body, "# batch 0 received at "), body)
+	assert.Contains(t, body, " in jaeger format\n")
+	assert.Contains(t, body, `value: "span"`)
+
+	code, _ = doRequest(t, h, http.MethodGet, "/debug/batches/traces/1?format=xml")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doRequest(t, h, http.MethodGet, "/debug/batches/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doRequest(t, h, http.MethodPut, "/debug/batches")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	code, body = doRequest(t, h, http.MethodDelete, "/debug/batches")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"recording": true, "pipelines": {"traces/1": 0}}`, body)
+}
+
+func TestRun(t *testing.T) {
+	endpoint := testutils.GetAvailableLocalAddress(t)
+	asyncErrChan := make(chan error, 1)
+	closeFn, err := Run(asyncErrChan, endpoint, NewRecorder(1, true))
+	require.NoError(t, err)
+	defer closeFn()
+
+	resp, err := http.Get("http://" + endpoint + Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = Run(asyncErrChan, endpoint, NewRecorder(1, true))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit implements the token bucket limiting the rate of the data
+// accepted by the processors.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket refilled at rate tokens per second up to burst
+// tokens, one token per item, e.g. a span. A batch bigger than the bucket is
+// accepted when the bucket is full, the tokens then become negative until the
+// bucket is refilled. It can be used concurrently.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// New returns a Limiter accepting itemsPerSecond items per second, with
+// bursts of up to burst items. A burst of zero defaults to itemsPerSecond.
+func New(itemsPerSecond float64, burst int) *Limiter {
+	b := float64(burst)
+	if b <= 0 {
+		b = itemsPerSecond
+	}
+	rl := &Limiter{
+		rate:   itemsPerSecond,
+		burst:  b,
+		tokens: b,
+		now:    time.Now,
+	}
+	rl.last = rl.now()
+	return rl
+}
+
+// Take takes n tokens if they are available, otherwise it returns the time
+// after which they would be.
+func (rl *Limiter) Take(n int) (time.Duration, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+
+	needed := float64(n)
+	if needed > rl.burst {
+		needed = rl.burst
+	}
+	if rl.tokens >= needed {
+		rl.tokens -= float64(n)
+		return 0, true
+	}
+	return time.Duration((needed - rl.tokens) / rl.rate * float64(time.Second)), false
+}
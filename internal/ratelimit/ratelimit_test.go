@@ -0,0 +1,55 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rl := New(10, 0)
+	rl.now = func() time.Time { return now }
+	rl.last = now
+
+	_, ok := rl.Take(6)
+	assert.True(t, ok)
+	retryAfter, ok := rl.Take(6)
+	assert.False(t, ok)
+	assert.Equal(t, 200*time.Millisecond, retryAfter)
+
+	now = now.Add(200 * time.Millisecond)
+	_, ok = rl.Take(6)
+	assert.True(t, ok)
+
+	// The bucket holds at most the burst.
+	now = now.Add(time.Hour)
+	_, ok = rl.Take(10)
+	assert.True(t, ok)
+	_, ok = rl.Take(1)
+	assert.False(t, ok)
+
+	// A batch bigger than the burst is accepted when the bucket is full, the
+	// next batches wait until the debt is paid back.
+	now = now.Add(time.Second)
+	_, ok = rl.Take(25)
+	assert.True(t, ok)
+	retryAfter, ok = rl.Take(1)
+	assert.False(t, ok)
+	assert.Equal(t, 1600*time.Millisecond, retryAfter)
+}
@@ -0,0 +1,57 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Path is the path under which the status is served.
+const Path = "/status"
+
+// Handler returns the HTTP handler serving on Path the status returned by the
+// given function, in JSON.
+func Handler(status func() Status) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status())
+	})
+	return mux
+}
+
+// Run serves the status returned by the given function on the given endpoint.
+func Run(asyncErrorChannel chan<- error, endpoint string, status func() Status) (closeFn func() error, err error) {
+	ln, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to run the status endpoint on %q: %v", endpoint, err)
+	}
+
+	srv := http.Server{Handler: Handler(status)}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			asyncErrorChannel <- fmt.Errorf("failed to serve the status: %v", err)
+		}
+	}()
+
+	return srv.Close, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status summarizes the pipelines of the collector, the state of their
+// components and the data they processed, and serves it in JSON over HTTP for
+// the health dashboards and the tools operating the collector.
+package status
+
+import (
+	"sort"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/service/builder"
+)
+
+// Status is the status of the collector.
+type Status struct {
+	// Healthy is true if all the receivers and exporters are running.
+	Healthy    bool        `json:"healthy"`
+	Pipelines  []Pipeline  `json:"pipelines"`
+	Receivers  []Component `json:"receivers"`
+	Processors []Component `json:"processors"`
+	Exporters  []Component `json:"exporters"`
+}
+
+// Pipeline is a pipeline of the collector, with the names of its components
+// in the order the data goes through them.
+type Pipeline struct {
+	Name       string   `json:"name"`
+	InputType  string   `json:"input-type"`
+	Receivers  []string `json:"receivers"`
+	Processors []string `json:"processors"`
+	Exporters  []string `json:"exporters"`
+}
+
+// Component is the status of a receiver, processor or exporter. The counts are
+// the spans and metric points the component processed, they are only counted
+// if the metrics of the service are enabled.
+type Component struct {
+	Name     string           `json:"name"`
+	Type     string           `json:"type"`
+	State    string           `json:"state"`
+	Error    string           `json:"error,omitempty"`
+	Restarts int              `json:"restarts,omitempty"`
+	Counts   obsreport.Counts `json:"counts,omitempty"`
+}
+
+// New returns the status of the collector running the given config, from the
+// statuses of the receivers and exporters built and the counts of the
+// components. The processors of the pipelines are running once built.
+func New(
+	cfg *configmodels.Config,
+	receivers map[string]builder.ComponentStatus,
+	exporters map[string]builder.ComponentStatus,
+	counts obsreport.ComponentCounts,
+) Status {
+	status := Status{
+		Healthy:    true,
+		Pipelines:  []Pipeline{},
+		Receivers:  []Component{},
+		Processors: []Component{},
+		Exporters:  []Component{},
+	}
+
+	for _, p := range cfg.Pipelines {
+		status.Pipelines = append(status.Pipelines, Pipeline{
+			Name:       p.Name,
+			InputType:  p.InputType.GetString(),
+			Receivers:  nonNil(p.Receivers),
+			Processors: nonNil(p.Processors),
+			Exporters:  nonNil(p.Exporters),
+		})
+	}
+	sort.Slice(status.Pipelines, func(i, j int) bool {
+		return status.Pipelines[i].Name < status.Pipelines[j].Name
+	})
+
+	for name, rcvStatus := range receivers {
+		c := newComponent(name, cfg.Receivers[name].Type(), rcvStatus, counts["receiver"])
+		status.Healthy = status.Healthy && c.State == string(builder.StateRunning)
+		status.Receivers = append(status.Receivers, c)
+	}
+	for _, name := range pipelineProcessors(cfg) {
+		running := builder.ComponentStatus{State: builder.StateRunning}
+		status.Processors = append(status.Processors, newComponent(name, cfg.Processors[name].Type(), running, counts["processor"]))
+	}
+	for name, expStatus := range exporters {
+		c := newComponent(name, cfg.Exporters[name].Type(), expStatus, counts["exporter"])
+		status.Healthy = status.Healthy && c.State == string(builder.StateRunning)
+		status.Exporters = append(status.Exporters, c)
+	}
+	for _, components := range [][]Component{status.Receivers, status.Processors, status.Exporters} {
+		sortComponents(components)
+	}
+	return status
+}
+
+func newComponent(name, typeStr string, status builder.ComponentStatus, counts map[string]obsreport.Counts) Component {
+	c := Component{
+		Name:     name,
+		Type:     typeStr,
+		State:    string(status.State),
+		Restarts: status.Restarts,
+		Counts:   counts[name],
+	}
+	if status.Err != nil {
+		c.Error = status.Err.Error()
+	}
+	return c
+}
+
+// pipelineProcessors returns the names of the processors of the pipelines.
+func pipelineProcessors(cfg *configmodels.Config) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range cfg.Pipelines {
+		for _, name := range p.Processors {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func sortComponents(components []Component) {
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+}
+
+// nonNil returns an empty slice for nil so that it is marshaled as [].
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
@@ -0,0 +1,163 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/service/builder"
+)
+
+func testConfig() *configmodels.Config {
+	return &configmodels.Config{
+		Receivers: configmodels.Receivers{
+			"jaeger":   &configmodels.ReceiverSettings{TypeVal: "jaeger", NameVal: "jaeger"},
+			"zipkin/2": &configmodels.ReceiverSettings{TypeVal: "zipkin", NameVal: "zipkin/2"},
+		},
+		Processors: configmodels.Processors{
+			"batch":  &configmodels.ProcessorSettings{TypeVal: "batch", NameVal: "batch"},
+			"unused": &configmodels.ProcessorSettings{TypeVal: "batch", NameVal: "unused"},
+		},
+		Exporters: configmodels.Exporters{
+			"logging": &configmodels.ExporterSettings{TypeVal: "logging", NameVal: "logging"},
+		},
+		Pipelines: configmodels.Pipelines{
+			"traces": &configmodels.Pipeline{
+				Name:       "traces",
+				InputType:  configmodels.TracesDataType,
+				Receivers:  []string{"zipkin/2", "jaeger"},
+				Processors: []string{"batch"},
+				Exporters:  []string{"logging"},
+			},
+			"metrics": &configmodels.Pipeline{
+				Name:      "metrics",
+				InputType: configmodels.MetricsDataType,
+				Receivers: []string{"jaeger"},
+				Exporters: []string{"logging"},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	status := New(testConfig(),
+		map[string]builder.ComponentStatus{
+			"jaeger":   {State: builder.StateRunning},
+			"zipkin/2": {State: builder.StateRestarting, Err: errors.New("listener closed"), Restarts: 2},
+		},
+		map[string]builder.ComponentStatus{
+			"logging": {State: builder.StateRunning},
+		},
+		obsreport.ComponentCounts{
+			"receiver": {"jaeger": {"accepted_spans": 10}},
+			"exporter": {"logging": {"sent_spans": 10}},
+		})
+
+	assert.Equal(t, Status{
+		Healthy: false,
+		Pipelines: []Pipeline{
+			{
+				Name:       "metrics",
+				InputType:  "metrics",
+				Receivers:  []string{"jaeger"},
+				Processors: []string{},
+				Exporters:  []string{"logging"},
+			},
+			{
+				Name:       "traces",
+				InputType:  "traces",
+				Receivers:  []string{"zipkin/2", "jaeger"},
+				Processors: []string{"batch"},
+				Exporters:  []string{"logging"},
+			},
+		},
+		Receivers: []Component{
+			{Name: "jaeger", Type: "jaeger", State: "running", Counts: obsreport.Counts{"accepted_spans": 10}},
+			{Name: "zipkin/2", Type: "zipkin", State: "restarting", Error: "listener closed", Restarts: 2},
+		},
+		Processors: []Component{
+			{Name: "batch", Type: "batch", State: "running"},
+		},
+		Exporters: []Component{
+			{Name: "logging", Type: "logging", State: "running", Counts: obsreport.Counts{"sent_spans": 10}},
+		},
+	}, status)
+}
+
+func TestNew_Healthy(t *testing.T) {
+	running := builder.ComponentStatus{State: builder.StateRunning}
+	status := New(testConfig(),
+		map[string]builder.ComponentStatus{"jaeger": running, "zipkin/2": running},
+		map[string]builder.ComponentStatus{"logging": running},
+		nil)
+	assert.True(t, status.Healthy)
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(func() Status {
+		return New(&configmodels.Config{}, nil, nil, nil)
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t,
+		`{"healthy": true, "pipelines": [], "receivers": [], "processors": [], "exporters": []}`,
+		rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestRun(t *testing.T) {
+	endpoint := testutils.GetAvailableLocalAddress(t)
+	asyncErrChan := make(chan error, 1)
+	running := builder.ComponentStatus{State: builder.StateRunning}
+	closeFn, err := Run(asyncErrChan, endpoint, func() Status {
+		return New(testConfig(),
+			map[string]builder.ComponentStatus{"jaeger": running, "zipkin/2": running},
+			map[string]builder.ComponentStatus{"logging": running},
+			nil)
+	})
+	require.NoError(t, err)
+	defer closeFn()
+
+	resp, err := http.Get("http://" + endpoint + Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var status Status
+	require.NoError(t, json.Unmarshal(body, &status))
+	assert.True(t, status.Healthy)
+	assert.Len(t, status.Pipelines, 2)
+
+	_, err = Run(asyncErrChan, endpoint, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tuning
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Path is the path under which the tunable processors are served.
+const Path = "/tuning/processors"
+
+// Handler returns the HTTP handler serving the tunable processors of the
+// Tuner, to the requests carrying the bearer token:
+//
+//	GET /tuning/processors           the configuration of the tunable processors, by name
+//	GET /tuning/processors/<name>    the configuration of the processor
+//	PUT /tuning/processors/<name>    changes the settings of the processor given in the
+//	                                 body, in YAML or JSON, and returns its configuration
+//
+// The configurations are served in JSON, with the keys of the configuration
+// file.
+func (t *Tuner) Handler(bearerToken string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, t.serveIndex)
+	mux.HandleFunc(Path+"/", t.serveProcessor)
+	return withBearerToken(bearerToken, mux)
+}
+
+func withBearerToken(bearerToken string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + bearerToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got := []byte(req.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (t *Tuner) serveIndex(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	configs := make(map[string]interface{})
+	for _, name := range t.Names() {
+		cfg, _ := t.Config(name)
+		configs[name] = config.DescribeConfig(cfg)
+	}
+	writeJSON(w, configs)
+}
+
+func (t *Tuner) serveProcessor(w http.ResponseWriter, req *http.Request) {
+	// The names of the processors can contain slashes.
+	name := strings.TrimPrefix(req.URL.Path, Path+"/")
+	cfg, ok := t.Config(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("processor %q is not tunable", name), http.StatusNotFound)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var err error
+		if cfg, err = t.Tune(name, req.Body); err != nil {
+			http.Error(w, fmt.Sprintf("failed to tune processor %q: %v", name, err), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, config.DescribeConfig(cfg))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// Run serves the tunable processors of the Tuner on the endpoint of the given
+// settings.
+func Run(asyncErrorChannel chan<- error, settings configmodels.Tuning, t *Tuner) (closeFn func() error, err error) {
+	var tlsCfg *tls.Config
+	if settings.TLSCredentials != nil {
+		if tlsCfg, err = settings.TLSCredentials.LoadTLSConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load the TLS credentials of the tuning endpoint: %v", err)
+		}
+	}
+
+	ln, err := net.Listen("tcp", settings.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to run the tuning endpoint on %q: %v", settings.Endpoint, err)
+	}
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
+
+	srv := http.Server{Handler: t.Handler(settings.BearerToken)}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			asyncErrorChannel <- fmt.Errorf("failed to serve the tuning endpoint: %v", err)
+		}
+	}()
+
+	return srv.Close, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tuning changes the configuration of the tunable processors, e.g. the
+// sampling processors, while the service runs.
+package tuning
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+// Tuner changes the configuration of the tunable processors of the pipelines.
+type Tuner struct {
+	logger    *zap.Logger
+	factories map[string]processor.Factory
+	tunables  map[string][]processor.Tunable
+
+	mu      sync.Mutex
+	configs map[string]configmodels.Processor
+}
+
+// NewTuner returns a Tuner of the given tunable processors, keyed by processor
+// name, see builder.PipelineProcessors.Tunables. The processors are
+// configured by the given processor configurations.
+func NewTuner(
+	logger *zap.Logger,
+	factories map[string]processor.Factory,
+	configs configmodels.Processors,
+	tunables map[string][]processor.Tunable,
+) *Tuner {
+	t := &Tuner{
+		logger:    logger,
+		factories: factories,
+		tunables:  tunables,
+		configs:   make(map[string]configmodels.Processor, len(tunables)),
+	}
+	for name := range tunables {
+		t.configs[name] = configs[name]
+	}
+	return t
+}
+
+// Names returns the sorted names of the tunable processors.
+func (t *Tuner) Names() []string {
+	names := make([]string, 0, len(t.tunables))
+	for name := range t.tunables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config returns the current configuration of the tunable processor, false if
+// there is no such processor.
+func (t *Tuner) Config(name string) (configmodels.Processor, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cfg, ok := t.configs[name]
+	return cfg, ok
+}
+
+// Tune changes the settings of the tunable processor to the ones read from r,
+// in YAML or JSON, as written in the configuration file. The settings missing
+// from r keep their current value. All the instances of the processor, one
+// per pipeline using it, are tuned.
+func (t *Tuner) Tune(name string, r io.Reader) (configmodels.Processor, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cfg, ok := t.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("processor %q is not tunable", name)
+	}
+	factory := t.factories[cfg.Type()]
+	if factory == nil {
+		return nil, fmt.Errorf("unknown processor type %q", cfg.Type())
+	}
+	updated, err := config.UpdateProcessorConfig(factory, cfg, r)
+	if err != nil {
+		return nil, err
+	}
+	// The instances are of the same type, they all refuse an invalid
+	// configuration: the first one fails before any is changed.
+	for _, tunable := range t.tunables[name] {
+		if err := tunable.Tune(updated); err != nil {
+			return nil, err
+		}
+	}
+	t.configs[name] = updated
+	t.logger.Info("Processor tuned", zap.String("processor", name))
+	return updated, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tuning
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/probabilisticsampler"
+)
+
+const testToken = "some-token"
+
+func newTestTuner(t *testing.T) *Tuner {
+	factory := &probabilisticsampler.Factory{}
+	cfg := factory.CreateDefaultConfig().(*probabilisticsampler.Config)
+	cfg.SetName("probabilistic-sampler/1")
+	cfg.SamplingPercentage = 10
+
+	var tunables []processor.Tunable
+	for i := 0; i < 2; i++ {
+		tp, err := factory.CreateTraceProcessor(zap.NewNop(), new(exportertest.SinkTraceExporter), cfg)
+		require.NoError(t, err)
+		tunables = append(tunables, tp.(processor.Tunable))
+	}
+	return NewTuner(
+		zap.NewNop(),
+		map[string]processor.Factory{factory.Type(): factory},
+		configmodels.Processors{cfg.Name(): cfg},
+		map[string][]processor.Tunable{cfg.Name(): tunables},
+	)
+}
+
+func doRequest(t *testing.T, h http.Handler, method, target, token string, body io.Reader) (int, string) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	h.ServeHTTP(rec, req)
+	respBody, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(respBody)
+}
+
+func TestHandler(t *testing.T) {
+	tuner := newTestTuner(t)
+	h := tuner.Handler(testToken)
+
+	code, _ := doRequest(t, h, http.MethodGet, Path, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = doRequest(t, h, http.MethodGet, Path, "wrong-token", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, body := doRequest(t, h, http.MethodGet, Path, testToken, nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"probabilistic-sampler/1": {"disabled": false, "sampling-percentage": 10, "hash-seed": 0}}`, body)
+
+	code, body = doRequest(t, h, http.MethodPut, Path+"/probabilistic-sampler/1", testToken,
+		strings.NewReader(`{"sampling-percentage": 25}`))
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"disabled": false, "sampling-percentage": 25, "hash-seed": 0}`, body)
+
+	// An invalid configuration is refused and the current one is kept.
+	code, _ = doRequest(t, h, http.MethodPut, Path+"/probabilistic-sampler/1", testToken,
+		strings.NewReader("sampling-percentage: -1\n"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doRequest(t, h, http.MethodPut, Path+"/probabilistic-sampler/1", testToken,
+		strings.NewReader(`{"unknown": 1}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, body = doRequest(t, h, http.MethodGet, Path+"/probabilistic-sampler/1", testToken, nil)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"disabled": false, "sampling-percentage": 25, "hash-seed": 0}`, body)
+
+	code, _ = doRequest(t, h, http.MethodGet, Path+"/batch", testToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doRequest(t, h, http.MethodDelete, Path+"/probabilistic-sampler/1", testToken, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestRun(t *testing.T) {
+	endpoint := testutils.GetAvailableLocalAddress(t)
+	asyncErrChan := make(chan error, 1)
+	settings := configmodels.Tuning{Endpoint: endpoint, BearerToken: testToken}
+	closeFn, err := Run(asyncErrChan, settings, newTestTuner(t))
+	require.NoError(t, err)
+	defer closeFn()
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+endpoint+Path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = Run(asyncErrChan, settings, newTestTuner(t))
+	assert.Error(t, err)
+}
@@ -26,6 +26,9 @@ var Version = "latest"
 // GitHash variable will be replaced at link time after `make` has been run.
 var GitHash = "<NOT PROPERLY GENERATED>"
 
+// BuildDate variable will be replaced at link time after `make` has been run.
+var BuildDate = ""
+
 // Info returns a formatted string, with linebreaks, intended to be displayed
 // on stdout.
 func Info() string {
@@ -33,6 +36,7 @@ func Info() string {
 	rows := [][2]string{
 		{"Version", Version},
 		{"GitHash", GitHash},
+		{"BuildDate", BuildDate},
 		{"Goversion", runtime.Version()},
 		{"OS", runtime.GOOS},
 		{"Architecture", runtime.GOARCH},
@@ -36,6 +36,13 @@ var (
 
 	mExporterReceivedSpans = stats.Int64("oc.io/exporter/received_spans", "Counts the number of spans received by the exporter", "1")
 	mExporterDroppedSpans  = stats.Int64("oc.io/exporter/dropped_spans", "Counts the number of spans received by the exporter", "1")
+
+	mExporterQueueLength     = stats.Int64("oc.io/exporter/queue_length", "Current number of requests in the exporter queue", "1")
+	mExporterQueueFullDrops  = stats.Int64("oc.io/exporter/queue_full_drops", "Counts the number of requests dropped because the exporter queue was full", "1")
+	mExporterRetries         = stats.Int64("oc.io/exporter/retries", "Counts the number of retried export requests", "1")
+	mExporterRetriesExceeded = stats.Int64("oc.io/exporter/retries_exceeded", "Counts the number of export requests dropped after exhausting retries", "1")
+	mExporterPermanentErrors = stats.Int64("oc.io/exporter/permanent_errors", "Counts the number of export requests dropped because of a permanent error", "1")
+	mExporterSendFailed      = stats.Int64("oc.io/exporter/send_failed", "Counts the number of queued export requests that failed to be sent", "1")
 )
 
 // TagKeyReceiver defines tag key for Receiver.
@@ -80,12 +87,72 @@ var ViewExporterDroppedSpans = &view.View{
 	TagKeys:     []tag.Key{TagKeyReceiver, TagKeyExporter},
 }
 
+// ViewExporterQueueLength defines the view for the exporter queue length metric.
+var ViewExporterQueueLength = &view.View{
+	Name:        mExporterQueueLength.Name(),
+	Description: mExporterQueueLength.Description(),
+	Measure:     mExporterQueueLength,
+	Aggregation: view.LastValue(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
+// ViewExporterQueueFullDrops defines the view for the exporter queue full drops metric.
+var ViewExporterQueueFullDrops = &view.View{
+	Name:        mExporterQueueFullDrops.Name(),
+	Description: mExporterQueueFullDrops.Description(),
+	Measure:     mExporterQueueFullDrops,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
+// ViewExporterRetries defines the view for the exporter retries metric.
+var ViewExporterRetries = &view.View{
+	Name:        mExporterRetries.Name(),
+	Description: mExporterRetries.Description(),
+	Measure:     mExporterRetries,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
+// ViewExporterRetriesExceeded defines the view for the exporter retries exceeded metric.
+var ViewExporterRetriesExceeded = &view.View{
+	Name:        mExporterRetriesExceeded.Name(),
+	Description: mExporterRetriesExceeded.Description(),
+	Measure:     mExporterRetriesExceeded,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
+// ViewExporterPermanentErrors defines the view for the exporter permanent errors metric.
+var ViewExporterPermanentErrors = &view.View{
+	Name:        mExporterPermanentErrors.Name(),
+	Description: mExporterPermanentErrors.Description(),
+	Measure:     mExporterPermanentErrors,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
+// ViewExporterSendFailed defines the view for the exporter failed queued requests metric.
+var ViewExporterSendFailed = &view.View{
+	Name:        mExporterSendFailed.Name(),
+	Description: mExporterSendFailed.Description(),
+	Measure:     mExporterSendFailed,
+	Aggregation: view.Sum(),
+	TagKeys:     []tag.Key{TagKeyExporter},
+}
+
 // AllViews has the views for the metrics provided by the agent.
 var AllViews = []*view.View{
 	ViewReceiverReceivedSpans,
 	ViewReceiverDroppedSpans,
 	ViewExporterReceivedSpans,
 	ViewExporterDroppedSpans,
+	ViewExporterQueueLength,
+	ViewExporterQueueFullDrops,
+	ViewExporterRetries,
+	ViewExporterRetriesExceeded,
+	ViewExporterPermanentErrors,
+	ViewExporterSendFailed,
 }
 
 // ContextWithReceiverName adds the tag "oc_receiver" and the name of the receiver as the value,
@@ -116,6 +183,42 @@ func RecordTraceExporterMetrics(ctx context.Context, receivedSpans int, droppedS
 	stats.Record(ctx, mExporterReceivedSpans.M(int64(receivedSpans)), mExporterDroppedSpans.M(int64(droppedSpans)))
 }
 
+// RecordExporterQueueLength records the current number of requests waiting in the exporter queue.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterQueueLength(ctx context.Context, length int) {
+	stats.Record(ctx, mExporterQueueLength.M(int64(length)))
+}
+
+// RecordExporterQueueFullDrop records that a request was dropped because the exporter queue was full.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterQueueFullDrop(ctx context.Context) {
+	stats.Record(ctx, mExporterQueueFullDrops.M(1))
+}
+
+// RecordExporterRetry records that an export request is going to be retried.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterRetry(ctx context.Context) {
+	stats.Record(ctx, mExporterRetries.M(1))
+}
+
+// RecordExporterRetriesExceeded records that an export request was dropped after exhausting its retries.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterRetriesExceeded(ctx context.Context) {
+	stats.Record(ctx, mExporterRetriesExceeded.M(1))
+}
+
+// RecordExporterPermanentError records that an export request was dropped because of a permanent error.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterPermanentError(ctx context.Context) {
+	stats.Record(ctx, mExporterPermanentErrors.M(1))
+}
+
+// RecordExporterSendFailed records that a queued export request failed and was dropped.
+// Use it with a context.Context generated using ContextWithExporterName().
+func RecordExporterSendFailed(ctx context.Context) {
+	stats.Record(ctx, mExporterSendFailed.M(1))
+}
+
 // GRPCServerWithObservabilityEnabled creates a gRPC server that at a bare minimum has
 // the OpenCensus ocgrpc server stats handler enabled for tracing and stats.
 // Use it instead of invoking grpc.NewServer directly.
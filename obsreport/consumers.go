@@ -0,0 +1,236 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package obsreport
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// WrapTraceReceiver returns the consumer a receiver sends its spans to,
+// recording the spans accepted or refused by next and its latency, and
+// tracing the call to next.
+func WrapTraceReceiver(receiver string, next consumer.TraceConsumer) consumer.TraceConsumer {
+	return &receiverTraceConsumer{receiver: receiver, next: next}
+}
+
+// WrapMetricsReceiver returns the consumer a receiver sends its metrics to,
+// recording the metric points accepted or refused by next and its latency,
+// and tracing the call to next.
+func WrapMetricsReceiver(receiver string, next consumer.MetricsConsumer) consumer.MetricsConsumer {
+	return &receiverMetricsConsumer{receiver: receiver, next: next}
+}
+
+// WrapTracePipeline returns the consumer a receiver sends its spans to for
+// one of its pipelines, when it has many, recording the spans refused by the
+// pipeline. They are dropped for this pipeline only, the data is sent to the
+// other pipelines anyway.
+func WrapTracePipeline(receiver, pipeline string, next consumer.TraceConsumer) consumer.TraceConsumer {
+	return &pipelineTraceConsumer{receiver: receiver, pipeline: pipeline, next: next}
+}
+
+// WrapMetricsPipeline is the equivalent of WrapTracePipeline for the metrics.
+func WrapMetricsPipeline(receiver, pipeline string, next consumer.MetricsConsumer) consumer.MetricsConsumer {
+	return &pipelineMetricsConsumer{receiver: receiver, pipeline: pipeline, next: next}
+}
+
+// WrapTraceProcessor returns the processor recording the spans it accepts or
+// refuses, and tracing its calls.
+func WrapTraceProcessor(processor string, proc consumer.TraceConsumer) consumer.TraceConsumer {
+	return &processorTraceConsumer{processor: processor, next: proc}
+}
+
+// WrapMetricsProcessor returns the processor recording the metric points it
+// accepts or refuses, and tracing its calls.
+func WrapMetricsProcessor(processor string, proc consumer.MetricsConsumer) consumer.MetricsConsumer {
+	return &processorMetricsConsumer{processor: processor, next: proc}
+}
+
+// WrapTraceExporter returns the exporter recording the spans it sends or
+// fails to send and its latency, and tracing its calls. It declares the
+// consumer.Capabilities of the exporter.
+func WrapTraceExporter(exporter string, exp consumer.TraceConsumer) consumer.TraceConsumer {
+	return &exporterTraceConsumer{exporter: exporter, next: exp}
+}
+
+// WrapMetricsExporter returns the exporter recording the metric points it
+// sends or fails to send and its latency, and tracing its calls. It declares
+// the consumer.Capabilities of the exporter.
+func WrapMetricsExporter(exporter string, exp consumer.MetricsConsumer) consumer.MetricsConsumer {
+	return &exporterMetricsConsumer{exporter: exporter, next: exp}
+}
+
+type receiverTraceConsumer struct {
+	receiver string
+	next     consumer.TraceConsumer
+}
+
+func (c *receiverTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	start := time.Now()
+	ctx, span := startSpan(ctx, "receiver/"+c.receiver, numSpansAttribute, len(td.Spans))
+	err := c.next.ConsumeTraceData(ctx, td)
+	endSpan(span, err)
+	recordReceiver(ReceiverContext(ctx, c.receiver), start, err,
+		mReceiverAcceptedSpans, mReceiverRefusedSpans, len(td.Spans))
+	return err
+}
+
+type receiverMetricsConsumer struct {
+	receiver string
+	next     consumer.MetricsConsumer
+}
+
+func (c *receiverMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	start := time.Now()
+	ctx, span := startSpan(ctx, "receiver/"+c.receiver, numMetricPointsAttribute, MetricPointCount(md.Metrics))
+	err := c.next.ConsumeMetricsData(ctx, md)
+	endSpan(span, err)
+	recordReceiver(ReceiverContext(ctx, c.receiver), start, err,
+		mReceiverAcceptedMetricPoints, mReceiverRefusedMetricPoints, MetricPointCount(md.Metrics))
+	return err
+}
+
+func recordReceiver(ctx context.Context, start time.Time, err error, accepted, refused *stats.Int64Measure, count int) {
+	m := accepted
+	if err != nil {
+		m = refused
+	}
+	stats.Record(ctx, m.M(int64(count)), mReceiverLatency.M(sinceMillis(start)))
+}
+
+type pipelineTraceConsumer struct {
+	receiver string
+	pipeline string
+	next     consumer.TraceConsumer
+}
+
+func (c *pipelineTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	err := c.next.ConsumeTraceData(ctx, td)
+	if err != nil {
+		recordPipelineDropped(ctx, c.receiver, c.pipeline, mReceiverPipelineDroppedSpans, len(td.Spans))
+	}
+	return err
+}
+
+type pipelineMetricsConsumer struct {
+	receiver string
+	pipeline string
+	next     consumer.MetricsConsumer
+}
+
+func (c *pipelineMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	err := c.next.ConsumeMetricsData(ctx, md)
+	if err != nil {
+		recordPipelineDropped(ctx, c.receiver, c.pipeline, mReceiverPipelineDroppedMetricPoints, MetricPointCount(md.Metrics))
+	}
+	return err
+}
+
+func recordPipelineDropped(ctx context.Context, receiver, pipeline string, dropped *stats.Int64Measure, count int) {
+	ctx, _ = tag.New(ctx, tag.Upsert(TagKeyReceiver, receiver), tag.Upsert(TagKeyPipeline, pipeline))
+	stats.Record(ctx, dropped.M(int64(count)))
+}
+
+type processorTraceConsumer struct {
+	processor string
+	next      consumer.TraceConsumer
+}
+
+func (c *processorTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	ctx, span := startSpan(ctx, "processor/"+c.processor, numSpansAttribute, len(td.Spans))
+	err := c.next.ConsumeTraceData(ctx, td)
+	endSpan(span, err)
+	recordProcessor(ProcessorContext(ctx, c.processor), err,
+		mProcessorAcceptedSpans, mProcessorRefusedSpans, len(td.Spans))
+	return err
+}
+
+type processorMetricsConsumer struct {
+	processor string
+	next      consumer.MetricsConsumer
+}
+
+func (c *processorMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	ctx, span := startSpan(ctx, "processor/"+c.processor, numMetricPointsAttribute, MetricPointCount(md.Metrics))
+	err := c.next.ConsumeMetricsData(ctx, md)
+	endSpan(span, err)
+	recordProcessor(ProcessorContext(ctx, c.processor), err,
+		mProcessorAcceptedMetricPoints, mProcessorRefusedMetricPoints, MetricPointCount(md.Metrics))
+	return err
+}
+
+func recordProcessor(ctx context.Context, err error, accepted, refused *stats.Int64Measure, count int) {
+	m := accepted
+	if err != nil {
+		m = refused
+	}
+	stats.Record(ctx, m.M(int64(count)))
+}
+
+type exporterTraceConsumer struct {
+	exporter string
+	next     consumer.TraceConsumer
+}
+
+var _ consumer.Capable = (*exporterTraceConsumer)(nil)
+
+func (c *exporterTraceConsumer) Capabilities() consumer.Capabilities {
+	return consumer.GetCapabilities(c.next)
+}
+
+func (c *exporterTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	start := time.Now()
+	ctx, span := startSpan(ctx, "exporter/"+c.exporter, numSpansAttribute, len(td.Spans))
+	err := c.next.ConsumeTraceData(ctx, td)
+	endSpan(span, err)
+	recordExporter(ExporterContext(ctx, c.exporter), start, err,
+		mExporterSentSpans, mExporterSendFailedSpans, len(td.Spans))
+	return err
+}
+
+type exporterMetricsConsumer struct {
+	exporter string
+	next     consumer.MetricsConsumer
+}
+
+var _ consumer.Capable = (*exporterMetricsConsumer)(nil)
+
+func (c *exporterMetricsConsumer) Capabilities() consumer.Capabilities {
+	return consumer.GetCapabilities(c.next)
+}
+
+func (c *exporterMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	start := time.Now()
+	ctx, span := startSpan(ctx, "exporter/"+c.exporter, numMetricPointsAttribute, MetricPointCount(md.Metrics))
+	err := c.next.ConsumeMetricsData(ctx, md)
+	endSpan(span, err)
+	recordExporter(ExporterContext(ctx, c.exporter), start, err,
+		mExporterSentMetricPoints, mExporterSendFailedMetricPoints, MetricPointCount(md.Metrics))
+	return err
+}
+
+func recordExporter(ctx context.Context, start time.Time, err error, sent, failed *stats.Int64Measure, count int) {
+	m := sent
+	if err != nil {
+		m = failed
+	}
+	stats.Record(ctx, m.M(int64(count)), mExporterLatency.M(sinceMillis(start)))
+}
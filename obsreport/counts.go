@@ -0,0 +1,95 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package obsreport
+
+import (
+	"strings"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// Counts are the numbers of spans or metric points counted by the metrics of
+// a component since the start of the service, by the name of the metric
+// without the kind of the component, e.g. "accepted_spans".
+type Counts map[string]int64
+
+// ComponentCounts are the Counts of the components by kind, "receiver",
+// "processor" or "exporter", then by name.
+type ComponentCounts map[string]map[string]Counts
+
+// countedMeasures are the measures summed in the Counts, with the tag of the
+// component they are counted for.
+var countedMeasures = []struct {
+	measure *stats.Int64Measure
+	key     tag.Key
+}{
+	{mReceiverAcceptedSpans, TagKeyReceiver},
+	{mReceiverRefusedSpans, TagKeyReceiver},
+	{mReceiverAcceptedMetricPoints, TagKeyReceiver},
+	{mReceiverRefusedMetricPoints, TagKeyReceiver},
+	{mReceiverPipelineDroppedSpans, TagKeyReceiver},
+	{mReceiverPipelineDroppedMetricPoints, TagKeyReceiver},
+	{mProcessorAcceptedSpans, TagKeyProcessor},
+	{mProcessorRefusedSpans, TagKeyProcessor},
+	{mProcessorDroppedSpans, TagKeyProcessor},
+	{mProcessorAcceptedMetricPoints, TagKeyProcessor},
+	{mProcessorRefusedMetricPoints, TagKeyProcessor},
+	{mProcessorDroppedMetricPoints, TagKeyProcessor},
+	{mExporterSentSpans, TagKeyExporter},
+	{mExporterSendFailedSpans, TagKeyExporter},
+	{mExporterSentMetricPoints, TagKeyExporter},
+	{mExporterSendFailedMetricPoints, TagKeyExporter},
+}
+
+// ReadCounts returns the counts of the components from the views of the
+// metrics, summed over the other tags, e.g. the transports of a receiver. The
+// metrics whose views are not registered, see Views, are not counted.
+func ReadCounts() ComponentCounts {
+	counts := make(ComponentCounts)
+	for _, m := range countedMeasures {
+		rows, err := view.RetrieveData(m.measure.Name())
+		if err != nil {
+			continue
+		}
+		kind := m.key.Name()
+		name := strings.TrimPrefix(m.measure.Name(), kind+"/")
+		for _, row := range rows {
+			sum, ok := row.Data.(*view.SumData)
+			if !ok {
+				continue
+			}
+			component := tagValue(row.Tags, m.key)
+			if counts[kind] == nil {
+				counts[kind] = make(map[string]Counts)
+			}
+			if counts[kind][component] == nil {
+				counts[kind][component] = make(Counts)
+			}
+			counts[kind][component][name] += int64(sum.Value)
+		}
+	}
+	return counts
+}
+
+func tagValue(tags []tag.Tag, key tag.Key) string {
+	for _, t := range tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,278 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package obsreport records the metrics that every receiver, processor and
+// exporter reports about the data going through it, with the same tags for
+// all the components: the spans and metric points accepted, refused or
+// dropped, the latency and the queue sizes.
+//
+// The service builder wraps the consumers of the components so that the
+// accepted and refused data is recorded for all of them, the components only
+// record what the builder cannot see, e.g. the transport of a receiver, the
+// data dropped by a processor or the size of a queue.
+//
+// The wrappers also trace the calls to the components, the sampled spans can
+// be sent to a pipeline of the service with a SpanExporter.
+package obsreport
+
+import (
+	"context"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
+)
+
+// Tag keys of the metrics, the name of the component in the configuration,
+// the transport of the data received by a receiver, e.g. "grpc" or "http", the
+// pipeline a receiver sends the data to and the reason a receiver refused a
+// request.
+var (
+	TagKeyReceiver, _  = tag.NewKey("receiver")
+	TagKeyTransport, _ = tag.NewKey("transport")
+	TagKeyProcessor, _ = tag.NewKey("processor")
+	TagKeyExporter, _  = tag.NewKey("exporter")
+	TagKeyPipeline, _  = tag.NewKey("pipeline")
+	TagKeyReason, _    = tag.NewKey("reason")
+)
+
+// Reasons of the requests refused by a receiver, see
+// RecordReceiverRefusedRequest.
+const (
+	// RefusedReasonConnections is the reason of the connections closed
+	// beyond the maximum number of connections.
+	RefusedReasonConnections = "connections"
+	// RefusedReasonRate is the reason of the requests exceeding the maximum
+	// rate.
+	RefusedReasonRate = "rate"
+	// RefusedReasonBatchSize is the reason of the requests with too many
+	// spans or metrics.
+	RefusedReasonBatchSize = "batch-size"
+	// RefusedReasonRequestSize is the reason of the requests whose body or
+	// message is too large.
+	RefusedReasonRequestSize = "request-size"
+	// RefusedReasonAttributeLength is the reason of the requests with a too
+	// long attribute or label value.
+	RefusedReasonAttributeLength = "attribute-length"
+)
+
+var (
+	mReceiverAcceptedSpans        = stats.Int64("receiver/accepted_spans", "Number of spans successfully pushed into the pipeline.", stats.UnitDimensionless)
+	mReceiverRefusedSpans         = stats.Int64("receiver/refused_spans", "Number of spans that could not be pushed into the pipeline.", stats.UnitDimensionless)
+	mReceiverAcceptedMetricPoints = stats.Int64("receiver/accepted_metric_points", "Number of metric points successfully pushed into the pipeline.", stats.UnitDimensionless)
+	mReceiverRefusedMetricPoints  = stats.Int64("receiver/refused_metric_points", "Number of metric points that could not be pushed into the pipeline.", stats.UnitDimensionless)
+	mReceiverRefusedRequests      = stats.Int64("receiver/refused_requests", "Number of requests, or connections, refused by the limits of the receiver before their data was pushed into the pipeline.", stats.UnitDimensionless)
+	mReceiverLatency              = stats.Float64("receiver/latency", "Time taken by the pipeline to accept or refuse the data.", stats.UnitMilliseconds)
+
+	mReceiverPipelineDroppedSpans        = stats.Int64("receiver/pipeline_dropped_spans", "Number of spans refused by one of the pipelines of the receiver, the other pipelines may have accepted them.", stats.UnitDimensionless)
+	mReceiverPipelineDroppedMetricPoints = stats.Int64("receiver/pipeline_dropped_metric_points", "Number of metric points refused by one of the pipelines of the receiver, the other pipelines may have accepted them.", stats.UnitDimensionless)
+
+	mProcessorAcceptedSpans        = stats.Int64("processor/accepted_spans", "Number of spans successfully pushed into the next component.", stats.UnitDimensionless)
+	mProcessorRefusedSpans         = stats.Int64("processor/refused_spans", "Number of spans refused by the processor.", stats.UnitDimensionless)
+	mProcessorDroppedSpans         = stats.Int64("processor/dropped_spans", "Number of spans dropped by the processor.", stats.UnitDimensionless)
+	mProcessorAcceptedMetricPoints = stats.Int64("processor/accepted_metric_points", "Number of metric points successfully pushed into the next component.", stats.UnitDimensionless)
+	mProcessorRefusedMetricPoints  = stats.Int64("processor/refused_metric_points", "Number of metric points refused by the processor.", stats.UnitDimensionless)
+	mProcessorDroppedMetricPoints  = stats.Int64("processor/dropped_metric_points", "Number of metric points dropped by the processor.", stats.UnitDimensionless)
+	mProcessorQueueSize            = stats.Int64("processor/queue_size", "Current number of batches in the queue of the processor.", stats.UnitDimensionless)
+	mProcessorQueueCapacity        = stats.Int64("processor/queue_capacity", "Maximum number of batches in the queue of the processor.", stats.UnitDimensionless)
+
+	mExporterSentSpans              = stats.Int64("exporter/sent_spans", "Number of spans successfully sent to the destination.", stats.UnitDimensionless)
+	mExporterSendFailedSpans        = stats.Int64("exporter/send_failed_spans", "Number of spans the exporter failed to send or to queue.", stats.UnitDimensionless)
+	mExporterSentMetricPoints       = stats.Int64("exporter/sent_metric_points", "Number of metric points successfully sent to the destination.", stats.UnitDimensionless)
+	mExporterSendFailedMetricPoints = stats.Int64("exporter/send_failed_metric_points", "Number of metric points the exporter failed to send or to queue.", stats.UnitDimensionless)
+	mExporterLatency                = stats.Float64("exporter/latency", "Time taken by the exporter to send or to queue the data.", stats.UnitMilliseconds)
+	mExporterQueueSize              = stats.Int64("exporter/queue_size", "Current number of requests in the sending queue of the exporter.", stats.UnitDimensionless)
+	mExporterQueueCapacity          = stats.Int64("exporter/queue_capacity", "Maximum number of requests in the sending queue of the exporter.", stats.UnitDimensionless)
+)
+
+// Views returns the views of the metrics for the given telemetry level: none
+// for None, the accepted, refused, dropped and sent data for Basic, plus the
+// queue sizes and capacities for Normal, plus the latencies for Detailed.
+func Views(level telemetry.Level) []*view.View {
+	if level == telemetry.None {
+		return nil
+	}
+
+	receiverTags := []tag.Key{TagKeyReceiver, TagKeyTransport}
+	receiverPipelineTags := []tag.Key{TagKeyReceiver, TagKeyPipeline}
+	processorTags := []tag.Key{TagKeyProcessor}
+	exporterTags := []tag.Key{TagKeyExporter}
+
+	var views []*view.View
+	for _, m := range []*stats.Int64Measure{
+		mReceiverAcceptedSpans,
+		mReceiverRefusedSpans,
+		mReceiverAcceptedMetricPoints,
+		mReceiverRefusedMetricPoints,
+	} {
+		views = append(views, sumView(m, receiverTags))
+	}
+	views = append(views,
+		sumView(mReceiverRefusedRequests, []tag.Key{TagKeyReceiver, TagKeyTransport, TagKeyReason}),
+		sumView(mReceiverPipelineDroppedSpans, receiverPipelineTags),
+		sumView(mReceiverPipelineDroppedMetricPoints, receiverPipelineTags))
+	for _, m := range []*stats.Int64Measure{
+		mProcessorAcceptedSpans,
+		mProcessorRefusedSpans,
+		mProcessorDroppedSpans,
+		mProcessorAcceptedMetricPoints,
+		mProcessorRefusedMetricPoints,
+		mProcessorDroppedMetricPoints,
+	} {
+		views = append(views, sumView(m, processorTags))
+	}
+	for _, m := range []*stats.Int64Measure{
+		mExporterSentSpans,
+		mExporterSendFailedSpans,
+		mExporterSentMetricPoints,
+		mExporterSendFailedMetricPoints,
+	} {
+		views = append(views, sumView(m, exporterTags))
+	}
+	if level == telemetry.Basic {
+		return views
+	}
+
+	views = append(views,
+		lastValueView(mProcessorQueueSize, processorTags),
+		lastValueView(mProcessorQueueCapacity, processorTags),
+		lastValueView(mExporterQueueSize, exporterTags),
+		lastValueView(mExporterQueueCapacity, exporterTags))
+	if level == telemetry.Normal {
+		return views
+	}
+
+	return append(views,
+		latencyView(mReceiverLatency, receiverTags),
+		latencyView(mExporterLatency, exporterTags))
+}
+
+func sumView(m *stats.Int64Measure, tagKeys []tag.Key) *view.View {
+	return &view.View{
+		Name:        m.Name(),
+		Description: m.Description(),
+		Measure:     m,
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+}
+
+func lastValueView(m *stats.Int64Measure, tagKeys []tag.Key) *view.View {
+	return &view.View{
+		Name:        m.Name(),
+		Description: m.Description(),
+		Measure:     m,
+		TagKeys:     tagKeys,
+		Aggregation: view.LastValue(),
+	}
+}
+
+func latencyView(m *stats.Float64Measure, tagKeys []tag.Key) *view.View {
+	return &view.View{
+		Name:        m.Name(),
+		Description: m.Description(),
+		Measure:     m,
+		TagKeys:     tagKeys,
+		Aggregation: view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	}
+}
+
+// ReceiverContext returns a copy of the context tagged with the receiver.
+func ReceiverContext(ctx context.Context, receiver string) context.Context {
+	ctx, _ = tag.New(ctx, tag.Upsert(TagKeyReceiver, receiver))
+	return ctx
+}
+
+// WithTransport returns a copy of the context tagged with the transport of
+// the data, e.g. "grpc" or "http". The receivers call it on the context they
+// pass to their next consumer.
+func WithTransport(ctx context.Context, transport string) context.Context {
+	ctx, _ = tag.New(ctx, tag.Upsert(TagKeyTransport, transport))
+	return ctx
+}
+
+// ProcessorContext returns a copy of the context tagged with the processor.
+func ProcessorContext(ctx context.Context, processor string) context.Context {
+	ctx, _ = tag.New(ctx, tag.Upsert(TagKeyProcessor, processor))
+	return ctx
+}
+
+// ExporterContext returns a copy of the context tagged with the exporter.
+func ExporterContext(ctx context.Context, exporter string) context.Context {
+	ctx, _ = tag.New(ctx, tag.Upsert(TagKeyExporter, exporter))
+	return ctx
+}
+
+// RecordReceiverRefusedRequest records a request refused by the receiver of
+// the context, see ReceiverContext, for the given reason, e.g.
+// RefusedReasonRate.
+func RecordReceiverRefusedRequest(receiverCtx context.Context, reason string) {
+	ctx, _ := tag.New(receiverCtx, tag.Upsert(TagKeyReason, reason))
+	stats.Record(ctx, mReceiverRefusedRequests.M(1))
+}
+
+// ProcessorTraceDataDropped records the spans dropped by the processor of the
+// context, see ProcessorContext.
+func ProcessorTraceDataDropped(processorCtx context.Context, numSpans int) {
+	stats.Record(processorCtx, mProcessorDroppedSpans.M(int64(numSpans)))
+}
+
+// ProcessorMetricsDataDropped records the metric points dropped by the
+// processor of the context, see ProcessorContext.
+func ProcessorMetricsDataDropped(processorCtx context.Context, numPoints int) {
+	stats.Record(processorCtx, mProcessorDroppedMetricPoints.M(int64(numPoints)))
+}
+
+// RecordProcessorQueueSize records the current size of the queue of the
+// processor of the context, see ProcessorContext.
+func RecordProcessorQueueSize(processorCtx context.Context, size int) {
+	stats.Record(processorCtx, mProcessorQueueSize.M(int64(size)))
+}
+
+// RecordProcessorQueueCapacity records the maximum size of the queue of the
+// processor of the context, see ProcessorContext.
+func RecordProcessorQueueCapacity(processorCtx context.Context, capacity int) {
+	stats.Record(processorCtx, mProcessorQueueCapacity.M(int64(capacity)))
+}
+
+// RecordExporterQueueSize records the current size of the sending queue of
+// the exporter of the context, see ExporterContext.
+func RecordExporterQueueSize(exporterCtx context.Context, size int) {
+	stats.Record(exporterCtx, mExporterQueueSize.M(int64(size)))
+}
+
+// RecordExporterQueueCapacity records the maximum size of the sending queue
+// of the exporter of the context, see ExporterContext.
+func RecordExporterQueueCapacity(exporterCtx context.Context, capacity int) {
+	stats.Record(exporterCtx, mExporterQueueCapacity.M(int64(capacity)))
+}
+
+// MetricPointCount returns the number of points of the metrics.
+func MetricPointCount(metrics []*metricspb.Metric) int {
+	count := 0
+	for _, metric := range metrics {
+		for _, ts := range metric.Timeseries {
+			count += len(ts.Points)
+		}
+	}
+	return count
+}
+
+func sinceMillis(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
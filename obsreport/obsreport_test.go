@@ -0,0 +1,224 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package obsreport
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
+)
+
+type fakeConsumer struct {
+	err error
+}
+
+func (c *fakeConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return c.err
+}
+
+func (c *fakeConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	return c.err
+}
+
+func TestViews(t *testing.T) {
+	assert.Nil(t, Views(telemetry.None))
+	basic := Views(telemetry.Basic)
+	normal := Views(telemetry.Normal)
+	detailed := Views(telemetry.Detailed)
+	assert.Equal(t, 17, len(basic))
+	assert.Equal(t, len(basic)+4, len(normal))
+	assert.Equal(t, len(normal)+2, len(detailed))
+}
+
+func TestWrapTraceConsumers(t *testing.T) {
+	views := Views(telemetry.Detailed)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}
+	ctx := WithTransport(context.Background(), "grpc")
+
+	ok := &fakeConsumer{}
+	failing := &fakeConsumer{err: errors.New("failed")}
+	require.NoError(t, WrapTraceReceiver("oc", ok).ConsumeTraceData(ctx, td))
+	require.Error(t, WrapTraceReceiver("oc", failing).ConsumeTraceData(ctx, td))
+	require.NoError(t, WrapTraceProcessor("batch", ok).ConsumeTraceData(ctx, td))
+	require.Error(t, WrapTraceProcessor("batch", failing).ConsumeTraceData(ctx, td))
+	require.NoError(t, WrapTraceExporter("jaeger", ok).ConsumeTraceData(ctx, td))
+	require.NoError(t, WrapTraceExporter("jaeger", ok).ConsumeTraceData(ctx, td))
+	require.Error(t, WrapTraceExporter("jaeger", failing).ConsumeTraceData(ctx, td))
+
+	require.NoError(t, WrapTracePipeline("oc", "traces", ok).ConsumeTraceData(ctx, td))
+	require.Error(t, WrapTracePipeline("oc", "traces/2", failing).ConsumeTraceData(ctx, td))
+
+	receiverTags := []tag.Tag{{Key: TagKeyReceiver, Value: "oc"}, {Key: TagKeyTransport, Value: "grpc"}}
+	assertSum(t, "receiver/accepted_spans", receiverTags, 3)
+	assertSum(t, "receiver/pipeline_dropped_spans",
+		[]tag.Tag{{Key: TagKeyPipeline, Value: "traces/2"}, {Key: TagKeyReceiver, Value: "oc"}}, 3)
+	assertSum(t, "receiver/refused_spans", receiverTags, 3)
+	assertSum(t, "processor/accepted_spans", []tag.Tag{{Key: TagKeyProcessor, Value: "batch"}}, 3)
+	assertSum(t, "processor/refused_spans", []tag.Tag{{Key: TagKeyProcessor, Value: "batch"}}, 3)
+	assertSum(t, "exporter/sent_spans", []tag.Tag{{Key: TagKeyExporter, Value: "jaeger"}}, 6)
+	assertSum(t, "exporter/send_failed_spans", []tag.Tag{{Key: TagKeyExporter, Value: "jaeger"}}, 3)
+
+	rows, err := view.RetrieveData("exporter/latency")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, int64(3), rows[0].Data.(*view.DistributionData).Count)
+}
+
+func TestWrapMetricsConsumers(t *testing.T) {
+	views := Views(telemetry.Basic)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	md := consumerdata.MetricsData{Metrics: []*metricspb.Metric{
+		{Timeseries: []*metricspb.TimeSeries{
+			{Points: make([]*metricspb.Point, 2)},
+			{Points: make([]*metricspb.Point, 3)},
+		}},
+		{Timeseries: []*metricspb.TimeSeries{
+			{Points: make([]*metricspb.Point, 1)},
+		}},
+	}}
+	assert.Equal(t, 6, MetricPointCount(md.Metrics))
+
+	ctx := WithTransport(context.Background(), "http")
+	require.NoError(t, WrapMetricsReceiver("prometheus", &fakeConsumer{}).ConsumeMetricsData(ctx, md))
+	require.Error(t, WrapMetricsProcessor("filter", &fakeConsumer{err: errors.New("failed")}).ConsumeMetricsData(ctx, md))
+	require.NoError(t, WrapMetricsExporter("opencensus", &fakeConsumer{}).ConsumeMetricsData(ctx, md))
+	require.Error(t, WrapMetricsPipeline("prometheus", "metrics", &fakeConsumer{err: errors.New("failed")}).ConsumeMetricsData(ctx, md))
+
+	assertSum(t, "receiver/accepted_metric_points",
+		[]tag.Tag{{Key: TagKeyReceiver, Value: "prometheus"}, {Key: TagKeyTransport, Value: "http"}}, 6)
+	assertSum(t, "receiver/pipeline_dropped_metric_points",
+		[]tag.Tag{{Key: TagKeyPipeline, Value: "metrics"}, {Key: TagKeyReceiver, Value: "prometheus"}}, 6)
+	assertSum(t, "processor/refused_metric_points", []tag.Tag{{Key: TagKeyProcessor, Value: "filter"}}, 6)
+	assertSum(t, "exporter/sent_metric_points", []tag.Tag{{Key: TagKeyExporter, Value: "opencensus"}}, 6)
+}
+
+// readOnlyConsumer is a fakeConsumer declaring it doesn't modify the data.
+type readOnlyConsumer struct {
+	fakeConsumer
+}
+
+func (c *readOnlyConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+func TestWrapExportersCapabilities(t *testing.T) {
+	readOnly := &readOnlyConsumer{}
+	assert.False(t, consumer.GetCapabilities(WrapTraceExporter("exporter", readOnly)).MutatesConsumedData)
+	assert.False(t, consumer.GetCapabilities(WrapMetricsExporter("exporter", readOnly)).MutatesConsumedData)
+	assert.False(t, consumer.GetCapabilities(WithoutTracing(WrapTraceExporter("exporter", readOnly))).MutatesConsumedData)
+
+	mutating := &fakeConsumer{}
+	assert.True(t, consumer.GetCapabilities(WrapTraceExporter("exporter", mutating)).MutatesConsumedData)
+	assert.True(t, consumer.GetCapabilities(WrapMetricsExporter("exporter", mutating)).MutatesConsumedData)
+}
+
+func TestProcessorAndExporterRecords(t *testing.T) {
+	views := Views(telemetry.Normal)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	processorCtx := ProcessorContext(context.Background(), "queued-retry")
+	ProcessorTraceDataDropped(processorCtx, 4)
+	ProcessorMetricsDataDropped(processorCtx, 5)
+	RecordProcessorQueueSize(processorCtx, 7)
+	RecordProcessorQueueCapacity(processorCtx, 100)
+	RecordExporterQueueSize(ExporterContext(context.Background(), "zipkin"), 9)
+	RecordExporterQueueCapacity(ExporterContext(context.Background(), "zipkin"), 200)
+
+	processorTags := []tag.Tag{{Key: TagKeyProcessor, Value: "queued-retry"}}
+	assertSum(t, "processor/dropped_spans", processorTags, 4)
+	assertSum(t, "processor/dropped_metric_points", processorTags, 5)
+
+	rows, err := view.RetrieveData("processor/queue_size")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, processorTags, rows[0].Tags)
+	assert.Equal(t, float64(7), rows[0].Data.(*view.LastValueData).Value)
+
+	rows, err = view.RetrieveData("processor/queue_capacity")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, float64(100), rows[0].Data.(*view.LastValueData).Value)
+
+	rows, err = view.RetrieveData("exporter/queue_size")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, float64(9), rows[0].Data.(*view.LastValueData).Value)
+
+	rows, err = view.RetrieveData("exporter/queue_capacity")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rows))
+	assert.Equal(t, float64(200), rows[0].Data.(*view.LastValueData).Value)
+}
+
+func TestReceiverRefusedRequests(t *testing.T) {
+	views := Views(telemetry.Basic)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	receiverCtx := WithTransport(ReceiverContext(context.Background(), "zipkin"), "http")
+	RecordReceiverRefusedRequest(receiverCtx, RefusedReasonRequestSize)
+	RecordReceiverRefusedRequest(receiverCtx, RefusedReasonRequestSize)
+
+	assertSum(t, "receiver/refused_requests", []tag.Tag{
+		{Key: TagKeyReason, Value: RefusedReasonRequestSize},
+		{Key: TagKeyReceiver, Value: "zipkin"},
+		{Key: TagKeyTransport, Value: "http"},
+	}, 2)
+}
+
+func assertSum(t *testing.T, name string, tags []tag.Tag, want float64) {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err, name)
+	require.Equal(t, 1, len(rows), name)
+	assert.Equal(t, tags, rows[0].Tags, name)
+	assert.Equal(t, want, rows[0].Data.(*view.SumData).Value, name)
+}
+
+func TestReadCounts(t *testing.T) {
+	assert.Empty(t, ReadCounts())
+
+	views := Views(telemetry.Basic)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}
+	ok := &fakeConsumer{}
+	require.NoError(t, WrapTraceReceiver("oc", ok).ConsumeTraceData(WithTransport(context.Background(), "grpc"), td))
+	require.NoError(t, WrapTraceReceiver("oc", ok).ConsumeTraceData(WithTransport(context.Background(), "http"), td))
+	require.Error(t, WrapTracePipeline("oc", "traces/2", &fakeConsumer{err: errors.New("failed")}).ConsumeTraceData(context.Background(), td))
+	require.NoError(t, WrapTraceExporter("jaeger", ok).ConsumeTraceData(context.Background(), td))
+
+	assert.Equal(t, ComponentCounts{
+		"receiver": {"oc": {"accepted_spans": 6, "pipeline_dropped_spans": 3}},
+		"exporter": {"jaeger": {"sent_spans": 3}},
+	}, ReadCounts())
+}
@@ -0,0 +1,195 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package obsreport
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/translator/trace/spandata"
+)
+
+const (
+	numSpansAttribute        = "num_spans"
+	numMetricPointsAttribute = "num_metric_points"
+
+	// spansSourceFormat is the source format of the spans sent by a
+	// SpanExporter.
+	spansSourceFormat = "obsreport"
+
+	defaultSpanExporterBatchSize     = 512
+	defaultSpanExporterFlushInterval = time.Second
+)
+
+func startSpan(ctx context.Context, name string, countAttribute string, count int) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	if span.IsRecordingEvents() {
+		span.AddAttributes(trace.Int64Attribute(countAttribute, int64(count)))
+	}
+	return ctx, span
+}
+
+func endSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// WithoutTracing returns the consumer calling next with a context in which no
+// span is sampled. The service builder uses it in the pipeline receiving the
+// spans of the service so that they do not generate more spans.
+func WithoutTracing(next consumer.TraceConsumer) consumer.TraceConsumer {
+	return &untracedTraceConsumer{next: next}
+}
+
+type untracedTraceConsumer struct {
+	next consumer.TraceConsumer
+}
+
+var _ consumer.Capable = (*untracedTraceConsumer)(nil)
+
+func (c *untracedTraceConsumer) Capabilities() consumer.Capabilities {
+	return consumer.GetCapabilities(c.next)
+}
+
+func (c *untracedTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return c.next.ConsumeTraceData(untracedContext(ctx), td)
+}
+
+// untracedContext returns a copy of the context with a span that is not
+// sampled, its children inherit the sampling decision.
+func untracedContext(ctx context.Context) context.Context {
+	ctx, span := trace.StartSpan(ctx, "obsreport/untraced", trace.WithSampler(trace.NeverSample()))
+	span.End()
+	return ctx
+}
+
+// SpanExporter is a trace.Exporter sending the spans of the service to a
+// pipeline of the service, in batches. The spans are dropped when next is too
+// slow to keep up.
+type SpanExporter struct {
+	next          consumer.TraceConsumer
+	logger        *zap.Logger
+	node          *commonpb.Node
+	batchSize     int
+	flushInterval time.Duration
+	spans         chan *tracepb.Span
+	stopCh        chan struct{}
+	stopOnce      sync.Once
+	done          chan struct{}
+}
+
+var _ trace.Exporter = (*SpanExporter)(nil)
+
+// NewSpanExporter creates a SpanExporter sending the spans to next, it must be
+// stopped with Stop.
+func NewSpanExporter(next consumer.TraceConsumer, logger *zap.Logger) *SpanExporter {
+	hostname, _ := os.Hostname()
+	e := &SpanExporter{
+		next:   next,
+		logger: logger,
+		node: &commonpb.Node{
+			Identifier: &commonpb.ProcessIdentifier{
+				HostName: hostname,
+				Pid:      uint32(os.Getpid()),
+			},
+			ServiceInfo: &commonpb.ServiceInfo{Name: "otelsvc"},
+		},
+		batchSize:     defaultSpanExporterBatchSize,
+		flushInterval: defaultSpanExporterFlushInterval,
+		spans:         make(chan *tracepb.Span, 4*defaultSpanExporterBatchSize),
+		stopCh:        make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// ExportSpan implements trace.Exporter.
+func (e *SpanExporter) ExportSpan(sd *trace.SpanData) {
+	span, err := spandata.OCSpanDataToProtoSpan(sd)
+	if err != nil {
+		return
+	}
+	select {
+	case e.spans <- span:
+	default:
+		// Never block the code ending the span.
+	}
+}
+
+// Stop sends the pending spans and stops the exporter.
+func (e *SpanExporter) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+	<-e.done
+}
+
+func (e *SpanExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	var batch []*tracepb.Span
+	for {
+		select {
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) >= e.batchSize {
+				batch = e.send(batch)
+			}
+		case <-ticker.C:
+			batch = e.send(batch)
+		case <-e.stopCh:
+			for {
+				select {
+				case span := <-e.spans:
+					batch = append(batch, span)
+				default:
+					e.send(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends the batch and returns the slice to use for the next batch.
+func (e *SpanExporter) send(batch []*tracepb.Span) []*tracepb.Span {
+	if len(batch) == 0 {
+		return batch
+	}
+	td := consumerdata.TraceData{
+		Node:         e.node,
+		Spans:        batch,
+		SourceFormat: spansSourceFormat,
+	}
+	if err := e.next.ConsumeTraceData(untracedContext(context.Background()), td); err != nil {
+		e.logger.Debug("Failed to send the spans of the service", zap.Int("#spans", len(batch)), zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package obsreport
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+type traceSink struct {
+	mu     sync.Mutex
+	traces []consumerdata.TraceData
+	ctxs   []context.Context
+}
+
+func (s *traceSink) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traces = append(s.traces, td)
+	s.ctxs = append(s.ctxs, ctx)
+	return nil
+}
+
+func TestSpanExporter(t *testing.T) {
+	sink := &traceSink{}
+	exp := NewSpanExporter(sink, zap.NewNop())
+	trace.RegisterExporter(exp)
+
+	for i := 0; i < 3; i++ {
+		_, span := trace.StartSpan(context.Background(), "processor/batch", trace.WithSampler(trace.AlwaysSample()))
+		span.End()
+	}
+	trace.UnregisterExporter(exp)
+	exp.Stop()
+
+	require.Equal(t, 1, len(sink.traces))
+	td := sink.traces[0]
+	assert.Equal(t, "obsreport", td.SourceFormat)
+	assert.Equal(t, "otelsvc", td.Node.ServiceInfo.Name)
+	require.Equal(t, 3, len(td.Spans))
+	assert.Equal(t, "processor/batch", td.Spans[0].Name.Value)
+
+	// The spans are sent in a context in which nothing is sampled.
+	_, span := trace.StartSpan(sink.ctxs[0], "exporter/zipkin")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+}
+
+func TestWithoutTracing(t *testing.T) {
+	sink := &traceSink{}
+	ctx, span := trace.StartSpan(context.Background(), "receiver/opencensus", trace.WithSampler(trace.AlwaysSample()))
+	defer span.End()
+
+	traced := WrapTraceProcessor("batch", sink)
+	require.NoError(t, traced.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	require.NoError(t, WithoutTracing(traced).ConsumeTraceData(ctx, consumerdata.TraceData{}))
+
+	require.Equal(t, 2, len(sink.ctxs))
+	assert.True(t, trace.FromContext(sink.ctxs[0]).SpanContext().IsSampled())
+	assert.False(t, trace.FromContext(sink.ctxs[1]).SpanContext().IsSampled())
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 var (
@@ -33,6 +34,7 @@ var (
 type addattributesprocessor struct {
 	attributeMap map[string]*tracepb.AttributeValue
 	overwrite    bool
+	filter       *filtermatch.Filter
 	nextConsumer consumer.TraceConsumer
 }
 
@@ -47,6 +49,14 @@ func WithOverwrite(overwrite bool) Option {
 	}
 }
 
+// WithFilter returns an Option to add the attributes only to the spans selected by the filter.
+func WithFilter(filter *filtermatch.Filter) Option {
+	return func(aap *addattributesprocessor) error {
+		aap.filter = filter
+		return nil
+	}
+}
+
 // WithAttributes returns an Option to configure the attributes to be added to all spans.
 func WithAttributes(attributes map[string]interface{}) Option {
 	return func(aap *addattributesprocessor) error {
@@ -98,6 +108,9 @@ func (aap *addattributesprocessor) ConsumeTraceData(ctx context.Context, td cons
 			// We will not create nil spans with just attributes on them
 			continue
 		}
+		if !aap.filter.MatchSpan(td.Node, td.Resource, span) {
+			continue
+		}
 		if span.Attributes == nil {
 			span.Attributes = &tracepb.Span_Attributes{}
 		}
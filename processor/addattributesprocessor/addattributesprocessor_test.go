@@ -22,6 +22,7 @@ import (
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 func TestAddAttributesProcessorInvalidValue(t *testing.T) {
@@ -181,3 +182,38 @@ func addAttributesProcessorTestHelper(t *testing.T, overwrite bool) {
 		}
 	}
 }
+
+func TestAddAttributesProcessorWithFilter(t *testing.T) {
+	sinkExporter := &exportertest.SinkTraceExporter{}
+
+	filter, err := filtermatch.NewFilter(
+		&filtermatch.MatchProperties{SpanNames: []string{"foo"}}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the filter: want nil got %v", err)
+	}
+	tt, err := NewTraceProcessor(
+		sinkExporter,
+		WithAttributes(map[string]interface{}{"some_int": 1234}),
+		WithFilter(filter))
+	if err != nil {
+		t.Fatalf("Unexpected error when creating: want nil got %v", err)
+	}
+
+	td := consumerdata.TraceData{
+		Spans: []*tracepb.Span{
+			{Name: &tracepb.TruncatableString{Value: "foo"}},
+			{Name: &tracepb.TruncatableString{Value: "bar"}},
+		},
+	}
+	if err := tt.ConsumeTraceData(context.Background(), td); err != nil {
+		t.Fatalf("ConsumeTraceData return error: want nil got %v", err)
+	}
+
+	spans := sinkExporter.AllTraces()[0].Spans
+	if val, ok := spans[0].Attributes.GetAttributeMap()["some_int"]; !ok || val.GetIntValue() != 1234 {
+		t.Errorf("Missing or invalid int value in the matching span")
+	}
+	if spans[1].Attributes != nil {
+		t.Errorf("Attributes added to the span not matching the filter")
+	}
+}
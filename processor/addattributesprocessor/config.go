@@ -16,6 +16,7 @@ package addattributesprocessor
 
 import (
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 // Config defines configuration for Attributes processor.
@@ -23,4 +24,9 @@ type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 	Overwrite                      bool                   `mapstructure:"overwrite"`
 	Values                         map[string]interface{} `mapstructure:"values"`
+
+	// Include and Exclude select the spans the attributes are added to, by
+	// default all of them.
+	Include *filtermatch.MatchProperties `mapstructure:"include"`
+	Exclude *filtermatch.MatchProperties `mapstructure:"exclude"`
 }
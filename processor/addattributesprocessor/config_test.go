@@ -23,6 +23,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -37,6 +38,7 @@ func TestLoadConfig(t *testing.T) {
 
 	require.Nil(t, err)
 	require.NotNil(t, cfg)
+	assert.Equal(t, len(cfg.Processors), 3)
 
 	p0 := cfg.Processors["add-attributes"]
 	assert.Equal(t, p0, factory.CreateDefaultConfig())
@@ -54,4 +56,28 @@ func TestLoadConfig(t *testing.T) {
 				"attribute.with.dot": "another value",
 			},
 		})
+	p2 := cfg.Processors["add-attributes/filtered"]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "add-attributes",
+				NameVal: "add-attributes/filtered",
+			},
+			Values: map[string]interface{}{
+				"env": "production",
+			},
+			Include: &filtermatch.MatchProperties{
+				MatchType: filtermatch.MatchTypeRegexp,
+				Services:  []string{"^frontend"},
+				SpanNames: []string{"^GET "},
+			},
+			Exclude: &filtermatch.MatchProperties{
+				Attributes: []filtermatch.Attribute{
+					{Key: "http.status_code", Value: "200"},
+				},
+				ResourceLabels: []filtermatch.Attribute{
+					{Key: "host"},
+				},
+			},
+		})
 }
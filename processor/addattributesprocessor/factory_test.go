@@ -20,6 +20,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -40,3 +42,13 @@ func TestCreateProcessor(t *testing.T) {
 	assert.Nil(t, mp)
 	assert.Error(t, err, "should not be able to create metric processor")
 }
+
+func TestCreateProcessorInvalidFilter(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Include = &filtermatch.MatchProperties{}
+
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), nil, cfg)
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 // KeyReplacement identifies a key and its respective replacement.
@@ -42,12 +43,24 @@ type KeyReplacement struct {
 type attributekeyprocessor struct {
 	nextConsumer consumer.TraceConsumer
 	replacements []KeyReplacement
+	filter       *filtermatch.Filter
 }
 
 var _ processor.TraceProcessor = (*attributekeyprocessor)(nil)
 
 // NewTraceProcessor returns a processor.TraceProcessor
 func NewTraceProcessor(nextConsumer consumer.TraceConsumer, replacements ...KeyReplacement) (processor.TraceProcessor, error) {
+	return NewFilteredTraceProcessor(nextConsumer, nil, replacements...)
+}
+
+// NewFilteredTraceProcessor returns a processor.TraceProcessor replacing the
+// keys only in the spans selected by the filter, a nil filter selects all
+// the spans.
+func NewFilteredTraceProcessor(
+	nextConsumer consumer.TraceConsumer,
+	filter *filtermatch.Filter,
+	replacements ...KeyReplacement,
+) (processor.TraceProcessor, error) {
 	if nextConsumer == nil {
 		return nil, errors.New("nextConsumer is nil")
 	}
@@ -69,6 +82,7 @@ func NewTraceProcessor(nextConsumer consumer.TraceConsumer, replacements ...KeyR
 	return &attributekeyprocessor{
 		nextConsumer: nextConsumer,
 		replacements: replacements,
+		filter:       filter,
 	}, nil
 }
 
@@ -81,6 +95,9 @@ func (akp *attributekeyprocessor) ConsumeTraceData(ctx context.Context, td consu
 			// Nothing to do
 			continue
 		}
+		if !akp.filter.MatchSpan(td.Node, td.Resource, span) {
+			continue
+		}
 
 		attribMap := span.Attributes.AttributeMap
 		for _, replacement := range akp.replacements {
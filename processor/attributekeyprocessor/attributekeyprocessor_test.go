@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"testing"
 
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/google/go-cmp/cmp"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 	"github.com/open-telemetry/opentelemetry-service/processor/processortest"
 )
 
@@ -329,3 +331,45 @@ func Test_attributekeyprocessor_ConsumeTraceData(t *testing.T) {
 		})
 	}
 }
+
+func Test_attributekeyprocessor_ConsumeTraceDataWithFilter(t *testing.T) {
+	filter, err := filtermatch.NewFilter(nil, &filtermatch.MatchProperties{
+		Services: []string{"excluded"},
+	})
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v, want nil", err)
+	}
+	sinkExporter := &exportertest.SinkTraceExporter{}
+	akp, err := NewFilteredTraceProcessor(sinkExporter, filter, KeyReplacement{Key: "foo", NewKey: "bar"})
+	if err != nil {
+		t.Fatalf("NewFilteredTraceProcessor() error = %v, want nil", err)
+	}
+
+	newTraceData := func(serviceName string) consumerdata.TraceData {
+		return consumerdata.TraceData{
+			Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: serviceName}},
+			Spans: []*tracepb.Span{
+				{
+					Attributes: &tracepb.Span_Attributes{
+						AttributeMap: map[string]*tracepb.AttributeValue{
+							"foo": {Value: &tracepb.AttributeValue_BoolValue{BoolValue: true}},
+						},
+					},
+				},
+			},
+		}
+	}
+	for _, serviceName := range []string{"included", "excluded"} {
+		if err := akp.ConsumeTraceData(context.Background(), newTraceData(serviceName)); err != nil {
+			t.Fatalf("ConsumeTraceData() error = %v, want nil", err)
+		}
+	}
+
+	traces := sinkExporter.AllTraces()
+	if _, ok := traces[0].Spans[0].Attributes.AttributeMap["bar"]; !ok {
+		t.Errorf("Key not replaced in the span matching the filter")
+	}
+	if _, ok := traces[1].Spans[0].Attributes.AttributeMap["foo"]; !ok {
+		t.Errorf("Key replaced in the span excluded by the filter")
+	}
+}
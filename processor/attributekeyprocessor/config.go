@@ -16,6 +16,7 @@ package attributekeyprocessor
 
 import (
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 // Config defines configuration for Attribute Key processor.
@@ -23,6 +24,11 @@ type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 	// map key is the attribute key to be replaced.
 	Keys map[string]NewKeyProperties `mapstructure:"keys"`
+
+	// Include and Exclude select the spans the keys are replaced in, by
+	// default all of them.
+	Include *filtermatch.MatchProperties `mapstructure:"include"`
+	Exclude *filtermatch.MatchProperties `mapstructure:"exclude"`
 }
 
 // NewKeyProperties defines the key's replacments properties.
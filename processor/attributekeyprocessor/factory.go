@@ -21,6 +21,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
 )
 
 const (
@@ -55,7 +56,11 @@ func (f *Factory) CreateTraceProcessor(
 	cfg configmodels.Processor,
 ) (processor.TraceProcessor, error) {
 	oCfg := cfg.(*Config)
-	return NewTraceProcessor(nextConsumer, convertToKeyReplacements(&oCfg.Keys)...)
+	filter, err := filtermatch.NewFilter(oCfg.Include, oCfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	return NewFilteredTraceProcessor(nextConsumer, filter, convertToKeyReplacements(&oCfg.Keys)...)
 }
 
 // CreateMetricsProcessor creates a metrics processor based on this config.
@@ -0,0 +1,34 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Cumulative to Delta processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Metrics are the names of the metrics converted, by default all the
+	// cumulative int64 and double metrics.
+	Metrics []string `mapstructure:"metrics"`
+
+	// MaxStaleness is the time after which the last value of a series that
+	// isn't received anymore is forgotten.
+	MaxStaleness time.Duration `mapstructure:"max-staleness"`
+}
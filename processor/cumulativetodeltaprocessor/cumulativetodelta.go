@@ -0,0 +1,324 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cumulativetodeltaprocessor implements a processor converting the
+// cumulative metrics to deltas, for the backends that only accept deltas.
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/storage"
+)
+
+// seriesStorageKey is the storage key of the last points of the series.
+const seriesStorageKey = "series"
+
+// lastPoint is the last point received of a series, it is stored in JSON.
+type lastPoint struct {
+	Start       *timestamp.Timestamp `json:"start,omitempty"`
+	Timestamp   *timestamp.Timestamp `json:"timestamp,omitempty"`
+	Int64Value  int64                `json:"int64,omitempty"`
+	DoubleValue float64              `json:"double,omitempty"`
+	Seen        time.Time            `json:"seen"`
+}
+
+type cumulativeToDelta struct {
+	logger       *zap.Logger
+	nextConsumer consumer.MetricsConsumer
+	// metrics are the names of the metrics converted, nil to convert all of
+	// them.
+	metrics      map[string]bool
+	maxStaleness time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	series    map[string]*lastPoint
+	lastSweep time.Time
+	// client stores the series at every sweep and when the processor stops,
+	// nil until it is set.
+	client storage.Client
+}
+
+var _ processor.MetricsProcessor = (*cumulativeToDelta)(nil)
+var _ processor.Stopper = (*cumulativeToDelta)(nil)
+var _ storage.User = (*cumulativeToDelta)(nil)
+
+// NewMetricsProcessor returns a processor.MetricsProcessor converting the
+// cumulative metrics to gauges holding the difference with the previous
+// point, the start timestamp of their series being the timestamp of the
+// previous point. The first point of each series is dropped, there is nothing
+// to compare it to. The series are stored, so that they survive the restarts
+// of the service when the storage persists them.
+func NewMetricsProcessor(logger *zap.Logger, nextConsumer consumer.MetricsConsumer, cfg Config) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if cfg.MaxStaleness <= 0 {
+		return nil, errors.New("max-staleness must be positive")
+	}
+
+	ctd := &cumulativeToDelta{
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		maxStaleness: cfg.MaxStaleness,
+		now:          time.Now,
+		series:       make(map[string]*lastPoint),
+	}
+	if len(cfg.Metrics) > 0 {
+		ctd.metrics = make(map[string]bool, len(cfg.Metrics))
+		for _, name := range cfg.Metrics {
+			ctd.metrics[name] = true
+		}
+	}
+	ctd.lastSweep = ctd.now()
+	return ctd, nil
+}
+
+// SetStorageClient restores the series stored, e.g. before the service
+// restarted, except the stale ones.
+func (ctd *cumulativeToDelta) SetStorageClient(client storage.Client) error {
+	data, err := client.Get(seriesStorageKey)
+	if err != nil {
+		return err
+	}
+	var series map[string]*lastPoint
+	if data != nil {
+		if err := json.Unmarshal(data, &series); err != nil {
+			return fmt.Errorf("cannot decode the stored series: %v", err)
+		}
+	}
+
+	ctd.mu.Lock()
+	defer ctd.mu.Unlock()
+	ctd.client = client
+	now := ctd.now()
+	for key, last := range series {
+		if now.Sub(last.Seen) <= ctd.maxStaleness {
+			ctd.series[key] = last
+		}
+	}
+	return nil
+}
+
+// Stop stores the series.
+func (ctd *cumulativeToDelta) Stop() {
+	ctd.mu.Lock()
+	defer ctd.mu.Unlock()
+	ctd.store()
+}
+
+// store stores the series if the client is set. The lock must be held.
+func (ctd *cumulativeToDelta) store() {
+	if ctd.client == nil {
+		return
+	}
+	data, err := json.Marshal(ctd.series)
+	if err == nil {
+		err = ctd.client.Set(seriesStorageKey, data)
+	}
+	if err != nil {
+		ctd.logger.Warn("Failed to store the series", zap.Error(err))
+	}
+}
+
+func (ctd *cumulativeToDelta) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	ctd.mu.Lock()
+	now := ctd.now()
+	batchKey := resourceKey(md.Node, md.Resource)
+	metrics := make([]*metricspb.Metric, 0, len(md.Metrics))
+	for _, metric := range md.Metrics {
+		key := batchKey
+		if metric.GetResource() != nil {
+			key = resourceKey(md.Node, metric.Resource)
+		}
+		if converted := ctd.convert(now, key, metric); converted != nil {
+			metrics = append(metrics, converted)
+		}
+	}
+	ctd.sweep(now)
+	ctd.mu.Unlock()
+
+	if len(metrics) == 0 {
+		return nil
+	}
+	md.Metrics = metrics
+	return ctd.nextConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// convert returns the metric converted to deltas, the metric itself if it
+// isn't converted or nil if it has no delta yet. The lock must be held.
+func (ctd *cumulativeToDelta) convert(now time.Time, resourceKey string, metric *metricspb.Metric) *metricspb.Metric {
+	descriptor := metric.GetMetricDescriptor()
+	var gaugeType metricspb.MetricDescriptor_Type
+	switch descriptor.GetType() {
+	case metricspb.MetricDescriptor_CUMULATIVE_INT64:
+		gaugeType = metricspb.MetricDescriptor_GAUGE_INT64
+	case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+		gaugeType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+	default:
+		return metric
+	}
+	if ctd.metrics != nil && !ctd.metrics[descriptor.Name] {
+		return metric
+	}
+
+	// The metric may be shared with other pipelines, it is copied.
+	converted := &metricspb.Metric{
+		MetricDescriptor: proto.Clone(descriptor).(*metricspb.MetricDescriptor),
+		Resource:         metric.Resource,
+	}
+	converted.MetricDescriptor.Type = gaugeType
+	for _, ts := range metric.Timeseries {
+		key := seriesKey(resourceKey, descriptor.Name, ts.LabelValues)
+		for _, point := range ts.Points {
+			previous, delta := ctd.delta(now, key, ts.StartTimestamp, point)
+			if delta == nil {
+				continue
+			}
+			converted.Timeseries = append(converted.Timeseries, &metricspb.TimeSeries{
+				StartTimestamp: previous,
+				LabelValues:    ts.LabelValues,
+				Points:         []*metricspb.Point{delta},
+			})
+		}
+	}
+	if len(converted.Timeseries) == 0 {
+		return nil
+	}
+	return converted
+}
+
+// delta records the point as the last one of its series and returns the
+// timestamp of the previous one and the difference with it, or a nil point
+// for the first point. When the series was reset, its start changed or its
+// value decreased, the difference is the value itself.
+func (ctd *cumulativeToDelta) delta(now time.Time, key string, start *timestamp.Timestamp, point *metricspb.Point) (*timestamp.Timestamp, *metricspb.Point) {
+	last, ok := ctd.series[key]
+	if !ok {
+		last = &lastPoint{}
+		ctd.series[key] = last
+	}
+	reset := start != nil && last.Start != nil && !proto.Equal(start, last.Start)
+	previous := last.Timestamp
+	delta := &metricspb.Point{Timestamp: point.Timestamp}
+
+	switch v := point.Value.(type) {
+	case *metricspb.Point_Int64Value:
+		value := v.Int64Value
+		if reset || value < last.Int64Value {
+			delta.Value = &metricspb.Point_Int64Value{Int64Value: value}
+		} else {
+			delta.Value = &metricspb.Point_Int64Value{Int64Value: value - last.Int64Value}
+		}
+		last.Int64Value = value
+	case *metricspb.Point_DoubleValue:
+		value := v.DoubleValue
+		if reset || value < last.DoubleValue {
+			delta.Value = &metricspb.Point_DoubleValue{DoubleValue: value}
+		} else {
+			delta.Value = &metricspb.Point_DoubleValue{DoubleValue: value - last.DoubleValue}
+		}
+		last.DoubleValue = value
+	default:
+		return nil, nil
+	}
+
+	last.Start = start
+	last.Timestamp = point.Timestamp
+	last.Seen = now
+	if !ok {
+		return nil, nil
+	}
+	if reset && start != nil {
+		// The value accumulated since the new start.
+		previous = start
+	}
+	return previous, delta
+}
+
+// sweep forgets the series not seen for longer than the maximum staleness and
+// stores the others, at most once per maximum staleness. The lock must be
+// held.
+func (ctd *cumulativeToDelta) sweep(now time.Time) {
+	if now.Sub(ctd.lastSweep) < ctd.maxStaleness {
+		return
+	}
+	ctd.lastSweep = now
+	for key, last := range ctd.series {
+		if now.Sub(last.Seen) > ctd.maxStaleness {
+			delete(ctd.series, key)
+		}
+	}
+	ctd.store()
+}
+
+// resourceKey identifies the source of the metrics, by node and resource.
+func resourceKey(node *commonpb.Node, resource *resourcepb.Resource) string {
+	var sb strings.Builder
+	sb.WriteString(node.GetIdentifier().GetHostName())
+	sb.WriteByte(0)
+	sb.WriteString(strconv.FormatUint(uint64(node.GetIdentifier().GetPid()), 10))
+	sb.WriteByte(0)
+	sb.WriteString(node.GetServiceInfo().GetName())
+	sb.WriteByte(0)
+	sb.WriteString(resource.GetType())
+	labels := resource.GetLabels()
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteByte(0)
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(labels[k])
+	}
+	return sb.String()
+}
+
+// seriesKey identifies a series of a metric.
+func seriesKey(resourceKey, name string, labelValues []*metricspb.LabelValue) string {
+	var sb strings.Builder
+	sb.WriteString(resourceKey)
+	sb.WriteByte(1)
+	sb.WriteString(name)
+	for _, v := range labelValues {
+		sb.WriteByte(0)
+		if v.GetHasValue() {
+			sb.WriteByte('=')
+			sb.WriteString(v.Value)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,219 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cumulativetodeltaprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/storage"
+)
+
+func newTestMetric(name string, metricType metricspb.MetricDescriptor_Type, start, ts int64, value interface{}) *metricspb.Metric {
+	point := &metricspb.Point{Timestamp: &timestamp.Timestamp{Seconds: ts}}
+	switch v := value.(type) {
+	case int64:
+		point.Value = &metricspb.Point_Int64Value{Int64Value: v}
+	case float64:
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: v}
+	}
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      name,
+			Type:      metricType,
+			LabelKeys: []*metricspb.LabelKey{{Key: "method"}},
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			StartTimestamp: &timestamp.Timestamp{Seconds: start},
+			LabelValues:    []*metricspb.LabelValue{{Value: "GET", HasValue: true}},
+			Points:         []*metricspb.Point{point},
+		}},
+	}
+}
+
+func TestCumulativeToDelta(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(zap.NewNop(), sink, Config{MaxStaleness: time.Minute})
+	require.NoError(t, err)
+
+	node := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}}
+	send := func(metrics ...*metricspb.Metric) {
+		require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Node:    node,
+			Metrics: metrics,
+		}))
+	}
+	gauge := newTestMetric("memory", metricspb.MetricDescriptor_GAUGE_INT64, 0, 10, int64(512))
+
+	// The first points have nothing to be compared to.
+	first := newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 10, int64(100))
+	send(first, newTestMetric("latency", metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, 0, 10, 1.5))
+	send(
+		newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 20, int64(150)),
+		newTestMetric("latency", metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, 0, 20, 4.0),
+		gauge,
+	)
+	// The counter was reset.
+	send(newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 25, 30, int64(20)))
+	// The value decreased without a new start.
+	send(newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 25, 40, int64(5)))
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 3)
+	require.Len(t, got[0].Metrics, 3)
+	assert.Equal(t, node, got[0].Node)
+
+	requests := got[0].Metrics[0]
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, requests.MetricDescriptor.Type)
+	assert.True(t, proto.Equal(&metricspb.TimeSeries{
+		StartTimestamp: &timestamp.Timestamp{Seconds: 10},
+		LabelValues:    []*metricspb.LabelValue{{Value: "GET", HasValue: true}},
+		Points: []*metricspb.Point{{
+			Timestamp: &timestamp.Timestamp{Seconds: 20},
+			Value:     &metricspb.Point_Int64Value{Int64Value: 50},
+		}},
+	}, requests.Timeseries[0]))
+
+	latency := got[0].Metrics[1]
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, latency.MetricDescriptor.Type)
+	assert.Equal(t, 2.5, latency.Timeseries[0].Points[0].GetDoubleValue())
+
+	// The other metrics are left untouched.
+	assert.Equal(t, gauge, got[0].Metrics[2])
+	// The metrics received are not modified.
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, first.MetricDescriptor.Type)
+
+	reset := got[1].Metrics[0].Timeseries[0]
+	assert.Equal(t, int64(25), reset.StartTimestamp.Seconds)
+	assert.Equal(t, int64(20), reset.Points[0].GetInt64Value())
+	decreased := got[2].Metrics[0].Timeseries[0]
+	assert.Equal(t, int64(30), decreased.StartTimestamp.Seconds)
+	assert.Equal(t, int64(5), decreased.Points[0].GetInt64Value())
+}
+
+func TestCumulativeToDeltaSelectedMetrics(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(zap.NewNop(), sink, Config{Metrics: []string{"requests"}, MaxStaleness: time.Minute})
+	require.NoError(t, err)
+
+	for ts := int64(10); ts <= 20; ts += 10 {
+		require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Metrics: []*metricspb.Metric{
+				newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, ts, ts),
+				newTestMetric("bytes", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, ts, ts),
+			},
+		}))
+	}
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 2)
+	// Only the unconverted metric is left of the first batch.
+	require.Len(t, got[0].Metrics, 1)
+	assert.Equal(t, "bytes", got[0].Metrics[0].MetricDescriptor.Name)
+	require.Len(t, got[1].Metrics, 2)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, got[1].Metrics[0].MetricDescriptor.Type)
+	assert.Equal(t, int64(10), got[1].Metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, got[1].Metrics[1].MetricDescriptor.Type)
+}
+
+func TestCumulativeToDeltaSeries(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(zap.NewNop(), sink, Config{MaxStaleness: time.Minute})
+	require.NoError(t, err)
+	ctd := mp.(*cumulativeToDelta)
+	now := time.Unix(0, 0)
+	ctd.now = func() time.Time { return now }
+	ctd.lastSweep = now
+
+	// The series of different nodes are distinct.
+	for _, service := range []string{"frontend", "backend", "frontend"} {
+		require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Node:    &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: service}},
+			Metrics: []*metricspb.Metric{newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 10, int64(1))},
+		}))
+	}
+	assert.Len(t, sink.AllMetrics(), 1)
+	assert.Len(t, ctd.series, 2)
+
+	// The stale series are forgotten.
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{newTestMetric("bytes", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 10, int64(1))},
+	}))
+	assert.Len(t, ctd.series, 1)
+}
+
+func TestCumulativeToDeltaStorage(t *testing.T) {
+	st := storage.NewMemoryStorage()
+	client, err := st.Client("processor/cumulative-to-delta/metrics")
+	require.NoError(t, err)
+	now := time.Unix(100, 0)
+	newProcessor := func(sink *exportertest.SinkMetricsExporter) *cumulativeToDelta {
+		mp, err := NewMetricsProcessor(zap.NewNop(), sink, Config{MaxStaleness: time.Minute})
+		require.NoError(t, err)
+		ctd := mp.(*cumulativeToDelta)
+		ctd.now = func() time.Time { return now }
+		ctd.lastSweep = now
+		require.NoError(t, ctd.SetStorageClient(client))
+		return ctd
+	}
+	send := func(ctd *cumulativeToDelta, metrics ...*metricspb.Metric) {
+		require.NoError(t, ctd.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Metrics: metrics}))
+	}
+
+	sink := &exportertest.SinkMetricsExporter{}
+	ctd := newProcessor(sink)
+	send(ctd,
+		newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 10, int64(100)),
+		newTestMetric("latency", metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, 0, 10, 1.5),
+	)
+	assert.Len(t, sink.AllMetrics(), 0)
+	ctd.Stop()
+
+	// The processor created after a restart has the deltas of the first
+	// points, except for the stale series.
+	now = now.Add(30 * time.Second)
+	sink = &exportertest.SinkMetricsExporter{}
+	ctd = newProcessor(sink)
+	send(ctd,
+		newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 20, int64(150)),
+	)
+	got := sink.AllMetrics()
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(10), got[0].Metrics[0].Timeseries[0].StartTimestamp.Seconds)
+	assert.Equal(t, int64(50), got[0].Metrics[0].Timeseries[0].Points[0].GetInt64Value())
+
+	// The series are also stored when they are swept.
+	now = now.Add(time.Minute)
+	send(ctd, newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, 0, 30, int64(160)))
+	ctd = newProcessor(&exportertest.SinkMetricsExporter{})
+	assert.Len(t, ctd.series, 1)
+
+	require.NoError(t, client.Set(seriesStorageKey, []byte("{")))
+	mp, err := NewMetricsProcessor(zap.NewNop(), sink, Config{MaxStaleness: time.Minute})
+	require.NoError(t, err)
+	assert.Error(t, mp.(storage.User).SetStorageClient(client))
+}
@@ -0,0 +1,46 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datalimiter
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Data Limiter processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// SpansPerSecond is the number of spans accepted per second from each
+	// service, zero for no limit.
+	SpansPerSecond float64 `mapstructure:"spans-per-second"`
+
+	// SpansBurst is the number of spans that can be accepted at once from a
+	// service, it defaults to SpansPerSecond.
+	SpansBurst int `mapstructure:"spans-burst"`
+
+	// MaxTimeSeriesPerMetric is the number of unique combinations of label
+	// values accepted for each metric of each service, zero for no limit.
+	MaxTimeSeriesPerMetric int `mapstructure:"max-time-series-per-metric"`
+
+	// TimeSeriesAction is what is done with the time series beyond the limit,
+	// "drop" or "aggregate" them into a single time series per metric.
+	TimeSeriesAction string `mapstructure:"time-series-action"`
+
+	// Window is the period after which the limits start over, the time
+	// series seen during the previous window are forgotten.
+	Window time.Duration `mapstructure:"window"`
+}
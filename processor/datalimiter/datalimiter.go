@@ -0,0 +1,343 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package datalimiter implements a processor protecting the backends from
+// the services sending too much data: it limits the rate of the spans of each
+// service and the number of time series of each of their metrics.
+package datalimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	collectorprocessor "github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
+	"github.com/open-telemetry/opentelemetry-service/internal/ratelimit"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+const (
+	actionDrop      = "drop"
+	actionAggregate = "aggregate"
+
+	// overflowLabelValue is the value of all the labels of the time series
+	// aggregating the time series beyond the limit.
+	overflowLabelValue = "overflow"
+)
+
+var errNilNextConsumer = errors.New("nil nextConsumer")
+
+// limits holds the state shared by the batches of a window.
+type limits struct {
+	cfg Config
+	now func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	// spans holds the rate limiter of each service.
+	spans map[string]*ratelimit.Limiter
+	// series holds the known time series of each metric of each service, by
+	// service and metric name.
+	series map[string]map[string]map[string]bool
+}
+
+func newLimits(cfg Config) (*limits, error) {
+	if cfg.SpansPerSecond < 0 {
+		return nil, fmt.Errorf("spans-per-second must not be negative, got %v", cfg.SpansPerSecond)
+	}
+	if cfg.MaxTimeSeriesPerMetric < 0 {
+		return nil, fmt.Errorf("max-time-series-per-metric must not be negative, got %d", cfg.MaxTimeSeriesPerMetric)
+	}
+	switch cfg.TimeSeriesAction {
+	case actionDrop, actionAggregate:
+	default:
+		return nil, fmt.Errorf("time-series-action must be either %q or %q, got %q", actionDrop, actionAggregate, cfg.TimeSeriesAction)
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultWindow
+	}
+	l := &limits{cfg: cfg, now: time.Now}
+	l.reset(l.now())
+	return l, nil
+}
+
+func (l *limits) reset(now time.Time) {
+	l.windowStart = now
+	l.spans = make(map[string]*ratelimit.Limiter)
+	l.series = make(map[string]map[string]map[string]bool)
+}
+
+// startWindow starts a new window if the current one is over, l.mu must be
+// held.
+func (l *limits) startWindow() {
+	if now := l.now(); now.Sub(l.windowStart) >= l.cfg.Window {
+		l.reset(now)
+	}
+}
+
+// admitSpans returns whether the given number of spans of the service are
+// accepted.
+func (l *limits) admitSpans(service string, n int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.startWindow()
+
+	limiter, ok := l.spans[service]
+	if !ok {
+		limiter = ratelimit.New(l.cfg.SpansPerSecond, l.cfg.SpansBurst)
+		l.spans[service] = limiter
+	}
+	_, accepted := limiter.Take(n)
+	return accepted
+}
+
+// admitTimeSeries returns whether each time series of the metric of the
+// service is accepted.
+func (l *limits) admitTimeSeries(service, metric string, timeseries []*metricspb.TimeSeries) []bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.startWindow()
+
+	metrics, ok := l.series[service]
+	if !ok {
+		metrics = make(map[string]map[string]bool)
+		l.series[service] = metrics
+	}
+	known, ok := metrics[metric]
+	if !ok {
+		known = make(map[string]bool)
+		metrics[metric] = known
+	}
+
+	accepted := make([]bool, len(timeseries))
+	for i, ts := range timeseries {
+		key := timeSeriesKey(ts)
+		if !known[key] && len(known) < l.cfg.MaxTimeSeriesPerMetric {
+			known[key] = true
+		}
+		accepted[i] = known[key]
+	}
+	return accepted
+}
+
+// timeSeriesKey returns the combination of label values of the time series.
+func timeSeriesKey(ts *metricspb.TimeSeries) string {
+	var sb strings.Builder
+	for _, v := range ts.GetLabelValues() {
+		if v.GetHasValue() {
+			sb.WriteByte('+')
+			sb.WriteString(v.GetValue())
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+type traceLimiter struct {
+	*limits
+	nextConsumer consumer.TraceConsumer
+}
+
+var _ processor.TraceProcessor = (*traceLimiter)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor dropping the batches
+// of the services sending more spans than allowed.
+func NewTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	l, err := newLimits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &traceLimiter{limits: l, nextConsumer: nextConsumer}, nil
+}
+
+func (tl *traceLimiter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if tl.cfg.SpansPerSecond == 0 || len(td.Spans) == 0 {
+		return tl.nextConsumer.ConsumeTraceData(ctx, td)
+	}
+	service := collectorprocessor.ServiceNameForNode(td.Node)
+	if !tl.admitSpans(service, len(td.Spans)) {
+		record(ctx, tl.cfg.Name(), service, statSpansDropped, len(td.Spans))
+		return nil
+	}
+	return tl.nextConsumer.ConsumeTraceData(ctx, td)
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, it only drops or forwards the data.
+func (tl *traceLimiter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+type metricsLimiter struct {
+	*limits
+	nextConsumer consumer.MetricsConsumer
+}
+
+var _ processor.MetricsProcessor = (*metricsLimiter)(nil)
+
+// NewMetricsProcessor returns a processor.MetricsProcessor dropping or
+// aggregating the time series of the metrics having more time series than
+// allowed.
+func NewMetricsProcessor(nextConsumer consumer.MetricsConsumer, cfg Config) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	l, err := newLimits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsLimiter{limits: l, nextConsumer: nextConsumer}, nil
+}
+
+func (ml *metricsLimiter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if ml.cfg.MaxTimeSeriesPerMetric == 0 {
+		return ml.nextConsumer.ConsumeMetricsData(ctx, md)
+	}
+	service := collectorprocessor.ServiceNameForNode(md.Node)
+
+	var metrics []*metricspb.Metric
+	dropped, aggregated := 0, 0
+	for i, metric := range md.Metrics {
+		if metric == nil || len(metric.Timeseries) == 0 {
+			if metrics != nil {
+				metrics = append(metrics, metric)
+			}
+			continue
+		}
+		accepted := ml.admitTimeSeries(service, metric.GetMetricDescriptor().GetName(), metric.Timeseries)
+		limited, d, a := ml.limitMetric(metric, accepted)
+		dropped += d
+		aggregated += a
+		if limited != metric && metrics == nil {
+			// The metrics are only copied once one of them is limited, the
+			// original metrics are not modified.
+			metrics = append(make([]*metricspb.Metric, 0, len(md.Metrics)), md.Metrics[:i]...)
+		}
+		if metrics != nil && limited != nil {
+			metrics = append(metrics, limited)
+		}
+	}
+	record(ctx, ml.cfg.Name(), service, statTimeSeriesDropped, dropped)
+	record(ctx, ml.cfg.Name(), service, statTimeSeriesAggregated, aggregated)
+	if metrics == nil {
+		return ml.nextConsumer.ConsumeMetricsData(ctx, md)
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+	md.Metrics = metrics
+	return ml.nextConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, the limited metrics are copies, the received ones are not modified.
+func (ml *metricsLimiter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// limitMetric returns the metric unchanged if all its time series are
+// accepted, otherwise a copy with the accepted time series, and the time
+// series aggregating the others if possible, or nil if none is left. It also
+// returns the number of dropped and aggregated time series.
+func (ml *metricsLimiter) limitMetric(metric *metricspb.Metric, accepted []bool) (*metricspb.Metric, int, int) {
+	var kept, overflow []*metricspb.TimeSeries
+	for i, ts := range metric.Timeseries {
+		if accepted[i] {
+			kept = append(kept, ts)
+		} else {
+			overflow = append(overflow, ts)
+		}
+	}
+	if len(overflow) == 0 {
+		return metric, 0, 0
+	}
+
+	dropped, aggregated := len(overflow), 0
+	if ml.cfg.TimeSeriesAction == actionAggregate {
+		if ts := aggregate(metric.GetMetricDescriptor(), overflow); ts != nil {
+			kept = append(kept, ts)
+			dropped, aggregated = 0, len(overflow)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, dropped, aggregated
+	}
+	return &metricspb.Metric{
+		MetricDescriptor: metric.MetricDescriptor,
+		Resource:         metric.Resource,
+		Timeseries:       kept,
+	}, dropped, aggregated
+}
+
+// aggregate returns a time series whose labels are all "overflow" and whose
+// value is the sum of the last values of the time series. Only the metrics
+// with integer or double values can be aggregated, it returns nil for the
+// others.
+func aggregate(descriptor *metricspb.MetricDescriptor, timeseries []*metricspb.TimeSeries) *metricspb.TimeSeries {
+	var isDouble bool
+	switch descriptor.GetType() {
+	case metricspb.MetricDescriptor_GAUGE_INT64, metricspb.MetricDescriptor_CUMULATIVE_INT64:
+	case metricspb.MetricDescriptor_GAUGE_DOUBLE, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+		isDouble = true
+	default:
+		return nil
+	}
+
+	var sumInt int64
+	var sumDouble float64
+	var start, last *timestamp.Timestamp
+	for _, ts := range timeseries {
+		if len(ts.Points) == 0 {
+			continue
+		}
+		point := ts.Points[len(ts.Points)-1]
+		sumInt += point.GetInt64Value()
+		sumDouble += point.GetDoubleValue()
+		if ts.StartTimestamp != nil && (start == nil || before(ts.StartTimestamp, start)) {
+			start = ts.StartTimestamp
+		}
+		if point.Timestamp != nil && (last == nil || before(last, point.Timestamp)) {
+			last = point.Timestamp
+		}
+	}
+
+	labelValues := make([]*metricspb.LabelValue, len(descriptor.GetLabelKeys()))
+	for i := range labelValues {
+		labelValues[i] = &metricspb.LabelValue{Value: overflowLabelValue, HasValue: true}
+	}
+	point := &metricspb.Point{Timestamp: last}
+	if isDouble {
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: sumDouble}
+	} else {
+		point.Value = &metricspb.Point_Int64Value{Int64Value: sumInt}
+	}
+	return &metricspb.TimeSeries{
+		StartTimestamp: start,
+		LabelValues:    labelValues,
+		Points:         []*metricspb.Point{point},
+	}
+}
+
+func before(a, b *timestamp.Timestamp) bool {
+	return a.Seconds < b.Seconds || (a.Seconds == b.Seconds && a.Nanos < b.Nanos)
+}
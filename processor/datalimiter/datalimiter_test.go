@@ -0,0 +1,189 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datalimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func node(service string) *commonpb.Node {
+	return &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: service}}
+}
+
+func TestNewLimitsInvalid(t *testing.T) {
+	valid := (&Factory{}).CreateDefaultConfig().(*Config)
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"negative spans per second", func(cfg *Config) { cfg.SpansPerSecond = -1 }},
+		{"negative max time series", func(cfg *Config) { cfg.MaxTimeSeriesPerMetric = -1 }},
+		{"unknown action", func(cfg *Config) { cfg.TimeSeriesAction = "sample" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *valid
+			tt.modify(&cfg)
+			_, err := newLimits(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTraceLimiter(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{SpansPerSecond: 1, SpansBurst: 3, TimeSeriesAction: actionDrop})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	tl := tp.(*traceLimiter)
+	tl.now = func() time.Time { return now }
+	tl.reset(now)
+
+	batch := func(service string) consumerdata.TraceData {
+		return consumerdata.TraceData{Node: node(service), Spans: []*tracepb.Span{{}, {}}}
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), batch("frontend")))
+	// The second batch of the service exceeds its rate and is dropped.
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), batch("frontend")))
+	// The other services have their own limit.
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), batch("backend")))
+	assert.Equal(t, 4, sink.SpansCount())
+
+	// The limits start over with the next window.
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), batch("frontend")))
+	assert.Equal(t, 6, sink.SpansCount())
+}
+
+func TestTraceLimiterNoLimit(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{TimeSeriesAction: actionDrop})
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: make([]*tracepb.Span, 100)}))
+	}
+	assert.Equal(t, 1000, sink.SpansCount())
+}
+
+func gaugeMetric(name string, values ...int64) *metricspb.Metric {
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      name,
+			Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys: []*metricspb.LabelKey{{Key: "user"}},
+		},
+	}
+	for i, v := range values {
+		metric.Timeseries = append(metric.Timeseries, &metricspb.TimeSeries{
+			LabelValues: []*metricspb.LabelValue{{Value: string(rune('a' + i)), HasValue: true}},
+			Points: []*metricspb.Point{{
+				Timestamp: &timestamp.Timestamp{Seconds: int64(100 + i)},
+				Value:     &metricspb.Point_Int64Value{Int64Value: v},
+			}},
+		})
+	}
+	return metric
+}
+
+func TestMetricsLimiterDrop(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(sink, Config{MaxTimeSeriesPerMetric: 2, TimeSeriesAction: actionDrop})
+	require.NoError(t, err)
+
+	orig := gaugeMetric("requests", 1, 2, 3, 4)
+	md := consumerdata.MetricsData{
+		Node:    node("frontend"),
+		Metrics: []*metricspb.Metric{orig, gaugeMetric("errors", 5)},
+	}
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), md))
+	// The known time series are still accepted, the new ones are not.
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Node:    node("frontend"),
+		Metrics: []*metricspb.Metric{gaugeMetric("requests", 6, 7, 8)},
+	}))
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 2)
+	require.Len(t, got[0].Metrics, 2)
+	assert.Len(t, got[0].Metrics[0].Timeseries, 2)
+	assert.Len(t, got[0].Metrics[1].Timeseries, 1)
+	assert.Len(t, got[1].Metrics[0].Timeseries, 2)
+	// The metrics of the batch are not modified.
+	assert.Len(t, orig.Timeseries, 4)
+	assert.Equal(t, 5, sink.TimeSeriesCount())
+}
+
+func TestMetricsLimiterAggregate(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(sink, Config{MaxTimeSeriesPerMetric: 1, TimeSeriesAction: actionAggregate})
+	require.NoError(t, err)
+
+	summary := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: "latency", Type: metricspb.MetricDescriptor_SUMMARY},
+		Timeseries: []*metricspb.TimeSeries{
+			{LabelValues: []*metricspb.LabelValue{{Value: "a", HasValue: true}}},
+			{LabelValues: []*metricspb.LabelValue{{Value: "b", HasValue: true}}},
+		},
+	}
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Node:    node("frontend"),
+		Metrics: []*metricspb.Metric{gaugeMetric("requests", 1, 2, 3), summary},
+	}))
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Metrics, 2)
+	requests := got[0].Metrics[0]
+	require.Len(t, requests.Timeseries, 2)
+	assert.Equal(t, &metricspb.TimeSeries{
+		LabelValues: []*metricspb.LabelValue{{Value: "overflow", HasValue: true}},
+		Points: []*metricspb.Point{{
+			Timestamp: &timestamp.Timestamp{Seconds: 102},
+			Value:     &metricspb.Point_Int64Value{Int64Value: 5},
+		}},
+	}, requests.Timeseries[1])
+	// The summaries can't be aggregated, the time series beyond the limit
+	// are dropped.
+	assert.Len(t, got[0].Metrics[1].Timeseries, 1)
+}
+
+func TestMetricsLimiterAllDropped(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(sink, Config{MaxTimeSeriesPerMetric: 1, TimeSeriesAction: actionDrop})
+	require.NoError(t, err)
+
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{gaugeMetric("requests", 1)},
+	}))
+	// The batch left without metrics is dropped.
+	metric := gaugeMetric("requests", 2, 3)
+	metric.Timeseries = metric.Timeseries[1:]
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{metric},
+	}))
+	assert.Equal(t, 1, len(sink.AllMetrics()))
+}
@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datalimiter
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
+)
+
+var (
+	tagProcessorKey, _ = tag.NewKey("processor")
+
+	statSpansDropped         = stats.Int64("data_limiter_spans_dropped", "Number of spans dropped because their service exceeded its rate", stats.UnitDimensionless)
+	statTimeSeriesDropped    = stats.Int64("data_limiter_time_series_dropped", "Number of time series dropped because their metric exceeded its cardinality", stats.UnitDimensionless)
+	statTimeSeriesAggregated = stats.Int64("data_limiter_time_series_aggregated", "Number of time series aggregated because their metric exceeded its cardinality", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views of the data limiter.
+func MetricViews(level telemetry.Level) []*view.View {
+	if level == telemetry.None {
+		return nil
+	}
+
+	tagKeys := []tag.Key{tagProcessorKey, processor.TagServiceNameKey}
+	var views []*view.View
+	for _, measure := range []*stats.Int64Measure{statSpansDropped, statTimeSeriesDropped, statTimeSeriesAggregated} {
+		views = append(views, &view.View{
+			Name:        measure.Name(),
+			Measure:     measure,
+			Description: measure.Description(),
+			TagKeys:     tagKeys,
+			Aggregation: view.Sum(),
+		})
+	}
+	return views
+}
+
+func record(ctx context.Context, processorName, serviceName string, measure *stats.Int64Measure, n int) {
+	if n == 0 {
+		return
+	}
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{
+		tag.Upsert(tagProcessorKey, processorName),
+		tag.Upsert(processor.TagServiceNameKey, serviceName),
+	}, measure.M(int64(n)))
+}
@@ -0,0 +1,138 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deltatorateprocessor implements a processor converting delta
+// metrics, e.g. produced by the cumulative-to-delta processor, to per second
+// rates.
+package deltatorateprocessor
+
+import (
+	"context"
+	"errors"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+type deltaToRate struct {
+	nextConsumer consumer.MetricsConsumer
+	metrics      map[string]bool
+}
+
+var _ processor.MetricsProcessor = (*deltaToRate)(nil)
+
+// NewMetricsProcessor returns a processor.MetricsProcessor converting the
+// named delta metrics, gauges whose series start at the end of the previous
+// delta, to double gauges of their value per second. The points without an
+// interval, their series having no start timestamp, are dropped.
+func NewMetricsProcessor(nextConsumer consumer.MetricsConsumer, cfg Config) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if len(cfg.Metrics) == 0 {
+		return nil, errors.New("metrics must list the delta metrics to convert")
+	}
+
+	dtr := &deltaToRate{
+		nextConsumer: nextConsumer,
+		metrics:      make(map[string]bool, len(cfg.Metrics)),
+	}
+	for _, name := range cfg.Metrics {
+		dtr.metrics[name] = true
+	}
+	return dtr, nil
+}
+
+func (dtr *deltaToRate) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	metrics := make([]*metricspb.Metric, 0, len(md.Metrics))
+	for _, metric := range md.Metrics {
+		if converted := dtr.convert(metric); converted != nil {
+			metrics = append(metrics, converted)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+	md.Metrics = metrics
+	return dtr.nextConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// convert returns the metric converted to rates, the metric itself if it
+// isn't converted or nil if none of its points has an interval.
+func (dtr *deltaToRate) convert(metric *metricspb.Metric) *metricspb.Metric {
+	descriptor := metric.GetMetricDescriptor()
+	switch descriptor.GetType() {
+	case metricspb.MetricDescriptor_GAUGE_INT64, metricspb.MetricDescriptor_GAUGE_DOUBLE:
+	default:
+		return metric
+	}
+	if !dtr.metrics[descriptor.Name] {
+		return metric
+	}
+
+	// The metric may be shared with other pipelines, it is copied.
+	converted := &metricspb.Metric{
+		MetricDescriptor: proto.Clone(descriptor).(*metricspb.MetricDescriptor),
+		Resource:         metric.Resource,
+	}
+	converted.MetricDescriptor.Type = metricspb.MetricDescriptor_GAUGE_DOUBLE
+	converted.MetricDescriptor.Unit = descriptor.Unit + "/s"
+	for _, ts := range metric.Timeseries {
+		var points []*metricspb.Point
+		for _, point := range ts.Points {
+			seconds := intervalSeconds(ts.StartTimestamp, point.Timestamp)
+			if seconds <= 0 {
+				continue
+			}
+			var value float64
+			switch v := point.Value.(type) {
+			case *metricspb.Point_Int64Value:
+				value = float64(v.Int64Value)
+			case *metricspb.Point_DoubleValue:
+				value = v.DoubleValue
+			default:
+				continue
+			}
+			points = append(points, &metricspb.Point{
+				Timestamp: point.Timestamp,
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: value / seconds},
+			})
+		}
+		if len(points) > 0 {
+			converted.Timeseries = append(converted.Timeseries, &metricspb.TimeSeries{
+				StartTimestamp: ts.StartTimestamp,
+				LabelValues:    ts.LabelValues,
+				Points:         points,
+			})
+		}
+	}
+	if len(converted.Timeseries) == 0 {
+		return nil
+	}
+	return converted
+}
+
+// intervalSeconds returns the seconds between the timestamps, 0 if either is
+// missing.
+func intervalSeconds(start, end *timestamp.Timestamp) float64 {
+	if start == nil || end == nil {
+		return 0
+	}
+	return float64(end.Seconds-start.Seconds) + float64(end.Nanos-start.Nanos)/1e9
+}
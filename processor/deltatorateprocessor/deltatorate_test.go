@@ -0,0 +1,94 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func newTestMetric(name string, metricType metricspb.MetricDescriptor_Type, start *timestamp.Timestamp, points ...*metricspb.Point) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: name, Unit: "1", Type: metricType},
+		Timeseries: []*metricspb.TimeSeries{{
+			StartTimestamp: start,
+			LabelValues:    []*metricspb.LabelValue{{Value: "GET", HasValue: true}},
+			Points:         points,
+		}},
+	}
+}
+
+func TestDeltaToRate(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	mp, err := NewMetricsProcessor(sink, Config{Metrics: []string{"requests", "bytes", "no-interval"}})
+	require.NoError(t, err)
+
+	start := &timestamp.Timestamp{Seconds: 100}
+	requests := newTestMetric("requests", metricspb.MetricDescriptor_GAUGE_INT64, start,
+		&metricspb.Point{
+			Timestamp: &timestamp.Timestamp{Seconds: 110},
+			Value:     &metricspb.Point_Int64Value{Int64Value: 50},
+		},
+		// Not after the start.
+		&metricspb.Point{
+			Timestamp: &timestamp.Timestamp{Seconds: 100},
+			Value:     &metricspb.Point_Int64Value{Int64Value: 50},
+		})
+	bytes := newTestMetric("bytes", metricspb.MetricDescriptor_GAUGE_DOUBLE, start, &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: 100, Nanos: 500000000},
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: 1024},
+	})
+	noInterval := newTestMetric("no-interval", metricspb.MetricDescriptor_GAUGE_INT64, nil, &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: 110},
+		Value:     &metricspb.Point_Int64Value{Int64Value: 50},
+	})
+	other := newTestMetric("memory", metricspb.MetricDescriptor_GAUGE_INT64, start)
+	cumulative := newTestMetric("requests", metricspb.MetricDescriptor_CUMULATIVE_INT64, start)
+
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{requests, bytes, noInterval, other, cumulative},
+	}))
+	// Nothing is sent when no metric is left.
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{noInterval},
+	}))
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Metrics, 4)
+
+	rate := got[0].Metrics[0]
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, rate.MetricDescriptor.Type)
+	assert.Equal(t, "1/s", rate.MetricDescriptor.Unit)
+	require.Len(t, rate.Timeseries, 1)
+	require.Len(t, rate.Timeseries[0].Points, 1)
+	assert.Equal(t, 5.0, rate.Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, start, rate.Timeseries[0].StartTimestamp)
+	assert.Equal(t, 2048.0, got[0].Metrics[1].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, other, got[0].Metrics[2])
+	assert.Equal(t, cumulative, got[0].Metrics[3])
+
+	// The metrics received are not modified.
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, requests.MetricDescriptor.Type)
+	assert.Equal(t, int64(50), requests.Timeseries[0].Points[0].GetInt64Value())
+}
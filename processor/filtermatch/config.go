@@ -0,0 +1,66 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filtermatch
+
+// MatchType specifies how the names and values are compared to the ones of
+// the data.
+type MatchType string
+
+const (
+	// MatchTypeStrict compares the names and values for equality.
+	MatchTypeStrict MatchType = "strict"
+
+	// MatchTypeRegexp matches the names and values against regular
+	// expressions, see https://golang.org/pkg/regexp/syntax/.
+	MatchTypeRegexp MatchType = "regexp"
+
+	// MatchTypePrefix matches the names and values starting with the given
+	// prefixes.
+	MatchTypePrefix MatchType = "prefix"
+)
+
+// MatchProperties specifies the properties the data must have to match.
+// Each of the properties that is set must match: one of the services and one
+// of the span or metric names, and all the attributes and resource labels.
+type MatchProperties struct {
+	// MatchType is how the properties are compared, "strict" by default.
+	MatchType MatchType `mapstructure:"match-type"`
+
+	// Services are the names of the services, from the node sending the data.
+	Services []string `mapstructure:"services"`
+
+	// SpanNames are the names of the spans, they only apply to spans.
+	SpanNames []string `mapstructure:"span-names"`
+
+	// MetricNames are the names of the metrics, they only apply to metrics.
+	MetricNames []string `mapstructure:"metric-names"`
+
+	// Attributes are the attributes of the spans, they only apply to spans.
+	Attributes []Attribute `mapstructure:"attributes"`
+
+	// ResourceLabels are the labels of the resource of the data.
+	ResourceLabels []Attribute `mapstructure:"resource-labels"`
+}
+
+// Attribute specifies an attribute or label, by key, and its value.
+type Attribute struct {
+	// Key is always compared strictly.
+	Key string `mapstructure:"key"`
+
+	// Value is compared according to the match type. When it is empty only
+	// the presence of the key is checked. The values that are not strings are
+	// compared with their string representation, e.g. "true" or "42".
+	Value string `mapstructure:"value"`
+}
@@ -0,0 +1,267 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filtermatch selects the data a processor applies to, by matching
+// the services, span and metric names, attributes and resource labels against
+// include and exclude properties.
+package filtermatch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+// Filter selects the spans, or the metrics, matching the include properties
+// and not matching the exclude properties. A nil Filter matches all the spans
+// and metrics.
+type Filter struct {
+	include *matcher
+	exclude *matcher
+}
+
+// NewFilter creates a Filter of spans from the include and exclude properties,
+// any of them can be nil to not include or exclude based on it.
+func NewFilter(include, exclude *MatchProperties) (*Filter, error) {
+	return newFilter(include, exclude, false)
+}
+
+// NewMetricsFilter creates a Filter of metrics from the include and exclude
+// properties, any of them can be nil to not include or exclude based on it.
+func NewMetricsFilter(include, exclude *MatchProperties) (*Filter, error) {
+	return newFilter(include, exclude, true)
+}
+
+func newFilter(include, exclude *MatchProperties, metrics bool) (*Filter, error) {
+	var f Filter
+	var err error
+	if include != nil {
+		if f.include, err = newMatcher(include, metrics); err != nil {
+			return nil, fmt.Errorf("invalid include properties: %v", err)
+		}
+	}
+	if exclude != nil {
+		if f.exclude, err = newMatcher(exclude, metrics); err != nil {
+			return nil, fmt.Errorf("invalid exclude properties: %v", err)
+		}
+	}
+	return &f, nil
+}
+
+// MatchSpan returns true if the span, sent by the node with the resource, is
+// selected by the filter. The resource of the span, when set, takes precedence
+// over the given one.
+func (f *Filter) MatchSpan(node *commonpb.Node, resource *resourcepb.Resource, span *tracepb.Span) bool {
+	if f == nil {
+		return true
+	}
+	if span.GetResource() != nil {
+		resource = span.GetResource()
+	}
+	if f.include != nil && !f.include.matchSpan(node, resource, span) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.matchSpan(node, resource, span)
+}
+
+// MatchMetric returns true if the metric, sent by the node with the resource,
+// is selected by the filter. The resource of the metric, when set, takes
+// precedence over the given one.
+func (f *Filter) MatchMetric(node *commonpb.Node, resource *resourcepb.Resource, metric *metricspb.Metric) bool {
+	if f == nil {
+		return true
+	}
+	if metric.GetResource() != nil {
+		resource = metric.GetResource()
+	}
+	if f.include != nil && !f.include.matchMetric(node, resource, metric) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.matchMetric(node, resource, metric)
+}
+
+type matcher struct {
+	services       []stringMatcher
+	spanNames      []stringMatcher
+	metricNames    []stringMatcher
+	attributes     []attributeMatcher
+	resourceLabels []attributeMatcher
+}
+
+type stringMatcher func(string) bool
+
+type attributeMatcher struct {
+	key   string
+	value stringMatcher
+}
+
+func newMatcher(mp *MatchProperties, metrics bool) (*matcher, error) {
+	if metrics {
+		if len(mp.SpanNames) > 0 || len(mp.Attributes) > 0 {
+			return nil, errors.New("span-names and attributes don't apply to metrics")
+		}
+		if len(mp.Services) == 0 && len(mp.MetricNames) == 0 && len(mp.ResourceLabels) == 0 {
+			return nil, errors.New("at least one of services, metric-names or resource-labels must be set")
+		}
+	} else {
+		if len(mp.MetricNames) > 0 {
+			return nil, errors.New("metric-names don't apply to spans")
+		}
+		if len(mp.Services) == 0 && len(mp.SpanNames) == 0 &&
+			len(mp.Attributes) == 0 && len(mp.ResourceLabels) == 0 {
+			return nil, errors.New("at least one of services, span-names, attributes or resource-labels must be set")
+		}
+	}
+
+	newStringMatcher := func(s string) (stringMatcher, error) {
+		return func(v string) bool { return v == s }, nil
+	}
+	switch mp.MatchType {
+	case "", MatchTypeStrict:
+	case MatchTypeRegexp:
+		newStringMatcher = func(s string) (stringMatcher, error) {
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, err
+			}
+			return re.MatchString, nil
+		}
+	case MatchTypePrefix:
+		newStringMatcher = func(s string) (stringMatcher, error) {
+			return func(v string) bool { return strings.HasPrefix(v, s) }, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown match-type %q, must be %q, %q or %q",
+			mp.MatchType, MatchTypeStrict, MatchTypeRegexp, MatchTypePrefix)
+	}
+
+	newStringMatchers := func(strs []string) ([]stringMatcher, error) {
+		var matchers []stringMatcher
+		for _, s := range strs {
+			m, err := newStringMatcher(s)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+		}
+		return matchers, nil
+	}
+	newAttributeMatchers := func(attrs []Attribute) ([]attributeMatcher, error) {
+		var matchers []attributeMatcher
+		for _, attr := range attrs {
+			if attr.Key == "" {
+				return nil, errors.New("attribute key must not be empty")
+			}
+			am := attributeMatcher{key: attr.Key}
+			if attr.Value != "" {
+				m, err := newStringMatcher(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				am.value = m
+			}
+			matchers = append(matchers, am)
+		}
+		return matchers, nil
+	}
+
+	var m matcher
+	var err error
+	if m.services, err = newStringMatchers(mp.Services); err != nil {
+		return nil, err
+	}
+	if m.spanNames, err = newStringMatchers(mp.SpanNames); err != nil {
+		return nil, err
+	}
+	if m.metricNames, err = newStringMatchers(mp.MetricNames); err != nil {
+		return nil, err
+	}
+	if m.attributes, err = newAttributeMatchers(mp.Attributes); err != nil {
+		return nil, err
+	}
+	if m.resourceLabels, err = newAttributeMatchers(mp.ResourceLabels); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *matcher) matchSpan(node *commonpb.Node, resource *resourcepb.Resource, span *tracepb.Span) bool {
+	if len(m.services) > 0 && !matchAny(m.services, node.GetServiceInfo().GetName()) {
+		return false
+	}
+	if len(m.spanNames) > 0 && !matchAny(m.spanNames, span.GetName().GetValue()) {
+		return false
+	}
+	attributes := span.GetAttributes().GetAttributeMap()
+	for _, am := range m.attributes {
+		value, ok := attributes[am.key]
+		if !ok || (am.value != nil && !am.value(AttributeValueString(value))) {
+			return false
+		}
+	}
+	return m.matchResource(resource)
+}
+
+func (m *matcher) matchMetric(node *commonpb.Node, resource *resourcepb.Resource, metric *metricspb.Metric) bool {
+	if len(m.services) > 0 && !matchAny(m.services, node.GetServiceInfo().GetName()) {
+		return false
+	}
+	if len(m.metricNames) > 0 && !matchAny(m.metricNames, metric.GetMetricDescriptor().GetName()) {
+		return false
+	}
+	return m.matchResource(resource)
+}
+
+func (m *matcher) matchResource(resource *resourcepb.Resource) bool {
+	labels := resource.GetLabels()
+	for _, am := range m.resourceLabels {
+		value, ok := labels[am.key]
+		if !ok || (am.value != nil && !am.value(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(matchers []stringMatcher, s string) bool {
+	for _, m := range matchers {
+		if m(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// AttributeValueString returns the string representation of the value, the
+// one the attributes are matched against.
+func AttributeValueString(value *tracepb.AttributeValue) string {
+	switch v := value.GetValue().(type) {
+	case *tracepb.AttributeValue_StringValue:
+		return v.StringValue.GetValue()
+	case *tracepb.AttributeValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *tracepb.AttributeValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *tracepb.AttributeValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	}
+	return ""
+}
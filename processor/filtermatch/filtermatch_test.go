@@ -0,0 +1,265 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filtermatch
+
+import (
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mp   *MatchProperties
+	}{
+		{
+			name: "empty",
+			mp:   &MatchProperties{},
+		},
+		{
+			name: "unknown_match_type",
+			mp:   &MatchProperties{MatchType: "exact", Services: []string{"svc"}},
+		},
+		{
+			name: "invalid_regexp",
+			mp:   &MatchProperties{MatchType: MatchTypeRegexp, SpanNames: []string{"("}},
+		},
+		{
+			name: "empty_attribute_key",
+			mp:   &MatchProperties{Attributes: []Attribute{{Value: "v"}}},
+		},
+		{
+			name: "metric_names",
+			mp:   &MatchProperties{MetricNames: []string{"m"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFilter(tt.mp, nil)
+			assert.Error(t, err)
+			_, err = NewFilter(nil, tt.mp)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewMetricsFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mp   *MatchProperties
+	}{
+		{
+			name: "empty",
+			mp:   &MatchProperties{},
+		},
+		{
+			name: "span_names",
+			mp:   &MatchProperties{SpanNames: []string{"s"}},
+		},
+		{
+			name: "attributes",
+			mp:   &MatchProperties{MetricNames: []string{"m"}, Attributes: []Attribute{{Key: "k"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMetricsFilter(tt.mp, nil)
+			assert.Error(t, err)
+			_, err = NewMetricsFilter(nil, tt.mp)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFilter_MatchSpan(t *testing.T) {
+	node := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}}
+	resource := &resourcepb.Resource{Labels: map[string]string{"host": "host-1"}}
+	span := &tracepb.Span{
+		Name: &tracepb.TruncatableString{Value: "GET /health"},
+		Attributes: &tracepb.Span_Attributes{
+			AttributeMap: map[string]*tracepb.AttributeValue{
+				"http.status_code": {Value: &tracepb.AttributeValue_IntValue{IntValue: 200}},
+				"db.type": {Value: &tracepb.AttributeValue_StringValue{
+					StringValue: &tracepb.TruncatableString{Value: "sql"},
+				}},
+				"error": {Value: &tracepb.AttributeValue_BoolValue{BoolValue: false}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		include *MatchProperties
+		exclude *MatchProperties
+		want    bool
+	}{
+		{
+			name: "no_properties",
+			want: true,
+		},
+		{
+			name:    "include_service",
+			include: &MatchProperties{Services: []string{"backend", "frontend"}},
+			want:    true,
+		},
+		{
+			name:    "include_other_service",
+			include: &MatchProperties{Services: []string{"backend"}},
+			want:    false,
+		},
+		{
+			name:    "include_service_and_span_name",
+			include: &MatchProperties{Services: []string{"frontend"}, SpanNames: []string{"GET /"}},
+			want:    false,
+		},
+		{
+			name: "include_span_name_regexp",
+			include: &MatchProperties{
+				MatchType: MatchTypeRegexp,
+				SpanNames: []string{"^GET "},
+			},
+			want: true,
+		},
+		{
+			name: "include_span_name_prefix",
+			include: &MatchProperties{
+				MatchType: MatchTypePrefix,
+				SpanNames: []string{"POST ", "GET "},
+			},
+			want: true,
+		},
+		{
+			name: "include_attributes",
+			include: &MatchProperties{Attributes: []Attribute{
+				{Key: "http.status_code", Value: "200"},
+				{Key: "db.type"},
+				{Key: "error", Value: "false"},
+			}},
+			want: true,
+		},
+		{
+			name: "include_attributes_missing_key",
+			include: &MatchProperties{Attributes: []Attribute{
+				{Key: "http.status_code"},
+				{Key: "http.method"},
+			}},
+			want: false,
+		},
+		{
+			name: "include_attribute_regexp",
+			include: &MatchProperties{
+				MatchType:  MatchTypeRegexp,
+				Attributes: []Attribute{{Key: "http.status_code", Value: "^5"}},
+			},
+			want: false,
+		},
+		{
+			name:    "include_resource_labels",
+			include: &MatchProperties{ResourceLabels: []Attribute{{Key: "host", Value: "host-1"}}},
+			want:    true,
+		},
+		{
+			name:    "exclude_span_name",
+			exclude: &MatchProperties{SpanNames: []string{"GET /health"}},
+			want:    false,
+		},
+		{
+			name:    "include_and_exclude",
+			include: &MatchProperties{Services: []string{"frontend"}},
+			exclude: &MatchProperties{ResourceLabels: []Attribute{{Key: "host", Value: "host-2"}}},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.include, tt.exclude)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.MatchSpan(node, resource, span))
+		})
+	}
+}
+
+func TestFilter_MatchMetric(t *testing.T) {
+	node := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}}
+	resource := &resourcepb.Resource{Labels: map[string]string{"host": "host-1"}}
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: "debug/queue_length"},
+	}
+
+	tests := []struct {
+		name    string
+		include *MatchProperties
+		exclude *MatchProperties
+		want    bool
+	}{
+		{
+			name: "no_properties",
+			want: true,
+		},
+		{
+			name:    "include_metric_name",
+			include: &MatchProperties{MetricNames: []string{"debug/queue_length"}},
+			want:    true,
+		},
+		{
+			name: "exclude_metric_name_prefix",
+			exclude: &MatchProperties{
+				MatchType:   MatchTypePrefix,
+				MetricNames: []string{"debug/"},
+			},
+			want: false,
+		},
+		{
+			name: "include_service_and_resource_labels",
+			include: &MatchProperties{
+				Services:       []string{"frontend"},
+				ResourceLabels: []Attribute{{Key: "host", Value: "host-2"}},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewMetricsFilter(tt.include, tt.exclude)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.MatchMetric(node, resource, metric))
+		})
+	}
+}
+
+func TestFilter_MatchSpanResource(t *testing.T) {
+	f, err := NewFilter(&MatchProperties{ResourceLabels: []Attribute{{Key: "host", Value: "host-2"}}}, nil)
+	require.NoError(t, err)
+
+	resource := &resourcepb.Resource{Labels: map[string]string{"host": "host-1"}}
+	span := &tracepb.Span{}
+	assert.False(t, f.MatchSpan(nil, resource, span))
+
+	// The resource of the span takes precedence.
+	span.Resource = &resourcepb.Resource{Labels: map[string]string{"host": "host-2"}}
+	assert.True(t, f.MatchSpan(nil, resource, span))
+}
+
+func TestFilter_Nil(t *testing.T) {
+	var f *Filter
+	assert.True(t, f.MatchSpan(nil, nil, &tracepb.Span{}))
+	assert.True(t, f.MatchMetric(nil, nil, &metricspb.Metric{}))
+}
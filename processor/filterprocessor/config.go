@@ -0,0 +1,38 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+// Config defines configuration for filter processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Spans selects the spans that are kept, the other ones are dropped.
+	Spans MatchConfig `mapstructure:"spans"`
+
+	// Metrics selects the metrics that are kept, the other ones are dropped.
+	Metrics MatchConfig `mapstructure:"metrics"`
+}
+
+// MatchConfig specifies the data that is kept: the data matching Include, if
+// set, and not matching Exclude, if set.
+type MatchConfig struct {
+	Include *filtermatch.MatchProperties `mapstructure:"include"`
+	Exclude *filtermatch.MatchProperties `mapstructure:"exclude"`
+}
@@ -0,0 +1,101 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factory := &Factory{}
+	processors[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, len(cfg.Processors), 4)
+
+	p0 := cfg.Processors["filter"]
+	assert.Equal(t, p0, factory.CreateDefaultConfig())
+
+	p1 := cfg.Processors["filter/health-checks"]
+	assert.Equal(t, p1,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "filter",
+				NameVal: "filter/health-checks",
+			},
+			Spans: MatchConfig{
+				Exclude: &filtermatch.MatchProperties{
+					MatchType: filtermatch.MatchTypeRegexp,
+					SpanNames: []string{"^GET /health"},
+				},
+			},
+		})
+
+	p2 := cfg.Processors["filter/debug-metrics"]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "filter",
+				NameVal: "filter/debug-metrics",
+			},
+			Metrics: MatchConfig{
+				Exclude: &filtermatch.MatchProperties{
+					MatchType:   filtermatch.MatchTypePrefix,
+					MetricNames: []string{"debug/"},
+				},
+			},
+		})
+
+	p3 := cfg.Processors["filter/keep"]
+	assert.Equal(t, p3,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "filter",
+				NameVal: "filter/keep",
+			},
+			Spans: MatchConfig{
+				Include: &filtermatch.MatchProperties{
+					Services: []string{"frontend", "backend"},
+					Attributes: []filtermatch.Attribute{
+						{Key: "sampled", Value: "true"},
+					},
+				},
+			},
+			Metrics: MatchConfig{
+				Include: &filtermatch.MatchProperties{
+					ResourceLabels: []filtermatch.Attribute{
+						{Key: "env", Value: "production"},
+					},
+				},
+			},
+		})
+
+	assert.Equal(t, []string{"filter/debug-metrics"}, cfg.Pipelines["metrics"].Processors)
+}
@@ -0,0 +1,117 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filterprocessor implements a processor dropping the spans and
+// metrics that are not selected by its filters, e.g. the health check spans
+// or the high cardinality debug metrics, before they reach the exporters.
+package filterprocessor
+
+import (
+	"context"
+	"errors"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+var errNilNextConsumer = errors.New("nil nextConsumer")
+
+type filterTraceProcessor struct {
+	nextConsumer consumer.TraceConsumer
+	filter       *filtermatch.Filter
+}
+
+var _ processor.TraceProcessor = (*filterTraceProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor passing to the next
+// consumer only the spans selected by the filter. The data left without spans
+// is dropped.
+func NewTraceProcessor(nextConsumer consumer.TraceConsumer, filter *filtermatch.Filter) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	return &filterTraceProcessor{
+		nextConsumer: nextConsumer,
+		filter:       filter,
+	}, nil
+}
+
+func (ftp *filterTraceProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if len(td.Spans) == 0 {
+		return ftp.nextConsumer.ConsumeTraceData(ctx, td)
+	}
+	spans := make([]*tracepb.Span, 0, len(td.Spans))
+	for _, span := range td.Spans {
+		if span != nil && ftp.filter.MatchSpan(td.Node, td.Resource, span) {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	td.Spans = spans
+	return ftp.nextConsumer.ConsumeTraceData(ctx, td)
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, the matching spans are sent in a new slice.
+func (ftp *filterTraceProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+type filterMetricsProcessor struct {
+	nextConsumer consumer.MetricsConsumer
+	filter       *filtermatch.Filter
+}
+
+var _ processor.MetricsProcessor = (*filterMetricsProcessor)(nil)
+
+// NewMetricsProcessor returns a processor.MetricsProcessor passing to the next
+// consumer only the metrics selected by the filter. The data left without
+// metrics is dropped.
+func NewMetricsProcessor(nextConsumer consumer.MetricsConsumer, filter *filtermatch.Filter) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	return &filterMetricsProcessor{
+		nextConsumer: nextConsumer,
+		filter:       filter,
+	}, nil
+}
+
+func (fmp *filterMetricsProcessor) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if len(md.Metrics) == 0 {
+		return fmp.nextConsumer.ConsumeMetricsData(ctx, md)
+	}
+	metrics := make([]*metricspb.Metric, 0, len(md.Metrics))
+	for _, metric := range md.Metrics {
+		if metric != nil && fmp.filter.MatchMetric(md.Node, md.Resource, metric) {
+			metrics = append(metrics, metric)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+	md.Metrics = metrics
+	return fmp.nextConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, the matching metrics are sent in a new slice.
+func (fmp *filterMetricsProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
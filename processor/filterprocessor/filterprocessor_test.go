@@ -0,0 +1,93 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+func TestNewProcessorNilNextConsumer(t *testing.T) {
+	tp, err := NewTraceProcessor(nil, nil)
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+
+	mp, err := NewMetricsProcessor(nil, nil)
+	assert.Nil(t, mp)
+	assert.Error(t, err)
+}
+
+func TestFilterTraceProcessor(t *testing.T) {
+	filter, err := filtermatch.NewFilter(nil, &filtermatch.MatchProperties{
+		MatchType: filtermatch.MatchTypePrefix,
+		SpanNames: []string{"GET /health"},
+	})
+	require.NoError(t, err)
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewTraceProcessor(sink, filter)
+	require.NoError(t, err)
+
+	newSpan := func(name string) *tracepb.Span {
+		return &tracepb.Span{Name: &tracepb.TruncatableString{Value: name}}
+	}
+	ctx := context.Background()
+	require.NoError(t, tp.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Spans: []*tracepb.Span{newSpan("GET /health/live"), nil, newSpan("GET /users")},
+	}))
+	// All the spans are dropped, the data isn't sent.
+	require.NoError(t, tp.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Spans: []*tracepb.Span{newSpan("GET /health")},
+	}))
+
+	traces := sink.AllTraces()
+	require.Equal(t, 1, len(traces))
+	assert.Equal(t, []*tracepb.Span{newSpan("GET /users")}, traces[0].Spans)
+}
+
+func TestFilterMetricsProcessor(t *testing.T) {
+	filter, err := filtermatch.NewMetricsFilter(&filtermatch.MatchProperties{
+		MatchType:   filtermatch.MatchTypeRegexp,
+		MetricNames: []string{"^http/"},
+	}, nil)
+	require.NoError(t, err)
+	sink := new(exportertest.SinkMetricsExporter)
+	mp, err := NewMetricsProcessor(sink, filter)
+	require.NoError(t, err)
+
+	newMetric := func(name string) *metricspb.Metric {
+		return &metricspb.Metric{MetricDescriptor: &metricspb.MetricDescriptor{Name: name}}
+	}
+	ctx := context.Background()
+	require.NoError(t, mp.ConsumeMetricsData(ctx, consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{newMetric("http/latency"), newMetric("debug/queue_length")},
+	}))
+	// All the metrics are dropped, the data isn't sent.
+	require.NoError(t, mp.ConsumeMetricsData(ctx, consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{newMetric("debug/queue_length")},
+	}))
+
+	metrics := sink.AllMetrics()
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, []*metricspb.Metric{newMetric("http/latency")}, metrics[0].Metrics)
+}
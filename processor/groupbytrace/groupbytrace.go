@@ -0,0 +1,260 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package groupbytrace implements a processor reassembling the traces: it
+// keeps the spans of each trace for a while and then releases them together,
+// so that the next processors and exporters see whole traces even when the
+// receivers deliver them in fragments.
+package groupbytrace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+// traceKey is the trace ID as a string, to be used as a map key.
+type traceKey string
+
+// traceGroup holds the spans received for a trace, grouped by node and
+// resource.
+type traceGroup struct {
+	arrival time.Time
+	batches []consumerdata.TraceData
+}
+
+type groupByTraceProcessor struct {
+	nextConsumer consumer.TraceConsumer
+	logger       *zap.Logger
+	waitDuration time.Duration
+	numTraces    int
+	now          func() time.Time
+	// obsCtx is used to record the obsreport metrics of the processor.
+	obsCtx context.Context
+
+	mu     sync.Mutex
+	traces map[traceKey]*traceGroup
+	// queue holds the keys of the traces in arrival order, the traces are
+	// released from its front.
+	queue []traceKey
+	// inFlight is the number of spans kept in traces.
+	inFlight int
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+var _ processor.TraceProcessor = (*groupByTraceProcessor)(nil)
+var _ processor.Flusher = (*groupByTraceProcessor)(nil)
+var _ processor.Stopper = (*groupByTraceProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor that groups the spans
+// by trace, and passes each trace to the next consumer once the wait duration
+// elapsed since its first span arrived. The spans of a trace sent by several
+// nodes, or with several resources, are passed in several consecutive calls.
+func NewTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if cfg.WaitDuration <= 0 {
+		return nil, errors.New("wait-duration must be greater than zero")
+	}
+	if cfg.NumTraces <= 0 {
+		return nil, errors.New("num-traces must be greater than zero")
+	}
+
+	gbt := &groupByTraceProcessor{
+		nextConsumer: nextConsumer,
+		logger:       logger,
+		waitDuration: cfg.WaitDuration,
+		numTraces:    cfg.NumTraces,
+		now:          time.Now,
+		obsCtx:       obsreport.ProcessorContext(context.Background(), cfg.Name()),
+		traces:       make(map[traceKey]*traceGroup),
+		stopCh:       make(chan struct{}),
+	}
+	tickTime := cfg.WaitDuration / 10
+	if tickTime < time.Millisecond {
+		tickTime = time.Millisecond
+	}
+	go gbt.releaseExpiredEvery(tickTime)
+	return gbt, nil
+}
+
+func (gbt *groupByTraceProcessor) releaseExpiredEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			gbt.release(gbt.takeExpired())
+		case <-gbt.stopCh:
+			return
+		}
+	}
+}
+
+// Stop halts the periodic release of the traces, the remaining ones are
+// released by Flush.
+func (gbt *groupByTraceProcessor) Stop() {
+	gbt.stopOnce.Do(func() {
+		close(gbt.stopCh)
+	})
+}
+
+func (gbt *groupByTraceProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	// Group the spans per trace ID first, to add each trace only once.
+	var keys []traceKey
+	var withoutTraceID int
+	idToSpans := make(map[traceKey][]*tracepb.Span)
+	for _, span := range td.Spans {
+		if span == nil || len(span.TraceId) == 0 {
+			withoutTraceID++
+			continue
+		}
+		key := traceKey(span.TraceId)
+		if _, ok := idToSpans[key]; !ok {
+			keys = append(keys, key)
+		}
+		idToSpans[key] = append(idToSpans[key], span)
+	}
+	if withoutTraceID > 0 {
+		obsreport.ProcessorTraceDataDropped(gbt.obsCtx, withoutTraceID)
+		gbt.logger.Debug("Spans without TraceId dropped",
+			zap.Int("count", withoutTraceID),
+			zap.String("SourceFormat", td.SourceFormat))
+	}
+
+	var evicted []*traceGroup
+	gbt.mu.Lock()
+	for _, key := range keys {
+		group, ok := gbt.traces[key]
+		if !ok {
+			if len(gbt.queue) >= gbt.numTraces {
+				evicted = append(evicted, gbt.popOldest())
+			}
+			group = &traceGroup{arrival: gbt.now()}
+			gbt.traces[key] = group
+			gbt.queue = append(gbt.queue, key)
+		}
+		spans := idToSpans[key]
+		group.add(td, spans)
+		gbt.inFlight += len(spans)
+	}
+	gbt.mu.Unlock()
+
+	gbt.release(evicted)
+	return nil
+}
+
+// Flush synchronously releases all the traces, regardless of their arrival,
+// e.g. when the service shuts down.
+func (gbt *groupByTraceProcessor) Flush(ctx context.Context) error {
+	gbt.mu.Lock()
+	var groups []*traceGroup
+	for len(gbt.queue) > 0 {
+		groups = append(groups, gbt.popOldest())
+	}
+	gbt.mu.Unlock()
+
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		gbt.release([]*traceGroup{group})
+	}
+	return nil
+}
+
+// InFlight returns the number of spans waiting for their trace to be released.
+func (gbt *groupByTraceProcessor) InFlight() int {
+	gbt.mu.Lock()
+	defer gbt.mu.Unlock()
+	return gbt.inFlight
+}
+
+// takeExpired removes and returns the traces whose wait duration elapsed.
+func (gbt *groupByTraceProcessor) takeExpired() []*traceGroup {
+	gbt.mu.Lock()
+	defer gbt.mu.Unlock()
+
+	deadline := gbt.now().Add(-gbt.waitDuration)
+	var expired []*traceGroup
+	for len(gbt.queue) > 0 && !gbt.traces[gbt.queue[0]].arrival.After(deadline) {
+		expired = append(expired, gbt.popOldest())
+	}
+	return expired
+}
+
+// popOldest removes and returns the oldest trace, the caller must hold the
+// lock and ensure that the queue isn't empty.
+func (gbt *groupByTraceProcessor) popOldest() *traceGroup {
+	key := gbt.queue[0]
+	gbt.queue[0] = ""
+	gbt.queue = gbt.queue[1:]
+	group := gbt.traces[key]
+	delete(gbt.traces, key)
+	for _, batch := range group.batches {
+		gbt.inFlight -= len(batch.Spans)
+	}
+	return group
+}
+
+func (gbt *groupByTraceProcessor) release(groups []*traceGroup) {
+	for _, group := range groups {
+		for _, batch := range group.batches {
+			if err := gbt.nextConsumer.ConsumeTraceData(context.Background(), batch); err != nil {
+				gbt.logger.Warn("Error sending trace to the next consumer", zap.Error(err))
+			}
+		}
+	}
+}
+
+// add adds the spans, from the given trace data, to the batch with the same
+// node and resource.
+func (group *traceGroup) add(td consumerdata.TraceData, spans []*tracepb.Span) {
+	for i := range group.batches {
+		batch := &group.batches[i]
+		if sameOrigin(batch, &td) {
+			batch.Spans = append(batch.Spans, spans...)
+			return
+		}
+	}
+	group.batches = append(group.batches, consumerdata.TraceData{
+		Node:         td.Node,
+		Resource:     td.Resource,
+		Spans:        spans,
+		SourceFormat: td.SourceFormat,
+	})
+}
+
+func sameOrigin(a, b *consumerdata.TraceData) bool {
+	if a.SourceFormat != b.SourceFormat {
+		return false
+	}
+	if a.Node != b.Node && !proto.Equal(a.Node, b.Node) {
+		return false
+	}
+	return a.Resource == b.Resource || proto.Equal(a.Resource, b.Resource)
+}
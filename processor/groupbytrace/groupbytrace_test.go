@@ -0,0 +1,177 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytrace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
+)
+
+func TestNewTraceProcessor(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  Config{WaitDuration: time.Second, NumTraces: 10},
+		},
+		{
+			name:    "no_wait_duration",
+			cfg:     Config{NumTraces: 10},
+			wantErr: true,
+		},
+		{
+			name:    "no_num_traces",
+			cfg:     Config{WaitDuration: time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTraceProcessor(zap.NewNop(), sink, tt.cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+
+	_, err := NewTraceProcessor(zap.NewNop(), nil, Config{WaitDuration: time.Second, NumTraces: 10})
+	assert.Error(t, err)
+}
+
+func TestGroupByTrace(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewTraceProcessor(zap.NewNop(), sink, Config{WaitDuration: time.Hour, NumTraces: 10})
+	require.NoError(t, err)
+	gbt := tp.(*groupByTraceProcessor)
+	// The traces are released by the test, not by the timer.
+	gbt.Stop()
+	now := time.Unix(1000, 0)
+	gbt.now = func() time.Time { return now }
+
+	nodeA := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "a"}}
+	nodeB := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "b"}}
+	ctx := context.Background()
+	// The fragments of trace 1 arrive from two nodes, mixed with trace 2.
+	require.NoError(t, gbt.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Node:  nodeA,
+		Spans: []*tracepb.Span{newSpan(1, 1)},
+	}))
+	now = now.Add(time.Minute)
+	require.NoError(t, gbt.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "a"}},
+		Spans: []*tracepb.Span{newSpan(2, 1), newSpan(1, 2), nil},
+	}))
+	require.NoError(t, gbt.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Node:  nodeB,
+		Spans: []*tracepb.Span{newSpan(1, 3)},
+	}))
+	assert.Equal(t, 4, gbt.InFlight())
+	assert.Empty(t, gbt.takeExpired())
+
+	// Only trace 1 waited long enough.
+	now = now.Add(time.Hour - time.Minute)
+	gbt.release(gbt.takeExpired())
+	traces := sink.AllTraces()
+	require.Equal(t, 2, len(traces))
+	assert.Equal(t, nodeA, traces[0].Node)
+	assert.Equal(t, []*tracepb.Span{newSpan(1, 1), newSpan(1, 2)}, traces[0].Spans)
+	assert.Equal(t, nodeB, traces[1].Node)
+	assert.Equal(t, []*tracepb.Span{newSpan(1, 3)}, traces[1].Spans)
+	assert.Equal(t, 1, gbt.InFlight())
+
+	// Flush releases the remaining traces.
+	require.NoError(t, gbt.Flush(ctx))
+	traces = sink.AllTraces()
+	require.Equal(t, 3, len(traces))
+	assert.Equal(t, []*tracepb.Span{newSpan(2, 1)}, traces[2].Spans)
+	assert.Equal(t, 0, gbt.InFlight())
+}
+
+func TestGroupByTraceNumTraces(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewTraceProcessor(zap.NewNop(), sink, Config{WaitDuration: time.Hour, NumTraces: 2})
+	require.NoError(t, err)
+	defer tp.(*groupByTraceProcessor).Stop()
+
+	for traceID := uint64(1); traceID <= 3; traceID++ {
+		require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+			Spans: []*tracepb.Span{newSpan(traceID, 1)},
+		}))
+	}
+
+	// The oldest trace is released early to make room for the third one.
+	traces := sink.AllTraces()
+	require.Equal(t, 1, len(traces))
+	assert.Equal(t, []*tracepb.Span{newSpan(1, 1)}, traces[0].Spans)
+	assert.Equal(t, 2, tp.(*groupByTraceProcessor).InFlight())
+}
+
+func TestGroupByTraceTimer(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewTraceProcessor(zap.NewNop(), sink, Config{WaitDuration: 10 * time.Millisecond, NumTraces: 10})
+	require.NoError(t, err)
+	defer tp.(*groupByTraceProcessor).Stop()
+
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+		Spans: []*tracepb.Span{newSpan(1, 1)},
+	}))
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.AllTraces()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the trace wasn't released after the wait duration")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGroupByTraceStop(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewTraceProcessor(zap.NewNop(), sink, Config{WaitDuration: time.Millisecond, NumTraces: 10})
+	require.NoError(t, err)
+	gbt := tp.(*groupByTraceProcessor)
+	gbt.Stop()
+	gbt.Stop()
+
+	require.NoError(t, gbt.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+		Spans: []*tracepb.Span{newSpan(1, 1)},
+	}))
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, sink.AllTraces())
+	assert.Equal(t, 1, gbt.InFlight())
+
+	// The stopped processor is still flushed.
+	require.NoError(t, gbt.Flush(context.Background()))
+	assert.Equal(t, 1, len(sink.AllTraces()))
+}
+
+func newSpan(traceID, spanID uint64) *tracepb.Span {
+	return &tracepb.Span{
+		TraceId: tracetranslator.UInt64ToByteTraceID(0, traceID),
+		SpanId:  tracetranslator.UInt64ToByteSpanID(spanID),
+	}
+}
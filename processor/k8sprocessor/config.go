@@ -0,0 +1,78 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Kubernetes Attributes processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Endpoint is the URL of the Kubernetes API, e.g. "http://localhost:8001"
+	// for "kubectl proxy". By default the in-cluster API is used, with the
+	// service account of the pod.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Extract specifies the pod metadata set as resource labels.
+	Extract ExtractConfig `mapstructure:"extract"`
+
+	// Filter restricts the pods watched.
+	Filter FilterConfig `mapstructure:"filter"`
+
+	// PodAssociation lists, in order, how the data is associated to its pod.
+	// By default it is associated by the IP of the client that sent it.
+	PodAssociation []PodAssociationConfig `mapstructure:"pod-association"`
+}
+
+// ExtractConfig specifies the pod metadata set as resource labels.
+type ExtractConfig struct {
+	// Metadata are the fields set, among "pod-name", "pod-uid", "namespace",
+	// "deployment" and "node". By default all of them are set.
+	Metadata []string `mapstructure:"metadata"`
+
+	// Labels and Annotations are the pod labels and annotations set.
+	Labels      []FieldExtractConfig `mapstructure:"labels"`
+	Annotations []FieldExtractConfig `mapstructure:"annotations"`
+}
+
+// FieldExtractConfig specifies a pod label or annotation set as a resource
+// label.
+type FieldExtractConfig struct {
+	// Key is the key of the pod label or annotation.
+	Key string `mapstructure:"key"`
+	// TagName is the resource label set, by default "k8s.pod.labels.<key>"
+	// for labels and "k8s.pod.annotations.<key>" for annotations.
+	TagName string `mapstructure:"tag-name"`
+}
+
+// FilterConfig restricts the pods watched.
+type FilterConfig struct {
+	// Node only watches the pods running on the node, e.g. when the service
+	// runs as an agent on every node. It is typically set from an environment
+	// variable, itself set from the spec.nodeName field of the pod.
+	Node string `mapstructure:"node"`
+}
+
+// PodAssociationConfig specifies a way to find the IP of the pod that sent
+// the data.
+type PodAssociationConfig struct {
+	// From is either "connection", the IP of the client that sent the data,
+	// or "resource-label", the value of a label of the resource of the data.
+	From string `mapstructure:"from"`
+	// Name is the resource label holding the pod IP, for "resource-label".
+	Name string `mapstructure:"name"`
+}
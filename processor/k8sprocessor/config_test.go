@@ -0,0 +1,78 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	require.NoError(t, err)
+
+	factory := &Factory{}
+	processors[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, len(cfg.Processors), 3)
+
+	p0 := cfg.Processors["k8s-attributes"]
+	assert.Equal(t, p0, factory.CreateDefaultConfig())
+
+	p1 := cfg.Processors["k8s-attributes/agent"]
+	assert.Equal(t, p1,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "k8s-attributes",
+				NameVal: "k8s-attributes/agent",
+			},
+			Extract: ExtractConfig{
+				Metadata: []string{"pod-name", "namespace", "deployment"},
+				Labels: []FieldExtractConfig{
+					{Key: "app"},
+					{Key: "version", TagName: "service.version"},
+				},
+				Annotations: []FieldExtractConfig{
+					{Key: "team"},
+				},
+			},
+			Filter: FilterConfig{Node: "node-1"},
+			PodAssociation: []PodAssociationConfig{
+				{From: "resource-label", Name: "k8s.pod.ip"},
+				{From: "connection"},
+			},
+		})
+
+	p2 := cfg.Processors["k8s-attributes/proxy"]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "k8s-attributes",
+				NameVal: "k8s-attributes/proxy",
+			},
+			Endpoint: "http://localhost:8001",
+		})
+}
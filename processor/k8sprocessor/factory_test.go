@@ -0,0 +1,90 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateProcessor(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), exportertest.NewNopTraceExporter(), cfg)
+	require.NotNil(t, tp)
+	assert.NoError(t, err, "cannot create trace processor")
+	tkp := tp.(*kubernetesProcessor)
+
+	mp, err := factory.CreateMetricsProcessor(zap.NewNop(), exportertest.NewNopMetricsExporter(), cfg)
+	require.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+	mkp := mp.(*kubernetesProcessor)
+
+	// The processors share the pod watcher, the watch ends once both are
+	// stopped.
+	require.True(t, tkp.pods == mkp.pods)
+	sw := tkp.pods.(*sharedWatcher)
+	tkp.Stop()
+	tkp.Stop()
+	select {
+	case <-sw.done:
+		t.Fatal("the watch ended while a processor still uses it")
+	case <-time.After(10 * time.Millisecond):
+	}
+	mkp.Stop()
+	select {
+	case <-sw.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch didn't end once the processors were stopped")
+	}
+}
+
+func TestCreateProcessorOutsideCluster(t *testing.T) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		t.Skip("running in a Kubernetes cluster")
+	}
+
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), exportertest.NewNopTraceExporter(), cfg)
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+
+	mp, err := factory.CreateMetricsProcessor(zap.NewNop(), exportertest.NewNopMetricsExporter(), cfg)
+	assert.Nil(t, mp)
+	assert.Error(t, err)
+}
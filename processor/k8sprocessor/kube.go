@@ -0,0 +1,270 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// serviceAccountDir holds the credentials of the pod's service account, a
+// variable to be replaced in tests.
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// watchRetryDelay is the time waited before listing the pods again when the
+// watch fails.
+const watchRetryDelay = time.Second
+
+// pod holds the fields of the Kubernetes pods used by the processor.
+type pod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		UID             string            `json:"uid"`
+		Labels          map[string]string `json:"labels"`
+		Annotations     map[string]string `json:"annotations"`
+		OwnerReferences []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		NodeName    string `json:"nodeName"`
+		HostNetwork bool   `json:"hostNetwork"`
+	} `json:"spec"`
+	Status struct {
+		PodIP string `json:"podIP"`
+	} `json:"status"`
+}
+
+type podList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []*pod `json:"items"`
+}
+
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// podStore returns the pods by IP.
+type podStore interface {
+	getPod(ip string) (*pod, bool)
+}
+
+// podWatcher keeps the pods up to date by listing them and then watching
+// their changes through the Kubernetes API, listing them again whenever the
+// watch ends.
+type podWatcher struct {
+	logger        *zap.Logger
+	client        *http.Client
+	endpoint      string
+	token         string
+	fieldSelector string
+
+	mu   sync.RWMutex
+	pods map[string]*pod
+}
+
+var _ podStore = (*podWatcher)(nil)
+
+// newPodWatcher creates a podWatcher for the API at the endpoint or, when it
+// is empty, for the in-cluster API.
+func newPodWatcher(logger *zap.Logger, endpoint string, filter FilterConfig) (*podWatcher, error) {
+	pw := &podWatcher{
+		logger:   logger,
+		client:   &http.Client{},
+		endpoint: endpoint,
+		pods:     make(map[string]*pod),
+	}
+	if filter.Node != "" {
+		pw.fieldSelector = "spec.nodeName=" + filter.Node
+	}
+	if endpoint == "" {
+		if err := pw.useInClusterConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return pw, nil
+}
+
+func (pw *podWatcher) useInClusterConfig() error {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return errors.New("not running in a Kubernetes cluster, the endpoint of the API must be set")
+	}
+	token, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "token"))
+	if err != nil {
+		return err
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return errors.New("invalid Kubernetes API certificate authority")
+	}
+
+	pw.endpoint = "https://" + net.JoinHostPort(host, port)
+	pw.token = string(token)
+	pw.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	return nil
+}
+
+// run keeps the pods up to date until the context is done.
+func (pw *podWatcher) run(ctx context.Context) {
+	for {
+		err := pw.listAndWatch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// The API ends the watches after a timeout, the other errors are
+		// worth reporting.
+		if err != io.EOF {
+			pw.logger.Warn("Failed to watch the Kubernetes pods", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// listAndWatch replaces the pods with the listed ones and applies their
+// changes until the watch ends, it always returns an error.
+func (pw *podWatcher) listAndWatch(ctx context.Context) error {
+	var list podList
+	if err := pw.get(ctx, pw.podsURL(false, ""), func(dec *json.Decoder) error {
+		return dec.Decode(&list)
+	}); err != nil {
+		return err
+	}
+	pods := make(map[string]*pod, len(list.Items))
+	for _, p := range list.Items {
+		if ip, ok := podIP(p); ok {
+			pods[ip] = p
+		}
+	}
+	pw.mu.Lock()
+	pw.pods = pods
+	pw.mu.Unlock()
+
+	return pw.get(ctx, pw.podsURL(true, list.Metadata.ResourceVersion), func(dec *json.Decoder) error {
+		for {
+			var event watchEvent
+			if err := dec.Decode(&event); err != nil {
+				return err
+			}
+			if err := pw.handleEvent(event); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func (pw *podWatcher) handleEvent(event watchEvent) error {
+	if event.Type == "ERROR" {
+		// The resource version is too old, the pods are listed again.
+		return fmt.Errorf("watch error: %s", event.Object)
+	}
+	p := &pod{}
+	if err := json.Unmarshal(event.Object, p); err != nil {
+		return err
+	}
+
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	// The pods changing IP, or losing it, are removed from their old IP.
+	for ip, old := range pw.pods {
+		if old.Metadata.UID == p.Metadata.UID {
+			delete(pw.pods, ip)
+		}
+	}
+	if ip, ok := podIP(p); ok && event.Type != "DELETED" {
+		pw.pods[ip] = p
+	}
+	return nil
+}
+
+func (pw *podWatcher) podsURL(watch bool, resourceVersion string) string {
+	query := url.Values{}
+	if pw.fieldSelector != "" {
+		query.Set("fieldSelector", pw.fieldSelector)
+	}
+	if watch {
+		query.Set("watch", "true")
+		query.Set("resourceVersion", resourceVersion)
+	}
+	u := pw.endpoint + "/api/v1/pods"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// get sends a GET request to the API and decodes the response body.
+func (pw *podWatcher) get(ctx context.Context, u string, decode func(*json.Decoder) error) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if pw.token != "" {
+		req.Header.Set("Authorization", "Bearer "+pw.token)
+	}
+	resp, err := pw.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %s", u, resp.Status)
+	}
+	return decode(json.NewDecoder(resp.Body))
+}
+
+func (pw *podWatcher) getPod(ip string) (*pod, bool) {
+	pw.mu.RLock()
+	defer pw.mu.RUnlock()
+	p, ok := pw.pods[ip]
+	return p, ok
+}
+
+// podIP returns the IP identifying the pod, the pods sharing the network of
+// their node can't be identified by IP.
+func podIP(p *pod) (string, bool) {
+	if p.Status.PodIP == "" || p.Spec.HostNetwork {
+		return "", false
+	}
+	return p.Status.PodIP, true
+}
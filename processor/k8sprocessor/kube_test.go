@@ -0,0 +1,172 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testPodList = `{
+		"metadata": {"resourceVersion": "10"},
+		"items": [
+			{"metadata": {"name": "a", "uid": "a"}, "status": {"podIP": "10.0.0.1"}},
+			{"metadata": {"name": "b", "uid": "b"}, "status": {"podIP": "10.0.0.2"}},
+			{"metadata": {"name": "host", "uid": "host"}, "spec": {"hostNetwork": true}, "status": {"podIP": "192.168.0.1"}},
+			{"metadata": {"name": "pending", "uid": "pending"}}
+		]
+	}`
+	testPodEvents = `
+		{"type": "MODIFIED", "object": {"metadata": {"name": "a", "uid": "a"}, "status": {"podIP": "10.0.0.3"}}}
+		{"type": "DELETED", "object": {"metadata": {"name": "b", "uid": "b"}, "status": {"podIP": "10.0.0.2"}}}
+		{"type": "ADDED", "object": {"metadata": {"name": "c", "uid": "c"}, "status": {"podIP": "10.0.0.4"}}}
+	`
+)
+
+// newTestAPIHandler returns the handler of a Kubernetes API listing the test
+// pods and sending the test events to the watches, and the received queries.
+func newTestAPIHandler(t *testing.T) (http.Handler, chan url.Values) {
+	queries := make(chan url.Values, 10)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/pods", r.URL.Path)
+		queries <- r.URL.Query()
+		if r.URL.Query().Get("watch") == "true" {
+			io.WriteString(w, testPodEvents)
+			return
+		}
+		io.WriteString(w, testPodList)
+	}), queries
+}
+
+func TestPodWatcher(t *testing.T) {
+	handler, queries := newTestAPIHandler(t)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	pw, err := newPodWatcher(zap.NewNop(), server.URL, FilterConfig{Node: "node-1"})
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, pw.listAndWatch(context.Background()))
+
+	list := <-queries
+	assert.Equal(t, "spec.nodeName=node-1", list.Get("fieldSelector"))
+	assert.Equal(t, "", list.Get("watch"))
+	watch := <-queries
+	assert.Equal(t, "spec.nodeName=node-1", watch.Get("fieldSelector"))
+	assert.Equal(t, "true", watch.Get("watch"))
+	assert.Equal(t, "10", watch.Get("resourceVersion"))
+
+	names := make(map[string]string)
+	for ip, p := range pw.pods {
+		names[ip] = p.Metadata.Name
+	}
+	assert.Equal(t, map[string]string{"10.0.0.3": "a", "10.0.0.4": "c"}, names)
+	p, ok := pw.getPod("10.0.0.4")
+	require.True(t, ok)
+	assert.Equal(t, "c", p.Metadata.Name)
+	_, ok = pw.getPod("10.0.0.1")
+	assert.False(t, ok)
+}
+
+func TestPodWatcherErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") == "true" {
+			io.WriteString(w, `{"type": "ERROR", "object": {"code": 410}}`)
+			return
+		}
+		io.WriteString(w, testPodList)
+	}))
+	defer server.Close()
+
+	pw, err := newPodWatcher(zap.NewNop(), server.URL, FilterConfig{})
+	require.NoError(t, err)
+	err = pw.listAndWatch(context.Background())
+	assert.Error(t, err)
+	assert.NotEqual(t, io.EOF, err)
+	// The listed pods are kept.
+	assert.Len(t, pw.pods, 2)
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	pw.endpoint = notFound.URL
+	assert.Error(t, pw.listAndWatch(context.Background()))
+}
+
+func TestPodWatcherInCluster(t *testing.T) {
+	handler, _ := newTestAPIHandler(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "k8sprocessor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(saved string) { serviceAccountDir = saved }(serviceAccountDir)
+	serviceAccountDir = dir
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("test-token"), 0600))
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0600))
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	defer os.Unsetenv("KUBERNETES_SERVICE_HOST")
+	defer os.Unsetenv("KUBERNETES_SERVICE_PORT")
+	os.Setenv("KUBERNETES_SERVICE_HOST", u.Hostname())
+	os.Setenv("KUBERNETES_SERVICE_PORT", u.Port())
+
+	pw, err := newPodWatcher(zap.NewNop(), "", FilterConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, pw.listAndWatch(context.Background()))
+	assert.Len(t, pw.pods, 2)
+}
+
+func TestPodWatcherRun(t *testing.T) {
+	handler, queries := newTestAPIHandler(t)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	pw, err := newPodWatcher(zap.NewNop(), server.URL, FilterConfig{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pw.run(ctx)
+		close(done)
+	}()
+
+	// The pods are listed again once the watch ends.
+	for i := 0; i < 3; i++ {
+		<-queries
+	}
+	cancel()
+	<-done
+}
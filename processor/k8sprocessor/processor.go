@@ -0,0 +1,349 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8sprocessor implements a processor setting the metadata of the
+// Kubernetes pods that sent the data as resource labels, e.g. when the data
+// reaches a gateway through an agent that doesn't know about Kubernetes.
+package k8sprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+// Resource label keys set from the pod metadata.
+const (
+	LabelPodName        = "k8s.pod.name"
+	LabelPodUID         = "k8s.pod.uid"
+	LabelNamespaceName  = "k8s.namespace.name"
+	LabelDeploymentName = "k8s.deployment.name"
+	LabelNodeName       = "k8s.node.name"
+)
+
+// metadataExtractors returns the metadata fields of the pods by their name in
+// the configuration.
+var metadataExtractors = map[string]struct {
+	label   string
+	extract func(*pod) string
+}{
+	"pod-name":   {LabelPodName, func(p *pod) string { return p.Metadata.Name }},
+	"pod-uid":    {LabelPodUID, func(p *pod) string { return p.Metadata.UID }},
+	"namespace":  {LabelNamespaceName, func(p *pod) string { return p.Metadata.Namespace }},
+	"deployment": {LabelDeploymentName, deploymentName},
+	"node":       {LabelNodeName, func(p *pod) string { return p.Spec.NodeName }},
+}
+
+// Pod associations.
+const (
+	associationConnection    = "connection"
+	associationResourceLabel = "resource-label"
+)
+
+type kubernetesProcessor struct {
+	pods         podStore
+	metadata     []string
+	labels       map[string]string
+	annotations  map[string]string
+	associations []PodAssociationConfig
+
+	nextTrace   consumer.TraceConsumer
+	nextMetrics consumer.MetricsConsumer
+
+	// release releases the pod watcher, if not nil.
+	release  func()
+	stopOnce sync.Once
+}
+
+var _ processor.TraceProcessor = (*kubernetesProcessor)(nil)
+var _ processor.MetricsProcessor = (*kubernetesProcessor)(nil)
+var _ processor.Stopper = (*kubernetesProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor setting the metadata
+// of the pods that sent the traces as resource labels.
+func NewTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	kp, err := newWatchingProcessor(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	kp.nextTrace = nextConsumer
+	return kp, nil
+}
+
+// NewMetricsProcessor returns a processor.MetricsProcessor setting the
+// metadata of the pods that sent the metrics as resource labels.
+func NewMetricsProcessor(logger *zap.Logger, nextConsumer consumer.MetricsConsumer, cfg Config) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	kp, err := newWatchingProcessor(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	kp.nextMetrics = nextConsumer
+	return kp, nil
+}
+
+// newWatchingProcessor creates a processor finding the pods with the shared
+// pod watcher of the configuration.
+func newWatchingProcessor(logger *zap.Logger, cfg Config) (*kubernetesProcessor, error) {
+	var release func()
+	kp, err := newProcessor(cfg, func() (podStore, error) {
+		pw, r, err := acquirePodWatcher(logger, cfg)
+		release = r
+		return pw, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	kp.release = release
+	return kp, nil
+}
+
+// watcherKey identifies the pod watchers that can be shared.
+type watcherKey struct {
+	endpoint string
+	filter   FilterConfig
+}
+
+// sharedWatcher is a pod watcher shared by the processors with the same
+// endpoint and filter, e.g. the trace and metrics processors created from the
+// same configuration, so that the pods are watched once.
+type sharedWatcher struct {
+	*podWatcher
+	refs   int
+	cancel context.CancelFunc
+	// done is closed once the watch ended.
+	done chan struct{}
+}
+
+var watchers = struct {
+	sync.Mutex
+	byKey map[watcherKey]*sharedWatcher
+}{byKey: make(map[watcherKey]*sharedWatcher)}
+
+// acquirePodWatcher returns the pod watcher of the configuration, started by
+// the first call, and the function releasing it. The watch ends once all the
+// callers released it.
+func acquirePodWatcher(logger *zap.Logger, cfg Config) (*sharedWatcher, func(), error) {
+	key := watcherKey{endpoint: cfg.Endpoint, filter: cfg.Filter}
+	watchers.Lock()
+	defer watchers.Unlock()
+
+	sw, ok := watchers.byKey[key]
+	if !ok {
+		pw, err := newPodWatcher(logger, cfg.Endpoint, cfg.Filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		sw = &sharedWatcher{podWatcher: pw, cancel: cancel, done: make(chan struct{})}
+		go func() {
+			pw.run(ctx)
+			close(sw.done)
+		}()
+		watchers.byKey[key] = sw
+	}
+	sw.refs++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			watchers.Lock()
+			defer watchers.Unlock()
+			sw.refs--
+			if sw.refs == 0 {
+				delete(watchers.byKey, key)
+				sw.cancel()
+			}
+		})
+	}
+	return sw, release, nil
+}
+
+// newProcessor validates the configuration and then creates the pod store.
+func newProcessor(cfg Config, newPodStore func() (podStore, error)) (*kubernetesProcessor, error) {
+	kp := &kubernetesProcessor{
+		metadata:     cfg.Extract.Metadata,
+		associations: cfg.PodAssociation,
+	}
+	if len(kp.metadata) == 0 {
+		kp.metadata = []string{"pod-name", "pod-uid", "namespace", "deployment", "node"}
+	}
+	for _, field := range kp.metadata {
+		if _, ok := metadataExtractors[field]; !ok {
+			return nil, fmt.Errorf("unknown pod metadata %q", field)
+		}
+	}
+
+	var err error
+	if kp.labels, err = fieldTags(cfg.Extract.Labels, "k8s.pod.labels."); err != nil {
+		return nil, err
+	}
+	if kp.annotations, err = fieldTags(cfg.Extract.Annotations, "k8s.pod.annotations."); err != nil {
+		return nil, err
+	}
+
+	if len(kp.associations) == 0 {
+		kp.associations = []PodAssociationConfig{{From: associationConnection}}
+	}
+	for _, a := range kp.associations {
+		switch {
+		case a.From == associationConnection:
+		case a.From == associationResourceLabel && a.Name != "":
+		case a.From == associationResourceLabel:
+			return nil, errors.New("the resource label of the pod association must be named")
+		default:
+			return nil, fmt.Errorf("unknown pod association %q", a.From)
+		}
+	}
+
+	if kp.pods, err = newPodStore(); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Stop releases the pod watcher, the pods are watched until all the
+// processors sharing it are stopped.
+func (kp *kubernetesProcessor) Stop() {
+	kp.stopOnce.Do(func() {
+		if kp.release != nil {
+			kp.release()
+		}
+	})
+}
+
+// fieldTags returns the resource labels set from the pod labels or
+// annotations, by key.
+func fieldTags(fields []FieldExtractConfig, defaultPrefix string) (map[string]string, error) {
+	tags := make(map[string]string, len(fields))
+	for _, field := range fields {
+		if field.Key == "" {
+			return nil, errors.New("the key of the pod labels and annotations to extract must be set")
+		}
+		tag := field.TagName
+		if tag == "" {
+			tag = defaultPrefix + field.Key
+		}
+		tags[field.Key] = tag
+	}
+	return tags, nil
+}
+
+func (kp *kubernetesProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if p, ok := kp.findPod(ctx, td.Resource); ok {
+		td.Resource = kp.setLabels(td.Resource, p)
+		for _, span := range td.Spans {
+			if span != nil && span.Resource != nil {
+				span.Resource = kp.setLabels(span.Resource, p)
+			}
+		}
+	}
+	return kp.nextTrace.ConsumeTraceData(ctx, td)
+}
+
+func (kp *kubernetesProcessor) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if p, ok := kp.findPod(ctx, md.Resource); ok {
+		md.Resource = kp.setLabels(md.Resource, p)
+		for _, metric := range md.Metrics {
+			if metric != nil && metric.Resource != nil {
+				metric.Resource = kp.setLabels(metric.Resource, p)
+			}
+		}
+	}
+	return kp.nextMetrics.ConsumeMetricsData(ctx, md)
+}
+
+// findPod returns the pod of the first association that finds one.
+func (kp *kubernetesProcessor) findPod(ctx context.Context, resource *resourcepb.Resource) (*pod, bool) {
+	for _, a := range kp.associations {
+		var ip string
+		switch a.From {
+		case associationConnection:
+			if c, ok := client.FromContext(ctx); ok {
+				ip = c.IP
+			}
+		case associationResourceLabel:
+			ip = resource.GetLabels()[a.Name]
+		}
+		if ip == "" {
+			continue
+		}
+		if p, ok := kp.pods.getPod(ip); ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// setLabels returns a copy of the resource with the pod metadata, the
+// resource itself may be shared with other pipelines and is not modified.
+// The labels the resource already has are kept.
+func (kp *kubernetesProcessor) setLabels(resource *resourcepb.Resource, p *pod) *resourcepb.Resource {
+	result := &resourcepb.Resource{
+		Type:   resource.GetType(),
+		Labels: make(map[string]string, len(resource.GetLabels())+len(kp.metadata)),
+	}
+	for k, v := range resource.GetLabels() {
+		result.Labels[k] = v
+	}
+	if result.Type == "" {
+		result.Type = "k8s"
+	}
+
+	set := func(label, value string) {
+		if _, ok := result.Labels[label]; !ok && value != "" {
+			result.Labels[label] = value
+		}
+	}
+	for _, field := range kp.metadata {
+		extractor := metadataExtractors[field]
+		set(extractor.label, extractor.extract(p))
+	}
+	for key, tag := range kp.labels {
+		set(tag, p.Metadata.Labels[key])
+	}
+	for key, tag := range kp.annotations {
+		set(tag, p.Metadata.Annotations[key])
+	}
+	return result
+}
+
+// deploymentName returns the name of the deployment of the pod, found from the
+// name of the replica set owning it, the deployment name followed by a hash.
+func deploymentName(p *pod) string {
+	for _, owner := range p.Metadata.OwnerReferences {
+		if owner.Kind != "ReplicaSet" {
+			continue
+		}
+		if i := strings.LastIndex(owner.Name, "-"); i > 0 {
+			return owner.Name[:i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sprocessor
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+type fakePodStore map[string]*pod
+
+func (s fakePodStore) getPod(ip string) (*pod, bool) {
+	p, ok := s[ip]
+	return p, ok
+}
+
+func newTestPod(name, ip string) *pod {
+	p := &pod{}
+	p.Metadata.Name = name
+	p.Metadata.Namespace = "shop"
+	p.Metadata.UID = name + "-uid"
+	p.Metadata.Labels = map[string]string{"app": "checkout", "version": "1.2"}
+	p.Metadata.Annotations = map[string]string{"team": "payments"}
+	p.Metadata.OwnerReferences = append(p.Metadata.OwnerReferences, struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	}{Kind: "ReplicaSet", Name: "checkout-5d8f7c9b4"})
+	p.Spec.NodeName = "node-1"
+	p.Status.PodIP = ip
+	return p
+}
+
+func newTestProcessor(t *testing.T, cfg Config, pods fakePodStore) *kubernetesProcessor {
+	kp, err := newProcessor(cfg, func() (podStore, error) { return pods, nil })
+	require.NoError(t, err)
+	return kp
+}
+
+func TestNewProcessorInvalidConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{Extract: ExtractConfig{Metadata: []string{"pod-name", "cluster"}}},
+		{Extract: ExtractConfig{Labels: []FieldExtractConfig{{TagName: "app"}}}},
+		{Extract: ExtractConfig{Annotations: []FieldExtractConfig{{TagName: "team"}}}},
+		{PodAssociation: []PodAssociationConfig{{From: "hostname"}}},
+		{PodAssociation: []PodAssociationConfig{{From: "resource-label"}}},
+	} {
+		_, err := newProcessor(cfg, func() (podStore, error) { return fakePodStore{}, nil })
+		assert.Error(t, err)
+	}
+}
+
+func TestProcessorTracesFromConnection(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	kp := newTestProcessor(t, Config{}, fakePodStore{"10.0.0.1": newTestPod("checkout-5d8f7c9b4-x2x7z", "10.0.0.1")})
+	kp.nextTrace = sink
+
+	spanResource := &resourcepb.Resource{Labels: map[string]string{LabelPodName: "other"}}
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.1"})
+	require.NoError(t, kp.ConsumeTraceData(ctx, consumerdata.TraceData{
+		Spans: []*tracepb.Span{{Resource: spanResource}, {}},
+	}))
+	// Data from an unknown client is left untouched.
+	ctx = client.NewContext(context.Background(), &client.Client{IP: "10.0.0.2"})
+	require.NoError(t, kp.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	require.NoError(t, kp.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+
+	got := sink.AllTraces()
+	require.Len(t, got, 3)
+	want := map[string]string{
+		LabelPodName:        "checkout-5d8f7c9b4-x2x7z",
+		LabelPodUID:         "checkout-5d8f7c9b4-x2x7z-uid",
+		LabelNamespaceName:  "shop",
+		LabelDeploymentName: "checkout",
+		LabelNodeName:       "node-1",
+	}
+	assert.Equal(t, &resourcepb.Resource{Type: "k8s", Labels: want}, got[0].Resource)
+	want[LabelPodName] = "other"
+	assert.Equal(t, &resourcepb.Resource{Type: "k8s", Labels: want}, got[0].Spans[0].Resource)
+	assert.Nil(t, got[0].Spans[1].Resource)
+	assert.Equal(t, map[string]string{LabelPodName: "other"}, spanResource.Labels)
+	assert.Nil(t, got[1].Resource)
+	assert.Nil(t, got[2].Resource)
+}
+
+func TestProcessorMetricsFromResourceLabel(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	kp := newTestProcessor(t, Config{
+		Extract: ExtractConfig{
+			Metadata:    []string{"pod-name"},
+			Labels:      []FieldExtractConfig{{Key: "app"}, {Key: "version", TagName: "service.version"}},
+			Annotations: []FieldExtractConfig{{Key: "team"}, {Key: "missing"}},
+		},
+		PodAssociation: []PodAssociationConfig{
+			{From: "resource-label", Name: "k8s.pod.ip"},
+			{From: "connection"},
+		},
+	}, fakePodStore{
+		"10.0.0.1": newTestPod("from-label", "10.0.0.1"),
+		"10.0.0.2": newTestPod("from-connection", "10.0.0.2"),
+	})
+	kp.nextMetrics = sink
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.2"})
+	for _, ip := range []string{"10.0.0.1", "10.0.0.3"} {
+		require.NoError(t, kp.ConsumeMetricsData(ctx, consumerdata.MetricsData{
+			Resource: &resourcepb.Resource{Type: "container", Labels: map[string]string{"k8s.pod.ip": ip}},
+			Metrics:  []*metricspb.Metric{{}},
+		}))
+	}
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 2)
+	assert.Equal(t, &resourcepb.Resource{
+		Type: "container",
+		Labels: map[string]string{
+			"k8s.pod.ip":               "10.0.0.1",
+			LabelPodName:               "from-label",
+			"k8s.pod.labels.app":       "checkout",
+			"service.version":          "1.2",
+			"k8s.pod.annotations.team": "payments",
+		},
+	}, got[0].Resource)
+	// The IP of the label is unknown, the connection is used.
+	assert.Equal(t, "from-connection", got[1].Resource.Labels[LabelPodName])
+}
+
+func TestDeploymentName(t *testing.T) {
+	p := newTestPod("checkout-5d8f7c9b4-x2x7z", "10.0.0.1")
+	assert.Equal(t, "checkout", deploymentName(p))
+
+	p.Metadata.OwnerReferences[0].Kind = "StatefulSet"
+	assert.Equal(t, "", deploymentName(p))
+}
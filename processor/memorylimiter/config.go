@@ -0,0 +1,41 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimiter
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for memory limiter processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// CheckInterval is the time between two measurements of the memory usage.
+	CheckInterval time.Duration `mapstructure:"check-interval,omitempty"`
+
+	// HardLimitMiB is the maximum heap usage, in MiB, above which the garbage
+	// collector is forced to run. It is required.
+	HardLimitMiB uint64 `mapstructure:"hard-limit-mib,omitempty"`
+
+	// SoftLimitMiB is the heap usage, in MiB, above which the data is refused.
+	// The default is 80% of HardLimitMiB.
+	SoftLimitMiB uint64 `mapstructure:"soft-limit-mib,omitempty"`
+
+	// BallastSizeMiB must match the size of the memory ballast of the
+	// service, the ballast is subtracted from the heap usage.
+	BallastSizeMiB uint64 `mapstructure:"ballast-size-mib,omitempty"`
+}
@@ -0,0 +1,174 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memorylimiter implements a processor refusing data when the memory
+// usage of the service is too high, to prevent it from running out of memory
+// under traffic spikes.
+package memorylimiter
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+const mib = 1024 * 1024
+
+// ErrDataRefused is returned by the processor, wrapped as a
+// consumererror.Overloaded error, when the memory usage is above the soft
+// limit. It is temporary: the data can be sent again later, e.g. by a
+// queued-retry processor placed before the memory limiter, or by the clients
+// of the receivers asked to slow down.
+var ErrDataRefused = errors.New("data refused due to high memory usage")
+
+type memoryLimiter struct {
+	traceConsumer   consumer.TraceConsumer
+	metricsConsumer consumer.MetricsConsumer
+
+	// The limits and the ballast are in bytes.
+	softLimit uint64
+	hardLimit uint64
+	ballast   uint64
+
+	// refusing is 1 while the memory usage is above the soft limit.
+	refusing int32
+	// errRefused is ErrDataRefused suggesting to retry after the memory usage
+	// is checked again.
+	errRefused error
+
+	readMemStats func(*runtime.MemStats)
+	logger       *zap.Logger
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+var _ processor.TraceProcessor = (*memoryLimiter)(nil)
+var _ processor.MetricsProcessor = (*memoryLimiter)(nil)
+var _ processor.Stopper = (*memoryLimiter)(nil)
+
+func newMemoryLimiter(logger *zap.Logger, cfg Config) (*memoryLimiter, error) {
+	if cfg.CheckInterval <= 0 {
+		return nil, errors.New("check-interval must be greater than zero")
+	}
+	if cfg.HardLimitMiB == 0 {
+		return nil, errors.New("hard-limit-mib must be set")
+	}
+	softLimitMiB := cfg.SoftLimitMiB
+	if softLimitMiB == 0 {
+		softLimitMiB = cfg.HardLimitMiB * 80 / 100
+	}
+	if softLimitMiB > cfg.HardLimitMiB {
+		return nil, errors.New("soft-limit-mib must not be greater than hard-limit-mib")
+	}
+
+	ml := &memoryLimiter{
+		softLimit:    softLimitMiB * mib,
+		hardLimit:    cfg.HardLimitMiB * mib,
+		ballast:      cfg.BallastSizeMiB * mib,
+		errRefused:   consumererror.Overloaded(ErrDataRefused, cfg.CheckInterval),
+		readMemStats: runtime.ReadMemStats,
+		logger:       logger,
+		stopCh:       make(chan struct{}),
+	}
+	go ml.checkMemLimitsEvery(cfg.CheckInterval)
+	return ml, nil
+}
+
+func (ml *memoryLimiter) checkMemLimitsEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ml.checkMemLimits()
+		case <-ml.stopCh:
+			return
+		}
+	}
+}
+
+// Stop halts the periodic check of the memory usage.
+func (ml *memoryLimiter) Stop() {
+	ml.stopOnce.Do(func() {
+		close(ml.stopCh)
+	})
+}
+
+func (ml *memoryLimiter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if ml.refusingData() {
+		return ml.errRefused
+	}
+	return ml.traceConsumer.ConsumeTraceData(ctx, td)
+}
+
+func (ml *memoryLimiter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if ml.refusingData() {
+		return ml.errRefused
+	}
+	return ml.metricsConsumer.ConsumeMetricsData(ctx, md)
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, it only refuses or forwards the data.
+func (ml *memoryLimiter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+func (ml *memoryLimiter) refusingData() bool {
+	return atomic.LoadInt32(&ml.refusing) != 0
+}
+
+// checkMemLimits forces a garbage collection if the memory usage is above the
+// hard limit, then starts or stops refusing data depending on the soft limit.
+func (ml *memoryLimiter) checkMemLimits() {
+	used := ml.memUsage()
+	if used > ml.hardLimit {
+		ml.logger.Warn("Memory usage is above the hard limit, forcing a GC.",
+			zap.Uint64("cur_mem_mib", used/mib))
+		runtime.GC()
+		used = ml.memUsage()
+	}
+
+	if used > ml.softLimit {
+		if atomic.SwapInt32(&ml.refusing, 1) == 0 {
+			ml.logger.Warn("Memory usage is above the soft limit, refusing data.",
+				zap.Uint64("cur_mem_mib", used/mib))
+		}
+		return
+	}
+	if atomic.SwapInt32(&ml.refusing, 0) != 0 {
+		ml.logger.Info("Memory usage is back under the soft limit, resuming normal operation.",
+			zap.Uint64("cur_mem_mib", used/mib))
+	}
+}
+
+// memUsage returns the bytes allocated on the heap, excluding the ballast.
+func (ml *memoryLimiter) memUsage() uint64 {
+	var ms runtime.MemStats
+	ml.readMemStats(&ms)
+	if ms.Alloc < ml.ballast {
+		return 0
+	}
+	return ms.Alloc - ml.ballast
+}
@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimiter
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestNewMemoryLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+		soft    uint64
+	}{
+		{
+			name: "default_soft_limit",
+			cfg:  Config{CheckInterval: time.Second, HardLimitMiB: 100},
+			soft: 80 * mib,
+		},
+		{
+			name: "soft_limit",
+			cfg:  Config{CheckInterval: time.Second, HardLimitMiB: 100, SoftLimitMiB: 90},
+			soft: 90 * mib,
+		},
+		{
+			name:    "no_hard_limit",
+			cfg:     Config{CheckInterval: time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "soft_above_hard_limit",
+			cfg:     Config{CheckInterval: time.Second, HardLimitMiB: 100, SoftLimitMiB: 101},
+			wantErr: true,
+		},
+		{
+			name:    "no_check_interval",
+			cfg:     Config{HardLimitMiB: 100},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml, err := newMemoryLimiter(zap.NewNop(), tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.soft, ml.softLimit)
+			assert.Equal(t, tt.cfg.HardLimitMiB*mib, ml.hardLimit)
+		})
+	}
+}
+
+func TestCheckMemLimits(t *testing.T) {
+	traceSink := new(exportertest.SinkTraceExporter)
+	metricsSink := new(exportertest.SinkMetricsExporter)
+	ml, err := newMemoryLimiter(zap.NewNop(), Config{
+		CheckInterval:  time.Hour,
+		HardLimitMiB:   100,
+		SoftLimitMiB:   50,
+		BallastSizeMiB: 1000,
+	})
+	require.NoError(t, err)
+	ml.traceConsumer = traceSink
+	ml.metricsConsumer = metricsSink
+
+	var alloc uint64
+	ml.readMemStats = func(ms *runtime.MemStats) {
+		ms.Alloc = alloc
+	}
+	ctx := context.Background()
+
+	// The ballast isn't accounted for.
+	alloc = 1040 * mib
+	ml.checkMemLimits()
+	assert.NoError(t, ml.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	assert.NoError(t, ml.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+
+	// Above the soft limit, the clients are asked to retry after the next
+	// check.
+	alloc = 1060 * mib
+	ml.checkMemLimits()
+	err = ml.ConsumeTraceData(ctx, consumerdata.TraceData{})
+	assert.Equal(t, ErrDataRefused.Error(), err.Error())
+	retryAfter, ok := consumererror.IsOverloaded(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, retryAfter)
+	assert.Equal(t, err, ml.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+
+	// Above the hard limit, the data is still refused after the GC.
+	alloc = 1200 * mib
+	ml.checkMemLimits()
+	assert.Equal(t, err, ml.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+
+	// Back under the soft limit.
+	alloc = 1010 * mib
+	ml.checkMemLimits()
+	assert.NoError(t, ml.ConsumeTraceData(ctx, consumerdata.TraceData{}))
+	assert.NoError(t, ml.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+
+	assert.Equal(t, 2, len(traceSink.AllTraces()))
+	assert.Equal(t, 2, len(metricsSink.AllMetrics()))
+}
@@ -17,6 +17,12 @@ package multiconsumer
 import (
 	"context"
 
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/oterr"
@@ -62,3 +68,144 @@ func (tcs traceConsumers) ConsumeTraceData(ctx context.Context, td consumerdata.
 	}
 	return oterr.CombineErrors(errs)
 }
+
+// NewMetricsProcessorCloning wraps multiple metrics consumers in a single one
+// that sends each consumer its own copy of the data, so that the consumers
+// modifying it don't affect each other. The last consumer gets the original
+// data.
+func NewMetricsProcessorCloning(mcs []consumer.MetricsConsumer) processor.MetricsProcessor {
+	return NewMetricsProcessorSharing(nil, mcs)
+}
+
+// NewMetricsProcessorSharing wraps multiple metrics consumers in a single one
+// that sends the original data to the readOnly consumers, which must not
+// modify it, and its own copy of the data to each of the mutating consumers.
+// The last mutating consumer gets the original data if there are no readOnly
+// consumers.
+func NewMetricsProcessorSharing(readOnly, mutating []consumer.MetricsConsumer) processor.MetricsProcessor {
+	return &sharingMetricsConsumers{readOnly: readOnly, mutating: mutating}
+}
+
+type sharingMetricsConsumers struct {
+	readOnly []consumer.MetricsConsumer
+	mutating []consumer.MetricsConsumer
+}
+
+var _ processor.MetricsProcessor = (*sharingMetricsConsumers)(nil)
+
+// ConsumeMetricsData exports the MetricsData, or a copy of it, to all
+// consumers wrapped by the current one.
+func (mcs *sharingMetricsConsumers) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	var errs []error
+	for i, mdp := range mcs.mutating {
+		data := md
+		if len(mcs.readOnly) > 0 || i < len(mcs.mutating)-1 {
+			data = CloneMetricsData(md)
+		}
+		if err := mdp.ConsumeMetricsData(ctx, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, mdp := range mcs.readOnly {
+		if err := mdp.ConsumeMetricsData(ctx, md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return oterr.CombineErrors(errs)
+}
+
+// NewTraceProcessorCloning wraps multiple trace consumers in a single one
+// that sends each consumer its own copy of the data, so that the consumers
+// modifying it don't affect each other. The last consumer gets the original
+// data.
+func NewTraceProcessorCloning(tcs []consumer.TraceConsumer) processor.TraceProcessor {
+	return NewTraceProcessorSharing(nil, tcs)
+}
+
+// NewTraceProcessorSharing wraps multiple trace consumers in a single one that
+// sends the original data to the readOnly consumers, which must not modify
+// it, and its own copy of the data to each of the mutating consumers. The
+// last mutating consumer gets the original data if there are no readOnly
+// consumers.
+func NewTraceProcessorSharing(readOnly, mutating []consumer.TraceConsumer) processor.TraceProcessor {
+	return &sharingTraceConsumers{readOnly: readOnly, mutating: mutating}
+}
+
+type sharingTraceConsumers struct {
+	readOnly []consumer.TraceConsumer
+	mutating []consumer.TraceConsumer
+}
+
+var _ processor.TraceProcessor = (*sharingTraceConsumers)(nil)
+
+// ConsumeTraceData exports the span data, or a copy of it, to all trace
+// consumers wrapped by the current one.
+func (tcs *sharingTraceConsumers) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	var errs []error
+	for i, tdp := range tcs.mutating {
+		data := td
+		if len(tcs.readOnly) > 0 || i < len(tcs.mutating)-1 {
+			data = CloneTraceData(td)
+		}
+		if err := tdp.ConsumeTraceData(ctx, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, tdp := range tcs.readOnly {
+		if err := tdp.ConsumeTraceData(ctx, td); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return oterr.CombineErrors(errs)
+}
+
+// CloneMetricsData returns a deep copy of md, the consumers modifying it don't
+// modify md.
+func CloneMetricsData(md consumerdata.MetricsData) consumerdata.MetricsData {
+	clone := consumerdata.MetricsData{
+		Node:     cloneNode(md.Node),
+		Resource: cloneResource(md.Resource),
+	}
+	if md.Metrics != nil {
+		clone.Metrics = make([]*metricspb.Metric, len(md.Metrics))
+		for i, metric := range md.Metrics {
+			if metric != nil {
+				clone.Metrics[i] = proto.Clone(metric).(*metricspb.Metric)
+			}
+		}
+	}
+	return clone
+}
+
+// CloneTraceData returns a deep copy of td, the consumers modifying it don't
+// modify td.
+func CloneTraceData(td consumerdata.TraceData) consumerdata.TraceData {
+	clone := consumerdata.TraceData{
+		Node:         cloneNode(td.Node),
+		Resource:     cloneResource(td.Resource),
+		SourceFormat: td.SourceFormat,
+	}
+	if td.Spans != nil {
+		clone.Spans = make([]*tracepb.Span, len(td.Spans))
+		for i, span := range td.Spans {
+			if span != nil {
+				clone.Spans[i] = proto.Clone(span).(*tracepb.Span)
+			}
+		}
+	}
+	return clone
+}
+
+func cloneNode(node *commonpb.Node) *commonpb.Node {
+	if node == nil {
+		return nil
+	}
+	return proto.Clone(node).(*commonpb.Node)
+}
+
+func cloneResource(resource *resourcepb.Resource) *resourcepb.Resource {
+	if resource == nil {
+		return nil
+	}
+	return proto.Clone(resource).(*resourcepb.Resource)
+}
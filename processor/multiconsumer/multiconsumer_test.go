@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
@@ -150,6 +151,110 @@ func TestMetricsProcessorWhenOneErrors(t *testing.T) {
 	}
 }
 
+func TestTraceProcessorCloning(t *testing.T) {
+	processors := []consumer.TraceConsumer{&mutatingTraceConsumer{}, &mutatingTraceConsumer{}}
+	tdp := NewTraceProcessorCloning(processors)
+	td := consumerdata.TraceData{
+		Resource: &resourcepb.Resource{Labels: map[string]string{"service": "test"}},
+		Spans:    []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}}, nil},
+	}
+	if err := tdp.ConsumeTraceData(context.Background(), td); err != nil {
+		t.Fatalf("Wanted nil got error %v", err)
+	}
+
+	// Each consumer saw the data unmodified by the other, the last one got
+	// the original data.
+	for i, p := range processors {
+		m := p.(*mutatingTraceConsumer)
+		if m.SeenName != "span" || m.SeenLabels != 1 {
+			t.Errorf("Processor %d saw the data modified by another: %q, %d labels", i, m.SeenName, m.SeenLabels)
+		}
+	}
+	if td.Spans[0].Name.Value != "modified" {
+		t.Errorf("Wanted the last processor to get the original data")
+	}
+}
+
+func TestMetricsProcessorCloning(t *testing.T) {
+	processors := []consumer.MetricsConsumer{&mutatingMetricsConsumer{}, &mutatingMetricsConsumer{}}
+	mdp := NewMetricsProcessorCloning(processors)
+	md := consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{Labels: map[string]string{"service": "test"}},
+		Metrics: []*metricspb.Metric{
+			{MetricDescriptor: &metricspb.MetricDescriptor{Name: "metric"}}, nil,
+		},
+	}
+	if err := mdp.ConsumeMetricsData(context.Background(), md); err != nil {
+		t.Fatalf("Wanted nil got error %v", err)
+	}
+
+	for i, p := range processors {
+		m := p.(*mutatingMetricsConsumer)
+		if m.SeenName != "metric" || m.SeenLabels != 1 {
+			t.Errorf("Processor %d saw the data modified by another: %q, %d labels", i, m.SeenName, m.SeenLabels)
+		}
+	}
+	if md.Metrics[0].MetricDescriptor.Name != "modified" {
+		t.Errorf("Wanted the last processor to get the original data")
+	}
+}
+
+func TestTraceProcessorSharing(t *testing.T) {
+	readOnly := &recordingTraceConsumer{}
+	mutating := []consumer.TraceConsumer{&mutatingTraceConsumer{}, &mutatingTraceConsumer{}}
+	tdp := NewTraceProcessorSharing([]consumer.TraceConsumer{readOnly}, mutating)
+	td := consumerdata.TraceData{
+		Resource: &resourcepb.Resource{Labels: map[string]string{"service": "test"}},
+		Spans:    []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}}},
+	}
+	if err := tdp.ConsumeTraceData(context.Background(), td); err != nil {
+		t.Fatalf("Wanted nil got error %v", err)
+	}
+
+	// The mutating consumers got their own copy, the read-only one got the
+	// original data, unmodified.
+	for i, p := range mutating {
+		m := p.(*mutatingTraceConsumer)
+		if m.SeenName != "span" || m.SeenLabels != 1 {
+			t.Errorf("Processor %d saw the data modified by another: %q, %d labels", i, m.SeenName, m.SeenLabels)
+		}
+	}
+	if len(readOnly.Seen.Spans) != 1 || readOnly.Seen.Spans[0] != td.Spans[0] {
+		t.Errorf("Wanted the read-only processor to get the original data")
+	}
+	if td.Spans[0].Name.Value != "span" || len(td.Resource.Labels) != 1 {
+		t.Errorf("Wanted the original data to be unmodified")
+	}
+}
+
+func TestMetricsProcessorSharing(t *testing.T) {
+	readOnly := &recordingMetricsConsumer{}
+	mutating := []consumer.MetricsConsumer{&mutatingMetricsConsumer{}, &mutatingMetricsConsumer{}}
+	mdp := NewMetricsProcessorSharing([]consumer.MetricsConsumer{readOnly}, mutating)
+	md := consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{Labels: map[string]string{"service": "test"}},
+		Metrics: []*metricspb.Metric{
+			{MetricDescriptor: &metricspb.MetricDescriptor{Name: "metric"}},
+		},
+	}
+	if err := mdp.ConsumeMetricsData(context.Background(), md); err != nil {
+		t.Fatalf("Wanted nil got error %v", err)
+	}
+
+	for i, p := range mutating {
+		m := p.(*mutatingMetricsConsumer)
+		if m.SeenName != "metric" || m.SeenLabels != 1 {
+			t.Errorf("Processor %d saw the data modified by another: %q, %d labels", i, m.SeenName, m.SeenLabels)
+		}
+	}
+	if len(readOnly.Seen.Metrics) != 1 || readOnly.Seen.Metrics[0] != md.Metrics[0] {
+		t.Errorf("Wanted the read-only processor to get the original data")
+	}
+	if md.Metrics[0].MetricDescriptor.Name != "metric" || len(md.Resource.Labels) != 1 {
+		t.Errorf("Wanted the original data to be unmodified")
+	}
+}
+
 type mockTraceConsumer struct {
 	TotalSpans int
 	MustFail   bool
@@ -181,3 +286,53 @@ func (p *mockMetricsConsumer) ConsumeMetricsData(ctx context.Context, td consume
 
 	return nil
 }
+
+// recordingTraceConsumer records the data it receives.
+type recordingTraceConsumer struct {
+	Seen consumerdata.TraceData
+}
+
+func (p *recordingTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	p.Seen = td
+	return nil
+}
+
+// recordingMetricsConsumer records the data it receives.
+type recordingMetricsConsumer struct {
+	Seen consumerdata.MetricsData
+}
+
+func (p *recordingMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	p.Seen = md
+	return nil
+}
+
+// mutatingTraceConsumer records the name of the first span and the number of
+// resource labels it receives, then modifies them.
+type mutatingTraceConsumer struct {
+	SeenName   string
+	SeenLabels int
+}
+
+func (p *mutatingTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	p.SeenName = td.Spans[0].Name.Value
+	p.SeenLabels = len(td.Resource.Labels)
+	td.Spans[0].Name.Value = "modified"
+	td.Resource.Labels["modified"] = "true"
+	return nil
+}
+
+// mutatingMetricsConsumer records the name of the first metric and the number
+// of resource labels it receives, then modifies them.
+type mutatingMetricsConsumer struct {
+	SeenName   string
+	SeenLabels int
+}
+
+func (p *mutatingMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	p.SeenName = md.Metrics[0].MetricDescriptor.Name
+	p.SeenLabels = len(md.Resource.Labels)
+	md.Metrics[0].MetricDescriptor.Name = "modified"
+	md.Resource.Labels["modified"] = "true"
+	return nil
+}
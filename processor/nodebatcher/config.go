@@ -30,8 +30,18 @@ type Config struct {
 	// SendBatchSize is the size of a batch which after hit, will trigger it to be sent.
 	SendBatchSize *int `mapstructure:"send-batch-size,omitempty"`
 
+	// SendBatchMaxBytes is the estimated size in bytes of a batch which after hit,
+	// will trigger it to be sent. Zero disables it. It only applies to spans.
+	SendBatchMaxBytes int `mapstructure:"send-batch-max-bytes,omitempty"`
+
+	// SizeEncoding is the encoding used to estimate the size of the spans for
+	// SendBatchMaxBytes, "protobuf" (default) or "json". It should match the
+	// encoding used by the exporters of the pipeline.
+	SizeEncoding string `mapstructure:"size-encoding,omitempty"`
+
 	// NumTickers sets the number of tickers to use to divide the work of looping
-	// over batch buckets. This is an advanced configuration option.
+	// over batch buckets. This is an advanced configuration option, metrics
+	// always use a single ticker.
 	NumTickers int `mapstructure:"num-tickers,omitempty"`
 
 	// TickTime sets time interval at which the tickers tick. This is an advanced
@@ -40,6 +50,7 @@ type Config struct {
 
 	// RemoveAfterTicks is the number of ticks that must pass without a span arriving
 	// from a node after which the batcher for that node will be deleted. This is an
-	// advanced configuration option.
+	// advanced configuration option. The batches of metrics are deleted as soon as
+	// they are empty at a tick.
 	RemoveAfterTicks *int `mapstructure:"remove-after-ticks,omitempty"`
 }
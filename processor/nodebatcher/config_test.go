@@ -55,10 +55,14 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "batch",
 				NameVal: "batch/2",
 			},
-			Timeout:          &timeout,
-			NumTickers:       10,
-			RemoveAfterTicks: &removeAfterTicks,
-			SendBatchSize:    &sendBatchSize,
-			TickTime:         &tickTime,
+			Timeout:           &timeout,
+			NumTickers:        10,
+			RemoveAfterTicks:  &removeAfterTicks,
+			SendBatchSize:     &sendBatchSize,
+			SendBatchMaxBytes: 4194304,
+			SizeEncoding:      SizeEncodingJSON,
+			TickTime:          &tickTime,
 		})
+
+	assert.Equal(t, []string{"batch"}, cfg.Pipelines["metrics"].Processors)
 }
@@ -17,7 +17,6 @@ package nodebatcher
 import (
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/processor"
@@ -89,6 +88,15 @@ func (f *Factory) CreateTraceProcessor(
 			batchingOptions, WithRemoveAfterTicks(*cfg.RemoveAfterTicks),
 		)
 	}
+	if cfg.SendBatchMaxBytes > 0 {
+		sizer, err := NewSpanSizer(cfg.SizeEncoding)
+		if err != nil {
+			return nil, err
+		}
+		batchingOptions = append(
+			batchingOptions, WithSendBatchMaxBytes(cfg.SendBatchMaxBytes), WithSpanSizer(sizer),
+		)
+	}
 
 	return NewBatcher(cfg.NameVal, logger, nextConsumer, batchingOptions...), nil
 }
@@ -97,7 +105,24 @@ func (f *Factory) CreateTraceProcessor(
 func (f *Factory) CreateMetricsProcessor(
 	logger *zap.Logger,
 	nextConsumer consumer.MetricsConsumer,
-	cfg configmodels.Processor,
+	c configmodels.Processor,
 ) (processor.MetricsProcessor, error) {
-	return nil, configerror.ErrDataTypeIsNotSupported
+	cfg := c.(*Config)
+
+	var batchingOptions []Option
+	if cfg.Timeout != nil {
+		batchingOptions = append(batchingOptions, WithTimeout(*cfg.Timeout))
+	}
+	if cfg.TickTime != nil {
+		batchingOptions = append(
+			batchingOptions, WithTickTime(*cfg.TickTime),
+		)
+	}
+	if cfg.SendBatchSize != nil {
+		batchingOptions = append(
+			batchingOptions, WithSendBatchSize(*cfg.SendBatchSize),
+		)
+	}
+
+	return NewMetricsBatcher(cfg.NameVal, logger, nextConsumer, batchingOptions...), nil
 }
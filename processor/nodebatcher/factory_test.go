@@ -38,6 +38,17 @@ func TestCreateProcessor(t *testing.T) {
 	assert.NoError(t, err, "cannot create trace processor")
 
 	mp, err := factory.CreateMetricsProcessor(zap.NewNop(), nil, cfg)
-	assert.Nil(t, mp)
-	assert.Error(t, err, "should not be able to create metric processor")
+	assert.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+}
+
+func TestCreateProcessorInvalidSizeEncoding(t *testing.T) {
+	factory := &Factory{}
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SendBatchMaxBytes = 1024
+	cfg.SizeEncoding = "unknown"
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), nil, cfg)
+	assert.Nil(t, tp)
+	assert.Error(t, err)
 }
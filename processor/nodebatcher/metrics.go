@@ -24,13 +24,14 @@ import (
 )
 
 var (
-	statBatchSize               = stats.Int64("batch_size", "Size of batches sent from the batcher (in span)", stats.UnitDimensionless)
+	statBatchSize               = stats.Int64("batch_size", "Size of batches sent from the batcher (in spans or metrics)", stats.UnitDimensionless)
 	statNodesAddedToBatches     = stats.Int64("nodes_added_to_batches", "Count of nodes that are being batched.", stats.UnitDimensionless)
 	statNodesRemovedFromBatches = stats.Int64("nodes_removed_from_batches", "Number of nodes that have been removed from batching.", stats.UnitDimensionless)
 
 	statBatchSizeTriggerSend = stats.Int64("batch_size_trigger_send", "Number of times the batch was sent due to a size trigger", stats.UnitDimensionless)
 	statTimeoutTriggerSend   = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
 	statBatchOnDeadNode      = stats.Int64("removed_node_send", "Number of times the batch was sent due to spans being added for a no longer active node", stats.UnitDimensionless)
+	statFlushTriggerSend     = stats.Int64("flush_trigger_send", "Number of times the batch was sent due to a flush, e.g. on shutdown", stats.UnitDimensionless)
 )
 
 // MetricViews returns the metrics views related to batching
@@ -96,6 +97,14 @@ func MetricViews(level telemetry.Level) []*view.View {
 		Aggregation: view.Sum(),
 	}
 
+	countFlushTriggerSendView := &view.View{
+		Name:        statFlushTriggerSend.Name(),
+		Measure:     statFlushTriggerSend,
+		Description: statFlushTriggerSend.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	return []*view.View{
 		batchSizeView,
 		nodesAddedToBatchesView,
@@ -103,5 +112,6 @@ func MetricViews(level telemetry.Level) []*view.View {
 		countBatchSizeTriggerSendView,
 		countTimeoutTriggerSendView,
 		countBatchOnDeadNode,
+		countFlushTriggerSendView,
 	}
 }
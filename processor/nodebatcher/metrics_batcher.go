@@ -0,0 +1,203 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodebatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opencensus.io/stats"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
+)
+
+// metricsBatcher accepts metrics and places them into batches grouped by node
+// and resource. A batch is sent when it holds more than the send batch size
+// metrics, or when the timeout elapsed since it was last sent, checked at
+// every tick.
+//
+// metricsBatcher implements consumer.MetricsConsumer
+type metricsBatcher struct {
+	// settings holds the settings shared with the trace batcher, it is only
+	// used for them.
+	settings *batcher
+	sender   consumer.MetricsConsumer
+
+	mu      sync.Mutex
+	batches map[string]*metricsBatch
+	ticker  *time.Ticker
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+var _ consumer.MetricsConsumer = (*metricsBatcher)(nil)
+
+type metricsBatch struct {
+	node     *commonpb.Node
+	resource *resourcepb.Resource
+	metrics  []*metricspb.Metric
+	lastSent time.Time
+}
+
+// NewMetricsBatcher creates a new batcher that batches metrics by node and
+// resource. Of the options only WithTimeout, WithTickTime and
+// WithSendBatchSize apply to metrics.
+func NewMetricsBatcher(name string, logger *zap.Logger, sender consumer.MetricsConsumer, opts ...Option) consumer.MetricsConsumer {
+	settings := &batcher{
+		name:          name,
+		logger:        logger,
+		sendBatchSize: defaultSendBatchSize,
+		tickTime:      defaultTickTime,
+		timeout:       defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(settings)
+	}
+
+	mb := &metricsBatcher{
+		settings: settings,
+		sender:   sender,
+		batches:  make(map[string]*metricsBatch),
+		ticker:   time.NewTicker(settings.tickTime),
+		stopCh:   make(chan struct{}),
+	}
+	go mb.runTicker()
+	return mb
+}
+
+// ConsumeMetricsData adds the metrics to the batch of their node and resource.
+func (mb *metricsBatcher) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	bucketID := mb.settings.genBucketID(md.Node, md.Resource, "")
+
+	mb.mu.Lock()
+	batch, ok := mb.batches[bucketID]
+	if !ok {
+		batch = &metricsBatch{node: md.Node, resource: md.Resource, lastSent: time.Now()}
+		mb.batches[bucketID] = batch
+	}
+	batch.metrics = append(batch.metrics, md.Metrics...)
+	var toSend consumerdata.MetricsData
+	if uint32(len(batch.metrics)) > mb.settings.sendBatchSize {
+		toSend = batch.getAndReset()
+	}
+	mb.mu.Unlock()
+
+	if len(toSend.Metrics) > 0 {
+		mb.send(toSend, statBatchSizeTriggerSend)
+	}
+	return nil
+}
+
+// Capabilities returns the consumer.Capabilities of the processor, the batches reference the received metrics without modifying them.
+func (mb *metricsBatcher) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// Flush synchronously sends all the batches, regardless of their size and
+// timeout, e.g. when the service shuts down.
+func (mb *metricsBatcher) Flush(ctx context.Context) error {
+	for _, md := range mb.takeBatches(func(*metricsBatch) bool { return true }) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mb.send(md, statFlushTriggerSend)
+	}
+	return nil
+}
+
+// InFlight returns the number of metrics waiting in the batches.
+func (mb *metricsBatcher) InFlight() int {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	inFlight := 0
+	for _, batch := range mb.batches {
+		inFlight += len(batch.metrics)
+	}
+	return inFlight
+}
+
+// Stop halts the ticker of the batcher, the batches still open are only sent
+// by Flush.
+func (mb *metricsBatcher) Stop() {
+	mb.stopOnce.Do(func() {
+		close(mb.stopCh)
+	})
+}
+
+func (mb *metricsBatcher) runTicker() {
+	for {
+		select {
+		case <-mb.ticker.C:
+			deadline := time.Now().Add(-mb.settings.timeout)
+			timedOut := func(batch *metricsBatch) bool { return batch.lastSent.Before(deadline) }
+			for _, md := range mb.takeBatches(timedOut) {
+				mb.send(md, statTimeoutTriggerSend)
+			}
+		case <-mb.stopCh:
+			mb.ticker.Stop()
+			return
+		}
+	}
+}
+
+// takeBatches returns and resets the non-empty batches matching the filter.
+// The batches that are already empty are removed, they are created again
+// when new metrics arrive.
+func (mb *metricsBatcher) takeBatches(filter func(*metricsBatch) bool) []consumerdata.MetricsData {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	var toSend []consumerdata.MetricsData
+	for bucketID, batch := range mb.batches {
+		if len(batch.metrics) == 0 {
+			delete(mb.batches, bucketID)
+			continue
+		}
+		if filter(batch) {
+			toSend = append(toSend, batch.getAndReset())
+		}
+	}
+	return toSend
+}
+
+func (mb *metricsBatcher) send(md consumerdata.MetricsData, measure *stats.Int64Measure) {
+	statsTags := processor.StatsTagsForBatch(
+		mb.settings.name, processor.ServiceNameForNode(md.Node), "",
+	)
+	_ = stats.RecordWithTags(context.Background(), statsTags,
+		measure.M(1), statBatchSize.M(int64(len(md.Metrics))))
+
+	_ = mb.sender.ConsumeMetricsData(context.Background(), md)
+}
+
+func (batch *metricsBatch) getAndReset() consumerdata.MetricsData {
+	md := consumerdata.MetricsData{
+		Node:     batch.node,
+		Resource: batch.resource,
+		Metrics:  batch.metrics,
+	}
+	batch.metrics = nil
+	batch.lastSent = time.Now()
+	return md
+}
@@ -0,0 +1,162 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodebatcher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+func TestMetricsBatcherSendBatchSize(t *testing.T) {
+	sender := newTestMetricsSender()
+	batcher := NewMetricsBatcher("test", zap.NewNop(), sender, WithSendBatchSize(3), WithTimeout(time.Hour))
+
+	node := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}}
+	for requestNum := 0; requestNum < 2; requestNum++ {
+		_ = batcher.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Node:    node,
+			Metrics: []*metricspb.Metric{newTestMetric(requestNum, 0), newTestMetric(requestNum, 1)},
+		})
+	}
+
+	select {
+	case md := <-sender.reqChan:
+		if len(md.Metrics) != 4 {
+			t.Errorf("Got a batch of %d metrics, want 4", len(md.Metrics))
+		}
+		if md.Node != node {
+			t.Errorf("Got node %v, want %v", md.Node, node)
+		}
+	default:
+		t.Fatal("The batch wasn't sent once its size was exceeded")
+	}
+	if inFlight := batcher.(*metricsBatcher).InFlight(); inFlight != 0 {
+		t.Errorf("Got %d metrics in flight, want 0", inFlight)
+	}
+}
+
+func TestMetricsBatcherTimeout(t *testing.T) {
+	sender := newTestMetricsSender()
+	batcher := NewMetricsBatcher("test", zap.NewNop(), sender,
+		WithTimeout(50*time.Millisecond), WithTickTime(10*time.Millisecond))
+
+	for requestNum := 0; requestNum < 2; requestNum++ {
+		_ = batcher.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Node:    &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: fmt.Sprintf("svc-%d", requestNum)}},
+			Metrics: []*metricspb.Metric{newTestMetric(requestNum, 0)},
+		})
+	}
+
+	// One batch per node.
+	for i := 0; i < 2; i++ {
+		select {
+		case md := <-sender.reqChan:
+			if len(md.Metrics) != 1 {
+				t.Errorf("Got a batch of %d metrics, want 1", len(md.Metrics))
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("The batches weren't sent after the timeout")
+		}
+	}
+}
+
+func TestMetricsBatcherFlush(t *testing.T) {
+	sender := newTestMetricsSender()
+	batcher := NewMetricsBatcher("test", zap.NewNop(), sender, WithTimeout(time.Hour)).(*metricsBatcher)
+
+	for requestNum := 0; requestNum < 2; requestNum++ {
+		_ = batcher.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Node:    &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: fmt.Sprintf("svc-%d", requestNum)}},
+			Metrics: []*metricspb.Metric{newTestMetric(requestNum, 0), newTestMetric(requestNum, 1)},
+		})
+	}
+	if inFlight := batcher.InFlight(); inFlight != 4 {
+		t.Errorf("Got %d metrics in flight, want 4", inFlight)
+	}
+
+	if err := batcher.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	// Flush is synchronous, both batches have already been sent.
+	for i := 0; i < 2; i++ {
+		select {
+		case md := <-sender.reqChan:
+			if len(md.Metrics) != 2 {
+				t.Errorf("Got a batch of %d metrics, want 2", len(md.Metrics))
+			}
+		default:
+			t.Fatal("Flush returned before sending all the batches")
+		}
+	}
+	if inFlight := batcher.InFlight(); inFlight != 0 {
+		t.Errorf("Got %d metrics in flight, want 0", inFlight)
+	}
+}
+
+func newTestMetric(requestNum, index int) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: fmt.Sprintf("test-metric-%d-%d", requestNum, index),
+		},
+	}
+}
+
+type testMetricsSender struct {
+	reqChan chan consumerdata.MetricsData
+}
+
+func newTestMetricsSender() *testMetricsSender {
+	return &testMetricsSender{reqChan: make(chan consumerdata.MetricsData, 100)}
+}
+
+func (ts *testMetricsSender) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	ts.reqChan <- md
+	return nil
+}
+
+func TestMetricsBatcherStop(t *testing.T) {
+	sender := newTestMetricsSender()
+	batcher := NewMetricsBatcher("test", zap.NewNop(), sender,
+		WithTimeout(10*time.Millisecond), WithTickTime(10*time.Millisecond)).(*metricsBatcher)
+	batcher.Stop()
+	// Stopping twice is fine.
+	batcher.Stop()
+
+	_ = batcher.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Node:    &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+		Metrics: []*metricspb.Metric{newTestMetric(0, 0)},
+	})
+
+	// The batch isn't sent after the timeout anymore, only by Flush.
+	select {
+	case <-sender.reqChan:
+		t.Fatal("The batch was sent by the ticker after Stop")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := batcher.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if inFlight := batcher.InFlight(); inFlight != 0 {
+		t.Errorf("Got %d metrics in flight, want 0", inFlight)
+	}
+}
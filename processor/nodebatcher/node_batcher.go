@@ -17,7 +17,8 @@ package nodebatcher
 import (
 	"context"
 	"crypto/sha256"
-	"fmt"
+	"encoding/hex"
+	"hash"
 	"math/rand"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
 	"github.com/open-telemetry/opentelemetry-service/observability"
 )
@@ -65,12 +67,18 @@ type batcher struct {
 	tickers []*bucketTicker
 	name    string
 	logger  *zap.Logger
+	account *memorybudget.Account
 
 	removeAfterCycles uint32
 	sendBatchSize     uint32
+	sendBatchMaxBytes int
+	spanSizer         SpanSizer
 	numTickers        int
 	tickTime          time.Duration
 	timeout           time.Duration
+
+	// stopOnce makes Stop idempotent, the tickers cannot be stopped twice.
+	stopOnce sync.Once
 }
 
 var _ consumer.TraceConsumer = (*batcher)(nil)
@@ -79,12 +87,14 @@ var _ consumer.TraceConsumer = (*batcher)(nil)
 func NewBatcher(name string, logger *zap.Logger, sender consumer.TraceConsumer, opts ...Option) consumer.TraceConsumer {
 	// Init with defaults
 	b := &batcher{
-		name:   name,
-		sender: sender,
-		logger: logger,
+		name:    name,
+		sender:  sender,
+		logger:  logger,
+		account: memorybudget.Default().Register("processor/" + name),
 
 		removeAfterCycles: defaultRemoveAfterCycles,
 		sendBatchSize:     defaultSendBatchSize,
+		spanSizer:         protobufSpanSize,
 		numTickers:        defaultNumTickers,
 		tickTime:          defaultTickTime,
 		timeout:           defaultTimeout,
@@ -109,25 +119,94 @@ func (b *batcher) ConsumeTraceData(ctx context.Context, td consumerdata.TraceDat
 	return nil
 }
 
+// Capabilities returns the consumer.Capabilities of the processor, the batches reference the received spans without modifying them.
+func (b *batcher) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// Flush synchronously sends all the batches, regardless of their size and
+// timeout, e.g. when the service shuts down.
+func (b *batcher) Flush(ctx context.Context) error {
+	var err error
+	b.buckets.Range(func(key, value interface{}) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		nb := value.(*nodeBatch)
+		nb.mu.Lock()
+		itemsToProcess, itemCount := nb.getAndReset()
+		nb.mu.Unlock()
+		if len(itemsToProcess) > 0 {
+			nb.sendItems(itemsToProcess, itemCount, statFlushTriggerSend)
+		}
+		return true
+	})
+	return err
+}
+
+// Stop halts the tickers of the batcher, the batches still open are only sent
+// by Flush.
+func (b *batcher) Stop() {
+	b.stopOnce.Do(func() {
+		for _, ticker := range b.tickers {
+			ticker.stop()
+		}
+	})
+}
+
+// InFlight returns the number of spans waiting in the batches.
+func (b *batcher) InFlight() int {
+	inFlight := 0
+	b.buckets.Range(func(key, value interface{}) bool {
+		nb := value.(*nodeBatch)
+		nb.mu.RLock()
+		inFlight += int(nb.totalItemCount)
+		nb.mu.RUnlock()
+		return true
+	})
+	return inFlight
+}
+
+// bucketIDEncoder computes the ID of the bucket of a node and resource.
+type bucketIDEncoder struct {
+	buf  *proto.Buffer
+	hash hash.Hash
+}
+
+// bucketIDEncoders are reused by all the batches, to save the allocations of
+// the encoding of every batch.
+var bucketIDEncoders = sync.Pool{
+	New: func() interface{} {
+		buf := proto.NewBuffer(nil)
+		// The same node and resource must always get the same bucket, whatever
+		// the order of the labels in their maps.
+		buf.SetDeterministic(true)
+		return &bucketIDEncoder{buf: buf, hash: sha256.New()}
+	},
+}
+
 func (b *batcher) genBucketID(node *commonpb.Node, resource *resourcepb.Resource, spanFormat string) string {
-	h := sha256.New()
+	enc := bucketIDEncoders.Get().(*bucketIDEncoder)
+	defer bucketIDEncoders.Put(enc)
+	enc.hash.Reset()
+
 	if node != nil {
-		nodeKey, err := proto.Marshal(node)
-		if err != nil {
+		enc.buf.Reset()
+		if err := enc.buf.Marshal(node); err != nil {
 			b.logger.Error("Error marshalling node to batcher mapkey.", zap.Error(err))
 		} else {
-			h.Write(nodeKey)
+			enc.hash.Write(enc.buf.Bytes())
 		}
 	}
 	if resource != nil {
-		resourceKey, err := proto.Marshal(resource) // TODO: remove once resource is in span
-		if err != nil {
+		enc.buf.Reset()
+		if err := enc.buf.Marshal(resource); err != nil { // TODO: remove once resource is in span
 			b.logger.Error("Error marshalling resource to batcher mapkey.", zap.Error(err))
 		} else {
-			h.Write(resourceKey)
+			enc.hash.Write(enc.buf.Bytes())
 		}
 	}
-	return fmt.Sprintf("%x", h.Sum([]byte(spanFormat)))
+	return hex.EncodeToString(enc.hash.Sum([]byte(spanFormat)))
 }
 
 func (b *batcher) getBucket(bucketID string) *nodeBatch {
@@ -166,6 +245,8 @@ type nodeBatch struct {
 	mu              sync.RWMutex
 	items           [][]*tracepb.Span
 	totalItemCount  uint32
+	totalBytes      int
+	reservedBytes   int64
 	cyclesUntouched uint32
 	dead            uint32
 	lastSent        int64
@@ -191,22 +272,77 @@ func newNodeBatch(
 	}
 }
 
+// pendingItems are the items of a batch that must be sent.
+type pendingItems struct {
+	items     [][]*tracepb.Span
+	itemCount uint32
+}
+
 func (nb *nodeBatch) add(spans []*tracepb.Span) {
 	nb.mu.Lock()
+	var toSend []pendingItems
+	var bytes int
+	if nb.parent.sendBatchMaxBytes > 0 {
+		toSend, spans, bytes = nb.addUpToMaxBytes(spans)
+	} else if nb.parent.account.Enabled() {
+		for _, span := range spans {
+			bytes += nb.parent.spanSizer(span)
+		}
+	}
 	nb.items = append(nb.items, spans)
 	nb.totalItemCount = nb.totalItemCount + uint32(len(spans))
 	nb.cyclesUntouched = 0
 
-	itemCount := nb.totalItemCount
-	var itemsToProcess [][]*tracepb.Span
-	if nb.totalItemCount > nb.parent.sendBatchSize || nb.dead == nodeStatusDead {
-		itemsToProcess, itemCount = nb.getAndReset()
+	// If the memory budget is exhausted send the batch right away instead of
+	// keeping it in memory.
+	overBudget := false
+	if nb.parent.account.Enabled() {
+		if nb.parent.account.Reserve(int64(bytes)) {
+			nb.reservedBytes += int64(bytes)
+		} else {
+			overBudget = true
+		}
+	}
+
+	if nb.totalItemCount > nb.parent.sendBatchSize || nb.dead == nodeStatusDead || overBudget ||
+		(nb.parent.sendBatchMaxBytes > 0 && nb.totalBytes >= nb.parent.sendBatchMaxBytes) {
+		itemsToProcess, itemCount := nb.getAndReset()
+		toSend = append(toSend, pendingItems{items: itemsToProcess, itemCount: itemCount})
 	}
 	nb.mu.Unlock()
 
-	if len(itemsToProcess) > 0 {
-		nb.sendItems(itemsToProcess, itemCount, statBatchSizeTriggerSend)
+	for _, pending := range toSend {
+		if len(pending.items) > 0 {
+			nb.sendItems(pending.items, pending.itemCount, statBatchSizeTriggerSend)
+		}
+	}
+}
+
+// addUpToMaxBytes accounts the estimated bytes of the spans and, every time
+// the next span would make the batch exceed the max bytes, closes the batch.
+// It returns the closed batches, the spans that were not added to any of
+// them and their estimated bytes. A single span bigger than the max bytes is
+// sent in its own batch. nb.mu must be held.
+func (nb *nodeBatch) addUpToMaxBytes(spans []*tracepb.Span) ([]pendingItems, []*tracepb.Span, int) {
+	var toSend []pendingItems
+	start := 0
+	bytes := 0
+	for i, span := range spans {
+		size := nb.parent.spanSizer(span)
+		if nb.totalBytes > 0 && nb.totalBytes+size > nb.parent.sendBatchMaxBytes {
+			if i > start {
+				nb.items = append(nb.items, spans[start:i])
+				nb.totalItemCount = nb.totalItemCount + uint32(i-start)
+			}
+			itemsToProcess, itemCount := nb.getAndReset()
+			toSend = append(toSend, pendingItems{items: itemsToProcess, itemCount: itemCount})
+			start = i
+			bytes = 0
+		}
+		nb.totalBytes += size
+		bytes += size
 	}
+	return toSend, spans[start:], bytes
 }
 
 func (nb *nodeBatch) sendItems(
@@ -227,7 +363,7 @@ func (nb *nodeBatch) sendItems(
 	statsTags := processor.StatsTagsForBatch(
 		nb.parent.name, processor.ServiceNameForNode(nb.node), nb.format,
 	)
-	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1))
+	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSize.M(int64(itemCount)))
 
 	// TODO: This process should be done in an async way, perhaps with a channel + goroutine worker(s)
 	ctx := observability.ContextWithReceiverName(context.Background(), nb.format)
@@ -240,6 +376,9 @@ func (nb *nodeBatch) getAndReset() ([][]*tracepb.Span, uint32) {
 	nb.items = make([][]*tracepb.Span, 0, len(itemsToProcess))
 	nb.lastSent = time.Now().UnixNano()
 	nb.totalItemCount = 0
+	nb.totalBytes = 0
+	nb.parent.account.Release(nb.reservedBytes)
+	nb.reservedBytes = 0
 	return itemsToProcess, itemsCount
 }
 
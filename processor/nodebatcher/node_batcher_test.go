@@ -24,6 +24,7 @@ import (
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
 	"go.uber.org/zap"
 )
 
@@ -119,6 +120,20 @@ func TestGenBucketID(t *testing.T) {
 				"oc",
 			},
 		},
+		{
+			"identical resources with many labels",
+			true,
+			bucketIDTestInput{
+				nil,
+				&resourcepb.Resource{Labels: map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"}},
+				"oc",
+			},
+			bucketIDTestInput{
+				nil,
+				&resourcepb.Resource{Labels: map[string]string{"e": "5", "d": "4", "c": "3", "b": "2", "a": "1"}},
+				"oc",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -302,6 +317,163 @@ func TestConcurrentBatchAdds(t *testing.T) {
 	}
 }
 
+func TestBatchMaxBytes(t *testing.T) {
+	sender := newTestSender()
+	sizer := func(span *tracepb.Span) int {
+		if span.Name.Value == "big" {
+			return 1000
+		}
+		return 10
+	}
+	batcher := NewBatcher(
+		"test", zap.NewNop(), sender,
+		WithSendBatchMaxBytes(100), WithSpanSizer(sizer), WithTimeout(time.Hour),
+	).(*batcher)
+
+	spans := make([]*tracepb.Span, 0, 26)
+	for spanIndex := 0; spanIndex < 25; spanIndex++ {
+		spans = append(spans, &tracepb.Span{Name: getTestSpanName(0, spanIndex)})
+		if spanIndex == 14 {
+			spans = append(spans, &tracepb.Span{Name: &tracepb.TruncatableString{Value: "big"}})
+		}
+	}
+	request := consumerdata.TraceData{
+		Node:         &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+		Spans:        spans,
+		SourceFormat: "oc_trace",
+	}
+	_ = batcher.ConsumeTraceData(context.Background(), request)
+
+	// The big span is sent on its own, the remaining 10 spans reach the max
+	// bytes and are sent as well.
+	for _, want := range []int{10, 5, 1, 10} {
+		select {
+		case td := <-sender.reqChan:
+			if len(td.Spans) != want {
+				t.Errorf("Got a batch of %d spans, want %d", len(td.Spans), want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for a batch of %d spans", want)
+		}
+	}
+	select {
+	case td := <-sender.reqChan:
+		t.Errorf("Got an unexpected batch of %d spans", len(td.Spans))
+	default:
+	}
+}
+
+func TestBatchMemoryBudget(t *testing.T) {
+	budget := memorybudget.Default()
+	budget.SetLimit(50)
+	defer budget.SetLimit(0)
+
+	sender := newTestSender()
+	sizer := func(span *tracepb.Span) int { return 10 }
+	batcher := NewBatcher(
+		"test", zap.NewNop(), sender, WithSpanSizer(sizer), WithTimeout(time.Hour),
+	).(*batcher)
+
+	newRequest := func(requestNum int) consumerdata.TraceData {
+		spans := make([]*tracepb.Span, 0, 3)
+		for spanIndex := 0; spanIndex < 3; spanIndex++ {
+			spans = append(spans, &tracepb.Span{Name: getTestSpanName(requestNum, spanIndex)})
+		}
+		return consumerdata.TraceData{
+			Node:         &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+			Spans:        spans,
+			SourceFormat: "oc_trace",
+		}
+	}
+
+	_ = batcher.ConsumeTraceData(context.Background(), newRequest(0))
+	if used := budget.Used(); used != 30 {
+		t.Errorf("Budget used %d, want 30", used)
+	}
+
+	// The second request exceeds the budget, the batch is sent right away.
+	_ = batcher.ConsumeTraceData(context.Background(), newRequest(1))
+	select {
+	case td := <-sender.reqChan:
+		if len(td.Spans) != 6 {
+			t.Errorf("Got a batch of %d spans, want 6", len(td.Spans))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the batch")
+	}
+	if used := budget.Used(); used != 0 {
+		t.Errorf("Budget used %d, want 0", used)
+	}
+}
+
+func TestBatchFlush(t *testing.T) {
+	sender := newTestSender()
+	batcher := NewBatcher("test", zap.NewNop(), sender, WithTimeout(time.Hour)).(*batcher)
+
+	for requestNum := 0; requestNum < 2; requestNum++ {
+		_ = batcher.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+			Node:         &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: fmt.Sprintf("svc-%d", requestNum)}},
+			Spans:        []*tracepb.Span{{Name: getTestSpanName(requestNum, 0)}, {Name: getTestSpanName(requestNum, 1)}},
+			SourceFormat: "oc_trace",
+		})
+	}
+	if inFlight := batcher.InFlight(); inFlight != 4 {
+		t.Errorf("Got %d spans in flight, want 4", inFlight)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := batcher.Flush(ctx); err == nil {
+		t.Error("Flush with a done context should fail")
+	}
+
+	if err := batcher.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	// Flush is synchronous, both batches have already been sent.
+	for i := 0; i < 2; i++ {
+		select {
+		case td := <-sender.reqChan:
+			if len(td.Spans) != 2 {
+				t.Errorf("Got a batch of %d spans, want 2", len(td.Spans))
+			}
+		default:
+			t.Fatal("Flush returned before sending all the batches")
+		}
+	}
+	if inFlight := batcher.InFlight(); inFlight != 0 {
+		t.Errorf("Got %d spans in flight, want 0", inFlight)
+	}
+}
+
+func TestBatchStop(t *testing.T) {
+	sender := newTestSender()
+	batcher := NewBatcher("test", zap.NewNop(), sender,
+		WithTimeout(10*time.Millisecond), WithTickTime(10*time.Millisecond)).(*batcher)
+	batcher.Stop()
+	// Stopping twice is fine.
+	batcher.Stop()
+
+	_ = batcher.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+		Node:         &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+		Spans:        []*tracepb.Span{{Name: getTestSpanName(0, 0)}},
+		SourceFormat: "oc_trace",
+	})
+
+	// The batch isn't sent after the timeout anymore, only by Flush.
+	select {
+	case <-sender.reqChan:
+		t.Fatal("The batch was sent by a ticker after Stop")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := batcher.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if inFlight := batcher.InFlight(); inFlight != 0 {
+		t.Errorf("Got %d spans in flight, want 0", inFlight)
+	}
+}
+
 func BenchmarkConcurrentBatchAdds(b *testing.B) {
 	sender1 := newNopSender()
 	batcher := NewBatcher("test", zap.NewNop(), sender1).(*batcher)
@@ -53,6 +53,23 @@ func WithSendBatchSize(sendBatchSize int) Option {
 	}
 }
 
+// WithSendBatchMaxBytes sets the estimated number of bytes after which a
+// batch will be sent, so that the requests sent downstream stay under the
+// size limits of the backends. Zero disables the limit.
+func WithSendBatchMaxBytes(maxBytes int) Option {
+	return func(b *batcher) {
+		b.sendBatchMaxBytes = maxBytes
+	}
+}
+
+// WithSpanSizer sets how the size of the spans is estimated when
+// WithSendBatchMaxBytes is used, by default their protobuf size.
+func WithSpanSizer(sizer SpanSizer) Option {
+	return func(b *batcher) {
+		b.spanSizer = sizer
+	}
+}
+
 // WithRemoveAfterTicks sets the number of ticks that must pass
 // without new spans arriving for a node before that node is deleted
 // from the batcher.
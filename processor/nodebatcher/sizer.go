@@ -0,0 +1,61 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodebatcher
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// SizeEncodingProtobuf estimates the size of the spans encoded as protobuf,
+	// e.g. for the OpenCensus and Jaeger gRPC exporters.
+	SizeEncodingProtobuf = "protobuf"
+	// SizeEncodingJSON estimates the size of the spans encoded as JSON. It is
+	// more expensive to compute than SizeEncodingProtobuf.
+	SizeEncodingJSON = "json"
+)
+
+// SpanSizer returns the estimated number of bytes of the span once serialized.
+type SpanSizer func(span *tracepb.Span) int
+
+// NewSpanSizer returns the SpanSizer for the given encoding, an empty encoding
+// defaults to SizeEncodingProtobuf.
+func NewSpanSizer(encoding string) (SpanSizer, error) {
+	switch encoding {
+	case "", SizeEncodingProtobuf:
+		return protobufSpanSize, nil
+	case SizeEncodingJSON:
+		return jsonSpanSize, nil
+	default:
+		return nil, fmt.Errorf("unknown size encoding %q", encoding)
+	}
+}
+
+func protobufSpanSize(span *tracepb.Span) int {
+	return proto.Size(span)
+}
+
+func jsonSpanSize(span *tracepb.Span) int {
+	blob, err := json.Marshal(span)
+	if err != nil {
+		// Fallback to the protobuf size, JSON is always bigger.
+		return 2 * proto.Size(span)
+	}
+	return len(blob)
+}
@@ -0,0 +1,52 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodebatcher
+
+import (
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpanSizer(t *testing.T) {
+	span := &tracepb.Span{
+		Name: &tracepb.TruncatableString{Value: "operation"},
+		Attributes: &tracepb.Span_Attributes{
+			AttributeMap: map[string]*tracepb.AttributeValue{
+				"key": {Value: &tracepb.AttributeValue_StringValue{
+					StringValue: &tracepb.TruncatableString{Value: "value"}},
+				},
+			},
+		},
+	}
+
+	sizer, err := NewSpanSizer("")
+	require.NoError(t, err)
+	assert.Equal(t, proto.Size(span), sizer(span))
+
+	sizer, err = NewSpanSizer(SizeEncodingProtobuf)
+	require.NoError(t, err)
+	assert.Equal(t, proto.Size(span), sizer(span))
+
+	sizer, err = NewSpanSizer(SizeEncodingJSON)
+	require.NoError(t, err)
+	assert.True(t, sizer(span) > proto.Size(span))
+
+	_, err = NewSpanSizer("xml")
+	assert.Error(t, err)
+}
@@ -18,10 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync/atomic"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/spf13/viper"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/processor"
@@ -36,6 +39,24 @@ const (
 	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
 	bitMaskHashBuckets    = numHashBuckets - 1
 	percentageScaleFactor = numHashBuckets / 100.0
+
+	// samplingPriorityAttr is the span attribute, from OpenTracing, used by
+	// the applications to force the sampling decision of a trace.
+	samplingPriorityAttr = "sampling.priority"
+)
+
+type samplingPriority int
+
+const (
+	// deferDecision means that the sampling decision is based on the hash of
+	// the trace ID.
+	deferDecision samplingPriority = iota
+	// mustSampleSpan means that the span must be sampled, it has a positive
+	// sampling priority.
+	mustSampleSpan
+	// doNotSampleSpan means that the span must not be sampled, it has a zero
+	// sampling priority.
+	doNotSampleSpan
 )
 
 // InitFromViper updates TraceSampler config according to the viper configuration.
@@ -53,12 +74,15 @@ func (tsc *Config) InitFromViper(v *viper.Viper) (*Config, error) {
 }
 
 type tracesamplerprocessor struct {
-	nextConsumer       consumer.TraceConsumer
+	nextConsumer consumer.TraceConsumer
+	// scaledSamplingRate and hashSeed are accessed atomically, they can be
+	// changed by Tune while the data is sampled.
 	scaledSamplingRate uint32
 	hashSeed           uint32
 }
 
 var _ processor.TraceProcessor = (*tracesamplerprocessor)(nil)
+var _ processor.Tunable = (*tracesamplerprocessor)(nil)
 
 // NewTraceProcessor returns a processor.TraceProcessor that will perform head sampling according to the given
 // configuration.
@@ -76,10 +100,8 @@ func NewTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (process
 }
 
 func (tsp *tracesamplerprocessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
-	scaledSamplingRate := tsp.scaledSamplingRate
-	if scaledSamplingRate >= numHashBuckets {
-		return tsp.nextConsumer.ConsumeTraceData(ctx, td)
-	}
+	scaledSamplingRate := atomic.LoadUint32(&tsp.scaledSamplingRate)
+	hashSeed := atomic.LoadUint32(&tsp.hashSeed)
 
 	sampledTraceData := consumerdata.TraceData{
 		Node:         td.Node,
@@ -92,7 +114,15 @@ func (tsp *tracesamplerprocessor) ConsumeTraceData(ctx context.Context, td consu
 		// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
 		// with various different criteria to generate trace id and perhaps were already sampled without hashing.
 		// Hashing here prevents bias due to such systems.
-		if hash(span.TraceId, tsp.hashSeed)&bitMaskHashBuckets < scaledSamplingRate {
+		sampled := scaledSamplingRate >= numHashBuckets ||
+			hash(span.TraceId, hashSeed)&bitMaskHashBuckets < scaledSamplingRate
+		switch parseSpanSamplingPriority(span) {
+		case mustSampleSpan:
+			sampled = true
+		case doNotSampleSpan:
+			sampled = false
+		}
+		if sampled {
 			sampledSpans = append(sampledSpans, span)
 		}
 	}
@@ -102,6 +132,60 @@ func (tsp *tracesamplerprocessor) ConsumeTraceData(ctx context.Context, td consu
 	return tsp.nextConsumer.ConsumeTraceData(ctx, sampledTraceData)
 }
 
+// Capabilities returns the consumer.Capabilities of the processor, the sampled spans are sent in a new TraceData.
+func (tsp *tracesamplerprocessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// Tune changes the sampling percentage and the hash seed of the processor to
+// the ones of the given configuration, see processor.Tunable.
+func (tsp *tracesamplerprocessor) Tune(cfg configmodels.Processor) error {
+	tCfg, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("unexpected configuration type %T", cfg)
+	}
+	if tCfg.SamplingPercentage < 0 {
+		return fmt.Errorf("%s must not be negative", samplingPercentageCfgTag)
+	}
+	atomic.StoreUint32(&tsp.scaledSamplingRate, uint32(tCfg.SamplingPercentage*percentageScaleFactor))
+	atomic.StoreUint32(&tsp.hashSeed, tCfg.HashSeed)
+	return nil
+}
+
+// parseSpanSamplingPriority returns the sampling decision forced by the
+// "sampling.priority" attribute of the span, if any. The attribute can be a
+// number, or a string holding a number: zero means that the span must not be
+// sampled and a positive value that it must be sampled.
+func parseSpanSamplingPriority(span *tracepb.Span) samplingPriority {
+	attrib := span.GetAttributes().GetAttributeMap()[samplingPriorityAttr]
+	if attrib == nil {
+		return deferDecision
+	}
+
+	var priority float64
+	switch v := attrib.Value.(type) {
+	case *tracepb.AttributeValue_IntValue:
+		priority = float64(v.IntValue)
+	case *tracepb.AttributeValue_DoubleValue:
+		priority = v.DoubleValue
+	case *tracepb.AttributeValue_StringValue:
+		var err error
+		if priority, err = strconv.ParseFloat(v.StringValue.GetValue(), 64); err != nil {
+			return deferDecision
+		}
+	default:
+		return deferDecision
+	}
+
+	switch {
+	case priority == 0:
+		return doNotSampleSpan
+	case priority > 0:
+		return mustSampleSpan
+	}
+	return deferDecision
+}
+
 // hash is a murmur3 hash function, see http://en.wikipedia.org/wiki/MurmurHash.
 func hash(key []byte, seed uint32) (hash uint32) {
 	const (
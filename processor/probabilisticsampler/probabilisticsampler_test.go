@@ -251,6 +251,121 @@ func Test_tracesamplerprocessor_SamplingPercentageRange(t *testing.T) {
 	}
 }
 
+// Test_tracesamplerprocessor_Tune checks that the sampling percentage can be
+// changed while the processor runs.
+func Test_tracesamplerprocessor_Tune(t *testing.T) {
+	const testSvcName = "test-svc"
+	sink := &exportertest.SinkTraceExporter{}
+	tsp, err := NewTraceProcessor(sink, Config{SamplingPercentage: 0})
+	if err != nil {
+		t.Fatalf("error when creating tracesamplerprocessor: %v", err)
+	}
+	tunable := tsp.(processor.Tunable)
+
+	if err := tunable.Tune(&Config{SamplingPercentage: -1}); err == nil {
+		t.Fatalf("Tune() with a negative percentage: want error got nil")
+	}
+	if err := tunable.Tune(&Config{SamplingPercentage: 100}); err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	for _, td := range genRandomTestData(10, 1, testSvcName) {
+		if err := tsp.ConsumeTraceData(context.Background(), td); err != nil {
+			t.Fatalf("tracesamplerprocessor.ConsumeTraceData() error = %v", err)
+		}
+	}
+	if _, sampled := assertSampledData(t, sink.AllTraces(), testSvcName); sampled != 10 {
+		t.Fatalf("got %d sampled spans, want 10", sampled)
+	}
+
+	if err := tunable.Tune(&Config{SamplingPercentage: 0}); err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	for _, td := range genRandomTestData(10, 1, testSvcName) {
+		if err := tsp.ConsumeTraceData(context.Background(), td); err != nil {
+			t.Fatalf("tracesamplerprocessor.ConsumeTraceData() error = %v", err)
+		}
+	}
+	if _, sampled := assertSampledData(t, sink.AllTraces(), testSvcName); sampled != 10 {
+		t.Fatalf("got %d sampled spans, want still 10", sampled)
+	}
+}
+
+// Test_tracesamplerprocessor_SpanSamplingPriority checks that the "sampling.priority" attribute of the spans
+// overrides the sampling decision.
+func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		attrib  *tracepb.AttributeValue
+		sampled bool
+	}{
+		{
+			name:    "must_sample_int",
+			cfg:     Config{SamplingPercentage: 0},
+			attrib:  &tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 2}},
+			sampled: true,
+		},
+		{
+			name:    "must_sample_double",
+			cfg:     Config{SamplingPercentage: 0},
+			attrib:  &tracepb.AttributeValue{Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 1}},
+			sampled: true,
+		},
+		{
+			name: "must_sample_string",
+			cfg:  Config{SamplingPercentage: 0},
+			attrib: &tracepb.AttributeValue{Value: &tracepb.AttributeValue_StringValue{
+				StringValue: &tracepb.TruncatableString{Value: "1"},
+			}},
+			sampled: true,
+		},
+		{
+			name:    "must_not_sample",
+			cfg:     Config{SamplingPercentage: 100},
+			attrib:  &tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: 0}},
+			sampled: false,
+		},
+		{
+			name: "defer_invalid_string",
+			cfg:  Config{SamplingPercentage: 100},
+			attrib: &tracepb.AttributeValue{Value: &tracepb.AttributeValue_StringValue{
+				StringValue: &tracepb.TruncatableString{Value: "high"},
+			}},
+			sampled: true,
+		},
+		{
+			name:    "defer_bool",
+			cfg:     Config{SamplingPercentage: 0},
+			attrib:  &tracepb.AttributeValue{Value: &tracepb.AttributeValue_BoolValue{BoolValue: true}},
+			sampled: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &exportertest.SinkTraceExporter{}
+			tsp, err := NewTraceProcessor(sink, tt.cfg)
+			if err != nil {
+				t.Fatalf("NewTraceProcessor() error = %v", err)
+			}
+
+			td := genRandomTestData(1, 1, "svc")[0]
+			td.Spans[0].Attributes = &tracepb.Span_Attributes{
+				AttributeMap: map[string]*tracepb.AttributeValue{
+					samplingPriorityAttr: tt.attrib,
+				},
+			}
+			if err := tsp.ConsumeTraceData(context.Background(), td); err != nil {
+				t.Fatalf("tracesamplerprocessor.ConsumeTraceData() error = %v", err)
+			}
+
+			_, sampledCount := assertSampledData(t, sink.AllTraces(), "svc")
+			if sampled := sampledCount == 1; sampled != tt.sampled {
+				t.Errorf("span sampled = %v, want %v", sampled, tt.sampled)
+			}
+		})
+	}
+}
+
 // Test_hash ensures that the hash function supports different key lengths even if in
 // practice it is only expected to receive keys with length 16 (trace id length in OC proto).
 func Test_hash(t *testing.T) {
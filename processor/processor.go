@@ -16,6 +16,9 @@
 package processor
 
 import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 )
 
@@ -33,6 +36,56 @@ type MetricsProcessor interface {
 	// TODO: Add processor specific functions.
 }
 
+// Flusher is implemented by the processors that buffer data, e.g. to batch it,
+// so that it isn't lost when the service shuts down.
+type Flusher interface {
+	// Flush synchronously sends all the buffered data to the next consumer.
+	// It returns an error if the context is done before the data is sent.
+	Flush(ctx context.Context) error
+
+	// InFlight returns the number of items (spans or metrics) buffered.
+	InFlight() int
+}
+
+// Stopper is implemented by the processors that work in the background, e.g.
+// on a timer, so that they are stopped when the service shuts down. They are
+// flushed before being stopped.
+type Stopper interface {
+	// Stop halts the goroutines of the processor.
+	Stop()
+}
+
+// TraceRouter is implemented by the trace processors that send each batch to
+// some of the exporters of their pipeline, instead of the next consumer. The
+// exporters are set once the pipeline is built.
+type TraceRouter interface {
+	// RoutedExporters returns the names of the exporters the data is routed
+	// to.
+	RoutedExporters() []string
+
+	// SetTraceExporters sets the exporters of RoutedExporters, by name.
+	SetTraceExporters(exporters map[string]consumer.TraceConsumer)
+}
+
+// MetricsRouter is the equivalent of TraceRouter for the metrics processors.
+type MetricsRouter interface {
+	// RoutedExporters returns the names of the exporters the data is routed
+	// to.
+	RoutedExporters() []string
+
+	// SetMetricsExporters sets the exporters of RoutedExporters, by name.
+	SetMetricsExporters(exporters map[string]consumer.MetricsConsumer)
+}
+
+// Tunable is implemented by the processors whose configuration can be changed
+// while the service runs, e.g. the sampling processors.
+type Tunable interface {
+	// Tune applies the given configuration, of the type created by the
+	// factory of the processor. The processor keeps its current configuration
+	// if the given one is invalid or changes settings that can't be tuned.
+	Tune(cfg configmodels.Processor) error
+}
+
 // Processor is a data consumer.
 type Processor interface {
 	consumer.DataConsumer
@@ -0,0 +1,39 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processortest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+// LoadConfigFile loads the config file with the example components of the
+// config package and the factory of the processor under test, the test
+// fails if the config can't be loaded.
+func LoadConfigFile(t *testing.T, fileName string, factory processor.Factory) *configmodels.Config {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	require.NoError(t, err)
+	processors[factory.Type()] = factory
+
+	cfg, err := config.LoadConfigFile(t, fileName, receivers, processors, exporters)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	return cfg
+}
@@ -0,0 +1,78 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processortest
+
+import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+// SinkTraceProcessor is a TraceProcessor storing the data it receives, see
+// exportertest.SinkTraceExporter, before passing it to the next consumer, if
+// any. It is used to assert what reached a point of a pipeline.
+type SinkTraceProcessor struct {
+	exportertest.SinkTraceExporter
+	next consumer.TraceConsumer
+}
+
+var _ processor.TraceProcessor = (*SinkTraceProcessor)(nil)
+
+// NewSinkTraceProcessor creates a SinkTraceProcessor passing the data to next,
+// which can be nil.
+func NewSinkTraceProcessor(next consumer.TraceConsumer) *SinkTraceProcessor {
+	return &SinkTraceProcessor{next: next}
+}
+
+// ConsumeTraceData stores the data and passes it to the next consumer.
+func (stp *SinkTraceProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	if err := stp.SinkTraceExporter.ConsumeTraceData(ctx, td); err != nil {
+		return err
+	}
+	if stp.next == nil {
+		return nil
+	}
+	return stp.next.ConsumeTraceData(ctx, td)
+}
+
+// SinkMetricsProcessor is a MetricsProcessor storing the data it receives,
+// see exportertest.SinkMetricsExporter, before passing it to the next
+// consumer, if any.
+type SinkMetricsProcessor struct {
+	exportertest.SinkMetricsExporter
+	next consumer.MetricsConsumer
+}
+
+var _ processor.MetricsProcessor = (*SinkMetricsProcessor)(nil)
+
+// NewSinkMetricsProcessor creates a SinkMetricsProcessor passing the data to
+// next, which can be nil.
+func NewSinkMetricsProcessor(next consumer.MetricsConsumer) *SinkMetricsProcessor {
+	return &SinkMetricsProcessor{next: next}
+}
+
+// ConsumeMetricsData stores the data and passes it to the next consumer.
+func (smp *SinkMetricsProcessor) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	if err := smp.SinkMetricsExporter.ConsumeMetricsData(ctx, md); err != nil {
+		return err
+	}
+	if smp.next == nil {
+		return nil
+	}
+	return smp.next.ConsumeMetricsData(ctx, md)
+}
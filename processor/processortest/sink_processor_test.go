@@ -0,0 +1,75 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processortest
+
+import (
+	"context"
+	"errors"
+	"path"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestSinkTraceProcessor(t *testing.T) {
+	next := new(exportertest.SinkTraceExporter)
+	stp := NewSinkTraceProcessor(next)
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}
+
+	assert.NoError(t, stp.ConsumeTraceData(context.Background(), td))
+	assert.Equal(t, []consumerdata.TraceData{td}, stp.AllTraces())
+	assert.Equal(t, 3, stp.SpansCount())
+	assert.Equal(t, []consumerdata.TraceData{td}, next.AllTraces())
+
+	// Without a next consumer the data is only stored.
+	stp = NewSinkTraceProcessor(nil)
+	assert.NoError(t, stp.ConsumeTraceData(context.Background(), td))
+	assert.Equal(t, 3, stp.SpansCount())
+
+	// The error of the next consumer is returned.
+	errNext := errors.New("next failed")
+	stp = NewSinkTraceProcessor(exportertest.NewNopTraceExporter(exportertest.WithReturnError(errNext)))
+	assert.Equal(t, errNext, stp.ConsumeTraceData(context.Background(), td))
+}
+
+func TestSinkMetricsProcessor(t *testing.T) {
+	next := new(exportertest.SinkMetricsExporter)
+	smp := NewSinkMetricsProcessor(next)
+	md := consumerdata.MetricsData{Metrics: make([]*metricspb.Metric, 2)}
+
+	assert.NoError(t, smp.ConsumeMetricsData(context.Background(), md))
+	assert.Equal(t, []consumerdata.MetricsData{md}, smp.AllMetrics())
+	assert.Equal(t, 2, smp.MetricsCount())
+	assert.Equal(t, []consumerdata.MetricsData{md}, next.AllMetrics())
+
+	smp = NewSinkMetricsProcessor(nil)
+	assert.NoError(t, smp.ConsumeMetricsData(context.Background(), md))
+	assert.Equal(t, 2, smp.MetricsCount())
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	cfg := LoadConfigFile(t, path.Join("testdata", "config.yaml"), &config.ExampleProcessorFactory{})
+
+	p := cfg.Processors["exampleprocessor/custom"]
+	if assert.IsType(t, &config.ExampleProcessor{}, p) {
+		assert.Equal(t, "custom string", p.(*config.ExampleProcessor).ExtraSetting)
+	}
+}
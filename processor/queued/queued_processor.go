@@ -16,7 +16,10 @@ package queued
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/queue"
@@ -30,9 +33,15 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
+	"github.com/open-telemetry/opentelemetry-service/internal/contextutils"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
 	"github.com/open-telemetry/opentelemetry-service/processor/nodebatcher"
 )
 
+// errQueueIsFull is returned when a batch cannot be added to the queue, so
+// that the receivers ask their clients to slow down.
+var errQueueIsFull = consumererror.Overloaded(errors.New("queued-retry queue is full"), 0)
+
 type queuedSpanProcessor struct {
 	name                     string
 	queue                    *queue.BoundedQueue
@@ -43,10 +52,39 @@ type queuedSpanProcessor struct {
 	backoffDelay             time.Duration
 	stopCh                   chan struct{}
 	stopOnce                 sync.Once
+	// obsCtx is used to record the obsreport metrics of the processor.
+	obsCtx context.Context
+	// inFlight is the number of spans queued or being sent.
+	inFlight int64
 }
 
 var _ consumer.TraceConsumer = (*queuedSpanProcessor)(nil)
 
+// batchingQueuedProcessor is the queued processor with batching enabled, the
+// batches are flushed to the queue before it is drained.
+type batchingQueuedProcessor struct {
+	consumer.TraceConsumer
+	batcher flusher
+	queue   *queuedSpanProcessor
+}
+
+// flusher is the processor.Flusher implemented by the batcher.
+type flusher interface {
+	Flush(ctx context.Context) error
+	InFlight() int
+}
+
+func (bp *batchingQueuedProcessor) Flush(ctx context.Context) error {
+	if err := bp.batcher.Flush(ctx); err != nil {
+		return err
+	}
+	return bp.queue.Flush(ctx)
+}
+
+func (bp *batchingQueuedProcessor) InFlight() int {
+	return bp.batcher.InFlight() + bp.queue.InFlight()
+}
+
 type queueItem struct {
 	queuedTime time.Time
 	td         consumerdata.TraceData
@@ -77,6 +115,8 @@ func NewQueuedSpanProcessor(sender consumer.TraceConsumer, opts ...Option) consu
 			case <-ticker.C:
 				length := int64(sp.queue.Size())
 				stats.Record(ctx, statQueueLength.M(length))
+				obsreport.RecordProcessorQueueSize(sp.obsCtx, int(length))
+				obsreport.RecordProcessorQueueCapacity(sp.obsCtx, options.queueSize)
 			}
 		}
 	}(ctx)
@@ -84,7 +124,11 @@ func NewQueuedSpanProcessor(sender consumer.TraceConsumer, opts ...Option) consu
 	if options.batchingEnabled {
 		sp.logger.Info("Using queued processor with batching.")
 		batcher := nodebatcher.NewBatcher(sp.name, sp.logger, sp, options.batchingOptions...)
-		return batcher
+		return &batchingQueuedProcessor{
+			TraceConsumer: batcher,
+			batcher:       batcher.(flusher),
+			queue:         sp,
+		}
 	}
 
 	return sp
@@ -101,6 +145,7 @@ func newQueuedSpanProcessor(sender consumer.TraceConsumer, opts options) *queued
 		retryOnProcessingFailure: opts.retryOnProcessingFailure,
 		backoffDelay:             opts.backoffDelay,
 		stopCh:                   make(chan struct{}),
+		obsCtx:                   obsreport.ProcessorContext(context.Background(), opts.name),
 	}
 }
 
@@ -112,25 +157,60 @@ func (sp *queuedSpanProcessor) Stop() {
 	})
 }
 
-// ConsumeTraceData implements the SpanProcessor interface
+// ConsumeTraceData implements the SpanProcessor interface. The spans are sent
+// after the call returned, with the values of its context but not its deadline
+// and cancellation, until the processor is stopped.
 func (sp *queuedSpanProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
 	item := &queueItem{
 		queuedTime: time.Now(),
 		td:         td,
-		ctx:        ctx,
+		ctx:        contextutils.Detach(ctx, sp.stopCh),
 	}
 
 	statsTags := processor.StatsTagsForBatch(sp.name, processor.ServiceNameForNode(td.Node), td.SourceFormat)
 	numSpans := len(td.Spans)
 	stats.RecordWithTags(context.Background(), statsTags, processor.StatReceivedSpanCount.M(int64(numSpans)))
 
+	atomic.AddInt64(&sp.inFlight, int64(numSpans))
 	addedToQueue := sp.queue.Produce(item)
 	if !addedToQueue {
 		sp.onItemDropped(item, statsTags)
+		return errQueueIsFull
 	}
 	return nil
 }
 
+// Capabilities returns the consumer.Capabilities of the processor, it only keeps the data until it is sent.
+func (sp *queuedSpanProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+// flushPollInterval is how often Flush checks whether the queue is empty.
+const flushPollInterval = 10 * time.Millisecond
+
+// Flush waits until all the queued spans are sent, or dropped, or the context
+// is done.
+func (sp *queuedSpanProcessor) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushPollInterval)
+	defer ticker.Stop()
+	for {
+		inFlight := sp.InFlight()
+		if inFlight == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d spans still in flight: %v", inFlight, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// InFlight returns the number of spans queued or being sent.
+func (sp *queuedSpanProcessor) InFlight() int {
+	return int(atomic.LoadInt64(&sp.inFlight))
+}
+
 func (sp *queuedSpanProcessor) processItemFromQueue(item *queueItem) {
 	startTime := time.Now()
 	err := sp.sender.ConsumeTraceData(item.ctx, item.td)
@@ -144,6 +224,7 @@ func (sp *queuedSpanProcessor) processItemFromQueue(item *queueItem) {
 			statSuccessSendOps.M(1),
 			statSendLatencyMs.M(sendLatencyMs),
 			statInQueueLatencyMs.M(inQueueLatencyMs))
+		atomic.AddInt64(&sp.inFlight, -int64(len(item.td.Spans)))
 
 		return
 	}
@@ -166,6 +247,7 @@ func (sp *queuedSpanProcessor) processItemFromQueue(item *queueItem) {
 			context.Background(),
 			statsTags,
 			processor.StatBadBatchDroppedSpanCount.M(int64(numSpans)))
+		atomic.AddInt64(&sp.inFlight, -int64(numSpans))
 
 		return
 	}
@@ -206,7 +288,9 @@ func (sp *queuedSpanProcessor) processItemFromQueue(item *queueItem) {
 
 func (sp *queuedSpanProcessor) onItemDropped(item *queueItem, statsTags []tag.Mutator) {
 	numSpans := len(item.td.Spans)
+	atomic.AddInt64(&sp.inFlight, -int64(numSpans))
 	stats.RecordWithTags(context.Background(), statsTags, processor.StatDroppedSpanCount.M(int64(numSpans)))
+	obsreport.ProcessorTraceDataDropped(sp.obsCtx, numSpans)
 
 	sp.logger.Warn("Span batch dropped",
 		zap.String("processor", sp.name),
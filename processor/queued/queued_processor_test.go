@@ -23,11 +23,15 @@ import (
 	"time"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/nodebatcher"
 )
 
 func TestQueuedProcessor_noEnqueueOnPermanentError(t *testing.T) {
@@ -65,6 +69,102 @@ func TestQueuedProcessor_noEnqueueOnPermanentError(t *testing.T) {
 	require.Equal(t, 1, qp.queue.Size())
 }
 
+func TestQueuedProcessor_QueueIsFull(t *testing.T) {
+	blockCh := make(chan struct{})
+	defer close(blockCh)
+	qp := NewQueuedSpanProcessor(
+		&blockingTraceConsumer{blockCh: blockCh},
+		Options.WithNumWorkers(1),
+		Options.WithQueueSize(1),
+	)
+
+	// The worker blocks on the first batch, the second one fills the queue.
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 1)}
+	require.Nil(t, qp.ConsumeTraceData(context.Background(), td))
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = qp.ConsumeTraceData(context.Background(), td)
+	}
+	_, ok := consumererror.IsOverloaded(err)
+	assert.True(t, ok, "got %v, want an overloaded error", err)
+}
+
+type blockingTraceConsumer struct {
+	blockCh chan struct{}
+}
+
+func (c *blockingTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	<-c.blockCh
+	return nil
+}
+
+func TestQueuedProcessor_Flush(t *testing.T) {
+	c := &waitGroupTraceConsumer{}
+	qp := NewQueuedSpanProcessor(
+		c,
+		Options.WithLogger(zap.NewNop()),
+		Options.WithNumWorkers(1),
+		Options.WithBatching(true),
+		Options.WithBatchingOptions(nodebatcher.WithTimeout(time.Hour)),
+	)
+	f := qp.(processor.Flusher)
+
+	c.Add(1)
+	require.Nil(t, qp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: make([]*tracepb.Span, 3)}))
+	// The spans wait in the batch until it is flushed.
+	require.Equal(t, 3, f.InFlight())
+
+	require.NoError(t, f.Flush(context.Background()))
+	c.Wait()
+	require.Equal(t, 0, f.InFlight())
+}
+
+// contextTraceConsumer blocks until the context of the spans is done, or it
+// is released.
+type contextTraceConsumer struct {
+	started chan context.Context
+	release chan struct{}
+	errs    chan error
+}
+
+func (c *contextTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	c.started <- ctx
+	select {
+	case <-ctx.Done():
+	case <-c.release:
+	}
+	c.errs <- ctx.Err()
+	return ctx.Err()
+}
+
+type ctxKey struct{}
+
+func TestQueuedProcessor_Context(t *testing.T) {
+	c := &contextTraceConsumer{
+		started: make(chan context.Context, 2),
+		release: make(chan struct{}),
+		errs:    make(chan error, 2),
+	}
+	qp := NewQueuedSpanProcessor(c, Options.WithNumWorkers(1)).(*queuedSpanProcessor)
+
+	// The spans are sent after the call returned and its context is canceled,
+	// with the values of the context.
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	require.NoError(t, qp.ConsumeTraceData(ctx, consumerdata.TraceData{Spans: make([]*tracepb.Span, 1)}))
+	cancel()
+	sendCtx := <-c.started
+	assert.Equal(t, "value", sendCtx.Value(ctxKey{}))
+	close(c.release)
+	assert.NoError(t, <-c.errs)
+
+	// Stopping the processor aborts the spans being sent.
+	c.release = make(chan struct{})
+	require.NoError(t, qp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: make([]*tracepb.Span, 1)}))
+	<-c.started
+	qp.Stop()
+	assert.Equal(t, context.Canceled, <-c.errs)
+}
+
 type waitGroupTraceConsumer struct {
 	sync.WaitGroup
 	consumeTraceDataError error
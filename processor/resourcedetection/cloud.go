@@ -0,0 +1,111 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// The metadata endpoints, variables to be replaced in tests.
+var (
+	ec2IdentityURL   = "http://169.254.169.254/latest/dynamic/instance-identity/document"
+	gceMetadataURL   = "http://metadata.google.internal/computeMetadata/v1/"
+	azureMetadataURL = "http://169.254.169.254/metadata/instance/compute?api-version=2019-06-01"
+)
+
+// detectEC2 reads the identity document of the EC2 instance.
+func detectEC2(ctx context.Context) (map[string]string, error) {
+	body, err := httpGet(ctx, ec2IdentityURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		AccountID        string `json:"accountId"`
+		AvailabilityZone string `json:"availabilityZone"`
+		Region           string `json:"region"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		LabelCloudProvider:  "aws",
+		LabelCloudAccountID: doc.AccountID,
+		LabelCloudRegion:    doc.Region,
+		LabelCloudZone:      doc.AvailabilityZone,
+		LabelHostID:         doc.InstanceID,
+		LabelHostType:       doc.InstanceType,
+	}, nil
+}
+
+// detectGCE reads the metadata of the GCE instance, one value per request.
+func detectGCE(ctx context.Context) (map[string]string, error) {
+	header := http.Header{"Metadata-Flavor": []string{"Google"}}
+	get := func(path string) (string, error) {
+		body, err := httpGet(ctx, gceMetadataURL+path, header)
+		return strings.TrimSpace(string(body)), err
+	}
+
+	labels := map[string]string{LabelCloudProvider: "gcp"}
+	for label, path := range map[string]string{
+		LabelCloudAccountID: "project/project-id",
+		LabelHostID:         "instance/id",
+		LabelHostHostname:   "instance/hostname",
+		LabelCloudZone:      "instance/zone",
+		LabelHostType:       "instance/machine-type",
+	} {
+		value, err := get(path)
+		if err != nil {
+			return nil, err
+		}
+		// The zone and machine type are returned as paths, e.g.
+		// "projects/123/zones/us-central1-a".
+		labels[label] = value[strings.LastIndex(value, "/")+1:]
+	}
+	if zone := labels[LabelCloudZone]; strings.Count(zone, "-") == 2 {
+		labels[LabelCloudRegion] = zone[:strings.LastIndex(zone, "-")]
+	}
+	return labels, nil
+}
+
+// detectAzure reads the compute metadata of the Azure virtual machine.
+func detectAzure(ctx context.Context) (map[string]string, error) {
+	body, err := httpGet(ctx, azureMetadataURL, http.Header{"Metadata": []string{"true"}})
+	if err != nil {
+		return nil, err
+	}
+	var compute struct {
+		Location       string `json:"location"`
+		Name           string `json:"name"`
+		VMID           string `json:"vmId"`
+		VMSize         string `json:"vmSize"`
+		SubscriptionID string `json:"subscriptionId"`
+	}
+	if err := json.Unmarshal(body, &compute); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		LabelCloudProvider:  "azure",
+		LabelCloudAccountID: compute.SubscriptionID,
+		LabelCloudRegion:    compute.Location,
+		LabelHostID:         compute.VMID,
+		LabelHostHostname:   compute.Name,
+		LabelHostType:       compute.VMSize,
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEC2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"accountId": "123456789012",
+			"availabilityZone": "us-east-1a",
+			"region": "us-east-1",
+			"instanceId": "i-1234567890abcdef0",
+			"instanceType": "t2.micro"
+		}`))
+	}))
+	defer server.Close()
+	defer func(saved string) { ec2IdentityURL = saved }(ec2IdentityURL)
+	ec2IdentityURL = server.URL
+
+	labels, err := detectEC2(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		LabelCloudProvider:  "aws",
+		LabelCloudAccountID: "123456789012",
+		LabelCloudRegion:    "us-east-1",
+		LabelCloudZone:      "us-east-1a",
+		LabelHostID:         "i-1234567890abcdef0",
+		LabelHostType:       "t2.micro",
+	}, labels)
+}
+
+func TestDetectGCE(t *testing.T) {
+	metadata := map[string]string{
+		"/project/project-id":    "my-project",
+		"/instance/id":           "4520031799277581759",
+		"/instance/hostname":     "instance-1.c.my-project.internal",
+		"/instance/zone":         "projects/123/zones/us-central1-a",
+		"/instance/machine-type": "projects/123/machineTypes/n1-standard-1",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := metadata[r.URL.Path]
+		if !ok || r.Header.Get("Metadata-Flavor") != "Google" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(value))
+	}))
+	defer server.Close()
+	defer func(saved string) { gceMetadataURL = saved }(gceMetadataURL)
+	gceMetadataURL = server.URL + "/"
+
+	labels, err := detectGCE(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		LabelCloudProvider:  "gcp",
+		LabelCloudAccountID: "my-project",
+		LabelCloudRegion:    "us-central1",
+		LabelCloudZone:      "us-central1-a",
+		LabelHostID:         "4520031799277581759",
+		LabelHostHostname:   "instance-1.c.my-project.internal",
+		LabelHostType:       "n1-standard-1",
+	}, labels)
+}
+
+func TestDetectAzure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			http.Error(w, "missing header", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{
+			"location": "westeurope",
+			"name": "vm-1",
+			"vmId": "13f56399-bd52-4150-9748-7190aae1ff21",
+			"vmSize": "Standard_D2s_v3",
+			"subscriptionId": "8d10da13-8125-4ba9-a717-bf7490507b3d"
+		}`))
+	}))
+	defer server.Close()
+	defer func(saved string) { azureMetadataURL = saved }(azureMetadataURL)
+	azureMetadataURL = server.URL
+
+	labels, err := detectAzure(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		LabelCloudProvider:  "azure",
+		LabelCloudAccountID: "8d10da13-8125-4ba9-a717-bf7490507b3d",
+		LabelCloudRegion:    "westeurope",
+		LabelHostID:         "13f56399-bd52-4150-9748-7190aae1ff21",
+		LabelHostHostname:   "vm-1",
+		LabelHostType:       "Standard_D2s_v3",
+	}, labels)
+}
+
+func TestDetectCloudNotAvailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	defer func(ec2, gce, azure string) {
+		ec2IdentityURL, gceMetadataURL, azureMetadataURL = ec2, gce, azure
+	}(ec2IdentityURL, gceMetadataURL, azureMetadataURL)
+	ec2IdentityURL, gceMetadataURL, azureMetadataURL = server.URL, server.URL+"/", server.URL
+
+	for _, detect := range []detectFunc{detectEC2, detectGCE, detectAzure} {
+		_, err := detect(context.Background())
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Resource Detection processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Detectors are the names of the detectors run at startup, in order. When
+	// several detectors find the same label the first one wins.
+	Detectors []string `mapstructure:"detectors"`
+	// Timeout is the maximum time each detector may take, the detectors
+	// that time out don't set any label.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Override is set to true to replace the labels the resources already
+	// have with the detected ones. By default the existing labels are kept.
+	Override bool `mapstructure:"override"`
+}
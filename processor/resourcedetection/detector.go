@@ -0,0 +1,133 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcedetection implements a processor detecting at startup the
+// environment the service runs in, the cloud instance, host or container, and
+// setting it as resource labels on all the data passing through a pipeline.
+package resourcedetection
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Resource label keys set by the detectors.
+const (
+	LabelCloudProvider  = "cloud.provider"
+	LabelCloudAccountID = "cloud.account.id"
+	LabelCloudRegion    = "cloud.region"
+	LabelCloudZone      = "cloud.zone"
+	LabelHostID         = "host.id"
+	LabelHostHostname   = "host.hostname"
+	LabelHostType       = "host.type"
+	LabelOSType         = "os.type"
+	LabelContainerID    = "container.id"
+)
+
+// Names of the detectors in the configuration.
+const (
+	ec2Detector    = "ec2"
+	gceDetector    = "gce"
+	azureDetector  = "azure"
+	systemDetector = "system"
+	dockerDetector = "docker"
+)
+
+// detectFunc returns the labels of the environment, or an error when the
+// service doesn't run in it.
+type detectFunc func(ctx context.Context) (map[string]string, error)
+
+var detectors = map[string]detectFunc{
+	ec2Detector:    detectEC2,
+	gceDetector:    detectGCE,
+	azureDetector:  detectAzure,
+	systemDetector: detectSystem,
+	dockerDetector: detectDocker,
+}
+
+// Detect runs the named detectors in order, each with the given timeout, and
+// returns the labels they found. When several detectors find the same label
+// the first one wins. The detectors that fail, e.g. the cloud detectors
+// outside of their cloud, are logged and skipped.
+func Detect(ctx context.Context, logger *zap.Logger, names []string, timeout time.Duration) (map[string]string, error) {
+	funcs := make([]detectFunc, 0, len(names))
+	for _, name := range names {
+		detect, ok := detectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource detector %q", name)
+		}
+		funcs = append(funcs, detect)
+	}
+
+	labels := make(map[string]string)
+	for i, detect := range funcs {
+		detected, err := runDetector(ctx, detect, timeout)
+		if err != nil {
+			logger.Info("Resource detector found nothing",
+				zap.String("detector", names[i]), zap.Error(err))
+			continue
+		}
+		for k, v := range detected {
+			if _, ok := labels[k]; !ok && v != "" {
+				labels[k] = v
+			}
+		}
+	}
+	return labels, nil
+}
+
+func runDetector(ctx context.Context, detect detectFunc, timeout time.Duration) (map[string]string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return detect(ctx)
+}
+
+// httpGet returns the body of a successful GET request to a metadata
+// endpoint.
+func httpGet(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func sortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,53 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDetect(t *testing.T) {
+	defer func(saved map[string]detectFunc) { detectors = saved }(detectors)
+	detectors = map[string]detectFunc{
+		"first": func(context.Context) (map[string]string, error) {
+			return map[string]string{"a": "first", "empty": ""}, nil
+		},
+		"second": func(context.Context) (map[string]string, error) {
+			return map[string]string{"a": "second", "b": "second", "empty": "second"}, nil
+		},
+		"failing": func(context.Context) (map[string]string, error) {
+			return map[string]string{"c": "failing"}, errors.New("not here")
+		},
+		"slow": func(ctx context.Context) (map[string]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	labels, err := Detect(context.Background(), zap.NewNop(),
+		[]string{"failing", "slow", "first", "second"}, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "first", "b": "second", "empty": "second"}, labels)
+
+	_, err = Detect(context.Background(), zap.NewNop(), []string{"first", "unknown"}, time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/resourceprocessor"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "resource-detection"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// Factory is the factory for Resource Detection processor.
+type Factory struct {
+}
+
+// Type gets the type of the config created by this factory.
+func (f *Factory) Type() string {
+	return typeStr
+}
+
+// CreateDefaultConfig creates the default configuration for processor.
+func (f *Factory) CreateDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Detectors: []string{systemDetector},
+		Timeout:   defaultTimeout,
+	}
+}
+
+// CreateTraceProcessor creates a trace processor based on this config.
+func (f *Factory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	rCfg, err := resourceConfig(logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return resourceprocessor.NewTraceProcessor(nextConsumer, rCfg)
+}
+
+// CreateMetricsProcessor creates a metrics processor based on this config.
+func (f *Factory) CreateMetricsProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.MetricsConsumer,
+	cfg configmodels.Processor,
+) (processor.MetricsProcessor, error) {
+	rCfg, err := resourceConfig(logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return resourceprocessor.NewMetricsProcessor(nextConsumer, rCfg)
+}
+
+// resourceConfig runs the detectors and returns the configuration of the
+// resource processor setting the detected labels.
+func resourceConfig(logger *zap.Logger, cfg *Config) (resourceprocessor.Config, error) {
+	labels, err := Detect(context.Background(), logger, cfg.Detectors, cfg.Timeout)
+	if err != nil {
+		return resourceprocessor.Config{}, err
+	}
+	rCfg := resourceprocessor.Config{ProcessorSettings: cfg.ProcessorSettings}
+	for _, key := range sortedKeys(labels) {
+		rCfg.Labels = append(rCfg.Labels, resourceprocessor.Label{
+			Key:       key,
+			Value:     labels[key],
+			Overwrite: cfg.Override,
+		})
+	}
+	return rCfg, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateProcessor(t *testing.T) {
+	factory := &Factory{}
+
+	cfg := factory.CreateDefaultConfig()
+
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), exportertest.NewNopTraceExporter(), cfg)
+	assert.NotNil(t, tp)
+	assert.NoError(t, err, "cannot create trace processor")
+
+	mp, err := factory.CreateMetricsProcessor(zap.NewNop(), exportertest.NewNopMetricsExporter(), cfg)
+	assert.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+}
+
+func TestCreateProcessorUnknownDetector(t *testing.T) {
+	factory := &Factory{}
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Detectors = []string{"system", "mainframe"}
+
+	tp, err := factory.CreateTraceProcessor(zap.NewNop(), exportertest.NewNopTraceExporter(), cfg)
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+
+	mp, err := factory.CreateMetricsProcessor(zap.NewNop(), exportertest.NewNopMetricsExporter(), cfg)
+	assert.Nil(t, mp)
+	assert.Error(t, err)
+}
+
+func TestProcessorSetsDetectedLabels(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	factory := &Factory{}
+	sink := &exportertest.SinkMetricsExporter{}
+	for _, override := range []bool{false, true} {
+		cfg := factory.CreateDefaultConfig().(*Config)
+		cfg.Override = override
+		mp, err := factory.CreateMetricsProcessor(zap.NewNop(), sink, cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+			Resource: &resourcepb.Resource{Labels: map[string]string{LabelHostHostname: "reported"}},
+		}))
+	}
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 2)
+	assert.Equal(t, map[string]string{
+		LabelHostHostname: "reported",
+		LabelOSType:       runtime.GOOS,
+	}, got[0].Resource.Labels)
+	assert.Equal(t, map[string]string{
+		LabelHostHostname: hostname,
+		LabelOSType:       runtime.GOOS,
+	}, got[1].Resource.Labels)
+}
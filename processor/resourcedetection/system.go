@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"runtime"
+)
+
+// cgroupPath is the file the docker container id is read from, a variable to
+// be replaced in tests.
+var cgroupPath = "/proc/self/cgroup"
+
+var containerIDRegexp = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// detectSystem returns the host name and the operating system.
+func detectSystem(ctx context.Context) (map[string]string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		LabelHostHostname: hostname,
+		LabelOSType:       runtime.GOOS,
+	}, nil
+}
+
+// detectDocker returns the id of the docker container, found in the cgroups of
+// the process.
+func detectDocker(ctx context.Context) (map[string]string, error) {
+	f, err := os.Open(cgroupPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := containerIDRegexp.FindString(scanner.Text()); id != "" {
+			return map[string]string{LabelContainerID: id}, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("not running in a docker container")
+}
@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetection
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSystem(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	labels, err := detectSystem(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		LabelHostHostname: hostname,
+		LabelOSType:       runtime.GOOS,
+	}, labels)
+}
+
+func TestDetectDocker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resourcedetection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(saved string) { cgroupPath = saved }(cgroupPath)
+
+	const id = "3c3c8a9c2b1e06f8d3f7e1c3a5c8d5f1a2b3c4d5e6f708192a3b4c5d6e7f8091"
+	cgroupPath = filepath.Join(dir, "docker")
+	require.NoError(t, ioutil.WriteFile(cgroupPath, []byte(
+		"12:pids:/docker/"+id+"\n11:memory:/docker/"+id+"\n"), 0600))
+	labels, err := detectDocker(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{LabelContainerID: id}, labels)
+
+	cgroupPath = filepath.Join(dir, "host")
+	require.NoError(t, ioutil.WriteFile(cgroupPath, []byte("12:pids:/user.slice\n"), 0600))
+	_, err = detectDocker(context.Background())
+	assert.Error(t, err)
+
+	cgroupPath = filepath.Join(dir, "missing")
+	_, err = detectDocker(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Resource processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// ResourceType sets the type of the resources, e.g. "host" or "k8s".
+	ResourceType string `mapstructure:"resource-type"`
+	// OverwriteType is set to true to replace the type of the resources that
+	// already have one.
+	OverwriteType bool `mapstructure:"overwrite-type"`
+
+	// Labels are the labels set on the resources.
+	Labels []Label `mapstructure:"labels"`
+}
+
+// Label defines a label set on the resources.
+type Label struct {
+	Key   string `mapstructure:"key"`
+	Value string `mapstructure:"value"`
+	// Overwrite is set to true to replace the value of the label when the
+	// resource already has it. By default the existing value is kept.
+	Overwrite bool `mapstructure:"overwrite"`
+}
@@ -0,0 +1,124 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourceprocessor implements a processor setting the type and
+// labels of the resources of the data passing through a pipeline, e.g. the
+// service name or the deployment environment.
+package resourceprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+type resourceProcessor struct {
+	cfg         Config
+	nextTrace   consumer.TraceConsumer
+	nextMetrics consumer.MetricsConsumer
+}
+
+var _ processor.TraceProcessor = (*resourceProcessor)(nil)
+var _ processor.MetricsProcessor = (*resourceProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor setting the resource
+// type and labels of the traces.
+func NewTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return &resourceProcessor{cfg: cfg, nextTrace: nextConsumer}, nil
+}
+
+// NewMetricsProcessor returns a processor.MetricsProcessor setting the
+// resource type and labels of the metrics.
+func NewMetricsProcessor(nextConsumer consumer.MetricsConsumer, cfg Config) (processor.MetricsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return &resourceProcessor{cfg: cfg, nextMetrics: nextConsumer}, nil
+}
+
+func validate(cfg Config) error {
+	seen := make(map[string]bool, len(cfg.Labels))
+	for _, label := range cfg.Labels {
+		if label.Key == "" {
+			return errors.New("resource label key must not be empty")
+		}
+		if seen[label.Key] {
+			return fmt.Errorf("resource label %q is set more than once", label.Key)
+		}
+		seen[label.Key] = true
+	}
+	return nil
+}
+
+// ConsumeTraceData sets the resource of the batch and of the spans that have
+// their own resource, those taking precedence over the one of the batch.
+func (rp *resourceProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	td.Resource = rp.apply(td.Resource)
+	for _, span := range td.Spans {
+		if span != nil && span.Resource != nil {
+			span.Resource = rp.apply(span.Resource)
+		}
+	}
+	return rp.nextTrace.ConsumeTraceData(ctx, td)
+}
+
+// ConsumeMetricsData sets the resource of the batch and of the metrics that
+// have their own resource, those taking precedence over the one of the batch.
+func (rp *resourceProcessor) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	md.Resource = rp.apply(md.Resource)
+	for _, metric := range md.Metrics {
+		if metric != nil && metric.Resource != nil {
+			metric.Resource = rp.apply(metric.Resource)
+		}
+	}
+	return rp.nextMetrics.ConsumeMetricsData(ctx, md)
+}
+
+// apply returns a copy of the resource with the configured type and labels,
+// the resource itself may be shared with other pipelines and is not modified.
+func (rp *resourceProcessor) apply(resource *resourcepb.Resource) *resourcepb.Resource {
+	result := &resourcepb.Resource{
+		Type:   resource.GetType(),
+		Labels: make(map[string]string, len(resource.GetLabels())+len(rp.cfg.Labels)),
+	}
+	for k, v := range resource.GetLabels() {
+		result.Labels[k] = v
+	}
+
+	if rp.cfg.ResourceType != "" && (result.Type == "" || rp.cfg.OverwriteType) {
+		result.Type = rp.cfg.ResourceType
+	}
+	for _, label := range rp.cfg.Labels {
+		if _, ok := result.Labels[label.Key]; ok && !label.Overwrite {
+			continue
+		}
+		result.Labels[label.Key] = label.Value
+	}
+	return result
+}
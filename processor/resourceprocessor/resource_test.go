@@ -0,0 +1,113 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+var testConfig = Config{
+	ResourceType: "host",
+	Labels: []Label{
+		{Key: "service.name", Value: "checkout"},
+		{Key: "deployment.environment", Value: "production", Overwrite: true},
+		{Key: "region", Value: "us-east-1"},
+	},
+}
+
+func TestResourceProcessor_Traces(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, testConfig)
+	require.NoError(t, err)
+
+	original := &resourcepb.Resource{
+		Type: "k8s",
+		Labels: map[string]string{
+			"service.name":           "cart",
+			"deployment.environment": "staging",
+		},
+	}
+	spanResource := &resourcepb.Resource{Labels: map[string]string{"region": "eu-west-1"}}
+	td := consumerdata.TraceData{
+		Resource: original,
+		Spans: []*tracepb.Span{
+			{Name: &tracepb.TruncatableString{Value: "own resource"}, Resource: spanResource},
+			{Name: &tracepb.TruncatableString{Value: "batch resource"}},
+		},
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), td))
+
+	got := sink.AllTraces()
+	require.Len(t, got, 1)
+	assert.Equal(t, &resourcepb.Resource{
+		Type: "k8s",
+		Labels: map[string]string{
+			"service.name":           "cart",
+			"deployment.environment": "production",
+			"region":                 "us-east-1",
+		},
+	}, got[0].Resource)
+	assert.Equal(t, &resourcepb.Resource{
+		Type: "host",
+		Labels: map[string]string{
+			"service.name":           "checkout",
+			"deployment.environment": "production",
+			"region":                 "eu-west-1",
+		},
+	}, got[0].Spans[0].Resource)
+	assert.Nil(t, got[0].Spans[1].Resource)
+
+	// The original resources are left untouched.
+	assert.Equal(t, "staging", original.Labels["deployment.environment"])
+	assert.Equal(t, "", spanResource.Type)
+}
+
+func TestResourceProcessor_Metrics(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	cfg := testConfig
+	cfg.OverwriteType = true
+	mp, err := NewMetricsProcessor(sink, cfg)
+	require.NoError(t, err)
+
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{
+			{Resource: &resourcepb.Resource{Type: "k8s"}},
+		},
+	}
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), md))
+
+	got := sink.AllMetrics()
+	require.Len(t, got, 1)
+	want := &resourcepb.Resource{
+		Type: "host",
+		Labels: map[string]string{
+			"service.name":           "checkout",
+			"deployment.environment": "production",
+			"region":                 "us-east-1",
+		},
+	}
+	assert.Equal(t, want, got[0].Resource)
+	assert.Equal(t, want, got[0].Metrics[0].Resource)
+}
@@ -0,0 +1,64 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for Routing processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// From is where the value routed on is read, either "resource-label",
+	// a label of the resource of the batch, or "header", a metadata header of
+	// the request that sent the batch, e.g. a gRPC header.
+	From string `mapstructure:"from"`
+
+	// Key is the name of the resource label or header.
+	Key string `mapstructure:"key"`
+
+	// DefaultExporters are the exporters of the batches whose value isn't
+	// in the table.
+	DefaultExporters []string `mapstructure:"default-exporters"`
+
+	// Table lists the exporters of each value.
+	Table []RouteConfig `mapstructure:"table"`
+}
+
+// RouteConfig is the route of the batches with a value.
+type RouteConfig struct {
+	// Value is the value of the resource label or header.
+	Value string `mapstructure:"value"`
+	// Exporters are the exporters the batches are sent to.
+	Exporters []string `mapstructure:"exporters"`
+	// Headers are added to the metadata of the requests of the batches, the
+	// exporters listing them in their forward-headers send them, e.g. the
+	// credentials of the tenant at its backend.
+	Headers map[string]string `mapstructure:"headers"`
+	// RateLimit limits the number of spans or metrics of the route, the
+	// batches exceeding it are refused.
+	RateLimit *RateLimitConfig `mapstructure:"rate-limit"`
+}
+
+// RateLimitConfig is the rate limit of a route.
+type RateLimitConfig struct {
+	// ItemsPerSecond is the number of spans, for traces, or metrics, for
+	// metrics, accepted per second.
+	ItemsPerSecond float64 `mapstructure:"items-per-second"`
+	// Burst is the number of items that can be accepted at once, it defaults
+	// to ItemsPerSecond.
+	Burst int `mapstructure:"burst"`
+}
@@ -0,0 +1,271 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package routingprocessor implements a processor sending each batch to the
+// exporters of the value of one of its resource labels or request headers,
+// e.g. the tenant of multi-tenant data.
+package routingprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/internal/ratelimit"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/multiconsumer"
+)
+
+const (
+	fromResourceLabel = "resource-label"
+	fromHeader        = "header"
+)
+
+// router holds the routing configuration shared by the trace and metrics
+// processors.
+type router struct {
+	from             string
+	key              string
+	defaultExporters []string
+	table            map[string]*route
+}
+
+// route is the route of the batches with a value.
+type route struct {
+	exporters []string
+	// headers are added to the metadata of the client of the batches.
+	headers map[string][]string
+	// limiter is nil if the route has no rate limit.
+	limiter *ratelimit.Limiter
+}
+
+func newRouter(cfg Config) (*router, error) {
+	switch cfg.From {
+	case fromResourceLabel, fromHeader:
+	default:
+		return nil, fmt.Errorf("from must be either %q or %q, got %q", fromResourceLabel, fromHeader, cfg.From)
+	}
+	if cfg.Key == "" {
+		return nil, errors.New("key must be set")
+	}
+	if len(cfg.DefaultExporters) == 0 {
+		return nil, errors.New("default-exporters must be set")
+	}
+
+	r := &router{
+		from:             cfg.From,
+		key:              cfg.Key,
+		defaultExporters: cfg.DefaultExporters,
+		table:            make(map[string]*route, len(cfg.Table)),
+	}
+	for _, rc := range cfg.Table {
+		if len(rc.Exporters) == 0 {
+			return nil, fmt.Errorf("the route of %q has no exporters", rc.Value)
+		}
+		if _, ok := r.table[rc.Value]; ok {
+			return nil, fmt.Errorf("duplicate route for %q", rc.Value)
+		}
+		rt := &route{exporters: rc.Exporters}
+		if len(rc.Headers) > 0 {
+			rt.headers = make(map[string][]string, len(rc.Headers))
+			for k, v := range rc.Headers {
+				rt.headers[strings.ToLower(k)] = []string{v}
+			}
+		}
+		if rc.RateLimit != nil {
+			if rc.RateLimit.ItemsPerSecond <= 0 {
+				return nil, fmt.Errorf("the rate limit of %q must have positive items-per-second", rc.Value)
+			}
+			rt.limiter = ratelimit.New(rc.RateLimit.ItemsPerSecond, rc.RateLimit.Burst)
+		}
+		r.table[rc.Value] = rt
+	}
+	return r, nil
+}
+
+// RoutedExporters returns the names of all the exporters of the routes.
+func (r *router) RoutedExporters() []string {
+	names := make(map[string]bool)
+	for _, name := range r.defaultExporters {
+		names[name] = true
+	}
+	for _, rt := range r.table {
+		for _, name := range rt.exporters {
+			names[name] = true
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// value returns the value the batch is routed on.
+func (r *router) value(ctx context.Context, resource *resourcepb.Resource) string {
+	if r.from == fromHeader {
+		if c, ok := client.FromContext(ctx); ok {
+			return c.MetadataValue(r.key)
+		}
+		return ""
+	}
+	return resource.GetLabels()[r.key]
+}
+
+// admit applies the rate limit of the route to a batch of the given number of
+// items and returns the context of the batch with the headers of the route.
+func (r *router) admit(ctx context.Context, value string, rt *route, items int) (context.Context, error) {
+	if rt.limiter != nil {
+		if retryAfter, ok := rt.limiter.Take(items); !ok {
+			return ctx, consumererror.Overloaded(fmt.Errorf("the rate limit of %q is exceeded", value), retryAfter)
+		}
+	}
+	if len(rt.headers) == 0 {
+		return ctx, nil
+	}
+
+	c := &client.Client{Metadata: make(map[string][]string)}
+	if orig, ok := client.FromContext(ctx); ok {
+		c.IP = orig.IP
+		for k, v := range orig.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	for k, v := range rt.headers {
+		c.Metadata[k] = v
+	}
+	return client.NewContext(ctx, c), nil
+}
+
+type traceRouter struct {
+	*router
+	defaultRoute consumer.TraceConsumer
+	routes       map[string]consumer.TraceConsumer
+}
+
+var _ processor.TraceProcessor = (*traceRouter)(nil)
+var _ processor.TraceRouter = (*traceRouter)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor sending each batch to
+// the exporters of its route, instead of the next consumer. The exporters
+// are set by the pipeline builder and must be exporters of the pipeline.
+func NewTraceProcessor(cfg Config) (processor.TraceProcessor, error) {
+	r, err := newRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &traceRouter{router: r}, nil
+}
+
+func (tr *traceRouter) SetTraceExporters(exporters map[string]consumer.TraceConsumer) {
+	fanout := func(names []string) consumer.TraceConsumer {
+		// Each exporter that may modify the data gets its own copy of it,
+		// the other ones share the original data.
+		var readOnly, mutating []consumer.TraceConsumer
+		for _, name := range names {
+			exp := exporters[name]
+			if consumer.GetCapabilities(exp).MutatesConsumedData {
+				mutating = append(mutating, exp)
+			} else {
+				readOnly = append(readOnly, exp)
+			}
+		}
+		return multiconsumer.NewTraceProcessorSharing(readOnly, mutating)
+	}
+
+	tr.defaultRoute = fanout(tr.defaultExporters)
+	tr.routes = make(map[string]consumer.TraceConsumer, len(tr.table))
+	for value, rt := range tr.table {
+		tr.routes[value] = fanout(rt.exporters)
+	}
+}
+
+func (tr *traceRouter) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	value := tr.value(ctx, td.Resource)
+	tc, ok := tr.routes[value]
+	if !ok {
+		return tr.defaultRoute.ConsumeTraceData(ctx, td)
+	}
+	ctx, err := tr.admit(ctx, value, tr.table[value], len(td.Spans))
+	if err != nil {
+		return err
+	}
+	return tc.ConsumeTraceData(ctx, td)
+}
+
+type metricsRouter struct {
+	*router
+	defaultRoute consumer.MetricsConsumer
+	routes       map[string]consumer.MetricsConsumer
+}
+
+var _ processor.MetricsProcessor = (*metricsRouter)(nil)
+var _ processor.MetricsRouter = (*metricsRouter)(nil)
+
+// NewMetricsProcessor returns a processor.MetricsProcessor sending each batch
+// to the exporters of its route, instead of the next consumer. The exporters
+// are set by the pipeline builder and must be exporters of the pipeline.
+func NewMetricsProcessor(cfg Config) (processor.MetricsProcessor, error) {
+	r, err := newRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsRouter{router: r}, nil
+}
+
+func (mr *metricsRouter) SetMetricsExporters(exporters map[string]consumer.MetricsConsumer) {
+	fanout := func(names []string) consumer.MetricsConsumer {
+		// Each exporter that may modify the data gets its own copy of it,
+		// the other ones share the original data.
+		var readOnly, mutating []consumer.MetricsConsumer
+		for _, name := range names {
+			exp := exporters[name]
+			if consumer.GetCapabilities(exp).MutatesConsumedData {
+				mutating = append(mutating, exp)
+			} else {
+				readOnly = append(readOnly, exp)
+			}
+		}
+		return multiconsumer.NewMetricsProcessorSharing(readOnly, mutating)
+	}
+
+	mr.defaultRoute = fanout(mr.defaultExporters)
+	mr.routes = make(map[string]consumer.MetricsConsumer, len(mr.table))
+	for value, rt := range mr.table {
+		mr.routes[value] = fanout(rt.exporters)
+	}
+}
+
+func (mr *metricsRouter) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	value := mr.value(ctx, md.Resource)
+	mc, ok := mr.routes[value]
+	if !ok {
+		return mr.defaultRoute.ConsumeMetricsData(ctx, md)
+	}
+	ctx, err := mr.admit(ctx, value, mr.table[value], len(md.Metrics))
+	if err != nil {
+		return err
+	}
+	return mc.ConsumeMetricsData(ctx, md)
+}
@@ -0,0 +1,249 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+)
+
+func TestNewRouter(t *testing.T) {
+	valid := Config{
+		From:             fromResourceLabel,
+		Key:              "tenant",
+		DefaultExporters: []string{"default"},
+		Table: []RouteConfig{
+			{Value: "acme", Exporters: []string{"acme", "default"}},
+			{Value: "globex", Exporters: []string{"globex"}},
+		},
+	}
+	r, err := newRouter(valid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "default", "globex"}, r.RoutedExporters())
+
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"unknown from", func(cfg *Config) { cfg.From = "connection" }},
+		{"no key", func(cfg *Config) { cfg.Key = "" }},
+		{"no default exporters", func(cfg *Config) { cfg.DefaultExporters = nil }},
+		{"route without exporters", func(cfg *Config) { cfg.Table = []RouteConfig{{Value: "acme"}} }},
+		{"duplicate route", func(cfg *Config) { cfg.Table = append(cfg.Table, cfg.Table[0]) }},
+		{"invalid rate limit", func(cfg *Config) { cfg.Table[0].RateLimit = &RateLimitConfig{Burst: 10} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			cfg.Table = append([]RouteConfig(nil), valid.Table...)
+			tt.modify(&cfg)
+			_, err := newRouter(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTraceRouter(t *testing.T) {
+	tp, err := NewTraceProcessor(Config{
+		From:             fromResourceLabel,
+		Key:              "tenant",
+		DefaultExporters: []string{"default"},
+		Table: []RouteConfig{
+			{Value: "acme", Exporters: []string{"acme", "default"}},
+		},
+	})
+	require.NoError(t, err)
+
+	sinks := map[string]*exportertest.SinkTraceExporter{"default": {}, "acme": {}}
+	tp.(processor.TraceRouter).SetTraceExporters(map[string]consumer.TraceConsumer{
+		"default": sinks["default"],
+		"acme":    sinks["acme"],
+	})
+
+	for _, tenant := range []string{"acme", "globex"} {
+		require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+			Resource: &resourcepb.Resource{Labels: map[string]string{"tenant": tenant}},
+		}))
+	}
+	// The batches without the label take the default route.
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+
+	assert.Equal(t, 1, len(sinks["acme"].AllTraces()))
+	assert.Equal(t, 3, len(sinks["default"].AllTraces()))
+}
+
+func TestMetricsRouter(t *testing.T) {
+	mp, err := NewMetricsProcessor(Config{
+		From:             fromHeader,
+		Key:              "X-Tenant",
+		DefaultExporters: []string{"default"},
+		Table: []RouteConfig{
+			{Value: "acme", Exporters: []string{"acme"}},
+		},
+	})
+	require.NoError(t, err)
+
+	sinks := map[string]*exportertest.SinkMetricsExporter{"default": {}, "acme": {}}
+	mp.(processor.MetricsRouter).SetMetricsExporters(map[string]consumer.MetricsConsumer{
+		"default": sinks["default"],
+		"acme":    sinks["acme"],
+	})
+
+	ctx := client.NewContext(context.Background(), &client.Client{
+		Metadata: map[string][]string{"x-tenant": {"acme"}},
+	})
+	require.NoError(t, mp.ConsumeMetricsData(ctx, consumerdata.MetricsData{}))
+	// The header is read from the request, not the resource.
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{Labels: map[string]string{"X-Tenant": "acme"}},
+	}))
+
+	assert.Equal(t, 1, len(sinks["acme"].AllMetrics()))
+	assert.Equal(t, 1, len(sinks["default"].AllMetrics()))
+}
+
+// readOnlyTraceSink is a SinkTraceExporter declaring it doesn't modify the
+// data.
+type readOnlyTraceSink struct {
+	exportertest.SinkTraceExporter
+}
+
+func (s *readOnlyTraceSink) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+func TestRouteSharing(t *testing.T) {
+	tp, err := NewTraceProcessor(Config{
+		From:             fromResourceLabel,
+		Key:              "tenant",
+		DefaultExporters: []string{"read-only", "mutating"},
+	})
+	require.NoError(t, err)
+
+	readOnly := &readOnlyTraceSink{}
+	mutating := &exportertest.SinkTraceExporter{}
+	tp.(processor.TraceRouter).SetTraceExporters(map[string]consumer.TraceConsumer{
+		"read-only": readOnly,
+		"mutating":  mutating,
+	})
+
+	td := consumerdata.TraceData{
+		Spans: []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}}},
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), td))
+
+	// The read-only exporter shares the data, the mutating one gets a copy.
+	require.Equal(t, 1, len(readOnly.AllTraces()))
+	require.Equal(t, 1, len(mutating.AllTraces()))
+	assert.True(t, readOnly.AllTraces()[0].Spans[0] == td.Spans[0])
+	assert.False(t, mutating.AllTraces()[0].Spans[0] == td.Spans[0])
+	assert.Equal(t, td, mutating.AllTraces()[0])
+}
+
+// contextSink records the client of the batches it receives.
+type contextSink struct {
+	clients []*client.Client
+}
+
+func (cs *contextSink) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	c, _ := client.FromContext(ctx)
+	cs.clients = append(cs.clients, c)
+	return nil
+}
+
+func TestRouteHeaders(t *testing.T) {
+	tp, err := NewTraceProcessor(Config{
+		From:             fromHeader,
+		Key:              "X-Tenant",
+		DefaultExporters: []string{"default"},
+		Table: []RouteConfig{
+			{Value: "acme", Exporters: []string{"acme"}, Headers: map[string]string{"Authorization": "Bearer acme"}},
+		},
+	})
+	require.NoError(t, err)
+	sink := &contextSink{}
+	tp.(processor.TraceRouter).SetTraceExporters(map[string]consumer.TraceConsumer{
+		"default": sink,
+		"acme":    sink,
+	})
+
+	orig := &client.Client{
+		IP:       "10.0.0.1",
+		Metadata: map[string][]string{"x-tenant": {"acme"}, "authorization": {"Bearer client"}},
+	}
+	require.NoError(t, tp.ConsumeTraceData(client.NewContext(context.Background(), orig), consumerdata.TraceData{}))
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{}))
+
+	require.Len(t, sink.clients, 2)
+	assert.Equal(t, &client.Client{
+		IP:       "10.0.0.1",
+		Metadata: map[string][]string{"x-tenant": {"acme"}, "authorization": {"Bearer acme"}},
+	}, sink.clients[0])
+	// The client of the request is left unchanged.
+	assert.Equal(t, []string{"Bearer client"}, orig.Metadata["authorization"])
+	// The default route has no headers.
+	assert.Nil(t, sink.clients[1])
+}
+
+func TestRouteRateLimit(t *testing.T) {
+	mp, err := NewMetricsProcessor(Config{
+		From:             fromResourceLabel,
+		Key:              "tenant",
+		DefaultExporters: []string{"default"},
+		Table: []RouteConfig{
+			{Value: "acme", Exporters: []string{"acme"}, RateLimit: &RateLimitConfig{ItemsPerSecond: 1, Burst: 3}},
+		},
+	})
+	require.NoError(t, err)
+	sinks := map[string]*exportertest.SinkMetricsExporter{"default": {}, "acme": {}}
+	mp.(processor.MetricsRouter).SetMetricsExporters(map[string]consumer.MetricsConsumer{
+		"default": sinks["default"],
+		"acme":    sinks["acme"],
+	})
+
+	batch := func(tenant string) consumerdata.MetricsData {
+		return consumerdata.MetricsData{
+			Resource: &resourcepb.Resource{Labels: map[string]string{"tenant": tenant}},
+			Metrics:  []*metricspb.Metric{{}, {}},
+		}
+	}
+	require.NoError(t, mp.ConsumeMetricsData(context.Background(), batch("acme")))
+	err = mp.ConsumeMetricsData(context.Background(), batch("acme"))
+	require.Error(t, err)
+	retryAfter, ok := consumererror.IsOverloaded(err)
+	assert.True(t, ok)
+	assert.True(t, retryAfter > 0 && retryAfter <= time.Second, retryAfter)
+	// The other routes are not limited.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, mp.ConsumeMetricsData(context.Background(), batch("globex")))
+	}
+
+	assert.Equal(t, 1, len(sinks["acme"].AllMetrics()))
+	assert.Equal(t, 5, len(sinks["default"].AllMetrics()))
+}
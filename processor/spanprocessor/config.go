@@ -0,0 +1,56 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+// Config defines configuration for span processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Include and Exclude select the spans that are modified, by default all
+	// of them.
+	Include *filtermatch.MatchProperties `mapstructure:"include"`
+	Exclude *filtermatch.MatchProperties `mapstructure:"exclude"`
+
+	// Rename specifies how the spans are renamed.
+	Rename Name `mapstructure:"name"`
+}
+
+// Name specifies how the spans are renamed, from their attributes or to
+// their attributes. The renaming from attributes is applied first.
+type Name struct {
+	// FromAttributes is a template of the new name, the attribute keys in
+	// braces are replaced by their values, e.g. "{http.method} {http.route}".
+	// The spans without all the attributes are not renamed.
+	FromAttributes string `mapstructure:"from-attributes"`
+
+	// ToAttributes extracts attributes from the span names.
+	ToAttributes *ToAttributes `mapstructure:"to-attributes"`
+}
+
+// ToAttributes specifies how attributes are extracted from the span names.
+type ToAttributes struct {
+	// Rules are regular expressions with named subexpressions, e.g.
+	// "^/api/v1/document/(?P<documentId>.*)/update$". The subexpressions
+	// matching a span name are added as string attributes, and replaced in
+	// the name by their name in braces, e.g. "/api/v1/document/{documentId}/update".
+	// All the rules are applied, in order, each to the name left by the
+	// previous one.
+	Rules []string `mapstructure:"rules"`
+}
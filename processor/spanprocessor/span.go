@@ -0,0 +1,184 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanprocessor implements a processor normalizing the span names:
+// it renames the spans from their attributes, e.g. to remove high cardinality
+// values from the names, and extracts attributes from the names.
+package spanprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+type spanProcessor struct {
+	nextConsumer consumer.TraceConsumer
+	filter       *filtermatch.Filter
+	// template is the parsed name template, nil to not rename from the
+	// attributes.
+	template []templatePart
+	rules    []*regexp.Regexp
+}
+
+// templatePart is either a literal part of the name template or, when key is
+// true, an attribute key.
+type templatePart struct {
+	value string
+	key   bool
+}
+
+var _ processor.TraceProcessor = (*spanProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor renaming the spans
+// according to the given configuration.
+func NewTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+
+	filter, err := filtermatch.NewFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	sp := &spanProcessor{
+		nextConsumer: nextConsumer,
+		filter:       filter,
+	}
+	if cfg.Rename.FromAttributes != "" {
+		if sp.template, err = parseTemplate(cfg.Rename.FromAttributes); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Rename.ToAttributes != nil {
+		if len(cfg.Rename.ToAttributes.Rules) == 0 {
+			return nil, errors.New("to-attributes must have at least one rule")
+		}
+		for _, rule := range cfg.Rename.ToAttributes.Rules {
+			re, err := regexp.Compile(rule)
+			if err != nil {
+				return nil, fmt.Errorf("invalid to-attributes rule %q: %v", rule, err)
+			}
+			sp.rules = append(sp.rules, re)
+		}
+	}
+	return sp, nil
+}
+
+// parseTemplate splits the template in literal parts and attribute keys, in
+// braces.
+func parseTemplate(template string) ([]templatePart, error) {
+	var parts []templatePart
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			parts = append(parts, templatePart{value: template})
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed brace in the from-attributes template %q", template)
+		}
+		end += start
+		if start > 0 {
+			parts = append(parts, templatePart{value: template[:start]})
+		}
+		key := template[start+1 : end]
+		if key == "" {
+			return nil, errors.New("empty attribute key in the from-attributes template")
+		}
+		parts = append(parts, templatePart{value: key, key: true})
+		template = template[end+1:]
+	}
+	return parts, nil
+}
+
+func (sp *spanProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	for _, span := range td.Spans {
+		if span == nil || !sp.filter.MatchSpan(td.Node, td.Resource, span) {
+			continue
+		}
+		sp.renameFromAttributes(span)
+		sp.renameToAttributes(span)
+	}
+	return sp.nextConsumer.ConsumeTraceData(ctx, td)
+}
+
+func (sp *spanProcessor) renameFromAttributes(span *tracepb.Span) {
+	if sp.template == nil {
+		return
+	}
+	attributes := span.GetAttributes().GetAttributeMap()
+	var sb strings.Builder
+	for _, part := range sp.template {
+		if !part.key {
+			sb.WriteString(part.value)
+			continue
+		}
+		value, ok := attributes[part.value]
+		if !ok {
+			return
+		}
+		sb.WriteString(filtermatch.AttributeValueString(value))
+	}
+	span.Name = &tracepb.TruncatableString{Value: sb.String()}
+}
+
+func (sp *spanProcessor) renameToAttributes(span *tracepb.Span) {
+	if span.Name == nil {
+		return
+	}
+	for _, re := range sp.rules {
+		name := span.Name.Value
+		submatches := re.FindStringSubmatchIndex(name)
+		if submatches == nil {
+			continue
+		}
+
+		// Replace the named subexpressions in the name, keeping the rest.
+		var sb strings.Builder
+		last := 0
+		for i, groupName := range re.SubexpNames() {
+			start, end := submatches[2*i], submatches[2*i+1]
+			if i == 0 || groupName == "" || start < last {
+				continue
+			}
+			if span.Attributes == nil {
+				span.Attributes = &tracepb.Span_Attributes{}
+			}
+			if span.Attributes.AttributeMap == nil {
+				span.Attributes.AttributeMap = make(map[string]*tracepb.AttributeValue)
+			}
+			span.Attributes.AttributeMap[groupName] = &tracepb.AttributeValue{
+				Value: &tracepb.AttributeValue_StringValue{
+					StringValue: &tracepb.TruncatableString{Value: name[start:end]},
+				},
+			}
+			sb.WriteString(name[last:start])
+			sb.WriteString("{" + groupName + "}")
+			last = end
+		}
+		sb.WriteString(name[last:])
+		span.Name = &tracepb.TruncatableString{Value: sb.String()}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanprocessor
+
+import (
+	"context"
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+func TestSpanProcessor_FromAttributes(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{
+		Rename: Name{FromAttributes: "{http.method} {http.route}"},
+	})
+	require.NoError(t, err)
+
+	spans := []*tracepb.Span{
+		newSpan("renamed", map[string]*tracepb.AttributeValue{
+			"http.method": stringValue("GET"),
+			"http.route":  stringValue("/users/{id}"),
+		}),
+		newSpan("missing", map[string]*tracepb.AttributeValue{
+			"http.method": stringValue("GET"),
+		}),
+		newSpan("no-attributes", nil),
+		newSpan("non-string", map[string]*tracepb.AttributeValue{
+			"http.method": stringValue("GET"),
+			"http.route":  {Value: &tracepb.AttributeValue_IntValue{IntValue: 1}},
+		}),
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: spans}))
+
+	got := sink.AllTraces()
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"GET /users/{id}", "missing", "no-attributes", "GET 1"}, spanNames(got[0].Spans))
+}
+
+func TestSpanProcessor_ToAttributes(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{
+		Rename: Name{ToAttributes: &ToAttributes{Rules: []string{
+			"^/api/v1/document/(?P<documentId>[^/]*)/.*$",
+			"^.*/(?P<action>update|delete)$",
+		}}},
+	})
+	require.NoError(t, err)
+
+	spans := []*tracepb.Span{
+		newSpan("/api/v1/document/12345678/update", nil),
+		newSpan("/api/v1/user/42", map[string]*tracepb.AttributeValue{
+			"http.method": stringValue("GET"),
+		}),
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: spans}))
+
+	assert.Equal(t, []string{"/api/v1/document/{documentId}/{action}", "/api/v1/user/42"}, spanNames(spans))
+	assert.Equal(t, map[string]*tracepb.AttributeValue{
+		"documentId": stringValue("12345678"),
+		"action":     stringValue("update"),
+	}, spans[0].Attributes.AttributeMap)
+	assert.Equal(t, map[string]*tracepb.AttributeValue{
+		"http.method": stringValue("GET"),
+	}, spans[1].Attributes.AttributeMap)
+}
+
+func TestSpanProcessor_Filter(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{
+		Include: &filtermatch.MatchProperties{Services: []string{"document-service"}},
+		Rename: Name{ToAttributes: &ToAttributes{Rules: []string{
+			"^/document/(?P<documentId>.*)$",
+		}}},
+	})
+	require.NoError(t, err)
+
+	for _, service := range []string{"document-service", "other-service"} {
+		spans := []*tracepb.Span{newSpan("/document/1", nil)}
+		require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+			Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: service}},
+			Spans: spans,
+		}))
+		if service == "document-service" {
+			assert.Equal(t, "/document/{documentId}", spans[0].Name.Value)
+		} else {
+			assert.Equal(t, "/document/1", spans[0].Name.Value)
+		}
+	}
+}
+
+func newSpan(name string, attributes map[string]*tracepb.AttributeValue) *tracepb.Span {
+	span := &tracepb.Span{Name: &tracepb.TruncatableString{Value: name}}
+	if attributes != nil {
+		span.Attributes = &tracepb.Span_Attributes{AttributeMap: attributes}
+	}
+	return span
+}
+
+func stringValue(value string) *tracepb.AttributeValue {
+	return &tracepb.AttributeValue{
+		Value: &tracepb.AttributeValue_StringValue{
+			StringValue: &tracepb.TruncatableString{Value: value},
+		},
+	}
+}
+
+func spanNames(spans []*tracepb.Span) []string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name.Value)
+	}
+	return names
+}
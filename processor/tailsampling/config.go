@@ -34,19 +34,30 @@ const (
 	StringAttributeFilter PolicyType = "string-attribute-filter"
 	// RateLimiting allows all traces until the specified limits are satisfied.
 	RateLimiting PolicyType = "rate-limiting"
+	// Latency sample traces lasting longer than a threshold.
+	Latency PolicyType = "latency"
+	// ErrorStatus sample traces with at least one span with an error status.
+	ErrorStatus PolicyType = "error-status"
+	// And sample traces sampled by all its sub-policies.
+	And PolicyType = "and"
+	// Composite sample traces sampled by its sub-policies, in priority order,
+	// within a spans per second budget shared by the sub-policies.
+	Composite PolicyType = "composite"
 )
 
 // PolicyCfg holds the common configuration to all policies.
 type PolicyCfg struct {
 	// Name given to the instance of the policy to make easy to identify it in metrics and logs.
-	Name string
+	Name string `mapstructure:"name"`
 	// Type of the policy this will be used to match the proper configuration of the policy.
-	Type PolicyType
-	// Exporters hold the name of the exporters that the policy evaluator uses to make decisions
-	// about whether or not sending the traces.
-	Exporters []string
-	// Configuration holds the settings specific to the policy.
-	Configuration interface{}
+	Type PolicyType `mapstructure:"type"`
+	// Configs for the policies with settings, only the one matching Type is used.
+	NumericAttributeFilterCfg NumericAttributeFilterCfg `mapstructure:"numeric-attribute-filter"`
+	StringAttributeFilterCfg  StringAttributeFilterCfg  `mapstructure:"string-attribute-filter"`
+	RateLimitingCfg           RateLimitingCfg           `mapstructure:"rate-limiting"`
+	LatencyCfg                LatencyCfg                `mapstructure:"latency"`
+	AndCfg                    AndCfg                    `mapstructure:"and"`
+	CompositeCfg              CompositeCfg              `mapstructure:"composite"`
 }
 
 // NumericAttributeFilterCfg holds the configurable settings to create a numeric attribute filter
@@ -58,6 +69,8 @@ type NumericAttributeFilterCfg struct {
 	MinValue int64 `mapstructure:"min-value"`
 	// MaxValue is the maximum value of the attribute to be considered a match.
 	MaxValue int64 `mapstructure:"max-value"`
+	// InvertMatch samples the traces without a match instead.
+	InvertMatch bool `mapstructure:"invert-match"`
 }
 
 // StringAttributeFilterCfg holds the configurable settings to create a string attribute filter
@@ -67,6 +80,50 @@ type StringAttributeFilterCfg struct {
 	Key string `mapstructure:"key"`
 	// Values is the set of attribute values that if any is equal to the actual attribute value to be considered a match.
 	Values []string `mapstructure:"values"`
+	// InvertMatch samples the traces without a match instead.
+	InvertMatch bool `mapstructure:"invert-match"`
+}
+
+// RateLimitingCfg holds the configurable settings to create a rate limiting
+// sampling policy evaluator.
+type RateLimitingCfg struct {
+	// SpansPerSecond sets the limit on the maximum number of spans that can be processed each second.
+	SpansPerSecond int64 `mapstructure:"spans-per-second"`
+}
+
+// LatencyCfg holds the configurable settings to create a latency sampling
+// policy evaluator.
+type LatencyCfg struct {
+	// Threshold is the minimum duration of the traces that are sampled.
+	Threshold time.Duration `mapstructure:"threshold"`
+}
+
+// AndCfg holds the configurable settings to create an and sampling policy
+// evaluator.
+type AndCfg struct {
+	// SubPolicyCfgs are the policies that must all sample a trace.
+	SubPolicyCfgs []PolicyCfg `mapstructure:"sub-policies"`
+}
+
+// CompositeCfg holds the configurable settings to create a composite sampling
+// policy evaluator.
+type CompositeCfg struct {
+	// MaxTotalSpansPerSecond is the spans per second budget shared by the sub-policies.
+	MaxTotalSpansPerSecond int64 `mapstructure:"max-total-spans-per-second"`
+	// SubPolicyCfgs are the policies evaluated, in priority order, until one samples the trace.
+	SubPolicyCfgs []PolicyCfg `mapstructure:"sub-policies"`
+	// RateAllocation limits the share of the budget used by some of the
+	// sub-policies, the other ones are only limited by the total budget.
+	RateAllocation []RateAllocationCfg `mapstructure:"rate-allocation"`
+}
+
+// RateAllocationCfg limits the share of the budget of a composite policy used
+// by one of its sub-policies.
+type RateAllocationCfg struct {
+	// Policy is the name of the sub-policy.
+	Policy string `mapstructure:"policy"`
+	// Percent is the percentage of the budget the sub-policy can use.
+	Percent int64 `mapstructure:"percent"`
 }
 
 // Config holds the configuration for tail-based sampling.
@@ -81,4 +138,7 @@ type Config struct {
 	// ExpectedNewTracesPerSec sets the expected number of new traces sending to the tail sampling processor
 	// per second. This helps with allocating data structures with closer to actual usage size.
 	ExpectedNewTracesPerSec uint64 `mapstructure:"expected-new-traces-per-sec"`
+	// PolicyCfgs sets the tail-based sampling policies, a trace is sampled
+	// if any of the policies samples it.
+	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
 }
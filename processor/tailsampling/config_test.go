@@ -50,5 +50,71 @@ func TestLoadConfig(t *testing.T) {
 			DecisionWait:            31 * time.Second,
 			NumTraces:               20001,
 			ExpectedNewTracesPerSec: 100,
+			PolicyCfgs: []PolicyCfg{
+				{
+					Name: "test-policy-1",
+					Type: AlwaysSample,
+				},
+				{
+					Name:                      "test-policy-2",
+					Type:                      NumericAttributeFilter,
+					NumericAttributeFilterCfg: NumericAttributeFilterCfg{Key: "key1", MinValue: 50, MaxValue: 100},
+				},
+				{
+					Name:                     "test-policy-3",
+					Type:                     StringAttributeFilter,
+					StringAttributeFilterCfg: StringAttributeFilterCfg{Key: "key2", Values: []string{"value1", "value2"}},
+				},
+				{
+					Name:            "test-policy-4",
+					Type:            RateLimiting,
+					RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 35},
+				},
+				{
+					Name:       "test-policy-5",
+					Type:       Latency,
+					LatencyCfg: LatencyCfg{Threshold: 5 * time.Second},
+				},
+				{
+					Name: "test-policy-6",
+					Type: ErrorStatus,
+				},
+				{
+					Name: "test-policy-7",
+					Type: And,
+					AndCfg: AndCfg{
+						SubPolicyCfgs: []PolicyCfg{
+							{
+								Name:       "test-and-policy-1",
+								Type:       Latency,
+								LatencyCfg: LatencyCfg{Threshold: time.Second},
+							},
+							{
+								Name: "test-and-policy-2",
+								Type: StringAttributeFilter,
+								StringAttributeFilterCfg: StringAttributeFilterCfg{
+									Key:         "key3",
+									Values:      []string{"value3"},
+									InvertMatch: true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "test-policy-8",
+					Type: Composite,
+					CompositeCfg: CompositeCfg{
+						MaxTotalSpansPerSecond: 1000,
+						SubPolicyCfgs: []PolicyCfg{
+							{Name: "test-composite-policy-1", Type: ErrorStatus},
+							{Name: "test-composite-policy-2", Type: AlwaysSample},
+						},
+						RateAllocation: []RateAllocationCfg{
+							{Policy: "test-composite-policy-1", Percent: 80},
+						},
+					},
+				},
+			},
 		})
 }
@@ -42,6 +42,10 @@ func (f *Factory) Type() string {
 // CreateDefaultConfig creates the default configuration for processor.
 func (f *Factory) CreateDefaultConfig() configmodels.Processor {
 	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
 		DecisionWait: 30 * time.Second,
 		NumTraces:    50000,
 	}
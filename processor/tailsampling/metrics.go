@@ -40,6 +40,8 @@ var (
 	statDroppedTooEarlyCount    = stats.Int64("sampling_trace_dropped_too_early", "Count of traces that needed to be dropped the configured wait time", stats.UnitDimensionless)
 	statNewTraceIDReceivedCount = stats.Int64("new_trace_id_received", "Counts the arrival of new traces", stats.UnitDimensionless)
 	statTracesOnMemoryGauge     = stats.Int64("sampling_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+
+	statDroppedOverMemoryBudgetCount = stats.Int64("sampling_spans_dropped_over_memory_budget", "Count of spans dropped because the memory budget was exhausted", stats.UnitDimensionless)
 )
 
 // SamplingProcessorMetricViews return the metrics views according to given telemetry level.
@@ -114,6 +116,12 @@ func SamplingProcessorMetricViews(level telemetry.Level) []*view.View {
 		Description: statTracesOnMemoryGauge.Description(),
 		Aggregation: view.LastValue(),
 	}
+	countSpansDroppedOverMemoryBudgetView := &view.View{
+		Name:        statDroppedOverMemoryBudgetCount.Name(),
+		Measure:     statDroppedOverMemoryBudgetCount,
+		Description: statDroppedOverMemoryBudgetCount.Description(),
+		Aggregation: view.Sum(),
+	}
 
 	return []*view.View{
 		decisionLatencyView,
@@ -129,5 +137,6 @@ func SamplingProcessorMetricViews(level telemetry.Level) []*view.View {
 		countTraceDroppedTooEarlyView,
 		countTraceIDArrivalView,
 		trackTracesOnMemorylView,
+		countSpansDroppedOverMemoryBudgetView,
 	}
 }
@@ -17,32 +17,33 @@ package tailsampling
 import (
 	"context"
 	"errors"
+	"fmt"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor/idbatcher"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/sampling"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 )
 
-// Policy combines a sampling policy evaluator with the destinations to be
-// used for that policy.
+// Policy combines a sampling policy evaluator with its name.
 type Policy struct {
 	// Name used to identify this policy instance.
 	Name string
 	// Evaluator that decides if a trace is sampled or not by this policy instance.
 	Evaluator sampling.PolicyEvaluator
-	// Destination is the consumer of the traces selected to be sampled.
-	Destination consumer.TraceConsumer
 	// ctx used to carry metric tags of each policy.
 	ctx context.Context
 }
@@ -58,6 +59,8 @@ type tailSamplingSpanProcessor struct {
 	nextConsumer    consumer.TraceConsumer
 	start           sync.Once
 	maxNumTraces    uint64
+	cfg             Config
+	policiesMu      sync.RWMutex
 	policies        []*Policy
 	logger          *zap.Logger
 	idToTrace       sync.Map
@@ -65,9 +68,11 @@ type tailSamplingSpanProcessor struct {
 	decisionBatcher idbatcher.Batcher
 	deleteChan      chan traceKey
 	numTracesOnMap  uint64
+	account         *memorybudget.Account
 }
 
 var _ processor.TraceProcessor = (*tailSamplingSpanProcessor)(nil)
+var _ processor.Tunable = (*tailSamplingSpanProcessor)(nil)
 
 // NewTraceProcessor returns a processor.TraceProcessor that will perform tail sampling according to the given
 // configuration.
@@ -82,26 +87,185 @@ func NewTraceProcessor(logger *zap.Logger, nextConsumer consumer.TraceConsumer,
 		return nil, err
 	}
 
+	policies, err := getPolicies(cfg.PolicyCfgs)
+	if err != nil {
+		return nil, err
+	}
+
 	tsp := &tailSamplingSpanProcessor{
 		ctx:             context.Background(),
 		nextConsumer:    nextConsumer,
 		maxNumTraces:    cfg.NumTraces,
+		cfg:             cfg,
+		policies:        policies,
 		logger:          logger,
 		decisionBatcher: inBatcher,
+		account:         memorybudget.Default().Register("processor/" + cfg.Name()),
 	}
 
-	// TODO(#146): add policies to TailBasedCfg
 	tsp.policyTicker = &policyTicker{onTick: tsp.samplingPolicyOnTick}
 	tsp.deleteChan = make(chan traceKey, cfg.NumTraces)
 
 	return tsp, nil
 }
 
+func getPolicies(cfgs []PolicyCfg) ([]*Policy, error) {
+	var policies []*Policy
+	for i := range cfgs {
+		policyCfg := &cfgs[i]
+		policyCtx, err := tag.New(context.Background(), tag.Upsert(tagPolicyKey, policyCfg.Name))
+		if err != nil {
+			return nil, err
+		}
+		eval, err := getPolicyEvaluator(policyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %v", policyCfg.Name, err)
+		}
+		policies = append(policies, &Policy{
+			Name:      policyCfg.Name,
+			Evaluator: eval,
+			ctx:       policyCtx,
+		})
+	}
+	return policies, nil
+}
+
+// Tune replaces the policies of the processor by the ones of the given
+// configuration, see processor.Tunable. The other settings can't be changed.
+// The traces waiting for a decision are evaluated by the new policies, the
+// spans of the traces already decided arriving late are only reported to the
+// policies if they did not change.
+func (tsp *tailSamplingSpanProcessor) Tune(cfg configmodels.Processor) error {
+	tCfg, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("unexpected configuration type %T", cfg)
+	}
+	if tCfg.DecisionWait != tsp.cfg.DecisionWait ||
+		tCfg.NumTraces != tsp.cfg.NumTraces ||
+		tCfg.ExpectedNewTracesPerSec != tsp.cfg.ExpectedNewTracesPerSec {
+		return errors.New("only the policies can be changed while the processor runs")
+	}
+	policies, err := getPolicies(tCfg.PolicyCfgs)
+	if err != nil {
+		return err
+	}
+	tsp.policiesMu.Lock()
+	tsp.policies = policies
+	tsp.policiesMu.Unlock()
+	return nil
+}
+
+// currentPolicies returns the policies the traces are evaluated by.
+func (tsp *tailSamplingSpanProcessor) currentPolicies() []*Policy {
+	tsp.policiesMu.RLock()
+	defer tsp.policiesMu.RUnlock()
+	return tsp.policies
+}
+
+func getPolicyEvaluator(cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case AlwaysSample:
+		return sampling.NewAlwaysSample(), nil
+	case NumericAttributeFilter:
+		nafCfg := cfg.NumericAttributeFilterCfg
+		eval := sampling.NewNumericAttributeFilter(nafCfg.Key, nafCfg.MinValue, nafCfg.MaxValue)
+		if nafCfg.InvertMatch {
+			eval = sampling.NewInvert(eval)
+		}
+		return eval, nil
+	case StringAttributeFilter:
+		safCfg := cfg.StringAttributeFilterCfg
+		eval := sampling.NewStringAttributeFilter(safCfg.Key, safCfg.Values)
+		if safCfg.InvertMatch {
+			eval = sampling.NewInvert(eval)
+		}
+		return eval, nil
+	case RateLimiting:
+		rlCfg := cfg.RateLimitingCfg
+		return sampling.NewRateLimiting(rlCfg.SpansPerSecond), nil
+	case Latency:
+		return sampling.NewLatency(cfg.LatencyCfg.Threshold), nil
+	case ErrorStatus:
+		return sampling.NewErrorStatus(), nil
+	case And:
+		subPolicies, err := getSubPolicyEvaluators(cfg.AndCfg.SubPolicyCfgs)
+		if err != nil {
+			return nil, err
+		}
+		return sampling.NewAnd(subPolicies), nil
+	case Composite:
+		return getCompositeEvaluator(&cfg.CompositeCfg)
+	default:
+		return nil, fmt.Errorf("unknown sampling policy type %q", cfg.Type)
+	}
+}
+
+func getSubPolicyEvaluators(cfgs []PolicyCfg) ([]sampling.PolicyEvaluator, error) {
+	if len(cfgs) == 0 {
+		return nil, errors.New("no sub-policies")
+	}
+	evals := make([]sampling.PolicyEvaluator, 0, len(cfgs))
+	for i := range cfgs {
+		eval, err := getPolicyEvaluator(&cfgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("sub-policy %q: %v", cfgs[i].Name, err)
+		}
+		evals = append(evals, eval)
+	}
+	return evals, nil
+}
+
+func getCompositeEvaluator(cfg *CompositeCfg) (sampling.PolicyEvaluator, error) {
+	if cfg.MaxTotalSpansPerSecond <= 0 {
+		return nil, errors.New("max-total-spans-per-second must be greater than zero")
+	}
+	evals, err := getSubPolicyEvaluators(cfg.SubPolicyCfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	percents := make(map[string]int64, len(cfg.RateAllocation))
+	var totalPercent int64
+	for _, allocation := range cfg.RateAllocation {
+		if allocation.Percent <= 0 {
+			return nil, fmt.Errorf("rate allocation of sub-policy %q must be greater than zero", allocation.Policy)
+		}
+		percents[allocation.Policy] = allocation.Percent
+		totalPercent += allocation.Percent
+	}
+	if totalPercent > 100 {
+		return nil, errors.New("rate allocations exceed 100 percent")
+	}
+
+	params := make([]sampling.SubPolicyEvalParams, 0, len(evals))
+	for i, eval := range evals {
+		name := cfg.SubPolicyCfgs[i].Name
+		var maxSpansPerSecond int64
+		if percent, ok := percents[name]; ok {
+			maxSpansPerSecond = cfg.MaxTotalSpansPerSecond * percent / 100
+			if maxSpansPerSecond == 0 {
+				// Zero would mean no limit besides the total budget.
+				maxSpansPerSecond = 1
+			}
+			delete(percents, name)
+		}
+		params = append(params, sampling.SubPolicyEvalParams{
+			Evaluator:         eval,
+			MaxSpansPerSecond: maxSpansPerSecond,
+		})
+	}
+	for name := range percents {
+		return nil, fmt.Errorf("rate allocation of unknown sub-policy %q", name)
+	}
+	return sampling.NewComposite(cfg.MaxTotalSpansPerSecond, params), nil
+}
+
 func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	var idNotFoundOnMapCount, evaluateErrorCount, decisionSampled, decisionNotSampled int64
 	startTime := time.Now()
 	batch, _ := tsp.decisionBatcher.CloseCurrentAndTakeFirstBatch()
 	batchLen := len(batch)
+	policies := tsp.currentPolicies()
 	tsp.logger.Debug("Sampling Policy Evaluation ticked")
 	for _, id := range batch {
 		d, ok := tsp.idToTrace.Load(traceKey(id))
@@ -110,21 +274,24 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 			continue
 		}
 		trace := d.(*sampling.TraceData)
-		trace.DecisionTime = time.Now()
-		for i, policy := range tsp.policies {
+		// The decisions are published under the lock with the final one, the
+		// late arriving spans read them concurrently.
+		decisions := pendingDecisions(len(policies))
+		finalDecision := sampling.NotSampled
+		for i, policy := range policies {
 			policyEvaluateStartTime := time.Now()
 			decision, err := policy.Evaluator.Evaluate(id, trace)
 			stats.Record(
 				policy.ctx,
 				statDecisionLatencyMicroSec.M(int64(time.Since(policyEvaluateStartTime)/time.Microsecond)))
 			if err != nil {
-				trace.Decision[i] = sampling.NotSampled
+				decisions[i] = sampling.NotSampled
 				evaluateErrorCount++
 				tsp.logger.Error("Sampling policy error", zap.Error(err))
 				continue
 			}
 
-			trace.Decision[i] = decision
+			decisions[i] = decision
 
 			switch decision {
 			case sampling.Sampled:
@@ -133,29 +300,39 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 					[]tag.Mutator{tag.Insert(tagSampledKey, "true")},
 					statCountTracesSampled.M(int64(1)),
 				)
-				decisionSampled++
-
-				trace.Lock()
-				traceBatches := trace.ReceivedBatches
-				trace.Unlock()
-
-				for j := 0; j < len(traceBatches); j++ {
-					policy.Destination.ConsumeTraceData(policy.ctx, traceBatches[j])
-				}
+				finalDecision = sampling.Sampled
 			case sampling.NotSampled:
 				stats.RecordWithTags(
 					policy.ctx,
 					[]tag.Mutator{tag.Insert(tagSampledKey, "false")},
 					statCountTracesSampled.M(int64(1)),
 				)
-				decisionNotSampled++
 			}
 		}
 
-		// Sampled or not, remove the batches
+		// Sampled or not, remove the batches. The spans arriving from now on
+		// are handled according to the final decision.
 		trace.Lock()
+		trace.Decision = decisions
+		trace.DecisionTime = time.Now()
+		trace.FinalDecision = finalDecision
+		traceBatches := trace.ReceivedBatches
 		trace.ReceivedBatches = nil
+		tsp.account.Release(trace.ReservedBytes)
+		trace.ReservedBytes = 0
 		trace.Unlock()
+
+		// The trace is forwarded once, even if several policies sampled it.
+		if finalDecision == sampling.Sampled {
+			decisionSampled++
+			for j := 0; j < len(traceBatches); j++ {
+				if err := tsp.nextConsumer.ConsumeTraceData(tsp.ctx, traceBatches[j]); err != nil {
+					tsp.logger.Warn("Error sending sampled trace to the next consumer", zap.Error(err))
+				}
+			}
+		} else {
+			decisionNotSampled++
+		}
 	}
 
 	stats.Record(tsp.ctx,
@@ -191,19 +368,21 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 		idToSpans[traceKey] = append(idToSpans[traceKey], span)
 	}
 
-	var newTraceIDs int64
+	var newTraceIDs, droppedOverBudget int64
 	singleTrace := len(idToSpans) == 1
 	for id, spans := range idToSpans {
 		lenSpans := int64(len(spans))
-		lenPolicies := len(tsp.policies)
-		initialDecisions := make([]sampling.Decision, lenPolicies)
-		for i := 0; i < lenPolicies; i++ {
-			initialDecisions[i] = sampling.Pending
+		var bytes int64
+		if tsp.account.Enabled() {
+			for _, span := range spans {
+				bytes += int64(proto.Size(span))
+			}
 		}
 		initialTraceData := &sampling.TraceData{
-			Decision:    initialDecisions,
-			ArrivalTime: time.Now(),
-			SpanCount:   lenSpans,
+			Decision:      pendingDecisions(len(tsp.currentPolicies())),
+			FinalDecision: sampling.Pending,
+			ArrivalTime:   time.Now(),
+			SpanCount:     lenSpans,
 		}
 		d, loaded := tsp.idToTrace.LoadOrStore(traceKey(id), initialTraceData)
 
@@ -227,46 +406,53 @@ func (tsp *tailSamplingSpanProcessor) ConsumeTraceData(ctx context.Context, td c
 			}
 		}
 
-		for i, policyAndDests := range tsp.policies {
-			actualData.Lock()
-			actualDecision := actualData.Decision[i]
-			// If decision is pending, we want to add the new spans still under the lock, so the decision doesn't happen
-			// in between the transition from pending.
-			if actualDecision == sampling.Pending {
-				// Add the spans to the trace, but only once for all policies, otherwise same spans will
-				// be duplicated in the final trace.
+		actualData.Lock()
+		finalDecision := actualData.FinalDecision
+		// The decisions are replaced, never modified, once published.
+		decisions := actualData.Decision
+		decisionTime := actualData.DecisionTime
+		// If the decision is pending, we want to add the new spans still under the lock, so the decision doesn't
+		// happen in between the transition from pending.
+		if finalDecision == sampling.Pending {
+			// Spans exceeding the memory budget are dropped, as if they
+			// never arrived.
+			if bytes == 0 || tsp.account.Reserve(bytes) {
 				traceTd := prepareTraceBatch(spans, singleTrace, td)
 				actualData.ReceivedBatches = append(actualData.ReceivedBatches, traceTd)
-				actualData.Unlock()
-				break
+				actualData.ReservedBytes += bytes
+			} else {
+				droppedOverBudget += lenSpans
 			}
 			actualData.Unlock()
+			continue
+		}
+		actualData.Unlock()
 
-			switch actualDecision {
-			case sampling.Pending:
-				// All process for pending done above, keep the case so it doesn't go to default.
-			case sampling.Sampled:
-				// Forward the spans to the policy destinations
-				traceTd := prepareTraceBatch(spans, singleTrace, td)
-				if err := policyAndDests.Destination.ConsumeTraceData(policyAndDests.ctx, traceTd); err != nil {
-					tsp.logger.Warn("Error sending late arrived spans to destination",
-						zap.String("policy", policyAndDests.Name),
-						zap.Error(err))
-				}
-				fallthrough // so OnLateArrivingSpans is also called for decision Sampled.
-			case sampling.NotSampled:
-				policyAndDests.Evaluator.OnLateArrivingSpans(actualDecision, spans)
-				stats.Record(tsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(actualData.DecisionTime)/time.Second)))
-
-			default:
-				tsp.logger.Warn("Encountered unexpected sampling decision",
-					zap.String("policy", policyAndDests.Name),
-					zap.Int("decision", int(actualDecision)))
+		if finalDecision == sampling.Sampled {
+			// Forward the late arrived spans to the next consumer.
+			traceTd := prepareTraceBatch(spans, singleTrace, td)
+			if err := tsp.nextConsumer.ConsumeTraceData(ctx, traceTd); err != nil {
+				tsp.logger.Warn("Error sending late arrived spans to the next consumer", zap.Error(err))
+			}
+		}
+		policies := tsp.currentPolicies()
+		for i, policy := range policies {
+			if len(decisions) != len(policies) {
+				// The trace was decided by policies tuned since.
+				break
+			}
+			if err := policy.Evaluator.OnLateArrivingSpans(decisions[i], spans); err != nil {
+				tsp.logger.Warn("OnLateArrivingSpans",
+					zap.String("policy", policy.Name),
+					zap.Error(err))
 			}
 		}
+		stats.Record(tsp.ctx, statLateSpanArrivalAfterDecision.M(int64(time.Since(decisionTime)/time.Second)))
 	}
 
-	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
+	stats.Record(tsp.ctx,
+		statNewTraceIDReceivedCount.M(newTraceIDs),
+		statDroppedOverMemoryBudgetCount.M(droppedOverBudget))
 	return nil
 }
 
@@ -282,11 +468,20 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID traceKey, deletionTime t
 		tsp.logger.Error("Attempt to delete traceID not on table")
 		return
 	}
-	policiesLen := len(tsp.policies)
+	trace.Lock()
+	tsp.account.Release(trace.ReservedBytes)
+	trace.ReservedBytes = 0
+	decisions := trace.Decision
+	trace.Unlock()
+	policies := tsp.currentPolicies()
 	stats.Record(tsp.ctx, statTraceRemovalAgeSec.M(int64(deletionTime.Sub(trace.ArrivalTime)/time.Second)))
-	for j := 0; j < policiesLen; j++ {
-		if trace.Decision[j] == sampling.Pending {
-			policy := tsp.policies[j]
+	if len(decisions) != len(policies) {
+		// The trace arrived before the policies were tuned.
+		return
+	}
+	for j := 0; j < len(policies); j++ {
+		if decisions[j] == sampling.Pending {
+			policy := policies[j]
 			if decision, err := policy.Evaluator.OnDroppedSpans([]byte(traceID), trace); err != nil {
 				tsp.logger.Warn("OnDroppedSpans",
 					zap.String("policy", policy.Name),
@@ -297,6 +492,16 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID traceKey, deletionTime t
 	}
 }
 
+// pendingDecisions returns the initial decisions of the given number of
+// policies for a trace.
+func pendingDecisions(lenPolicies int) []sampling.Decision {
+	decisions := make([]sampling.Decision, lenPolicies)
+	for i := range decisions {
+		decisions[i] = sampling.Pending
+	}
+	return decisions
+}
+
 func prepareTraceBatch(spans []*tracepb.Span, singleTrace bool, td consumerdata.TraceData) consumerdata.TraceData {
 	var traceTd consumerdata.TraceData
 	if singleTrace {
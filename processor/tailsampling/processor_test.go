@@ -21,11 +21,13 @@ import (
 	"time"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor/idbatcher"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/sampling"
 	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
@@ -153,8 +155,43 @@ func TestConcurrentTraceMapSize(t *testing.T) {
 	}
 }
 
+func TestTraceMemoryBudget(t *testing.T) {
+	traceIds, batches := generateIdsAndBatches(3)
+	spanSize := int64(proto.Size(batches[0].Spans[0]))
+	budget := memorybudget.Default()
+	budget.SetLimit(3 * spanSize)
+	defer budget.SetLimit(0)
+
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               uint64(2 * len(traceIds)),
+		ExpectedNewTracesPerSec: 64,
+	}
+	sp, _ := NewTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	tsp.policies = []*Policy{{Name: "mock", Evaluator: &mockPolicyEvaluator{}, ctx: context.Background()}}
+	for _, batch := range batches {
+		tsp.ConsumeTraceData(context.Background(), batch)
+	}
+
+	// Only the first three spans fit in the budget.
+	for i, want := range []int{1, 2, 0} {
+		d, _ := tsp.idToTrace.Load(traceKey(traceIds[i]))
+		if got := len(d.(*sampling.TraceData).ReceivedBatches); got != want {
+			t.Errorf("Got %d batches for trace %d, want %d", got, i, want)
+		}
+	}
+	if used := budget.Used(); used != 3*spanSize {
+		t.Errorf("Budget used %d, want %d", used, 3*spanSize)
+	}
+
+	tsp.dropTrace(traceKey(traceIds[1]), time.Now())
+	if used := budget.Used(); used != spanSize {
+		t.Errorf("Budget used %d after dropping a trace, want %d", used, spanSize)
+	}
+}
+
 func TestSamplingPolicyTypicalPath(t *testing.T) {
-	t.Skip("TODO(#146): add policies to TailBasedCfg and fix this test")
 	const maxSize = 100
 	const decisionWaitSeconds = 5
 	msp := &mockSpanProcessor{}
@@ -164,8 +201,9 @@ func TestSamplingPolicyTypicalPath(t *testing.T) {
 		NumTraces:               uint64(maxSize),
 		ExpectedNewTracesPerSec: 64,
 	}
-	sp, _ := NewTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	sp, _ := NewTraceProcessor(zap.NewNop(), msp, cfg)
 	tsp := sp.(*tailSamplingSpanProcessor)
+	tsp.policies = []*Policy{{Name: "mock", Evaluator: mpe, ctx: context.Background()}}
 
 	// For this test  explicitly control the timer calls and batcher.
 	mtt := &manualTTicker{}
@@ -211,6 +249,258 @@ func TestSamplingPolicyTypicalPath(t *testing.T) {
 	}
 }
 
+func TestSamplingPolicyForwardsTraceOnce(t *testing.T) {
+	msp := &mockSpanProcessor{}
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+	}
+	sp, _ := NewTraceProcessor(zap.NewNop(), msp, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	sampled := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	notSampled := &mockPolicyEvaluator{NextDecision: sampling.NotSampled}
+	tsp.policies = []*Policy{
+		{Name: "sampled-1", Evaluator: sampled, ctx: context.Background()},
+		{Name: "not-sampled", Evaluator: notSampled, ctx: context.Background()},
+		{Name: "sampled-2", Evaluator: sampled, ctx: context.Background()},
+	}
+	tsp.policyTicker = &manualTTicker{}
+	tsp.decisionBatcher = newSyncIDBatcher(1)
+
+	_, batches := generateIdsAndBatches(1)
+	tsp.ConsumeTraceData(context.Background(), batches[0])
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	if sampled.EvaluationCount != 2 || notSampled.EvaluationCount != 1 {
+		t.Fatalf("got %d and %d evaluations, want 2 and 1", sampled.EvaluationCount, notSampled.EvaluationCount)
+	}
+	if msp.TotalSpans != 1 {
+		t.Fatalf("got %d spans forwarded, want 1", msp.TotalSpans)
+	}
+
+	// Late spans are also forwarded once.
+	tsp.ConsumeTraceData(context.Background(), batches[0])
+	if msp.TotalSpans != 2 {
+		t.Fatalf("got %d spans forwarded, want 2", msp.TotalSpans)
+	}
+	if notSampled.LateArrivingSpansCount != 1 {
+		t.Fatalf("policy was not notified of the late span")
+	}
+}
+
+func TestSamplingPolicyNotSampled(t *testing.T) {
+	msp := &mockSpanProcessor{}
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+	}
+	sp, _ := NewTraceProcessor(zap.NewNop(), msp, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	tsp.policies = []*Policy{{Name: "mock", Evaluator: &mockPolicyEvaluator{NextDecision: sampling.NotSampled}, ctx: context.Background()}}
+	tsp.policyTicker = &manualTTicker{}
+	tsp.decisionBatcher = newSyncIDBatcher(1)
+
+	_, batches := generateIdsAndBatches(1)
+	tsp.ConsumeTraceData(context.Background(), batches[0])
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	tsp.ConsumeTraceData(context.Background(), batches[0])
+	if msp.TotalSpans != 0 {
+		t.Fatalf("got %d spans forwarded, want 0", msp.TotalSpans)
+	}
+}
+
+func TestLateSpansDuringEvaluation(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+	}
+	sp, _ := NewTraceProcessor(zap.NewNop(), sink, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	tsp.policies = []*Policy{{Name: "always", Evaluator: sampling.NewAlwaysSample(), ctx: context.Background()}}
+	tsp.policyTicker = &manualTTicker{}
+	tsp.decisionBatcher = newSyncIDBatcher(1)
+
+	_, batches := generateIdsAndBatches(10)
+	for _, batch := range batches {
+		tsp.ConsumeTraceData(context.Background(), batch)
+	}
+
+	// The late spans arrive while their traces are evaluated.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, batch := range batches {
+			tsp.ConsumeTraceData(context.Background(), batch)
+		}
+	}()
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	wg.Wait()
+
+	if got, want := sink.SpansCount(), 2*len(batches); got != want {
+		t.Fatalf("got %d spans forwarded, want %d", got, want)
+	}
+}
+
+func TestTune(t *testing.T) {
+	msp := &mockSpanProcessor{}
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs: []PolicyCfg{{
+			Name:                     "string",
+			Type:                     StringAttributeFilter,
+			StringAttributeFilterCfg: StringAttributeFilterCfg{Key: "missing", Values: []string{"value"}},
+		}},
+	}
+	sp, _ := NewTraceProcessor(zap.NewNop(), msp, cfg)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	tsp.policyTicker = &manualTTicker{}
+	tsp.decisionBatcher = newSyncIDBatcher(1)
+
+	_, batches := generateIdsAndBatches(1)
+	tsp.ConsumeTraceData(context.Background(), batches[0])
+
+	// Only the policies can be tuned.
+	tuned := cfg
+	tuned.NumTraces = 200
+	if err := tsp.Tune(&tuned); err == nil {
+		t.Fatal("Tune() changing num-traces, want error")
+	}
+	tuned = cfg
+	tuned.PolicyCfgs = []PolicyCfg{{Name: "unknown", Type: "unknown"}}
+	if err := tsp.Tune(&tuned); err == nil {
+		t.Fatal("Tune() with an unknown policy type, want error")
+	}
+	if got := tsp.currentPolicies()[0].Name; got != "string" {
+		t.Fatalf("got policy %q after a failed Tune(), want %q", got, "string")
+	}
+
+	// The pending trace is evaluated by the new policies.
+	tuned.PolicyCfgs = []PolicyCfg{{Name: "always", Type: AlwaysSample}, {Name: "errors", Type: ErrorStatus}}
+	if err := tsp.Tune(&tuned); err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+	if msp.TotalSpans != 1 {
+		t.Fatalf("got %d spans forwarded, want 1", msp.TotalSpans)
+	}
+}
+
+func TestNewTraceProcessorPolicies(t *testing.T) {
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs: []PolicyCfg{
+			{Name: "always", Type: AlwaysSample},
+			{Name: "numeric", Type: NumericAttributeFilter},
+			{Name: "string", Type: StringAttributeFilter},
+			{Name: "rate", Type: RateLimiting},
+			{Name: "latency", Type: Latency},
+			{Name: "errors", Type: ErrorStatus},
+		},
+	}
+	sp, err := NewTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+	if err != nil {
+		t.Fatalf("NewTraceProcessor() error = %v", err)
+	}
+	if got := len(sp.(*tailSamplingSpanProcessor).policies); got != len(cfg.PolicyCfgs) {
+		t.Fatalf("got %d policies, want %d", got, len(cfg.PolicyCfgs))
+	}
+
+	cfg.PolicyCfgs = []PolicyCfg{{Name: "unknown", Type: "unknown"}}
+	if _, err := NewTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg); err == nil {
+		t.Fatal("NewTraceProcessor() with an unknown policy type, want error")
+	}
+}
+
+func TestNewTraceProcessorCombinedPolicies(t *testing.T) {
+	always := PolicyCfg{Name: "always", Type: AlwaysSample}
+	tests := []struct {
+		name      string
+		policyCfg PolicyCfg
+		wantErr   bool
+	}{
+		{
+			name: "and",
+			policyCfg: PolicyCfg{Type: And, AndCfg: AndCfg{
+				SubPolicyCfgs: []PolicyCfg{always, {Name: "errors", Type: ErrorStatus}},
+			}},
+		},
+		{
+			name:      "and_no_sub_policies",
+			policyCfg: PolicyCfg{Type: And},
+			wantErr:   true,
+		},
+		{
+			name: "and_invalid_sub_policy",
+			policyCfg: PolicyCfg{Type: And, AndCfg: AndCfg{
+				SubPolicyCfgs: []PolicyCfg{{Name: "unknown", Type: "unknown"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "composite",
+			policyCfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs:          []PolicyCfg{always},
+				RateAllocation:         []RateAllocationCfg{{Policy: "always", Percent: 50}},
+			}},
+		},
+		{
+			name: "composite_no_budget",
+			policyCfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				SubPolicyCfgs: []PolicyCfg{always},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "composite_over_allocated",
+			policyCfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs:          []PolicyCfg{always, {Name: "errors", Type: ErrorStatus}},
+				RateAllocation: []RateAllocationCfg{
+					{Policy: "always", Percent: 50},
+					{Policy: "errors", Percent: 60},
+				},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "composite_unknown_allocation",
+			policyCfg: PolicyCfg{Type: Composite, CompositeCfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 100,
+				SubPolicyCfgs:          []PolicyCfg{always},
+				RateAllocation:         []RateAllocationCfg{{Policy: "errors", Percent: 50}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				DecisionWait:            defaultTestDecisionWait,
+				NumTraces:               100,
+				ExpectedNewTracesPerSec: 64,
+				PolicyCfgs:              []PolicyCfg{tt.policyCfg},
+			}
+			_, err := NewTraceProcessor(zap.NewNop(), &exportertest.SinkTraceExporter{}, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTraceProcessor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func generateIdsAndBatches(numIds int) ([][]byte, []consumerdata.TraceData) {
 	traceIds := make([][]byte, numIds)
 	for i := 0; i < numIds; i++ {
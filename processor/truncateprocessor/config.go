@@ -0,0 +1,43 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truncateprocessor
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+// Config defines configuration for the truncate processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Include and Exclude select the spans that are truncated, by default all
+	// of them.
+	Include *filtermatch.MatchProperties `mapstructure:"include"`
+	Exclude *filtermatch.MatchProperties `mapstructure:"exclude"`
+
+	// MaxSpanNameLength is the maximum length of the span names in bytes, 0
+	// to not truncate them.
+	MaxSpanNameLength int `mapstructure:"max-span-name-length"`
+
+	// MaxAttributeLength is the maximum length of the string attribute values
+	// of the spans, of their annotations and of their links in bytes, 0 to not
+	// truncate them.
+	MaxAttributeLength int `mapstructure:"max-attribute-length"`
+
+	// TruncatedAttribute is the key of the boolean attribute set to true on
+	// the truncated spans, empty to not set it.
+	TruncatedAttribute string `mapstructure:"truncated-attribute"`
+}
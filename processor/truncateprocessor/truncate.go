@@ -0,0 +1,136 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package truncateprocessor implements a processor truncating the span names
+// and the string attribute values longer than the limits of the backends,
+// rather than having the backends drop the whole spans.
+package truncateprocessor
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+type truncateProcessor struct {
+	nextConsumer       consumer.TraceConsumer
+	filter             *filtermatch.Filter
+	maxSpanNameLength  int
+	maxAttributeLength int
+	truncatedAttribute string
+}
+
+var _ processor.TraceProcessor = (*truncateProcessor)(nil)
+
+// NewTraceProcessor returns a processor.TraceProcessor truncating the spans
+// according to the given configuration.
+func NewTraceProcessor(nextConsumer consumer.TraceConsumer, cfg Config) (processor.TraceProcessor, error) {
+	if nextConsumer == nil {
+		return nil, errors.New("nextConsumer is nil")
+	}
+	if cfg.MaxSpanNameLength < 0 || cfg.MaxAttributeLength < 0 {
+		return nil, errors.New("max-span-name-length and max-attribute-length must not be negative")
+	}
+	if cfg.MaxSpanNameLength == 0 && cfg.MaxAttributeLength == 0 {
+		return nil, errors.New("at least one of max-span-name-length and max-attribute-length must be set")
+	}
+
+	filter, err := filtermatch.NewFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &truncateProcessor{
+		nextConsumer:       nextConsumer,
+		filter:             filter,
+		maxSpanNameLength:  cfg.MaxSpanNameLength,
+		maxAttributeLength: cfg.MaxAttributeLength,
+		truncatedAttribute: cfg.TruncatedAttribute,
+	}, nil
+}
+
+func (tp *truncateProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	for _, span := range td.Spans {
+		if span == nil || !tp.filter.MatchSpan(td.Node, td.Resource, span) {
+			continue
+		}
+		if tp.truncateSpan(span) && tp.truncatedAttribute != "" {
+			if span.Attributes == nil {
+				span.Attributes = &tracepb.Span_Attributes{}
+			}
+			if span.Attributes.AttributeMap == nil {
+				span.Attributes.AttributeMap = make(map[string]*tracepb.AttributeValue)
+			}
+			span.Attributes.AttributeMap[tp.truncatedAttribute] = &tracepb.AttributeValue{
+				Value: &tracepb.AttributeValue_BoolValue{BoolValue: true},
+			}
+		}
+	}
+	return tp.nextConsumer.ConsumeTraceData(ctx, td)
+}
+
+// truncateSpan truncates the name and the attributes of the span, it returns
+// true if any of them was truncated.
+func (tp *truncateProcessor) truncateSpan(span *tracepb.Span) bool {
+	truncated := truncateString(span.Name, tp.maxSpanNameLength)
+	if tp.maxAttributeLength == 0 {
+		return truncated
+	}
+	if tp.truncateAttributes(span.Attributes) {
+		truncated = true
+	}
+	for _, event := range span.GetTimeEvents().GetTimeEvent() {
+		if tp.truncateAttributes(event.GetAnnotation().GetAttributes()) {
+			truncated = true
+		}
+	}
+	for _, link := range span.GetLinks().GetLink() {
+		if tp.truncateAttributes(link.GetAttributes()) {
+			truncated = true
+		}
+	}
+	return truncated
+}
+
+func (tp *truncateProcessor) truncateAttributes(attrs *tracepb.Span_Attributes) bool {
+	truncated := false
+	for _, value := range attrs.GetAttributeMap() {
+		if truncateString(value.GetStringValue(), tp.maxAttributeLength) {
+			truncated = true
+		}
+	}
+	return truncated
+}
+
+// truncateString truncates the string to at most max bytes, without splitting
+// a UTF-8 encoded rune, and adds the bytes removed to its TruncatedByteCount.
+// It returns true if the string was truncated.
+func truncateString(s *tracepb.TruncatableString, max int) bool {
+	if s == nil || max == 0 || len(s.Value) <= max {
+		return false
+	}
+	end := max
+	for end > 0 && !utf8.RuneStart(s.Value[end]) {
+		end--
+	}
+	s.TruncatedByteCount += int32(len(s.Value) - end)
+	s.Value = s.Value[:end]
+	return true
+}
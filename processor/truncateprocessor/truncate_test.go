@@ -0,0 +1,128 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truncateprocessor
+
+import (
+	"context"
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/processor/filtermatch"
+)
+
+func stringValue(s string) *tracepb.AttributeValue {
+	return &tracepb.AttributeValue{
+		Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: s}},
+	}
+}
+
+func boolValue(b bool) *tracepb.AttributeValue {
+	return &tracepb.AttributeValue{Value: &tracepb.AttributeValue_BoolValue{BoolValue: b}}
+}
+
+func TestTruncateProcessor(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{
+		MaxSpanNameLength:  8,
+		MaxAttributeLength: 4,
+		TruncatedAttribute: "truncated",
+	})
+	require.NoError(t, err)
+
+	spans := []*tracepb.Span{
+		{
+			Name: &tracepb.TruncatableString{Value: "GET /users/{id}"},
+			Attributes: &tracepb.Span_Attributes{AttributeMap: map[string]*tracepb.AttributeValue{
+				"http.method": stringValue("GET"),
+				"http.url":    stringValue("http://example.com"),
+			}},
+			TimeEvents: &tracepb.Span_TimeEvents{TimeEvent: []*tracepb.Span_TimeEvent{{
+				Value: &tracepb.Span_TimeEvent_Annotation_{Annotation: &tracepb.Span_TimeEvent_Annotation{
+					Attributes: &tracepb.Span_Attributes{AttributeMap: map[string]*tracepb.AttributeValue{
+						"message": stringValue("too long"),
+					}},
+				}},
+			}}},
+			Links: &tracepb.Span_Links{Link: []*tracepb.Span_Link{{
+				Attributes: &tracepb.Span_Attributes{AttributeMap: map[string]*tracepb.AttributeValue{
+					"reason": stringValue("retry"),
+				}},
+			}}},
+		},
+		// Only the name is too long.
+		{Name: &tracepb.TruncatableString{Value: "GET /users"}},
+		// A rune is not split.
+		{Name: &tracepb.TruncatableString{Value: "GET /éé"}},
+		{Name: &tracepb.TruncatableString{Value: "short"}},
+		nil,
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: spans}))
+
+	got := sink.AllTraces()
+	require.Len(t, got, 1)
+	span := got[0].Spans[0]
+	assert.Equal(t, &tracepb.TruncatableString{Value: "GET /use", TruncatedByteCount: 7}, span.Name)
+	assert.Equal(t, map[string]*tracepb.AttributeValue{
+		"http.method": stringValue("GET"),
+		"http.url": {Value: &tracepb.AttributeValue_StringValue{
+			StringValue: &tracepb.TruncatableString{Value: "http", TruncatedByteCount: 14},
+		}},
+		"truncated": boolValue(true),
+	}, span.Attributes.AttributeMap)
+	assert.Equal(t, &tracepb.TruncatableString{Value: "too ", TruncatedByteCount: 4},
+		span.TimeEvents.TimeEvent[0].GetAnnotation().Attributes.AttributeMap["message"].GetStringValue())
+	assert.Equal(t, &tracepb.TruncatableString{Value: "retr", TruncatedByteCount: 1},
+		span.Links.Link[0].Attributes.AttributeMap["reason"].GetStringValue())
+
+	assert.Equal(t, "GET /use", got[0].Spans[1].Name.Value)
+	assert.Equal(t, map[string]*tracepb.AttributeValue{"truncated": boolValue(true)}, got[0].Spans[1].Attributes.AttributeMap)
+	assert.Equal(t, &tracepb.TruncatableString{Value: "GET /é", TruncatedByteCount: 2}, got[0].Spans[2].Name)
+	assert.Equal(t, "short", got[0].Spans[3].Name.Value)
+	assert.Nil(t, got[0].Spans[3].Attributes)
+}
+
+func TestTruncateProcessor_Filter(t *testing.T) {
+	sink := &exportertest.SinkTraceExporter{}
+	tp, err := NewTraceProcessor(sink, Config{
+		Include:           &filtermatch.MatchProperties{Services: []string{"document-service"}},
+		MaxSpanNameLength: 3,
+	})
+	require.NoError(t, err)
+
+	newSpans := func() []*tracepb.Span {
+		return []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "GET /"}}}
+	}
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+		Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "document-service"}},
+		Spans: newSpans(),
+	}))
+	require.NoError(t, tp.ConsumeTraceData(context.Background(), consumerdata.TraceData{
+		Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}},
+		Spans: newSpans(),
+	}))
+
+	got := sink.AllTraces()
+	require.Len(t, got, 2)
+	// Without truncated-attribute the truncated spans are not flagged.
+	assert.Equal(t, &tracepb.TruncatableString{Value: "GET", TruncatedByteCount: 2}, got[0].Spans[0].Name)
+	assert.Nil(t, got[0].Spans[0].Attributes)
+	assert.Equal(t, "GET /", got[1].Spans[0].Name.Value)
+}
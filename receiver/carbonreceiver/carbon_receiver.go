@@ -0,0 +1,285 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package carbonreceiver receives the metrics sent with the plaintext
+// protocol of Carbon, the Graphite daemons, over TCP or UDP.
+package carbonreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+var (
+	errNilNextConsumer = errors.New("nil nextConsumer")
+	errAlreadyStarted  = errors.New("already started")
+	errAlreadyStopped  = errors.New("already stopped")
+)
+
+const (
+	metricsSource = "Carbon"
+
+	transportTCP = "tcp"
+	transportUDP = "udp"
+
+	// maxLineSize is the size of the longest line accepted on TCP, the
+	// connections sending longer lines are closed.
+	maxLineSize = 64 * 1024
+	// maxPacketSize is the size of the largest UDP packet.
+	maxPacketSize = 64 * 1024
+)
+
+var _ receiver.MetricsReceiver = (*carbonReceiver)(nil)
+
+// carbonReceiver implements the receiver.MetricsReceiver for the Carbon
+// plaintext protocol.
+type carbonReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	endpoint     string
+	transport    string
+	idleTimeout  time.Duration
+	parser       *pathParser
+	nextConsumer consumer.MetricsConsumer
+
+	listener   net.Listener
+	packetConn net.PacketConn
+	conns      map[net.Conn]struct{}
+	connsMu    sync.Mutex
+	closed     bool
+	wg         sync.WaitGroup
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// New creates the Carbon receiver listening on the endpoint with the
+// transport, "tcp" or "udp".
+func New(
+	logger *zap.Logger,
+	endpoint string,
+	transport string,
+	idleTimeout time.Duration,
+	rules []PathRule,
+	nextConsumer consumer.MetricsConsumer,
+) (receiver.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	if transport != transportTCP && transport != transportUDP {
+		return nil, fmt.Errorf("transport must be %q or %q, got %q", transportTCP, transportUDP, transport)
+	}
+	parser, err := newPathParser(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &carbonReceiver{
+		logger:       logger,
+		endpoint:     endpoint,
+		transport:    transport,
+		idleTimeout:  idleTimeout,
+		parser:       parser,
+		nextConsumer: nextConsumer,
+		conns:        make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// MetricsSource returns the name of the metrics data source.
+func (r *carbonReceiver) MetricsSource() string {
+	return metricsSource
+}
+
+// StartMetricsReception starts listening on the endpoint.
+func (r *carbonReceiver) StartMetricsReception(host receiver.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := errAlreadyStarted
+	r.startOnce.Do(func() {
+		ctx := obsreport.WithTransport(context.Background(), r.transport)
+		if r.transport == transportUDP {
+			r.packetConn, err = net.ListenPacket(transportUDP, r.endpoint)
+			if err != nil {
+				return
+			}
+			r.wg.Add(1)
+			go r.serveUDP(ctx)
+			return
+		}
+
+		r.listener, err = net.Listen(transportTCP, r.endpoint)
+		if err != nil {
+			return
+		}
+		r.wg.Add(1)
+		go r.serveTCP(ctx)
+	})
+
+	return err
+}
+
+// StopMetricsReception closes the listener and the open connections, and
+// waits for the metrics being received to be sent.
+func (r *carbonReceiver) StopMetricsReception() error {
+	r.Lock()
+	defer r.Unlock()
+
+	var err = errAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		switch {
+		case r.listener != nil:
+			err = r.listener.Close()
+			r.connsMu.Lock()
+			r.closed = true
+			for conn := range r.conns {
+				conn.Close()
+			}
+			r.connsMu.Unlock()
+		case r.packetConn != nil:
+			err = r.packetConn.Close()
+		}
+		r.wg.Wait()
+	})
+	return err
+}
+
+func (r *carbonReceiver) serveTCP(ctx context.Context) {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				r.logger.Debug("Failed to accept a Carbon connection", zap.Error(err))
+				continue
+			}
+			// The listener was closed.
+			return
+		}
+
+		r.connsMu.Lock()
+		if r.closed {
+			r.connsMu.Unlock()
+			conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.connsMu.Unlock()
+		go r.handleConn(ctx, conn)
+	}
+}
+
+// handleConn reads the lines of a TCP connection until it is closed by the
+// client, idle or fails. The metrics are sent every time the lines received
+// so far are parsed.
+func (r *carbonReceiver) handleConn(ctx context.Context, conn net.Conn) {
+	defer r.wg.Done()
+	defer func() {
+		r.connsMu.Lock()
+		delete(r.conns, conn)
+		r.connsMu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReaderSize(conn, maxLineSize)
+	var metrics []*metricspb.Metric
+	for {
+		if r.idleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(r.idleTimeout))
+		}
+		line, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			r.logger.Debug("Closing a Carbon connection sending a too long line", zap.Stringer("remote", conn.RemoteAddr()))
+			r.send(ctx, metrics)
+			return
+		}
+
+		// The last line may be sent without a trailing new line before the
+		// connection is closed.
+		if m := r.parseLine(line); m != nil {
+			metrics = append(metrics, m)
+		}
+		if err != nil {
+			r.send(ctx, metrics)
+			return
+		}
+		if reader.Buffered() == 0 {
+			r.send(ctx, metrics)
+			metrics = nil
+		}
+	}
+}
+
+func (r *carbonReceiver) serveUDP(ctx context.Context) {
+	defer r.wg.Done()
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := r.packetConn.ReadFrom(buf)
+		if n > 0 {
+			var metrics []*metricspb.Metric
+			for _, line := range bytes.Split(buf[:n], []byte{'\n'}) {
+				if m := r.parseLine(line); m != nil {
+					metrics = append(metrics, m)
+				}
+			}
+			r.send(ctx, metrics)
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			// The connection was closed.
+			return
+		}
+	}
+}
+
+// parseLine returns the metric of the line, or nil if it is empty or
+// invalid: the invalid lines are dropped, like Carbon does.
+func (r *carbonReceiver) parseLine(line []byte) *metricspb.Metric {
+	m, err := r.parser.parseLine(string(line), time.Now())
+	if err != nil {
+		if err != errEmptyLine {
+			r.logger.Debug("Dropping an invalid Carbon line", zap.Error(err))
+		}
+		return nil
+	}
+	return m
+}
+
+func (r *carbonReceiver) send(ctx context.Context, metrics []*metricspb.Metric) {
+	if len(metrics) == 0 {
+		return
+	}
+	md := consumerdata.MetricsData{Metrics: metrics}
+	if err := r.nextConsumer.ConsumeMetricsData(ctx, md); err != nil {
+		r.logger.Debug("Failed to send the Carbon metrics", zap.Error(err))
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(zap.NewNop(), "localhost:0", transportTCP, 0, nil, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+
+	_, err = New(zap.NewNop(), "localhost:0", "unix", 0, nil, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+
+	r, err := New(zap.NewNop(), "localhost:0", transportTCP, 0, nil, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	assert.Equal(t, metricsSource, r.MetricsSource())
+}
+
+func TestReceiveTCP(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := New(zap.NewNop(), "localhost:0", transportTCP, time.Minute, nil, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.StartMetricsReception(nil))
+	defer r.StopMetricsReception()
+	assert.Equal(t, errAlreadyStarted, r.StartMetricsReception(nil))
+
+	conn, err := net.Dial("tcp", r.(*carbonReceiver).listener.Addr().String())
+	require.NoError(t, err)
+	// The invalid line is dropped, the last line has no trailing new line.
+	_, err = fmt.Fprint(conn, "a.b 1 1567123456\ninvalid\nc.d;host=h1 2 1567123456\ne.f 3 1567123456")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.True(t, waitFor(func() bool { return sink.TimeSeriesCount() == 3 }), "got %d time series", sink.TimeSeriesCount())
+	var names []string
+	for _, md := range sink.AllMetrics() {
+		for _, m := range md.Metrics {
+			names = append(names, m.MetricDescriptor.Name)
+		}
+	}
+	assert.Equal(t, []string{"a.b", "c.d", "e.f"}, names)
+}
+
+func TestReceiveUDP(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := New(zap.NewNop(), "localhost:0", transportUDP, 0, nil, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.StartMetricsReception(nil))
+	defer r.StopMetricsReception()
+
+	conn, err := net.Dial("udp", r.(*carbonReceiver).packetConn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprint(conn, "a.b 1 1567123456\nc.d 2 1567123456\n")
+	require.NoError(t, err)
+
+	require.True(t, waitFor(func() bool { return sink.MetricsCount() == 2 }), "got %d metrics", sink.MetricsCount())
+	// The lines of a packet are sent together.
+	assert.Equal(t, 1, len(sink.AllMetrics()))
+}
+
+func TestStopClosesConnections(t *testing.T) {
+	r, err := New(zap.NewNop(), "localhost:0", transportTCP, 0, nil, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, r.StartMetricsReception(nil))
+
+	conn, err := net.Dial("tcp", r.(*carbonReceiver).listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprint(conn, "a.b 1 1567123456\n")
+	require.NoError(t, err)
+
+	// Stop doesn't wait for the client to close the connection.
+	assert.NoError(t, r.StopMetricsReception())
+	assert.Equal(t, errAlreadyStopped, r.StopMetricsReception())
+}
+
+func TestIdleTimeout(t *testing.T) {
+	r, err := New(zap.NewNop(), "localhost:0", transportTCP, 10*time.Millisecond, nil, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, r.StartMetricsReception(nil))
+	defer r.StopMetricsReception()
+
+	conn, err := net.Dial("tcp", r.(*carbonReceiver).listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	// The receiver closed the idle connection.
+	assert.Error(t, err)
+	if ne, ok := err.(net.Error); ok {
+		assert.False(t, ne.Timeout())
+	}
+}
+
+// waitFor polls the condition for up to 5 seconds.
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 500; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
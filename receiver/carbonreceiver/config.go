@@ -0,0 +1,52 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for the Carbon receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Transport is either "tcp" or "udp".
+	Transport string `mapstructure:"transport"`
+
+	// TCPIdleTimeout is the time after which an idle TCP connection is
+	// closed, zero disables it.
+	TCPIdleTimeout time.Duration `mapstructure:"tcp-idle-timeout"`
+
+	// PathRules extract the metric name and labels from the metric paths. The
+	// first rule whose regexp matches the path applies, the paths matching
+	// no rule are used as the metric name.
+	PathRules []PathRule `mapstructure:"path-rules"`
+}
+
+// PathRule extracts the metric name and labels from the metric paths matched
+// by its regexp: the named groups "key_<label>" are the values of the labels
+// and the named groups "name_<part>" are joined with "." to form the name.
+type PathRule struct {
+	// Regexp matched against the whole metric path.
+	Regexp string `mapstructure:"regexp"`
+
+	// NamePrefix is prepended to the metric name.
+	NamePrefix string `mapstructure:"name-prefix"`
+
+	// MetricType is either "gauge", the default, or "cumulative".
+	MetricType string `mapstructure:"metric-type"`
+}
@@ -0,0 +1,80 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestLoadConfig(t *testing.T) {
+	receivers, processors, exporters, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	receivers[typeStr] = factory
+	cfg, err := config.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), receivers, processors, exporters,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 3)
+
+	r0 := cfg.Receivers["carbon"]
+	assert.Equal(t, r0, factory.CreateDefaultConfig())
+
+	r1 := cfg.Receivers["carbon/udp"].(*Config)
+	assert.Equal(t, r1,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "carbon/udp",
+				Endpoint: "0.0.0.0:2003",
+			},
+			Transport:      transportUDP,
+			TCPIdleTimeout: defaultTCPIdleTimeout,
+		})
+
+	r2 := cfg.Receivers["carbon/rules"].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "carbon/rules",
+				Endpoint: defaultBindEndpoint,
+			},
+			Transport:      transportTCP,
+			TCPIdleTimeout: time.Minute,
+			PathRules: []PathRule{
+				{
+					Regexp:     `(?P<key_env>[^.]+)\.(?P<key_host>[^.]+)\.(?P<name_rest>.+)`,
+					NamePrefix: "graphite.",
+				},
+				{
+					Regexp:     `counters\.(?P<name_rest>.+)`,
+					MetricType: metricTypeCumulative,
+				},
+			},
+		})
+}
@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// This file implements factory for the Carbon receiver.
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "carbon"
+
+	defaultBindEndpoint   = "localhost:2003"
+	defaultTCPIdleTimeout = 30 * time.Second
+)
+
+// Factory is the factory for the Carbon receiver.
+type Factory struct {
+}
+
+// Type gets the type of the Receiver config created by this factory.
+func (f *Factory) Type() string {
+	return typeStr
+}
+
+// CustomUnmarshaler returns nil because we don't need custom unmarshaling for this config.
+func (f *Factory) CustomUnmarshaler() receiver.CustomUnmarshaler {
+	return nil
+}
+
+// CreateDefaultConfig creates the default configuration for the Carbon receiver.
+func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal:  typeStr,
+			NameVal:  typeStr,
+			Endpoint: defaultBindEndpoint,
+		},
+		Transport:      transportTCP,
+		TCPIdleTimeout: defaultTCPIdleTimeout,
+	}
+}
+
+// CreateTraceReceiver creates a trace receiver based on provided config.
+func (f *Factory) CreateTraceReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.TraceConsumer,
+) (receiver.TraceReceiver, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
+
+// CreateMetricsReceiver creates a metrics receiver based on provided config.
+func (f *Factory) CreateMetricsReceiver(
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	consumer consumer.MetricsConsumer,
+) (receiver.MetricsReceiver, error) {
+	rCfg := cfg.(*Config)
+	r, err := New(logger, rCfg.Endpoint, rCfg.Transport, rCfg.TCPIdleTimeout, rCfg.PathRules, consumer)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Carbon receiver %q: %v", rCfg.Name(), err)
+	}
+	return r, nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	keyGroupPrefix  = "key_"
+	nameGroupPrefix = "name_"
+
+	metricTypeGauge      = "gauge"
+	metricTypeCumulative = "cumulative"
+)
+
+var errEmptyLine = errors.New("empty line")
+
+// pathParser parses the lines of the Graphite plaintext protocol:
+//
+//	<path>[;<tag>=<value>...] <value> <timestamp>
+//
+// into metrics, the path rules giving their name and labels.
+type pathParser struct {
+	rules []*pathRule
+}
+
+type pathRule struct {
+	re         *regexp.Regexp
+	namePrefix string
+	metricType metricspb.MetricDescriptor_Type
+}
+
+func newPathParser(rules []PathRule) (*pathParser, error) {
+	p := &pathParser{}
+	for i, rule := range rules {
+		re, err := regexp.Compile("^(?:" + rule.Regexp + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp of path rule %d: %v", i, err)
+		}
+		for _, group := range re.SubexpNames()[1:] {
+			if group != "" && !strings.HasPrefix(group, keyGroupPrefix) && !strings.HasPrefix(group, nameGroupPrefix) {
+				return nil, fmt.Errorf("group %q of path rule %d must be prefixed by %q or %q", group, i, keyGroupPrefix, nameGroupPrefix)
+			}
+		}
+
+		pr := &pathRule{re: re, namePrefix: rule.NamePrefix}
+		switch rule.MetricType {
+		case "", metricTypeGauge:
+			pr.metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+		case metricTypeCumulative:
+			pr.metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+		default:
+			return nil, fmt.Errorf("metric type of path rule %d must be %q or %q, got %q", i, metricTypeGauge, metricTypeCumulative, rule.MetricType)
+		}
+		p.rules = append(p.rules, pr)
+	}
+	return p, nil
+}
+
+// parseLine parses a line into a metric with a single point. now is the
+// timestamp of the lines with a negative timestamp, as sent by the clients
+// letting the server set it.
+func (p *pathParser) parseLine(line string, now time.Time) (*metricspb.Metric, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, errEmptyLine
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("invalid line %q: want \"<path> <value> <timestamp>\"", line)
+	}
+
+	path, labels, err := parseTags(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %v", fields[0], err)
+	}
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %v", fields[1], err)
+	}
+	ts, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: %v", fields[2], err)
+	}
+
+	name, metricType := p.applyRules(path, labels)
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	labelKeys := make([]*metricspb.LabelKey, 0, len(keys))
+	labelValues := make([]*metricspb.LabelValue, 0, len(keys))
+	for _, k := range keys {
+		labelKeys = append(labelKeys, &metricspb.LabelKey{Key: k})
+		labelValues = append(labelValues, &metricspb.LabelValue{Value: labels[k], HasValue: true})
+	}
+
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      name,
+			Type:      metricType,
+			LabelKeys: labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: labelValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: toTimestamp(ts, now),
+						Value:     &metricspb.Point_DoubleValue{DoubleValue: value},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// applyRules returns the name and the type of the metric of the path, and
+// adds the labels extracted by the first matching rule. The labels of the
+// tags take precedence over the extracted ones.
+func (p *pathParser) applyRules(path string, labels map[string]string) (string, metricspb.MetricDescriptor_Type) {
+	for _, rule := range p.rules {
+		match := rule.re.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+
+		var nameParts []string
+		for i, group := range rule.re.SubexpNames() {
+			switch {
+			case strings.HasPrefix(group, keyGroupPrefix):
+				key := strings.TrimPrefix(group, keyGroupPrefix)
+				if _, ok := labels[key]; !ok {
+					labels[key] = match[i]
+				}
+			case strings.HasPrefix(group, nameGroupPrefix):
+				if match[i] != "" {
+					nameParts = append(nameParts, match[i])
+				}
+			}
+		}
+
+		name := rule.namePrefix + strings.Join(nameParts, ".")
+		if name == "" {
+			name = path
+		}
+		return name, rule.metricType
+	}
+	return path, metricspb.MetricDescriptor_GAUGE_DOUBLE
+}
+
+// parseTags splits the "<path>;<tag>=<value>..." path of the Graphite tag
+// support into the path and the labels.
+func parseTags(s string) (string, map[string]string, error) {
+	parts := strings.Split(s, ";")
+	path := parts[0]
+	if path == "" {
+		return "", nil, errors.New("empty path")
+	}
+
+	labels := make(map[string]string, len(parts)-1)
+	for _, tag := range parts[1:] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return "", nil, fmt.Errorf("invalid tag %q: want \"<tag>=<value>\"", tag)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return path, labels, nil
+}
+
+// toTimestamp converts the Unix time in seconds of a line.
+func toTimestamp(seconds float64, now time.Time) *timestamp.Timestamp {
+	if seconds < 0 {
+		return &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())}
+	}
+	secs, frac := math.Modf(seconds)
+	return &timestamp.Timestamp{Seconds: int64(secs), Nanos: int32(frac * 1e9)}
+}
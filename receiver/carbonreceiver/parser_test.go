@@ -0,0 +1,163 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	p, err := newPathParser(nil)
+	require.NoError(t, err)
+
+	m, err := p.parseLine("servers.host1.cpu.load 1.5 1567123456\n", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      "servers.host1.cpu.load",
+			Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			LabelKeys: []*metricspb.LabelKey{},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: []*metricspb.LabelValue{},
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &timestamp.Timestamp{Seconds: 1567123456},
+						Value:     &metricspb.Point_DoubleValue{DoubleValue: 1.5},
+					},
+				},
+			},
+		},
+	}, m)
+}
+
+func TestParseLine_Tags(t *testing.T) {
+	p, err := newPathParser(nil)
+	require.NoError(t, err)
+
+	m, err := p.parseLine("cpu.load;host=host1;dc=eu 2 1567123456", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "cpu.load", m.MetricDescriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "dc"}, {Key: "host"}}, m.MetricDescriptor.LabelKeys)
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "eu", HasValue: true}, {Value: "host1", HasValue: true}}, m.Timeseries[0].LabelValues)
+}
+
+func TestParseLine_Timestamp(t *testing.T) {
+	p, err := newPathParser(nil)
+	require.NoError(t, err)
+
+	m, err := p.parseLine("a.b 1 1567123456.25", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 1567123456, Nanos: 250000000}, m.Timeseries[0].Points[0].Timestamp)
+
+	now := time.Unix(1567000000, 5)
+	m, err = p.parseLine("a.b 1 -1", now)
+	require.NoError(t, err)
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 1567000000, Nanos: 5}, m.Timeseries[0].Points[0].Timestamp)
+}
+
+func TestParseLine_PathRules(t *testing.T) {
+	p, err := newPathParser([]PathRule{
+		{
+			Regexp:     `(?P<key_env>[^.]+)\.(?P<key_host>[^.]+)\.(?P<name_rest>.+)`,
+			NamePrefix: "graphite.",
+		},
+		{
+			Regexp:     `counters\.(?P<name_rest>.+)`,
+			MetricType: metricTypeCumulative,
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		line       string
+		wantName   string
+		wantType   metricspb.MetricDescriptor_Type
+		wantKeys   []*metricspb.LabelKey
+		wantValues []*metricspb.LabelValue
+	}{
+		{
+			name:       "first_rule",
+			line:       "prod.host1.cpu.load 1 1567123456",
+			wantName:   "graphite.cpu.load",
+			wantType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			wantKeys:   []*metricspb.LabelKey{{Key: "env"}, {Key: "host"}},
+			wantValues: []*metricspb.LabelValue{{Value: "prod", HasValue: true}, {Value: "host1", HasValue: true}},
+		},
+		{
+			name:       "tags_take_precedence",
+			line:       "prod.host1.cpu.load;host=host2 1 1567123456",
+			wantName:   "graphite.cpu.load",
+			wantType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			wantKeys:   []*metricspb.LabelKey{{Key: "env"}, {Key: "host"}},
+			wantValues: []*metricspb.LabelValue{{Value: "prod", HasValue: true}, {Value: "host2", HasValue: true}},
+		},
+		{
+			name:       "second_rule",
+			line:       "counters.requests 10 1567123456",
+			wantName:   "requests",
+			wantType:   metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+			wantKeys:   []*metricspb.LabelKey{},
+			wantValues: []*metricspb.LabelValue{},
+		},
+		{
+			name:       "no_match",
+			line:       "uptime 10 1567123456",
+			wantName:   "uptime",
+			wantType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			wantKeys:   []*metricspb.LabelKey{},
+			wantValues: []*metricspb.LabelValue{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := p.parseLine(tt.line, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, m.MetricDescriptor.Name)
+			assert.Equal(t, tt.wantType, m.MetricDescriptor.Type)
+			assert.Equal(t, tt.wantKeys, m.MetricDescriptor.LabelKeys)
+			assert.Equal(t, tt.wantValues, m.Timeseries[0].LabelValues)
+		})
+	}
+}
+
+func TestParseLine_Invalid(t *testing.T) {
+	p, err := newPathParser(nil)
+	require.NoError(t, err)
+
+	_, err = p.parseLine("  \r\n", time.Now())
+	assert.Equal(t, errEmptyLine, err)
+
+	for _, line := range []string{
+		"a.b 1",
+		"a.b 1 1567123456 extra",
+		"a.b one 1567123456",
+		"a.b 1 now",
+		";host=h1 1 1567123456",
+		"a.b;host 1 1567123456",
+		"a.b;=h1 1 1567123456",
+	} {
+		_, err := p.parseLine(line, time.Now())
+		assert.Error(t, err, line)
+	}
+}
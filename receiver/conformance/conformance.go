@@ -0,0 +1,256 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance contains canonical requests of the protocols supported
+// by the trace receivers, and the data the receivers of this collector pass to
+// their next consumer for them. Run sends the requests to any receiver
+// implementation, e.g. a fork or a third-party receiver, and checks that it
+// translates them the same way.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+// Protocol is a protocol, and its transport, accepted by a receiver.
+type Protocol string
+
+const (
+	// OpenCensus is the OpenCensus agent protocol over gRPC.
+	OpenCensus Protocol = "opencensus"
+	// ZipkinV1JSON is the Zipkin v1 JSON format, POSTed to /api/v1/spans.
+	ZipkinV1JSON Protocol = "zipkin-v1-json"
+	// ZipkinV2JSON is the Zipkin v2 JSON format, POSTed to /api/v2/spans.
+	ZipkinV2JSON Protocol = "zipkin-v2-json"
+	// JaegerThriftHTTP is a Jaeger Thrift batch POSTed to the /api/traces
+	// endpoint of the Jaeger collector.
+	JaegerThriftHTTP Protocol = "jaeger-thrift-http"
+)
+
+// DefaultTimeout is the time Run waits for a receiver to pass the data of a
+// request to its next consumer.
+const DefaultTimeout = 5 * time.Second
+
+// Fixture is a request and the data a conforming receiver passes to its next
+// consumer when receiving it.
+type Fixture struct {
+	// Name describes the fixture.
+	Name string
+
+	// Protocol is the protocol of the request.
+	Protocol Protocol
+
+	// ExportRequests are the messages sent on an OpenCensus Export stream.
+	ExportRequests []*agenttracepb.ExportTraceServiceRequest
+
+	// Path, ContentType and Body are the HTTP request of the HTTP protocols.
+	Path        string
+	ContentType string
+	Body        []byte
+
+	// Want is the data passed to the next consumer. Receivers are free to
+	// split or group the spans of a Node differently, see Run.
+	Want []consumerdata.TraceData
+}
+
+// Target is a running receiver checked by Run.
+type Target struct {
+	// Endpoints are the addresses the receiver listens on for each supported
+	// protocol: "host:port" for gRPC, the base URL (e.g.
+	// "http://localhost:9411") for HTTP. Fixtures of other protocols are skipped.
+	Endpoints map[Protocol]string
+
+	// Sink is the next consumer of the receiver.
+	Sink *exportertest.SinkTraceExporter
+
+	// Timeout overrides DefaultTimeout if not zero.
+	Timeout time.Duration
+}
+
+// Run sends the Fixtures to the target, one at a time, and checks that the
+// data it passes to its next consumer matches the fixtures. The data is
+// compared per Node, Resource and source format with the spans in trace and
+// span ID order, so that the batching of the receiver doesn't matter.
+func Run(t *testing.T, target Target) {
+	t.Helper()
+	if len(target.Endpoints) == 0 {
+		t.Fatal("the target has no endpoints")
+	}
+	timeout := target.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	for _, fixture := range Fixtures() {
+		endpoint, ok := target.Endpoints[fixture.Protocol]
+		if !ok {
+			continue
+		}
+		fixture := fixture
+		t.Run(fixture.Name, func(t *testing.T) {
+			// The sink is shared by the fixtures, only the data received
+			// after sending the request belongs to this one.
+			offset := len(target.Sink.AllTraces())
+			if err := send(endpoint, fixture, timeout); err != nil {
+				t.Fatalf("failed to send the request: %v", err)
+			}
+			got := waitForSpans(target.Sink, offset, countSpans(fixture.Want), timeout)
+			if err := Compare(got, fixture.Want); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Compare returns an error describing the differences between the data
+// received and the data wanted, once normalized as described in Run.
+func Compare(got, want []consumerdata.TraceData) error {
+	gotGroups, wantGroups := normalize(got), normalize(want)
+	var diffs []string
+	for key, wantSpans := range wantGroups {
+		gotSpans, ok := gotGroups[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("missing spans of %s", key))
+			continue
+		}
+		if len(gotSpans) != len(wantSpans) {
+			diffs = append(diffs, fmt.Sprintf("got %d spans of %s, want %d", len(gotSpans), key, len(wantSpans)))
+			continue
+		}
+		for i := range wantSpans {
+			if !proto.Equal(gotSpans[i], wantSpans[i]) {
+				diffs = append(diffs, fmt.Sprintf("span of %s\nGot:  %v\nWant: %v", key, gotSpans[i], wantSpans[i]))
+			}
+		}
+	}
+	for key := range gotGroups {
+		if _, ok := wantGroups[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("unexpected spans of %s", key))
+		}
+	}
+	if len(diffs) > 0 {
+		sort.Strings(diffs)
+		return fmt.Errorf("mismatched trace data:\n%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// normalize groups the spans by Node, Resource and source format, the text
+// representation of the protos is deterministic and used as the key.
+func normalize(tds []consumerdata.TraceData) map[string][]*tracepb.Span {
+	groups := make(map[string][]*tracepb.Span)
+	for _, td := range tds {
+		key := fmt.Sprintf("node {%v} resource {%v} format %q", td.Node, td.Resource, td.SourceFormat)
+		groups[key] = append(groups[key], td.Spans...)
+	}
+	for _, spans := range groups {
+		sort.SliceStable(spans, func(i, j int) bool {
+			if c := bytes.Compare(spans[i].GetTraceId(), spans[j].GetTraceId()); c != 0 {
+				return c < 0
+			}
+			return bytes.Compare(spans[i].GetSpanId(), spans[j].GetSpanId()) < 0
+		})
+	}
+	return groups
+}
+
+func countSpans(tds []consumerdata.TraceData) int {
+	n := 0
+	for _, td := range tds {
+		n += len(td.Spans)
+	}
+	return n
+}
+
+// waitForSpans returns the data received after offset once it has at least
+// the given number of spans, or what was received when the timeout expires.
+func waitForSpans(sink *exportertest.SinkTraceExporter, offset, spans int, timeout time.Duration) []consumerdata.TraceData {
+	deadline := time.Now().Add(timeout)
+	for {
+		received := sink.AllTraces()[offset:]
+		if countSpans(received) >= spans || time.Now().After(deadline) {
+			return received
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func send(endpoint string, fixture Fixture, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if fixture.Protocol == OpenCensus {
+		return sendOpenCensus(ctx, endpoint, fixture.ExportRequests)
+	}
+	return sendHTTP(ctx, strings.TrimSuffix(endpoint, "/")+fixture.Path, fixture.ContentType, fixture.Body)
+}
+
+func sendOpenCensus(ctx context.Context, addr string, reqs []*agenttracepb.ExportTraceServiceRequest) error {
+	cc, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(ctx)
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	// The receiver ends the stream once it has read all the messages.
+	if _, err := stream.Recv(); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func sendHTTP(ctx context.Context, url, contentType string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
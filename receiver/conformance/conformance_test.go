@@ -0,0 +1,102 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+func TestFixtures(t *testing.T) {
+	names := make(map[string]bool)
+	for _, fixture := range Fixtures() {
+		assert.False(t, names[fixture.Name], "duplicate fixture %q", fixture.Name)
+		names[fixture.Name] = true
+		assert.NotZero(t, countSpans(fixture.Want), fixture.Name)
+		if fixture.Protocol == OpenCensus {
+			assert.NotEmpty(t, fixture.ExportRequests, fixture.Name)
+		} else {
+			assert.NotEmpty(t, fixture.Path, fixture.Name)
+			assert.NotEmpty(t, fixture.Body, fixture.Name)
+		}
+	}
+
+	// Each call returns a new copy.
+	Fixtures()[0].Want[0].Spans[0].Name = &tracepb.TruncatableString{Value: "modified"}
+	assert.NotEqual(t, "modified", Fixtures()[0].Want[0].Spans[0].Name.Value)
+}
+
+func TestCompare(t *testing.T) {
+	node := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}}
+	otherNode := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "other"}}
+	span1 := &tracepb.Span{TraceId: []byte{1}, SpanId: []byte{1}}
+	span2 := &tracepb.Span{TraceId: []byte{1}, SpanId: []byte{2}}
+	want := []consumerdata.TraceData{
+		{Node: node, Spans: []*tracepb.Span{span1, span2}, SourceFormat: "test"},
+	}
+
+	tests := []struct {
+		name    string
+		got     []consumerdata.TraceData
+		wantErr bool
+	}{
+		{
+			name: "same",
+			got:  want,
+		},
+		{
+			name: "split_and_reordered",
+			got: []consumerdata.TraceData{
+				{Node: node, Spans: []*tracepb.Span{span2}, SourceFormat: "test"},
+				{Node: node, Spans: []*tracepb.Span{span1}, SourceFormat: "test"},
+			},
+		},
+		{
+			name:    "missing_span",
+			got:     []consumerdata.TraceData{{Node: node, Spans: []*tracepb.Span{span1}, SourceFormat: "test"}},
+			wantErr: true,
+		},
+		{
+			name:    "other_node",
+			got:     []consumerdata.TraceData{{Node: otherNode, Spans: []*tracepb.Span{span1, span2}, SourceFormat: "test"}},
+			wantErr: true,
+		},
+		{
+			name:    "other_source_format",
+			got:     []consumerdata.TraceData{{Node: node, Spans: []*tracepb.Span{span1, span2}, SourceFormat: "other"}},
+			wantErr: true,
+		},
+		{
+			name:    "different_span",
+			got:     []consumerdata.TraceData{{Node: node, Spans: []*tracepb.Span{span1, {TraceId: []byte{1}, SpanId: []byte{2}, Name: &tracepb.TruncatableString{Value: "x"}}}, SourceFormat: "test"}},
+			wantErr: true,
+		},
+		{
+			name:    "nothing",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Compare(tt.got, want)
+			assert.Equal(t, tt.wantErr, err != nil, "%v", err)
+		})
+	}
+}
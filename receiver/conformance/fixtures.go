@@ -0,0 +1,286 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// Fixtures returns the canonical requests of all the protocols. A new copy is
+// returned by each call so the fixtures can be modified by the caller.
+func Fixtures() []Fixture {
+	return []Fixture{
+		openCensusNodeFixture(),
+		openCensusResourceFixture(),
+		zipkinV1JSONFixture(),
+		zipkinV2JSONFixture(),
+		jaegerThriftHTTPFixture(),
+	}
+}
+
+// openCensusNodeFixture checks that the messages without a Node use the Node
+// of the previous messages of the stream.
+func openCensusNodeFixture() Fixture {
+	node := func() *commonpb.Node {
+		return &commonpb.Node{
+			Identifier:  &commonpb.ProcessIdentifier{HostName: "host-1", Pid: 42},
+			LibraryInfo: &commonpb.LibraryInfo{Language: commonpb.LibraryInfo_GO_LANG, CoreLibraryVersion: "0.22.0"},
+			ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"},
+			Attributes:  map[string]string{"env": "prod"},
+		}
+	}
+	parent := func() *tracepb.Span {
+		return &tracepb.Span{
+			TraceId:   []byte{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			SpanId:    []byte{0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28},
+			Name:      &tracepb.TruncatableString{Value: "GET /api"},
+			Kind:      tracepb.Span_SERVER,
+			StartTime: &timestamp.Timestamp{Seconds: 1544805927, Nanos: 448081000},
+			EndTime:   &timestamp.Timestamp{Seconds: 1544805927, Nanos: 460102000},
+			Attributes: &tracepb.Span_Attributes{
+				AttributeMap: map[string]*tracepb.AttributeValue{
+					"http.method": {
+						Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "GET"}},
+					},
+					"http.status_code": {Value: &tracepb.AttributeValue_IntValue{IntValue: 200}},
+				},
+			},
+			Status: &tracepb.Status{Code: 0},
+		}
+	}
+	child := func() *tracepb.Span {
+		return &tracepb.Span{
+			TraceId:      []byte{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			SpanId:       []byte{0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38},
+			ParentSpanId: []byte{0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28},
+			Name:         &tracepb.TruncatableString{Value: "cache.get"},
+			Kind:         tracepb.Span_CLIENT,
+			StartTime:    &timestamp.Timestamp{Seconds: 1544805927, Nanos: 450000000},
+			EndTime:      &timestamp.Timestamp{Seconds: 1544805927, Nanos: 451000000},
+			TimeEvents: &tracepb.Span_TimeEvents{
+				TimeEvent: []*tracepb.Span_TimeEvent{
+					{
+						Time: &timestamp.Timestamp{Seconds: 1544805927, Nanos: 450500000},
+						Value: &tracepb.Span_TimeEvent_Annotation_{
+							Annotation: &tracepb.Span_TimeEvent_Annotation{
+								Description: &tracepb.TruncatableString{Value: "cache miss"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return Fixture{
+		Name:     "opencensus_node",
+		Protocol: OpenCensus,
+		ExportRequests: []*agenttracepb.ExportTraceServiceRequest{
+			{Node: node(), Spans: []*tracepb.Span{parent()}},
+			{Spans: []*tracepb.Span{child()}},
+		},
+		Want: []consumerdata.TraceData{
+			{Node: node(), Spans: []*tracepb.Span{parent(), child()}, SourceFormat: "oc_trace"},
+		},
+	}
+}
+
+// openCensusResourceFixture checks that the Resource is passed along with the
+// spans.
+func openCensusResourceFixture() Fixture {
+	node := func() *commonpb.Node {
+		return &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "backend"}}
+	}
+	resource := func() *resourcepb.Resource {
+		return &resourcepb.Resource{Type: "k8s", Labels: map[string]string{"k8s.pod.name": "backend-1"}}
+	}
+	span := func() *tracepb.Span {
+		return &tracepb.Span{
+			TraceId:   []byte{0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50},
+			SpanId:    []byte{0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58},
+			Name:      &tracepb.TruncatableString{Value: "query"},
+			StartTime: &timestamp.Timestamp{Seconds: 1544805928},
+			EndTime:   &timestamp.Timestamp{Seconds: 1544805929},
+		}
+	}
+
+	return Fixture{
+		Name:     "opencensus_resource",
+		Protocol: OpenCensus,
+		ExportRequests: []*agenttracepb.ExportTraceServiceRequest{
+			{Node: node(), Resource: resource(), Spans: []*tracepb.Span{span()}},
+		},
+		Want: []consumerdata.TraceData{
+			{Node: node(), Resource: resource(), Spans: []*tracepb.Span{span()}, SourceFormat: "oc_trace"},
+		},
+	}
+}
+
+// zipkinV1JSONFixture checks that the span kind and times of a Zipkin v1 span
+// are taken from its annotations.
+func zipkinV1JSONFixture() Fixture {
+	return Fixture{
+		Name:        "zipkin_v1_json",
+		Protocol:    ZipkinV1JSON,
+		Path:        "/api/v1/spans",
+		ContentType: "application/json",
+		Body: []byte(`[{
+  "traceId": "0ed2e63cbe71f5a8",
+  "name": "checkAvailability",
+  "id": "0ed2e63cbe71f5a8",
+  "annotations": [
+    {
+      "timestamp": 1544805927448081,
+      "value": "sr",
+      "endpoint": {"ipv4": "172.31.0.4", "port": 0, "serviceName": "service1"}
+    },
+    {
+      "timestamp": 1544805927460102,
+      "value": "ss",
+      "endpoint": {"ipv4": "172.31.0.4", "port": 0, "serviceName": "service1"}
+    }
+  ]
+}]`),
+		Want: []consumerdata.TraceData{
+			{
+				Node: &commonpb.Node{
+					ServiceInfo: &commonpb.ServiceInfo{Name: "service1"},
+					Attributes:  map[string]string{"ipv4": "172.31.0.4"},
+				},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:   []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0e, 0xd2, 0xe6, 0x3c, 0xbe, 0x71, 0xf5, 0xa8},
+						SpanId:    []byte{0x0e, 0xd2, 0xe6, 0x3c, 0xbe, 0x71, 0xf5, 0xa8},
+						Name:      &tracepb.TruncatableString{Value: "checkAvailability"},
+						Kind:      tracepb.Span_SERVER,
+						StartTime: &timestamp.Timestamp{Seconds: 1544805927, Nanos: 448081000},
+						EndTime:   &timestamp.Timestamp{Seconds: 1544805927, Nanos: 460102000},
+						TimeEvents: &tracepb.Span_TimeEvents{
+							TimeEvent: []*tracepb.Span_TimeEvent{
+								zipkinV1Annotation(448081000, "sr", "service1"),
+								zipkinV1Annotation(460102000, "ss", "service1"),
+							},
+						},
+					},
+				},
+				SourceFormat: "zipkin",
+			},
+		},
+	}
+}
+
+func zipkinV1Annotation(nanos int32, value, serviceName string) *tracepb.Span_TimeEvent {
+	return &tracepb.Span_TimeEvent{
+		Time: &timestamp.Timestamp{Seconds: 1544805927, Nanos: nanos},
+		Value: &tracepb.Span_TimeEvent_Annotation_{
+			Annotation: &tracepb.Span_TimeEvent_Annotation{
+				Attributes: &tracepb.Span_Attributes{
+					AttributeMap: map[string]*tracepb.AttributeValue{
+						value: {
+							Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: serviceName}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// zipkinV2JSONFixture checks that the local and remote endpoints of a Zipkin
+// v2 span become the Node, and its tags and annotations the attributes and
+// time events of the span.
+func zipkinV2JSONFixture() Fixture {
+	return Fixture{
+		Name:        "zipkin_v2_json",
+		Protocol:    ZipkinV2JSON,
+		Path:        "/api/v2/spans",
+		ContentType: "application/json",
+		Body: []byte(`[{
+  "traceId": "4d1e00c0db9010db86154a4ba6e91385",
+  "parentId": "86154a4ba6e91385",
+  "id": "4d1e00c0db9010db",
+  "kind": "CLIENT",
+  "name": "get",
+  "timestamp": 1472470996199000,
+  "duration": 207000,
+  "localEndpoint": {"serviceName": "frontend", "ipv6": "7::0.128.128.127"},
+  "remoteEndpoint": {"serviceName": "backend", "ipv4": "192.168.99.101", "port": 9000},
+  "annotations": [
+    {"timestamp": 1472470996238000, "value": "foo"},
+    {"timestamp": 1472470996403000, "value": "bar"}
+  ],
+  "tags": {"http.path": "/api", "clnt/finagle.version": "6.45.0"}
+}]`),
+		Want: []consumerdata.TraceData{
+			{
+				Node: &commonpb.Node{
+					ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"},
+					Attributes: map[string]string{
+						"ipv6":                              "7::80:807f",
+						"serviceName":                       "frontend",
+						"zipkin.remoteEndpoint.serviceName": "backend",
+						"zipkin.remoteEndpoint.ipv4":        "192.168.99.101",
+						"zipkin.remoteEndpoint.port":        "9000",
+					},
+				},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:      []byte{0x4d, 0x1e, 0x00, 0xc0, 0xdb, 0x90, 0x10, 0xdb, 0x86, 0x15, 0x4a, 0x4b, 0xa6, 0xe9, 0x13, 0x85},
+						SpanId:       []byte{0x4d, 0x1e, 0x00, 0xc0, 0xdb, 0x90, 0x10, 0xdb},
+						ParentSpanId: []byte{0x86, 0x15, 0x4a, 0x4b, 0xa6, 0xe9, 0x13, 0x85},
+						Name:         &tracepb.TruncatableString{Value: "get"},
+						Kind:         tracepb.Span_CLIENT,
+						StartTime:    &timestamp.Timestamp{Seconds: 1472470996, Nanos: 199000000},
+						EndTime:      &timestamp.Timestamp{Seconds: 1472470996, Nanos: 406000000},
+						TimeEvents: &tracepb.Span_TimeEvents{
+							TimeEvent: []*tracepb.Span_TimeEvent{
+								zipkinV2Annotation(238000000, "foo"),
+								zipkinV2Annotation(403000000, "bar"),
+							},
+						},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"http.path": {
+									Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "/api"}},
+								},
+								"clnt/finagle.version": {
+									Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "6.45.0"}},
+								},
+							},
+						},
+					},
+				},
+				SourceFormat: "zipkin",
+			},
+		},
+	}
+}
+
+func zipkinV2Annotation(nanos int32, value string) *tracepb.Span_TimeEvent {
+	return &tracepb.Span_TimeEvent{
+		Time: &timestamp.Timestamp{Seconds: 1472470996, Nanos: nanos},
+		Value: &tracepb.Span_TimeEvent_Annotation_{
+			Annotation: &tracepb.Span_TimeEvent_Annotation{
+				Description: &tracepb.TruncatableString{Value: value},
+			},
+		},
+	}
+}
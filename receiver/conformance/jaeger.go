@@ -0,0 +1,131 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"github.com/apache/thrift/lib/go/thrift"
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// jaegerThriftHTTPFixture checks that the process of a Jaeger batch becomes
+// the Node, with its "hostname" tag as the host name, and that the
+// "span.kind" tag and the logs of the spans are translated.
+func jaegerThriftHTTPFixture() Fixture {
+	str := func(s string) *string { return &s }
+	batch := &jaeger.Batch{
+		Process: &jaeger.Process{
+			ServiceName: "frontend",
+			Tags: []*jaeger.Tag{
+				{Key: "hostname", VType: jaeger.TagType_STRING, VStr: str("host-1")},
+				{Key: "ip", VType: jaeger.TagType_STRING, VStr: str("10.0.0.1")},
+			},
+		},
+		Spans: []*jaeger.Span{
+			{
+				TraceIdHigh:   0x1112131415161718,
+				TraceIdLow:    0x0102030405060708,
+				SpanId:        0x2122232425262728,
+				OperationName: "GET /api",
+				StartTime:     1544805927448081,
+				Duration:      12021,
+				Tags: []*jaeger.Tag{
+					{Key: "span.kind", VType: jaeger.TagType_STRING, VStr: str("server")},
+					{Key: "http.method", VType: jaeger.TagType_STRING, VStr: str("GET")},
+				},
+				Logs: []*jaeger.Log{
+					{
+						Timestamp: 1544805927450000,
+						Fields: []*jaeger.Tag{
+							{Key: "message", VType: jaeger.TagType_STRING, VStr: str("cache miss")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	stringValue := func(s string) *tracepb.AttributeValue {
+		return &tracepb.AttributeValue{
+			Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: s}},
+		}
+	}
+	return Fixture{
+		Name:        "jaeger_thrift_http",
+		Protocol:    JaegerThriftHTTP,
+		Path:        "/api/traces",
+		ContentType: "application/x-thrift",
+		Body:        serializeThrift(batch),
+		Want: []consumerdata.TraceData{
+			{
+				Node: &commonpb.Node{
+					Identifier:  &commonpb.ProcessIdentifier{HostName: "host-1"},
+					LibraryInfo: &commonpb.LibraryInfo{},
+					ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"},
+					Attributes:  map[string]string{"ip": "10.0.0.1"},
+				},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:   []byte{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+						SpanId:    []byte{0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28},
+						Name:      &tracepb.TruncatableString{Value: "GET /api"},
+						Kind:      tracepb.Span_SERVER,
+						StartTime: &timestamp.Timestamp{Seconds: 1544805927, Nanos: 448081000},
+						EndTime:   &timestamp.Timestamp{Seconds: 1544805927, Nanos: 460102000},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"span.kind":   stringValue("server"),
+								"http.method": stringValue("GET"),
+							},
+						},
+						TimeEvents: &tracepb.Span_TimeEvents{
+							TimeEvent: []*tracepb.Span_TimeEvent{
+								{
+									Time: &timestamp.Timestamp{Seconds: 1544805927, Nanos: 450000000},
+									Value: &tracepb.Span_TimeEvent_Annotation_{
+										Annotation: &tracepb.Span_TimeEvent_Annotation{
+											Description: &tracepb.TruncatableString{Value: "cache miss"},
+											Attributes: &tracepb.Span_Attributes{
+												AttributeMap: map[string]*tracepb.AttributeValue{
+													"message": stringValue("cache miss"),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				SourceFormat: "jaeger",
+			},
+		},
+	}
+}
+
+// serializeThrift encodes the batch with the binary protocol, as expected by
+// the Jaeger collector HTTP endpoint.
+func serializeThrift(batch *jaeger.Batch) []byte {
+	buffer := thrift.NewTMemoryBuffer()
+	if err := batch.Write(thrift.NewTBinaryProtocolTransport(buffer)); err != nil {
+		// Writing to a memory buffer can't fail.
+		panic(err)
+	}
+	return buffer.Bytes()
+}
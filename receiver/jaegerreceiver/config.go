@@ -15,14 +15,49 @@
 package jaegerreceiver
 
 import (
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 // Config defines configuration for Jaeger receiver.
 type Config struct {
-	TypeVal   string                                    `mapstructure:"-"`
-	NameVal   string                                    `mapstructure:"-"`
+	TypeVal string `mapstructure:"-"`
+	NameVal string `mapstructure:"-"`
+
+	// Protocols are the protocols received, by name: grpc, thrift-http and
+	// thrift-tchannel for the collector endpoints, thrift-compact and
+	// thrift-binary for the UDP agent endpoints. Only the enabled ones are
+	// started.
 	Protocols map[string]*configmodels.ReceiverSettings `mapstructure:"protocols"`
+
+	// TLSCredentials configures TLS on the grpc and thrift-http collector
+	// endpoints, thrift-tchannel stays in plaintext.
+	TLSCredentials *configtls.TLSServerSetting `mapstructure:"tls-credentials,omitempty"`
+
+	// Authentication rejects the requests without valid credentials on the
+	// grpc and thrift-http collector endpoints, thrift-tchannel and the agent
+	// endpoints are not authenticated.
+	Authentication *configauth.Settings `mapstructure:"authentication,omitempty"`
+
+	// Limits protect the grpc, thrift-http and thrift-tchannel collector
+	// endpoints from clients sending more data than the receiver can handle,
+	// the agent endpoints are not limited.
+	Limits *configlimit.Settings `mapstructure:"limits,omitempty"`
+
+	// Routes send the data matching them to some of the pipelines of the
+	// receiver only, whatever the protocol it was received with.
+	Routes []configmodels.ReceiverRoute `mapstructure:"routes,omitempty"`
+
+	// Attributes are set as labels on the resource of all the data of the
+	// receiver, whatever the protocol it was received with.
+	Attributes map[string]string `mapstructure:"attributes,omitempty"`
+
+	// IDs normalizes the trace and span IDs of the spans received with any
+	// protocol.
+	IDs *tracetranslator.IDNormalization `mapstructure:"ids,omitempty"`
 }
 
 // Name gets the receiver name.
@@ -48,7 +83,7 @@ func (rs *Config) SetType(typeStr string) {
 // IsEnabled returns true if the entity is enabled.
 func (rs *Config) IsEnabled() bool {
 	for _, p := range rs.Protocols {
-		if p.IsEnabled() {
+		if p != nil && p.IsEnabled() {
 			// If any protocol is enabled then the receiver as a whole should be enabled.
 			return true
 		}
@@ -56,3 +91,39 @@ func (rs *Config) IsEnabled() bool {
 	// All protocols are disabled so the entire receiver can be disabled.
 	return false
 }
+
+// SetEnabled disables all the protocols if enabled is false, the protocols
+// keep their own setting otherwise.
+func (rs *Config) SetEnabled(enabled bool) {
+	if enabled {
+		return
+	}
+	for _, p := range rs.Protocols {
+		if p != nil {
+			p.Disabled = true
+		}
+	}
+}
+
+var _ configmodels.Toggleable = (*Config)(nil)
+
+// ListenEndpoints returns the endpoints of the enabled protocols.
+func (rs *Config) ListenEndpoints() []string {
+	var endpoints []string
+	for _, p := range rs.Protocols {
+		if p != nil {
+			endpoints = append(endpoints, p.ListenEndpoints()...)
+		}
+	}
+	return endpoints
+}
+
+// ReceiverRoutes returns the routes of the receiver.
+func (rs *Config) ReceiverRoutes() []configmodels.ReceiverRoute {
+	return rs.Routes
+}
+
+// ReceiverAttributes returns the static attributes of the receiver.
+func (rs *Config) ReceiverAttributes() map[string]string {
+	return rs.Attributes
+}
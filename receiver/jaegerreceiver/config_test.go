@@ -22,7 +22,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -38,7 +41,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 6)
 
 	r0 := cfg.Receivers["jaeger"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -62,4 +65,92 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		})
+
+	r2 := cfg.Receivers["jaeger/tls"].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			TypeVal: typeStr,
+			NameVal: "jaeger/tls",
+			Protocols: map[string]*configmodels.ReceiverSettings{
+				"grpc": {
+					Endpoint: "127.0.0.1:14250",
+				},
+				"thrift-http": {
+					Endpoint: "127.0.0.1:14268",
+				},
+				"thrift-tchannel": {
+					Disabled: true,
+					Endpoint: "127.0.0.1:14267",
+				},
+			},
+			TLSCredentials: &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile:   "/etc/ssl/ca.crt",
+					CertFile: "/etc/ssl/server.crt",
+					KeyFile:  "/etc/ssl/server.key",
+				},
+				ClientAllowedOUs: []string{"agents"},
+			},
+		})
+
+	r3 := cfg.Receivers["jaeger/ids"].(*Config)
+	assert.Equal(t, r3,
+		&Config{
+			TypeVal: typeStr,
+			NameVal: "jaeger/ids",
+			Protocols: map[string]*configmodels.ReceiverSettings{
+				"thrift-tchannel": {
+					Endpoint: "0.0.0.0:14267",
+				},
+			},
+			IDs: &tracetranslator.IDNormalization{
+				TraceIDPadding: tracetranslator.TraceIDPaddingZero,
+				DropInvalid:    true,
+			},
+		})
+
+	r4 := cfg.Receivers["jaeger/limits"].(*Config)
+	assert.Equal(t, r4,
+		&Config{
+			TypeVal: typeStr,
+			NameVal: "jaeger/limits",
+			Protocols: map[string]*configmodels.ReceiverSettings{
+				"grpc": {
+					Endpoint: "0.0.0.0:14250",
+				},
+			},
+			Limits: &configlimit.Settings{
+				MaxConnections:     100,
+				MaxRequestSize:     4194304,
+				MaxAttributeLength: 4096,
+			},
+		})
+	r5 := cfg.Receivers["jaeger/agent"].(*Config)
+	assert.Equal(t, r5,
+		&Config{
+			TypeVal: typeStr,
+			NameVal: "jaeger/agent",
+			Protocols: map[string]*configmodels.ReceiverSettings{
+				"thrift-compact": {
+					Endpoint: defaultThriftCompactBindEndpoint,
+				},
+				"thrift-binary": {
+					Disabled: true,
+					Endpoint: "0.0.0.0:6832",
+				},
+			},
+		})
+}
+
+func TestSetEnabled(t *testing.T) {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+
+	cfg.SetEnabled(true)
+	assert.True(t, cfg.IsEnabled())
+
+	cfg.SetEnabled(false)
+	assert.False(t, cfg.IsEnabled())
+	for name, protocol := range cfg.Protocols {
+		assert.True(t, protocol.Disabled, name)
+	}
 }
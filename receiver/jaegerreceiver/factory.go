@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
@@ -34,17 +36,36 @@ const (
 	// The value of "type" key in configuration.
 	typeStr = "jaeger"
 
+	// The key of the protocols in the configuration.
+	protocolsKey = "protocols"
+
 	// Protocol values.
 	protoGRPC           = "grpc"
 	protoThriftHTTP     = "thrift-http"
 	protoThriftTChannel = "thrift-tchannel"
+	protoThriftCompact  = "thrift-compact"
+	protoThriftBinary   = "thrift-binary"
 
 	// Default endpoints to bind to.
-	defaultGRPCBindEndpoint     = "127.0.0.1:14250"
-	defaultHTTPBindEndpoint     = "127.0.0.1:14268"
-	defaultTChannelBindEndpoint = "127.0.0.1:14267"
+	defaultGRPCBindEndpoint          = "127.0.0.1:14250"
+	defaultHTTPBindEndpoint          = "127.0.0.1:14268"
+	defaultTChannelBindEndpoint      = "127.0.0.1:14267"
+	defaultThriftCompactBindEndpoint = "127.0.0.1:6831"
+	defaultThriftBinaryBindEndpoint  = "127.0.0.1:6832"
 )
 
+// protocols are the names of the protocols, in the order of the messages.
+var protocols = []string{protoGRPC, protoThriftHTTP, protoThriftTChannel, protoThriftCompact, protoThriftBinary}
+
+// defaultEndpoints are the default endpoints of the protocols.
+var defaultEndpoints = map[string]string{
+	protoGRPC:           defaultGRPCBindEndpoint,
+	protoThriftHTTP:     defaultHTTPBindEndpoint,
+	protoThriftTChannel: defaultTChannelBindEndpoint,
+	protoThriftCompact:  defaultThriftCompactBindEndpoint,
+	protoThriftBinary:   defaultThriftBinaryBindEndpoint,
+}
+
 // Factory is the factory for Jaeger receiver.
 type Factory struct {
 }
@@ -54,28 +75,66 @@ func (f *Factory) Type() string {
 	return typeStr
 }
 
-// CustomUnmarshaler returns nil because we don't need custom unmarshaling for this config.
+// CustomUnmarshaler returns the unmarshaler of the protocols.
 func (f *Factory) CustomUnmarshaler() receiver.CustomUnmarshaler {
+	return customUnmarshaler
+}
+
+// customUnmarshaler unmarshals the config. The protocols set replace the
+// default ones, they get their default endpoint if they have none, e.g. with
+// only "thrift-compact:". It returns an error if a protocol is unknown or if
+// none is enabled.
+func customUnmarshaler(v *viper.Viper, viperKey string, intoCfg interface{}) error {
+	rCfg := intoCfg.(*Config)
+	// Viper merges the protocols into the default ones, clear them so that
+	// only the configured protocols are received.
+	if vSub := v.Sub(viperKey); vSub != nil && vSub.IsSet(protocolsKey) {
+		rCfg.Protocols = make(map[string]*configmodels.ReceiverSettings)
+	}
+	if err := v.UnmarshalKey(viperKey, intoCfg); err != nil {
+		return fmt.Errorf("%s receiver failed to parse config: %s", typeStr, err)
+	}
+	for name, protocol := range rCfg.Protocols {
+		if protocol == nil {
+			protocol = &configmodels.ReceiverSettings{}
+			rCfg.Protocols[name] = protocol
+		}
+		if protocol.Endpoint == "" {
+			protocol.Endpoint = defaultEndpoints[name]
+		}
+	}
+	return checkProtocols(rCfg)
+}
+
+// checkProtocols returns an error if a protocol is unknown or if none is
+// enabled.
+func checkProtocols(rCfg *Config) error {
+	for name := range rCfg.Protocols {
+		if _, ok := defaultEndpoints[name]; !ok {
+			return fmt.Errorf("unknown protocol %q for %s receiver, the protocols are %s",
+				name, typeStr, strings.Join(protocols, ", "))
+		}
+	}
+	if !rCfg.IsEnabled() {
+		return fmt.Errorf("at least one of the %s protocols must be enabled for %s receiver",
+			strings.Join(protocols, ", "), typeStr)
+	}
 	return nil
 }
 
 // CreateDefaultConfig creates the default configuration for Jaeger receiver.
 func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
-	return &Config{
-		TypeVal: typeStr,
-		NameVal: typeStr,
-		Protocols: map[string]*configmodels.ReceiverSettings{
-			protoGRPC: {
-				Endpoint: defaultGRPCBindEndpoint,
-			},
-			protoThriftTChannel: {
-				Endpoint: defaultTChannelBindEndpoint,
-			},
-			protoThriftHTTP: {
-				Endpoint: defaultHTTPBindEndpoint,
-			},
-		},
+	cfg := &Config{
+		TypeVal:   typeStr,
+		NameVal:   typeStr,
+		Protocols: make(map[string]*configmodels.ReceiverSettings, len(protocols)),
+	}
+	for _, name := range protocols {
+		cfg.Protocols[name] = &configmodels.ReceiverSettings{
+			Endpoint: defaultEndpoints[name],
+		}
 	}
+	return cfg
 }
 
 // CreateTraceReceiver creates a trace receiver based on provided config.
@@ -90,47 +149,59 @@ func (f *Factory) CreateTraceReceiver(
 	// that Jaeger receiver understands.
 
 	rCfg := cfg.(*Config)
-
-	protoGRPC := rCfg.Protocols[protoGRPC]
-	protoHTTP := rCfg.Protocols[protoThriftHTTP]
-	protoTChannel := rCfg.Protocols[protoThriftTChannel]
+	if err := checkProtocols(rCfg); err != nil {
+		return nil, err
+	}
 
 	config := Configuration{}
 
-	// Set ports
-	if protoGRPC != nil && protoGRPC.IsEnabled() {
+	// Set the endpoints of the enabled protocols, only they are started.
+	endpoints := map[string]*string{
+		protoGRPC:           &config.CollectorGRPCEndpoint,
+		protoThriftHTTP:     &config.CollectorHTTPEndpoint,
+		protoThriftTChannel: &config.CollectorThriftEndpoint,
+		protoThriftCompact:  &config.AgentCompactThriftEndpoint,
+		protoThriftBinary:   &config.AgentBinaryThriftEndpoint,
+	}
+	for name, protocol := range rCfg.Protocols {
+		if protocol == nil || !protocol.IsEnabled() {
+			continue
+		}
+		if _, err := extractPortFromEndpoint(protocol.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid %s endpoint for %s receiver: %v", name, typeStr, err)
+		}
+		*endpoints[name] = protocol.Endpoint
+	}
+
+	if rCfg.TLSCredentials != nil {
 		var err error
-		config.CollectorGRPCPort, err = extractPortFromEndpoint(protoGRPC.Endpoint)
+		config.CollectorTLSConfig, err = rCfg.TLSCredentials.LoadTLSConfig()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("error initializing Jaeger receiver %q TLS credentials: %v", rCfg.NameVal, err)
 		}
 	}
 
-	if protoHTTP != nil && protoHTTP.IsEnabled() {
+	if rCfg.Authentication != nil {
 		var err error
-		config.CollectorHTTPPort, err = extractPortFromEndpoint(protoHTTP.Endpoint)
+		config.CollectorAuthenticator, err = rCfg.Authentication.NewAuthenticator()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("error initializing Jaeger receiver %q authentication: %v", rCfg.NameVal, err)
 		}
 	}
 
-	if protoTChannel != nil && protoTChannel.IsEnabled() {
+	if rCfg.Limits != nil {
 		var err error
-		config.CollectorThriftPort, err = extractPortFromEndpoint(protoTChannel.Endpoint)
+		config.CollectorLimiter, err = rCfg.Limits.NewLimiter(rCfg.NameVal)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("error initializing Jaeger receiver %q limits: %v", rCfg.NameVal, err)
 		}
 	}
 
-	if (protoGRPC == nil && protoHTTP == nil && protoTChannel == nil) ||
-		(config.CollectorGRPCPort == 0 && config.CollectorHTTPPort == 0 && config.CollectorThriftPort == 0) {
-		err := fmt.Errorf("either %v, %v, or %v protocol endpoint with non-zero port must be enabled for %s receiver",
-			protoGRPC,
-			protoThriftHTTP,
-			protoThriftTChannel,
-			typeStr,
-		)
-		return nil, err
+	if rCfg.IDs != nil {
+		if err := rCfg.IDs.Validate(); err != nil {
+			return nil, fmt.Errorf("error initializing Jaeger receiver %q: %v", rCfg.NameVal, err)
+		}
+		config.IDNormalization = rCfg.IDs
 	}
 
 	// Create the receiver.
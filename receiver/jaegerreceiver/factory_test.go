@@ -21,7 +21,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -43,6 +48,48 @@ func TestCreateReceiver(t *testing.T) {
 	assert.Nil(t, mReceiver)
 }
 
+func TestCreateReceiverTLSError(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.TLSCredentials = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: "testdata/missing.crt", KeyFile: "testdata/missing.key"},
+	}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation with invalid TLS credentials must fail")
+}
+
+func TestCreateReceiverAuthenticationError(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.Authentication = &configauth.Settings{}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation without any authentication method must fail")
+}
+
+func TestCreateReceiverIDsError(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.IDs = &tracetranslator.IDNormalization{TraceIDPadding: "unknown"}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation with an unknown trace ID padding must fail")
+}
+
+func TestCreateReceiverLimitsError(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.Limits = &configlimit.Settings{MaxRequestSize: -1}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation with a negative maximum request size must fail")
+}
+
 func TestCreateInvalidGRPCEndpoint(t *testing.T) {
 	factory := Factory{}
 	cfg := factory.CreateDefaultConfig()
@@ -98,13 +145,54 @@ func TestCreateNoProtocols(t *testing.T) {
 	cfg := factory.CreateDefaultConfig()
 	rCfg := cfg.(*Config)
 
-	delete(rCfg.Protocols, protoGRPC)
-	delete(rCfg.Protocols, protoThriftHTTP)
-	delete(rCfg.Protocols, protoThriftTChannel)
+	rCfg.Protocols = map[string]*configmodels.ReceiverSettings{}
 	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
 	assert.Error(t, err, "receiver creation with no protocols must fail")
 }
 
+func TestCreateAllProtocolsDisabled(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.SetEnabled(false)
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation with all the protocols disabled must fail")
+}
+
+func TestCreateUnknownProtocol(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.Protocols["thrift-udp"] = &configmodels.ReceiverSettings{Endpoint: "127.0.0.1:6831"}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Error(t, err, "receiver creation with an unknown protocol must fail")
+}
+
+func TestCreateInvalidDisabledEndpoint(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.Protocols[protoThriftCompact].Endpoint = ""
+	rCfg.Protocols[protoThriftCompact].Disabled = true
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.NoError(t, err, "the endpoints of the disabled protocols must be ignored")
+}
+
+func TestCreateOnlyAgent(t *testing.T) {
+	factory := Factory{}
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.Protocols = map[string]*configmodels.ReceiverSettings{
+		protoThriftCompact: {Endpoint: "127.0.0.1:6831"},
+	}
+	_, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.NoError(t, err, "receiver creation with only the agent protocols must not fail")
+}
+
 func TestCreateWithoutThrift(t *testing.T) {
 	factory := Factory{}
 	cfg := factory.CreateDefaultConfig()
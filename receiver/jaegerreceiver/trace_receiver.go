@@ -17,17 +17,21 @@ package jaegerreceiver
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
 
+	apachethrift "github.com/apache/thrift/lib/go/thrift"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/gorilla/mux"
 	agentapp "github.com/jaegertracing/jaeger/cmd/agent/app"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/configmanager"
 	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
-	"github.com/jaegertracing/jaeger/cmd/collector/app"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
@@ -38,15 +42,24 @@ import (
 	"github.com/uber/tchannel-go/thrift"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 	jaegertranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/jaeger"
 )
 
 // Configuration defines the behavior and the ports that
-// the Jaeger receiver will use.
+// the Jaeger receiver will use. Only the protocols with an endpoint or a port
+// are started, the endpoint takes precedence over the port.
 type Configuration struct {
 	CollectorThriftPort int `mapstructure:"tchannel_port"`
 	CollectorHTTPPort   int `mapstructure:"collector_http_port"`
@@ -55,6 +68,28 @@ type Configuration struct {
 	AgentPort              int `mapstructure:"agent_port"`
 	AgentCompactThriftPort int `mapstructure:"agent_compact_thrift_port"`
 	AgentBinaryThriftPort  int `mapstructure:"agent_binary_thrift_port"`
+
+	// The endpoints, i.e. host:port, of the protocols.
+	CollectorThriftEndpoint    string `mapstructure:"-"`
+	CollectorHTTPEndpoint      string `mapstructure:"-"`
+	CollectorGRPCEndpoint      string `mapstructure:"-"`
+	AgentCompactThriftEndpoint string `mapstructure:"-"`
+	AgentBinaryThriftEndpoint  string `mapstructure:"-"`
+
+	// CollectorTLSConfig enables TLS on the gRPC and HTTP collector ports.
+	CollectorTLSConfig *tls.Config `mapstructure:"-"`
+
+	// CollectorAuthenticator, if not nil, rejects the unauthenticated requests
+	// on the gRPC and HTTP collector ports.
+	CollectorAuthenticator configauth.Authenticator `mapstructure:"-"`
+
+	// CollectorLimiter, if not nil, enforces its limits on the collector
+	// ports.
+	CollectorLimiter *configlimit.Limiter `mapstructure:"-"`
+
+	// IDNormalization, if not nil, normalizes the trace and span IDs of the
+	// received spans.
+	IDNormalization *tracetranslator.IDNormalization `mapstructure:"-"`
 }
 
 // Receiver type is used to receive spans that were originally intended to be sent to Jaeger.
@@ -80,26 +115,14 @@ type jReceiver struct {
 }
 
 const (
-	// As per https://www.jaegertracing.io/docs/1.13/deployment/
-	// By default, the port used by jaeger-agent to send spans in model.proto format
-	defaultGRPCPort = 14250
-	// By default, the port used by jaeger-agent to send spans in jaeger.thrift format
-	defaultTChannelPort = 14267
-	// By default, can accept spans directly from clients in jaeger.thrift format over binary thrift protocol
-	defaultCollectorHTTPPort = 14268
-
-	// As per https://www.jaegertracing.io/docs/1.7/deployment/#agent
-	// 5775	UDP accept zipkin.thrift over compact thrift protocol
-	// 6831	UDP accept jaeger.thrift over compact thrift protocol
-	// 6832	UDP accept jaeger.thrift over binary thrift protocol
-	defaultCompactThriftUDPPort = 6831
-	defaultBinaryThriftUDPPort  = 6832
-
 	traceSource string = "Jaeger"
 )
 
 // New creates a TraceReceiver that receives traffic as a collector with both Thrift and HTTP transports.
 func New(ctx context.Context, config *Configuration, nextConsumer consumer.TraceConsumer) (receiver.TraceReceiver, error) {
+	if config == nil {
+		config = &Configuration{}
+	}
 	return &jReceiver{
 		config:          config,
 		defaultAgentCtx: observability.ContextWithReceiverName(context.Background(), "jaeger-agent"),
@@ -114,24 +137,25 @@ var (
 	errAlreadyStopped = errors.New("already stopped")
 )
 
-func (jr *jReceiver) collectorAddr() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.CollectorHTTPPort
+// address returns the address of a protocol, empty if it is not configured.
+func address(endpoint string, port int) string {
+	if endpoint != "" {
+		return endpoint
 	}
-	if port <= 0 {
-		port = defaultCollectorHTTPPort
+	if port > 0 {
+		return fmt.Sprintf(":%d", port)
 	}
-	return fmt.Sprintf(":%d", port)
+	return ""
+}
+
+func (jr *jReceiver) collectorAddr() string {
+	return address(jr.config.CollectorHTTPEndpoint, jr.config.CollectorHTTPPort)
 }
 
 const defaultAgentPort = 5778
 
 func (jr *jReceiver) agentAddress() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.AgentPort
-	}
+	port := jr.config.AgentPort
 	if port <= 0 {
 		port = defaultAgentPort
 	}
@@ -139,47 +163,19 @@ func (jr *jReceiver) agentAddress() string {
 }
 
 func (jr *jReceiver) tchannelAddr() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.CollectorThriftPort
-	}
-	if port <= 0 {
-		port = defaultTChannelPort
-	}
-	return fmt.Sprintf(":%d", port)
+	return address(jr.config.CollectorThriftEndpoint, jr.config.CollectorThriftPort)
 }
 
 func (jr *jReceiver) grpcAddr() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.CollectorGRPCPort
-	}
-	if port <= 0 {
-		port = defaultGRPCPort
-	}
-	return fmt.Sprintf(":%d", port)
+	return address(jr.config.CollectorGRPCEndpoint, jr.config.CollectorGRPCPort)
 }
 
 func (jr *jReceiver) agentCompactThriftAddr() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.AgentCompactThriftPort
-	}
-	if port <= 0 {
-		port = defaultCompactThriftUDPPort
-	}
-	return fmt.Sprintf(":%d", port)
+	return address(jr.config.AgentCompactThriftEndpoint, jr.config.AgentCompactThriftPort)
 }
 
 func (jr *jReceiver) agentBinaryThriftAddr() string {
-	var port int
-	if jr.config != nil {
-		port = jr.config.AgentBinaryThriftPort
-	}
-	if port <= 0 {
-		port = defaultBinaryThriftUDPPort
-	}
-	return fmt.Sprintf(":%d", port)
+	return address(jr.config.AgentBinaryThriftEndpoint, jr.config.AgentBinaryThriftPort)
 }
 
 func (jr *jReceiver) TraceSource() string {
@@ -255,19 +251,52 @@ func (jr *jReceiver) stopTraceReceptionLocked() error {
 
 const collectorReceiverTagValue = "jaeger-collector"
 
+// normalizeIDs normalizes the IDs of the spans as configured and returns the
+// spans that are kept.
+func (jr *jReceiver) normalizeIDs(spans []*tracepb.Span) []*tracepb.Span {
+	if jr.config == nil {
+		return spans
+	}
+	return tracetranslator.NormalizeIDs(jr.config.IDNormalization, spans)
+}
+
+// limiter returns the limiter of the collector ports, nil if they are not
+// limited.
+func (jr *jReceiver) limiter() *configlimit.Limiter {
+	if jr.config == nil {
+		return nil
+	}
+	return jr.config.CollectorLimiter
+}
+
 func (jr *jReceiver) SubmitBatches(ctx thrift.Context, batches []*jaeger.Batch) ([]*jaeger.BatchSubmitResponse, error) {
 	jbsr := make([]*jaeger.BatchSubmitResponse, 0, len(batches))
 	ctxWithReceiverName := observability.ContextWithReceiverName(ctx, collectorReceiverTagValue)
 
+	// Both thrift-tchannel and thrift-http submit the batches here, the rate
+	// and the request size are limited by the HTTP handler only.
+	limiter := jr.limiter()
+	limitCtx := obsreport.WithTransport(ctx, "thrift")
+
+	// The error of the next consumer is returned, e.g. for the HTTP handler
+	// to ask the client to slow down when the pipeline is overloaded.
+	var consumerErr error
 	for _, batch := range batches {
 		td, err := jaegertranslator.ThriftBatchToOCProto(batch)
 		// TODO: (@odeke-em) add this error for Jaeger observability
 		ok := false
 
+		if err == nil && limiter != nil {
+			err = limiter.CheckSpans(limitCtx, td.Spans)
+		}
 		if err == nil {
-			ok = true
+			td.Spans = jr.normalizeIDs(td.Spans)
 			td.SourceFormat = "jaeger"
-			jr.nextConsumer.ConsumeTraceData(ctx, td)
+			if err = jr.nextConsumer.ConsumeTraceData(ctx, td); err != nil {
+				consumerErr = err
+			} else {
+				ok = true
+			}
 			// We MUST unconditionally record metrics from this reception.
 			observability.RecordTraceReceiverMetrics(ctxWithReceiverName, len(batch.Spans), len(batch.Spans)-len(td.Spans))
 		}
@@ -276,7 +305,7 @@ func (jr *jReceiver) SubmitBatches(ctx thrift.Context, batches []*jaeger.Batch)
 			Ok: ok,
 		})
 	}
-	return jbsr, nil
+	return jbsr, consumerErr
 }
 
 var _ reporter.Reporter = (*jReceiver)(nil)
@@ -298,6 +327,7 @@ func (jr *jReceiver) EmitBatch(batch *jaeger.Batch) error {
 		return err
 	}
 
+	td.Spans = jr.normalizeIDs(td.Spans)
 	err = jr.nextConsumer.ConsumeTraceData(jr.defaultAgentCtx, td)
 	observability.RecordTraceReceiverMetrics(jr.defaultAgentCtx, len(batch.Spans), len(batch.Spans)-len(td.Spans))
 
@@ -329,34 +359,53 @@ func (jr *jReceiver) PostSpans(ctx context.Context, r *api_v2.PostSpansRequest)
 		observability.RecordTraceReceiverMetrics(ctxWithReceiverName, len(r.Batch.Spans), len(r.Batch.Spans))
 		return nil, err
 	}
+	// The rate and the request size are limited by the gRPC server.
+	if limiter := jr.limiter(); limiter != nil {
+		if err := limiter.CheckSpans(obsreport.WithTransport(ctx, "grpc"), td.Spans); err != nil {
+			return nil, configlimit.GRPCError(err)
+		}
+	}
+	td.Spans = jr.normalizeIDs(td.Spans)
 
+	// Pass the client of the RPC to the processors and exporters that depend
+	// on it.
+	if c, ok := client.FromGRPC(ctx); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	ctx = obsreport.WithTransport(ctx, "grpc")
 	err = jr.nextConsumer.ConsumeTraceData(ctx, td)
 	observability.RecordTraceReceiverMetrics(ctxWithReceiverName, len(r.Batch.Spans), len(r.Batch.Spans)-len(td.Spans))
 	if err != nil {
-		return nil, err
+		// Ask the clients to slow down if the pipeline is overloaded.
+		return nil, consumererror.ToGRPC(err)
 	}
 
 	return &api_v2.PostSpansResponse{}, err
 }
 
 func (jr *jReceiver) startAgent(_ receiver.Host) error {
-	processorConfigs := []agentapp.ProcessorConfiguration{
-		{
-			// Compact Thrift running by default on 6831.
+	var processorConfigs []agentapp.ProcessorConfiguration
+	if addr := jr.agentCompactThriftAddr(); addr != "" {
+		processorConfigs = append(processorConfigs, agentapp.ProcessorConfiguration{
 			Model:    "jaeger",
 			Protocol: "compact",
 			Server: agentapp.ServerConfiguration{
-				HostPort: jr.agentCompactThriftAddr(),
+				HostPort: addr,
 			},
-		},
-		{
-			// Binary Thrift running by default on 6832.
+		})
+	}
+	if addr := jr.agentBinaryThriftAddr(); addr != "" {
+		processorConfigs = append(processorConfigs, agentapp.ProcessorConfiguration{
 			Model:    "jaeger",
 			Protocol: "binary",
 			Server: agentapp.ServerConfiguration{
-				HostPort: jr.agentBinaryThriftAddr(),
+				HostPort: addr,
 			},
-		},
+		})
+	}
+	if len(processorConfigs) == 0 {
+		// Neither of the agent protocols is configured.
+		return nil
 	}
 
 	builder := agentapp.Builder{
@@ -381,51 +430,155 @@ func (jr *jReceiver) startAgent(_ receiver.Host) error {
 	return nil
 }
 
-func (jr *jReceiver) startCollector(host receiver.Host) error {
-	tch, terr := tchannel.NewChannel("jaeger-collector", new(tchannel.ChannelOptions))
-	if terr != nil {
-		return fmt.Errorf("failed to create NewTChannel: %v", terr)
+// NewThriftHTTPHandler returns the handler of the thrift-http collector
+// protocol, i.e. of the POST requests on /api/traces, sending the received
+// batches to nextConsumer. It lets the other HTTP receivers accept the Jaeger
+// payloads, only the CollectorLimiter and the IDNormalization of the
+// configuration are used.
+func NewThriftHTTPHandler(config *Configuration, nextConsumer consumer.TraceConsumer) http.Handler {
+	if config == nil {
+		config = &Configuration{}
 	}
+	return thriftHTTPHandler(&jReceiver{
+		config:       config,
+		nextConsumer: nextConsumer,
+	})
+}
+
+// acceptedThriftFormats are the content types of the thrift-http requests
+// accepted by the Jaeger collector.
+var acceptedThriftFormats = map[string]bool{
+	"application/x-thrift":                 true,
+	"application/vnd.apache.thrift.binary": true,
+}
 
-	server := thrift.NewServer(tch)
-	server.Register(jaeger.NewTChanCollectorServer(jr))
+// thriftHTTPHandler handles the POST requests on /api/traces like the Jaeger
+// collector, but asks the clients to slow down with 429 Too Many Requests
+// when the pipeline is overloaded rather than failing the requests.
+func thriftHTTPHandler(jr *jReceiver) http.Handler {
+	nr := mux.NewRouter()
+	nr.HandleFunc("/api/traces", jr.serveThriftHTTP).Methods(http.MethodPost)
+	return nr
+}
 
-	taddr := jr.tchannelAddr()
-	tln, terr := net.Listen("tcp", taddr)
-	if terr != nil {
-		return fmt.Errorf("failed to bind to TChannnel address %q: %v", taddr, terr)
+func (jr *jReceiver) serveThriftHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Unable to process request body: %v", err), http.StatusInternalServerError)
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Cannot parse content type: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !acceptedThriftFormats[contentType] {
+		http.Error(w, fmt.Sprintf("Unsupported content type: %v", contentType), http.StatusBadRequest)
+		return
+	}
+	batch := &jaeger.Batch{}
+	if err := apachethrift.NewTDeserializer().Read(batch, body); err != nil {
+		http.Error(w, fmt.Sprintf("Unable to process request body: %v", err), http.StatusBadRequest)
+		return
 	}
-	tch.Serve(tln)
-	jr.tchannel = tch
 
-	// Now the collector that runs over HTTP
-	caddr := jr.collectorAddr()
-	cln, cerr := net.Listen("tcp", caddr)
-	if cerr != nil {
-		// Abort and close tch
-		tch.Close()
-		return fmt.Errorf("failed to bind to Collector address %q: %v", caddr, cerr)
+	if _, err := jr.SubmitBatches(thrift.Wrap(r.Context()), []*jaeger.Batch{batch}); err != nil {
+		if !consumererror.WriteHTTPOverloaded(w, err) {
+			http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusInternalServerError)
+		}
+		return
 	}
+	w.WriteHeader(http.StatusAccepted)
+}
 
-	nr := mux.NewRouter()
-	apiHandler := app.NewAPIHandler(jr)
-	apiHandler.RegisterRoutes(nr)
-	jr.collectorServer = &http.Server{Handler: nr}
-	go func() {
-		_ = jr.collectorServer.Serve(cln)
-	}()
+func (jr *jReceiver) startCollector(host receiver.Host) error {
+	limiter := jr.limiter()
+
+	if taddr := jr.tchannelAddr(); taddr != "" {
+		tch, terr := tchannel.NewChannel("jaeger-collector", new(tchannel.ChannelOptions))
+		if terr != nil {
+			return fmt.Errorf("failed to create NewTChannel: %v", terr)
+		}
+
+		server := thrift.NewServer(tch)
+		server.Register(jaeger.NewTChanCollectorServer(jr))
+
+		tln, terr := net.Listen("tcp", taddr)
+		if terr != nil {
+			tch.Close()
+			return fmt.Errorf("failed to bind to TChannnel address %q: %v", taddr, terr)
+		}
+		if limiter != nil {
+			tln = limiter.Listener(tln)
+		}
+		tch.Serve(tln)
+		jr.tchannel = tch
+	}
+
+	// Now the collector that runs over HTTP
+	if caddr := jr.collectorAddr(); caddr != "" {
+		cln, cerr := net.Listen("tcp", caddr)
+		if cerr != nil {
+			// The caller closes the servers already started.
+			return fmt.Errorf("failed to bind to Collector address %q: %v", caddr, cerr)
+		}
+		if limiter != nil {
+			cln = limiter.Listener(cln)
+		}
+		if jr.config.CollectorTLSConfig != nil {
+			cln = tls.NewListener(cln, jr.config.CollectorTLSConfig)
+		}
+
+		handler := thriftHTTPHandler(jr)
+		if jr.config.CollectorAuthenticator != nil {
+			handler = configauth.HTTPHandler(jr.config.CollectorAuthenticator, handler)
+		}
+		// The limits are checked first, the authentication can be expensive.
+		if limiter != nil {
+			handler = configlimit.HTTPHandler(limiter, handler)
+		}
+		jr.collectorServer = &http.Server{Handler: handler}
+		go func() {
+			_ = jr.collectorServer.Serve(cln)
+		}()
+	}
 
 	// And finally, the gRPC server
-	jr.grpc = grpc.NewServer()
 	gaddr := jr.grpcAddr()
+	if gaddr == "" {
+		return nil
+	}
+	var grpcOpts []grpc.ServerOption
+	if jr.config.CollectorTLSConfig != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(jr.config.CollectorTLSConfig)))
+	}
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if limiter != nil {
+		grpcOpts = append(grpcOpts, limiter.GRPCServerOptions()...)
+		unary = append(unary, configlimit.UnaryServerInterceptor(limiter))
+		stream = append(stream, configlimit.StreamServerInterceptor(limiter))
+	}
+	if jr.config.CollectorAuthenticator != nil {
+		unary = append(unary, configauth.UnaryServerInterceptor(jr.config.CollectorAuthenticator))
+		stream = append(stream, configauth.StreamServerInterceptor(jr.config.CollectorAuthenticator))
+	}
+	if len(unary) > 0 {
+		grpcOpts = append(grpcOpts,
+			grpc.UnaryInterceptor(configgrpc.ChainUnaryServerInterceptors(unary...)),
+			grpc.StreamInterceptor(configgrpc.ChainStreamServerInterceptors(stream...)))
+	}
 	gln, gerr := net.Listen("tcp", gaddr)
 	if gerr != nil {
-		// Abort and close tch, cln
-		tch.Close()
-		cln.Close()
+		// The caller closes the servers already started.
 		return fmt.Errorf("failed to bind to gRPC address %q: %v", gaddr, gerr)
 	}
+	if limiter != nil {
+		gln = limiter.Listener(gln)
+	}
 
+	jr.grpc = grpc.NewServer(grpcOpts...)
 	api_v2.RegisterCollectorServiceServer(jr.grpc, jr)
 
 	go func() {
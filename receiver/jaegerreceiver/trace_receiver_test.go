@@ -31,10 +31,18 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
-
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal"
+	"github.com/open-telemetry/opentelemetry-service/receiver/conformance"
 	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
 	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
@@ -137,6 +145,155 @@ func TestGRPCReception(t *testing.T) {
 
 }
 
+func TestGRPCReceptionWithClientAllowList(t *testing.T) {
+	serverTLS, err := configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   "../../config/configtls/testdata/ca.crt",
+			CertFile: "../../config/configtls/testdata/server.crt",
+			KeyFile:  "../../config/configtls/testdata/server.key",
+		},
+		ClientAllowedOUs: []string{"client"},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	config := &Configuration{
+		CollectorGRPCPort:  14251,
+		CollectorTLSConfig: serverTLS,
+	}
+	sink := new(exportertest.SinkTraceExporter)
+
+	jr, err := New(context.Background(), config, sink)
+	require.NoError(t, err)
+	defer jr.StopTraceReception()
+
+	mh := receivertest.NewMockHost()
+	require.NoError(t, jr.StartTraceReception(mh))
+
+	postSpans := func(certFile, keyFile string) error {
+		clientTLS, err := configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{
+				CAFile:   "../../config/configtls/testdata/ca.crt",
+				CertFile: certFile,
+				KeyFile:  keyFile,
+			},
+		}.LoadTLSConfig()
+		require.NoError(t, err)
+
+		conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", config.CollectorGRPCPort),
+			grpc.WithTransportCredentials(credentials.NewTLS(clientTLS)))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		req := grpcFixture(time.Unix(1542158650, 536343000).UTC(), 10*time.Minute, 2*time.Second)
+		_, err = api_v2.NewCollectorServiceClient(conn).PostSpans(ctx, req)
+		return err
+	}
+
+	// The server certificate has the "server" OU, it is not allowed.
+	assert.Error(t, postSpans(
+		"../../config/configtls/testdata/server.crt", "../../config/configtls/testdata/server.key"))
+	assert.Len(t, sink.AllTraces(), 0)
+
+	assert.NoError(t, postSpans(
+		"../../config/configtls/testdata/client.crt", "../../config/configtls/testdata/client.key"))
+	assert.Len(t, sink.AllTraces(), 1)
+}
+
+func TestGRPCReceptionWithAuthentication(t *testing.T) {
+	a, err := configauth.Settings{BearerTokens: []string{"token"}}.NewAuthenticator()
+	require.NoError(t, err)
+	config := &Configuration{
+		CollectorGRPCPort:      14252,
+		CollectorAuthenticator: a,
+	}
+	sink := new(exportertest.SinkTraceExporter)
+
+	jr, err := New(context.Background(), config, sink)
+	require.NoError(t, err)
+	defer jr.StopTraceReception()
+
+	mh := receivertest.NewMockHost()
+	require.NoError(t, jr.StartTraceReception(mh))
+
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", config.CollectorGRPCPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := api_v2.NewCollectorServiceClient(conn)
+	req := grpcFixture(time.Unix(1542158650, 536343000).UTC(), 10*time.Minute, 2*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = client.PostSpans(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Len(t, sink.AllTraces(), 0)
+
+	_, err = client.PostSpans(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token"), req)
+	assert.NoError(t, err)
+	assert.Len(t, sink.AllTraces(), 1)
+}
+
+func TestGRPCReceptionWithLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings configlimit.Settings
+		wantCode codes.Code
+	}{
+		{name: "none", wantCode: codes.OK},
+		{name: "batch size", settings: configlimit.Settings{MaxBatchSize: 1}, wantCode: codes.ResourceExhausted},
+		{name: "attribute length", settings: configlimit.Settings{MaxAttributeLength: 5}, wantCode: codes.InvalidArgument},
+		{name: "request size", settings: configlimit.Settings{MaxRequestSize: 64}, wantCode: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := tt.settings.NewLimiter("jaeger")
+			require.NoError(t, err)
+			config := &Configuration{
+				CollectorGRPCPort: 14253,
+				CollectorLimiter:  l,
+			}
+			sink := new(exportertest.SinkTraceExporter)
+
+			jr, err := New(context.Background(), config, sink)
+			require.NoError(t, err)
+			defer jr.StopTraceReception()
+			require.NoError(t, jr.StartTraceReception(receivertest.NewMockHost()))
+
+			conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", config.CollectorGRPCPort), grpc.WithInsecure())
+			require.NoError(t, err)
+			defer conn.Close()
+			req := grpcFixture(time.Unix(1542158650, 536343000).UTC(), 10*time.Minute, 2*time.Second)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err = api_v2.NewCollectorServiceClient(conn).PostSpans(ctx, req, grpc.WaitForReady(true))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, len(sink.AllTraces()) == 1)
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	config := &Configuration{
+		CollectorHTTPPort: 14270,
+	}
+	sink := new(exportertest.SinkTraceExporter)
+
+	jr, err := New(context.Background(), config, sink)
+	require.NoError(t, err)
+	defer jr.StopTraceReception()
+
+	require.NoError(t, jr.StartTraceReception(receivertest.NewMockHost()))
+
+	conformance.Run(t, conformance.Target{
+		Endpoints: map[conformance.Protocol]string{
+			conformance.JaegerThriftHTTP: fmt.Sprintf("http://localhost:%d", config.CollectorHTTPPort),
+		},
+		Sink: sink,
+	})
+}
+
 func expectedTraceData(t1, t2, t3 time.Time) []consumerdata.TraceData {
 	traceID := []byte{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8, 0xF9, 0xFA, 0xFB, 0xFC, 0xFD, 0xFE, 0xFF, 0x80}
 	parentSpanID := []byte{0x1F, 0x1E, 0x1D, 0x1C, 0x1B, 0x1A, 0x19, 0x18}
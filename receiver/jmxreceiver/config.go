@@ -0,0 +1,79 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
+)
+
+// Config defines configuration for the JMX receiver. The Endpoint is the URL
+// of the Jolokia agent of the JVM, e.g. "http://localhost:8778/jolokia".
+type Config struct {
+	configmodels.ReceiverSettings   `mapstructure:",squash"`
+	receiverhelper.ScheduleSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the MBeans are read.
+	CollectionInterval time.Duration `mapstructure:"collection-interval"`
+
+	// Timeout of the requests to the Jolokia agent.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Username and Password are the credentials of the basic authentication
+	// of the Jolokia agent, if any.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// TargetSystems are the presets of MBeans to read: "jvm", "kafka",
+	// "cassandra" or "tomcat".
+	TargetSystems []string `mapstructure:"target-systems"`
+
+	// Beans are read in addition to the MBeans of the target systems.
+	Beans []BeanConfig `mapstructure:"beans"`
+}
+
+// BeanConfig maps an attribute of the MBeans matching an object name, or
+// object name pattern, to a metric with a time series per MBean.
+type BeanConfig struct {
+	// MBean is the object name, or object name pattern, of the MBeans.
+	MBean string `mapstructure:"mbean"`
+
+	// Attribute is the name of the attribute read.
+	Attribute string `mapstructure:"attribute"`
+
+	// Path selects a value inside a composite attribute, the keys being
+	// separated by ".", e.g. "used" for the "HeapMemoryUsage" attribute.
+	Path string `mapstructure:"path"`
+
+	// Metric is the name of the metric.
+	Metric string `mapstructure:"metric"`
+
+	// Description and Unit of the metric.
+	Description string `mapstructure:"description"`
+	Unit        string `mapstructure:"unit"`
+
+	// Type is either "gauge", the default, or "cumulative".
+	Type string `mapstructure:"type"`
+
+	// Labels are the keys of the object names of the MBeans used as labels.
+	Labels []string `mapstructure:"labels"`
+}
+
+// ListenEndpoints returns nil, the endpoint is the one of the Jolokia agent.
+func (cfg *Config) ListenEndpoints() []string {
+	return nil
+}
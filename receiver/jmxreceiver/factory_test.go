@@ -0,0 +1,72 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateReceiver(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
+	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	assert.Nil(t, tReceiver)
+
+	mReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, exportertest.NewNopMetricsExporter())
+	assert.Nil(t, err, "receiver creation failed")
+	assert.NotNil(t, mReceiver, "receiver creation failed")
+}
+
+func TestCreateReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{name: "no_endpoint", modify: func(cfg *Config) { cfg.Endpoint = "" }},
+		{name: "no_interval", modify: func(cfg *Config) { cfg.CollectionInterval = 0 }},
+		{name: "unknown_target_system", modify: func(cfg *Config) { cfg.TargetSystems = []string{"hadoop"} }},
+		{name: "nothing_to_read", modify: func(cfg *Config) { cfg.TargetSystems = nil }},
+		{name: "incomplete_bean", modify: func(cfg *Config) { cfg.Beans = []BeanConfig{{MBean: "java.lang:type=Memory"}} }},
+		{name: "invalid_bean_type", modify: func(cfg *Config) {
+			cfg.Beans = []BeanConfig{{MBean: "java.lang:type=Threading", Attribute: "ThreadCount", Metric: "threads", Type: "counter"}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &Factory{}
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			mReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, exportertest.NewNopMetricsExporter())
+			assert.Error(t, err)
+			assert.Nil(t, mReceiver)
+		})
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jmxreceiver reads the MBeans of a JVM through its Jolokia agent
+// and converts their attributes to metrics.
+package jmxreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
+)
+
+var (
+	errNilNextConsumer = errors.New("nil nextConsumer")
+	errAlreadyStarted  = errors.New("already started")
+	errAlreadyStopped  = errors.New("already stopped")
+)
+
+const metricsSource = "JMX"
+
+var _ receiver.MetricsReceiver = (*jmxReceiver)(nil)
+
+// jmxReceiver implements the receiver.MetricsReceiver for the MBeans read
+// from a Jolokia agent.
+type jmxReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	client       *jolokiaClient
+	beans        []BeanConfig
+	requests     []jolokiaRequest
+	requestIndex []int
+	nextConsumer consumer.MetricsConsumer
+	scheduler    *receiverhelper.Scheduler
+	startTime    *timestamp.Timestamp
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func newJMXReceiver(
+	logger *zap.Logger,
+	cfg *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (*jmxReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	beans, err := configuredBeans(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &jmxReceiver{
+		logger:       logger,
+		client:       newJolokiaClient(cfg.Endpoint, cfg.Username, cfg.Password, cfg.Timeout),
+		beans:        beans,
+		nextConsumer: nextConsumer,
+	}
+	// The beans sharing an attribute, e.g. the parts of the heap memory
+	// usage, share its request.
+	indexes := make(map[jolokiaRequest]int)
+	for _, bean := range beans {
+		req := jolokiaRequest{Type: "read", MBean: bean.MBean, Attribute: bean.Attribute}
+		i, ok := indexes[req]
+		if !ok {
+			i = len(r.requests)
+			indexes[req] = i
+			r.requests = append(r.requests, req)
+		}
+		r.requestIndex = append(r.requestIndex, i)
+	}
+	r.scheduler = receiverhelper.NewScheduler(cfg.CollectionInterval, cfg.ScheduleSettings, r.scrape)
+	return r, nil
+}
+
+// configuredBeans returns the beans of the target systems followed by the
+// beans of the configuration.
+func configuredBeans(cfg *Config) ([]BeanConfig, error) {
+	var beans []BeanConfig
+	for _, system := range cfg.TargetSystems {
+		preset, ok := targetSystems[system]
+		if !ok {
+			return nil, fmt.Errorf("unknown target system %q", system)
+		}
+		beans = append(beans, preset...)
+	}
+	for i, bean := range cfg.Beans {
+		if bean.MBean == "" || bean.Attribute == "" || bean.Metric == "" {
+			return nil, fmt.Errorf("bean %d must have an \"mbean\", an \"attribute\" and a \"metric\"", i)
+		}
+		if bean.Type != "" && bean.Type != metricTypeGauge && bean.Type != metricTypeCumulative {
+			return nil, fmt.Errorf("type of bean %d must be %q or %q, got %q", i, metricTypeGauge, metricTypeCumulative, bean.Type)
+		}
+		beans = append(beans, bean)
+	}
+	if len(beans) == 0 {
+		return nil, errors.New("no target system or bean to read")
+	}
+	return beans, nil
+}
+
+// MetricsSource returns the name of the metrics data source.
+func (r *jmxReceiver) MetricsSource() string {
+	return metricsSource
+}
+
+// StartMetricsReception starts reading the MBeans periodically.
+func (r *jmxReceiver) StartMetricsReception(host receiver.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := errAlreadyStarted
+	r.startOnce.Do(func() {
+		now := time.Now()
+		r.startTime = &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())}
+		r.scheduler.Start()
+		err = nil
+	})
+	return err
+}
+
+// StopMetricsReception stops reading the MBeans, waiting for a read in
+// progress.
+func (r *jmxReceiver) StopMetricsReception() error {
+	r.Lock()
+	defer r.Unlock()
+
+	var err = errAlreadyStopped
+	r.stopOnce.Do(func() {
+		r.scheduler.Stop()
+		err = nil
+	})
+	return err
+}
+
+func (r *jmxReceiver) scrape() {
+	resps, err := r.client.read(r.requests)
+	if err != nil {
+		r.logger.Error("Failed to read the MBeans from the Jolokia agent", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	ts := &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())}
+	metrics := make([]*metricspb.Metric, 0, len(r.beans))
+	for i, bean := range r.beans {
+		resp := resps[r.requestIndex[i]]
+		if resp.Status != 200 {
+			// E.g. the MBean isn't registered (yet).
+			r.logger.Debug("Failed to read an MBean attribute",
+				zap.String("mbean", bean.MBean), zap.String("attribute", bean.Attribute), zap.String("error", resp.Error))
+			continue
+		}
+		m, err := r.beanMetric(bean, resp.Value, ts)
+		if err != nil {
+			r.logger.Debug("Failed to convert an MBean attribute",
+				zap.String("mbean", bean.MBean), zap.String("attribute", bean.Attribute), zap.Error(err))
+			continue
+		}
+		if m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	if len(metrics) == 0 {
+		return
+	}
+
+	ctx := obsreport.WithTransport(context.Background(), "http")
+	if err := r.nextConsumer.ConsumeMetricsData(ctx, consumerdata.MetricsData{Metrics: metrics}); err != nil {
+		r.logger.Debug("Failed to send the JMX metrics", zap.Error(err))
+	}
+}
+
+// beanMetric returns the metric of the bean, with a time series per MBean,
+// or nil if no MBean matched.
+func (r *jmxReceiver) beanMetric(bean BeanConfig, value json.RawMessage, ts *timestamp.Timestamp) (*metricspb.Metric, error) {
+	values := make(map[string]interface{})
+	if isPattern(bean.MBean) {
+		var byObjectName map[string]map[string]interface{}
+		if err := json.Unmarshal(value, &byObjectName); err != nil {
+			return nil, err
+		}
+		for objectName, attrs := range byObjectName {
+			if v, ok := attrs[bean.Attribute]; ok {
+				values[objectName] = v
+			}
+		}
+	} else {
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		values[bean.MBean] = v
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	objectNames := make([]string, 0, len(values))
+	for objectName := range values {
+		objectNames = append(objectNames, objectName)
+	}
+	sort.Strings(objectNames)
+
+	metricType := metricspb.MetricDescriptor_GAUGE_DOUBLE
+	var startTime *timestamp.Timestamp
+	if bean.Type == metricTypeCumulative {
+		metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+		startTime = r.startTime
+	}
+
+	labelKeys := make([]*metricspb.LabelKey, 0, len(bean.Labels))
+	for _, label := range bean.Labels {
+		labelKeys = append(labelKeys, &metricspb.LabelKey{Key: label})
+	}
+	timeseries := make([]*metricspb.TimeSeries, 0, len(objectNames))
+	for _, objectName := range objectNames {
+		v, err := numberAtPath(values[objectName], bean.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", objectName, err)
+		}
+		props := objectNameProperties(objectName)
+		labelValues := make([]*metricspb.LabelValue, 0, len(bean.Labels))
+		for _, label := range bean.Labels {
+			lv, ok := props[label]
+			labelValues = append(labelValues, &metricspb.LabelValue{Value: lv, HasValue: ok})
+		}
+		timeseries = append(timeseries, &metricspb.TimeSeries{
+			StartTimestamp: startTime,
+			LabelValues:    labelValues,
+			Points: []*metricspb.Point{
+				{
+					Timestamp: ts,
+					Value:     &metricspb.Point_DoubleValue{DoubleValue: v},
+				},
+			},
+		})
+	}
+
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        bean.Metric,
+			Description: bean.Description,
+			Unit:        bean.Unit,
+			Type:        metricType,
+			LabelKeys:   labelKeys,
+		},
+		Timeseries: timeseries,
+	}, nil
+}
+
+// numberAtPath returns the number at the "."-separated path of a composite
+// value, or the value itself if the path is empty.
+func numberAtPath(v interface{}, path string) (float64, error) {
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			composite, ok := v.(map[string]interface{})
+			if !ok {
+				return 0, fmt.Errorf("no %q in a non composite value", key)
+			}
+			if v, ok = composite[key]; !ok {
+				return 0, fmt.Errorf("no %q in the composite value", key)
+			}
+		}
+	}
+
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case bool:
+		if n {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		// Jolokia may serialize the longs as strings.
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("value %v is not a number", v)
+	}
+}
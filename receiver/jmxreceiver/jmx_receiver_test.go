@@ -0,0 +1,191 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+// fakeJolokia answers the read requests with the values by MBean and
+// attribute, the other MBeans are not found.
+func fakeJolokia(t *testing.T, values map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, _ := req.BasicAuth(); user != "monitor" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var reqs []jolokiaRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&reqs))
+
+		resps := make([]map[string]interface{}, 0, len(reqs))
+		for _, r := range reqs {
+			assert.Equal(t, "read", r.Type)
+			v, ok := values[r.MBean][r.Attribute]
+			if !ok {
+				resps = append(resps, map[string]interface{}{"status": 404, "error": "javax.management.InstanceNotFoundException"})
+				continue
+			}
+			resps = append(resps, map[string]interface{}{"status": 200, "value": v})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resps))
+	}))
+}
+
+func newTestReceiver(t *testing.T, endpoint string, sink *exportertest.SinkMetricsExporter, beans ...BeanConfig) *jmxReceiver {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.Username = "monitor"
+	cfg.Password = "secret"
+	cfg.Beans = beans
+	r, err := newJMXReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	return r
+}
+
+func TestScrape(t *testing.T) {
+	server := fakeJolokia(t, map[string]map[string]interface{}{
+		"java.lang:type=Memory": {
+			"HeapMemoryUsage": map[string]interface{}{"used": 100, "committed": 200, "max": 400},
+		},
+		"java.lang:type=GarbageCollector,name=*": {
+			"CollectionCount": map[string]interface{}{
+				"java.lang:name=G1 Young Generation,type=GarbageCollector": map[string]interface{}{"CollectionCount": 12},
+				"java.lang:name=G1 Old Generation,type=GarbageCollector":   map[string]interface{}{"CollectionCount": "3"},
+			},
+		},
+		"app:type=Cache": {
+			"Enabled": true,
+		},
+	})
+	defer server.Close()
+
+	sink := new(exportertest.SinkMetricsExporter)
+	r := newTestReceiver(t, server.URL, sink, BeanConfig{
+		MBean:     "app:type=Cache",
+		Attribute: "Enabled",
+		Metric:    "app.cache.enabled",
+	})
+	// The parts of the heap memory usage share a request.
+	assert.Equal(t, len(r.beans)-2, len(r.requests))
+
+	require.NoError(t, r.StartMetricsReception(nil))
+	r.scrape()
+	require.NoError(t, r.StopMetricsReception())
+
+	require.Equal(t, 1, len(sink.AllMetrics()))
+	metrics := make(map[string]*metricspb.Metric)
+	for _, m := range sink.AllMetrics()[0].Metrics {
+		metrics[m.MetricDescriptor.Name] = m
+	}
+	// The MBeans not found are skipped.
+	assert.Equal(t, 5, len(metrics))
+
+	heapMax := metrics["jvm.memory.heap.max"]
+	require.NotNil(t, heapMax)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, heapMax.MetricDescriptor.Type)
+	assert.Equal(t, unitBytes, heapMax.MetricDescriptor.Unit)
+	assert.Equal(t, 400.0, heapMax.Timeseries[0].Points[0].GetDoubleValue())
+	assert.Nil(t, heapMax.Timeseries[0].StartTimestamp)
+
+	gc := metrics["jvm.gc.collections.count"]
+	require.NotNil(t, gc)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, gc.MetricDescriptor.Type)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "name"}}, gc.MetricDescriptor.LabelKeys)
+	require.Equal(t, 2, len(gc.Timeseries))
+	// The time series are sorted by object name.
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "G1 Old Generation", HasValue: true}}, gc.Timeseries[0].LabelValues)
+	assert.Equal(t, 3.0, gc.Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "G1 Young Generation", HasValue: true}}, gc.Timeseries[1].LabelValues)
+	assert.Equal(t, 12.0, gc.Timeseries[1].Points[0].GetDoubleValue())
+	assert.Equal(t, r.startTime, gc.Timeseries[0].StartTimestamp)
+
+	assert.Equal(t, 1.0, metrics["app.cache.enabled"].Timeseries[0].Points[0].GetDoubleValue())
+}
+
+func TestScrape_AgentError(t *testing.T) {
+	server := fakeJolokia(t, nil)
+	defer server.Close()
+
+	sink := new(exportertest.SinkMetricsExporter)
+	r := newTestReceiver(t, server.URL, sink)
+	r.client.password = "wrong"
+	r.scrape()
+	assert.Equal(t, 0, len(sink.AllMetrics()))
+
+	r = newTestReceiver(t, "http://localhost:1/jolokia", sink)
+	r.scrape()
+	assert.Equal(t, 0, len(sink.AllMetrics()))
+}
+
+func TestStartStop(t *testing.T) {
+	server := fakeJolokia(t, map[string]map[string]interface{}{
+		"java.lang:type=Threading": {"ThreadCount": 42},
+	})
+	defer server.Close()
+
+	sink := new(exportertest.SinkMetricsExporter)
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Username = "monitor"
+	cfg.Password = "secret"
+	cfg.CollectionInterval = 10 * time.Millisecond
+	r, err := newJMXReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.StartMetricsReception(nil))
+	assert.Equal(t, errAlreadyStarted, r.StartMetricsReception(nil))
+	for i := 0; i < 500 && sink.MetricsCount() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, r.StopMetricsReception())
+	assert.Equal(t, errAlreadyStopped, r.StopMetricsReception())
+	assert.NotZero(t, sink.MetricsCount())
+}
+
+func TestNumberAtPath(t *testing.T) {
+	composite := map[string]interface{}{
+		"usage": map[string]interface{}{"used": 1.0},
+	}
+	v, err := numberAtPath(composite, "usage.used")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+
+	_, err = numberAtPath(composite, "usage.max")
+	assert.Error(t, err)
+	_, err = numberAtPath(composite, "usage.used.value")
+	assert.Error(t, err)
+	_, err = numberAtPath(composite, "")
+	assert.Error(t, err)
+	_, err = numberAtPath("not a number", "")
+	assert.Error(t, err)
+}
+
+func TestObjectNameProperties(t *testing.T) {
+	assert.Equal(t,
+		map[string]string{"type": "Manager", "host": "localhost", "context": "/app"},
+		objectNameProperties(`Catalina:type=Manager,host=localhost,context="/app"`))
+	assert.Equal(t, map[string]string{}, objectNameProperties("invalid"))
+}
@@ -0,0 +1,118 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// jolokiaClient reads MBean attributes with the bulk requests of the Jolokia
+// HTTP API, see https://jolokia.org/reference/html/protocol.html.
+type jolokiaClient struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+}
+
+type jolokiaRequest struct {
+	Type      string `json:"type"`
+	MBean     string `json:"mbean"`
+	Attribute string `json:"attribute"`
+}
+
+type jolokiaResponse struct {
+	Status int             `json:"status"`
+	Error  string          `json:"error"`
+	Value  json.RawMessage `json:"value"`
+}
+
+func newJolokiaClient(endpoint, username, password string, timeout time.Duration) *jolokiaClient {
+	return &jolokiaClient{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// read sends the read requests in a single bulk request, the responses are in
+// the order of the requests.
+func (c *jolokiaClient) read(reqs []jolokiaRequest) ([]jolokiaResponse, error) {
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.username != "" {
+		httpReq.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Drain the body to reuse the connection.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jolokia agent responded with %s", resp.Status)
+	}
+
+	var resps []jolokiaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&resps); err != nil {
+		return nil, fmt.Errorf("invalid jolokia response: %v", err)
+	}
+	if len(resps) != len(reqs) {
+		return nil, fmt.Errorf("jolokia agent sent %d responses to %d requests", len(resps), len(reqs))
+	}
+	return resps, nil
+}
+
+// isPattern returns true if the object name is a pattern: the value of a read
+// is then the attributes of each matching MBean, by object name.
+func isPattern(objectName string) bool {
+	return strings.ContainsAny(objectName, "*?")
+}
+
+// objectNameProperties returns the key properties of an object name, e.g.
+// "java.lang:type=GarbageCollector,name=G1 Young Generation".
+func objectNameProperties(objectName string) map[string]string {
+	props := make(map[string]string)
+	i := strings.IndexByte(objectName, ':')
+	if i < 0 {
+		return props
+	}
+	for _, prop := range strings.Split(objectName[i+1:], ",") {
+		kv := strings.SplitN(prop, "=", 2)
+		if len(kv) == 2 {
+			props[kv[0]] = strings.Trim(kv[1], "\"")
+		}
+	}
+	return props
+}
@@ -0,0 +1,271 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jmxreceiver
+
+// This file defines the MBeans read for the target systems.
+
+const (
+	metricTypeGauge      = "gauge"
+	metricTypeCumulative = "cumulative"
+
+	unitBytes         = "By"
+	unitMilliseconds  = "ms"
+	unitDimensionless = "1"
+)
+
+var targetSystems = map[string][]BeanConfig{
+	"jvm": {
+		{
+			MBean:       "java.lang:type=Memory",
+			Attribute:   "HeapMemoryUsage",
+			Path:        "used",
+			Metric:      "jvm.memory.heap.used",
+			Description: "The current heap memory usage",
+			Unit:        unitBytes,
+		},
+		{
+			MBean:       "java.lang:type=Memory",
+			Attribute:   "HeapMemoryUsage",
+			Path:        "committed",
+			Metric:      "jvm.memory.heap.committed",
+			Description: "The heap memory committed for the JVM",
+			Unit:        unitBytes,
+		},
+		{
+			MBean:       "java.lang:type=Memory",
+			Attribute:   "HeapMemoryUsage",
+			Path:        "max",
+			Metric:      "jvm.memory.heap.max",
+			Description: "The maximum heap memory",
+			Unit:        unitBytes,
+		},
+		{
+			MBean:       "java.lang:type=Memory",
+			Attribute:   "NonHeapMemoryUsage",
+			Path:        "used",
+			Metric:      "jvm.memory.nonheap.used",
+			Description: "The current non-heap memory usage",
+			Unit:        unitBytes,
+		},
+		{
+			MBean:       "java.lang:type=GarbageCollector,name=*",
+			Attribute:   "CollectionCount",
+			Metric:      "jvm.gc.collections.count",
+			Description: "The number of garbage collections",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "java.lang:type=GarbageCollector,name=*",
+			Attribute:   "CollectionTime",
+			Metric:      "jvm.gc.collections.elapsed",
+			Description: "The time spent in garbage collections",
+			Unit:        unitMilliseconds,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "java.lang:type=Threading",
+			Attribute:   "ThreadCount",
+			Metric:      "jvm.threads.count",
+			Description: "The number of live threads",
+			Unit:        unitDimensionless,
+		},
+		{
+			MBean:       "java.lang:type=ClassLoading",
+			Attribute:   "LoadedClassCount",
+			Metric:      "jvm.classes.loaded",
+			Description: "The number of loaded classes",
+			Unit:        unitDimensionless,
+		},
+	},
+	"kafka": {
+		{
+			MBean:       "kafka.server:type=BrokerTopicMetrics,name=MessagesInPerSec",
+			Attribute:   "Count",
+			Metric:      "kafka.messages.in",
+			Description: "The number of messages received by the broker",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+		},
+		{
+			MBean:       "kafka.server:type=BrokerTopicMetrics,name=BytesInPerSec",
+			Attribute:   "Count",
+			Metric:      "kafka.network.in",
+			Description: "The bytes received by the broker",
+			Unit:        unitBytes,
+			Type:        metricTypeCumulative,
+		},
+		{
+			MBean:       "kafka.server:type=BrokerTopicMetrics,name=BytesOutPerSec",
+			Attribute:   "Count",
+			Metric:      "kafka.network.out",
+			Description: "The bytes sent by the broker",
+			Unit:        unitBytes,
+			Type:        metricTypeCumulative,
+		},
+		{
+			MBean:       "kafka.server:type=ReplicaManager,name=PartitionCount",
+			Attribute:   "Value",
+			Metric:      "kafka.partitions.count",
+			Description: "The number of partitions of the broker",
+			Unit:        unitDimensionless,
+		},
+		{
+			MBean:       "kafka.server:type=ReplicaManager,name=UnderReplicatedPartitions",
+			Attribute:   "Value",
+			Metric:      "kafka.partitions.under_replicated",
+			Description: "The number of under replicated partitions",
+			Unit:        unitDimensionless,
+		},
+		{
+			MBean:       "kafka.controller:type=KafkaController,name=OfflinePartitionsCount",
+			Attribute:   "Value",
+			Metric:      "kafka.partitions.offline",
+			Description: "The number of partitions without an active leader",
+			Unit:        unitDimensionless,
+		},
+		{
+			MBean:       "kafka.controller:type=KafkaController,name=ActiveControllerCount",
+			Attribute:   "Value",
+			Metric:      "kafka.controllers.active",
+			Description: "The number of active controllers on the broker",
+			Unit:        unitDimensionless,
+		},
+	},
+	"cassandra": {
+		{
+			MBean:       "org.apache.cassandra.metrics:type=ClientRequest,scope=*,name=Latency",
+			Attribute:   "Count",
+			Metric:      "cassandra.client.requests",
+			Description: "The number of client requests",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"scope"},
+		},
+		{
+			MBean:       "org.apache.cassandra.metrics:type=ClientRequest,scope=*,name=Timeouts",
+			Attribute:   "Count",
+			Metric:      "cassandra.client.requests.timeouts",
+			Description: "The number of client requests that timed out",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"scope"},
+		},
+		{
+			MBean:       "org.apache.cassandra.metrics:type=ClientRequest,scope=*,name=Unavailables",
+			Attribute:   "Count",
+			Metric:      "cassandra.client.requests.unavailables",
+			Description: "The number of client requests failed for unavailable replicas",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"scope"},
+		},
+		{
+			MBean:       "org.apache.cassandra.metrics:type=Storage,name=Load",
+			Attribute:   "Count",
+			Metric:      "cassandra.storage.load",
+			Description: "The size of the data on disk of the node",
+			Unit:        unitBytes,
+		},
+		{
+			MBean:       "org.apache.cassandra.metrics:type=Compaction,name=PendingTasks",
+			Attribute:   "Value",
+			Metric:      "cassandra.compaction.tasks.pending",
+			Description: "The number of pending compactions",
+			Unit:        unitDimensionless,
+		},
+		{
+			MBean:       "org.apache.cassandra.metrics:type=Compaction,name=CompletedTasks",
+			Attribute:   "Value",
+			Metric:      "cassandra.compaction.tasks.completed",
+			Description: "The number of completed compactions",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+		},
+	},
+	"tomcat": {
+		{
+			MBean:       "Catalina:type=GlobalRequestProcessor,name=*",
+			Attribute:   "requestCount",
+			Metric:      "tomcat.requests",
+			Description: "The number of requests processed",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=GlobalRequestProcessor,name=*",
+			Attribute:   "errorCount",
+			Metric:      "tomcat.errors",
+			Description: "The number of requests that failed",
+			Unit:        unitDimensionless,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=GlobalRequestProcessor,name=*",
+			Attribute:   "processingTime",
+			Metric:      "tomcat.processing_time",
+			Description: "The time spent processing requests",
+			Unit:        unitMilliseconds,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=GlobalRequestProcessor,name=*",
+			Attribute:   "bytesReceived",
+			Metric:      "tomcat.network.in",
+			Description: "The bytes received",
+			Unit:        unitBytes,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=GlobalRequestProcessor,name=*",
+			Attribute:   "bytesSent",
+			Metric:      "tomcat.network.out",
+			Description: "The bytes sent",
+			Unit:        unitBytes,
+			Type:        metricTypeCumulative,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=ThreadPool,name=*",
+			Attribute:   "currentThreadsBusy",
+			Metric:      "tomcat.threads.busy",
+			Description: "The number of busy threads",
+			Unit:        unitDimensionless,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=ThreadPool,name=*",
+			Attribute:   "currentThreadCount",
+			Metric:      "tomcat.threads.count",
+			Description: "The number of threads",
+			Unit:        unitDimensionless,
+			Labels:      []string{"name"},
+		},
+		{
+			MBean:       "Catalina:type=Manager,host=*,context=*",
+			Attribute:   "activeSessions",
+			Metric:      "tomcat.sessions.active",
+			Description: "The number of active sessions",
+			Unit:        unitDimensionless,
+			Labels:      []string{"host", "context"},
+		},
+	},
+}
@@ -19,18 +19,56 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver/ocmetrics"
+	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver/octrace"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 // Config defines configuration for OpenCensus receiver.
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
-	// TLSCredentials is a (cert_file, key_file) configuration.
-	TLSCredentials *tlsCredentials `mapstructure:"tls-credentials,omitempty"`
+	// GRPCEndpoint is the address of the gRPC server, it overrides Endpoint.
+	GRPCEndpoint string `mapstructure:"grpc-endpoint,omitempty"`
+
+	// HTTPEndpoint is the address of the HTTP/JSON (grpc-gateway) server. When
+	// it is empty the HTTP/JSON requests are served on the gRPC address.
+	HTTPEndpoint string `mapstructure:"http-endpoint,omitempty"`
+
+	// CorsOrigins are the allowed origins of the CORS requests to the HTTP/JSON
+	// server, CORS is disabled when it is empty. An origin can contain a "*"
+	// wildcard, e.g. "https://*.example.com", "*" alone allows any origin.
+	CorsOrigins []string `mapstructure:"cors-allowed-origins,omitempty"`
+
+	// CorsHeaders are the headers, in addition to the simple ones, that the
+	// CORS requests are allowed to send, e.g. "Authorization".
+	CorsHeaders []string `mapstructure:"cors-allowed-headers,omitempty"`
+
+	// CorsMethods are the methods the CORS requests are allowed to use. The
+	// default is GET, POST and HEAD.
+	CorsMethods []string `mapstructure:"cors-allowed-methods,omitempty"`
+
+	// CorsMaxAge is how long the browsers can cache the responses to the CORS
+	// preflight requests. They aren't cached by default.
+	CorsMaxAge time.Duration `mapstructure:"cors-max-age,omitempty"`
+
+	// TLSCredentials configures TLS on the server. The certificate and key files are
+	// required, a CA file additionally requires and verifies client certificates.
+	TLSCredentials *configtls.TLSServerSetting `mapstructure:"tls-credentials,omitempty"`
+
+	// Authentication rejects the requests without valid credentials.
+	Authentication *configauth.Settings `mapstructure:"authentication,omitempty"`
+
+	// Limits protect the receiver from clients sending more data than it can handle.
+	Limits *configlimit.Settings `mapstructure:"limits,omitempty"`
 
 	// Keepalive anchor for all the settings related to keepalive.
 	Keepalive *serverParametersAndEnforcementPolicy `mapstructure:"keepalive,omitempty"`
@@ -40,18 +78,24 @@ type Config struct {
 
 	// MaxConcurrentStreams sets the limit on the number of concurrent streams to each ServerTransport.
 	MaxConcurrentStreams uint32 `mapstructure:"max-concurrent-streams,omitempty"`
-}
 
-// tlsCredentials holds the fields for TLS credentials
-// that are used for starting a server.
-// TODO(ccaraman): Add validation to check that these files exist at configuration loading time.
-//  Currently, these values aren't validated until the receiver is started.
-type tlsCredentials struct {
-	// CertFile is the file path containing the TLS certificate.
-	CertFile string `mapstructure:"cert-file"`
+	// ResourceFromNode enables synthesizing the Resource from the Node (service name,
+	// host, pid, library versions) when the client does not send a Resource.
+	ResourceFromNode bool `mapstructure:"resource-from-node,omitempty"`
 
-	// KeyFile is the file path containing the TLS key.
-	KeyFile string `mapstructure:"key-file"`
+	// Identity overrides the Node and the Resource reported by the clients,
+	// e.g. when untrusted clients send data to a shared gateway.
+	Identity *resourcetranslator.IdentityOverrides `mapstructure:"identity,omitempty"`
+
+	// IDGenerator is the type of the generator used to fill the trace and span IDs
+	// of spans received without them, "random" or "hash". IDs are not generated
+	// when it is empty.
+	IDGenerator string `mapstructure:"id-generator,omitempty"`
+
+	// DrainTimeout is how long the receiver waits, when it is stopped, for the
+	// active streams and requests to finish before closing them. The default
+	// is 5s.
+	DrainTimeout time.Duration `mapstructure:"drain-timeout,omitempty"`
 }
 
 type serverParametersAndEnforcementPolicy struct {
@@ -79,7 +123,7 @@ type keepaliveEnforcementPolicy struct {
 }
 
 func (rOpts *Config) buildOptions() (opts []Option, err error) {
-	tlsCredsOption, hasTLSCreds, err := rOpts.TLSCredentials.ToOpenCensusReceiverServerOption()
+	tlsCredsOption, hasTLSCreds, err := toServerOption(rOpts.TLSCredentials)
 	if err != nil {
 		return opts, fmt.Errorf("error initializing OpenCensus receiver %q TLS Credentials: %v", rOpts.NameVal, err)
 	}
@@ -87,14 +131,96 @@ func (rOpts *Config) buildOptions() (opts []Option, err error) {
 		opts = append(opts, tlsCredsOption)
 	}
 
+	perm, err := confignet.ParsePermissions(rOpts.SocketPermissions)
+	if err != nil {
+		return opts, fmt.Errorf("error initializing OpenCensus receiver %q: %v", rOpts.NameVal, err)
+	}
+	if perm != 0 {
+		opts = append(opts, WithSocketPermissions(perm))
+	}
+
+	if rOpts.HTTPEndpoint != "" {
+		opts = append(opts, WithHTTPEndpoint(rOpts.HTTPEndpoint))
+	}
+
+	if len(rOpts.CorsOrigins) > 0 {
+		opts = append(opts, WithCorsOrigins(rOpts.CorsOrigins))
+	}
+	if len(rOpts.CorsHeaders) > 0 {
+		opts = append(opts, WithCorsHeaders(rOpts.CorsHeaders))
+	}
+	if len(rOpts.CorsMethods) > 0 {
+		opts = append(opts, WithCorsMethods(rOpts.CorsMethods))
+	}
+	if rOpts.CorsMaxAge > 0 {
+		opts = append(opts, WithCorsMaxAge(rOpts.CorsMaxAge))
+	}
+
+	if rOpts.Authentication != nil {
+		a, err := rOpts.Authentication.NewAuthenticator()
+		if err != nil {
+			return opts, fmt.Errorf("error initializing OpenCensus receiver %q authentication: %v", rOpts.NameVal, err)
+		}
+		opts = append(opts, WithAuthenticator(a))
+	}
+
+	if rOpts.Limits != nil {
+		l, err := rOpts.Limits.NewLimiter(rOpts.NameVal)
+		if err != nil {
+			return opts, fmt.Errorf("error initializing OpenCensus receiver %q limits: %v", rOpts.NameVal, err)
+		}
+		opts = append(opts, WithLimiter(l))
+	}
+
 	grpcServerOptions := rOpts.grpcServerOptions()
 	if len(grpcServerOptions) > 0 {
 		opts = append(opts, WithGRPCServerOptions(grpcServerOptions...))
 	}
 
+	if rOpts.ResourceFromNode {
+		opts = append(opts,
+			WithTraceReceiverOptions(octrace.WithResourceFromNode(true)),
+			WithMetricsReceiverOptions(ocmetrics.WithResourceFromNode(true)))
+	}
+
+	if rOpts.Identity != nil {
+		opts = append(opts,
+			WithTraceReceiverOptions(octrace.WithIdentityOverrides(rOpts.Identity)),
+			WithMetricsReceiverOptions(ocmetrics.WithIdentityOverrides(rOpts.Identity)))
+	}
+
+	if rOpts.DrainTimeout > 0 {
+		opts = append(opts, WithDrainTimeout(rOpts.DrainTimeout))
+	}
+
+	if rOpts.IDGenerator != "" {
+		gen, err := tracetranslator.NewIDGenerator(rOpts.IDGenerator)
+		if err != nil {
+			return opts, fmt.Errorf("error initializing OpenCensus receiver %q ID generator: %v", rOpts.NameVal, err)
+		}
+		opts = append(opts, WithTraceReceiverOptions(octrace.WithIDGenerator(gen)))
+	}
+
 	return opts, err
 }
 
+// grpcEndpoint returns the address of the gRPC server.
+// ListenEndpoints returns the gRPC and HTTP/JSON endpoints of the receiver.
+func (rOpts *Config) ListenEndpoints() []string {
+	endpoints := []string{rOpts.grpcEndpoint()}
+	if rOpts.HTTPEndpoint != "" {
+		endpoints = append(endpoints, rOpts.HTTPEndpoint)
+	}
+	return endpoints
+}
+
+func (rOpts *Config) grpcEndpoint() string {
+	if rOpts.GRPCEndpoint != "" {
+		return rOpts.GRPCEndpoint
+	}
+	return rOpts.Endpoint
+}
+
 func (rOpts *Config) grpcServerOptions() []grpc.ServerOption {
 	var grpcServerOptions []grpc.ServerOption
 	if rOpts.MaxRecvMsgSizeMiB > 0 {
@@ -134,20 +260,22 @@ func (rOpts *Config) grpcServerOptions() []grpc.ServerOption {
 	return grpcServerOptions
 }
 
-// ToOpenCensusReceiverServerOption checks if the TLS credentials
-// in the form of a certificate file and a key file. If they aren't,
+// toServerOption checks if the TLS credentials are set. If they aren't,
 // it will return opencensusreceiver.WithNoopOption() and a nil error.
-// Otherwise, it will try to retrieve gRPC transport credentials from the file combinations,
-// and create a option, along with any errors encountered while retrieving the credentials.
-func (tlsCreds *tlsCredentials) ToOpenCensusReceiverServerOption() (opt Option, ok bool, err error) {
+// Otherwise, it will try to load the TLS configuration from the files,
+// and create a WithTLSConfig option, along with any errors encountered while
+// loading it.
+func toServerOption(tlsCreds *configtls.TLSServerSetting) (opt Option, ok bool, err error) {
 	if tlsCreds == nil {
 		return WithNoopOption(), false, nil
 	}
 
-	transportCreds, err := credentials.NewServerTLSFromFile(tlsCreds.CertFile, tlsCreds.KeyFile)
+	tlsCfg, err := tlsCreds.LoadTLSConfig()
 	if err != nil {
 		return nil, false, err
 	}
-	gRPCCredsOpt := grpc.Creds(transportCreds)
-	return WithGRPCServerOptions(gRPCCredsOpt), true, nil
+	if tlsCfg == nil {
+		return WithNoopOption(), false, nil
+	}
+	return WithTLSConfig(tlsCfg), true, nil
 }
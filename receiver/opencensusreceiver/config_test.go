@@ -23,7 +23,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -41,7 +46,7 @@ func TestLoadConfig(t *testing.T) {
 
 	// Currently disabled receivers are removed from the total list of receivers so 'opencensus/disabled' doesn't
 	// contribute to the count.
-	assert.Equal(t, len(cfg.Receivers), 5)
+	assert.Equal(t, len(cfg.Receivers), 14)
 
 	r0 := cfg.Receivers["opencensus"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -104,9 +109,122 @@ func TestLoadConfig(t *testing.T) {
 				NameVal:  "opencensus/tlscredentials",
 				Endpoint: "127.0.0.1:55678",
 			},
-			TLSCredentials: &tlsCredentials{
-				CertFile: "test.crt",
-				KeyFile:  "test.key",
+			TLSCredentials: &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CertFile: "test.crt",
+					KeyFile:  "test.key",
+				},
+			},
+		})
+
+	r5 := cfg.Receivers["opencensus/resourcefromnode"].(*Config)
+	assert.Equal(t, r5,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/resourcefromnode",
+				Endpoint: "127.0.0.1:55678",
+			},
+			ResourceFromNode: true,
+		})
+
+	r6 := cfg.Receivers["opencensus/idgenerator"].(*Config)
+	assert.Equal(t, r6,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/idgenerator",
+				Endpoint: "127.0.0.1:55678",
+			},
+			IDGenerator: tracetranslator.IDGeneratorHash,
+		})
+
+	r7 := cfg.Receivers["opencensus/authentication"].(*Config)
+	assert.Equal(t, r7,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/authentication",
+				Endpoint: "127.0.0.1:55678",
+			},
+			Authentication: &configauth.Settings{
+				BearerTokens: []string{"token"},
+				APIKeyHeader: "X-API-Key",
+				APIKeys:      []string{"key"},
+			},
+		})
+
+	r8 := cfg.Receivers["opencensus/unixsocket"].(*Config)
+	assert.Equal(t, r8.ReceiverSettings,
+		configmodels.ReceiverSettings{
+			TypeVal:           typeStr,
+			NameVal:           "opencensus/unixsocket",
+			Endpoint:          "unix:///var/run/otelsvc.sock",
+			SocketPermissions: "0660",
+		})
+
+	r9 := cfg.Receivers["opencensus/draintimeout"].(*Config)
+	assert.Equal(t, r9,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/draintimeout",
+				Endpoint: "127.0.0.1:55678",
+			},
+			DrainTimeout: 10 * time.Second,
+		})
+
+	r10 := cfg.Receivers["opencensus/separateports"].(*Config)
+	assert.Equal(t, r10,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/separateports",
+				Endpoint: "127.0.0.1:55678",
+			},
+			GRPCEndpoint: "0.0.0.0:55678",
+			HTTPEndpoint: "0.0.0.0:55680",
+		})
+	assert.Equal(t, "0.0.0.0:55678", r10.grpcEndpoint())
+
+	r11 := cfg.Receivers["opencensus/cors"].(*Config)
+	assert.Equal(t, r11,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/cors",
+				Endpoint: "127.0.0.1:55678",
+			},
+			CorsOrigins: []string{"https://*.test.com", "https://test.com"},
+			CorsHeaders: []string{"Authorization"},
+			CorsMaxAge:  10 * time.Minute,
+		})
+
+	r12 := cfg.Receivers["opencensus/limits"].(*Config)
+	assert.Equal(t, r12,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/limits",
+				Endpoint: "127.0.0.1:55678",
+			},
+			Limits: &configlimit.Settings{
+				MaxConnections:       100,
+				MaxRequestsPerSecond: 1000,
+				MaxBatchSize:         10000,
+			},
+		})
+	r13 := cfg.Receivers["opencensus/identity"].(*Config)
+	assert.Equal(t, r13,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "opencensus/identity",
+				Endpoint: "127.0.0.1:55678",
+			},
+			Identity: &resourcetranslator.IdentityOverrides{
+				StripNodeIdentifier: true,
+				ResourceLabels:      map[string]string{"deployment.environment": "prod"},
 			},
 		})
 }
@@ -106,7 +106,7 @@ func (f *Factory) createReceiver(cfg configmodels.Receiver) (*Receiver, error) {
 		}
 
 		// We don't have a receiver, so create one.
-		receiver, err = New(rCfg.Endpoint, nil, nil, opts...)
+		receiver, err = New(rCfg.grpcEndpoint(), nil, nil, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -22,7 +22,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
 	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
@@ -59,9 +61,10 @@ func TestCreateTraceReceiver(t *testing.T) {
 		Endpoint: endpoint,
 	}
 	tests := []struct {
-		name    string
-		cfg     *Config
-		wantErr bool
+		name         string
+		cfg          *Config
+		wantErr      bool
+		wantStartErr bool
 	}{
 		{
 			name: "default",
@@ -78,7 +81,8 @@ func TestCreateTraceReceiver(t *testing.T) {
 					Endpoint: "127.0.0.1:112233",
 				},
 			},
-			wantErr: true,
+			// The address is bound when the receiver starts.
+			wantStartErr: true,
 		},
 		{
 			name: "max-msg-size-and-concurrent-connections",
@@ -88,6 +92,48 @@ func TestCreateTraceReceiver(t *testing.T) {
 				MaxConcurrentStreams: 16,
 			},
 		},
+		{
+			name: "invalid_tls_credentials",
+			cfg: &Config{
+				ReceiverSettings: defaultReceiverSettings,
+				TLSCredentials: &configtls.TLSServerSetting{
+					TLSSetting: configtls.TLSSetting{CertFile: "nosuchfile.crt", KeyFile: "nosuchfile.key"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_id_generator",
+			cfg: &Config{
+				ReceiverSettings: defaultReceiverSettings,
+				IDGenerator:      "unknown",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_socket_permissions",
+			cfg: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal:           typeStr,
+					NameVal:           typeStr,
+					Endpoint:          "unix:///tmp/otelsvc.sock",
+					SocketPermissions: "rw-rw----",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_limits",
+			cfg: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal:  typeStr,
+					NameVal:  typeStr,
+					Endpoint: "localhost:0",
+				},
+				Limits: &configlimit.Settings{MaxConnections: -1},
+			},
+			wantErr: true,
+		},
 	}
 	ctx := context.Background()
 	logger := zap.NewNop()
@@ -101,10 +147,10 @@ func TestCreateTraceReceiver(t *testing.T) {
 			}
 			if tr != nil {
 				mh := receivertest.NewMockHost()
-				if err := tr.StartTraceReception(mh); err == nil {
-					tr.StopTraceReception()
-				} else {
-					t.Fatalf("StartTraceReception() error = %v", err)
+				err := tr.StartTraceReception(mh)
+				tr.StopTraceReception()
+				if (err != nil) != tt.wantStartErr {
+					t.Fatalf("StartTraceReception() error = %v, wantStartErr %v", err, tt.wantStartErr)
 				}
 			}
 		})
@@ -120,9 +166,10 @@ func TestCreateMetricReceiver(t *testing.T) {
 		Endpoint: endpoint,
 	}
 	tests := []struct {
-		name    string
-		cfg     *Config
-		wantErr bool
+		name         string
+		cfg          *Config
+		wantErr      bool
+		wantStartErr bool
 	}{
 		{
 			name: "default",
@@ -139,7 +186,8 @@ func TestCreateMetricReceiver(t *testing.T) {
 					Endpoint: "327.0.0.1:1122",
 				},
 			},
-			wantErr: true,
+			// The address is bound when the receiver starts.
+			wantStartErr: true,
 		},
 		{
 			name: "keepalive",
@@ -168,10 +216,10 @@ func TestCreateMetricReceiver(t *testing.T) {
 			}
 			if tc != nil {
 				mh := receivertest.NewMockHost()
-				if err := tc.StartMetricsReception(mh); err == nil {
-					tc.StopMetricsReception()
-				} else {
-					t.Fatalf("StartTraceReception() error = %v", err)
+				err := tc.StartMetricsReception(mh)
+				tc.StopMetricsReception()
+				if (err != nil) != tt.wantStartErr {
+					t.Fatalf("StartMetricsReception() error = %v, wantStartErr %v", err, tt.wantStartErr)
 				}
 			}
 		})
@@ -28,9 +28,13 @@ import (
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/open-telemetry/opentelemetry-service/client"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
 )
 
 // Receiver is the type used to handle metrics from OpenCensus exporters.
@@ -38,6 +42,8 @@ type Receiver struct {
 	nextConsumer       consumer.MetricsConsumer
 	metricBufferPeriod time.Duration
 	metricBufferCount  int
+	resourceFromNode   bool
+	identity           *resourcetranslator.IdentityOverrides
 }
 
 // New creates a new ocmetrics.Receiver reference.
@@ -64,8 +70,17 @@ func (ocr *Receiver) Export(mes agentmetricspb.MetricsService_ExportServer) erro
 	// The bundler will receive batches of metrics i.e. []*metricspb.Metric
 	// We need to ensure that it propagates the receiver name as a tag
 	ctxWithReceiverName := observability.ContextWithReceiverName(mes.Context(), receiverTagValue)
+	// overloaded receives the overloaded errors of the next consumer, to end
+	// the stream asking the client to slow down.
+	overloaded := make(chan error, 1)
 	metricsBundler := bundler.NewBundler((*consumerdata.MetricsData)(nil), func(payload interface{}) {
-		ocr.batchMetricExporting(ctxWithReceiverName, payload)
+		err := ocr.batchMetricExporting(ctxWithReceiverName, payload)
+		if _, ok := consumererror.IsOverloaded(err); ok {
+			select {
+			case overloaded <- err:
+			default:
+			}
+		}
 	})
 
 	metricBufferPeriod := ocr.metricBufferPeriod
@@ -93,12 +108,21 @@ func (ocr *Receiver) Export(mes agentmetricspb.MetricsService_ExportServer) erro
 	}
 
 	var lastNonNilNode *commonpb.Node
-	var resource *resourcepb.Resource
+	var resource, nodeResource, res *resourcepb.Resource
 	// Now that we've got the first message with a Node, we can start to receive streamed up metrics.
 	for {
+		select {
+		case err := <-overloaded:
+			return consumererror.ToGRPC(err)
+		default:
+		}
+
 		// If a Node has been sent from downstream, save and use it.
 		if recv.Node != nil {
-			lastNonNilNode = recv.Node
+			lastNonNilNode = ocr.identity.Node(recv.Node)
+			if ocr.resourceFromNode {
+				nodeResource = resourcetranslator.NodeToResource(lastNonNilNode)
+			}
 		}
 
 		// TODO(songya): differentiate between unset and nil resource. See
@@ -107,7 +131,17 @@ func (ocr *Receiver) Export(mes agentmetricspb.MetricsService_ExportServer) erro
 			resource = recv.Resource
 		}
 
-		processReceivedMetrics(lastNonNilNode, resource, recv.Metrics, metricsBundler)
+		// The Resource only changes with the Node or the Resource, it isn't
+		// overridden again for every message.
+		if recv.Node != nil || recv.Resource != nil {
+			res = resource
+			if res == nil {
+				res = nodeResource
+			}
+			res = ocr.identity.Resource(res)
+		}
+
+		processReceivedMetrics(lastNonNilNode, res, recv.Metrics, metricsBundler)
 
 		recv, err = mes.Recv()
 		if err != nil {
@@ -129,10 +163,10 @@ func processReceivedMetrics(ni *commonpb.Node, resource *resourcepb.Resource, me
 	}
 }
 
-func (ocr *Receiver) batchMetricExporting(longLivedRPCCtx context.Context, payload interface{}) {
+func (ocr *Receiver) batchMetricExporting(longLivedRPCCtx context.Context, payload interface{}) error {
 	mds := payload.([]*consumerdata.MetricsData)
 	if len(mds) == 0 {
-		return
+		return nil
 	}
 
 	// Trace this method
@@ -146,13 +180,23 @@ func (ocr *Receiver) batchMetricExporting(longLivedRPCCtx context.Context, paylo
 	// If the starting RPC has a parent span, then add it as a parent link.
 	observability.SetParentLink(longLivedRPCCtx, span)
 
+	// Pass the client of the RPC to the processors that depend on it.
+	if c, ok := client.FromGRPC(longLivedRPCCtx); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	ctx = obsreport.WithTransport(ctx, "grpc")
+
 	nMetrics := int64(0)
+	var lastErr error
 	for _, md := range mds {
-		ocr.nextConsumer.ConsumeMetricsData(ctx, *md)
+		if err := ocr.nextConsumer.ConsumeMetricsData(ctx, *md); err != nil {
+			lastErr = err
+		}
 		nMetrics += int64(len(md.Metrics))
 	}
 
 	span.Annotate([]trace.Attribute{
 		trace.Int64Attribute("num_metrics", nMetrics),
 	}, "")
+	return lastErr
 }
@@ -34,10 +34,13 @@ import (
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
 )
 
 // TODO: add E2E tests once ocagent implements metric service client.
@@ -296,6 +299,50 @@ func TestExportProtocolConformation_metricsInFirstMessage(t *testing.T) {
 	}
 }
 
+func TestExportResourceFromNode(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+
+	_, port, doneFn := ocReceiverOnGRPCServer(t, sink, WithMetricBufferPeriod(10*time.Millisecond), WithResourceFromNode(true))
+	defer doneFn()
+
+	metricsClient, metricsClientDoneFn, err := makeMetricsServiceClient(port)
+	if err != nil {
+		t.Fatalf("Failed to create the gRPC MetricsService_ExportClient: %v", err)
+	}
+	defer metricsClientDoneFn()
+
+	ni := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}}
+	explicitResource := &resourcepb.Resource{Type: "k8s", Labels: map[string]string{"pod": "p1"}}
+	requests := []*agentmetricspb.ExportMetricsServiceRequest{
+		// A Node-only stream gets the Resource synthesized from the Node.
+		{Node: ni, Metrics: []*metricspb.Metric{makeMetric(1)}},
+		// An explicit Resource always wins over the synthesized one.
+		{Resource: explicitResource, Metrics: []*metricspb.Metric{makeMetric(2)}},
+	}
+	for _, req := range requests {
+		if err := metricsClient.Send(req); err != nil {
+			t.Fatalf("Failed to send the request: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.AllMetrics()) < len(requests) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := sink.AllMetrics()
+	if g, w := len(got), len(requests); g != w {
+		t.Fatalf("Got %d MetricsData Want %d", g, w)
+	}
+	wantResource := &resourcepb.Resource{Labels: map[string]string{resourcetranslator.LabelServiceName: "svc"}}
+	if !proto.Equal(got[0].Resource, wantResource) {
+		t.Errorf("Resource synthesized from Node\nGot: %v\nWant: %v", got[0].Resource, wantResource)
+	}
+	if !proto.Equal(got[1].Resource, explicitResource) {
+		t.Errorf("Explicit Resource\nGot: %v\nWant: %v", got[1].Resource, explicitResource)
+	}
+}
+
 // Helper functions from here on below
 func makeMetricsServiceClient(port int) (agentmetricspb.MetricsService_ExportClient, func(), error) {
 	addr := fmt.Sprintf(":%d", port)
@@ -14,7 +14,11 @@
 
 package ocmetrics
 
-import "time"
+import (
+	"time"
+
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+)
 
 // Option interface defines for configuration settings to be applied to receivers.
 //
@@ -54,3 +58,36 @@ func (mpc metricBufferCount) WithReceiver(oci *Receiver) {
 func WithMetricBufferCount(count int) Option {
 	return metricBufferCount(count)
 }
+
+type resourceFromNode bool
+
+var _ Option = (*resourceFromNode)(nil)
+
+func (rfn resourceFromNode) WithReceiver(ocr *Receiver) {
+	ocr.resourceFromNode = bool(rfn)
+}
+
+// WithResourceFromNode is an option that allows one to configure whether
+// the Receiver synthesizes a Resource from the Node (service name, host,
+// pid, library versions) when the client does not send one.
+func WithResourceFromNode(enabled bool) Option {
+	return resourceFromNode(enabled)
+}
+
+type identityOverrides struct {
+	overrides *resourcetranslator.IdentityOverrides
+}
+
+var _ Option = (*identityOverrides)(nil)
+
+func (io *identityOverrides) WithReceiver(ocr *Receiver) {
+	ocr.identity = io.overrides
+}
+
+// WithIdentityOverrides is an option that allows one to configure the
+// overrides of the Node and the Resource reported by the clients. The Resource
+// is synthesized from the overridden Node. A nil overrides leaves them
+// unchanged.
+func WithIdentityOverrides(overrides *resourcetranslator.IdentityOverrides) Option {
+	return &identityOverrides{overrides: overrides}
+}
@@ -18,15 +18,21 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 
 	"go.opencensus.io/trace"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/open-telemetry/opentelemetry-service/client"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 const (
@@ -37,15 +43,22 @@ const (
 
 // Receiver is the type used to handle spans from OpenCensus exporters.
 type Receiver struct {
-	nextConsumer consumer.TraceConsumer
-	numWorkers   int
-	workers      []*receiverWorker
-	messageChan  chan *traceDataWithCtx
+	nextConsumer     consumer.TraceConsumer
+	numWorkers       int
+	workers          []*receiverWorker
+	workersDone      sync.WaitGroup
+	messageChan      chan *traceDataWithCtx
+	resourceFromNode bool
+	identity         *resourcetranslator.IdentityOverrides
+	idGenerator      tracetranslator.IDGenerator
 }
 
 type traceDataWithCtx struct {
 	data *consumerdata.TraceData
 	ctx  context.Context
+	// overloaded receives the overloaded errors of the next consumer, to end
+	// the stream asking the client to slow down.
+	overloaded chan<- error
 }
 
 // New creates a new opencensus.Receiver reference.
@@ -68,7 +81,11 @@ func New(nextConsumer consumer.TraceConsumer, opts ...Option) (*Receiver, error)
 	workers := make([]*receiverWorker, 0, ocr.numWorkers)
 	for index := 0; index < ocr.numWorkers; index++ {
 		worker := newReceiverWorker(ocr)
-		go worker.listenOn(messageChan)
+		ocr.workersDone.Add(1)
+		go func() {
+			defer ocr.workersDone.Done()
+			worker.listenOn(messageChan)
+		}()
 		workers = append(workers, worker)
 	}
 	ocr.workers = workers
@@ -108,12 +125,22 @@ func (ocr *Receiver) Export(tes agenttracepb.TraceService_ExportServer) error {
 	}
 
 	var lastNonNilNode *commonpb.Node
-	var resource *resourcepb.Resource
+	var resource, nodeResource, res *resourcepb.Resource
+	overloaded := make(chan error, 1)
 	// Now that we've got the first message with a Node, we can start to receive streamed up spans.
 	for {
+		select {
+		case err := <-overloaded:
+			return consumererror.ToGRPC(err)
+		default:
+		}
+
 		// If a Node has been sent from downstream, save and use it.
 		if recv.Node != nil {
-			lastNonNilNode = recv.Node
+			lastNonNilNode = ocr.identity.Node(recv.Node)
+			if ocr.resourceFromNode {
+				nodeResource = resourcetranslator.NodeToResource(lastNonNilNode)
+			}
 		}
 
 		// TODO(songya): differentiate between unset and nil resource. See
@@ -122,14 +149,28 @@ func (ocr *Receiver) Export(tes agenttracepb.TraceService_ExportServer) error {
 			resource = recv.Resource
 		}
 
+		// The Resource only changes with the Node or the Resource, it isn't
+		// overridden again for every message.
+		if recv.Node != nil || recv.Resource != nil {
+			res = resource
+			if res == nil {
+				res = nodeResource
+			}
+			res = ocr.identity.Resource(res)
+		}
+
+		if ocr.idGenerator != nil {
+			tracetranslator.FillMissingIDs(ocr.idGenerator, recv.Spans)
+		}
+
 		td := &consumerdata.TraceData{
 			Node:         lastNonNilNode,
-			Resource:     resource,
+			Resource:     res,
 			Spans:        recv.Spans,
 			SourceFormat: "oc_trace",
 		}
 
-		ocr.messageChan <- &traceDataWithCtx{data: td, ctx: ctxWithReceiverName}
+		ocr.messageChan <- &traceDataWithCtx{data: td, ctx: ctxWithReceiverName, overloaded: overloaded}
 
 		observability.RecordTraceReceiverMetrics(ctxWithReceiverName, len(td.Spans), 0)
 
@@ -145,11 +186,13 @@ func (ocr *Receiver) Export(tes agenttracepb.TraceService_ExportServer) error {
 	}
 }
 
-// Stop the receiver and its workers
+// Stop the receiver and its workers, once they have exported the data
+// already received.
 func (ocr *Receiver) Stop() {
 	for _, worker := range ocr.workers {
 		worker.stopListening()
 	}
+	ocr.workersDone.Wait()
 }
 
 type receiverWorker struct {
@@ -168,9 +211,17 @@ func (rw *receiverWorker) listenOn(cn <-chan *traceDataWithCtx) {
 	for {
 		select {
 		case tdWithCtx := <-cn:
-			rw.export(tdWithCtx.ctx, tdWithCtx.data)
+			rw.exportAndSignal(tdWithCtx)
 		case <-rw.cancel:
-			return
+			// Export the data already received before stopping.
+			for {
+				select {
+				case tdWithCtx := <-cn:
+					rw.exportAndSignal(tdWithCtx)
+				default:
+					return
+				}
+			}
 		}
 	}
 }
@@ -179,13 +230,25 @@ func (rw *receiverWorker) stopListening() {
 	close(rw.cancel)
 }
 
-func (rw *receiverWorker) export(longLivedCtx context.Context, tracedata *consumerdata.TraceData) {
+// exportAndSignal exports the data and signals an overloaded next consumer to
+// the stream that received it.
+func (rw *receiverWorker) exportAndSignal(tdWithCtx *traceDataWithCtx) {
+	err := rw.export(tdWithCtx.ctx, tdWithCtx.data)
+	if _, ok := consumererror.IsOverloaded(err); ok && tdWithCtx.overloaded != nil {
+		select {
+		case tdWithCtx.overloaded <- err:
+		default:
+		}
+	}
+}
+
+func (rw *receiverWorker) export(longLivedCtx context.Context, tracedata *consumerdata.TraceData) error {
 	if tracedata == nil {
-		return
+		return nil
 	}
 
 	if len(tracedata.Spans) == 0 {
-		return
+		return nil
 	}
 
 	// Trace this method
@@ -199,9 +262,16 @@ func (rw *receiverWorker) export(longLivedCtx context.Context, tracedata *consum
 	// If the starting RPC has a parent span, then add it as a parent link.
 	observability.SetParentLink(longLivedCtx, span)
 
-	rw.receiver.nextConsumer.ConsumeTraceData(ctx, *tracedata)
+	// Pass the client of the RPC to the processors that depend on it.
+	if c, ok := client.FromGRPC(longLivedCtx); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	ctx = obsreport.WithTransport(ctx, "grpc")
+
+	err := rw.receiver.nextConsumer.ConsumeTraceData(ctx, *tracedata)
 
 	span.Annotate([]trace.Attribute{
 		trace.Int64Attribute("num_spans", int64(len(tracedata.Spans))),
 	}, "")
+	return err
 }
@@ -31,15 +31,22 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"contrib.go.opencensus.io/exporter/ocagent"
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 	"go.opencensus.io/trace"
 	"go.opencensus.io/trace/tracestate"
 )
@@ -430,6 +437,200 @@ func TestExportProtocolConformation_spansInFirstMessage(t *testing.T) {
 	}
 }
 
+func TestExportResourceFromNode(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+
+	_, port, doneFn := ocReceiverOnGRPCServer(t, sink, WithWorkerCount(1), WithResourceFromNode(true))
+	defer doneFn()
+
+	traceClient, traceClientDoneFn, err := makeTraceServiceClient(port)
+	if err != nil {
+		t.Fatalf("Failed to create the gRPC TraceService_ExportClient: %v", err)
+	}
+	defer traceClientDoneFn()
+
+	ni := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}}
+	explicitResource := &resourcepb.Resource{Type: "k8s", Labels: map[string]string{"pod": "p1"}}
+	requests := []*agenttracepb.ExportTraceServiceRequest{
+		// A Node-only stream gets the Resource synthesized from the Node.
+		{Node: ni, Spans: []*tracepb.Span{{TraceId: []byte("1234567890abcde")}}},
+		// An explicit Resource always wins over the synthesized one.
+		{Resource: explicitResource, Spans: []*tracepb.Span{{TraceId: []byte("XXXXXXXXXXabcde")}}},
+	}
+	for _, req := range requests {
+		if err := traceClient.Send(req); err != nil {
+			t.Fatalf("Failed to send the request: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.AllTraces()) < len(requests) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := sink.AllTraces()
+	if g, w := len(got), len(requests); g != w {
+		t.Fatalf("Got %d TraceData Want %d", g, w)
+	}
+	wantResource := &resourcepb.Resource{Labels: map[string]string{resourcetranslator.LabelServiceName: "svc"}}
+	if !proto.Equal(got[0].Resource, wantResource) {
+		t.Errorf("Resource synthesized from Node\nGot: %v\nWant: %v", got[0].Resource, wantResource)
+	}
+	if !proto.Equal(got[1].Resource, explicitResource) {
+		t.Errorf("Explicit Resource\nGot: %v\nWant: %v", got[1].Resource, explicitResource)
+	}
+}
+
+func TestExportIdentityOverrides(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+
+	overrides := &resourcetranslator.IdentityOverrides{
+		StripNodeIdentifier: true,
+		ResourceLabels:      map[string]string{"deployment.environment": "prod"},
+	}
+	_, port, doneFn := ocReceiverOnGRPCServer(t, sink, WithWorkerCount(1), WithResourceFromNode(true), WithIdentityOverrides(overrides))
+	defer doneFn()
+
+	traceClient, traceClientDoneFn, err := makeTraceServiceClient(port)
+	if err != nil {
+		t.Fatalf("Failed to create the gRPC TraceService_ExportClient: %v", err)
+	}
+	defer traceClientDoneFn()
+
+	ni := &commonpb.Node{
+		Identifier:  &commonpb.ProcessIdentifier{HostName: "spoofed"},
+		ServiceInfo: &commonpb.ServiceInfo{Name: "svc"},
+	}
+	explicitResource := &resourcepb.Resource{Labels: map[string]string{"deployment.environment": "dev"}}
+	requests := []*agenttracepb.ExportTraceServiceRequest{
+		// The Resource is synthesized from the Node without its identifier.
+		{Node: ni, Spans: []*tracepb.Span{{TraceId: []byte("1234567890abcde")}}},
+		// The labels reported by the clients are overridden.
+		{Resource: explicitResource, Spans: []*tracepb.Span{{TraceId: []byte("XXXXXXXXXXabcde")}}},
+	}
+	for _, req := range requests {
+		if err := traceClient.Send(req); err != nil {
+			t.Fatalf("Failed to send the request: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.AllTraces()) < len(requests) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := sink.AllTraces()
+	if g, w := len(got), len(requests); g != w {
+		t.Fatalf("Got %d TraceData Want %d", g, w)
+	}
+	wantNode := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}}
+	if !proto.Equal(got[0].Node, wantNode) {
+		t.Errorf("Node\nGot: %v\nWant: %v", got[0].Node, wantNode)
+	}
+	wantResource := &resourcepb.Resource{Labels: map[string]string{
+		resourcetranslator.LabelServiceName: "svc",
+		"deployment.environment":            "prod",
+	}}
+	if !proto.Equal(got[0].Resource, wantResource) {
+		t.Errorf("Resource synthesized from Node\nGot: %v\nWant: %v", got[0].Resource, wantResource)
+	}
+	wantResource = &resourcepb.Resource{Labels: map[string]string{"deployment.environment": "prod"}}
+	if !proto.Equal(got[1].Resource, wantResource) {
+		t.Errorf("Explicit Resource\nGot: %v\nWant: %v", got[1].Resource, wantResource)
+	}
+}
+
+func TestExportIDGenerator(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	gen, err := tracetranslator.NewIDGenerator(tracetranslator.IDGeneratorHash)
+	if err != nil {
+		t.Fatalf("Failed to create the ID generator: %v", err)
+	}
+
+	_, port, doneFn := ocReceiverOnGRPCServer(t, sink, WithWorkerCount(1), WithIDGenerator(gen))
+	defer doneFn()
+
+	traceClient, traceClientDoneFn, err := makeTraceServiceClient(port)
+	if err != nil {
+		t.Fatalf("Failed to create the gRPC TraceService_ExportClient: %v", err)
+	}
+	defer traceClientDoneFn()
+
+	traceID := []byte("1234567890abcdef")
+	spanID := []byte("12345678")
+	req := &agenttracepb.ExportTraceServiceRequest{
+		Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+		Spans: []*tracepb.Span{
+			{Name: &tracepb.TruncatableString{Value: "parent"}, SpanId: []byte("abcdefgh")},
+			{Name: &tracepb.TruncatableString{Value: "child"}, ParentSpanId: []byte("abcdefgh")},
+			{Name: &tracepb.TruncatableString{Value: "with-ids"}, TraceId: traceID, SpanId: spanID},
+		},
+	}
+	if err := traceClient.Send(req); err != nil {
+		t.Fatalf("Failed to send the request: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.AllTraces()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := sink.AllTraces()
+	if len(got) != 1 {
+		t.Fatalf("Got %d TraceData Want 1", len(got))
+	}
+	spans := got[0].Spans
+	if len(spans[0].TraceId) != 16 || !bytes.Equal(spans[0].TraceId, spans[1].TraceId) {
+		t.Errorf("Parent and child trace IDs\nGot: %x and %x\nWant the same generated trace ID", spans[0].TraceId, spans[1].TraceId)
+	}
+	if len(spans[1].SpanId) != 8 {
+		t.Errorf("Child span ID\nGot: %x\nWant a generated span ID", spans[1].SpanId)
+	}
+	if !bytes.Equal(spans[2].TraceId, traceID) || !bytes.Equal(spans[2].SpanId, spanID) {
+		t.Errorf("Existing IDs\nGot: %x %x\nWant: %x %x", spans[2].TraceId, spans[2].SpanId, traceID, spanID)
+	}
+}
+
+func TestExportOverloaded(t *testing.T) {
+	overloaded := &overloadedConsumer{}
+	_, port, doneFn := ocReceiverOnGRPCServer(t, overloaded, WithWorkerCount(1))
+	defer doneFn()
+
+	cc, err := grpc.Dial(fmt.Sprintf(":%d", port), grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatalf("Failed to dial the receiver: %v", err)
+	}
+	defer cc.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	traceClient, err := agenttracepb.NewTraceServiceClient(cc).Export(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create the gRPC TraceService_ExportClient: %v", err)
+	}
+
+	req := &agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "svc"}},
+		Spans: []*tracepb.Span{{Name: &tracepb.TruncatableString{Value: "span"}}},
+	}
+	// The stream ends once the receiver sees the overloaded error of the
+	// consumer of a previous message.
+	go func() {
+		for traceClient.Send(req) == nil {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	_, err = traceClient.Recv()
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Fatalf("Got code %v (%v) Want %v", got, err, codes.ResourceExhausted)
+	}
+}
+
+type overloadedConsumer struct{}
+
+func (overloadedConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return consumererror.Overloaded(errors.New("queue is full"), time.Second)
+}
+
 // Helper functions from here on below
 func makeTraceServiceClient(port int) (agenttracepb.TraceService_ExportClient, func(), error) {
 	addr := fmt.Sprintf(":%d", port)
@@ -14,6 +14,11 @@
 
 package octrace
 
+import (
+	resourcetranslator "github.com/open-telemetry/opentelemetry-service/translator/resource"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
+)
+
 // Option interface defines for configuration settings to be applied to receivers.
 //
 // WithReceiver applies the configuration to the given receiver.
@@ -26,3 +31,30 @@ func WithWorkerCount(workerCount int) Option {
 		r.numWorkers = workerCount
 	}
 }
+
+// WithResourceFromNode sets whether the receiver synthesizes a Resource from
+// the Node (service name, host, pid, library versions) when the client does
+// not send one.
+func WithResourceFromNode(enabled bool) Option {
+	return func(r *Receiver) {
+		r.resourceFromNode = enabled
+	}
+}
+
+// WithIdentityOverrides sets the overrides of the Node and the Resource
+// reported by the clients. The Resource is synthesized from the overridden
+// Node. A nil overrides leaves them unchanged.
+func WithIdentityOverrides(overrides *resourcetranslator.IdentityOverrides) Option {
+	return func(r *Receiver) {
+		r.identity = overrides
+	}
+}
+
+// WithIDGenerator sets the generator used to fill the trace and span IDs of
+// the received spans that do not have them. A nil generator leaves the spans
+// unchanged.
+func WithIDGenerator(gen tracetranslator.IDGenerator) Option {
+	return func(r *Receiver) {
+		r.idGenerator = gen
+	}
+}
@@ -16,10 +16,13 @@ package opencensusreceiver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +33,10 @@ import (
 	"github.com/soheilhy/cmux"
 	"google.golang.org/grpc"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configgrpc"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/observability"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
@@ -40,12 +47,25 @@ import (
 // Receiver is the type that exposes Trace and Metrics reception.
 type Receiver struct {
 	mu                sync.Mutex
+	addr              string
 	ln                net.Listener
+	httpEndpoint      string
+	httpLn            net.Listener
 	serverGRPC        *grpc.Server
 	serverHTTP        *http.Server
+	serverGRPCTLS     *http.Server
 	gatewayMux        *gatewayruntime.ServeMux
 	corsOrigins       []string
+	corsHeaders       []string
+	corsMethods       []string
+	corsMaxAge        time.Duration
 	grpcServerOptions []grpc.ServerOption
+	tlsConfig         *tls.Config
+	gatewayLn         *pipeListener
+	authenticator     configauth.Authenticator
+	limiter           *configlimit.Limiter
+	socketPermissions os.FileMode
+	drainTimeout      time.Duration
 
 	traceReceiverOpts   []octrace.Option
 	metricsReceiverOpts []ocmetrics.Option
@@ -69,26 +89,35 @@ var (
 
 const source string = "OpenCensus"
 
+// defaultDrainTimeout is the time given to the active streams and requests to
+// finish when the receiver is stopped.
+const defaultDrainTimeout = 5 * time.Second
+
 // New just creates the OpenCensus receiver services. It is the caller's
 // responsibility to invoke the respective Start*Reception methods as well
-// as the various Stop*Reception methods to end it.
+// as the various Stop*Reception methods to end it. The addr is either a TCP
+// address or a Unix domain socket like "unix:///path/to/socket", the HTTP/JSON
+// requests are served on it too unless WithHTTPEndpoint is given. The
+// addresses are bound when the receiver is started.
 func New(addr string, tc consumer.TraceConsumer, mc consumer.MetricsConsumer, opts ...Option) (*Receiver, error) {
-	// TODO: (@odeke-em) use options to enable address binding changes.
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to bind to address %q: %v", addr, err)
-	}
-
 	ocr := &Receiver{
-		ln:          ln,
-		corsOrigins: []string{}, // Disable CORS by default.
-		gatewayMux:  gatewayruntime.NewServeMux(),
+		addr:         addr,
+		corsOrigins:  []string{}, // Disable CORS by default.
+		drainTimeout: defaultDrainTimeout,
 	}
 
 	for _, opt := range opts {
 		opt.withReceiver(ocr)
 	}
 
+	var muxOpts []gatewayruntime.ServeMuxOption
+	if ocr.authenticator != nil {
+		// The HTTP/JSON requests are authenticated by the gRPC server, it
+		// needs all their headers, e.g. to find an API key.
+		muxOpts = append(muxOpts, gatewayruntime.WithIncomingHeaderMatcher(forwardHeader))
+	}
+	ocr.gatewayMux = gatewayruntime.NewServeMux(muxOpts...)
+
 	ocr.traceConsumer = tc
 	ocr.metricsConsumer = mc
 
@@ -149,7 +178,27 @@ func (ocr *Receiver) grpcServer() *grpc.Server {
 	defer ocr.mu.Unlock()
 
 	if ocr.serverGRPC == nil {
-		ocr.serverGRPC = observability.GRPCServerWithObservabilityEnabled(ocr.grpcServerOptions...)
+		opts := append([]grpc.ServerOption{}, ocr.grpcServerOptions...)
+		// The limits are checked first, the authentication can be expensive.
+		var unary []grpc.UnaryServerInterceptor
+		var stream []grpc.StreamServerInterceptor
+		if ocr.limiter != nil {
+			// Set after the receiver options, the maximum request size
+			// takes precedence over max-recv-msg-size-mib.
+			opts = append(opts, ocr.limiter.GRPCServerOptions()...)
+			unary = append(unary, configlimit.UnaryServerInterceptor(ocr.limiter))
+			stream = append(stream, configlimit.StreamServerInterceptor(ocr.limiter))
+		}
+		if ocr.authenticator != nil {
+			unary = append(unary, configauth.UnaryServerInterceptor(ocr.authenticator))
+			stream = append(stream, configauth.StreamServerInterceptor(ocr.authenticator))
+		}
+		if len(unary) > 0 {
+			opts = append(opts,
+				grpc.UnaryInterceptor(configgrpc.ChainUnaryServerInterceptors(unary...)),
+				grpc.StreamInterceptor(configgrpc.ChainStreamServerInterceptors(stream...)))
+		}
+		ocr.serverGRPC = observability.GRPCServerWithObservabilityEnabled(opts...)
 	}
 
 	return ocr.serverGRPC
@@ -212,28 +261,72 @@ func (ocr *Receiver) stop() error {
 	ocr.stopOnce.Do(func() {
 		err = nil
 
+		// Stop accepting new connections, then let the active streams and
+		// requests finish, up to the drain timeout.
+		if ocr.ln != nil {
+			_ = ocr.ln.Close()
+		}
+		if ocr.httpLn != nil {
+			_ = ocr.httpLn.Close()
+		}
+
+		ocr.drainServers()
+
+		if ocr.gatewayLn != nil {
+			_ = ocr.gatewayLn.Close()
+		}
+
+		// The trace receiver exports the data already received before its
+		// workers stop. Currently there is no symmetric stop for metrics receiver.
 		if ocr.traceReceiver != nil {
 			ocr.traceReceiver.Stop()
 		}
+	})
+	return err
+}
 
-		// Currently there is no symmetric stop for metrics receiver.
+// drainServers gracefully stops the gRPC and HTTP servers, they are forced to
+// stop once the drain timeout expires.
+func (ocr *Receiver) drainServers() {
+	ctx, cancel := context.WithTimeout(context.Background(), ocr.drainTimeout)
+	defer cancel()
 
-		if ocr.serverHTTP != nil {
-			_ = ocr.serverHTTP.Close()
+	var wg sync.WaitGroup
+	for _, srv := range []*http.Server{ocr.serverHTTP, ocr.serverGRPCTLS} {
+		if srv == nil {
+			continue
 		}
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if srv.Shutdown(ctx) != nil {
+				_ = srv.Close()
+			}
+		}(srv)
+	}
 
-		if ocr.ln != nil {
-			_ = ocr.ln.Close()
+	if ocr.serverGRPC != nil {
+		if ocr.tlsConfig != nil {
+			// With TLS the gRPC streams are served by the HTTP server, the
+			// gRPC server doesn't support gracefully stopping them.
+			wg.Wait()
+			ocr.serverGRPC.Stop()
+			return
 		}
 
-		// TODO: @(odeke-em) investigate what utility invoking (*grpc.Server).Stop()
-		// gives us yet we invoke (net.Listener).Close().
-		// Sure (*grpc.Server).Stop() enables proper shutdown but imposes
-		// a painful and artificial wait time that goes into 20+seconds yet most of our
-		// tests and code should be reactive in less than even 1second.
-		// ocr.serverGRPC.Stop()
-	})
-	return err
+		stopped := make(chan struct{})
+		go func() {
+			ocr.serverGRPC.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			ocr.serverGRPC.Stop()
+			<-stopped
+		}
+	}
+	wg.Wait()
 }
 
 func (ocr *Receiver) httpServer() *http.Server {
@@ -243,24 +336,94 @@ func (ocr *Receiver) httpServer() *http.Server {
 	if ocr.serverHTTP == nil {
 		var mux http.Handler = ocr.gatewayMux
 		if len(ocr.corsOrigins) > 0 {
-			co := cors.Options{AllowedOrigins: ocr.corsOrigins}
+			co := cors.Options{
+				AllowedOrigins: ocr.corsOrigins,
+				AllowedHeaders: ocr.corsHeaders,
+				AllowedMethods: ocr.corsMethods,
+				MaxAge:         int(ocr.corsMaxAge / time.Second),
+			}
 			mux = cors.New(co).Handler(mux)
 		}
 		ocr.serverHTTP = &http.Server{Handler: mux}
+		if ocr.tlsConfig != nil {
+			if ocr.httpLn == nil {
+				ocr.serverHTTP.Handler = grpcHandlerFunc(ocr.serverGRPC, mux)
+			}
+			ocr.serverHTTP.TLSConfig = ocr.tlsConfig
+		}
 	}
 
 	return ocr.serverHTTP
 }
 
+// serveHTTP serves the HTTP/JSON requests on their own listener, and the gRPC
+// requests on the main one.
+func (ocr *Receiver) serveHTTP(errChan chan<- error) {
+	if ocr.tlsConfig == nil {
+		go func() {
+			errChan <- ocr.httpServer().Serve(ocr.httpLn)
+		}()
+		errChan <- ocr.serverGRPC.Serve(ocr.ln)
+		return
+	}
+
+	go func() {
+		errChan <- ocr.serverGRPC.Serve(ocr.gatewayLn)
+	}()
+	go func() {
+		errChan <- ocr.httpServer().ServeTLS(ocr.httpLn, "", "")
+	}()
+	ocr.mu.Lock()
+	ocr.serverGRPCTLS = &http.Server{Handler: ocr.serverGRPC, TLSConfig: ocr.tlsConfig}
+	ocr.mu.Unlock()
+	errChan <- ocr.serverGRPCTLS.ServeTLS(ocr.ln, "", "")
+}
+
+// listen binds the addresses of the receiver.
+func (ocr *Receiver) listen() error {
+	ocr.mu.Lock()
+	defer ocr.mu.Unlock()
+
+	ln, err := confignet.Listen(ocr.addr, ocr.socketPermissions)
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %q: %v", ocr.addr, err)
+	}
+	if ocr.limiter != nil {
+		ln = ocr.limiter.Listener(ln)
+	}
+
+	if ocr.httpEndpoint != "" {
+		httpLn, err := confignet.Listen(ocr.httpEndpoint, ocr.socketPermissions)
+		if err != nil {
+			ln.Close()
+			return fmt.Errorf("failed to bind to HTTP address %q: %v", ocr.httpEndpoint, err)
+		}
+		if ocr.limiter != nil {
+			httpLn = ocr.limiter.Listener(httpLn)
+		}
+		ocr.httpLn = httpLn
+	}
+	ocr.ln = ln
+	return nil
+}
+
 func (ocr *Receiver) startServer() error {
 	err := errAlreadyStarted
 	ocr.startServerOnce.Do(func() {
+		if err = ocr.listen(); err != nil {
+			return
+		}
+
 		errChan := make(chan error, 1)
 		go func() {
 			// Register the grpc-gateway on the HTTP server mux
 			c := context.Background()
-			opts := []grpc.DialOption{grpc.WithInsecure()}
-			endpoint := ocr.ln.Addr().String()
+			endpoint, opts := confignet.GRPCDialOptions(confignet.EndpointOf(ocr.ln.Addr()))
+			opts = append(opts, grpc.WithInsecure())
+			if ocr.tlsConfig != nil {
+				ocr.gatewayLn = newPipeListener()
+				opts = append(opts, grpc.WithContextDialer(ocr.gatewayLn.dial))
+			}
 
 			err := agenttracepb.RegisterTraceServiceHandlerFromEndpoint(c, ocr.gatewayMux, endpoint, opts)
 			if err != nil {
@@ -274,6 +437,21 @@ func (ocr *Receiver) startServer() error {
 				return
 			}
 
+			if ocr.httpLn != nil {
+				ocr.serveHTTP(errChan)
+				return
+			}
+
+			if ocr.tlsConfig != nil {
+				// The protocol can't be sniffed before the TLS handshake, a
+				// single HTTP/2 capable server dispatches the gRPC requests.
+				go func() {
+					errChan <- ocr.serverGRPC.Serve(ocr.gatewayLn)
+				}()
+				errChan <- ocr.httpServer().ServeTLS(ocr.ln, "", "")
+				return
+			}
+
 			// Start the gRPC and HTTP/JSON (grpc-gateway) servers on the same port.
 			m := cmux.New(ocr.ln)
 			grpcL := m.MatchWithWriters(
@@ -304,3 +482,24 @@ func (ocr *Receiver) startServer() error {
 	})
 	return err
 }
+
+// forwardHeader forwards to the gRPC server the headers that the default
+// grpc-gateway matcher drops, without any prefix.
+func forwardHeader(key string) (string, bool) {
+	if h, ok := gatewayruntime.DefaultHeaderMatcher(key); ok {
+		return h, true
+	}
+	return key, true
+}
+
+// grpcHandlerFunc returns a http.Handler that sends the gRPC requests to the
+// gRPC server and the other requests to the given handler.
+func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		otherHandler.ServeHTTP(w, r)
+	})
+}
@@ -17,11 +17,16 @@ package opencensusreceiver
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,12 +35,20 @@ import (
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+	"github.com/open-telemetry/opentelemetry-service/receiver/conformance"
 	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
 )
 
@@ -209,6 +222,57 @@ func TestMetricsGrpcGatewayCors_endToEnd(t *testing.T) {
 	verifyCorsResp(t, url, "disallowed-origin.com", 200, false)
 }
 
+func TestGrpcGatewayCorsPolicy(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil,
+		WithCorsOrigins([]string{"https://*.example.com"}),
+		WithCorsHeaders([]string{"Authorization"}),
+		WithCorsMethods([]string{"POST"}),
+		WithCorsMaxAge(10*time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+	defer ocr.StopTraceReception()
+
+	url := fmt.Sprintf("http://%s/v1/trace", addr)
+	preflight := func(origin, method, headers string) http.Header {
+		req, err := http.NewRequest("OPTIONS", url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.Header
+	}
+
+	// Browsers send the names of the headers in lower case.
+	got := preflight("https://web.example.com", "POST", "authorization")
+	require.Equal(t, "https://web.example.com", got.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "POST", got.Get("Access-Control-Allow-Methods"))
+	require.Equal(t, "authorization", strings.ToLower(got.Get("Access-Control-Allow-Headers")))
+	require.Equal(t, "600", got.Get("Access-Control-Max-Age"))
+
+	// Disallowed origin, method and header.
+	require.Empty(t, preflight("https://example.org", "POST", "").Get("Access-Control-Allow-Origin"))
+	require.Empty(t, preflight("https://web.example.com", "PUT", "").Get("Access-Control-Allow-Origin"))
+	require.Empty(t, preflight("https://web.example.com", "POST", "x-custom").Get("Access-Control-Allow-Origin"))
+
+	// The actual request.
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(traceJSON))
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://web.example.com")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "https://web.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
 // As per Issue https://github.com/census-instrumentation/opencensus-service/issues/366
 // the agent's mux should be able to accept all Proto affiliated content-types and not
 // redirect them to the web-grpc-gateway endpoint.
@@ -346,6 +410,388 @@ func verifyCorsResp(t *testing.T, url string, origin string, wantStatus int, wan
 	}
 }
 
+func TestTLS_endToEnd(t *testing.T) {
+	serverTLS, err := configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   "../../config/configtls/testdata/ca.crt",
+			CertFile: "../../config/configtls/testdata/server.crt",
+			KeyFile:  "../../config/configtls/testdata/server.key",
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithTLSConfig(serverTLS))
+	require.NoError(t, err)
+
+	mh := receivertest.NewMockHost()
+	require.NoError(t, ocr.StartTraceReception(mh))
+	defer ocr.StopTraceReception()
+
+	// Use a host name matching the server certificate.
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	addr = net.JoinHostPort("localhost", port)
+
+	withCert, err := configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   "../../config/configtls/testdata/ca.crt",
+			CertFile: "../../config/configtls/testdata/client.crt",
+			KeyFile:  "../../config/configtls/testdata/client.key",
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	withoutCert, err := configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: "../../config/configtls/testdata/ca.crt"},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// gRPC
+	require.Error(t, exportOverTLS(addr, withoutCert))
+	require.NoError(t, exportOverTLS(addr, withCert))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway
+	url := fmt.Sprintf("https://%s/v1/trace", addr)
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	_, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: withoutCert}}).
+		Post(url, "application/json", bytes.NewBuffer(traceJSON))
+	require.Error(t, err)
+
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: withCert}}).
+		Post(url, "application/json", bytes.NewBuffer(traceJSON))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, sink.AllTraces(), 2)
+}
+
+// exportOverTLS sends a span to the receiver and waits until the server ends
+// the stream.
+func exportOverTLS(addr string, tlsCfg *tls.Config) error {
+	return exportSpan(context.Background(), addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+}
+
+// exportSpan sends a span to the receiver, with the outgoing metadata of the
+// given context, and waits until the server ends the stream.
+func exportSpan(ctx context.Context, addr string, opts ...grpc.DialOption) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	cc, err := grpc.DialContext(ctx, addr,
+		append(opts, grpc.WithBlock(), grpc.WithDisableRetry())...)
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(ctx)
+	if err != nil {
+		return err
+	}
+	msg := &agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{HostName: "testHost"}},
+		Spans: []*tracepb.Span{{TraceId: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}}},
+	}
+	if err := stream.Send(msg); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func TestAuthentication(t *testing.T) {
+	a, err := configauth.Settings{
+		BearerTokens: []string{"token"},
+		APIKeyHeader: "X-API-Key",
+		APIKeys:      []string{"key"},
+	}.NewAuthenticator()
+	require.NoError(t, err)
+
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithAuthenticator(a))
+	require.NoError(t, err)
+
+	mh := receivertest.NewMockHost()
+	require.NoError(t, ocr.StartTraceReception(mh))
+	defer ocr.StopTraceReception()
+
+	// gRPC
+	err = exportSpan(context.Background(), addr, grpc.WithInsecure())
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	require.NoError(t, exportSpan(ctx, addr, grpc.WithInsecure()))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway
+	url := fmt.Sprintf("http://%s/v1/trace", addr)
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	for header, allowed := range map[string]bool{"": false, "Authorization": false, "X-API-Key": true} {
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(traceJSON))
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set(header, "key")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		if allowed {
+			require.Equal(t, http.StatusOK, resp.StatusCode, header)
+		} else {
+			require.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+		}
+	}
+	require.Len(t, sink.AllTraces(), 2)
+}
+
+func TestLimits(t *testing.T) {
+	l, err := configlimit.Settings{MaxRequestsPerSecond: 2, MaxBatchSize: 1}.NewLimiter("opencensus")
+	require.NoError(t, err)
+
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithLimiter(l))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+	defer ocr.StopTraceReception()
+
+	// gRPC
+	require.NoError(t, exportSpan(context.Background(), addr, grpc.WithInsecure()))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway
+	url := fmt.Sprintf("http://%s/v1/trace", addr)
+	twoSpansJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="},{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXQ="}]}`)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(twoSpansJSON))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	// The two tokens of the rate limiter were used.
+	err = exportSpan(context.Background(), addr, grpc.WithInsecure())
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opencensusreceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "otelsvc.sock")
+	endpoint := confignet.UnixScheme + socket
+
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(endpoint, sink, nil, WithSocketPermissions(0600))
+	require.NoError(t, err)
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+
+	// gRPC
+	target, opts := confignet.GRPCDialOptions(endpoint)
+	require.NoError(t, exportSpan(context.Background(), target, append(opts, grpc.WithInsecure())...))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	resp, err := client.Post("http://localhost/v1/trace", "application/json", bytes.NewBuffer(traceJSON))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The socket is removed on shutdown.
+	require.NoError(t, ocr.StopTraceReception())
+	_, err = os.Stat(socket)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestHTTPEndpoint(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	httpAddr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithHTTPEndpoint(httpAddr))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+	defer ocr.StopTraceReception()
+
+	// gRPC
+	require.NoError(t, exportSpan(context.Background(), addr, grpc.WithInsecure()))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway, only on its own address.
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	resp, err := http.Post(fmt.Sprintf("http://%s/v1/trace", httpAddr), "application/json", bytes.NewBuffer(traceJSON))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, sink.AllTraces(), 2)
+
+	resp, err = http.Post(fmt.Sprintf("http://%s/v1/trace", addr), "application/json", bytes.NewBuffer(traceJSON))
+	if err == nil {
+		resp.Body.Close()
+		require.NotEqual(t, http.StatusOK, resp.StatusCode)
+	}
+	require.Len(t, sink.AllTraces(), 2)
+}
+
+func TestHTTPEndpoint_TLS(t *testing.T) {
+	serverTLS, err := configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "../../config/configtls/testdata/server.crt",
+			KeyFile:  "../../config/configtls/testdata/server.key",
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientTLS, err := configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: "../../config/configtls/testdata/ca.crt"},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	addr := testutils.GetAvailableLocalAddress(t)
+	httpAddr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithHTTPEndpoint(httpAddr), WithTLSConfig(serverTLS))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+	defer ocr.StopTraceReception()
+
+	// Use host names matching the server certificate.
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	addr = net.JoinHostPort("localhost", port)
+	_, port, err = net.SplitHostPort(httpAddr)
+	require.NoError(t, err)
+	httpAddr = net.JoinHostPort("localhost", port)
+
+	// gRPC
+	require.NoError(t, exportOverTLS(addr, clientTLS))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// HTTP/JSON through the grpc-gateway
+	traceJSON := []byte(`{"node":{"identifier":{"hostName":"testHost"}},"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"7uGbfsPBsXM="}]}`)
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}).
+		Post(fmt.Sprintf("https://%s/v1/trace", httpAddr), "application/json", bytes.NewBuffer(traceJSON))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, sink.AllTraces(), 2)
+}
+
+func TestGracefulStop(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithDrainTimeout(5*time.Second))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+	msg := &agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{HostName: "testHost"}},
+		Spans: []*tracepb.Span{{TraceId: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}}},
+	}
+	require.NoError(t, stream.Send(msg))
+	// Wait for the stream to be active on the server.
+	for i := 0; i < 100 && len(sink.AllTraces()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Len(t, sink.AllTraces(), 1)
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- ocr.StopTraceReception()
+	}()
+
+	// The active stream keeps the receiver from stopping and can still be used.
+	select {
+	case <-stopped:
+		t.Fatal("the receiver stopped before the end of the active stream")
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.NoError(t, stream.Send(msg))
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the receiver didn't stop at the end of the active stream")
+	}
+	// All the data received was exported before the receiver stopped.
+	require.Len(t, sink.AllTraces(), 2)
+
+	// New connections are refused.
+	_, err = net.DialTimeout("tcp", addr, time.Second)
+	require.Error(t, err)
+}
+
+func TestDrainTimeout(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil, WithDrainTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+	stream, err := agenttracepb.NewTraceServiceClient(cc).Export(context.Background())
+	require.NoError(t, err)
+	msg := &agenttracepb.ExportTraceServiceRequest{
+		Node:  &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{HostName: "testHost"}},
+		Spans: []*tracepb.Span{{TraceId: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}}},
+	}
+	require.NoError(t, stream.Send(msg))
+	for i := 0; i < 100 && len(sink.AllTraces()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Len(t, sink.AllTraces(), 1)
+
+	// The stream is never closed by the client, it is closed by the receiver
+	// once the drain timeout expires.
+	start := time.Now()
+	require.NoError(t, ocr.StopTraceReception())
+	require.True(t, time.Since(start) < 2*time.Second, "stopped in %v", time.Since(start))
+	_, err = stream.Recv()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+}
+
+func TestConformance(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkTraceExporter)
+	ocr, err := New(addr, sink, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ocr.StartTraceReception(receivertest.NewMockHost()))
+	defer ocr.StopTraceReception()
+
+	conformance.Run(t, conformance.Target{
+		Endpoints: map[conformance.Protocol]string{conformance.OpenCensus: addr},
+		Sink:      sink,
+	})
+}
+
 func TestStopWithoutStartNeverCrashes(t *testing.T) {
 	ocr, err := New(":55444", nil, nil)
 	if err != nil {
@@ -355,7 +801,7 @@ func TestStopWithoutStartNeverCrashes(t *testing.T) {
 	ocr.stop()
 }
 
-func TestNewPortAlreadyUsed(t *testing.T) {
+func TestNewDoesNotBind(t *testing.T) {
 	addr := testutils.GetAvailableLocalAddress(t)
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -363,13 +809,47 @@ func TestNewPortAlreadyUsed(t *testing.T) {
 	}
 	defer ln.Close()
 
+	// The address is bound when the receiver is started.
 	r, err := New(addr, nil, nil)
-	if err == nil {
+	if err != nil {
+		t.Fatalf("Failed to create an OpenCensus receiver: %v", err)
+	}
+	r.stop()
+}
+
+func TestStartPortAlreadyUsed(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to listen on %q: %v", addr, err)
+	}
+	defer ln.Close()
+
+	mh := receivertest.NewMockHost()
+	r, err := New(addr, new(exportertest.SinkTraceExporter), nil)
+	if err != nil {
+		t.Fatalf("Failed to create an OpenCensus receiver: %v", err)
+	}
+	if err := r.StartTraceReception(mh); err == nil {
+		t.Fatalf("want err got nil")
+	}
+	r.StopTraceReception()
+
+	// The gRPC address is released when the HTTP address is already used.
+	grpcAddr := testutils.GetAvailableLocalAddress(t)
+	r, err = New(grpcAddr, new(exportertest.SinkTraceExporter), nil, WithHTTPEndpoint(addr))
+	if err != nil {
+		t.Fatalf("Failed to create an OpenCensus receiver: %v", err)
+	}
+	if err := r.StartTraceReception(mh); err == nil {
 		t.Fatalf("want err got nil")
 	}
-	if r != nil {
-		t.Fatalf("want nil got %v", r)
+	r.StopTraceReception()
+	grpcLn, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		t.Fatalf("failed to listen on %q: %v", grpcAddr, err)
 	}
+	grpcLn.Close()
 }
 
 func TestMultipleStopReceptionShouldNotError(t *testing.T) {
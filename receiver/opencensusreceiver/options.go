@@ -15,8 +15,14 @@
 package opencensusreceiver
 
 import (
+	"crypto/tls"
+	"os"
+	"time"
+
 	"google.golang.org/grpc"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver/ocmetrics"
 	"github.com/open-telemetry/opentelemetry-service/receiver/opencensusreceiver/octrace"
 )
@@ -76,6 +82,54 @@ func WithCorsOrigins(origins []string) Option {
 	return &corsOrigins{origins: origins}
 }
 
+type corsHeaders struct {
+	headers []string
+}
+
+var _ Option = (*corsHeaders)(nil)
+
+func (ch *corsHeaders) withReceiver(ocr *Receiver) {
+	ocr.corsHeaders = ch.headers
+}
+
+// WithCorsHeaders is an option to specify the headers, in addition to the
+// simple ones, that the CORS requests are allowed to send, e.g. "Authorization".
+func WithCorsHeaders(headers []string) Option {
+	return &corsHeaders{headers: headers}
+}
+
+type corsMethods struct {
+	methods []string
+}
+
+var _ Option = (*corsMethods)(nil)
+
+func (cm *corsMethods) withReceiver(ocr *Receiver) {
+	ocr.corsMethods = cm.methods
+}
+
+// WithCorsMethods is an option to specify the methods the CORS requests are
+// allowed to use. The default is GET, POST and HEAD.
+func WithCorsMethods(methods []string) Option {
+	return &corsMethods{methods: methods}
+}
+
+type corsMaxAge struct {
+	maxAge time.Duration
+}
+
+var _ Option = (*corsMaxAge)(nil)
+
+func (cma *corsMaxAge) withReceiver(ocr *Receiver) {
+	ocr.corsMaxAge = cma.maxAge
+}
+
+// WithCorsMaxAge is an option to specify how long the browsers can cache the
+// responses to the CORS preflight requests. They aren't cached by default.
+func WithCorsMaxAge(maxAge time.Duration) Option {
+	return &corsMaxAge{maxAge: maxAge}
+}
+
 var _ Option = (grpcServerOptions)(nil)
 
 type grpcServerOptions []grpc.ServerOption
@@ -90,6 +144,110 @@ func WithGRPCServerOptions(gsOpts ...grpc.ServerOption) Option {
 	return gsvOpts
 }
 
+type tlsConfig struct {
+	cfg *tls.Config
+}
+
+var _ Option = (*tlsConfig)(nil)
+
+func (tc *tlsConfig) withReceiver(ocr *Receiver) {
+	ocr.tlsConfig = tc.cfg
+}
+
+// WithTLSConfig is an option to terminate TLS on the receiver, for both the
+// gRPC and the HTTP/JSON (grpc-gateway) servers. Client certificates are
+// verified according to the given config, e.g. set ClientCAs and ClientAuth
+// to require mutual TLS. With TLS the gRPC requests are served through the
+// HTTP/2 server of the standard library, which ignores the keepalive and
+// max concurrent streams gRPC server options.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return &tlsConfig{cfg: cfg}
+}
+
+type authenticator struct {
+	a configauth.Authenticator
+}
+
+var _ Option = (*authenticator)(nil)
+
+func (ao *authenticator) withReceiver(ocr *Receiver) {
+	ocr.authenticator = ao.a
+}
+
+// WithAuthenticator is an option to reject the requests, both gRPC and
+// HTTP/JSON, that the given authenticator doesn't allow.
+func WithAuthenticator(a configauth.Authenticator) Option {
+	return &authenticator{a: a}
+}
+
+type limiter struct {
+	l *configlimit.Limiter
+}
+
+var _ Option = (*limiter)(nil)
+
+func (lo *limiter) withReceiver(ocr *Receiver) {
+	ocr.limiter = lo.l
+}
+
+// WithLimiter is an option to enforce the limits of the given limiter on the
+// connections and on the requests, both gRPC and HTTP/JSON. The requests
+// exceeding them fail with ResourceExhausted, or 429 Too Many Requests for
+// HTTP/JSON. Without TLS the HTTP/JSON gateway keeps a gRPC connection to the
+// receiver, counted in the maximum number of connections.
+func WithLimiter(l *configlimit.Limiter) Option {
+	return &limiter{l: l}
+}
+
+type socketPermissions struct {
+	perm os.FileMode
+}
+
+var _ Option = (*socketPermissions)(nil)
+
+func (sp *socketPermissions) withReceiver(ocr *Receiver) {
+	ocr.socketPermissions = sp.perm
+}
+
+// WithSocketPermissions is an option to set the permissions of the Unix
+// domain socket created when the address is like "unix:///path/to/socket".
+func WithSocketPermissions(perm os.FileMode) Option {
+	return &socketPermissions{perm: perm}
+}
+
+type drainTimeout struct {
+	timeout time.Duration
+}
+
+var _ Option = (*drainTimeout)(nil)
+
+func (dt *drainTimeout) withReceiver(ocr *Receiver) {
+	ocr.drainTimeout = dt.timeout
+}
+
+// WithDrainTimeout is an option to set how long the receiver waits, when it
+// is stopped, for the active gRPC streams and HTTP requests to finish before
+// closing them. The default is 5s.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return &drainTimeout{timeout: timeout}
+}
+
+type httpEndpoint struct {
+	endpoint string
+}
+
+var _ Option = (*httpEndpoint)(nil)
+
+func (he *httpEndpoint) withReceiver(ocr *Receiver) {
+	ocr.httpEndpoint = he.endpoint
+}
+
+// WithHTTPEndpoint is an option to serve the HTTP/JSON (grpc-gateway) requests
+// on their own endpoint, instead of sharing the address of the gRPC server.
+func WithHTTPEndpoint(endpoint string) Option {
+	return &httpEndpoint{endpoint: endpoint}
+}
+
 type noopOption int
 
 var _ Option = (noopOption)(0)
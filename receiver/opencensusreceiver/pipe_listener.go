@@ -0,0 +1,80 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opencensusreceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+var errPipeListenerClosed = errors.New("pipe listener closed")
+
+// pipeListener is a net.Listener of in-memory connections. It is used by the
+// grpc-gateway to reach the gRPC server when TLS is enabled, since it can't
+// present a client certificate to the TLS listener.
+type pipeListener struct {
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ net.Listener = (*pipeListener)(nil)
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+func (pl *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-pl.conns:
+		return conn, nil
+	case <-pl.closed:
+		return nil, errPipeListenerClosed
+	}
+}
+
+func (pl *pipeListener) Close() error {
+	pl.closeOnce.Do(func() {
+		close(pl.closed)
+	})
+	return nil
+}
+
+func (pl *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// dial returns the client side of a new connection, the address is ignored.
+func (pl *pipeListener) dial(ctx context.Context, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case pl.conns <- server:
+		return client, nil
+	case <-pl.closed:
+		return nil, errPipeListenerClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
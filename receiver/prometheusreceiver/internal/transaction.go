@@ -146,7 +146,8 @@ func (tr *transaction) Commit() error {
 			Node:    tr.node,
 			Metrics: metrics,
 		}
-		return tr.sink.ConsumeMetricsData(context.Background(), md)
+		// The export is aborted if the receiver stops meanwhile.
+		return tr.sink.ConsumeMetricsData(tr.ctx, md)
 	}
 	return nil
 }
@@ -0,0 +1,111 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package receiverhelper contains helpers shared by receivers.
+package receiverhelper
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ScheduleSettings defines configuration for when an interval-based receiver
+// (e.g. a receiver scraping a target) runs for the first time. Spreading the
+// first run avoids many collectors or targets being scraped in lockstep.
+type ScheduleSettings struct {
+	// InitialDelay is the time to wait before the first run. If zero the first
+	// run happens after one interval.
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	// Jitter is the upper bound of a random delay added on top of the delay
+	// before the first run, so it never makes the first run happen earlier.
+	Jitter time.Duration `mapstructure:"jitter"`
+}
+
+// Scheduler runs a function periodically, the first run happening after the
+// configured initial delay plus a random jitter.
+type Scheduler struct {
+	interval time.Duration
+	settings ScheduleSettings
+	run      func()
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewScheduler creates a Scheduler that calls run every interval.
+func NewScheduler(interval time.Duration, settings ScheduleSettings, run func()) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		settings: settings,
+		run:      run,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts the goroutine that calls run periodically. Calling Start more
+// than once has no effect.
+func (s *Scheduler) Start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.loop()
+	})
+}
+
+// Stop stops the scheduler and waits for any in progress run to finish.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+}
+
+// firstRunDelay returns the delay before the first run.
+func (s *Scheduler) firstRunDelay() time.Duration {
+	delay := s.settings.InitialDelay
+	if delay <= 0 {
+		// Preserve the behavior of a plain ticker: first run after one interval.
+		delay = s.interval
+	}
+	if s.settings.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(s.settings.Jitter)))
+	}
+	return delay
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(s.firstRunDelay())
+	select {
+	case <-timer.C:
+	case <-s.done:
+		timer.Stop()
+		return
+	}
+	s.run()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.run()
+		case <-s.done:
+			return
+		}
+	}
+}
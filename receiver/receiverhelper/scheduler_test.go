@@ -0,0 +1,73 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiverhelper
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_FirstRunDelay(t *testing.T) {
+	s := NewScheduler(time.Second, ScheduleSettings{}, func() {})
+	assert.Equal(t, time.Second, s.firstRunDelay())
+
+	s = NewScheduler(time.Second, ScheduleSettings{InitialDelay: time.Millisecond}, func() {})
+	assert.Equal(t, time.Millisecond, s.firstRunDelay())
+
+	s = NewScheduler(time.Second, ScheduleSettings{InitialDelay: time.Millisecond, Jitter: 10 * time.Millisecond}, func() {})
+	for i := 0; i < 100; i++ {
+		delay := s.firstRunDelay()
+		assert.True(t, delay >= time.Millisecond && delay < 11*time.Millisecond, "unexpected delay %v", delay)
+	}
+
+	// Jitter alone is added on top of the interval.
+	s = NewScheduler(time.Second, ScheduleSettings{Jitter: 10 * time.Millisecond}, func() {})
+	for i := 0; i < 100; i++ {
+		delay := s.firstRunDelay()
+		assert.True(t, delay >= time.Second && delay < time.Second+10*time.Millisecond, "unexpected delay %v", delay)
+	}
+}
+
+func TestScheduler_Runs(t *testing.T) {
+	var runs int32
+	s := NewScheduler(time.Millisecond, ScheduleSettings{InitialDelay: time.Millisecond}, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+	s.Start()
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&runs) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+	assert.True(t, atomic.LoadInt32(&runs) >= 3)
+
+	// No runs after Stop returned.
+	runsAfterStop := atomic.LoadInt32(&runs)
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, runsAfterStop, atomic.LoadInt32(&runs))
+}
+
+func TestScheduler_StopBeforeFirstRun(t *testing.T) {
+	var runs int32
+	s := NewScheduler(time.Millisecond, ScheduleSettings{InitialDelay: time.Hour}, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+	s.Start()
+	s.Stop()
+	assert.EqualValues(t, 0, atomic.LoadInt32(&runs))
+}
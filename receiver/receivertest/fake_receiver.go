@@ -0,0 +1,129 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+const fakeReceiverSource = "fake"
+
+// ErrNotStarted is returned by the FakeReceiver when data is sent while its
+// reception of this data type is not started.
+var ErrNotStarted = errors.New("the reception is not started")
+
+// FakeReceiver is a trace and metrics receiver passing the data the test
+// sends with SendTraceData and SendMetricsData to its consumers, e.g. to test
+// a pipeline without a network receiver.
+type FakeReceiver struct {
+	tc consumer.TraceConsumer
+	mc consumer.MetricsConsumer
+
+	mu             sync.Mutex
+	host           receiver.Host
+	traceStarted   bool
+	metricsStarted bool
+}
+
+var _ receiver.TraceReceiver = (*FakeReceiver)(nil)
+var _ receiver.MetricsReceiver = (*FakeReceiver)(nil)
+
+// NewFakeReceiver creates a FakeReceiver sending the data to tc and mc, either
+// can be nil if the receiver is not used for this data type.
+func NewFakeReceiver(tc consumer.TraceConsumer, mc consumer.MetricsConsumer) *FakeReceiver {
+	return &FakeReceiver{tc: tc, mc: mc}
+}
+
+// TraceSource returns the name of the trace data source.
+func (fr *FakeReceiver) TraceSource() string {
+	return fakeReceiverSource
+}
+
+// MetricsSource returns the name of the metrics data source.
+func (fr *FakeReceiver) MetricsSource() string {
+	return fakeReceiverSource
+}
+
+// StartTraceReception lets the test send trace data.
+func (fr *FakeReceiver) StartTraceReception(host receiver.Host) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.host = host
+	fr.traceStarted = true
+	return nil
+}
+
+// StopTraceReception stops the reception of trace data.
+func (fr *FakeReceiver) StopTraceReception() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.traceStarted = false
+	return nil
+}
+
+// StartMetricsReception lets the test send metrics data.
+func (fr *FakeReceiver) StartMetricsReception(host receiver.Host) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.host = host
+	fr.metricsStarted = true
+	return nil
+}
+
+// StopMetricsReception stops the reception of metrics data.
+func (fr *FakeReceiver) StopMetricsReception() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.metricsStarted = false
+	return nil
+}
+
+// Host returns the host the receiver was last started with, nil if it was
+// never started.
+func (fr *FakeReceiver) Host() receiver.Host {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	return fr.host
+}
+
+// SendTraceData passes the data to the trace consumer as if it was received,
+// the error of the consumer is returned.
+func (fr *FakeReceiver) SendTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	fr.mu.Lock()
+	started := fr.traceStarted
+	fr.mu.Unlock()
+	if !started {
+		return ErrNotStarted
+	}
+	return fr.tc.ConsumeTraceData(ctx, td)
+}
+
+// SendMetricsData passes the data to the metrics consumer as if it was
+// received, the error of the consumer is returned.
+func (fr *FakeReceiver) SendMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	fr.mu.Lock()
+	started := fr.metricsStarted
+	fr.mu.Unlock()
+	if !started {
+		return ErrNotStarted
+	}
+	return fr.mc.ConsumeMetricsData(ctx, md)
+}
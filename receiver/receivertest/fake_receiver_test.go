@@ -0,0 +1,72 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivertest
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestFakeReceiver_Trace(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	fr := NewFakeReceiver(sink, nil)
+	td := consumerdata.TraceData{Spans: make([]*tracepb.Span, 2)}
+
+	assert.Equal(t, ErrNotStarted, fr.SendTraceData(context.Background(), td))
+	assert.Nil(t, fr.Host())
+
+	host := NewMockHost()
+	assert.NoError(t, fr.StartTraceReception(host))
+	assert.Equal(t, host, fr.Host())
+	assert.NoError(t, fr.SendTraceData(context.Background(), td))
+	assert.Equal(t, []consumerdata.TraceData{td}, sink.AllTraces())
+
+	assert.NoError(t, fr.StopTraceReception())
+	assert.Equal(t, ErrNotStarted, fr.SendTraceData(context.Background(), td))
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestFakeReceiver_Metrics(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	fr := NewFakeReceiver(nil, sink)
+	md := consumerdata.MetricsData{Metrics: make([]*metricspb.Metric, 3)}
+
+	assert.Equal(t, ErrNotStarted, fr.SendMetricsData(context.Background(), md))
+
+	assert.NoError(t, fr.StartMetricsReception(NewMockHost()))
+	assert.NoError(t, fr.SendMetricsData(context.Background(), md))
+	assert.Equal(t, []consumerdata.MetricsData{md}, sink.AllMetrics())
+
+	assert.NoError(t, fr.StopMetricsReception())
+	assert.Equal(t, ErrNotStarted, fr.SendMetricsData(context.Background(), md))
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	cfg := LoadConfigFile(t, path.Join("testdata", "config.yaml"), &config.ExampleReceiverFactory{})
+
+	r := cfg.Receivers["examplereceiver/custom"]
+	if assert.IsType(t, &config.ExampleReceiver{}, r) {
+		assert.Equal(t, "custom string", r.(*config.ExampleReceiver).ExtraSetting)
+	}
+}
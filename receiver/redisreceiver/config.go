@@ -0,0 +1,47 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
+)
+
+// Config defines configuration for the Redis receiver. The Endpoint is the
+// "host:port" address of the Redis server.
+type Config struct {
+	configmodels.ReceiverSettings   `mapstructure:",squash"`
+	receiverhelper.ScheduleSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which INFO is run.
+	CollectionInterval time.Duration `mapstructure:"collection-interval"`
+
+	// Timeout of the connection to the server and of the commands.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Password is sent with AUTH, if set.
+	Password string `mapstructure:"password"`
+
+	// TLSSettings enables TLS on the connection to the server.
+	TLSSettings *configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+}
+
+// ListenEndpoints returns nil, the endpoint is the one of the Redis server.
+func (cfg *Config) ListenEndpoints() []string {
+	return nil
+}
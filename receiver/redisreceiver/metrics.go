@@ -0,0 +1,221 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// This file converts the output of INFO to metrics, see
+// https://redis.io/commands/info.
+
+const (
+	unitBytes         = "By"
+	unitSeconds       = "s"
+	unitMilliseconds  = "ms"
+	unitDimensionless = "1"
+
+	resourceType = "redis"
+)
+
+// redisMetric is a metric of a field of INFO.
+type redisMetric struct {
+	field       string
+	name        string
+	description string
+	unit        string
+	metricType  metricspb.MetricDescriptor_Type
+}
+
+var redisMetrics = []redisMetric{
+	{"uptime_in_seconds", "redis.uptime", "The time since the server started", unitSeconds, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+
+	{"connected_clients", "redis.clients.connected", "The number of client connections", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"blocked_clients", "redis.clients.blocked", "The number of clients blocked by a blocking command", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"total_connections_received", "redis.connections.received", "The number of connections accepted by the server", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"rejected_connections", "redis.connections.rejected", "The number of connections rejected because of the maxclients limit", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+
+	{"used_memory", "redis.memory.used", "The memory allocated by Redis", unitBytes, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"used_memory_rss", "redis.memory.rss", "The memory allocated by Redis as seen by the operating system", unitBytes, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"used_memory_peak", "redis.memory.peak", "The peak memory allocated by Redis", unitBytes, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"used_memory_lua", "redis.memory.lua", "The memory used by the Lua engine", unitBytes, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"mem_fragmentation_ratio", "redis.memory.fragmentation_ratio", "The ratio of the RSS to the memory allocated by Redis", unitDimensionless, metricspb.MetricDescriptor_GAUGE_DOUBLE},
+
+	{"total_commands_processed", "redis.commands.processed", "The number of commands processed by the server", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"instantaneous_ops_per_sec", "redis.commands", "The number of commands processed per second", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"total_net_input_bytes", "redis.net.input", "The bytes received by the server", unitBytes, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"total_net_output_bytes", "redis.net.output", "The bytes sent by the server", unitBytes, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"expired_keys", "redis.keys.expired", "The number of keys expired", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"evicted_keys", "redis.keys.evicted", "The number of keys evicted because of the maxmemory limit", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"keyspace_hits", "redis.keyspace.hits", "The number of successful key lookups", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"keyspace_misses", "redis.keyspace.misses", "The number of failed key lookups", unitDimensionless, metricspb.MetricDescriptor_CUMULATIVE_INT64},
+	{"latest_fork_usec", "redis.latest_fork", "The duration of the latest fork", "us", metricspb.MetricDescriptor_GAUGE_INT64},
+
+	{"rdb_changes_since_last_save", "redis.rdb.changes_since_last_save", "The number of changes since the last dump", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+
+	{"connected_slaves", "redis.replication.replicas", "The number of connected replicas", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"master_repl_offset", "redis.replication.offset", "The replication offset of the server", unitBytes, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"master_last_io_seconds_ago", "redis.replication.master_last_io", "The time since the last interaction with the master, on replicas", unitSeconds, metricspb.MetricDescriptor_GAUGE_INT64},
+}
+
+// keyspaceMetrics are the metrics of the "db<n>:keys=...,expires=...,avg_ttl=..."
+// lines, labeled by db.
+var keyspaceMetrics = []redisMetric{
+	{"keys", "redis.db.keys", "The number of keys of the database", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"expires", "redis.db.expires", "The number of keys with an expiration of the database", unitDimensionless, metricspb.MetricDescriptor_GAUGE_INT64},
+	{"avg_ttl", "redis.db.avg_ttl", "The average time to live of the keys with an expiration of the database", unitMilliseconds, metricspb.MetricDescriptor_GAUGE_INT64},
+}
+
+const dbLabel = "db"
+
+// parseInfo returns the fields of the INFO output, and the fields of the
+// keyspace lines by db.
+func parseInfo(info string) (map[string]string, map[string]map[string]string) {
+	fields := make(map[string]string)
+	keyspace := make(map[string]map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		if strings.HasPrefix(kv[0], "db") && strings.Contains(kv[1], "keys=") {
+			dbFields := make(map[string]string)
+			for _, field := range strings.Split(kv[1], ",") {
+				if fkv := strings.SplitN(field, "=", 2); len(fkv) == 2 {
+					dbFields[fkv[0]] = fkv[1]
+				}
+			}
+			keyspace[strings.TrimPrefix(kv[0], "db")] = dbFields
+			continue
+		}
+		fields[kv[0]] = kv[1]
+	}
+	return fields, keyspace
+}
+
+// infoToMetrics converts the INFO output to metrics. The start time of the
+// cumulative metrics is the start time of the server. The fields missing in
+// the output, e.g. the replication offset of old servers, are skipped.
+func infoToMetrics(info string, now time.Time) (*resourcepb.Resource, []*metricspb.Metric, error) {
+	fields, keyspace := parseInfo(info)
+
+	ts := toTimestamp(now)
+	var startTime *timestamp.Timestamp
+	if uptime, err := strconv.ParseInt(fields["uptime_in_seconds"], 10, 64); err == nil {
+		startTime = toTimestamp(now.Add(-time.Duration(uptime) * time.Second))
+	}
+
+	var metrics []*metricspb.Metric
+	for _, rm := range redisMetrics {
+		value, ok := fields[rm.field]
+		if !ok {
+			continue
+		}
+		point, err := toPoint(rm.metricType, value, ts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %v", rm.field, err)
+		}
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: rm.descriptor(nil),
+			Timeseries:       []*metricspb.TimeSeries{rm.timeseries(startTime, nil, point)},
+		})
+	}
+
+	if len(keyspace) > 0 {
+		dbs := make([]string, 0, len(keyspace))
+		for db := range keyspace {
+			dbs = append(dbs, db)
+		}
+		sort.Strings(dbs)
+
+		for _, rm := range keyspaceMetrics {
+			m := &metricspb.Metric{MetricDescriptor: rm.descriptor([]*metricspb.LabelKey{{Key: dbLabel}})}
+			for _, db := range dbs {
+				value, ok := keyspace[db][rm.field]
+				if !ok {
+					continue
+				}
+				point, err := toPoint(rm.metricType, value, ts)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid %s of db%s: %v", rm.field, db, err)
+				}
+				labelValues := []*metricspb.LabelValue{{Value: db, HasValue: true}}
+				m.Timeseries = append(m.Timeseries, rm.timeseries(startTime, labelValues, point))
+			}
+			if len(m.Timeseries) > 0 {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+
+	resource := &resourcepb.Resource{Type: resourceType}
+	if version, ok := fields["redis_version"]; ok {
+		resource.Labels = map[string]string{"redis.version": version}
+	}
+	return resource, metrics, nil
+}
+
+func (rm *redisMetric) descriptor(labelKeys []*metricspb.LabelKey) *metricspb.MetricDescriptor {
+	return &metricspb.MetricDescriptor{
+		Name:        rm.name,
+		Description: rm.description,
+		Unit:        rm.unit,
+		Type:        rm.metricType,
+		LabelKeys:   labelKeys,
+	}
+}
+
+func (rm *redisMetric) timeseries(startTime *timestamp.Timestamp, labelValues []*metricspb.LabelValue, point *metricspb.Point) *metricspb.TimeSeries {
+	ts := &metricspb.TimeSeries{
+		LabelValues: labelValues,
+		Points:      []*metricspb.Point{point},
+	}
+	if rm.metricType == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+		ts.StartTimestamp = startTime
+	}
+	return ts
+}
+
+func toPoint(metricType metricspb.MetricDescriptor_Type, value string, ts *timestamp.Timestamp) (*metricspb.Point, error) {
+	if metricType == metricspb.MetricDescriptor_GAUGE_DOUBLE {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &metricspb.Point{Timestamp: ts, Value: &metricspb.Point_DoubleValue{DoubleValue: v}}, nil
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &metricspb.Point{Timestamp: ts, Value: &metricspb.Point_Int64Value{Int64Value: v}}, nil
+}
+
+func toTimestamp(t time.Time) *timestamp.Timestamp {
+	return &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
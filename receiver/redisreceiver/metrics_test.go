@@ -0,0 +1,88 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoToMetrics(t *testing.T) {
+	info, err := ioutil.ReadFile("./testdata/info.txt")
+	require.NoError(t, err)
+
+	now := time.Unix(1567000000, 0)
+	resource, metrics, err := infoToMetrics(string(info), now)
+	require.NoError(t, err)
+	assert.Equal(t, &resourcepb.Resource{Type: "redis", Labels: map[string]string{"redis.version": "5.0.5"}}, resource)
+
+	byName := make(map[string]*metricspb.Metric)
+	for _, m := range metrics {
+		byName[m.MetricDescriptor.Name] = m
+	}
+	// All the metrics but the master_last_io_seconds_ago of the replicas.
+	assert.Equal(t, len(redisMetrics)+len(keyspaceMetrics)-1, len(byName))
+	assert.Nil(t, byName["redis.replication.master_last_io"])
+
+	clients := byName["redis.clients.connected"]
+	require.NotNil(t, clients)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, clients.MetricDescriptor.Type)
+	assert.Equal(t, int64(5), clients.Timeseries[0].Points[0].GetInt64Value())
+	assert.Nil(t, clients.Timeseries[0].StartTimestamp)
+
+	commands := byName["redis.commands.processed"]
+	require.NotNil(t, commands)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, commands.MetricDescriptor.Type)
+	assert.Equal(t, int64(5000), commands.Timeseries[0].Points[0].GetInt64Value())
+	// The cumulative metrics start when the server started.
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 1567000000 - 3600}, commands.Timeseries[0].StartTimestamp)
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 1567000000}, commands.Timeseries[0].Points[0].Timestamp)
+
+	ratio := byName["redis.memory.fragmentation_ratio"]
+	require.NotNil(t, ratio)
+	assert.Equal(t, 2.0, ratio.Timeseries[0].Points[0].GetDoubleValue())
+
+	keys := byName["redis.db.keys"]
+	require.NotNil(t, keys)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "db"}}, keys.MetricDescriptor.LabelKeys)
+	require.Equal(t, 2, len(keys.Timeseries))
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "0", HasValue: true}}, keys.Timeseries[0].LabelValues)
+	assert.Equal(t, int64(10), keys.Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "3", HasValue: true}}, keys.Timeseries[1].LabelValues)
+	assert.Equal(t, int64(1), keys.Timeseries[1].Points[0].GetInt64Value())
+}
+
+func TestInfoToMetrics_MissingFields(t *testing.T) {
+	resource, metrics, err := infoToMetrics("# Clients\r\nconnected_clients:5\r\n", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, &resourcepb.Resource{Type: "redis"}, resource)
+	require.Equal(t, 1, len(metrics))
+	assert.Equal(t, "redis.clients.connected", metrics[0].MetricDescriptor.Name)
+}
+
+func TestInfoToMetrics_Invalid(t *testing.T) {
+	_, _, err := infoToMetrics("connected_clients:five\r\n", time.Now())
+	assert.Error(t, err)
+
+	_, _, err = infoToMetrics("db0:keys=ten,expires=0,avg_ttl=0\r\n", time.Now())
+	assert.Error(t, err)
+}
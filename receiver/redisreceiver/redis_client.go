@@ -0,0 +1,130 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// redisClient runs the commands of the receiver on a new connection each
+// time, using the RESP protocol, see https://redis.io/topics/protocol.
+type redisClient struct {
+	endpoint  string
+	password  string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+// info returns the output of the INFO command.
+func (c *redisClient) info() (string, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if c.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	if c.password != "" {
+		if _, err := c.do(rw, "AUTH", c.password); err != nil {
+			return "", fmt.Errorf("AUTH failed: %v", err)
+		}
+	}
+	info, err := c.do(rw, "INFO")
+	if err != nil {
+		return "", fmt.Errorf("INFO failed: %v", err)
+	}
+	return info, nil
+}
+
+func (c *redisClient) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	if c.tlsConfig == nil {
+		return dialer.Dial("tcp", c.endpoint)
+	}
+
+	tlsConfig := c.tlsConfig
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.endpoint)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = host
+	}
+	return tls.DialWithDialer(dialer, "tcp", c.endpoint, tlsConfig)
+}
+
+// do sends the command and returns its simple string or bulk string reply.
+func (c *redisClient) do(rw *bufio.ReadWriter, args ...string) (string, error) {
+	fmt.Fprintf(rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := rw.Flush(); err != nil {
+		return "", err
+	}
+	return readReply(rw.Reader)
+}
+
+func readReply(r *bufio.Reader) (string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 {
+		return "", errors.New("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return "", errors.New(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid bulk string length %q", line[1:])
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	default:
+		return "", fmt.Errorf("unexpected reply %q", line)
+	}
+}
+
+// readLine reads a CRLF terminated line, without the CRLF.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("invalid line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redisreceiver runs INFO on a Redis server periodically and converts
+// its output to metrics.
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
+)
+
+var (
+	errNilNextConsumer = errors.New("nil nextConsumer")
+	errAlreadyStarted  = errors.New("already started")
+	errAlreadyStopped  = errors.New("already stopped")
+)
+
+const metricsSource = "Redis"
+
+var _ receiver.MetricsReceiver = (*redisReceiver)(nil)
+
+// redisReceiver implements the receiver.MetricsReceiver for the INFO of a
+// Redis server.
+type redisReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	client       *redisClient
+	nextConsumer consumer.MetricsConsumer
+	scheduler    *receiverhelper.Scheduler
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func newRedisReceiver(
+	logger *zap.Logger,
+	client *redisClient,
+	interval time.Duration,
+	scheduleSettings receiverhelper.ScheduleSettings,
+	nextConsumer consumer.MetricsConsumer,
+) (*redisReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	r := &redisReceiver{
+		logger:       logger,
+		client:       client,
+		nextConsumer: nextConsumer,
+	}
+	r.scheduler = receiverhelper.NewScheduler(interval, scheduleSettings, r.scrape)
+	return r, nil
+}
+
+// MetricsSource returns the name of the metrics data source.
+func (r *redisReceiver) MetricsSource() string {
+	return metricsSource
+}
+
+// StartMetricsReception starts running INFO periodically.
+func (r *redisReceiver) StartMetricsReception(host receiver.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := errAlreadyStarted
+	r.startOnce.Do(func() {
+		r.scheduler.Start()
+		err = nil
+	})
+	return err
+}
+
+// StopMetricsReception stops running INFO, waiting for a run in progress.
+func (r *redisReceiver) StopMetricsReception() error {
+	r.Lock()
+	defer r.Unlock()
+
+	var err = errAlreadyStopped
+	r.stopOnce.Do(func() {
+		r.scheduler.Stop()
+		err = nil
+	})
+	return err
+}
+
+func (r *redisReceiver) scrape() {
+	info, err := r.client.info()
+	if err != nil {
+		r.logger.Error("Failed to run INFO on the Redis server", zap.String("endpoint", r.client.endpoint), zap.Error(err))
+		return
+	}
+	resource, metrics, err := infoToMetrics(info, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to convert the INFO of the Redis server", zap.String("endpoint", r.client.endpoint), zap.Error(err))
+		return
+	}
+
+	ctx := obsreport.WithTransport(context.Background(), "tcp")
+	md := consumerdata.MetricsData{Resource: resource, Metrics: metrics}
+	if err := r.nextConsumer.ConsumeMetricsData(ctx, md); err != nil {
+		r.logger.Debug("Failed to send the Redis metrics", zap.Error(err))
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
+)
+
+// fakeRedis serves the INFO of the testdata, and requires AUTH if the
+// password is set.
+func fakeRedis(t *testing.T, password string) net.Listener {
+	info, err := ioutil.ReadFile("./testdata/info.txt")
+	require.NoError(t, err)
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				authenticated := password == ""
+				for {
+					args, err := readCommand(r)
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(args[0]) {
+					case "AUTH":
+						if len(args) != 2 || args[1] != password {
+							fmt.Fprint(conn, "-ERR invalid password\r\n")
+							continue
+						}
+						authenticated = true
+						fmt.Fprint(conn, "+OK\r\n")
+					case "INFO":
+						if !authenticated {
+							fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+							continue
+						}
+						fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(info), info)
+					default:
+						fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+					}
+				}
+			}(conn)
+		}
+	}()
+	return l
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	var n int
+	if _, err := fmt.Sscanf(line, "*%d", &n); err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		arg, err := readReply(r)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func TestClientInfo(t *testing.T) {
+	l := fakeRedis(t, "secret")
+	defer l.Close()
+
+	client := &redisClient{endpoint: l.Addr().String(), password: "secret", timeout: 5 * time.Second}
+	info, err := client.info()
+	require.NoError(t, err)
+	assert.Contains(t, info, "redis_version:5.0.5")
+
+	client.password = "wrong"
+	_, err = client.info()
+	assert.EqualError(t, err, "AUTH failed: ERR invalid password")
+
+	client.password = ""
+	_, err = client.info()
+	assert.EqualError(t, err, "INFO failed: NOAUTH Authentication required.")
+}
+
+func TestScrape(t *testing.T) {
+	l := fakeRedis(t, "")
+	defer l.Close()
+
+	sink := new(exportertest.SinkMetricsExporter)
+	client := &redisClient{endpoint: l.Addr().String(), timeout: 5 * time.Second}
+	r, err := newRedisReceiver(zap.NewNop(), client, 10*time.Millisecond, receiverhelper.ScheduleSettings{}, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.StartMetricsReception(nil))
+	assert.Equal(t, errAlreadyStarted, r.StartMetricsReception(nil))
+	for i := 0; i < 500 && len(sink.AllMetrics()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, r.StopMetricsReception())
+	assert.Equal(t, errAlreadyStopped, r.StopMetricsReception())
+
+	require.NotEmpty(t, sink.AllMetrics())
+	md := sink.AllMetrics()[0]
+	assert.Equal(t, "redis", md.Resource.Type)
+	assert.Equal(t, len(redisMetrics)+len(keyspaceMetrics)-1, len(md.Metrics))
+}
+
+func TestScrape_ServerDown(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	endpoint := l.Addr().String()
+	l.Close()
+
+	sink := new(exportertest.SinkMetricsExporter)
+	client := &redisClient{endpoint: endpoint, timeout: time.Second}
+	r, err := newRedisReceiver(zap.NewNop(), client, time.Minute, receiverhelper.ScheduleSettings{}, sink)
+	require.NoError(t, err)
+	r.scrape()
+	assert.Empty(t, sink.AllMetrics())
+}
@@ -18,13 +18,15 @@ import (
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
 )
 
 // Config defines configuration for VMMetrics receiver.
 type Config struct {
-	configmodels.ReceiverSettings `mapstructure:",squash"`
-	ScrapeInterval                time.Duration `mapstructure:"scrape_interval"`
-	MountPoint                    string        `mapstructure:"mount_point"`
-	ProcessMountPoint             string        `mapstructure:"process_mount_point"`
-	MetricPrefix                  string        `mapstructure:"metric_prefix"`
+	configmodels.ReceiverSettings   `mapstructure:",squash"`
+	receiverhelper.ScheduleSettings `mapstructure:",squash"`
+	ScrapeInterval                  time.Duration `mapstructure:"scrape_interval"`
+	MountPoint                      string        `mapstructure:"mount_point"`
+	ProcessMountPoint               string        `mapstructure:"process_mount_point"`
+	MetricPrefix                    string        `mapstructure:"metric_prefix"`
 }
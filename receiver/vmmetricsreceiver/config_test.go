@@ -24,6 +24,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -51,6 +52,10 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: typeStr,
 				NameVal: "vmmetrics/customname",
 			},
+			ScheduleSettings: receiverhelper.ScheduleSettings{
+				InitialDelay: 2 * time.Second,
+				Jitter:       3 * time.Second,
+			},
 			ScrapeInterval:    5 * time.Second,
 			MetricPrefix:      "testmetric",
 			MountPoint:        "/mountpoint",
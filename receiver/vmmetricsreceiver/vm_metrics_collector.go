@@ -31,7 +31,9 @@ import (
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/internal"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
 	"github.com/open-telemetry/opentelemetry-service/oterr"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receiverhelper"
 )
 
 // VMMetricsCollector is a struct that collects and reports VM and process metrics (cpu, mem, etc).
@@ -46,7 +48,7 @@ type VMMetricsCollector struct {
 
 	scrapeInterval time.Duration
 	metricPrefix   string
-	done           chan struct{}
+	scheduler      *receiverhelper.Scheduler
 }
 
 const (
@@ -58,7 +60,12 @@ var rsc *resourcepb.Resource
 var resourceDetectionSync sync.Once
 
 // NewVMMetricsCollector creates a new set of VM and Process Metrics (mem, cpu).
-func NewVMMetricsCollector(si time.Duration, mountPoint, processMountPoint, prefix string, consumer consumer.MetricsConsumer) (*VMMetricsCollector, error) {
+func NewVMMetricsCollector(
+	si time.Duration,
+	scheduleSettings receiverhelper.ScheduleSettings,
+	mountPoint, processMountPoint, prefix string,
+	consumer consumer.MetricsConsumer,
+) (*VMMetricsCollector, error) {
 	if mountPoint == "" {
 		mountPoint = defaultMountPoint
 	}
@@ -84,8 +91,8 @@ func NewVMMetricsCollector(si time.Duration, mountPoint, processMountPoint, pref
 		pid:            os.Getpid(),
 		scrapeInterval: si,
 		metricPrefix:   prefix,
-		done:           make(chan struct{}),
 	}
+	vmc.scheduler = receiverhelper.NewScheduler(si, scheduleSettings, vmc.scrapeAndExport)
 
 	return vmc, nil
 }
@@ -108,27 +115,16 @@ func detectResource() {
 	})
 }
 
-// StartCollection starts a ticker'd goroutine that will scrape and export vm metrics periodically.
+// StartCollection starts a scheduled goroutine that will scrape and export vm metrics periodically.
 func (vmc *VMMetricsCollector) StartCollection() {
 	detectResource()
 
-	go func() {
-		ticker := time.NewTicker(vmc.scrapeInterval)
-		for {
-			select {
-			case <-ticker.C:
-				vmc.scrapeAndExport()
-
-			case <-vmc.done:
-				return
-			}
-		}
-	}()
+	vmc.scheduler.Start()
 }
 
 // StopCollection stops the collection of metric information
 func (vmc *VMMetricsCollector) StopCollection() {
-	close(vmc.done)
+	vmc.scheduler.Stop()
 }
 
 func (vmc *VMMetricsCollector) scrapeAndExport() {
@@ -159,21 +155,45 @@ func (vmc *VMMetricsCollector) scrapeAndExport() {
 		},
 	)
 
-	var proc procfs.Proc
-	var err error
-	proc, err = vmc.processFs.NewProc(vmc.pid)
-	if err == nil {
-		procStat, err := proc.NewStat()
-		if err == nil {
-			metrics = append(
-				metrics,
-				&metricspb.Metric{
-					MetricDescriptor: metricProcessCPUSeconds,
-					Resource:         rsc,
-					Timeseries:       []*metricspb.TimeSeries{vmc.getDoubleTimeSeries(procStat.CPUTime(), nil)},
-				},
-			)
-		}
+	proc, err := vmc.processFs.NewProc(vmc.pid)
+	ps, err := telemetry.ReadProcessStats(ms, proc, err)
+	metrics = append(
+		metrics,
+		&metricspb.Metric{
+			MetricDescriptor: metricProcessUptime,
+			Resource:         rsc,
+			Timeseries:       []*metricspb.TimeSeries{vmc.getDoubleTimeSeries(ps.Uptime.Seconds(), nil)},
+		},
+		&metricspb.Metric{
+			MetricDescriptor: metricProcessGCCount,
+			Resource:         rsc,
+			Timeseries:       []*metricspb.TimeSeries{vmc.getInt64TimeSeries(uint64(ps.NumGC))},
+		},
+		&metricspb.Metric{
+			MetricDescriptor: metricProcessGCPauseTotal,
+			Resource:         rsc,
+			Timeseries:       []*metricspb.TimeSeries{vmc.getDoubleTimeSeries(ps.GCPauseTotal.Seconds(), nil)},
+		},
+	)
+	if ps.ProcfsOK {
+		metrics = append(
+			metrics,
+			&metricspb.Metric{
+				MetricDescriptor: metricProcessCPUSeconds,
+				Resource:         rsc,
+				Timeseries:       []*metricspb.TimeSeries{vmc.getDoubleTimeSeries(ps.CPUSeconds, nil)},
+			},
+			&metricspb.Metric{
+				MetricDescriptor: metricProcessRSSMemory,
+				Resource:         rsc,
+				Timeseries:       []*metricspb.TimeSeries{vmc.getInt64TimeSeries(uint64(ps.RSSBytes))},
+			},
+			&metricspb.Metric{
+				MetricDescriptor: metricProcessOpenFDs,
+				Resource:         rsc,
+				Timeseries:       []*metricspb.TimeSeries{vmc.getInt64TimeSeries(uint64(ps.OpenFDs))},
+			},
+		)
 	}
 	if err != nil {
 		errs = append(errs, err)
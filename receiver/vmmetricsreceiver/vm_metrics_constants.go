@@ -52,6 +52,46 @@ var metricProcessCPUSeconds = &metricspb.MetricDescriptor{
 	LabelKeys:   nil,
 }
 
+var metricProcessUptime = &metricspb.MetricDescriptor{
+	Name:        "process/uptime",
+	Description: "Time since the process started",
+	Unit:        "s",
+	Type:        metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	LabelKeys:   nil,
+}
+
+var metricProcessRSSMemory = &metricspb.MetricDescriptor{
+	Name:        "process/rss_memory",
+	Description: "Resident set size of the process",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys:   nil,
+}
+
+var metricProcessOpenFDs = &metricspb.MetricDescriptor{
+	Name:        "process/open_fds",
+	Description: "Number of file descriptors open by the process",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys:   nil,
+}
+
+var metricProcessGCCount = &metricspb.MetricDescriptor{
+	Name:        "process/gc_count",
+	Description: "Number of completed garbage collections",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	LabelKeys:   nil,
+}
+
+var metricProcessGCPauseTotal = &metricspb.MetricDescriptor{
+	Name:        "process/gc_pause_total",
+	Description: "Total time the garbage collections stopped the process",
+	Unit:        "s",
+	Type:        metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	LabelKeys:   nil,
+}
+
 var metricCPUSeconds = &metricspb.MetricDescriptor{
 	Name:        "system/cpu_seconds",
 	Description: "Total kernel/system CPU seconds broken down by different states",
@@ -97,6 +137,11 @@ var vmMetricDescriptors = []*metricspb.MetricDescriptor{
 	metricTotalAllocMem,
 	metricSysMem,
 	metricProcessCPUSeconds,
+	metricProcessUptime,
+	metricProcessRSSMemory,
+	metricProcessOpenFDs,
+	metricProcessGCCount,
+	metricProcessGCPauseTotal,
 	metricCPUSeconds,
 	metricProcessesCreated,
 	metricProcessesRunning,
@@ -0,0 +1,193 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/receiver/jaegerreceiver"
+)
+
+// The formats of the payloads accepted by the HTTP server.
+const (
+	// FormatZipkin accepts the Zipkin v1 and v2 payloads, it is the default.
+	FormatZipkin = "zipkin"
+	// FormatAny also accepts the Jaeger thrift payloads, the format of each
+	// request is detected from its path and content type.
+	FormatAny = "any"
+)
+
+const (
+	zipkinV1Path    = "/api/v1/spans"
+	zipkinV2Path    = "/api/v2/spans"
+	jaegerTracePath = "/api/traces"
+
+	// sniffLen is the length of the beginning of the bodies inspected when
+	// the path of the request does not identify its format.
+	sniffLen = 512
+)
+
+type payloadFormat int
+
+const (
+	unknownFormat payloadFormat = iota
+	zipkinV1Format
+	zipkinV2Format
+	jaegerThriftFormat
+)
+
+// anyFormatHandler dispatches the requests to the Zipkin or the Jaeger
+// handler according to the format of their payload.
+type anyFormatHandler struct {
+	zipkin http.Handler
+	jaeger http.Handler
+}
+
+var _ http.Handler = (*anyFormatHandler)(nil)
+
+// newAnyFormatHandler returns the handler of the "any" format, the Jaeger
+// batches are limited and their IDs normalized like the Zipkin spans.
+func newAnyFormatHandler(zr *ZipkinReceiver, nextConsumer consumer.TraceConsumer) *anyFormatHandler {
+	return &anyFormatHandler{
+		zipkin: zr,
+		jaeger: jaegerreceiver.NewThriftHTTPHandler(&jaegerreceiver.Configuration{
+			CollectorLimiter: zr.limiter,
+			IDNormalization:  zr.idNormalization,
+		}, nextConsumer),
+	}
+}
+
+func (h *anyFormatHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch detectFormat(r) {
+	case zipkinV1Format:
+		h.zipkin.ServeHTTP(w, withPath(r, zipkinV1Path))
+	case zipkinV2Format:
+		h.zipkin.ServeHTTP(w, withPath(r, zipkinV2Path))
+	case jaegerThriftFormat:
+		h.jaeger.ServeHTTP(w, withPath(r, jaegerTracePath))
+	default:
+		http.Error(w, "unknown payload format", http.StatusUnsupportedMediaType)
+	}
+}
+
+// detectFormat returns the format of the payload of the request. The paths
+// of the Zipkin and Jaeger APIs identify it, the content type and the
+// beginning of the body of the requests sent to other paths are inspected.
+func detectFormat(r *http.Request) payloadFormat {
+	switch path := r.URL.Path; {
+	case strings.HasSuffix(path, zipkinV1Path):
+		return zipkinV1Format
+	case strings.HasSuffix(path, zipkinV2Path):
+		return zipkinV2Format
+	case strings.HasSuffix(path, jaegerTracePath):
+		return jaegerThriftFormat
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "application/x-thrift", "application/vnd.apache.thrift.binary":
+		// Zipkin v1 sends lists of spans, Jaeger a batch.
+		if isThriftList(sniff(r)) {
+			return zipkinV1Format
+		}
+		return jaegerThriftFormat
+	case "application/json", "":
+		return sniffJSONVersion(sniff(r))
+	case "application/x-protobuf":
+		// Only Zipkin v2 has a protobuf encoding.
+		return zipkinV2Format
+	}
+	return unknownFormat
+}
+
+// sniff returns the beginning of the body of the request, without consuming
+// it. Nothing is returned if the body is compressed.
+func sniff(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+	if enc := r.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		return nil
+	}
+	br := bufio.NewReaderSize(r.Body, sniffLen)
+	r.Body = &readCloser{Reader: br, Closer: r.Body}
+	b, _ := br.Peek(sniffLen)
+	return b
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// isThriftList returns whether b starts like a thrift binary list of
+// structs, i.e. with the type of the elements followed by their 32 bits
+// count. A Jaeger batch starts with the header of its "process" struct field,
+// i.e. with the field type followed by its 16 bits ID 1.
+func isThriftList(b []byte) bool {
+	const thriftStructType = 12
+	return len(b) >= 5 && b[0] == thriftStructType && b[1] == 0 && b[2] == 0
+}
+
+var (
+	zipkinV1JSONKeys = [][]byte{[]byte(`"binaryAnnotations"`), []byte(`"endpoint"`)}
+	zipkinV2JSONKeys = [][]byte{
+		[]byte(`"localEndpoint"`), []byte(`"remoteEndpoint"`), []byte(`"kind"`),
+		[]byte(`"tags"`), []byte(`"shared"`),
+	}
+)
+
+// sniffJSONVersion returns the version of the Zipkin JSON spans from the
+// first key specific to a version found in b, v2 if there is none.
+func sniffJSONVersion(b []byte) payloadFormat {
+	v1 := firstIndex(b, zipkinV1JSONKeys)
+	v2 := firstIndex(b, zipkinV2JSONKeys)
+	if v1 >= 0 && (v2 < 0 || v1 < v2) {
+		return zipkinV1Format
+	}
+	return zipkinV2Format
+}
+
+// firstIndex returns the index of the first of the keys found in b, -1 if
+// none is.
+func firstIndex(b []byte, keys [][]byte) int {
+	first := -1
+	for _, key := range keys {
+		if i := bytes.Index(b, key); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	return first
+}
+
+// withPath returns a shallow copy of the request sent to the given path.
+func withPath(r *http.Request, path string) *http.Request {
+	if r.URL.Path == path {
+		return r
+	}
+	r2 := new(http.Request)
+	*r2 = *r
+	u := *r.URL
+	u.Path = path
+	u.RawPath = ""
+	r2.URL = &u
+	return r2
+}
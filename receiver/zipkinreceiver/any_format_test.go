@@ -0,0 +1,184 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		encoding    string
+		body        string
+		want        payloadFormat
+	}{
+		{name: "zipkin v1 path", path: "/api/v1/spans", want: zipkinV1Format},
+		{name: "zipkin v2 path", path: "/api/v2/spans", want: zipkinV2Format},
+		{name: "jaeger path", path: "/api/traces", contentType: "application/x-thrift", want: jaegerThriftFormat},
+		{name: "prefixed path", path: "/legacy/api/v2/spans", want: zipkinV2Format},
+		{
+			name:        "zipkin v1 json",
+			path:        "/",
+			contentType: "application/json; charset=utf-8",
+			body:        `[{"traceId":"1","id":"2","annotations":[{"timestamp":1,"value":"cs","endpoint":{"serviceName":"a"}}]}]`,
+			want:        zipkinV1Format,
+		},
+		{
+			name:        "zipkin v2 json",
+			path:        "/",
+			contentType: "application/json",
+			body:        `[{"traceId":"1","id":"2","kind":"CLIENT","localEndpoint":{"serviceName":"a"}}]`,
+			want:        zipkinV2Format,
+		},
+		{name: "json without keys", path: "/", contentType: "application/json", body: `[]`, want: zipkinV2Format},
+		{
+			name:        "compressed json",
+			path:        "/",
+			contentType: "application/json",
+			encoding:    "gzip",
+			body:        `"binaryAnnotations"`,
+			want:        zipkinV2Format,
+		},
+		{name: "zipkin v1 thrift", path: "/", contentType: "application/x-thrift", body: "\x0c\x00\x00\x00\x01", want: zipkinV1Format},
+		{name: "jaeger thrift", path: "/", contentType: "application/vnd.apache.thrift.binary", body: "\x0c\x00\x01\x0b\x00", want: jaegerThriftFormat},
+		{name: "zipkin v2 protobuf", path: "/", contentType: "application/x-protobuf", want: zipkinV2Format},
+		{name: "unknown", path: "/", contentType: "text/plain", want: unknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			assert.Equal(t, tt.want, detectFormat(req))
+
+			// The body is left untouched.
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestAnyFormat(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	addr := testutils.GetAvailableLocalAddress(t)
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.Format = FormatAny
+	tr, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, tr.StartTraceReception(receivertest.NewMockHost()))
+	defer tr.StopTraceReception()
+
+	zipkinBlob, err := ioutil.ReadFile("./testdata/sample1.json")
+	require.NoError(t, err)
+	jaegerBlob := serializeJaegerBatch(t, &jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: "jaeger-client"},
+		Spans: []*jaeger.Span{{
+			TraceIdLow:    1,
+			SpanId:        2,
+			OperationName: "op",
+			StartTime:     1,
+			Duration:      1,
+		}},
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+		wantStatus  int
+		wantFormat  string
+	}{
+		{name: "zipkin", path: "/api/v2/spans", contentType: "application/json", body: zipkinBlob, wantStatus: http.StatusAccepted, wantFormat: "zipkin"},
+		{name: "zipkin sniffed", path: "/", contentType: "application/json", body: zipkinBlob, wantStatus: http.StatusAccepted, wantFormat: "zipkin"},
+		{name: "jaeger", path: "/api/traces", contentType: "application/x-thrift", body: jaegerBlob, wantStatus: http.StatusAccepted, wantFormat: "jaeger"},
+		{name: "jaeger sniffed", path: "/", contentType: "application/x-thrift", body: jaegerBlob, wantStatus: http.StatusAccepted, wantFormat: "jaeger"},
+		{name: "unknown", path: "/", contentType: "text/plain", body: []byte("spans"), wantStatus: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink.Reset()
+			url := fmt.Sprintf("http://%s%s", addr, tt.path)
+			resp, err := http.Post(url, tt.contentType, bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			traces := sink.AllTraces()
+			if tt.wantFormat == "" {
+				assert.Empty(t, traces)
+				return
+			}
+			require.NotEmpty(t, traces)
+			assert.Equal(t, tt.wantFormat, traces[0].SourceFormat)
+		})
+	}
+}
+
+func TestAnyFormatOverloaded(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.Format = FormatAny
+	tr, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, overloadedConsumer{})
+	require.NoError(t, err)
+	require.NoError(t, tr.StartTraceReception(receivertest.NewMockHost()))
+	defer tr.StopTraceReception()
+
+	// The Jaeger payloads are answered like the Zipkin ones.
+	jaegerBlob := serializeJaegerBatch(t, &jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: "jaeger-client"},
+		Spans:   []*jaeger.Span{{TraceIdLow: 1, SpanId: 2, OperationName: "op"}},
+	})
+	resp, err := http.Post(fmt.Sprintf("http://%s/api/traces", addr), "application/x-thrift", bytes.NewReader(jaegerBlob))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+}
+
+func serializeJaegerBatch(t *testing.T, batch *jaeger.Batch) []byte {
+	buf := thrift.NewTMemoryBuffer()
+	require.NoError(t, batch.Write(thrift.NewTBinaryProtocolTransport(buf)))
+	return buf.Bytes()
+}
@@ -14,9 +14,36 @@
 
 package zipkinreceiver
 
-import "github.com/open-telemetry/opentelemetry-service/config/configmodels"
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
+	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
+)
 
 // Config defines configuration for Zipkin receiver.
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// TLSCredentials configures TLS on the HTTP server.
+	TLSCredentials *configtls.TLSServerSetting `mapstructure:"tls-credentials,omitempty"`
+
+	// Authentication rejects the requests without valid credentials.
+	Authentication *configauth.Settings `mapstructure:"authentication,omitempty"`
+
+	// Limits protect the receiver from clients sending more data than it can handle.
+	Limits *configlimit.Settings `mapstructure:"limits,omitempty"`
+
+	// Status configures how the "error" and HTTP status tags of the spans map
+	// to their status.
+	Status zipkintranslator.StatusOptions `mapstructure:"status"`
+
+	// Format is the format of the accepted payloads: "zipkin" (default), or
+	// "any" to also accept the Jaeger thrift payloads on the same endpoint.
+	Format string `mapstructure:"format,omitempty"`
+
+	// IDs normalizes the trace and span IDs of the received spans.
+	IDs *tracetranslator.IDNormalization `mapstructure:"ids,omitempty"`
 }
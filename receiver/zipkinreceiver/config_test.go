@@ -22,7 +22,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
+	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -38,7 +43,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 8)
 
 	r0 := cfg.Receivers["zipkin"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -51,5 +56,95 @@ func TestLoadConfig(t *testing.T) {
 				NameVal:  "zipkin/customname",
 				Endpoint: "127.0.0.1:8765",
 			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+		})
+
+	r2 := cfg.Receivers["zipkin/tls"].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/tls",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+			TLSCredentials: &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CertFile: "test.crt",
+					KeyFile:  "test.key",
+				},
+			},
+		})
+
+	r3 := cfg.Receivers["zipkin/authentication"].(*Config)
+	assert.Equal(t, r3,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/authentication",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+			Authentication: &configauth.Settings{
+				BearerTokens: []string{"token"},
+			},
+		})
+
+	r4 := cfg.Receivers["zipkin/limits"].(*Config)
+	assert.Equal(t, r4,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/limits",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+			Limits: &configlimit.Settings{
+				MaxConnections:       100,
+				MaxRequestsPerSecond: 1000,
+				MaxBatchSize:         10000,
+			},
+		})
+
+	r5 := cfg.Receivers["zipkin/status"].(*Config)
+	assert.Equal(t, r5,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/status",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.StatusOptions{
+				ErrorTag:  true,
+				StripTags: true,
+			},
+		})
+
+	r6 := cfg.Receivers["zipkin/ids"].(*Config)
+	assert.Equal(t, r6,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/ids",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+			IDs: &tracetranslator.IDNormalization{
+				TraceIDPadding:  tracetranslator.TraceIDPaddingHash,
+				DropInvalid:     true,
+				RemapCollisions: true,
+			},
+		})
+
+	r7 := cfg.Receivers["zipkin/any"].(*Config)
+	assert.Equal(t, r7,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal:  typeStr,
+				NameVal:  "zipkin/any",
+				Endpoint: defaultBindEndpoint,
+			},
+			Status: zipkintranslator.DefaultStatusOptions(),
+			Format: FormatAny,
 		})
 }
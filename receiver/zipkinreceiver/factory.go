@@ -16,13 +16,16 @@ package zipkinreceiver
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
 )
 
 // This file implements factory for Zipkin receiver.
@@ -56,6 +59,7 @@ func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
 			NameVal:  typeStr,
 			Endpoint: defaultBindEndpoint,
 		},
+		Status: zipkintranslator.DefaultStatusOptions(),
 	}
 }
 
@@ -68,7 +72,48 @@ func (f *Factory) CreateTraceReceiver(
 ) (receiver.TraceReceiver, error) {
 
 	rCfg := cfg.(*Config)
-	return New(rCfg.Endpoint, nextConsumer)
+	zr, err := New(rCfg.Endpoint, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	zr.statusOptions = rCfg.Status
+	if rCfg.IDs != nil {
+		if err := rCfg.IDs.Validate(); err != nil {
+			return nil, fmt.Errorf("error initializing Zipkin receiver %q: %v", rCfg.Name(), err)
+		}
+		zr.idNormalization = rCfg.IDs
+	}
+	zr.socketPermissions, err = confignet.ParsePermissions(rCfg.SocketPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Zipkin receiver %q: %v", rCfg.Name(), err)
+	}
+	if rCfg.TLSCredentials != nil {
+		zr.tlsConfig, err = rCfg.TLSCredentials.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Zipkin receiver %q TLS Credentials: %v", rCfg.Name(), err)
+		}
+	}
+	if rCfg.Authentication != nil {
+		zr.authenticator, err = rCfg.Authentication.NewAuthenticator()
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Zipkin receiver %q authentication: %v", rCfg.Name(), err)
+		}
+	}
+	if rCfg.Limits != nil {
+		zr.limiter, err = rCfg.Limits.NewLimiter(rCfg.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error initializing Zipkin receiver %q limits: %v", rCfg.Name(), err)
+		}
+	}
+	switch rCfg.Format {
+	case "", FormatZipkin:
+	case FormatAny:
+		// Created last, it shares the limiter and the ID normalization.
+		zr.anyFormat = newAnyFormatHandler(zr, nextConsumer)
+	default:
+		return nil, fmt.Errorf("error initializing Zipkin receiver %q: unknown format %q", rCfg.Name(), rCfg.Format)
+	}
+	return zr, nil
 }
 
 // CreateMetricsReceiver creates a metrics receiver based on provided config.
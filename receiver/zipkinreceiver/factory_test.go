@@ -22,8 +22,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/configtls"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -55,3 +59,55 @@ func TestCreateReceiver(t *testing.T) {
 	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
 	assert.Nil(t, mReceiver)
 }
+
+func TestCreateReceiverTLSError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.TLSCredentials = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: "nosuchfile.crt", KeyFile: "nosuchfile.key"},
+	}
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, &mockTraceConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+}
+
+func TestCreateReceiverLimitsError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Limits = &configlimit.Settings{MaxBatchSize: -1}
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, &mockTraceConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+}
+
+func TestCreateReceiverAuthenticationError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Authentication = &configauth.Settings{APIKeys: []string{"key"}}
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, &mockTraceConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+}
+
+func TestCreateReceiverIDsError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.IDs = &tracetranslator.IDNormalization{TraceIDPadding: "unknown"}
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, &mockTraceConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+}
+
+func TestCreateReceiverFormatError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Format = "otlp"
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, &mockTraceConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+}
@@ -0,0 +1,91 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
+	zipkinproto "github.com/openzipkin/zipkin-go/proto/v2"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
+)
+
+var errUnknownMessageEncoding = errors.New("unknown encoding of the Zipkin message")
+
+// The first byte of the encodings of a list of spans.
+const (
+	jsonListStart = '['
+	// protoListStart is the tag of the repeated spans field of ListOfSpans.
+	protoListStart = 0x0a
+	// thriftListStart is the element type, struct, of a Thrift list.
+	thriftListStart = 0x0c
+)
+
+// MessageToTraceData converts a message with a list of Zipkin spans, e.g.
+// consumed from the Kafka topic of a Zipkin deployment, to OpenCensus Proto
+// spans. As done by the Zipkin collectors the encoding is detected from the
+// message: a JSON list of v1 or v2 spans, a proto3 ListOfSpans or a Thrift
+// list of v1 spans.
+func MessageToTraceData(msg []byte) ([]consumerdata.TraceData, error) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 {
+		return nil, nil
+	}
+
+	switch msg[0] {
+	case jsonListStart:
+		if isZipkinV1JSON(msg) {
+			return zipkintranslator.V1JSONBatchToOCProto(msg)
+		}
+		var zipkinSpans []*zipkinmodel.SpanModel
+		if err := json.Unmarshal(msg, &zipkinSpans); err != nil {
+			return nil, err
+		}
+		return zipkinSpansToTraceData(zipkinSpans, zipkintranslator.DefaultStatusOptions(), nil), nil
+
+	case protoListStart:
+		zipkinSpans, err := zipkinproto.ParseSpans(msg, false)
+		if err != nil {
+			return nil, err
+		}
+		return zipkinSpansToTraceData(zipkinSpans, zipkintranslator.DefaultStatusOptions(), nil), nil
+
+	case thriftListStart:
+		zSpans, err := deserializeThrift(msg)
+		if err != nil {
+			return nil, err
+		}
+		return zipkintranslator.V1ThriftBatchToOCProto(zSpans)
+	}
+
+	return nil, errUnknownMessageEncoding
+}
+
+// isZipkinV1JSON returns whether the JSON list of spans has v1 spans, using
+// the same heuristic as the Zipkin collectors: only v1 spans have binary
+// annotations or endpoints in their annotations, only v2 spans have local and
+// remote endpoints.
+func isZipkinV1JSON(msg []byte) bool {
+	if bytes.Contains(msg, []byte(`"binaryAnnotations"`)) {
+		return true
+	}
+	return !bytes.Contains(msg, []byte(`"localEndpoint"`)) &&
+		!bytes.Contains(msg, []byte(`"remoteEndpoint"`)) &&
+		bytes.Contains(msg, []byte(`"endpoint"`))
+}
@@ -0,0 +1,125 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinreceiver
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/golang/protobuf/proto"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
+	zipkin_proto3 "github.com/openzipkin/zipkin-go/proto/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+func TestMessageToTraceData_JSONV2(t *testing.T) {
+	msg, err := ioutil.ReadFile("./testdata/sample1.json")
+	require.NoError(t, err)
+
+	tds, err := MessageToTraceData(msg)
+	require.NoError(t, err)
+	require.Len(t, tds, 1)
+	assert.Equal(t, 9, len(tds[0].Spans))
+	assert.Equal(t, "frontend", tds[0].Node.ServiceInfo.Name)
+}
+
+func TestMessageToTraceData_JSONV1(t *testing.T) {
+	msg, err := ioutil.ReadFile("../../translator/trace/zipkin/testdata/zipkin_v1_single_batch.json")
+	require.NoError(t, err)
+
+	tds, err := MessageToTraceData(msg)
+	require.NoError(t, err)
+	assert.Equal(t, 5, countSpans(tds))
+}
+
+func TestMessageToTraceData_Proto(t *testing.T) {
+	msg, err := proto.Marshal(&zipkin_proto3.ListOfSpans{
+		Spans: []*zipkin_proto3.Span{
+			{
+				TraceId:       []byte{0x7F, 0x6F, 0x5F, 0x4F, 0x3F, 0x2F, 0x1F, 0x0F, 0xF7, 0xF6, 0xF5, 0xF4, 0xF3, 0xF2, 0xF1, 0xF0},
+				Id:            []byte{0xF7, 0xF6, 0xF5, 0xF4, 0xF3, 0xF2, 0xF1, 0xF0},
+				Name:          "ProtoSpan1",
+				LocalEndpoint: &zipkin_proto3.Endpoint{ServiceName: "svc-1"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	tds, err := MessageToTraceData(msg)
+	require.NoError(t, err)
+	require.Len(t, tds, 1)
+	assert.Equal(t, "ProtoSpan1", tds[0].Spans[0].Name.Value)
+	assert.Equal(t, "svc-1", tds[0].Node.ServiceInfo.Name)
+}
+
+func TestMessageToTraceData_Thrift(t *testing.T) {
+	buf := thrift.NewTMemoryBuffer()
+	protocol := thrift.NewTBinaryProtocolTransport(buf)
+	spans := []*zipkincore.Span{
+		{
+			TraceID: 1,
+			ID:      2,
+			Name:    "ThriftSpan",
+			Annotations: []*zipkincore.Annotation{
+				{
+					Timestamp: 1485467191639875,
+					Value:     "cs",
+					Host:      &zipkincore.Endpoint{ServiceName: "svc-1"},
+				},
+			},
+		},
+	}
+	require.NoError(t, protocol.WriteListBegin(thrift.STRUCT, len(spans)))
+	for _, span := range spans {
+		require.NoError(t, span.Write(protocol))
+	}
+	require.NoError(t, protocol.WriteListEnd())
+
+	tds, err := MessageToTraceData(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 1, countSpans(tds))
+	assert.Equal(t, "ThriftSpan", tds[0].Spans[0].Name.Value)
+}
+
+func TestMessageToTraceData_Invalid(t *testing.T) {
+	tds, err := MessageToTraceData([]byte(" "))
+	assert.NoError(t, err)
+	assert.Nil(t, tds)
+
+	_, err = MessageToTraceData([]byte("not spans"))
+	assert.Equal(t, errUnknownMessageEncoding, err)
+
+	_, err = MessageToTraceData([]byte("[{"))
+	assert.Error(t, err)
+}
+
+func TestIsZipkinV1JSON(t *testing.T) {
+	assert.True(t, isZipkinV1JSON([]byte(`[{"binaryAnnotations":[]}]`)))
+	assert.True(t, isZipkinV1JSON([]byte(`[{"annotations":[{"endpoint":{}}]}]`)))
+	assert.False(t, isZipkinV1JSON([]byte(`[{"localEndpoint":{},"annotations":[{"value":"foo"}]}]`)))
+	assert.False(t, isZipkinV1JSON([]byte(`[{"name":"span"}]`)))
+}
+
+func countSpans(tds []consumerdata.TraceData) int {
+	count := 0
+	for _, td := range tds {
+		count += len(td.Spans)
+	}
+	return count
+}
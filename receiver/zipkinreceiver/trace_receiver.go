@@ -17,6 +17,7 @@ package zipkinreceiver
 import (
 	"compress/gzip"
 	"compress/zlib"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,11 +38,18 @@ import (
 	zipkinproto "github.com/openzipkin/zipkin-go/proto/v2"
 	"go.opencensus.io/trace"
 
+	"github.com/open-telemetry/opentelemetry-service/client"
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/internal"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/translator/propagation"
 	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
 )
@@ -61,6 +70,29 @@ type ZipkinReceiver struct {
 	host         receiver.Host
 	nextConsumer consumer.TraceConsumer
 
+	// tlsConfig enables TLS on the HTTP server if not nil.
+	tlsConfig *tls.Config
+
+	// authenticator rejects the unauthenticated requests if not nil.
+	authenticator configauth.Authenticator
+
+	// limiter enforces the limits on the connections and requests if not nil.
+	limiter *configlimit.Limiter
+
+	// socketPermissions are the permissions of the Unix domain socket if the
+	// address is like "unix:///path/to/socket", 0 keeps the umask ones.
+	socketPermissions os.FileMode
+
+	// statusOptions configure how the tags of the spans map to their status.
+	statusOptions zipkintranslator.StatusOptions
+
+	// idNormalization normalizes the trace and span IDs if not nil.
+	idNormalization *tracetranslator.IDNormalization
+
+	// anyFormat, if not nil, serves the requests instead of the receiver to
+	// also accept the Jaeger payloads.
+	anyFormat *anyFormatHandler
+
 	startOnce sync.Once
 	stopOnce  sync.Once
 	server    *http.Server
@@ -76,8 +108,9 @@ func New(address string, nextConsumer consumer.TraceConsumer) (*ZipkinReceiver,
 	}
 
 	zr := &ZipkinReceiver{
-		addr:         address,
-		nextConsumer: nextConsumer,
+		addr:          address,
+		nextConsumer:  nextConsumer,
+		statusOptions: zipkintranslator.DefaultStatusOptions(),
 	}
 	return zr, nil
 }
@@ -111,14 +144,31 @@ func (zr *ZipkinReceiver) StartTraceReception(host receiver.Host) error {
 	var err = errAlreadyStarted
 
 	zr.startOnce.Do(func() {
-		ln, lerr := net.Listen("tcp", zr.address())
+		ln, lerr := confignet.Listen(zr.address(), zr.socketPermissions)
 		if lerr != nil {
 			err = lerr
 			return
 		}
+		if zr.limiter != nil {
+			ln = zr.limiter.Listener(ln)
+		}
+		if zr.tlsConfig != nil {
+			ln = tls.NewListener(ln, zr.tlsConfig)
+		}
 
 		zr.host = host
-		server := &http.Server{Handler: zr}
+		var handler http.Handler = zr
+		if zr.anyFormat != nil {
+			handler = zr.anyFormat
+		}
+		if zr.authenticator != nil {
+			handler = configauth.HTTPHandler(zr.authenticator, handler)
+		}
+		// The limits are checked first, the authentication can be expensive.
+		if zr.limiter != nil {
+			handler = configlimit.HTTPHandler(zr.limiter, handler)
+		}
+		server := &http.Server{Handler: handler}
 		zr.server = server
 		go func() {
 			host.ReportFatalError(server.Serve(ln))
@@ -138,9 +188,9 @@ func (zr *ZipkinReceiver) v1ToTraceSpans(blob []byte, hdr http.Header) (reqs []c
 			return nil, err
 		}
 
-		return zipkintranslator.V1ThriftBatchToOCProto(zSpans)
+		return zipkintranslator.V1ThriftBatchToOCProtoWithOptions(zSpans, zr.statusOptions)
 	}
-	return zipkintranslator.V1JSONBatchToOCProto(blob)
+	return zipkintranslator.V1JSONBatchToOCProtoWithOptions(blob, zr.statusOptions)
 }
 
 // deserializeThrift decodes Thrift bytes to a list of spans.
@@ -175,7 +225,8 @@ func deserializeThrift(b []byte) ([]*zipkincore.Span, error) {
 func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs []consumerdata.TraceData, err error) {
 	// This flag's reference is from:
 	//      https://github.com/openzipkin/zipkin-go/blob/3793c981d4f621c0e3eb1457acffa2c1cc591384/proto/v2/zipkin.proto#L154
-	debugWasSet := hdr.Get("X-B3-Flags") == "1"
+	// It can also be set with the "d" sampling state of the b3 header.
+	debugWasSet := propagation.IsB3Debug(hdr)
 
 	var zipkinSpans []*zipkinmodel.SpanModel
 
@@ -193,15 +244,31 @@ func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs []c
 		return nil, err
 	}
 
+	return zipkinSpansToTraceData(zipkinSpans, zr.statusOptions, zr.idNormalization), nil
+}
+
+// zipkinSpansToTraceData converts Zipkin v2 spans to OpenCensus Proto spans
+// grouped by node. If idNormalization remaps the collisions, the spans shared
+// with their client are remapped, since their client is usually reported in
+// another batch.
+func zipkinSpansToTraceData(
+	zipkinSpans []*zipkinmodel.SpanModel,
+	statusOptions zipkintranslator.StatusOptions,
+	idNormalization *tracetranslator.IDNormalization,
+) (reqs []consumerdata.TraceData) {
+	remapShared := idNormalization != nil && idNormalization.RemapCollisions
 	// *commonpb.Node instances have unique addresses hence
 	// for grouping within a map, we'll use the .String() value
 	byNodeGrouping := make(map[string][]*tracepb.Span)
 	uniqueNodes := make([]*commonpb.Node, 0, len(zipkinSpans))
 	// Now translate them into tracepb.Span
 	for _, zspan := range zipkinSpans {
-		span, node, err := zipkinSpanToTraceSpan(zspan)
+		span, node, err := zipkinSpanToTraceSpan(zspan, statusOptions)
 		// TODO:(@odeke-em) record errors
 		if err == nil && span != nil {
+			if remapShared && zspan.Shared {
+				tracetranslator.RemapSpanID(span)
+			}
 			key := node.String()
 			if _, alreadyAdded := byNodeGrouping[key]; !alreadyAdded {
 				uniqueNodes = append(uniqueNodes, node)
@@ -225,7 +292,7 @@ func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs []c
 		delete(byNodeGrouping, key)
 	}
 
-	return reqs, nil
+	return reqs
 }
 
 func (zr *ZipkinReceiver) deserializeFromJSON(jsonBlob []byte, debugWasSet bool) (zs []*zipkinmodel.SpanModel, err error) {
@@ -310,12 +377,29 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	ctxWithReceiverName := observability.ContextWithReceiverName(ctx, receiverTagValue)
 
+	limitCtx := obsreport.WithTransport(ctx, "http")
 	pr := processBodyIfNecessary(r)
-	slurp, _ := ioutil.ReadAll(pr)
+	var slurp []byte
+	var readErr error
+	if zr.limiter != nil {
+		// The decompressed body is limited too, the compressed one was
+		// limited by the HTTP handler.
+		slurp, readErr = zr.limiter.ReadBody(limitCtx, pr)
+	} else {
+		slurp, _ = ioutil.ReadAll(pr)
+	}
 	if c, ok := pr.(io.Closer); ok {
 		_ = c.Close()
 	}
 	_ = r.Body.Close()
+	if readErr == configlimit.ErrRequestTooLarge {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeResourceExhausted,
+			Message: readErr.Error(),
+		})
+		http.Error(w, readErr.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
 
 	var tds []consumerdata.TraceData
 	var err error
@@ -336,13 +420,57 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	tdsSize := 0
 	for _, td := range tds {
-		td.SourceFormat = "zipkin"
-		zr.nextConsumer.ConsumeTraceData(ctxWithReceiverName, td)
 		tdsSize += len(td.Spans)
 	}
+	if zr.limiter != nil {
+		spans := make([]*tracepb.Span, 0, tdsSize)
+		for _, td := range tds {
+			spans = append(spans, td.Spans...)
+		}
+		if err := zr.limiter.CheckSpans(limitCtx, spans); err != nil {
+			code := trace.StatusCodeResourceExhausted
+			if err == configlimit.ErrAttributeTooLong {
+				code = trace.StatusCodeInvalidArgument
+			}
+			span.SetStatus(trace.Status{Code: code, Message: err.Error()})
+			http.Error(w, err.Error(), configlimit.HTTPStatus(err))
+			return
+		}
+	}
+
+	// Pass the client of the request to the processors and exporters that
+	// depend on it.
+	consumerCtx := ctxWithReceiverName
+	if c, ok := client.FromHTTP(r); ok {
+		consumerCtx = client.NewContext(consumerCtx, c)
+	}
+	consumerCtx = obsreport.WithTransport(consumerCtx, "http")
+	var consumerErr error
+	droppedSpans := 0
+	for _, td := range tds {
+		spanCount := len(td.Spans)
+		td.Spans = tracetranslator.NormalizeIDs(zr.idNormalization, td.Spans)
+		droppedSpans += spanCount - len(td.Spans)
+		if len(td.Spans) == 0 {
+			continue
+		}
+		td.SourceFormat = "zipkin"
+		if err := zr.nextConsumer.ConsumeTraceData(consumerCtx, td); err != nil {
+			consumerErr = err
+		}
+	}
 
 	// TODO: Get the number of dropped spans from the conversion failure.
-	observability.RecordTraceReceiverMetrics(ctxWithReceiverName, tdsSize, 0)
+	observability.RecordTraceReceiverMetrics(ctxWithReceiverName, tdsSize, droppedSpans)
+
+	// Ask the clients to slow down if the pipeline is overloaded.
+	if consumererror.WriteHTTPOverloaded(w, consumerErr) {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeResourceExhausted,
+			Message: consumerErr.Error(),
+		})
+		return
+	}
 
 	// Finally send back the response "Accepted" as
 	// required at https://zipkin.io/zipkin-api/#/default/post_spans
@@ -369,7 +497,10 @@ func zSpanIDToOCProtoSpanID(id zipkinmodel.ID) ([]byte, error) {
 	return tracetranslator.UInt64ToByteSpanID(uint64(id)), nil
 }
 
-func zipkinSpanToTraceSpan(zs *zipkinmodel.SpanModel) (*tracepb.Span, *commonpb.Node, error) {
+func zipkinSpanToTraceSpan(
+	zs *zipkinmodel.SpanModel,
+	statusOptions zipkintranslator.StatusOptions,
+) (*tracepb.Span, *commonpb.Node, error) {
 	if zs == nil {
 		return nil, nil, errNilZipkinSpan
 	}
@@ -391,6 +522,12 @@ func zipkinSpanToTraceSpan(zs *zipkinmodel.SpanModel) (*tracepb.Span, *commonpb.
 		}
 	}
 
+	attributes := zipkinTagsToTraceAttributes(zs.Tags)
+	status := zipkintranslator.StatusFromAttributes(attributes, statusOptions)
+	if attributes != nil && len(attributes.AttributeMap) == 0 {
+		attributes = nil
+	}
+
 	pbs := &tracepb.Span{
 		TraceId:      traceID,
 		SpanId:       spanID,
@@ -399,8 +536,8 @@ func zipkinSpanToTraceSpan(zs *zipkinmodel.SpanModel) (*tracepb.Span, *commonpb.
 		StartTime:    internal.TimeToTimestamp(zs.Timestamp),
 		EndTime:      internal.TimeToTimestamp(zs.Timestamp.Add(zs.Duration)),
 		Kind:         zipkinSpanKindToProtoSpanKind(zs.Kind),
-		Status:       extractProtoStatus(zs),
-		Attributes:   zipkinTagsToTraceAttributes(zs.Tags),
+		Status:       status,
+		Attributes:   attributes,
 		TimeEvents:   zipkinAnnotationsToProtoTimeEvents(zs.Annotations),
 	}
 
@@ -473,51 +610,6 @@ func zipkinEndpointIntoAttributes(ep *zipkinmodel.Endpoint, into map[string]stri
 	return into
 }
 
-const statusCodeUnknown = 2
-
-func extractProtoStatus(zs *zipkinmodel.SpanModel) *tracepb.Status {
-	// The status is stored with the "error" key
-	// See https://github.com/census-instrumentation/opencensus-go/blob/1eb9a13c7dd02141e065a665f6bf5c99a090a16a/exporter/zipkin/zipkin.go#L160-L165
-	if zs == nil || len(zs.Tags) == 0 {
-		return nil
-	}
-	canonicalCodeStr := zs.Tags["error"]
-	message := zs.Tags["opencensus.status_description"]
-	if message == "" && canonicalCodeStr == "" {
-		return nil
-	}
-	code, set := canonicalCodesMap[canonicalCodeStr]
-	if !set {
-		// If not status code was set, then we should use UNKNOWN
-		code = statusCodeUnknown
-	}
-	return &tracepb.Status{
-		Message: message,
-		Code:    code,
-	}
-}
-
-var canonicalCodesMap = map[string]int32{
-	// https://github.com/googleapis/googleapis/blob/bee79fbe03254a35db125dc6d2f1e9b752b390fe/google/rpc/code.proto#L33-L186
-	"OK":                  0,
-	"CANCELLED":           1,
-	"UNKNOWN":             2,
-	"INVALID_ARGUMENT":    3,
-	"DEADLINE_EXCEEDED":   4,
-	"NOT_FOUND":           5,
-	"ALREADY_EXISTS":      6,
-	"PERMISSION_DENIED":   7,
-	"RESOURCE_EXHAUSTED":  8,
-	"FAILED_PRECONDITION": 9,
-	"ABORTED":             10,
-	"OUT_OF_RANGE":        11,
-	"UNIMPLEMENTED":       12,
-	"INTERNAL":            13,
-	"UNAVAILABLE":         14,
-	"DATA_LOSS":           15,
-	"UNAUTHENTICATED":     16,
-}
-
 func zipkinSpanKindToProtoSpanKind(skind zipkinmodel.Kind) tracepb.Span_SpanKind {
 	switch strings.ToUpper(string(skind)) {
 	case "CLIENT":
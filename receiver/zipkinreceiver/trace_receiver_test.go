@@ -16,13 +16,18 @@ package zipkinreceiver
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,14 +39,21 @@ import (
 	zhttp "github.com/openzipkin/zipkin-go/reporter/http"
 	"github.com/stretchr/testify/require"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configauth"
+	"github.com/open-telemetry/opentelemetry-service/config/configlimit"
+	"github.com/open-telemetry/opentelemetry-service/config/confignet"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumererror"
 	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
 	"github.com/open-telemetry/opentelemetry-service/internal"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/conformance"
 	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
+	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 	spandatatranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/spandata"
+	zipkintranslator "github.com/open-telemetry/opentelemetry-service/translator/trace/zipkin"
 )
 
 func TestTraceIDConversion(t *testing.T) {
@@ -99,7 +111,7 @@ func TestShortIDSpanConversion(t *testing.T) {
 		SpanContext: zc,
 	}
 
-	ocSpan, _, err := zipkinSpanToTraceSpan(&zs)
+	ocSpan, _, err := zipkinSpanToTraceSpan(&zs, zipkintranslator.DefaultStatusOptions())
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
 	}
@@ -113,6 +125,67 @@ func TestShortIDSpanConversion(t *testing.T) {
 	}
 }
 
+func TestSpanStatusConversion(t *testing.T) {
+	zs := zipkinmodel.SpanModel{
+		SpanContext: zipkinmodel.SpanContext{
+			TraceID: zipkinmodel.TraceID{Low: 1},
+			ID:      zipkinmodel.ID(1),
+		},
+		Tags: map[string]string{
+			"error":            "true",
+			"http.status_code": "503",
+		},
+	}
+
+	ocSpan, _, err := zipkinSpanToTraceSpan(&zs, zipkintranslator.DefaultStatusOptions())
+	require.NoError(t, err)
+	require.Equal(t, &tracepb.Status{Code: 2}, ocSpan.Status)
+	require.Len(t, ocSpan.Attributes.AttributeMap, 2)
+
+	ocSpan, _, err = zipkinSpanToTraceSpan(&zs, zipkintranslator.StatusOptions{
+		HTTPStatusCode: true,
+		StripTags:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &tracepb.Status{Code: 14}, ocSpan.Status)
+	require.Len(t, ocSpan.Attributes.AttributeMap, 1)
+	require.Contains(t, ocSpan.Attributes.AttributeMap, "error")
+
+	ocSpan, _, err = zipkinSpanToTraceSpan(&zs, zipkintranslator.StatusOptions{
+		ErrorTag:       true,
+		HTTPStatusCode: true,
+		StripTags:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &tracepb.Status{Code: 2}, ocSpan.Status)
+	require.Nil(t, ocSpan.Attributes)
+}
+
+func TestSharedSpanRemapping(t *testing.T) {
+	parentID := zipkinmodel.ID(2)
+	zs := &zipkinmodel.SpanModel{
+		SpanContext: zipkinmodel.SpanContext{
+			TraceID:  zipkinmodel.TraceID{Low: 1},
+			ID:       zipkinmodel.ID(1),
+			ParentID: &parentID,
+		},
+		Kind:   zipkinmodel.Server,
+		Shared: true,
+	}
+	spanID := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+	tds := zipkinSpansToTraceData([]*zipkinmodel.SpanModel{zs}, zipkintranslator.DefaultStatusOptions(), nil)
+	require.Len(t, tds, 1)
+	require.Equal(t, spanID, tds[0].Spans[0].SpanId)
+
+	ids := &tracetranslator.IDNormalization{RemapCollisions: true}
+	tds = zipkinSpansToTraceData([]*zipkinmodel.SpanModel{zs}, zipkintranslator.DefaultStatusOptions(), ids)
+	require.Len(t, tds, 1)
+	ocSpan := tds[0].Spans[0]
+	require.Equal(t, spanID, ocSpan.ParentSpanId)
+	require.Equal(t, tracetranslator.RemappedSpanID(ocSpan.TraceId, spanID, tracepb.Span_SERVER), ocSpan.SpanId)
+}
+
 func TestNew(t *testing.T) {
 	type args struct {
 		address      string
@@ -555,3 +628,161 @@ func TestStartTraceReception(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthentication(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	addr := testutils.GetAvailableLocalAddress(t)
+	zr, err := New(addr, sink)
+	require.NoError(t, err)
+	zr.authenticator, err = configauth.Settings{BearerTokens: []string{"token"}}.NewAuthenticator()
+	require.NoError(t, err)
+
+	require.NoError(t, zr.StartTraceReception(receivertest.NewMockHost()))
+	defer zr.StopTraceReception()
+
+	blob, err := ioutil.ReadFile("./testdata/sample1.json")
+	require.NoError(t, err)
+	url := fmt.Sprintf("http://%s/api/v2/spans", addr)
+	tests := []struct {
+		token      string
+		wantStatus int
+	}{
+		{wantStatus: http.StatusUnauthorized},
+		{token: "other", wantStatus: http.StatusUnauthorized},
+		{token: "token", wantStatus: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(blob))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, tt.wantStatus, resp.StatusCode, tt.token)
+		require.Equal(t, tt.wantStatus == http.StatusAccepted, len(sink.AllTraces()) > 0, tt.token)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	addr := testutils.GetAvailableLocalAddress(t)
+	zr, err := New(addr, sink)
+	require.NoError(t, err)
+	zr.limiter, err = configlimit.Settings{
+		MaxRequestsPerSecond: 4,
+		MaxBatchSize:         10,
+		MaxRequestSize:       1024,
+		MaxAttributeLength:   8,
+	}.NewLimiter("zipkin")
+	require.NoError(t, err)
+
+	require.NoError(t, zr.StartTraceReception(receivertest.NewMockHost()))
+	defer zr.StopTraceReception()
+
+	post := func(spans string) int {
+		resp, err := http.Post(fmt.Sprintf("http://%s/api/v2/spans", addr), "application/json", bytes.NewBufferString(spans))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	span := `{"traceId":"4d1e00c0db9010db86154a4ba6e91385","id":"4d1e00c0db9010db","name":"get"}`
+	require.Equal(t, http.StatusAccepted, post("["+span+"]"))
+	require.Len(t, sink.AllTraces(), 1)
+
+	// Too many spans.
+	spans := make([]string, 11)
+	for i := range spans {
+		spans[i] = span
+	}
+	require.Equal(t, http.StatusTooManyRequests, post("["+strings.Join(spans, ",")+"]"))
+
+	// Too long tag value.
+	longTag := `{"traceId":"4d1e00c0db9010db86154a4ba6e91385","id":"4d1e00c0db9010db","name":"get","tags":{"key":"too long value"}}`
+	require.Equal(t, http.StatusBadRequest, post("["+longTag+"]"))
+
+	// Too large request.
+	require.Equal(t, http.StatusRequestEntityTooLarge, post("["+span+strings.Repeat(" ", 1024)+"]"))
+
+	// Too many requests.
+	require.Equal(t, http.StatusTooManyRequests, post("["+span+"]"))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+func TestOverloaded(t *testing.T) {
+	addr := testutils.GetAvailableLocalAddress(t)
+	zr, err := New(addr, overloadedConsumer{})
+	require.NoError(t, err)
+	require.NoError(t, zr.StartTraceReception(receivertest.NewMockHost()))
+	defer zr.StopTraceReception()
+
+	span := `[{"traceId":"4d1e00c0db9010db86154a4ba6e91385","id":"4d1e00c0db9010db","name":"get"}]`
+	resp, err := http.Post(fmt.Sprintf("http://%s/api/v2/spans", addr), "application/json", bytes.NewBufferString(span))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("Retry-After"))
+}
+
+type overloadedConsumer struct{}
+
+func (overloadedConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return consumererror.Overloaded(errors.New("queue is full"), 2*time.Second)
+}
+
+func TestConformance(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	addr := testutils.GetAvailableLocalAddress(t)
+	zr, err := New(addr, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, zr.StartTraceReception(receivertest.NewMockHost()))
+	defer zr.StopTraceReception()
+
+	url := fmt.Sprintf("http://%s", addr)
+	conformance.Run(t, conformance.Target{
+		Endpoints: map[conformance.Protocol]string{
+			conformance.ZipkinV1JSON: url,
+			conformance.ZipkinV2JSON: url,
+		},
+		Sink: sink,
+	})
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zipkinreceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "otelsvc.sock")
+
+	sink := new(exportertest.SinkTraceExporter)
+	zr, err := New(confignet.UnixScheme+socket, sink)
+	require.NoError(t, err)
+	zr.socketPermissions = 0600
+
+	require.NoError(t, zr.StartTraceReception(receivertest.NewMockHost()))
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	blob, err := ioutil.ReadFile("./testdata/sample1.json")
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Post("http://localhost/api/v2/spans", "application/json", bytes.NewBuffer(blob))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.NotEmpty(t, sink.AllTraces())
+
+	// The socket is removed on shutdown.
+	require.NoError(t, zr.StopTraceReception())
+	_, err = os.Stat(socket)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,45 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinkafkareceiver
+
+import (
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// Config defines configuration for the Zipkin Kafka receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Brokers are the addresses of the Kafka brokers used to discover the
+	// cluster.
+	Brokers []string `mapstructure:"brokers"`
+
+	// Topic is the topic the spans are consumed from.
+	Topic string `mapstructure:"topic"`
+
+	// GroupID is the consumer group of the receiver, the partitions of the
+	// topic are shared by the receivers of the same group.
+	GroupID string `mapstructure:"group-id"`
+
+	// ClientID identifies the receiver in the logs and metrics of the brokers.
+	ClientID string `mapstructure:"client-id"`
+
+	// ProtocolVersion is the version of Kafka of the brokers, e.g. "2.0.0".
+	ProtocolVersion string `mapstructure:"protocol-version"`
+
+	// InitialOffset is where the group starts consuming the topic when it has
+	// no committed offset, "latest" or "earliest".
+	InitialOffset string `mapstructure:"initial-offset"`
+}
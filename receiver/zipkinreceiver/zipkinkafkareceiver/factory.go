@@ -0,0 +1,133 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinkafkareceiver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// This file implements factory for the Zipkin Kafka receiver.
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "zipkin-kafka"
+
+	defaultBroker          = "localhost:9092"
+	defaultTopic           = "zipkin"
+	defaultGroupID         = "zipkin"
+	defaultClientID        = "otelsvc"
+	defaultProtocolVersion = "2.0.0"
+
+	offsetLatest   = "latest"
+	offsetEarliest = "earliest"
+)
+
+// Factory is the factory for the Zipkin Kafka receiver.
+type Factory struct {
+}
+
+// Type gets the type of the Receiver config created by this factory.
+func (f *Factory) Type() string {
+	return typeStr
+}
+
+// CustomUnmarshaler returns nil because we don't need custom unmarshaling for this config.
+func (f *Factory) CustomUnmarshaler() receiver.CustomUnmarshaler {
+	return nil
+}
+
+// CreateDefaultConfig creates the default configuration for the Zipkin Kafka receiver.
+func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Brokers:         []string{defaultBroker},
+		Topic:           defaultTopic,
+		GroupID:         defaultGroupID,
+		ClientID:        defaultClientID,
+		ProtocolVersion: defaultProtocolVersion,
+		InitialOffset:   offsetLatest,
+	}
+}
+
+// CreateTraceReceiver creates a trace receiver based on provided config.
+func (f *Factory) CreateTraceReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.TraceConsumer,
+) (receiver.TraceReceiver, error) {
+	rCfg := cfg.(*Config)
+	saramaConfig, err := newSaramaConfig(rCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Zipkin Kafka receiver %q: %v", rCfg.Name(), err)
+	}
+	return New(logger, rCfg.Brokers, rCfg.Topic, rCfg.GroupID, saramaConfig, nextConsumer)
+}
+
+// CreateMetricsReceiver creates a metrics receiver based on provided config.
+func (f *Factory) CreateMetricsReceiver(
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	consumer consumer.MetricsConsumer,
+) (receiver.MetricsReceiver, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
+
+// newSaramaConfig returns the configuration of the Kafka client of the
+// receiver.
+func newSaramaConfig(cfg *Config) (*sarama.Config, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("\"brokers\" must not be empty")
+	}
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("\"topic\" must not be empty")
+	}
+	if cfg.GroupID == "" {
+		return nil, fmt.Errorf("\"group-id\" must not be empty")
+	}
+
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = cfg.ClientID
+	version, err := sarama.ParseKafkaVersion(cfg.ProtocolVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"protocol-version\": %v", err)
+	}
+	saramaConfig.Version = version
+	switch cfg.InitialOffset {
+	case offsetLatest:
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	case offsetEarliest:
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	default:
+		return nil, fmt.Errorf("\"initial-offset\" must be %q or %q, got %q", offsetLatest, offsetEarliest, cfg.InitialOffset)
+	}
+	saramaConfig.Consumer.Return.Errors = true
+	if err := saramaConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return saramaConfig, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinkafkareceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateReceiver(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, exportertest.NewNopTraceExporter())
+	assert.NoError(t, err, "receiver creation failed")
+	assert.NotNil(t, tReceiver, "receiver creation failed")
+
+	mReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, nil)
+	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	assert.Nil(t, mReceiver)
+}
+
+func TestNewSaramaConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "1.1.0"
+	cfg.InitialOffset = offsetEarliest
+
+	saramaConfig, err := newSaramaConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, sarama.V1_1_0_0, saramaConfig.Version)
+	assert.Equal(t, sarama.OffsetOldest, saramaConfig.Consumer.Offsets.Initial)
+	assert.Equal(t, defaultClientID, saramaConfig.ClientID)
+}
+
+func TestNewSaramaConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{name: "no_brokers", modify: func(cfg *Config) { cfg.Brokers = nil }},
+		{name: "no_topic", modify: func(cfg *Config) { cfg.Topic = "" }},
+		{name: "no_group_id", modify: func(cfg *Config) { cfg.GroupID = "" }},
+		{name: "invalid_version", modify: func(cfg *Config) { cfg.ProtocolVersion = "latest" }},
+		{name: "invalid_offset", modify: func(cfg *Config) { cfg.InitialOffset = "oldest" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+			_, err := newSaramaConfig(cfg)
+			assert.Error(t, err)
+
+			_, err = (&Factory{}).CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, exportertest.NewNopTraceExporter())
+			assert.Error(t, err)
+		})
+	}
+}
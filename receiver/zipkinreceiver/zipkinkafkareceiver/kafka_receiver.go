@@ -0,0 +1,212 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zipkinkafkareceiver receives the Zipkin spans published to a Kafka
+// topic, as done by the Kafka reporters of the Zipkin tracers and consumed by
+// the Kafka collector of Zipkin.
+package zipkinkafkareceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/zipkinreceiver"
+)
+
+var (
+	errNilNextConsumer = errors.New("nil nextConsumer")
+	errAlreadyStarted  = errors.New("already started")
+	errAlreadyStopped  = errors.New("already stopped")
+)
+
+const (
+	traceSource      = "Zipkin-Kafka"
+	receiverTagValue = "zipkin-kafka"
+)
+
+var _ receiver.TraceReceiver = (*kafkaReceiver)(nil)
+
+// kafkaReceiver implements the receiver.TraceReceiver for the Zipkin spans
+// consumed from Kafka.
+type kafkaReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	brokers      []string
+	topic        string
+	groupID      string
+	config       *sarama.Config
+	nextConsumer consumer.TraceConsumer
+
+	group  sarama.ConsumerGroup
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// New creates the Zipkin Kafka receiver consuming the topic as a member of
+// the given consumer group.
+func New(
+	logger *zap.Logger,
+	brokers []string,
+	topic string,
+	groupID string,
+	config *sarama.Config,
+	nextConsumer consumer.TraceConsumer,
+) (receiver.TraceReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	return &kafkaReceiver{
+		logger:       logger,
+		brokers:      brokers,
+		topic:        topic,
+		groupID:      groupID,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}, nil
+}
+
+// TraceSource returns the name of the trace data source.
+func (r *kafkaReceiver) TraceSource() string {
+	return traceSource
+}
+
+// StartTraceReception joins the consumer group and starts consuming the
+// topic. The brokers being unavailable at start is an error, later errors are
+// logged and the consumption is retried by the Kafka client.
+func (r *kafkaReceiver) StartTraceReception(host receiver.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := errAlreadyStarted
+	r.startOnce.Do(func() {
+		r.group, err = sarama.NewConsumerGroup(r.brokers, r.groupID, r.config)
+		if err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		r.done = make(chan struct{})
+		handler := &groupHandler{
+			logger:       r.logger,
+			nextConsumer: r.nextConsumer,
+			ctx:          obsreport.WithTransport(observability.ContextWithReceiverName(ctx, receiverTagValue), "kafka"),
+		}
+		go r.logErrors()
+		go func() {
+			defer close(r.done)
+			// Consume returns at every rebalance of the group, it must be
+			// called again to get the new claims.
+			for {
+				if err := r.group.Consume(ctx, []string{r.topic}, handler); err != nil {
+					if err == sarama.ErrClosedConsumerGroup {
+						return
+					}
+					r.logger.Error("Failed to consume the Zipkin Kafka topic", zap.String("topic", r.topic), zap.Error(err))
+				}
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}()
+	})
+
+	return err
+}
+
+// logErrors logs the errors of the consumer group until it is closed.
+func (r *kafkaReceiver) logErrors() {
+	for err := range r.group.Errors() {
+		r.logger.Error("Zipkin Kafka consumer error", zap.Error(err))
+	}
+}
+
+// StopTraceReception leaves the consumer group after the messages being
+// consumed are sent.
+func (r *kafkaReceiver) StopTraceReception() error {
+	r.Lock()
+	defer r.Unlock()
+
+	var err = errAlreadyStopped
+	r.stopOnce.Do(func() {
+		if r.group == nil {
+			err = nil
+			return
+		}
+		r.cancel()
+		<-r.done
+		err = r.group.Close()
+	})
+	return err
+}
+
+// groupHandler sends the spans of the messages of the claimed partitions to
+// the next consumer.
+type groupHandler struct {
+	logger       *zap.Logger
+	nextConsumer consumer.TraceConsumer
+	ctx          context.Context
+}
+
+var _ sarama.ConsumerGroupHandler = (*groupHandler)(nil)
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim consumes the messages of a partition. The offset of a message
+// is committed once its spans were sent, whether the next consumer accepted
+// them or not: like the HTTP receivers the receiver doesn't retry.
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		h.consumeMessage(msg.Value)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+func (h *groupHandler) consumeMessage(msg []byte) {
+	tds, err := zipkinreceiver.MessageToTraceData(msg)
+	if err != nil {
+		// The message is dropped, its spans can't be counted.
+		h.logger.Debug("Failed to decode a Zipkin Kafka message", zap.Error(err))
+		return
+	}
+
+	spans := 0
+	for _, td := range tds {
+		td.SourceFormat = "zipkin"
+		spans += len(td.Spans)
+		if err := h.nextConsumer.ConsumeTraceData(h.ctx, td); err != nil {
+			h.logger.Debug("Failed to send the spans of a Zipkin Kafka message", zap.Error(err))
+		}
+	}
+	observability.RecordTraceReceiverMetrics(h.ctx, spans, 0)
+}
@@ -0,0 +1,63 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinkafkareceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestNew_NilNextConsumer(t *testing.T) {
+	r, err := New(zap.NewNop(), []string{defaultBroker}, defaultTopic, defaultGroupID, nil, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+	assert.Nil(t, r)
+}
+
+func TestStopWithoutStart(t *testing.T) {
+	r, err := New(zap.NewNop(), []string{defaultBroker}, defaultTopic, defaultGroupID, nil, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+	assert.NoError(t, r.StopTraceReception())
+	assert.Equal(t, errAlreadyStopped, r.StopTraceReception())
+}
+
+func TestGroupHandler_ConsumeMessage(t *testing.T) {
+	msg, err := ioutil.ReadFile("../testdata/sample1.json")
+	require.NoError(t, err)
+
+	sink := new(exportertest.SinkTraceExporter)
+	h := &groupHandler{
+		logger:       zap.NewNop(),
+		nextConsumer: sink,
+		ctx:          context.Background(),
+	}
+
+	h.consumeMessage(msg)
+	assert.Equal(t, 9, sink.SpansCount())
+	for _, td := range sink.AllTraces() {
+		assert.Equal(t, "zipkin", td.SourceFormat)
+	}
+
+	// Messages that can't be decoded are dropped.
+	sink.Reset()
+	h.consumeMessage([]byte("not a zipkin message"))
+	assert.Equal(t, 0, sink.SpansCount())
+}
@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinscribereceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// This file implements factory for the Zipkin Scribe receiver.
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "zipkin-scribe"
+
+	defaultBindEndpoint = "127.0.0.1:9410"
+	defaultCategory     = "zipkin"
+)
+
+// Factory is the factory for the Zipkin Scribe receiver.
+type Factory struct {
+}
+
+// Type gets the type of the Receiver config created by this factory.
+func (f *Factory) Type() string {
+	return typeStr
+}
+
+// CustomUnmarshaler returns nil because we don't need custom unmarshaling for this config.
+func (f *Factory) CustomUnmarshaler() receiver.CustomUnmarshaler {
+	return nil
+}
+
+// CreateDefaultConfig creates the default configuration for the Zipkin Scribe receiver.
+func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal:  typeStr,
+			NameVal:  typeStr,
+			Endpoint: defaultBindEndpoint,
+		},
+		Category: defaultCategory,
+	}
+}
+
+// CreateTraceReceiver creates a trace receiver based on provided config.
+func (f *Factory) CreateTraceReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.TraceConsumer,
+) (receiver.TraceReceiver, error) {
+	rCfg := cfg.(*Config)
+	if rCfg.Category == "" {
+		return nil, fmt.Errorf("error initializing Zipkin Scribe receiver %q: \"category\" must not be empty", rCfg.Name())
+	}
+	addr, port, err := splitEndpoint(rCfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Zipkin Scribe receiver %q: %v", rCfg.Name(), err)
+	}
+	return NewReceiver(addr, port, rCfg.Category, nextConsumer)
+}
+
+// CreateMetricsReceiver creates a metrics receiver based on provided config.
+func (f *Factory) CreateMetricsReceiver(
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	consumer consumer.MetricsConsumer,
+) (receiver.MetricsReceiver, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
+
+// splitEndpoint splits the "[host]:port" endpoint into the address and the
+// port expected by NewReceiver.
+func splitEndpoint(endpoint string) (string, uint16, error) {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in endpoint %q: %v", endpoint, err)
+	}
+	return host, uint16(port), nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkinscribereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+}
+
+func TestCreateReceiver(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, exportertest.NewNopTraceExporter())
+	assert.Nil(t, err, "receiver creation failed")
+	assert.NotNil(t, tReceiver, "receiver creation failed")
+
+	r := tReceiver.(*scribeReceiver)
+	assert.Equal(t, "127.0.0.1", r.addr)
+	assert.Equal(t, uint16(9410), r.port)
+	assert.Equal(t, defaultCategory, r.collector.category)
+
+	mReceiver, err := factory.CreateMetricsReceiver(zap.NewNop(), cfg, nil)
+	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	assert.Nil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		category string
+	}{
+		{name: "no_port", endpoint: "localhost", category: defaultCategory},
+		{name: "invalid_port", endpoint: "localhost:65536", category: defaultCategory},
+		{name: "no_category", endpoint: defaultBindEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &Factory{}
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.Endpoint = tt.endpoint
+			cfg.Category = tt.category
+
+			tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, exportertest.NewNopTraceExporter())
+			assert.Error(t, err)
+			assert.Nil(t, tReceiver)
+		})
+	}
+}
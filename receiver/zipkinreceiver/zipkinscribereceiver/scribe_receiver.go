@@ -118,6 +118,11 @@ func (r *scribeReceiver) StopTraceReception() error {
 
 	var err = errAlreadyStopped
 	r.stopOnce.Do(func() {
+		if r.server == nil {
+			// Never started, or failed to start.
+			err = nil
+			return
+		}
 		err = r.server.Stop()
 	})
 	return err
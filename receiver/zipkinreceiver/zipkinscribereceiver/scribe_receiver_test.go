@@ -153,6 +153,19 @@ func TestScribeReceiverPortAlreadyInUse(t *testing.T) {
 	}
 }
 
+func TestStopWithoutStart(t *testing.T) {
+	traceReceiver, err := NewReceiver("", 0, "zipkin", exportertest.NewNopTraceExporter())
+	if err != nil {
+		t.Fatalf("Failed to create receiver: %v", err)
+	}
+	if err := traceReceiver.StopTraceReception(); err != nil {
+		t.Errorf("StopTraceReception() error = %v, want nil", err)
+	}
+	if err := traceReceiver.StopTraceReception(); err != errAlreadyStopped {
+		t.Errorf("StopTraceReception() error = %v, want %v", err, errAlreadyStopped)
+	}
+}
+
 func TestScribeReceiverServer(t *testing.T) {
 	const host = ""
 	const port = 9410
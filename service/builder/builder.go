@@ -19,22 +19,40 @@ package builder
 import (
 	"flag"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 const (
 	// flags
-	configCfg      = "config"
-	memBallastFlag = "mem-ballast-size-mib"
+	configCfg        = "config"
+	memBallastFlag   = "mem-ballast-size-mib"
+	memBudgetFlag    = "mem-budget-mib"
+	drainTimeoutFlag = "drain-timeout"
+	pluginsFlag      = "plugins"
 )
 
 // Flags adds flags related to basic building of the collector application to the given flagset.
 func Flags(flags *flag.FlagSet) {
 	flags.String(configCfg, "", "Path to the config file")
 	flags.Uint(memBallastFlag, 0,
-		fmt.Sprintf("Flag to specify size of memory (MiB) ballast to set. Ballast is not used when this is not specified. "+
-			"default settings: 0"))
+		fmt.Sprintf("Flag to specify size of memory (MiB) ballast to set, takes precedence over the runtime section "+
+			"of the config file. Ballast is not used when neither is specified. default settings: 0"))
+	flags.Uint(memBudgetFlag, 0,
+		"Flag to specify the memory (MiB) that queues, batches and traces waiting for a sampling decision can use "+
+			"altogether. Data exceeding it is sent right away or dropped. Unlimited when this is not specified.")
+	flags.Duration(drainTimeoutFlag, 5*time.Second,
+		"Maximum time to flush the processors and wait for the exporter queues to be empty on shutdown. "+
+			"Data still in flight after it is lost.")
+}
+
+// PluginsFlags adds the flag of the plugins to load to the given flagset. It
+// is shared by the commands of the service.
+func PluginsFlags(flags *flag.FlagSet) {
+	flags.String(pluginsFlag, "",
+		"Comma separated paths of Go plugins adding receivers, processors and exporters to the service.")
 }
 
 // GetConfigFile gets the config file from the config file flag.
@@ -46,3 +64,24 @@ func GetConfigFile(v *viper.Viper) string {
 func MemBallastSize(v *viper.Viper) int {
 	return v.GetInt(memBallastFlag)
 }
+
+// MemBudgetSize returns the size of the memory budget to use in MBs
+func MemBudgetSize(v *viper.Viper) int {
+	return v.GetInt(memBudgetFlag)
+}
+
+// DrainTimeout returns the maximum time to flush the pipelines on shutdown.
+func DrainTimeout(v *viper.Viper) time.Duration {
+	return v.GetDuration(drainTimeoutFlag)
+}
+
+// Plugins returns the paths of the plugins to load.
+func Plugins(v *viper.Viper) []string {
+	var paths []string
+	for _, path := range strings.Split(v.GetString(pluginsFlag), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,209 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// ComponentState is the state of a receiver or an exporter.
+type ComponentState string
+
+// States of the receivers and exporters.
+const (
+	// StateStarting is the state of a component not started yet.
+	StateStarting ComponentState = "starting"
+	// StateRunning is the state of a component started, or restarted, without
+	// error.
+	StateRunning ComponentState = "running"
+	// StateRestarting is the state of a component waiting to be restarted
+	// after a fatal error.
+	StateRestarting ComponentState = "restarting"
+	// StateError is the state of a component which failed to start or
+	// reported a fatal error it is not restarted after.
+	StateError ComponentState = "error"
+	// StateStopped is the state of a component stopped with the service.
+	StateStopped ComponentState = "stopped"
+)
+
+// ComponentStatus is the state of a receiver or an exporter, with the number
+// of times it was restarted.
+type ComponentStatus struct {
+	State ComponentState
+	// Err is the error of a component in the StateError or StateRestarting
+	// state.
+	Err      error
+	Restarts int
+}
+
+// componentState tracks the status of a component, the zero value is the
+// status of a component not started yet.
+type componentState struct {
+	mu     sync.Mutex
+	status ComponentStatus
+}
+
+func (s *componentState) set(state ComponentState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == StateRestarting {
+		s.status.Restarts++
+	}
+	s.status.State = state
+	s.status.Err = err
+}
+
+func (s *componentState) get() ComponentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	if status.State == "" {
+		status.State = StateStarting
+	}
+	return status
+}
+
+// componentHost is the host given to a receiver or an exporter when it starts.
+// It handles the fatal errors of the component according to the restart
+// policy: it either reports them to the host of the service, which shuts
+// down, or restarts the component with backoff.
+type componentHost struct {
+	host    receiver.Host
+	logger  *zap.Logger
+	policy  configmodels.RestartPolicy
+	kind    string
+	name    string
+	restart func(host *componentHost) error
+	state   *componentState
+
+	mu       sync.Mutex
+	restarts int
+	stopped  bool
+	stopCh   chan struct{}
+}
+
+var _ receiver.Host = (*componentHost)(nil)
+var _ exporter.Host = (*componentHost)(nil)
+
+func newComponentHost(
+	host receiver.Host,
+	logger *zap.Logger,
+	policy configmodels.RestartPolicy,
+	kind string,
+	name string,
+	restart func(host *componentHost) error,
+	state *componentState,
+) *componentHost {
+	return &componentHost{
+		host:    host,
+		logger:  logger.With(zap.String(kind, name)),
+		policy:  policy,
+		kind:    kind,
+		name:    name,
+		restart: restart,
+		state:   state,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Context returns the context of the host of the service.
+func (h *componentHost) Context() context.Context {
+	return h.host.Context()
+}
+
+// ReportFatalError restarts the component if the restart policy says so,
+// otherwise it reports the error to the host of the service.
+func (h *componentHost) ReportFatalError(err error) {
+	if h.policy.Action != configmodels.RestartPolicyRestart || h.restart == nil {
+		h.state.set(StateError, err)
+		h.host.ReportFatalError(fmt.Errorf("%s %s failed: %v", h.kind, h.name, err))
+		return
+	}
+	// The component may report the error from a goroutine waited for when it
+	// stops, it must not be restarted from there.
+	go h.restartAfterBackoff(err)
+}
+
+func (h *componentHost) restartAfterBackoff(err error) {
+	h.mu.Lock()
+	h.restarts++
+	restarts := h.restarts
+	h.mu.Unlock()
+
+	if h.policy.MaxRestarts > 0 && restarts > h.policy.MaxRestarts {
+		h.state.set(StateError, err)
+		h.host.ReportFatalError(fmt.Errorf("%s %s failed after %d restarts: %v",
+			h.kind, h.name, h.policy.MaxRestarts, err))
+		return
+	}
+
+	h.state.set(StateRestarting, err)
+	delay := h.restartDelay(restarts)
+	h.logger.Error("Component failed, restarting it",
+		zap.Error(err), zap.Int("restarts", restarts), zap.Duration("delay", delay))
+	select {
+	case <-time.After(delay):
+	case <-h.stopCh:
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopped {
+		return
+	}
+	if err := h.restart(h); err != nil {
+		h.ReportFatalError(err)
+		return
+	}
+	h.state.set(StateRunning, nil)
+	h.logger.Info("Component restarted")
+}
+
+// restartDelay returns the time to wait before the given restart, starting
+// at 1: the initial interval doubled after every restart, up to the max.
+func (h *componentHost) restartDelay(restarts int) time.Duration {
+	delay := h.policy.InitialInterval
+	for i := 1; i < restarts && delay < h.policy.MaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > h.policy.MaxInterval {
+		delay = h.policy.MaxInterval
+	}
+	return delay
+}
+
+// stop prevents any further restart, waiting for the one in progress if any.
+// It must be called before stopping the component.
+func (h *componentHost) stop() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.stopped {
+		h.stopped = true
+		close(h.stopCh)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/exporter/exportertest"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// errorsHost is the host of the service, it records the fatal errors.
+type errorsHost struct {
+	errs chan error
+}
+
+func newErrorsHost() *errorsHost {
+	return &errorsHost{errs: make(chan error, 10)}
+}
+
+func (h *errorsHost) Context() context.Context {
+	return context.Background()
+}
+
+func (h *errorsHost) ReportFatalError(err error) {
+	h.errs <- err
+}
+
+// hostReceiver is a trace receiver keeping the host it is started with.
+type hostReceiver struct {
+	hosts    chan receiver.Host
+	stops    chan struct{}
+	stopped  bool
+	startErr error
+}
+
+func (r *hostReceiver) TraceSource() string {
+	return "host"
+}
+
+func (r *hostReceiver) StartTraceReception(host receiver.Host) error {
+	if r.startErr != nil {
+		return r.startErr
+	}
+	r.hosts <- host
+	return nil
+}
+
+func (r *hostReceiver) StopTraceReception() error {
+	r.stopped = true
+	if r.stops != nil {
+		r.stops <- struct{}{}
+	}
+	return nil
+}
+
+var restartPolicy = configmodels.RestartPolicy{
+	Action:          configmodels.RestartPolicyRestart,
+	InitialInterval: time.Millisecond,
+	MaxInterval:     time.Millisecond,
+	MaxRestarts:     1,
+}
+
+func TestComponentHost_Shutdown(t *testing.T) {
+	host := newErrorsHost()
+	policy := restartPolicy
+	policy.Action = configmodels.RestartPolicyShutdown
+	restarted := false
+	h := newComponentHost(host, zap.NewNop(), policy, "receiver", "jaeger", func(*componentHost) error {
+		restarted = true
+		return nil
+	}, &componentState{})
+
+	h.ReportFatalError(errors.New("listener closed"))
+	assert.EqualError(t, <-host.errs, "receiver jaeger failed: listener closed")
+	assert.False(t, restarted)
+}
+
+func TestComponentHost_RestartDelay(t *testing.T) {
+	h := newComponentHost(newErrorsHost(), zap.NewNop(), configmodels.RestartPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+	}, "receiver", "jaeger", nil, &componentState{})
+
+	assert.Equal(t, time.Second, h.restartDelay(1))
+	assert.Equal(t, 2*time.Second, h.restartDelay(2))
+	assert.Equal(t, 4*time.Second, h.restartDelay(3))
+	assert.Equal(t, 5*time.Second, h.restartDelay(4))
+	assert.Equal(t, 5*time.Second, h.restartDelay(100))
+}
+
+func TestReceivers_Restart(t *testing.T) {
+	first := &hostReceiver{hosts: make(chan receiver.Host, 1), stops: make(chan struct{}, 1)}
+	second := &hostReceiver{hosts: make(chan receiver.Host, 1)}
+	rcvCfg := &configmodels.ReceiverSettings{NameVal: "jaeger"}
+	receivers := Receivers{
+		rcvCfg: &builtReceiver{
+			trace: first,
+			rebuild: func() (*builtReceiver, error) {
+				return &builtReceiver{trace: second}, nil
+			},
+			restartPolicy: restartPolicy,
+		},
+	}
+
+	host := newErrorsHost()
+	require.NoError(t, receivers.StartAll(zap.NewNop(), host))
+
+	// A new receiver is started, then the failed one is stopped.
+	(<-first.hosts).ReportFatalError(errors.New("listener closed"))
+	secondHost := <-second.hosts
+	<-first.stops
+	assert.Equal(t, second, receivers[rcvCfg].trace)
+
+	// The service shuts down once the receiver was restarted MaxRestarts times.
+	secondHost.ReportFatalError(errors.New("listener closed again"))
+	assert.EqualError(t, <-host.errs, "receiver jaeger failed after 1 restarts: listener closed again")
+	assert.Equal(t, map[string]ComponentStatus{
+		"jaeger": {State: StateError, Err: errors.New("listener closed again"), Restarts: 1},
+	}, receivers.Statuses())
+
+	receivers.StopAll()
+	assert.Equal(t, StateStopped, receivers.Statuses()["jaeger"].State)
+}
+
+func TestReceivers_RestartAfterStop(t *testing.T) {
+	first := &hostReceiver{hosts: make(chan receiver.Host, 1)}
+	busy := &hostReceiver{startErr: errors.New("address already in use")}
+	third := &hostReceiver{hosts: make(chan receiver.Host, 1)}
+	rebuilt := []*hostReceiver{busy, third}
+	rcvCfg := &configmodels.ReceiverSettings{NameVal: "jaeger"}
+	receivers := Receivers{
+		rcvCfg: &builtReceiver{
+			trace: first,
+			rebuild: func() (*builtReceiver, error) {
+				r := rebuilt[0]
+				rebuilt = rebuilt[1:]
+				return &builtReceiver{trace: r}, nil
+			},
+			restartPolicy: restartPolicy,
+		},
+	}
+
+	require.NoError(t, receivers.StartAll(zap.NewNop(), newErrorsHost()))
+
+	// The new receiver cannot start while the failed one runs, the failed one
+	// is stopped first.
+	(<-first.hosts).ReportFatalError(errors.New("listener closed"))
+	<-third.hosts
+	assert.True(t, first.stopped)
+	assert.True(t, busy.stopped)
+	receivers.StopAll()
+}
+
+func TestReceivers_StopAllCancelsRestart(t *testing.T) {
+	first := &hostReceiver{hosts: make(chan receiver.Host, 1)}
+	rebuilt := false
+	rcvCfg := &configmodels.ReceiverSettings{NameVal: "jaeger"}
+	policy := restartPolicy
+	policy.InitialInterval = time.Hour
+	policy.MaxInterval = time.Hour
+	receivers := Receivers{
+		rcvCfg: &builtReceiver{
+			trace: first,
+			rebuild: func() (*builtReceiver, error) {
+				rebuilt = true
+				return &builtReceiver{trace: first}, nil
+			},
+			restartPolicy: policy,
+		},
+	}
+
+	require.NoError(t, receivers.StartAll(zap.NewNop(), newErrorsHost()))
+	(<-first.hosts).ReportFatalError(errors.New("listener closed"))
+	receivers.StopAll()
+
+	assert.True(t, first.stopped)
+	assert.False(t, rebuilt)
+}
+
+// startingExporter is a metrics exporter working in the background.
+type startingExporter struct {
+	hosts chan exporter.Host
+}
+
+func (e *startingExporter) ConsumeMetricsData(context.Context, consumerdata.MetricsData) error {
+	return nil
+}
+
+func (e *startingExporter) Start(host exporter.Host) error {
+	e.hosts <- host
+	return nil
+}
+
+func TestExporters_Restart(t *testing.T) {
+	exp := &startingExporter{hosts: make(chan exporter.Host, 2)}
+	exporters := Exporters{
+		&configmodels.ExporterSettings{NameVal: "prometheus"}: &builtExporter{
+			mc:            exp,
+			stop:          func() error { return nil },
+			restartPolicy: restartPolicy,
+		},
+		// Exporters not working in the background are not started.
+		&configmodels.ExporterSettings{NameVal: "logging"}: &builtExporter{
+			mc:   &exportertest.SinkMetricsExporter{},
+			stop: func() error { return nil },
+		},
+	}
+
+	assert.Equal(t, map[string]ComponentStatus{
+		"prometheus": {State: StateStarting},
+		"logging":    {State: StateRunning},
+	}, exporters.Statuses())
+
+	host := newErrorsHost()
+	require.NoError(t, exporters.StartAll(zap.NewNop(), host))
+	assert.Equal(t, StateRunning, exporters.Statuses()["prometheus"].State)
+
+	// The same exporter is started again.
+	(<-exp.hosts).ReportFatalError(errors.New("listener closed"))
+	(<-exp.hosts).ReportFatalError(errors.New("listener closed again"))
+	assert.EqualError(t, <-host.errs, "exporter prometheus failed after 1 restarts: listener closed again")
+	exporters.StopAll()
+}
@@ -15,15 +15,18 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/oterr"
+	"github.com/open-telemetry/opentelemetry-service/storage"
 )
 
 // builtExporter is an exporter that is built based on a config. It can have
@@ -32,6 +35,21 @@ type builtExporter struct {
 	tc   consumer.TraceConsumer
 	mc   consumer.MetricsConsumer
 	stop func() error
+
+	restartPolicy configmodels.RestartPolicy
+	host          *componentHost
+	state         componentState
+}
+
+// Start the background work of the exporter, if any.
+func (exp *builtExporter) Start(host exporter.Host) error {
+	var errors []error
+	for _, s := range exp.starters() {
+		if err := s.Start(host); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return oterr.CombineErrors(errors)
 }
 
 // Stop the exporter.
@@ -39,14 +57,104 @@ func (exp *builtExporter) Stop() error {
 	return exp.stop()
 }
 
+func (exp *builtExporter) starters() []exporter.Starter {
+	var starters []exporter.Starter
+	if s, ok := exp.tc.(exporter.Starter); ok {
+		starters = append(starters, s)
+	}
+	// The same exporter can consume both data types.
+	if s, ok := exp.mc.(exporter.Starter); ok && (len(starters) == 0 || starters[0] != s) {
+		starters = append(starters, s)
+	}
+	return starters
+}
+
 // Exporters is a map of exporters created from exporter configs.
 type Exporters map[configmodels.Exporter]*builtExporter
 
+// StartAll starts the exporters working in the background. The fatal errors
+// they report later are handled according to the restart policy of the
+// config, the host is told of the ones that must shut the service down.
+func (exps Exporters) StartAll(logger *zap.Logger, host exporter.Host) error {
+	for cfg, exp := range exps {
+		if len(exp.starters()) == 0 {
+			continue
+		}
+		exp := exp
+		logger.Info("Exporter is starting...", zap.String("exporter", cfg.Name()))
+
+		// Restarting the exporter starts it again, the pipelines keep
+		// sending to the same instance.
+		exp.host = newComponentHost(host, logger, exp.restartPolicy, "exporter", cfg.Name(),
+			func(h *componentHost) error { return exp.Start(h) }, &exp.state)
+		if err := exp.Start(exp.host); err != nil {
+			exp.state.set(StateError, err)
+			return err
+		}
+		exp.state.set(StateRunning, nil)
+		logger.Info("Exporter is started.", zap.String("exporter", cfg.Name()))
+	}
+	return nil
+}
+
 // StopAll stops all exporters.
 func (exps Exporters) StopAll() {
 	for _, exp := range exps {
+		exp.host.stop()
 		exp.Stop()
+		exp.state.set(StateStopped, nil)
+	}
+}
+
+// Statuses returns the status of the exporters by name. The exporters
+// without background work are running once built.
+func (exps Exporters) Statuses() map[string]ComponentStatus {
+	statuses := make(map[string]ComponentStatus, len(exps))
+	for cfg, exp := range exps {
+		status := exp.state.get()
+		if status.State == StateStarting && len(exp.starters()) == 0 {
+			status.State = StateRunning
+		}
+		statuses[cfg.Name()] = status
+	}
+	return statuses
+}
+
+// DrainAll waits until the exporters that queue data have sent it all, or
+// the context is done.
+func (exps Exporters) DrainAll(ctx context.Context) error {
+	var errs []error
+	for _, exp := range exps {
+		for _, d := range exp.drainers() {
+			if err := d.Drain(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return oterr.CombineErrors(errs)
+}
+
+// InFlight returns the number of items (spans or metrics) queued or being
+// sent by the exporters.
+func (exps Exporters) InFlight() int {
+	inFlight := 0
+	for _, exp := range exps {
+		for _, d := range exp.drainers() {
+			inFlight += d.InFlight()
+		}
 	}
+	return inFlight
+}
+
+func (exp *builtExporter) drainers() []exporter.Drainer {
+	var drainers []exporter.Drainer
+	if d, ok := exp.tc.(exporter.Drainer); ok {
+		drainers = append(drainers, d)
+	}
+	if d, ok := exp.mc.(exporter.Drainer); ok {
+		drainers = append(drainers, d)
+	}
+	return drainers
 }
 
 type dataTypeRequirement struct {
@@ -65,6 +173,8 @@ type ExportersBuilder struct {
 	logger    *zap.Logger
 	config    *configmodels.Config
 	factories map[string]exporter.Factory
+	storage   storage.Storage
+	buildInfo buildinfo.BuildInfo
 }
 
 // NewExportersBuilder creates a new ExportersBuilder. Call Build() on the returned value.
@@ -73,7 +183,21 @@ func NewExportersBuilder(
 	config *configmodels.Config,
 	factories map[string]exporter.Factory,
 ) *ExportersBuilder {
-	return &ExportersBuilder{logger, config, factories}
+	return &ExportersBuilder{logger, config, factories, storage.NewMemoryStorage(), buildinfo.Default()}
+}
+
+// WithStorage gives the exporters persisting state their client of the
+// storage, by default their state is kept in memory.
+func (eb *ExportersBuilder) WithStorage(st storage.Storage) *ExportersBuilder {
+	eb.storage = st
+	return eb
+}
+
+// WithBuildInfo gives the exporter factories using it the build of the
+// service, by default the build of otelsvc.
+func (eb *ExportersBuilder) WithBuildInfo(bi buildinfo.BuildInfo) *ExportersBuilder {
+	eb.buildInfo = bi
+	return eb
 }
 
 // Build exporters from config.
@@ -83,6 +207,9 @@ func (eb *ExportersBuilder) Build() (Exporters, error) {
 	// We need to calculate required input data types for each exporter so that we know
 	// which data type must be started for each exporter.
 	exporterInputDataTypes := eb.calcExportersRequiredDataTypes()
+	if err := eb.addWrappedRequiredDataTypes(exporterInputDataTypes); err != nil {
+		return nil, err
+	}
 
 	// Build exporters based on configuration and required input data types.
 	for _, cfg := range eb.config.Exporters {
@@ -93,9 +220,94 @@ func (eb *ExportersBuilder) Build() (Exporters, error) {
 		exporters[cfg] = exp
 	}
 
+	eb.connectWrappers(exporters)
 	return exporters, nil
 }
 
+// addWrappedRequiredDataTypes adds the data types required for the exporters
+// wrapping other exporters to the exporters they wrap, through any number of
+// wrapping levels.
+func (eb *ExportersBuilder) addWrappedRequiredDataTypes(result exportersRequiredDataTypes) error {
+	for _, cfg := range eb.config.Exporters {
+		if err := eb.requireWrapped(cfg, result[cfg], result, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (eb *ExportersBuilder) requireWrapped(
+	cfg configmodels.Exporter,
+	requirements dataTypeRequirements,
+	result exportersRequiredDataTypes,
+	wrappers []string,
+) error {
+	wc, ok := cfg.(exporter.WrappingConfig)
+	if !ok || len(requirements) == 0 {
+		return nil
+	}
+	for _, name := range wrappers {
+		if name == cfg.Name() {
+			return fmt.Errorf("exporter %q wraps itself", cfg.Name())
+		}
+	}
+	wrappers = append(wrappers, cfg.Name())
+
+	for _, name := range wc.WrappedExporters() {
+		wrapped := eb.config.Exporters[name]
+		if wrapped == nil {
+			return fmt.Errorf("exporter %q wraps exporter %q which does not exist", cfg.Name(), name)
+		}
+		if result[wrapped] == nil {
+			result[wrapped] = make(dataTypeRequirements)
+		}
+		for dataType, requirement := range requirements {
+			result[wrapped][dataType] = requirement
+		}
+		if err := eb.requireWrapped(wrapped, requirements, result, wrappers); err != nil {
+			return err
+		}
+	}
+
+	// The errors of the exporters queuing the data don't reach the exporters
+	// wrapping them.
+	if swc, ok := cfg.(exporter.SynchronousWrappingConfig); ok {
+		for _, name := range swc.SynchronousExporters() {
+			qc, ok := eb.config.Exporters[name].(exporter.QueuedConfig)
+			if ok && qc.QueueEnabled() {
+				return fmt.Errorf("exporter %q needs the errors of exporter %q which must disable its sending-queue", cfg.Name(), name)
+			}
+		}
+	}
+	return nil
+}
+
+// connectWrappers sets the exporters wrapped by the exporters of a
+// exporter.WrappingConfig.
+func (eb *ExportersBuilder) connectWrappers(exporters Exporters) {
+	for cfg, exp := range exporters {
+		wc, ok := cfg.(exporter.WrappingConfig)
+		if !ok {
+			continue
+		}
+		names := wc.WrappedExporters()
+		if w, ok := exp.tc.(exporter.TraceWrapper); ok {
+			tcs := make([]consumer.TraceConsumer, len(names))
+			for i, name := range names {
+				tcs[i] = exporters[eb.config.Exporters[name]].tc
+			}
+			w.SetTraceExporters(tcs)
+		}
+		if w, ok := exp.mc.(exporter.MetricsWrapper); ok {
+			mcs := make([]consumer.MetricsConsumer, len(names))
+			for i, name := range names {
+				mcs[i] = exporters[eb.config.Exporters[name]].mc
+			}
+			w.SetMetricsExporters(mcs)
+		}
+	}
+}
+
 func (eb *ExportersBuilder) calcExportersRequiredDataTypes() exportersRequiredDataTypes {
 
 	// Go over all pipelines. The data type of the pipeline defines what data type
@@ -163,8 +375,9 @@ func (eb *ExportersBuilder) buildExporter(
 	if factory == nil {
 		return nil, fmt.Errorf("exporter factory not found for type: %s", config.Type())
 	}
+	setBuildInfo(factory, eb.buildInfo)
 
-	exporter := &builtExporter{}
+	exporter := &builtExporter{restartPolicy: eb.config.RestartPolicy}
 
 	inputDataTypes := exportersInputDataTypes[config]
 	if inputDataTypes == nil {
@@ -175,9 +388,11 @@ func (eb *ExportersBuilder) buildExporter(
 		return exporter, nil
 	}
 
+	logger := eb.logger.With(zap.String("exporter", config.Name()))
+
 	if requirement, ok := inputDataTypes[configmodels.TracesDataType]; ok {
 		// Traces data type is required. Create a trace exporter based on config.
-		tc, stopFunc, err := factory.CreateTraceExporter(eb.logger, config)
+		tc, stopFunc, err := factory.CreateTraceExporter(logger, config)
 		if err != nil {
 			if err == configerror.ErrDataTypeIsNotSupported {
 				// Could not create because this exporter does not support this data type.
@@ -185,6 +400,12 @@ func (eb *ExportersBuilder) buildExporter(
 			}
 			return nil, fmt.Errorf("error creating %s exporter: %v", config.Name(), err)
 		}
+		if tc == nil {
+			return nil, nilConsumerErr(config, configmodels.TracesDataType)
+		}
+		if err := setStorageClient(eb.storage, tc, "exporter", config.Name(), "traces"); err != nil {
+			return nil, err
+		}
 
 		exporter.tc = tc
 		exporter.stop = stopFunc
@@ -192,7 +413,7 @@ func (eb *ExportersBuilder) buildExporter(
 
 	if requirement, ok := inputDataTypes[configmodels.MetricsDataType]; ok {
 		// Metrics data type is required. Create a trace exporter based on config.
-		mc, stopFunc, err := factory.CreateMetricsExporter(eb.logger, config)
+		mc, stopFunc, err := factory.CreateMetricsExporter(logger, config)
 		if err != nil {
 			if err == configerror.ErrDataTypeIsNotSupported {
 				// Could not create because this exporter does not support this data type.
@@ -200,6 +421,12 @@ func (eb *ExportersBuilder) buildExporter(
 			}
 			return nil, fmt.Errorf("error creating %s exporter: %v", config.Name(), err)
 		}
+		if mc == nil {
+			return nil, nilConsumerErr(config, configmodels.MetricsDataType)
+		}
+		if err := setStorageClient(eb.storage, mc, "exporter", config.Name(), "metrics"); err != nil {
+			return nil, err
+		}
 
 		exporter.mc = mc
 		exporter.stop = combineStopFunc(exporter.stop, stopFunc)
@@ -220,3 +447,8 @@ func typeMismatchErr(
 		config.Name(), dataType.GetString(),
 	)
 }
+
+func nilConsumerErr(config configmodels.Exporter, dataType configmodels.DataType) error {
+	return fmt.Errorf("factory for exporter %s returned a nil %s exporter without an error",
+		config.Name(), dataType.GetString())
+}
@@ -15,6 +15,7 @@
 package builder
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -24,7 +25,10 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
 	"github.com/open-telemetry/opentelemetry-service/exporter/opencensusexporter"
+	"github.com/open-telemetry/opentelemetry-service/processor/addattributesprocessor"
 )
 
 func TestExportersBuilder_Build(t *testing.T) {
@@ -93,6 +97,39 @@ func TestExportersBuilder_Build(t *testing.T) {
 	// TODO: once we have an exporter that supports metrics data type test it too.
 }
 
+// nilExporterFactory is an exporter factory that returns nil trace exporters
+// without an error.
+type nilExporterFactory struct {
+	config.ExampleExporterFactory
+}
+
+func (f *nilExporterFactory) CreateTraceExporter(
+	logger *zap.Logger,
+	cfg configmodels.Exporter,
+) (consumer.TraceConsumer, exporter.StopFunc, error) {
+	return nil, nil, nil
+}
+
+func TestExportersBuilder_NilExporter(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	nilFactory := &nilExporterFactory{}
+	exporterFactories[nilFactory.Type()] = nilFactory
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	// This should fail instead of wiring a nil consumer into the pipeline.
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "returned a nil traces exporter")
+	assert.Nil(t, exporters)
+}
+
 func TestExportersBuilder_StopAll(t *testing.T) {
 	exporters := make(Exporters)
 	expCfg := &configmodels.ExporterSettings{}
@@ -109,6 +146,160 @@ func TestExportersBuilder_StopAll(t *testing.T) {
 	assert.Equal(t, stopCalled, true)
 }
 
+// drainingExporter is a trace exporter whose queue is drained by Drain.
+type drainingExporter struct {
+	consumer.TraceConsumer
+	queued int
+	err    error
+}
+
+var _ exporter.Drainer = (*drainingExporter)(nil)
+
+func (de *drainingExporter) Drain(ctx context.Context) error {
+	if de.err == nil {
+		de.queued = 0
+	}
+	return de.err
+}
+
+func (de *drainingExporter) InFlight() int {
+	return de.queued
+}
+
+func TestExportersBuilder_DrainAll(t *testing.T) {
+	exporters := make(Exporters)
+	drained := &drainingExporter{queued: 2}
+	stuck := &drainingExporter{queued: 3, err: errors.New("still in flight")}
+	exporters[&configmodels.ExporterSettings{NameVal: "drained"}] = &builtExporter{tc: drained}
+	exporters[&configmodels.ExporterSettings{NameVal: "stuck"}] = &builtExporter{tc: stuck}
+	// Exporters that don't queue data are ignored.
+	exporters[&configmodels.ExporterSettings{NameVal: "other"}] = &builtExporter{tc: &config.ExampleExporterConsumer{}}
+	assert.Equal(t, 5, exporters.InFlight())
+
+	assert.Error(t, exporters.DrainAll(context.Background()))
+	assert.Equal(t, 3, exporters.InFlight())
+}
+
+// wrappingExporterConfig is the config of the wrappingExporterFactory
+// exporters.
+type wrappingExporterConfig struct {
+	configmodels.ExporterSettings
+	wrapped []string
+}
+
+var _ exporter.SynchronousWrappingConfig = (*wrappingExporterConfig)(nil)
+
+func (cfg *wrappingExporterConfig) WrappedExporters() []string {
+	return cfg.wrapped
+}
+
+func (cfg *wrappingExporterConfig) SynchronousExporters() []string {
+	return cfg.wrapped
+}
+
+// queuedExporterConfig is the config of an example exporter that may queue
+// the data.
+type queuedExporterConfig struct {
+	config.ExampleExporter
+	queued bool
+}
+
+var _ exporter.QueuedConfig = (*queuedExporterConfig)(nil)
+
+func (cfg *queuedExporterConfig) QueueEnabled() bool {
+	return cfg.queued
+}
+
+// wrappingExporterFactory is an exporter factory that creates trace exporters
+// recording the exporters they wrap.
+type wrappingExporterFactory struct {
+	config.ExampleExporterFactory
+}
+
+func (f *wrappingExporterFactory) Type() string {
+	return "wrapping"
+}
+
+func (f *wrappingExporterFactory) CreateTraceExporter(
+	logger *zap.Logger,
+	cfg configmodels.Exporter,
+) (consumer.TraceConsumer, exporter.StopFunc, error) {
+	return &wrappingExporter{}, nil, nil
+}
+
+type wrappingExporter struct {
+	config.ExampleExporterConsumer
+	wrapped []consumer.TraceConsumer
+}
+
+var _ exporter.TraceWrapper = (*wrappingExporter)(nil)
+
+func (we *wrappingExporter) SetTraceExporters(exporters []consumer.TraceConsumer) {
+	we.wrapped = exporters
+}
+
+func TestExportersBuilder_Wrapping(t *testing.T) {
+	_, _, exporterFactories, err := config.ExampleComponents()
+	require.NoError(t, err)
+	wrappingFactory := &wrappingExporterFactory{}
+	exporterFactories[wrappingFactory.Type()] = wrappingFactory
+
+	newConfig := func(wrapped ...string) *configmodels.Config {
+		return &configmodels.Config{
+			Exporters: map[string]configmodels.Exporter{
+				"wrapping": &wrappingExporterConfig{
+					ExporterSettings: configmodels.ExporterSettings{NameVal: "wrapping", TypeVal: "wrapping"},
+					wrapped:          wrapped,
+				},
+				"exampleexporter/1": &config.ExampleExporter{
+					ExporterSettings: configmodels.ExporterSettings{NameVal: "exampleexporter/1", TypeVal: "exampleexporter"},
+				},
+				"exampleexporter/2": &queuedExporterConfig{
+					ExampleExporter: config.ExampleExporter{
+						ExporterSettings: configmodels.ExporterSettings{NameVal: "exampleexporter/2", TypeVal: "exampleexporter"},
+					},
+				},
+				"exampleexporter/queued": &queuedExporterConfig{
+					ExampleExporter: config.ExampleExporter{
+						ExporterSettings: configmodels.ExporterSettings{NameVal: "exampleexporter/queued", TypeVal: "exampleexporter"},
+					},
+					queued: true,
+				},
+			},
+			Pipelines: map[string]*configmodels.Pipeline{
+				"trace": {
+					Name:      "trace",
+					InputType: configmodels.TracesDataType,
+					Exporters: []string{"wrapping"},
+				},
+			},
+		}
+	}
+
+	// The exporters wrapped are built for the data types of the wrapping
+	// exporter, although they aren't in any pipeline.
+	cfg := newConfig("exampleexporter/2", "exampleexporter/1")
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	wrapping := exporters[cfg.Exporters["wrapping"]].tc.(*wrappingExporter)
+	require.Len(t, wrapping.wrapped, 2)
+	assert.NotNil(t, wrapping.wrapped[0])
+	assert.True(t, wrapping.wrapped[0] == exporters[cfg.Exporters["exampleexporter/2"]].tc)
+	assert.True(t, wrapping.wrapped[1] == exporters[cfg.Exporters["exampleexporter/1"]].tc)
+
+	_, err = NewExportersBuilder(zap.NewNop(), newConfig("exampleexporter/3"), exporterFactories).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+
+	_, err = NewExportersBuilder(zap.NewNop(), newConfig("exampleexporter/1", "wrapping"), exporterFactories).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wraps itself")
+
+	_, err = NewExportersBuilder(zap.NewNop(), newConfig("exampleexporter/1", "exampleexporter/queued"), exporterFactories).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must disable its sending-queue")
+}
+
 func Test_combineStopFunc(t *testing.T) {
 	f := combineStopFunc(nil, nil)
 	assert.Nil(t, f)
@@ -15,14 +15,22 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
+	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/debugbatches"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/oterr"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/processor/multiconsumer"
+	"github.com/open-telemetry/opentelemetry-service/storage"
 )
 
 // builtProcessor is a processor that is built based on a config.
@@ -30,18 +38,102 @@ import (
 type builtProcessor struct {
 	tc consumer.TraceConsumer
 	mc consumer.MetricsConsumer
+
+	// flushers are the processors of the pipeline that buffer data, in
+	// pipeline order.
+	flushers []processor.Flusher
+
+	// stoppers are the processors of the pipeline that work in the
+	// background, in pipeline order.
+	stoppers []processor.Stopper
+
+	// tunables are the processors of the pipeline whose configuration can be
+	// changed while the service runs, keyed by processor name.
+	tunables map[string]processor.Tunable
+
+	// readOnly is true if the pipeline never modifies the data: none of its
+	// processors and exporters declare mutating the data, see
+	// consumer.Capable, and it doesn't send the data to other pipelines. It
+	// can share the data with other pipelines instead of getting a copy.
+	readOnly bool
 }
 
 // PipelineProcessors is a map of entry-point processors created from pipeline configs.
 // Each element of the map points to the first processor of the pipeline.
 type PipelineProcessors map[*configmodels.Pipeline]*builtProcessor
 
+// FlushAll synchronously sends the data buffered by the processors, e.g.
+// batches, to the exporters. The processors of a pipeline are flushed in
+// order, so that the data flushed by one is flushed by the next ones too.
+func (pps PipelineProcessors) FlushAll(ctx context.Context) error {
+	var errs []error
+	for _, pp := range pps {
+		for _, f := range pp.flushers {
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return oterr.CombineErrors(errs)
+}
+
+// StopAll stops the processors working in the background, in pipeline
+// order. They must be flushed first.
+func (pps PipelineProcessors) StopAll() {
+	for _, pp := range pps {
+		for _, s := range pp.stoppers {
+			s.Stop()
+		}
+	}
+}
+
+// InFlight returns the number of items (spans or metrics) buffered by the
+// processors.
+func (pps PipelineProcessors) InFlight() int {
+	inFlight := 0
+	for _, pp := range pps {
+		for _, f := range pp.flushers {
+			inFlight += f.InFlight()
+		}
+	}
+	return inFlight
+}
+
+// Tunables returns the tunable processors of all the pipelines, keyed by
+// processor name. A processor used by several pipelines has an instance in
+// each of them.
+func (pps PipelineProcessors) Tunables() map[string][]processor.Tunable {
+	tunables := make(map[string][]processor.Tunable)
+	for _, pp := range pps {
+		for name, t := range pp.tunables {
+			tunables[name] = append(tunables[name], t)
+		}
+	}
+	return tunables
+}
+
+// TraceConsumer returns the first processor of the traces pipeline, nil if
+// the pipeline was not built.
+func (pps PipelineProcessors) TraceConsumer(pipeline *configmodels.Pipeline) consumer.TraceConsumer {
+	if pp := pps[pipeline]; pp != nil {
+		return pp.tc
+	}
+	return nil
+}
+
 // PipelinesBuilder builds pipelines from config.
 type PipelinesBuilder struct {
 	logger    *zap.Logger
 	config    *configmodels.Config
 	exporters Exporters
 	factories map[string]processor.Factory
+
+	// recorder records the batches received by each pipeline if not nil.
+	recorder *debugbatches.Recorder
+
+	storage storage.Storage
+
+	buildInfo buildinfo.BuildInfo
 }
 
 // NewPipelinesBuilder creates a new PipelinesBuilder. Requires exporters to be already
@@ -52,7 +144,35 @@ func NewPipelinesBuilder(
 	exporters Exporters,
 	factories map[string]processor.Factory,
 ) *PipelinesBuilder {
-	return &PipelinesBuilder{logger, config, exporters, factories}
+	return &PipelinesBuilder{
+		logger:    logger,
+		config:    config,
+		exporters: exporters,
+		factories: factories,
+		storage:   storage.NewMemoryStorage(),
+		buildInfo: buildinfo.Default(),
+	}
+}
+
+// WithBatchRecorder makes the built pipelines record the batches they
+// receive with the given recorder, before their first processor.
+func (pb *PipelinesBuilder) WithBatchRecorder(recorder *debugbatches.Recorder) *PipelinesBuilder {
+	pb.recorder = recorder
+	return pb
+}
+
+// WithStorage gives the processors persisting state their client of the
+// storage, by default their state is kept in memory.
+func (pb *PipelinesBuilder) WithStorage(st storage.Storage) *PipelinesBuilder {
+	pb.storage = st
+	return pb
+}
+
+// WithBuildInfo gives the processor factories using it the build of the
+// service, by default the build of otelsvc.
+func (pb *PipelinesBuilder) WithBuildInfo(bi buildinfo.BuildInfo) *PipelinesBuilder {
+	pb.buildInfo = bi
+	return pb
 }
 
 // Build pipeline processors from config.
@@ -67,9 +187,71 @@ func (pb *PipelinesBuilder) Build() (PipelineProcessors, error) {
 		pipelineProcessors[pipeline] = firstProcessor
 	}
 
+	if err := pb.connectConnectors(pipelineProcessors); err != nil {
+		return nil, err
+	}
 	return pipelineProcessors, nil
 }
 
+// connectConnectors connects the exporters that are connectors to the first
+// processor of the pipelines having them as a receiver.
+func (pb *PipelinesBuilder) connectConnectors(pipelineProcessors PipelineProcessors) error {
+	for cfg, exp := range pb.exporters {
+		if _, ok := cfg.(exporter.ConnectorConfig); !ok {
+			continue
+		}
+
+		var traces, metrics []*configmodels.Pipeline
+		for _, pipeline := range pb.config.Pipelines {
+			if !hasReceiver(pipeline, cfg.Name()) {
+				continue
+			}
+			switch pipeline.InputType {
+			case configmodels.TracesDataType:
+				traces = append(traces, pipeline)
+			case configmodels.MetricsDataType:
+				metrics = append(metrics, pipeline)
+			}
+		}
+		if len(traces)+len(metrics) == 0 {
+			continue
+		}
+		if exp.tc == nil && exp.mc == nil {
+			return fmt.Errorf("connector %q is a receiver of pipelines but not an exporter of any", cfg.Name())
+		}
+
+		// The exporter of any data type can be the one sending the data,
+		// e.g. a trace exporter aggregating the spans in metrics.
+		if len(traces) > 0 {
+			tc := obsreport.WrapTraceReceiver(cfg.Name(), buildFanoutTraceConsumer(cfg.Name(), traces, pipelineProcessors))
+			connected := false
+			for _, c := range []interface{}{exp.tc, exp.mc} {
+				if tcn, ok := c.(exporter.TraceConnector); ok {
+					tcn.SetTraceConsumer(tc)
+					connected = true
+				}
+			}
+			if !connected {
+				return fmt.Errorf("connector %q is a receiver of traces pipelines but does not send traces", cfg.Name())
+			}
+		}
+		if len(metrics) > 0 {
+			mc := obsreport.WrapMetricsReceiver(cfg.Name(), buildFanoutMetricConsumer(cfg.Name(), metrics, pipelineProcessors))
+			connected := false
+			for _, c := range []interface{}{exp.tc, exp.mc} {
+				if mcn, ok := c.(exporter.MetricsConnector); ok {
+					mcn.SetMetricsConsumer(mc)
+					connected = true
+				}
+			}
+			if !connected {
+				return fmt.Errorf("connector %q is a receiver of metrics pipelines but does not send metrics", cfg.Name())
+			}
+		}
+	}
+	return nil
+}
+
 // Builds a pipeline of processors. Returns the first processor in the pipeline.
 // The last processor in the pipeline will be plugged to fan out the data into exporters
 // that are configured for this pipeline.
@@ -82,10 +264,14 @@ func (pb *PipelinesBuilder) buildPipeline(
 	// First create a consumer junction point that fans out the data to all exporters.
 	var tc consumer.TraceConsumer
 	var mc consumer.MetricsConsumer
+	var flushers []processor.Flusher
+	var stoppers []processor.Stopper
+	tunables := make(map[string]processor.Tunable)
+	readOnly := !pb.hasConnector(pipelineCfg) && pb.exportersReadOnly(pipelineCfg)
 
 	switch pipelineCfg.InputType {
 	case configmodels.TracesDataType:
-		tc = pb.buildFanoutExportersTraceConsumer(pipelineCfg.Exporters)
+		tc = pb.untraced(pipelineCfg, pb.buildFanoutExportersTraceConsumer(pipelineCfg.Exporters))
 	case configmodels.MetricsDataType:
 		mc = pb.buildFanoutExportersMetricsConsumer(pipelineCfg.Exporters)
 	}
@@ -99,27 +285,179 @@ func (pb *PipelinesBuilder) buildPipeline(
 		procCfg := pb.config.Processors[procName]
 
 		factory := pb.factories[procCfg.Type()]
+		setBuildInfo(factory, pb.buildInfo)
 
 		// This processor must point to the next consumer and then
 		// it becomes the next for the previous one (previous in the pipeline,
 		// which we will build in the next loop iteration).
 		var err error
+		var created bool
+		var proc interface{}
+		logger := pb.logger.With(zap.String("processor", procName))
 		switch pipelineCfg.InputType {
 		case configmodels.TracesDataType:
-			tc, err = factory.CreateTraceProcessor(pb.logger, tc, procCfg)
+			tc, err = factory.CreateTraceProcessor(logger, tc, procCfg)
+			created = tc != nil
+			proc = tc
 		case configmodels.MetricsDataType:
-			mc, err = factory.CreateMetricsProcessor(pb.logger, mc, procCfg)
+			mc, err = factory.CreateMetricsProcessor(logger, mc, procCfg)
+			created = mc != nil
+			proc = mc
 		}
 
 		if err != nil {
+			if err == configerror.ErrDataTypeIsNotSupported {
+				return nil, fmt.Errorf("%s is a %s pipeline but has a processor %s which does not support %s",
+					pipelineCfg.Name, pipelineCfg.InputType.GetString(),
+					procName, pipelineCfg.InputType.GetString())
+			}
 			return nil, fmt.Errorf("error creating processor %q in pipeline %q: %v",
 				procName, pipelineCfg.Name, err)
 		}
+
+		// Do not silently wire a nil consumer into the pipeline, it would only
+		// fail when the first data arrives.
+		if !created {
+			return nil, fmt.Errorf("factory for processor %q returned a nil %s processor in pipeline %q",
+				procName, pipelineCfg.InputType.GetString(), pipelineCfg.Name)
+		}
+
+		if err := setStorageClient(pb.storage, proc, "processor", procName, pipelineCfg.Name); err != nil {
+			return nil, err
+		}
+
+		// The pipeline is built backwards, prepend to keep the pipeline order.
+		if f, ok := proc.(processor.Flusher); ok {
+			flushers = append([]processor.Flusher{f}, flushers...)
+		}
+		if s, ok := proc.(processor.Stopper); ok {
+			stoppers = append([]processor.Stopper{s}, stoppers...)
+		}
+		if t, ok := proc.(processor.Tunable); ok {
+			tunables[procName] = t
+		}
+		if err := pb.connectRouter(pipelineCfg, procName, proc); err != nil {
+			return nil, err
+		}
+		if consumer.GetCapabilities(proc).MutatesConsumedData {
+			readOnly = false
+		}
+
+		// Record the data accepted or refused by the processor, the wrapper
+		// hides the interfaces of the processor so it goes after the checks.
+		switch pipelineCfg.InputType {
+		case configmodels.TracesDataType:
+			tc = pb.untraced(pipelineCfg, obsreport.WrapTraceProcessor(procName, tc))
+		case configmodels.MetricsDataType:
+			mc = obsreport.WrapMetricsProcessor(procName, mc)
+		}
+	}
+
+	if pb.recorder != nil {
+		switch pipelineCfg.InputType {
+		case configmodels.TracesDataType:
+			tc = pb.recorder.WrapTraceConsumer(pipelineCfg.Name, tc)
+		case configmodels.MetricsDataType:
+			mc = pb.recorder.WrapMetricsConsumer(pipelineCfg.Name, mc)
+		}
 	}
 
 	pb.logger.Info("Pipeline is enabled.", zap.String("pipelines", pipelineCfg.Name))
 
-	return &builtProcessor{tc, mc}, nil
+	return &builtProcessor{
+		tc:       tc,
+		mc:       mc,
+		flushers: flushers,
+		stoppers: stoppers,
+		tunables: tunables,
+		readOnly: readOnly,
+	}, nil
+}
+
+// hasConnector returns true if some exporters of the pipeline are connectors.
+func (pb *PipelinesBuilder) hasConnector(pipelineCfg *configmodels.Pipeline) bool {
+	for _, name := range pipelineCfg.Exporters {
+		if _, ok := pb.config.Exporters[name].(exporter.ConnectorConfig); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exportersReadOnly returns true if none of the exporters of the pipeline
+// declare mutating the data.
+func (pb *PipelinesBuilder) exportersReadOnly(pipelineCfg *configmodels.Pipeline) bool {
+	for _, exp := range pb.getBuiltExportersByNames(pipelineCfg.Exporters) {
+		var c interface{} = exp.tc
+		if pipelineCfg.InputType == configmodels.MetricsDataType {
+			c = exp.mc
+		}
+		if consumer.GetCapabilities(c).MutatesConsumedData {
+			return false
+		}
+	}
+	return true
+}
+
+// connectRouter sets the exporters of the processor if it routes the data to
+// the exporters of its pipeline.
+func (pb *PipelinesBuilder) connectRouter(pipelineCfg *configmodels.Pipeline, procName string, proc interface{}) error {
+	var names []string
+	switch r := proc.(type) {
+	case processor.TraceRouter:
+		names = r.RoutedExporters()
+	case processor.MetricsRouter:
+		names = r.RoutedExporters()
+	default:
+		return nil
+	}
+
+	traceExporters := make(map[string]consumer.TraceConsumer)
+	metricsExporters := make(map[string]consumer.MetricsConsumer)
+	for _, name := range names {
+		if !containsString(pipelineCfg.Exporters, name) {
+			return fmt.Errorf("processor %q in pipeline %q routes to exporter %q which is not an exporter of the pipeline",
+				procName, pipelineCfg.Name, name)
+		}
+		exporter := pb.exporters[pb.config.Exporters[name]]
+		if exporter.tc != nil {
+			traceExporters[name] = pb.untraced(pipelineCfg, obsreport.WrapTraceExporter(name, exporter.tc))
+		}
+		if exporter.mc != nil {
+			metricsExporters[name] = obsreport.WrapMetricsExporter(name, exporter.mc)
+		}
+	}
+
+	switch pipelineCfg.InputType {
+	case configmodels.TracesDataType:
+		if r, ok := proc.(processor.TraceRouter); ok {
+			r.SetTraceExporters(traceExporters)
+		}
+	case configmodels.MetricsDataType:
+		if r, ok := proc.(processor.MetricsRouter); ok {
+			r.SetMetricsExporters(metricsExporters)
+		}
+	}
+	return nil
+}
+
+// untraced returns the consumer without tracing if the pipeline receives the
+// spans of the service, so that they do not generate more spans.
+func (pb *PipelinesBuilder) untraced(pipelineCfg *configmodels.Pipeline, tc consumer.TraceConsumer) consumer.TraceConsumer {
+	selfPipeline := pb.config.Telemetry.Traces.Pipeline
+	if selfPipeline == "" || pipelineCfg.Name != selfPipeline {
+		return tc
+	}
+	return obsreport.WithoutTracing(tc)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // Converts the list of exporter names to a list of corresponding builtExporters.
@@ -138,16 +476,23 @@ func (pb *PipelinesBuilder) buildFanoutExportersTraceConsumer(exporterNames []st
 
 	// Optimize for the case when there is only one exporter, no need to create junction point.
 	if len(builtExporters) == 1 {
-		return builtExporters[0].tc
+		return obsreport.WrapTraceExporter(exporterNames[0], builtExporters[0].tc)
 	}
 
-	var exporters []consumer.TraceConsumer
-	for _, builtExp := range builtExporters {
-		exporters = append(exporters, builtExp.tc)
+	var readOnly, mutating []consumer.TraceConsumer
+	for i, builtExp := range builtExporters {
+		exp := obsreport.WrapTraceExporter(exporterNames[i], builtExp.tc)
+		if consumer.GetCapabilities(builtExp.tc).MutatesConsumedData {
+			mutating = append(mutating, exp)
+		} else {
+			readOnly = append(readOnly, exp)
+		}
 	}
 
-	// Create a junction point that fans out to all exporters.
-	return multiconsumer.NewTraceProcessor(exporters)
+	// Create a junction point that fans out to all exporters. Each exporter
+	// that may modify the data gets its own copy of it, the other ones share
+	// the original data.
+	return multiconsumer.NewTraceProcessorSharing(readOnly, mutating)
 }
 
 func (pb *PipelinesBuilder) buildFanoutExportersMetricsConsumer(exporterNames []string) consumer.MetricsConsumer {
@@ -155,14 +500,21 @@ func (pb *PipelinesBuilder) buildFanoutExportersMetricsConsumer(exporterNames []
 
 	// Optimize for the case when there is only one exporter, no need to create junction point.
 	if len(builtExporters) == 1 {
-		return builtExporters[0].mc
+		return obsreport.WrapMetricsExporter(exporterNames[0], builtExporters[0].mc)
 	}
 
-	var exporters []consumer.MetricsConsumer
-	for _, builtExp := range builtExporters {
-		exporters = append(exporters, builtExp.mc)
+	var readOnly, mutating []consumer.MetricsConsumer
+	for i, builtExp := range builtExporters {
+		exp := obsreport.WrapMetricsExporter(exporterNames[i], builtExp.mc)
+		if consumer.GetCapabilities(builtExp.mc).MutatesConsumedData {
+			mutating = append(mutating, exp)
+		} else {
+			readOnly = append(readOnly, exp)
+		}
 	}
 
-	// Create a junction point that fans out to all exporters.
-	return multiconsumer.NewMetricsProcessor(exporters)
+	// Create a junction point that fans out to all exporters. Each exporter
+	// that may modify the data gets its own copy of it, the other ones share
+	// the original data.
+	return multiconsumer.NewMetricsProcessorSharing(readOnly, mutating)
 }
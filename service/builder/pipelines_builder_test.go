@@ -16,19 +16,27 @@ package builder
 
 import (
 	"context"
+	"sync"
 	"testing"
 
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal/debugbatches"
+	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/processor/addattributesprocessor"
+	"github.com/open-telemetry/opentelemetry-service/processor/groupbytrace"
+	"github.com/open-telemetry/opentelemetry-service/storage"
 )
 
 func TestPipelinesBuilder_Build(t *testing.T) {
@@ -147,5 +155,531 @@ func TestPipelinesBuilder_Error(t *testing.T) {
 	// not support metrics data type.
 	_, err = NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
 
-	assert.NotNil(t, err)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "does not support metrics")
+}
+
+// nilProcessorFactory is a processor factory that returns nil processors
+// without an error.
+type nilProcessorFactory struct {
+	addattributesprocessor.Factory
+}
+
+func (f *nilProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return nil, nil
+}
+
+func TestPipelinesBuilder_NilProcessor(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	nilFactory := &nilProcessorFactory{}
+	processorsFactories[nilFactory.Type()] = nilFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	assert.NoError(t, err)
+
+	// This should fail instead of wiring a nil consumer into the pipeline.
+	_, err = NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "returned a nil traces processor")
+}
+
+// bufferingProcessorFactory is a processor factory that creates processors
+// holding the spans until they are flushed, and that must be stopped.
+type bufferingProcessorFactory struct {
+	addattributesprocessor.Factory
+}
+
+func (f *bufferingProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return &bufferingProcessor{next: nextConsumer}, nil
+}
+
+type bufferingProcessor struct {
+	next     consumer.TraceConsumer
+	buffered []consumerdata.TraceData
+	stopped  bool
+}
+
+var _ processor.Flusher = (*bufferingProcessor)(nil)
+var _ processor.Stopper = (*bufferingProcessor)(nil)
+
+func (bp *bufferingProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	bp.buffered = append(bp.buffered, td)
+	return nil
+}
+
+func (bp *bufferingProcessor) Flush(ctx context.Context) error {
+	for _, td := range bp.buffered {
+		if err := bp.next.ConsumeTraceData(ctx, td); err != nil {
+			return err
+		}
+	}
+	bp.buffered = nil
+	return nil
+}
+
+func (bp *bufferingProcessor) Stop() {
+	bp.stopped = true
+}
+
+func (bp *bufferingProcessor) InFlight() int {
+	inFlight := 0
+	for _, td := range bp.buffered {
+		inFlight += len(td.Spans)
+	}
+	return inFlight
+}
+
+func TestPipelinesBuilder_FlushAll(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	bufferingFactory := &bufferingProcessorFactory{}
+	processorsFactories[bufferingFactory.Type()] = bufferingFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}, {}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces"]].tc.ConsumeTraceData(context.Background(), traceData))
+	exporter := exporters[cfg.Exporters["exampleexporter"]].tc.(*config.ExampleExporterConsumer)
+	assert.Equal(t, 0, len(exporter.Traces))
+	assert.Equal(t, 2, pipelineProcessors.InFlight())
+
+	require.NoError(t, pipelineProcessors.FlushAll(context.Background()))
+	assert.Equal(t, 1, len(exporter.Traces))
+	assert.Equal(t, 0, pipelineProcessors.InFlight())
+
+	pipelineProcessors.StopAll()
+	assert.True(t, pipelineProcessors[cfg.Pipelines["traces"]].stoppers[0].(*bufferingProcessor).stopped)
+}
+
+func TestPipelinesBuilder_StopAll(t *testing.T) {
+	_, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	require.NoError(t, err)
+	gbtFactory := &groupbytrace.Factory{}
+	processorsFactories[gbtFactory.Type()] = gbtFactory
+	cfg := &configmodels.Config{
+		Exporters: map[string]configmodels.Exporter{
+			"exampleexporter": &config.ExampleExporter{
+				ExporterSettings: configmodels.ExporterSettings{NameVal: "exampleexporter", TypeVal: "exampleexporter"},
+			},
+		},
+		Processors: map[string]configmodels.Processor{
+			"group-by-trace": gbtFactory.CreateDefaultConfig(),
+		},
+		Pipelines: map[string]*configmodels.Pipeline{
+			"traces": {
+				Name:       "traces",
+				InputType:  configmodels.TracesDataType,
+				Processors: []string{"group-by-trace"},
+				Exporters:  []string{"exampleexporter"},
+			},
+		},
+	}
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	// The processors releasing the data on a timer are stopped with the
+	// pipelines.
+	stoppers := pipelineProcessors[cfg.Pipelines["traces"]].stoppers
+	require.Len(t, stoppers, 1)
+	assert.Implements(t, (*consumer.TraceConsumer)(nil), stoppers[0])
+	pipelineProcessors.StopAll()
+}
+
+// routingProcessorFactory is a processor factory that creates processors
+// sending the traces to one exporter of the pipeline.
+type routingProcessorFactory struct {
+	addattributesprocessor.Factory
+	exporter string
+}
+
+func (f *routingProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return &routingProcessor{exporter: f.exporter}, nil
+}
+
+type routingProcessor struct {
+	exporter  string
+	exporters map[string]consumer.TraceConsumer
+}
+
+var _ processor.TraceRouter = (*routingProcessor)(nil)
+
+func (rp *routingProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return rp.exporters[rp.exporter].ConsumeTraceData(ctx, td)
+}
+
+func (rp *routingProcessor) RoutedExporters() []string {
+	return []string{rp.exporter}
+}
+
+func (rp *routingProcessor) SetTraceExporters(exporters map[string]consumer.TraceConsumer) {
+	rp.exporters = exporters
+}
+
+func TestPipelinesBuilder_Router(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	routingFactory := &routingProcessorFactory{exporter: "exampleexporter/2"}
+	processorsFactories[routingFactory.Type()] = routingFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	// The traces pipeline doesn't have exampleexporter/2.
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	_, err = NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not an exporter of the pipeline")
+
+	cfg.Pipelines["traces"].Processors = nil
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces/2"]].tc.ConsumeTraceData(
+		context.Background(), consumerdata.TraceData{}))
+	// Only the routed exporter of traces/2 got the traces.
+	assert.Equal(t, 0, len(exporters[cfg.Exporters["exampleexporter"]].tc.(*config.ExampleExporterConsumer).Traces))
+	assert.Equal(t, 1, len(exporters[cfg.Exporters["exampleexporter/2"]].tc.(*config.ExampleExporterConsumer).Traces))
+}
+
+// spanRecorder is a trace.Exporter keeping the names of the exported spans.
+type spanRecorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (sr *spanRecorder) ExportSpan(sd *trace.SpanData) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.names = append(sr.names, sd.Name)
+}
+
+func (sr *spanRecorder) reset() []string {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	names := sr.names
+	sr.names = nil
+	return names
+}
+
+// readOnlyProcessorFactory is a processor factory that creates processors
+// declaring they do not modify the data.
+type readOnlyProcessorFactory struct {
+	addattributesprocessor.Factory
+}
+
+func (f *readOnlyProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return &readOnlyProcessor{next: nextConsumer}, nil
+}
+
+type readOnlyProcessor struct {
+	next consumer.TraceConsumer
+}
+
+var _ consumer.Capable = (*readOnlyProcessor)(nil)
+
+func (rp *readOnlyProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return rp.next.ConsumeTraceData(ctx, td)
+}
+
+func (rp *readOnlyProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesConsumedData: false}
+}
+
+func TestPipelinesBuilder_Capabilities(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	readOnlyFactory := &readOnlyProcessorFactory{}
+	processorsFactories[readOnlyFactory.Type()] = readOnlyFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	// Neither the processor nor the exporters modify the data.
+	assert.True(t, pipelineProcessors[cfg.Pipelines["traces/2"]].readOnly)
+	assert.True(t, pipelineProcessors[cfg.Pipelines["metrics"]].readOnly)
+
+	// The read-only exporters share the data instead of getting a copy.
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces/2"]].tc.ConsumeTraceData(context.Background(), traceData))
+	traces := exporters[cfg.Exporters["exampleexporter"]].tc.(*config.ExampleExporterConsumer).Traces
+	traces2 := exporters[cfg.Exporters["exampleexporter/2"]].tc.(*config.ExampleExporterConsumer).Traces
+	require.Equal(t, 1, len(traces))
+	require.Equal(t, 1, len(traces2))
+	assert.True(t, traces[0].Spans[0] == traceData.Spans[0])
+	assert.True(t, traces2[0].Spans[0] == traceData.Spans[0])
+
+	// The processors not declaring their capabilities are assumed to modify
+	// the data.
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	pipelineProcessors, err = NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+	assert.False(t, pipelineProcessors[cfg.Pipelines["traces/2"]].readOnly)
+	assert.True(t, pipelineProcessors[cfg.Pipelines["metrics"]].readOnly)
+}
+
+// tunableProcessorFactory is a processor factory that creates processors
+// whose configuration can be changed while they run.
+type tunableProcessorFactory struct {
+	addattributesprocessor.Factory
+}
+
+func (f *tunableProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return &tunableProcessor{next: nextConsumer, cfg: cfg}, nil
+}
+
+type tunableProcessor struct {
+	next consumer.TraceConsumer
+	cfg  configmodels.Processor
+}
+
+var _ processor.Tunable = (*tunableProcessor)(nil)
+
+func (tp *tunableProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	return tp.next.ConsumeTraceData(ctx, td)
+}
+
+func (tp *tunableProcessor) Tune(cfg configmodels.Processor) error {
+	tp.cfg = cfg
+	return nil
+}
+
+func TestPipelinesBuilder_Tunables(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	tunableFactory := &tunableProcessorFactory{}
+	processorsFactories[tunableFactory.Type()] = tunableFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	// The processor has an instance in each of the traces pipelines.
+	tunables := pipelineProcessors.Tunables()
+	require.Equal(t, 1, len(tunables))
+	require.Equal(t, 2, len(tunables["add-attributes"]))
+	assert.True(t, tunables["add-attributes"][0] != tunables["add-attributes"][1])
+}
+
+func TestPipelinesBuilder_Connectors(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/connectors.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	// The data exported to the connector reaches the exporter of the
+	// connected pipelines.
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces"]].tc.ConsumeTraceData(context.Background(), traceData))
+	metricsData := consumerdata.MetricsData{Metrics: []*metricspb.Metric{{}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["metrics"]].mc.ConsumeMetricsData(context.Background(), metricsData))
+
+	exporter := exporters[cfg.Exporters["exampleexporter"]]
+	traces := exporter.tc.(*config.ExampleExporterConsumer).Traces
+	require.Equal(t, 1, len(traces))
+	assert.Len(t, traces[0].Spans, 1)
+	assert.Equal(t, 1, len(exporter.mc.(*config.ExampleExporterConsumer).Metrics))
+
+	// Only the pipelines without mutating processors nor connectors share the
+	// data of their receivers with other pipelines.
+	assert.False(t, pipelineProcessors[cfg.Pipelines["traces/connected"]].readOnly)
+	assert.False(t, pipelineProcessors[cfg.Pipelines["metrics"]].readOnly)
+	assert.True(t, pipelineProcessors[cfg.Pipelines["metrics/connected"]].readOnly)
+}
+
+func TestPipelinesBuilder_SelfTracing(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/self_tracing.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).Build()
+	require.NoError(t, err)
+
+	recorder := &spanRecorder{}
+	trace.RegisterExporter(recorder)
+	defer trace.UnregisterExporter(recorder)
+
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}}}
+	ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces"]].tc.ConsumeTraceData(ctx, traceData))
+	span.End()
+	assert.Equal(t, []string{"exporter/exampleexporter", "processor/add-attributes", "test"}, recorder.reset())
+
+	// The pipeline receiving the spans of the service does not trace them.
+	ctx, span = trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces/self"]].tc.ConsumeTraceData(ctx, traceData))
+	span.End()
+	assert.Equal(t, []string{"test"}, recorder.reset())
+}
+
+func TestPipelinesBuilder_BatchRecorder(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	recorder := debugbatches.NewRecorder(10, true)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).
+		WithBatchRecorder(recorder).
+		Build()
+	require.NoError(t, err)
+
+	pipelines := recorder.Pipelines()
+	assert.Len(t, pipelines, len(cfg.Pipelines))
+	for name := range cfg.Pipelines {
+		assert.Contains(t, pipelines, name)
+	}
+
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces"]].tc.ConsumeTraceData(context.Background(), traceData))
+
+	// The batch is recorded as received by the pipeline, before the
+	// attributes are added by its processor.
+	batches, ok := recorder.Batches("traces")
+	require.True(t, ok)
+	require.Len(t, batches, 1)
+	assert.Nil(t, batches[0].Traces.Spans[0].Attributes)
+	assert.NotNil(t, traceData.Spans[0].Attributes)
+}
+
+// storingProcessorFactory is a processor factory that creates processors
+// counting the spans in their storage.
+type storingProcessorFactory struct {
+	addattributesprocessor.Factory
+}
+
+func (f *storingProcessorFactory) CreateTraceProcessor(
+	logger *zap.Logger,
+	nextConsumer consumer.TraceConsumer,
+	cfg configmodels.Processor,
+) (processor.TraceProcessor, error) {
+	return &storingProcessor{next: nextConsumer}, nil
+}
+
+type storingProcessor struct {
+	next   consumer.TraceConsumer
+	client storage.Client
+}
+
+var _ storage.User = (*storingProcessor)(nil)
+
+func (sp *storingProcessor) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	count, err := sp.client.Get("spans")
+	if err != nil {
+		return err
+	}
+	if err := sp.client.Set("spans", append(count, make([]byte, len(td.Spans))...)); err != nil {
+		return err
+	}
+	return sp.next.ConsumeTraceData(ctx, td)
+}
+
+func (sp *storingProcessor) SetStorageClient(client storage.Client) error {
+	sp.client = client
+	return nil
+}
+
+func TestPipelinesBuilder_Storage(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	storingFactory := &storingProcessorFactory{}
+	processorsFactories[storingFactory.Type()] = storingFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	exporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	st := storage.NewMemoryStorage()
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, exporters, processorsFactories).
+		WithStorage(st).
+		Build()
+	require.NoError(t, err)
+
+	traceData := consumerdata.TraceData{Spans: []*tracepb.Span{{}, {}}}
+	require.NoError(t, pipelineProcessors[cfg.Pipelines["traces"]].tc.ConsumeTraceData(context.Background(), traceData))
+
+	// Each instance of the processor has its own namespace.
+	client, err := st.Client("processor/add-attributes/traces")
+	require.NoError(t, err)
+	count, err := client.Get("spans")
+	require.NoError(t, err)
+	assert.Len(t, count, 2)
+	client, err = st.Client("processor/add-attributes/traces/2")
+	require.NoError(t, err)
+	count, err = client.Get("spans")
+	require.NoError(t, err)
+	assert.Len(t, count, 0)
 }
@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// withAttributes returns a copy of the resource with the attributes set as
+// labels. The resource received is never modified, it can be shared by the
+// batches of a receiver.
+func withAttributes(resource *resourcepb.Resource, attributes map[string]string) *resourcepb.Resource {
+	attributed := &resourcepb.Resource{
+		Labels: make(map[string]string, len(resource.GetLabels())+len(attributes)),
+	}
+	if resource != nil {
+		attributed.Type = resource.Type
+		for k, v := range resource.Labels {
+			attributed.Labels[k] = v
+		}
+	}
+	for k, v := range attributes {
+		attributed.Labels[k] = v
+	}
+	return attributed
+}
+
+// attributesTraceConsumer sets the static attributes of a receiver on its
+// traces.
+type attributesTraceConsumer struct {
+	attributes map[string]string
+	next       consumer.TraceConsumer
+}
+
+var _ consumer.TraceConsumer = (*attributesTraceConsumer)(nil)
+
+func (atc *attributesTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	td.Resource = withAttributes(td.Resource, atc.attributes)
+	return atc.next.ConsumeTraceData(ctx, td)
+}
+
+// attributesMetricsConsumer sets the static attributes of a receiver on its
+// metrics.
+type attributesMetricsConsumer struct {
+	attributes map[string]string
+	next       consumer.MetricsConsumer
+}
+
+var _ consumer.MetricsConsumer = (*attributesMetricsConsumer)(nil)
+
+func (amc *attributesMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	md.Resource = withAttributes(md.Resource, amc.attributes)
+	return amc.next.ConsumeMetricsData(ctx, md)
+}
+
+// buildAttributesTraceConsumer returns the consumer setting the attributes on
+// the traces before sending them to next, next itself if there are none.
+func buildAttributesTraceConsumer(attributes map[string]string, next consumer.TraceConsumer) consumer.TraceConsumer {
+	if len(attributes) == 0 {
+		return next
+	}
+	return &attributesTraceConsumer{attributes: attributes, next: next}
+}
+
+// buildAttributesMetricsConsumer is the equivalent of
+// buildAttributesTraceConsumer for the metrics.
+func buildAttributesMetricsConsumer(attributes map[string]string, next consumer.MetricsConsumer) consumer.MetricsConsumer {
+	if len(attributes) == 0 {
+		return next
+	}
+	return &attributesMetricsConsumer{attributes: attributes, next: next}
+}
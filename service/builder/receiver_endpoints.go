@@ -0,0 +1,90 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+// endpointConflicts describes the other receivers listening on an endpoint
+// of the receiver, they are the likely cause of the receiver failing to
+// start.
+func (rcvs Receivers) endpointConflicts(cfg configmodels.Receiver) []string {
+	lr, ok := cfg.(configmodels.ListeningReceiver)
+	if !ok {
+		return nil
+	}
+
+	var conflicts []string
+	for other := range rcvs {
+		if other == cfg {
+			continue
+		}
+		olr, ok := other.(configmodels.ListeningReceiver)
+		if !ok {
+			continue
+		}
+		for _, endpoint := range lr.ListenEndpoints() {
+			for _, otherEndpoint := range olr.ListenEndpoints() {
+				if sameEndpoint(endpoint, otherEndpoint) {
+					conflicts = append(conflicts,
+						fmt.Sprintf("receiver %q listens on %q too", other.Name(), otherEndpoint))
+				}
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// sameEndpoint returns true if the endpoints can't be listened on by
+// different receivers: the same Unix domain socket, or the same port on
+// overlapping addresses.
+func sameEndpoint(a, b string) bool {
+	if strings.HasPrefix(a, "unix://") || strings.HasPrefix(b, "unix://") {
+		return a == b
+	}
+
+	hostA, portA, err := net.SplitHostPort(a)
+	if err != nil {
+		return false
+	}
+	hostB, portB, err := net.SplitHostPort(b)
+	if err != nil {
+		return false
+	}
+	// Port 0 picks a free port.
+	if portA != portB || portA == "0" {
+		return false
+	}
+	hostA, hostB = normalizeHost(hostA), normalizeHost(hostB)
+	return hostA == hostB || hostA == "" || hostB == ""
+}
+
+// normalizeHost returns "" for the hosts meaning any address.
+func normalizeHost(host string) string {
+	switch host {
+	case "0.0.0.0", "::":
+		return ""
+	case "localhost":
+		return "127.0.0.1"
+	}
+	return host
+}
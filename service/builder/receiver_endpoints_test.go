@@ -0,0 +1,93 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
+)
+
+// unboundReceiver fails to start as if its endpoint was already used.
+type unboundReceiver struct{}
+
+var _ receiver.TraceReceiver = (*unboundReceiver)(nil)
+
+func (r *unboundReceiver) TraceSource() string { return "unbound" }
+
+func (r *unboundReceiver) StartTraceReception(host receiver.Host) error {
+	return errors.New("address already in use")
+}
+
+func (r *unboundReceiver) StopTraceReception() error { return nil }
+
+func TestSameEndpoint(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "localhost:9411", b: "localhost:9411", want: true},
+		{a: "localhost:9411", b: "127.0.0.1:9411", want: true},
+		{a: "0.0.0.0:9411", b: "10.0.0.1:9411", want: true},
+		{a: ":9411", b: "localhost:9411", want: true},
+		{a: "[::]:9411", b: "localhost:9411", want: true},
+		{a: "10.0.0.2:9411", b: "10.0.0.1:9411", want: false},
+		{a: "localhost:9411", b: "localhost:9412", want: false},
+		{a: "localhost:0", b: "localhost:0", want: false},
+		{a: "unix:///tmp/a.sock", b: "unix:///tmp/a.sock", want: true},
+		{a: "unix:///tmp/a.sock", b: "unix:///tmp/b.sock", want: false},
+		{a: "unix:///tmp/a.sock", b: "localhost:9411", want: false},
+		{a: "invalid", b: "invalid", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sameEndpoint(tt.a, tt.b), "%s and %s", tt.a, tt.b)
+	}
+}
+
+func TestReceiversBuilder_StartAllEndpointConflict(t *testing.T) {
+	started := &configmodels.ReceiverSettings{NameVal: "zipkin", Endpoint: "localhost:9411"}
+	failing := &configmodels.ReceiverSettings{NameVal: "zipkin/2", Endpoint: "0.0.0.0:9411"}
+	other := &configmodels.ReceiverSettings{NameVal: "zipkin/3", Endpoint: "localhost:9412"}
+
+	receivers := Receivers{
+		failing: &builtReceiver{trace: &unboundReceiver{}},
+		started: &builtReceiver{trace: receivertest.NewFakeReceiver(nil, nil)},
+		other:   &builtReceiver{trace: receivertest.NewFakeReceiver(nil, nil)},
+	}
+	assert.Equal(t, []string{`receiver "zipkin" listens on "localhost:9411" too`}, receivers.endpointConflicts(failing))
+	assert.Empty(t, receivers.endpointConflicts(other))
+
+	err := receivers.StartAll(zap.NewNop(), receivertest.NewMockHost())
+	assert.EqualError(t, err,
+		`cannot start receiver "zipkin/2": address already in use (receiver "zipkin" listens on "localhost:9411" too)`)
+	receivers.StopAll()
+}
+
+func TestReceiversBuilder_StartAllError(t *testing.T) {
+	failing := &configmodels.ReceiverSettings{NameVal: "zipkin", Endpoint: "localhost:9411"}
+	receivers := Receivers{
+		failing: &builtReceiver{trace: &unboundReceiver{}},
+	}
+
+	err := receivers.StartAll(zap.NewNop(), receivertest.NewMockHost())
+	assert.EqualError(t, err, `cannot start receiver "zipkin": address already in use`)
+	receivers.StopAll()
+}
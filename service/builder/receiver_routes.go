@@ -0,0 +1,194 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"regexp"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+)
+
+// serviceNameAttribute is the attribute of a route matching the name of the
+// service of the node that sent the data.
+const serviceNameAttribute = "service.name"
+
+// routeMatcher matches the data of a route of a receiver.
+type routeMatcher struct {
+	attribute string
+	pattern   *regexp.Regexp
+}
+
+func newRouteMatcher(route configmodels.ReceiverRoute) (routeMatcher, error) {
+	// The whole value must match the pattern.
+	pattern, err := regexp.Compile("^(?:" + route.Pattern + ")$")
+	if err != nil {
+		return routeMatcher{}, err
+	}
+	return routeMatcher{attribute: route.Attribute, pattern: pattern}, nil
+}
+
+func (m routeMatcher) matches(node *commonpb.Node, resource *resourcepb.Resource) bool {
+	if m.attribute == serviceNameAttribute {
+		return m.pattern.MatchString(node.GetServiceInfo().GetName())
+	}
+	value, ok := resource.GetLabels()[m.attribute]
+	return ok && m.pattern.MatchString(value)
+}
+
+// receiverRoutes splits the pipelines of a receiver of a data type between
+// its routes. The routes without pipelines of the data type are ignored.
+type receiverRoutes struct {
+	matchers []routeMatcher
+	// pipelines are the pipelines of each route.
+	pipelines [][]*configmodels.Pipeline
+	// defaultPipelines are the pipelines not in any route, receiving the data
+	// matching no route.
+	defaultPipelines []*configmodels.Pipeline
+}
+
+func newReceiverRoutes(
+	routes []configmodels.ReceiverRoute,
+	pipelines []*configmodels.Pipeline,
+) (*receiverRoutes, error) {
+	rr := &receiverRoutes{}
+	routed := make(map[string]bool)
+	for _, route := range routes {
+		var routePipelines []*configmodels.Pipeline
+		for _, pipeline := range pipelines {
+			if containsString(route.Pipelines, pipeline.Name) {
+				routePipelines = append(routePipelines, pipeline)
+			}
+		}
+		for _, name := range route.Pipelines {
+			routed[name] = true
+		}
+		if len(routePipelines) == 0 {
+			continue
+		}
+
+		matcher, err := newRouteMatcher(route)
+		if err != nil {
+			return nil, err
+		}
+		rr.matchers = append(rr.matchers, matcher)
+		rr.pipelines = append(rr.pipelines, routePipelines)
+	}
+
+	for _, pipeline := range pipelines {
+		if !routed[pipeline.Name] {
+			rr.defaultPipelines = append(rr.defaultPipelines, pipeline)
+		}
+	}
+	return rr, nil
+}
+
+// routedTraceConsumer sends each batch to the consumer of the first route it
+// matches, or to the default one.
+type routedTraceConsumer struct {
+	matchers     []routeMatcher
+	routes       []consumer.TraceConsumer
+	defaultRoute consumer.TraceConsumer
+}
+
+var _ consumer.TraceConsumer = (*routedTraceConsumer)(nil)
+
+func (rtc *routedTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
+	for i, matcher := range rtc.matchers {
+		if matcher.matches(td.Node, td.Resource) {
+			return rtc.routes[i].ConsumeTraceData(ctx, td)
+		}
+	}
+	if rtc.defaultRoute == nil {
+		// All the pipelines of the receiver are in routes.
+		return nil
+	}
+	return rtc.defaultRoute.ConsumeTraceData(ctx, td)
+}
+
+// routedMetricsConsumer is the equivalent of routedTraceConsumer for the
+// metrics.
+type routedMetricsConsumer struct {
+	matchers     []routeMatcher
+	routes       []consumer.MetricsConsumer
+	defaultRoute consumer.MetricsConsumer
+}
+
+var _ consumer.MetricsConsumer = (*routedMetricsConsumer)(nil)
+
+func (rmc *routedMetricsConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
+	for i, matcher := range rmc.matchers {
+		if matcher.matches(md.Node, md.Resource) {
+			return rmc.routes[i].ConsumeMetricsData(ctx, md)
+		}
+	}
+	if rmc.defaultRoute == nil {
+		return nil
+	}
+	return rmc.defaultRoute.ConsumeMetricsData(ctx, md)
+}
+
+// buildRoutedTraceConsumer returns the consumer of a receiver sending the
+// traces to the pipelines of their route.
+func buildRoutedTraceConsumer(
+	receiver string,
+	routes []configmodels.ReceiverRoute,
+	pipelines []*configmodels.Pipeline,
+	pipelineProcessors PipelineProcessors,
+) (consumer.TraceConsumer, error) {
+	rr, err := newReceiverRoutes(routes, pipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	rtc := &routedTraceConsumer{matchers: rr.matchers}
+	for _, routePipelines := range rr.pipelines {
+		rtc.routes = append(rtc.routes,
+			buildFanoutTraceConsumer(receiver, routePipelines, pipelineProcessors))
+	}
+	if len(rr.defaultPipelines) > 0 {
+		rtc.defaultRoute = buildFanoutTraceConsumer(receiver, rr.defaultPipelines, pipelineProcessors)
+	}
+	return rtc, nil
+}
+
+// buildRoutedMetricsConsumer is the equivalent of buildRoutedTraceConsumer
+// for the metrics.
+func buildRoutedMetricsConsumer(
+	receiver string,
+	routes []configmodels.ReceiverRoute,
+	pipelines []*configmodels.Pipeline,
+	pipelineProcessors PipelineProcessors,
+) (consumer.MetricsConsumer, error) {
+	rr, err := newReceiverRoutes(routes, pipelines)
+	if err != nil {
+		return nil, err
+	}
+
+	rmc := &routedMetricsConsumer{matchers: rr.matchers}
+	for _, routePipelines := range rr.pipelines {
+		rmc.routes = append(rmc.routes,
+			buildFanoutMetricConsumer(receiver, routePipelines, pipelineProcessors))
+	}
+	if len(rr.defaultPipelines) > 0 {
+		rmc.defaultRoute = buildFanoutMetricConsumer(receiver, rr.defaultPipelines, pipelineProcessors)
+	}
+	return rmc, nil
+}
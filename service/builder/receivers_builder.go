@@ -17,15 +17,19 @@ package builder
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config/configerror"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/consumer"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
 	"github.com/open-telemetry/opentelemetry-service/oterr"
 	"github.com/open-telemetry/opentelemetry-service/processor/multiconsumer"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
+	"github.com/open-telemetry/opentelemetry-service/storage"
 )
 
 // builtReceiver is a receiver that is built based on a config. It can have
@@ -33,6 +37,13 @@ import (
 type builtReceiver struct {
 	trace   receiver.TraceReceiver
 	metrics receiver.MetricsReceiver
+
+	// rebuild creates the receiver again, the receivers cannot be started
+	// again once stopped.
+	rebuild       func() (*builtReceiver, error)
+	restartPolicy configmodels.RestartPolicy
+	host          *componentHost
+	state         componentState
 }
 
 // Stop the receiver.
@@ -75,35 +86,92 @@ func (rcv *builtReceiver) Start(host receiver.Host) error {
 	return oterr.CombineErrors(errors)
 }
 
+// restart starts a new receiver created from the same config, then stops
+// the receiver, after a fatal error. The TCP endpoints can be listened on by
+// both receivers meanwhile, see confignet.Listen, so that no connection is
+// refused during the restart. If the new receiver can't start while the old
+// one runs, e.g. the endpoints can't be shared, it is started after the old
+// one is stopped.
+func (rcv *builtReceiver) restart(host *componentHost) error {
+	old := &builtReceiver{trace: rcv.trace, metrics: rcv.metrics}
+	built, err := rcv.rebuild()
+	if err != nil {
+		return err
+	}
+	if err := built.Start(host); err == nil {
+		rcv.trace, rcv.metrics = built.trace, built.metrics
+		if err := old.Stop(); err != nil {
+			host.logger.Warn("Failed to stop the receiver", zap.Error(err))
+		}
+		return nil
+	}
+
+	// A receiver cannot be started again once stopped, create another one.
+	built.Stop()
+	if err := old.Stop(); err != nil {
+		host.logger.Warn("Failed to stop the receiver", zap.Error(err))
+	}
+	if built, err = rcv.rebuild(); err != nil {
+		return err
+	}
+	rcv.trace, rcv.metrics = built.trace, built.metrics
+	return rcv.Start(host)
+}
+
 // Receivers is a map of receivers created from receiver configs.
 type Receivers map[configmodels.Receiver]*builtReceiver
 
 // StopAll stops all receivers.
 func (rcvs Receivers) StopAll() {
 	for _, rcv := range rcvs {
+		rcv.host.stop()
 		rcv.Stop()
+		rcv.state.set(StateStopped, nil)
 	}
 }
 
-// StartAll starts all receivers.
+// StartAll starts all receivers. The fatal errors they report later are
+// handled according to the restart policy of the config, the host is told of
+// the ones that must shut the service down.
 func (rcvs Receivers) StartAll(logger *zap.Logger, host receiver.Host) error {
 	for cfg, rcv := range rcvs {
 		logger.Info("Receiver is starting...", zap.String("receiver", cfg.Name()))
 
-		if err := rcv.Start(host); err != nil {
-			return err
+		var restart func(host *componentHost) error
+		if rcv.rebuild != nil {
+			restart = rcv.restart
+		}
+		rcv.host = newComponentHost(host, logger, rcv.restartPolicy, "receiver", cfg.Name(), restart, &rcv.state)
+		if err := rcv.Start(rcv.host); err != nil {
+			rcv.state.set(StateError, err)
+			if conflicts := rcvs.endpointConflicts(cfg); len(conflicts) > 0 {
+				return fmt.Errorf("cannot start receiver %q: %v (%s)", cfg.Name(), err, strings.Join(conflicts, ", "))
+			}
+			return fmt.Errorf("cannot start receiver %q: %v", cfg.Name(), err)
 		}
+		rcv.state.set(StateRunning, nil)
 		logger.Info("Receiver is started.", zap.String("receiver", cfg.Name()))
 	}
 	return nil
 }
 
+// Statuses returns the status of the receivers by name.
+func (rcvs Receivers) Statuses() map[string]ComponentStatus {
+	statuses := make(map[string]ComponentStatus, len(rcvs))
+	for cfg, rcv := range rcvs {
+		statuses[cfg.Name()] = rcv.state.get()
+	}
+	return statuses
+}
+
 // ReceiversBuilder builds receivers from config.
 type ReceiversBuilder struct {
 	logger             *zap.Logger
 	config             *configmodels.Config
 	pipelineProcessors PipelineProcessors
 	factories          map[string]receiver.Factory
+	storage            storage.Storage
+	buildInfo          buildinfo.BuildInfo
 }
 
 // NewReceiversBuilder creates a new ReceiversBuilder. Call Build() on the returned value.
@@ -113,7 +181,21 @@ func NewReceiversBuilder(
 	pipelineProcessors PipelineProcessors,
 	factories map[string]receiver.Factory,
 ) *ReceiversBuilder {
-	return &ReceiversBuilder{logger, config, pipelineProcessors, factories}
+	return &ReceiversBuilder{logger, config, pipelineProcessors, factories, storage.NewMemoryStorage(), buildinfo.Default()}
+}
+
+// WithStorage gives the receivers persisting state their client of the
+// storage, by default their state is kept in memory.
+func (rb *ReceiversBuilder) WithStorage(st storage.Storage) *ReceiversBuilder {
+	rb.storage = st
+	return rb
+}
+
+// WithBuildInfo gives the receiver factories using it the build of the
+// service, by default the build of otelsvc.
+func (rb *ReceiversBuilder) WithBuildInfo(bi buildinfo.BuildInfo) *ReceiversBuilder {
+	rb.buildInfo = bi
+	return rb
 }
 
 // Build receivers from config.
@@ -142,7 +224,7 @@ func hasReceiver(pipeline *configmodels.Pipeline, receiverName string) bool {
 	return false
 }
 
-type attachedPipelines map[configmodels.DataType][]*builtProcessor
+type attachedPipelines map[configmodels.DataType][]*configmodels.Pipeline
 
 func (rb *ReceiversBuilder) findPipelinesToAttach(config configmodels.Receiver) (attachedPipelines, error) {
 	// A receiver may be attached to multiple pipelines. Pipelines may consume different
@@ -150,14 +232,13 @@ func (rb *ReceiversBuilder) findPipelinesToAttach(config configmodels.Receiver)
 	// attached to this receiver according to configuration.
 
 	pipelinesToAttach := make(attachedPipelines)
-	pipelinesToAttach[configmodels.TracesDataType] = make([]*builtProcessor, 0)
-	pipelinesToAttach[configmodels.MetricsDataType] = make([]*builtProcessor, 0)
+	pipelinesToAttach[configmodels.TracesDataType] = make([]*configmodels.Pipeline, 0)
+	pipelinesToAttach[configmodels.MetricsDataType] = make([]*configmodels.Pipeline, 0)
 
 	// Iterate over all pipelines.
 	for _, pipelineCfg := range rb.config.Pipelines {
-		// Get the first processor of the pipeline.
-		pipelineProcessor := rb.pipelineProcessors[pipelineCfg]
-		if pipelineProcessor == nil {
+		// The pipeline must be built.
+		if rb.pipelineProcessors[pipelineCfg] == nil {
 			return nil, fmt.Errorf("cannot find pipeline processor for pipeline %s",
 				pipelineCfg.Name)
 		}
@@ -166,7 +247,7 @@ func (rb *ReceiversBuilder) findPipelinesToAttach(config configmodels.Receiver)
 		if hasReceiver(pipelineCfg, config.Name()) {
 			// Yes, add it to the list of pipelines of corresponding data type.
 			pipelinesToAttach[pipelineCfg.InputType] =
-				append(pipelinesToAttach[pipelineCfg.InputType], pipelineProcessor)
+				append(pipelinesToAttach[pipelineCfg.InputType], pipelineCfg)
 		}
 	}
 
@@ -178,23 +259,56 @@ func (rb *ReceiversBuilder) attachReceiverToPipelines(
 	dataType configmodels.DataType,
 	config configmodels.Receiver,
 	rcv *builtReceiver,
-	pipelineProcessors []*builtProcessor,
+	pipelines []*configmodels.Pipeline,
 ) error {
 	// There are pipelines of the specified data type that must be attached to
 	// the receiver. Create the receiver of corresponding data type and make
 	// sure its output is fanned out to all attached pipelines.
+	var routes []configmodels.ReceiverRoute
+	if routed, ok := config.(configmodels.RoutedReceiver); ok {
+		routes = routed.ReceiverRoutes()
+	}
+	var attributes map[string]string
+	if attributed, ok := config.(configmodels.AttributedReceiver); ok {
+		attributes = attributed.ReceiverAttributes()
+	}
+
 	var err error
+	logger := rb.logger.With(zap.String("receiver", config.Name()))
 	switch dataType {
 	case configmodels.TracesDataType:
-		// First, create the fan out junction point.
-		junction := buildFanoutTraceConsumer(pipelineProcessors)
+		// First, create the fan out junction point, sending the data to the
+		// pipelines of its route, if any, and recording the data accepted or
+		// refused by the pipelines of the receiver.
+		var tc consumer.TraceConsumer
+		if len(routes) > 0 {
+			tc, err = buildRoutedTraceConsumer(config.Name(), routes, pipelines, rb.pipelineProcessors)
+			if err != nil {
+				return fmt.Errorf("cannot route receiver %s: %s", config.Name(), err.Error())
+			}
+		} else {
+			tc = buildFanoutTraceConsumer(config.Name(), pipelines, rb.pipelineProcessors)
+		}
+		// The attributes are set before routing, the routes can match them.
+		tc = buildAttributesTraceConsumer(attributes, tc)
+		junction := obsreport.WrapTraceReceiver(config.Name(), tc)
 
 		// Now create the receiver and tell it to send to the junction point.
-		rcv.trace, err = factory.CreateTraceReceiver(context.Background(), rb.logger, config, junction)
+		rcv.trace, err = factory.CreateTraceReceiver(context.Background(), logger, config, junction)
 
 	case configmodels.MetricsDataType:
-		junction := buildFanoutMetricConsumer(pipelineProcessors)
-		rcv.metrics, err = factory.CreateMetricsReceiver(rb.logger, config, junction)
+		var mc consumer.MetricsConsumer
+		if len(routes) > 0 {
+			mc, err = buildRoutedMetricsConsumer(config.Name(), routes, pipelines, rb.pipelineProcessors)
+			if err != nil {
+				return fmt.Errorf("cannot route receiver %s: %s", config.Name(), err.Error())
+			}
+		} else {
+			mc = buildFanoutMetricConsumer(config.Name(), pipelines, rb.pipelineProcessors)
+		}
+		mc = buildAttributesMetricsConsumer(attributes, mc)
+		junction := obsreport.WrapMetricsReceiver(config.Name(), mc)
+		rcv.metrics, err = factory.CreateMetricsReceiver(logger, config, junction)
 	}
 
 	if err != nil {
@@ -209,6 +323,20 @@ func (rb *ReceiversBuilder) attachReceiverToPipelines(
 		return fmt.Errorf("cannot create receiver %s: %s", config.Name(), err.Error())
 	}
 
+	if (dataType == configmodels.TracesDataType && rcv.trace == nil) ||
+		(dataType == configmodels.MetricsDataType && rcv.metrics == nil) {
+		return fmt.Errorf("factory for receiver %s returned a nil %s receiver without an error",
+			config.Name(), dataType.GetString())
+	}
+
+	var created interface{} = rcv.trace
+	if dataType == configmodels.MetricsDataType {
+		created = rcv.metrics
+	}
+	if err := setStorageClient(rb.storage, created, "receiver", config.Name(), dataType.GetString()); err != nil {
+		return err
+	}
+
 	rb.logger.Info("Receiver is enabled.",
 		zap.String("receiver", config.Name()), zap.String("datatype", dataType.GetString()))
 
@@ -228,7 +356,11 @@ func (rb *ReceiversBuilder) buildReceiver(config configmodels.Receiver) (*builtR
 	if factory == nil {
 		return nil, fmt.Errorf("receiver factory not found for type: %s", config.Type())
 	}
-	rcv := &builtReceiver{}
+	setBuildInfo(factory, rb.buildInfo)
+	rcv := &builtReceiver{
+		rebuild:       func() (*builtReceiver, error) { return rb.buildReceiver(config) },
+		restartPolicy: rb.config.RestartPolicy,
+	}
 
 	// Now we have list of pipelines broken down by data type. Iterate for each data type.
 	for dataType, pipelines := range pipelinesToAttach {
@@ -248,32 +380,61 @@ func (rb *ReceiversBuilder) buildReceiver(config configmodels.Receiver) (*builtR
 	return rcv, nil
 }
 
-func buildFanoutTraceConsumer(pipelineFrontProcessors []*builtProcessor) consumer.TraceConsumer {
-	// Optimize for the case when there is only one processor, no need to create junction point.
-	if len(pipelineFrontProcessors) == 1 {
-		return pipelineFrontProcessors[0].tc
+// buildFanoutTraceConsumer returns the consumer of a receiver sending the
+// traces to the first processor of its pipelines. The receiver is a single
+// instance whatever the number of pipelines.
+func buildFanoutTraceConsumer(
+	receiver string,
+	pipelines []*configmodels.Pipeline,
+	pipelineProcessors PipelineProcessors,
+) consumer.TraceConsumer {
+	// Optimize for the case when there is only one pipeline, no need to create junction point.
+	if len(pipelines) == 1 {
+		return pipelineProcessors[pipelines[0]].tc
 	}
 
-	var pipelineConsumers []consumer.TraceConsumer
-	for _, builtProc := range pipelineFrontProcessors {
-		pipelineConsumers = append(pipelineConsumers, builtProc.tc)
+	var readOnly, mutating []consumer.TraceConsumer
+	for _, pipeline := range pipelines {
+		// The receiver only sees the errors of all the pipelines combined,
+		// record the data dropped by each one.
+		tc := obsreport.WrapTracePipeline(receiver, pipeline.Name, pipelineProcessors[pipeline].tc)
+		if pipelineProcessors[pipeline].readOnly {
+			readOnly = append(readOnly, tc)
+		} else {
+			mutating = append(mutating, tc)
+		}
 	}
 
-	// Create a junction point that fans out to all pipelines.
-	return multiconsumer.NewTraceProcessor(pipelineConsumers)
+	// Create a junction point that fans out to all pipelines. Each pipeline
+	// that may modify the data gets its own copy of it, the other ones share
+	// the original data.
+	return multiconsumer.NewTraceProcessorSharing(readOnly, mutating)
 }
 
-func buildFanoutMetricConsumer(pipelineFrontProcessors []*builtProcessor) consumer.MetricsConsumer {
-	// Optimize for the case when there is only one processor, no need to create junction point.
-	if len(pipelineFrontProcessors) == 1 {
-		return pipelineFrontProcessors[0].mc
+// buildFanoutMetricConsumer is the equivalent of buildFanoutTraceConsumer for
+// the metrics.
+func buildFanoutMetricConsumer(
+	receiver string,
+	pipelines []*configmodels.Pipeline,
+	pipelineProcessors PipelineProcessors,
+) consumer.MetricsConsumer {
+	// Optimize for the case when there is only one pipeline, no need to create junction point.
+	if len(pipelines) == 1 {
+		return pipelineProcessors[pipelines[0]].mc
 	}
 
-	var pipelineConsumers []consumer.MetricsConsumer
-	for _, builtProc := range pipelineFrontProcessors {
-		pipelineConsumers = append(pipelineConsumers, builtProc.mc)
+	var readOnly, mutating []consumer.MetricsConsumer
+	for _, pipeline := range pipelines {
+		mc := obsreport.WrapMetricsPipeline(receiver, pipeline.Name, pipelineProcessors[pipeline].mc)
+		if pipelineProcessors[pipeline].readOnly {
+			readOnly = append(readOnly, mc)
+		} else {
+			mutating = append(mutating, mc)
+		}
 	}
 
-	// Create a junction point that fans out to all pipelines.
-	return multiconsumer.NewMetricsProcessor(pipelineConsumers)
+	// Create a junction point that fans out to all pipelines. Each pipeline
+	// that may modify the data gets its own copy of it, the other ones share
+	// the original data.
+	return multiconsumer.NewMetricsProcessorSharing(readOnly, mutating)
 }
@@ -23,13 +23,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 
 	"github.com/open-telemetry/opentelemetry-service/config"
 	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+	"github.com/open-telemetry/opentelemetry-service/consumer"
 	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
 	"github.com/open-telemetry/opentelemetry-service/processor/addattributesprocessor"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
 	"github.com/open-telemetry/opentelemetry-service/receiver/receivertest"
 )
 
@@ -205,6 +209,102 @@ func testReceivers(
 	}
 }
 
+func TestReceiversBuilder_Routes(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/receiver_routes.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	allExporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, allExporters, processorsFactories).Build()
+	require.NoError(t, err)
+	receivers, err := NewReceiversBuilder(zap.NewNop(), cfg, pipelineProcessors, receiverFactories).Build()
+	require.NoError(t, err)
+
+	receiver := receivers[cfg.Receivers["examplereceiver"]]
+	tc := receiver.trace.(*config.ExampleReceiverProducer).TraceConsumer
+	mc := receiver.metrics.(*config.ExampleReceiverProducer).MetricsConsumer
+
+	frontend := &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend-1"}}
+	acme := &resourcepb.Resource{Labels: map[string]string{"tenant": "acme"}}
+	spans := []*tracepb.Span{{}}
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), consumerdata.TraceData{Node: frontend, Spans: spans}))
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), consumerdata.TraceData{Resource: acme, Spans: spans}))
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), consumerdata.TraceData{Spans: spans}))
+
+	// The first route has no metrics pipeline, the metrics of the frontend
+	// go to the default pipeline.
+	metrics := []*metricspb.Metric{{}}
+	require.NoError(t, mc.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Node: frontend, Metrics: metrics}))
+	require.NoError(t, mc.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Resource: acme, Metrics: metrics}))
+
+	exported := func(name string) *config.ExampleExporterConsumer {
+		return allExporters[cfg.Exporters[name]].tc.(*config.ExampleExporterConsumer)
+	}
+	exportedMetrics := func(name string) *config.ExampleExporterConsumer {
+		return allExporters[cfg.Exporters[name]].mc.(*config.ExampleExporterConsumer)
+	}
+	require.Len(t, exported("exampleexporter/frontend").Traces, 1)
+	assert.Equal(t, frontend, exported("exampleexporter/frontend").Traces[0].Node)
+	require.Len(t, exported("exampleexporter/acme").Traces, 1)
+	assert.Equal(t, acme, exported("exampleexporter/acme").Traces[0].Resource)
+	require.Len(t, exported("exampleexporter").Traces, 1)
+	assert.Nil(t, exported("exampleexporter").Traces[0].Node)
+
+	require.Len(t, exportedMetrics("exampleexporter").Metrics, 1)
+	assert.Equal(t, frontend, exportedMetrics("exampleexporter").Metrics[0].Node)
+	require.Len(t, exportedMetrics("exampleexporter/acme").Metrics, 1)
+	assert.Equal(t, acme, exportedMetrics("exampleexporter/acme").Metrics[0].Resource)
+}
+
+func TestReceiversBuilder_Attributes(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/receiver_attributes.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	allExporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	require.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, allExporters, processorsFactories).Build()
+	require.NoError(t, err)
+	receivers, err := NewReceiversBuilder(zap.NewNop(), cfg, pipelineProcessors, receiverFactories).Build()
+	require.NoError(t, err)
+
+	receiver := receivers[cfg.Receivers["examplereceiver"]]
+	tc := receiver.trace.(*config.ExampleReceiverProducer).TraceConsumer
+	mc := receiver.metrics.(*config.ExampleReceiverProducer).MetricsConsumer
+
+	// The attributes override the labels sent by the clients, and are set
+	// before the data is routed.
+	other := &resourcepb.Resource{Type: "k8s", Labels: map[string]string{"tenant": "other", "pod": "p1"}}
+	require.NoError(t, tc.ConsumeTraceData(context.Background(), consumerdata.TraceData{Resource: other, Spans: []*tracepb.Span{{}}}))
+	require.NoError(t, mc.ConsumeMetricsData(context.Background(), consumerdata.MetricsData{Metrics: []*metricspb.Metric{{}}}))
+
+	traces := allExporters[cfg.Exporters["exampleexporter/acme"]].tc.(*config.ExampleExporterConsumer).Traces
+	require.Len(t, traces, 1)
+	assert.Equal(t, &resourcepb.Resource{
+		Type:   "k8s",
+		Labels: map[string]string{"tenant": "acme", "ingest.endpoint": "public", "pod": "p1"},
+	}, traces[0].Resource)
+	// The resource received is not modified.
+	assert.Equal(t, "other", other.Labels["tenant"])
+
+	metrics := allExporters[cfg.Exporters["exampleexporter"]].mc.(*config.ExampleExporterConsumer).Metrics
+	require.Len(t, metrics, 1)
+	assert.Equal(t, &resourcepb.Resource{
+		Labels: map[string]string{"tenant": "acme", "ingest.endpoint": "public"},
+	}, metrics[0].Resource)
+}
+
 func TestReceiversBuilder_DataTypeError(t *testing.T) {
 	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
 	assert.Nil(t, err)
@@ -233,6 +333,47 @@ func TestReceiversBuilder_DataTypeError(t *testing.T) {
 	assert.Nil(t, receivers)
 }
 
+// nilReceiverFactory is a receiver factory that returns nil trace receivers
+// without an error.
+type nilReceiverFactory struct {
+	config.ExampleReceiverFactory
+}
+
+func (f *nilReceiverFactory) CreateTraceReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.TraceConsumer,
+) (receiver.TraceReceiver, error) {
+	return nil, nil
+}
+
+func TestReceiversBuilder_NilReceiver(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := config.ExampleComponents()
+	assert.Nil(t, err)
+
+	nilFactory := &nilReceiverFactory{}
+	receiverFactories[nilFactory.Type()] = nilFactory
+	attrFactory := &addattributesprocessor.Factory{}
+	processorsFactories[attrFactory.Type()] = attrFactory
+	cfg, err := config.LoadConfigFile(
+		t, "testdata/pipelines_builder.yaml", receiverFactories, processorsFactories, exporterFactories,
+	)
+	require.Nil(t, err)
+
+	allExporters, err := NewExportersBuilder(zap.NewNop(), cfg, exporterFactories).Build()
+	assert.NoError(t, err)
+	pipelineProcessors, err := NewPipelinesBuilder(zap.NewNop(), cfg, allExporters, processorsFactories).Build()
+	assert.NoError(t, err)
+
+	// This should fail instead of starting a nil receiver.
+	receivers, err := NewReceiversBuilder(zap.NewNop(), cfg, pipelineProcessors, receiverFactories).Build()
+
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "returned a nil traces receiver")
+	assert.Nil(t, receivers)
+}
+
 func TestReceiversBuilder_StartAll(t *testing.T) {
 	receivers := make(Receivers)
 	rcvCfg := &configmodels.ReceiverSettings{}
@@ -0,0 +1,42 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-service/storage"
+)
+
+// setStorageClient gives the component the client of its namespace if it
+// persists state. The namespace is made of the kind and name of the component
+// and of the instance, a data type for receivers and exporters and a pipeline
+// for processors, e.g. "processor/batch/traces/2".
+func setStorageClient(st storage.Storage, component interface{}, kind string, names ...string) error {
+	user, ok := component.(storage.User)
+	if !ok {
+		return nil
+	}
+	namespace := kind + "/" + strings.Join(names, "/")
+	client, err := st.Client(namespace)
+	if err == nil {
+		err = user.SetStorageClient(client)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot set the storage of %s: %v", namespace, err)
+	}
+	return nil
+}
@@ -15,28 +15,116 @@
 package service
 
 import (
-	"flag"
-
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-)
 
-const (
-	logLevelCfg = "log-level"
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 )
 
-func loggerFlags(flags *flag.FlagSet) {
-	flags.String(logLevelCfg, "INFO", "Output level of logs (TRACE, DEBUG, INFO, WARN, ERROR, FATAL)")
+func newLogger(v *viper.Viper) (*zap.Logger, error) {
+	logs, err := config.LoadTelemetryLogs(v)
+	if err != nil {
+		return nil, err
+	}
+	return buildLogger(logs)
 }
 
-func newLogger(v *viper.Viper) (*zap.Logger, error) {
+// buildLogger creates the logger of the service. The loggers of the
+// components, with a "receiver", "processor" or "exporter" field set to the
+// name of the component, use the level configured for the component if any.
+func buildLogger(logs configmodels.TelemetryLogs) (*zap.Logger, error) {
 	var level zapcore.Level
-	err := (&level).UnmarshalText([]byte(v.GetString(logLevelCfg)))
+	if err := level.UnmarshalText([]byte(logs.Level)); err != nil {
+		return nil, err
+	}
+	componentLevels, err := parseComponentLevels(logs.Levels)
 	if err != nil {
 		return nil, err
 	}
+
+	// The logs are filtered by the level of their component, the underlying
+	// core must let the lowest level through.
+	minLevel := level
+	for _, levels := range componentLevels {
+		for _, l := range levels {
+			if l < minLevel {
+				minLevel = l
+			}
+		}
+	}
+
 	conf := zap.NewProductionConfig()
-	conf.Level.SetLevel(level)
-	return conf.Build()
+	conf.Level = zap.NewAtomicLevelAt(minLevel)
+	conf.Encoding = logs.Encoding
+	if logs.Encoding == "console" {
+		conf.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	conf.OutputPaths = logs.OutputPaths
+	conf.Sampling = nil
+	if logs.Sampling.Initial > 0 {
+		conf.Sampling = &zap.SamplingConfig{
+			Initial:    logs.Sampling.Initial,
+			Thereafter: logs.Sampling.Thereafter,
+		}
+	}
+	return conf.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &componentLevelCore{Core: core, level: level, levels: componentLevels}
+	}))
+}
+
+// parseComponentLevels returns the levels of the components by key of the
+// field holding their name and by name.
+func parseComponentLevels(levels configmodels.TelemetryLogsLevels) (map[string]map[string]zapcore.Level, error) {
+	componentLevels := make(map[string]map[string]zapcore.Level)
+	for key, names := range map[string]map[string]string{
+		"receiver":  levels.Receivers,
+		"processor": levels.Processors,
+		"exporter":  levels.Exporters,
+	} {
+		for name, text := range names {
+			var l zapcore.Level
+			if err := l.UnmarshalText([]byte(text)); err != nil {
+				return nil, err
+			}
+			if componentLevels[key] == nil {
+				componentLevels[key] = make(map[string]zapcore.Level)
+			}
+			componentLevels[key][name] = l
+		}
+	}
+	return componentLevels, nil
+}
+
+// componentLevelCore filters the logs by level, the level changes when a
+// field naming a component with a configured level is added.
+type componentLevelCore struct {
+	zapcore.Core
+	level  zapcore.Level
+	levels map[string]map[string]zapcore.Level
+}
+
+func (c *componentLevelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
+}
+
+func (c *componentLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	level := c.level
+	for _, f := range fields {
+		if f.Type != zapcore.StringType {
+			continue
+		}
+		if l, ok := c.levels[f.Key][f.String]; ok {
+			level = l
+		}
+	}
+	return &componentLevelCore{Core: c.Core.With(fields), level: level, levels: c.levels}
+}
+
+func (c *componentLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
 }
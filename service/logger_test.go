@@ -0,0 +1,71 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
+)
+
+func TestBuildLogger_ComponentLevels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "otelsvc.log")
+
+	logger, err := buildLogger(configmodels.TelemetryLogs{
+		Level:       "warn",
+		Encoding:    "console",
+		OutputPaths: []string{path},
+		Levels: configmodels.TelemetryLogsLevels{
+			Exporters: map[string]string{"jaeger-grpc": "debug"},
+		},
+	})
+	require.NoError(t, err)
+
+	logger.Info("service info")
+	logger.Warn("service warn")
+	logger.With(zap.String("exporter", "jaeger-grpc")).Debug("jaeger debug")
+	logger.With(zap.String("exporter", "zipkin")).Info("zipkin info")
+	logger.With(zap.String("receiver", "jaeger-grpc")).Info("receiver info")
+	logger.Sync()
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	logs := string(b)
+	assert.False(t, strings.Contains(logs, "service info"))
+	assert.True(t, strings.Contains(logs, "service warn"))
+	assert.True(t, strings.Contains(logs, "jaeger debug"))
+	assert.False(t, strings.Contains(logs, "zipkin info"))
+	assert.False(t, strings.Contains(logs, "receiver info"))
+}
+
+func TestBuildLogger_InvalidLevel(t *testing.T) {
+	_, err := buildLogger(configmodels.TelemetryLogs{
+		Level:       "verbose",
+		Encoding:    "json",
+		OutputPaths: []string{"stderr"},
+	})
+	assert.Error(t, err)
+}
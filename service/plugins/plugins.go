@@ -0,0 +1,104 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugins loads the factories of receivers, processors and exporters
+// from Go plugins, so that third-party components can be added to the service
+// without building it again.
+//
+// A plugin is a main package built with "go build -buildmode=plugin" against
+// the same version of the service and of its dependencies. It exports a
+// function named Factories returning its factories:
+//
+//	func Factories() ([]receiver.Factory, []processor.Factory, []exporter.Factory) {
+//		return nil, nil, []exporter.Factory{&myexporter.Factory{}}
+//	}
+package plugins
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+// FactoriesSymbol is the name of the function exported by the plugins.
+const FactoriesSymbol = "Factories"
+
+// Load opens the plugins at the given paths and adds their factories to the
+// given maps. A factory of a type already in the maps is an error.
+func Load(
+	paths []string,
+	receivers map[string]receiver.Factory,
+	processors map[string]processor.Factory,
+	exporters map[string]exporter.Factory,
+) error {
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return fmt.Errorf("cannot open plugin %q: %v", path, err)
+		}
+		sym, err := p.Lookup(FactoriesSymbol)
+		if err != nil {
+			return fmt.Errorf("cannot load plugin %q: %v", path, err)
+		}
+		factories, ok := sym.(func() ([]receiver.Factory, []processor.Factory, []exporter.Factory))
+		if !ok {
+			return fmt.Errorf("cannot load plugin %q: %s is a %T, not a func() "+
+				"([]receiver.Factory, []processor.Factory, []exporter.Factory)", path, FactoriesSymbol, sym)
+		}
+		if err := add(factories, receivers, processors, exporters); err != nil {
+			return fmt.Errorf("cannot load plugin %q: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// add adds the factories returned by the function of a plugin to the maps.
+func add(
+	factories func() ([]receiver.Factory, []processor.Factory, []exporter.Factory),
+	receivers map[string]receiver.Factory,
+	processors map[string]processor.Factory,
+	exporters map[string]exporter.Factory,
+) error {
+	rcvs, procs, exps := factories()
+	for _, f := range rcvs {
+		if _, ok := receivers[f.Type()]; ok {
+			return fmt.Errorf("duplicate receiver factory %q", f.Type())
+		}
+	}
+	for _, f := range procs {
+		if _, ok := processors[f.Type()]; ok {
+			return fmt.Errorf("duplicate processor factory %q", f.Type())
+		}
+	}
+	for _, f := range exps {
+		if _, ok := exporters[f.Type()]; ok {
+			return fmt.Errorf("duplicate exporter factory %q", f.Type())
+		}
+	}
+
+	// The maps are only changed once all the factories are known to be new.
+	for _, f := range rcvs {
+		receivers[f.Type()] = f
+	}
+	for _, f := range procs {
+		processors[f.Type()] = f
+	}
+	for _, f := range exps {
+		exporters[f.Type()] = f
+	}
+	return nil
+}
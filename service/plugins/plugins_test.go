@@ -0,0 +1,57 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/processor"
+	"github.com/open-telemetry/opentelemetry-service/receiver"
+)
+
+func TestLoad_NotFound(t *testing.T) {
+	err := Load([]string{"testdata/not-found.so"}, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestAdd(t *testing.T) {
+	receivers := map[string]receiver.Factory{}
+	processors := map[string]processor.Factory{}
+	exporters := map[string]exporter.Factory{}
+
+	factories := func() ([]receiver.Factory, []processor.Factory, []exporter.Factory) {
+		return []receiver.Factory{&config.ExampleReceiverFactory{}},
+			[]processor.Factory{&config.ExampleProcessorFactory{}},
+			[]exporter.Factory{&config.ExampleExporterFactory{}}
+	}
+	require.NoError(t, add(factories, receivers, processors, exporters))
+	assert.Equal(t, map[string]receiver.Factory{"examplereceiver": &config.ExampleReceiverFactory{}}, receivers)
+	assert.Equal(t, map[string]processor.Factory{"exampleprocessor": &config.ExampleProcessorFactory{}}, processors)
+	assert.Equal(t, map[string]exporter.Factory{"exampleexporter": &config.ExampleExporterFactory{}}, exporters)
+
+	// Nothing is added when one of the factories is a duplicate.
+	duplicate := func() ([]receiver.Factory, []processor.Factory, []exporter.Factory) {
+		return []receiver.Factory{&config.MultiProtoReceiverFactory{}},
+			nil,
+			[]exporter.Factory{&config.ExampleExporterFactory{}}
+	}
+	assert.Error(t, add(duplicate, receivers, processors, exporters))
+	assert.Len(t, receivers, 1)
+}
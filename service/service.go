@@ -18,6 +18,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -27,25 +30,84 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/buildinfo"
 	"github.com/open-telemetry/opentelemetry-service/config"
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/exporter"
+	"github.com/open-telemetry/opentelemetry-service/internal/activepassive"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/memorybudget"
+	"github.com/open-telemetry/opentelemetry-service/internal/collector/runtimelimits"
 	"github.com/open-telemetry/opentelemetry-service/internal/config/viperutils"
+	"github.com/open-telemetry/opentelemetry-service/internal/debugbatches"
 	"github.com/open-telemetry/opentelemetry-service/internal/pprofserver"
+	"github.com/open-telemetry/opentelemetry-service/internal/status"
+	"github.com/open-telemetry/opentelemetry-service/internal/tuning"
+	"github.com/open-telemetry/opentelemetry-service/internal/version"
 	"github.com/open-telemetry/opentelemetry-service/internal/zpagesserver"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
 	"github.com/open-telemetry/opentelemetry-service/processor"
 	"github.com/open-telemetry/opentelemetry-service/receiver"
 	"github.com/open-telemetry/opentelemetry-service/service/builder"
+	"github.com/open-telemetry/opentelemetry-service/service/plugins"
+	"github.com/open-telemetry/opentelemetry-service/storage"
 )
 
+// ApplicationStartInfo is the information of the distribution of the
+// service shown on the command line and logged on start.
+type ApplicationStartInfo struct {
+	// ExeName is the name of the executable, e.g. "otelsvc".
+	ExeName string
+
+	// LongName is the full name of the distribution, e.g.
+	// "OpenTelemetry Service".
+	LongName string
+
+	// Version of the distribution.
+	Version string
+
+	// GitHash of the source the distribution was built from.
+	GitHash string
+
+	// BuildDate is when the distribution was built.
+	BuildDate string
+}
+
+// Parameters holds the components and information of a distribution of the
+// service. Distributions bundling their own components call New with them
+// instead of forking the main function of otelsvc.
+type Parameters struct {
+	ReceiverFactories  map[string]receiver.Factory
+	ProcessorFactories map[string]processor.Factory
+	ExporterFactories  map[string]exporter.Factory
+
+	// ApplicationStartInfo of the distribution, the fields left empty are
+	// the ones of otelsvc.
+	ApplicationStartInfo ApplicationStartInfo
+
+	// Logger used by the service and its components. If nil the logger is
+	// built from the telemetry section of the config.
+	Logger *zap.Logger
+}
+
 // Application represents a collector application
 type Application struct {
+	info           ApplicationStartInfo
 	v              *viper.Viper
 	logger         *zap.Logger
+	config         *configmodels.Config
 	healthCheck    *healthcheck.HealthCheck
 	exporters      builder.Exporters
+	builtPipelines builder.PipelineProcessors
 	builtReceivers builder.Receivers
+	activePassive  *activepassive.Coordinator
+	spanExporter   *obsreport.SpanExporter
+
+	// batchRecorder records the last batches of the pipelines for the debug
+	// endpoint, nil if it is disabled.
+	batchRecorder *debugbatches.Recorder
 
 	// factories
 	receiverFactories  map[string]receiver.Factory
@@ -67,6 +129,7 @@ type Application struct {
 }
 
 var _ receiver.Host = (*Application)(nil)
+var _ exporter.Host = (*Application)(nil)
 
 // Context returns a context provided by the host to be used on the receiver
 // operations.
@@ -75,26 +138,56 @@ func (app *Application) Context() context.Context {
 	return context.Background()
 }
 
-// ReportFatalError is used to report to the host that the receiver encountered
-// a fatal error (i.e.: an error that the instance can't recover from) after
-// its start function has already returned.
+// ReportFatalError is used to report to the host that a receiver or an
+// exporter encountered a fatal error (i.e.: an error that the instance can't
+// recover from) after its start function has already returned, and that the
+// restart policy says the service must shut down.
 func (app *Application) ReportFatalError(err error) {
 	app.asyncErrorChannel <- err
 }
 
-// New creates and returns a new instance of Application
-func New(
-	receiverFactories map[string]receiver.Factory,
-	processorFactories map[string]processor.Factory,
-	exporterFactories map[string]exporter.Factory,
-) *Application {
+// New creates and returns a new instance of Application running the
+// components of the given parameters.
+func New(params Parameters) (*Application, error) {
+	if len(params.ReceiverFactories) == 0 {
+		return nil, errors.New("no receiver factories, at least one is needed to build a pipeline")
+	}
+	if len(params.ExporterFactories) == 0 {
+		return nil, errors.New("no exporter factories, at least one is needed to build a pipeline")
+	}
+
+	info := params.ApplicationStartInfo
+	if info.ExeName == "" {
+		info.ExeName = "otelsvc"
+	}
+	if info.LongName == "" {
+		info.LongName = "OpenTelemetry Service"
+	}
+	if info.Version == "" {
+		info.Version = version.Version
+	}
+	if info.GitHash == "" {
+		info.GitHash = version.GitHash
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = version.BuildDate
+	}
+
+	processorFactories := params.ProcessorFactories
+	if processorFactories == nil {
+		// The plugins can add processors to the map.
+		processorFactories = map[string]processor.Factory{}
+	}
+
 	return &Application{
+		info:               info,
 		v:                  viper.New(),
+		logger:             params.Logger,
 		readyChan:          make(chan struct{}),
-		receiverFactories:  receiverFactories,
+		receiverFactories:  params.ReceiverFactories,
 		processorFactories: processorFactories,
-		exporterFactories:  exporterFactories,
-	}
+		exporterFactories:  params.ExporterFactories,
+	}, nil
 }
 
 func (app *Application) init() {
@@ -107,12 +200,26 @@ func (app *Application) init() {
 	if err != nil {
 		log.Fatalf("Error loading config file %q: %v", file, err)
 	}
+	app.loadPlugins()
+	if app.logger != nil {
+		// The logger was given by the distribution.
+		return
+	}
 	app.logger, err = newLogger(app.v)
 	if err != nil {
 		log.Fatalf("Failed to get logger: %v", err)
 	}
 }
 
+// loadPlugins adds the factories of the plugins given on the command line to
+// the ones of the distribution.
+func (app *Application) loadPlugins() {
+	err := plugins.Load(builder.Plugins(app.v), app.receiverFactories, app.processorFactories, app.exporterFactories)
+	if err != nil {
+		log.Fatalf("Failed to load plugins: %v", err)
+	}
+}
+
 func (app *Application) setupPProf() {
 	app.logger.Info("Setting up profiler...")
 	err := pprofserver.SetupFromViper(app.asyncErrorChannel, app.v, app.logger)
@@ -148,9 +255,69 @@ func (app *Application) setupZPages() {
 	}
 }
 
+// setupDebugBatches serves the last batches of the pipelines on the endpoint
+// of the debug section, if any.
+func (app *Application) setupDebugBatches() {
+	debug := app.config.Debug
+	if debug.Endpoint == "" {
+		return
+	}
+	app.batchRecorder = debugbatches.NewRecorder(debug.Batches, debug.Recording)
+	closeDebugBatches, err := debugbatches.Run(app.asyncErrorChannel, debug.Endpoint, app.batchRecorder)
+	if err != nil {
+		app.logger.Error("Failed to run the debug batches endpoint", zap.Error(err))
+		os.Exit(1)
+	}
+	app.logger.Info("Running the debug batches endpoint",
+		zap.String("endpoint", debug.Endpoint), zap.Bool("recording", debug.Recording))
+	app.closeFns = append(app.closeFns, func() {
+		closeDebugBatches()
+	})
+}
+
+// setupStatus serves the status of the pipelines and of their components on
+// the endpoint of the status section, if any.
+func (app *Application) setupStatus() {
+	endpoint := app.config.Status.Endpoint
+	if endpoint == "" {
+		return
+	}
+	closeStatus, err := status.Run(app.asyncErrorChannel, endpoint, func() status.Status {
+		return status.New(app.config, app.builtReceivers.Statuses(), app.exporters.Statuses(), obsreport.ReadCounts())
+	})
+	if err != nil {
+		app.logger.Error("Failed to run the status endpoint", zap.Error(err))
+		os.Exit(1)
+	}
+	app.logger.Info("Running the status endpoint", zap.String("endpoint", endpoint))
+	app.closeFns = append(app.closeFns, func() {
+		closeStatus()
+	})
+}
+
+// setupTuning serves the configuration of the tunable processors, e.g. the
+// sampling processors, on the endpoint of the tuning section, if any.
+func (app *Application) setupTuning() {
+	settings := app.config.Tuning
+	if settings.Endpoint == "" {
+		return
+	}
+	tuner := tuning.NewTuner(app.logger, app.processorFactories, app.config.Processors, app.builtPipelines.Tunables())
+	closeTuning, err := tuning.Run(app.asyncErrorChannel, settings, tuner)
+	if err != nil {
+		app.logger.Error("Failed to run the tuning endpoint", zap.Error(err))
+		os.Exit(1)
+	}
+	app.logger.Info("Running the tuning endpoint",
+		zap.String("endpoint", settings.Endpoint), zap.Strings("processors", tuner.Names()))
+	app.closeFns = append(app.closeFns, func() {
+		closeTuning()
+	})
+}
+
 func (app *Application) setupTelemetry(ballastSizeBytes uint64) {
 	app.logger.Info("Setting up own telemetry...")
-	err := AppTelemetry.init(app.asyncErrorChannel, ballastSizeBytes, app.v, app.logger)
+	err := AppTelemetry.init(app.asyncErrorChannel, ballastSizeBytes, app.config.Telemetry, app.logger)
 	if err != nil {
 		app.logger.Error("Failed to initialize telemetry", zap.Error(err))
 		os.Exit(1)
@@ -189,39 +356,87 @@ func (app *Application) shutdownClosableComponents() {
 	}
 }
 
-func (app *Application) setupPipelines() {
+func (app *Application) loadConfig() {
 	app.logger.Info("Loading configuration...")
 
-	// Load configuration.
-	cfg, err := config.Load(app.v, app.receiverFactories, app.processorFactories, app.exporterFactories, app.logger)
+	var err error
+	app.config, err = config.Load(app.v, app.receiverFactories, app.processorFactories, app.exporterFactories, app.logger)
 	if err != nil {
 		log.Fatalf("Cannot load configuration: %v", err)
 	}
+}
 
+func (app *Application) setupPipelines() {
 	app.logger.Info("Applying configuration...")
 
+	cfg := app.config
+
 	// Pipeline is built backwards, starting from exporters, so that we create objects
 	// which are referenced before objects which reference them.
 
+	// The state of the components is kept in memory unless a storage
+	// directory is configured.
+	var err error
+	st := storage.NewMemoryStorage()
+	if cfg.Storage.Directory != "" {
+		st, err = storage.NewFileStorage(cfg.Storage.Directory)
+		if err != nil {
+			log.Fatalf("Cannot open the storage: %v", err)
+		}
+		app.logger.Info("Persisting the state of the components", zap.String("directory", cfg.Storage.Directory))
+	}
+
 	// First create exporters.
-	app.exporters, err = builder.NewExportersBuilder(app.logger, cfg, app.exporterFactories).Build()
+	app.exporters, err = builder.NewExportersBuilder(app.logger, cfg, app.exporterFactories).
+		WithStorage(st).
+		WithBuildInfo(app.buildInfo()).
+		Build()
 	if err != nil {
 		log.Fatalf("Cannot load configuration: %v", err)
 	}
 
 	// Create pipelines and their processors and plug exporters to the
 	// end of the pipelines.
-	pipelines, err := builder.NewPipelinesBuilder(app.logger, cfg, app.exporters, app.processorFactories).Build()
+	app.builtPipelines, err = builder.NewPipelinesBuilder(app.logger, cfg, app.exporters, app.processorFactories).
+		WithBatchRecorder(app.batchRecorder).
+		WithStorage(st).
+		WithBuildInfo(app.buildInfo()).
+		Build()
 	if err != nil {
 		log.Fatalf("Cannot load configuration: %v", err)
 	}
 
+	app.logger.Info("Starting exporters...")
+	err = app.exporters.StartAll(app.logger, app)
+	if err != nil {
+		log.Fatalf("Cannot start exporters: %v", err)
+	}
+
 	// Create receivers and plug them into the start of the pipelines.
-	app.builtReceivers, err = builder.NewReceiversBuilder(app.logger, cfg, pipelines, app.receiverFactories).Build()
+	app.builtReceivers, err = builder.NewReceiversBuilder(app.logger, cfg, app.builtPipelines, app.receiverFactories).
+		WithStorage(st).
+		WithBuildInfo(app.buildInfo()).
+		Build()
 	if err != nil {
 		log.Fatalf("Cannot load configuration: %v", err)
 	}
 
+	app.activePassive, err = activepassive.NewFromViper(app.v, app.logger)
+	if err != nil {
+		log.Fatalf("Cannot configure the active/passive pair: %v", err)
+	}
+	if app.activePassive != nil {
+		// The receivers are started only once the collector is active.
+		err = app.activePassive.Start(app.asyncErrorChannel, func() error {
+			app.logger.Info("Starting receivers...")
+			return app.builtReceivers.StartAll(app.logger, app)
+		})
+		if err != nil {
+			log.Fatalf("Cannot start receivers: %v", err)
+		}
+		return
+	}
+
 	app.logger.Info("Starting receivers...")
 	err = app.builtReceivers.StartAll(app.logger, app)
 	if err != nil {
@@ -229,33 +444,104 @@ func (app *Application) setupPipelines() {
 	}
 }
 
+// setupSelfTracing sends the spans of the service to the pipeline configured
+// in the telemetry section, if any.
+func (app *Application) setupSelfTracing() {
+	traces := app.config.Telemetry.Traces
+	if traces.Pipeline == "" {
+		return
+	}
+
+	tc := app.builtPipelines.TraceConsumer(app.config.Pipelines[traces.Pipeline])
+	app.spanExporter = obsreport.NewSpanExporter(tc, app.logger)
+	trace.RegisterExporter(app.spanExporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(traces.SamplingRate)})
+	app.logger.Info("Sending the spans of the service to a pipeline",
+		zap.String("pipeline", traces.Pipeline), zap.Float64("sampling-rate", traces.SamplingRate))
+}
+
 func (app *Application) shutdownPipelines() {
 	// Shutdown order is the reverse of building: first receivers, then flushing pipelines
 	// giving senders a chance to send all their data. This may take time, the allowed
 	// time should be part of configuration.
 
+	if app.activePassive != nil {
+		app.activePassive.Stop()
+	}
+
 	app.logger.Info("Stopping receivers...")
 	app.builtReceivers.StopAll()
 
-	// TODO: shutdown processors
+	// The last spans of the service are sent before the pipelines are flushed.
+	if app.spanExporter != nil {
+		trace.UnregisterExporter(app.spanExporter)
+		app.spanExporter.Stop()
+	}
+
+	app.FlushAll()
 
+	app.logger.Info("Stopping processors...")
+	app.builtPipelines.StopAll()
+
+	app.logger.Info("Stopping exporters...")
 	app.exporters.StopAll()
 }
 
-func (app *Application) executeUnified() {
-	app.logger.Info("Starting...", zap.Int("NumCPU", runtime.NumCPU()))
+// FlushAll synchronously flushes the processors buffering data, e.g. batches,
+// and waits for the exporter queues to be empty, bounded by the drain timeout.
+// The receivers must be stopped first, otherwise new data keeps coming in. It
+// returns the number of items (spans or metrics) still in flight, i.e. lost
+// if the service stops.
+func (app *Application) FlushAll() int {
+	ctx, cancel := context.WithTimeout(context.Background(), builder.DrainTimeout(app.v))
+	defer cancel()
+
+	app.logger.Info("Flushing pipelines...")
+	if err := app.builtPipelines.FlushAll(ctx); err != nil {
+		app.logger.Warn("Failed to flush processors", zap.Error(err))
+	}
+	if err := app.exporters.DrainAll(ctx); err != nil {
+		app.logger.Warn("Failed to drain exporters", zap.Error(err))
+	}
 
-	// Set memory ballast
-	ballast, ballastSizeBytes := app.createMemoryBallast()
+	inFlight := app.InFlight()
+	if inFlight > 0 {
+		app.logger.Warn("Data still in flight after flushing pipelines", zap.Int("items", inFlight))
+	}
+	return inFlight
+}
+
+// InFlight returns the number of items (spans or metrics) buffered by the
+// processors or queued by the exporters. Tests and deployment tooling can
+// check it is zero after a shutdown to assert that no data was lost.
+func (app *Application) InFlight() int {
+	return app.builtPipelines.InFlight() + app.exporters.InFlight()
+}
+
+func (app *Application) executeUnified() {
+	app.logger.Info("Starting "+app.info.LongName+"...",
+		zap.String("Version", app.info.Version),
+		zap.String("GitHash", app.info.GitHash),
+		zap.String("BuildDate", app.info.BuildDate),
+		zap.Int("NumCPU", runtime.NumCPU()),
+	)
 
 	app.asyncErrorChannel = make(chan error)
 
 	// Setup everything.
+	app.loadConfig()
+	app.setupMaxProcs()
+	ballast, ballastSizeBytes := app.createMemoryBallast()
 	app.setupPProf()
 	app.setupHealthCheck()
 	app.setupZPages()
 	app.setupTelemetry(ballastSizeBytes)
+	app.setupMemoryBudget()
+	app.setupDebugBatches()
 	app.setupPipelines()
+	app.setupStatus()
+	app.setupTuning()
+	app.setupSelfTracing()
 
 	// Everything is ready, now run until an event requiring shutdown happens.
 	app.runAndWaitForShutdownEvent()
@@ -277,31 +563,94 @@ func (app *Application) executeUnified() {
 // given by the user.
 func (app *Application) StartUnified() error {
 	rootCmd := &cobra.Command{
-		Use:  "otelsvc",
-		Long: "OpenTelemetry Service",
+		Use:     app.info.ExeName,
+		Long:    app.info.LongName,
+		Version: app.info.Version,
 		Run: func(cmd *cobra.Command, args []string) {
 			app.init()
 			app.executeUnified()
 		},
 	}
 	viperutils.AddFlags(app.v, rootCmd,
-		telemetryFlags,
 		builder.Flags,
+		activepassive.AddFlags,
 		healthCheckFlags,
-		loggerFlags,
 		pprofserver.AddFlags,
 		zpagesserver.AddFlags,
 	)
+	pluginsFlagSet := new(flag.FlagSet)
+	builder.PluginsFlags(pluginsFlagSet)
+	rootCmd.PersistentFlags().AddGoFlagSet(pluginsFlagSet)
+	app.v.BindPFlags(rootCmd.PersistentFlags())
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "components",
+		Short: "Print the receivers, processors and exporters of the build with their default configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app.loadPlugins()
+			components := config.DescribeComponents(app.receiverFactories, app.processorFactories, app.exporterFactories)
+			return components.WriteYAML(cmd.OutOrStdout())
+		},
+	})
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print the version, git hash and build date of the build",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(cmd.OutOrStdout(), app.buildInfo().Info())
+		},
+	})
 
 	return rootCmd.Execute()
 }
 
+// buildInfo returns the build of the distribution given to the components.
+func (app *Application) buildInfo() buildinfo.BuildInfo {
+	return buildinfo.BuildInfo{
+		Command:   app.info.ExeName,
+		Version:   app.info.Version,
+		GitHash:   app.info.GitHash,
+		BuildDate: app.info.BuildDate,
+	}
+}
+
+func (app *Application) setupMemoryBudget() {
+	budgetSizeMiB := builder.MemBudgetSize(app.v)
+	if budgetSizeMiB > 0 {
+		memorybudget.Default().SetLimit(int64(budgetSizeMiB) * 1024 * 1024)
+		app.logger.Info("Using memory budget", zap.Int("MiBs", budgetSizeMiB))
+	}
+}
+
+func (app *Application) setupMaxProcs() {
+	if !app.config.Runtime.AutoMaxProcs {
+		return
+	}
+	procs, ok := runtimelimits.MaxProcs()
+	if !ok {
+		return
+	}
+	runtime.GOMAXPROCS(procs)
+	app.logger.Info("Set GOMAXPROCS to the CPU quota", zap.Int("GOMAXPROCS", procs))
+}
+
 func (app *Application) createMemoryBallast() ([]byte, uint64) {
-	ballastSizeMiB := builder.MemBallastSize(app.v)
+	// The flag takes precedence over the config file.
+	ballastSizeMiB := uint64(builder.MemBallastSize(app.v))
+	if ballastSizeMiB == 0 {
+		ballastSizeMiB = app.config.Runtime.BallastSizeMiB
+	}
+	if ballastSizeMiB == 0 && app.config.Runtime.BallastSizePercentage > 0 {
+		total, err := runtimelimits.TotalMemory()
+		if err != nil {
+			log.Fatalf("Cannot read the total memory to size the ballast: %v", err)
+		}
+		ballastSizeMiB = total * app.config.Runtime.BallastSizePercentage / 100 / (1024 * 1024)
+	}
 	if ballastSizeMiB > 0 {
-		ballastSizeBytes := uint64(ballastSizeMiB) * 1024 * 1024
+		ballastSizeBytes := ballastSizeMiB * 1024 * 1024
 		ballast := make([]byte, ballastSizeBytes)
-		app.logger.Info("Using memory ballast", zap.Int("MiBs", ballastSizeMiB))
+		app.logger.Info("Using memory ballast", zap.Uint64("MiBs", ballastSizeMiB))
 		return ballast, ballastSizeBytes
 	}
 	return nil, 0
@@ -22,9 +22,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-service/defaults"
 	"github.com/open-telemetry/opentelemetry-service/internal/testutils"
+	"github.com/open-telemetry/opentelemetry-service/internal/version"
 	"github.com/open-telemetry/opentelemetry-service/internal/zpagesserver"
 )
 
@@ -32,12 +35,16 @@ func TestApplication_StartUnified(t *testing.T) {
 	receiverFactories, processorsFactories, exporterFactories, err := defaults.Components()
 	assert.Nil(t, err)
 
-	app := New(receiverFactories, processorsFactories, exporterFactories)
+	app, err := New(Parameters{
+		ReceiverFactories:  receiverFactories,
+		ProcessorFactories: processorsFactories,
+		ExporterFactories:  exporterFactories,
+	})
+	require.NoError(t, err)
 
 	portArg := []string{
 		healthCheckHTTPPort, // Keep it as first since its address is used later.
 		zpagesserver.ZPagesHTTPPort,
-		"metrics-port",
 	}
 	addresses := getMultipleAvailableLocalAddresses(t, uint(len(portArg)))
 	for i, addr := range addresses {
@@ -47,6 +54,8 @@ func TestApplication_StartUnified(t *testing.T) {
 		}
 		app.v.Set(portArg[i], port)
 	}
+	metricsAddress := testutils.GetAvailableLocalAddress(t)
+	app.v.Set("telemetry.address", metricsAddress)
 
 	app.v.Set("config", "testdata/otelsvc-config.yaml")
 
@@ -65,6 +74,12 @@ func TestApplication_StartUnified(t *testing.T) {
 		t.Fatalf("app didn't reach ready state")
 	}
 
+	// The own metrics are served on the address of the telemetry section.
+	resp, err := http.Get("http://" + metricsAddress + "/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
 	// We have to wait here work around a data race bug in Jaeger
 	// (https://github.com/jaegertracing/jaeger/pull/1625) caused
 	// by stopping immediately after starting.
@@ -79,6 +94,36 @@ func TestApplication_StartUnified(t *testing.T) {
 
 	close(app.stopTestChan)
 	<-appDone
+
+	// Nothing was received, the pipelines must be empty after the shutdown.
+	assert.Equal(t, 0, app.InFlight())
+}
+
+func TestNew(t *testing.T) {
+	receiverFactories, processorsFactories, exporterFactories, err := defaults.Components()
+	require.NoError(t, err)
+
+	_, err = New(Parameters{ExporterFactories: exporterFactories})
+	assert.Error(t, err)
+	_, err = New(Parameters{ReceiverFactories: receiverFactories})
+	assert.Error(t, err)
+
+	app, err := New(Parameters{
+		ReceiverFactories:    receiverFactories,
+		ProcessorFactories:   processorsFactories,
+		ExporterFactories:    exporterFactories,
+		ApplicationStartInfo: ApplicationStartInfo{ExeName: "mysvc", Version: "1.2.3"},
+		Logger:               zap.NewNop(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationStartInfo{
+		ExeName:   "mysvc",
+		LongName:  "OpenTelemetry Service",
+		Version:   "1.2.3",
+		GitHash:   version.GitHash,
+		BuildDate: version.BuildDate,
+	}, app.info)
+	assert.NotNil(t, app.logger)
 }
 
 // isAppAvailable checks if the healthcheck server at the given endpoint is
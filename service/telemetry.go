@@ -15,29 +15,23 @@
 package service
 
 import (
-	"flag"
-	"log"
 	"net/http"
-	"strconv"
 
 	"contrib.go.opencensus.io/exporter/prometheus"
-	"github.com/spf13/viper"
 	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-service/config/configmodels"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/processor"
 	"github.com/open-telemetry/opentelemetry-service/internal/collector/telemetry"
 	"github.com/open-telemetry/opentelemetry-service/observability"
+	"github.com/open-telemetry/opentelemetry-service/obsreport"
+	"github.com/open-telemetry/opentelemetry-service/processor/datalimiter"
 	"github.com/open-telemetry/opentelemetry-service/processor/nodebatcher"
 	"github.com/open-telemetry/opentelemetry-service/processor/queued"
 	"github.com/open-telemetry/opentelemetry-service/processor/tailsampling"
 )
 
-const (
-	metricsPortCfg  = "metrics-port"
-	metricsLevelCfg = "metrics-level"
-)
-
 var (
 	// AppTelemetry is application's own telemetry.
 	AppTelemetry = &appTelemetry{}
@@ -47,29 +41,24 @@ type appTelemetry struct {
 	views []*view.View
 }
 
-func telemetryFlags(flags *flag.FlagSet) {
-	flags.String(metricsLevelCfg, "BASIC", "Output level of telemetry metrics (NONE, BASIC, NORMAL, DETAILED)")
-	// At least until we can use a generic, i.e.: OpenCensus, metrics exporter we default to Prometheus at port 8888, if not otherwise specified.
-	flags.Uint(metricsPortCfg, 8888, "Port exposing collector telemetry.")
-}
-
-func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes uint64, v *viper.Viper, logger *zap.Logger) error {
-	level, err := telemetry.ParseLevel(v.GetString(metricsLevelCfg))
+func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes uint64, cfg configmodels.Telemetry, logger *zap.Logger) error {
+	// The level is validated when the configuration is loaded.
+	level, err := telemetry.ParseLevel(cfg.Level)
 	if err != nil {
-		log.Fatalf("Failed to parse metrics level: %v", err)
+		return err
 	}
 
 	if level == telemetry.None {
 		return nil
 	}
 
-	port := v.GetInt(metricsPortCfg)
-
 	views := processor.MetricViews(level)
 	views = append(views, queued.MetricViews(level)...)
 	views = append(views, nodebatcher.MetricViews(level)...)
 	views = append(views, observability.AllViews...)
 	views = append(views, tailsampling.SamplingProcessorMetricViews(level)...)
+	views = append(views, datalimiter.MetricViews(level)...)
+	views = append(views, obsreport.Views(level)...)
 	processMetricsViews := telemetry.NewProcessMetricsViews(ballastSizeBytes)
 	views = append(views, processMetricsViews.Views()...)
 	tel.views = views
@@ -81,7 +70,8 @@ func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes u
 
 	// Until we can use a generic metrics exporter, default to Prometheus.
 	opts := prometheus.Options{
-		Namespace: "oc_collector",
+		Namespace:   "oc_collector",
+		ConstLabels: cfg.Labels,
 	}
 	pe, err := prometheus.NewExporter(opts)
 	if err != nil {
@@ -90,11 +80,11 @@ func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes u
 
 	view.RegisterExporter(pe)
 
-	logger.Info("Serving Prometheus metrics", zap.Int("port", port))
+	logger.Info("Serving Prometheus metrics", zap.String("address", cfg.Address))
 	go func() {
 		mux := http.NewServeMux()
 		mux.Handle("/metrics", pe)
-		serveErr := http.ListenAndServe(":"+strconv.Itoa(port), mux)
+		serveErr := http.ListenAndServe(cfg.Address, mux)
 		if serveErr != nil && serveErr != http.ErrServerClosed {
 			asyncErrorChannel <- serveErr
 		}
@@ -0,0 +1,145 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type fileStorage struct {
+	directory string
+
+	mu      sync.Mutex
+	clients map[string]*fileClient
+}
+
+// NewFileStorage returns a Storage persisting the state of each namespace in
+// a file of the directory, which is created if needed. Every change rewrites
+// the file of the namespace: it is meant for small states, e.g. offsets,
+// changed at most a few times per second.
+func NewFileStorage(directory string) (Storage, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create the storage directory: %v", err)
+	}
+	return &fileStorage{
+		directory: directory,
+		clients:   make(map[string]*fileClient),
+	}, nil
+}
+
+func (fs *fileStorage) Client(namespace string) (Client, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if client := fs.clients[namespace]; client != nil {
+		return client, nil
+	}
+
+	client := &fileClient{
+		path:   filepath.Join(fs.directory, url.PathEscape(namespace)+".json"),
+		values: make(map[string][]byte),
+	}
+	data, err := ioutil.ReadFile(client.path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("cannot read the storage of %s: %v", namespace, err)
+	default:
+		if err := json.Unmarshal(data, &client.values); err != nil {
+			return nil, fmt.Errorf("cannot decode the storage of %s: %v", namespace, err)
+		}
+	}
+	fs.clients[namespace] = client
+	return client, nil
+}
+
+// fileClient keeps the values in memory and writes them all to its file on
+// every change.
+type fileClient struct {
+	path string
+
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (fc *fileClient) Get(key string) ([]byte, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return copyValue(fc.values[key]), nil
+}
+
+func (fc *fileClient) Set(key string, value []byte) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	previous, ok := fc.values[key]
+	fc.values[key] = copyValue(value)
+	if err := fc.write(); err != nil {
+		if ok {
+			fc.values[key] = previous
+		} else {
+			delete(fc.values, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (fc *fileClient) Delete(key string) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	previous, ok := fc.values[key]
+	if !ok {
+		return nil
+	}
+	delete(fc.values, key)
+	if err := fc.write(); err != nil {
+		fc.values[key] = previous
+		return err
+	}
+	return nil
+}
+
+// write replaces the file with the values, through a temporary file renamed
+// once synced so that the file is never partially written. The lock must be
+// held.
+func (fc *fileClient) write() error {
+	data, err := json.Marshal(fc.values)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(fc.path), filepath.Base(fc.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fc.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"sync"
+)
+
+type memoryStorage struct {
+	mu      sync.Mutex
+	clients map[string]*memoryClient
+}
+
+// NewMemoryStorage returns a Storage keeping the state in memory, it is lost
+// when the service stops. It is used when no storage is configured.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{clients: make(map[string]*memoryClient)}
+}
+
+func (ms *memoryStorage) Client(namespace string) (Client, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	client := ms.clients[namespace]
+	if client == nil {
+		client = &memoryClient{values: make(map[string][]byte)}
+		ms.clients[namespace] = client
+	}
+	return client, nil
+}
+
+type memoryClient struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (mc *memoryClient) Get(key string) ([]byte, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return copyValue(mc.values[key]), nil
+}
+
+func (mc *memoryClient) Set(key string, value []byte) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.values[key] = copyValue(value)
+	return nil
+}
+
+func (mc *memoryClient) Delete(key string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	delete(mc.values, key)
+	return nil
+}
+
+// copyValue copies the value so that the caller can't modify the stored one.
+func copyValue(value []byte) []byte {
+	if value == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(value)), value...)
+}
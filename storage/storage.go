@@ -0,0 +1,48 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage defines the storage the receivers, processors and exporters
+// persist their state in across restarts of the service, e.g. file offsets or
+// the last points of the metrics series.
+package storage
+
+// Client stores the state of a single component instance, as key and value
+// pairs. It is safe for concurrent use.
+type Client interface {
+	// Get returns the value of the key, nil if the key is not set.
+	Get(key string) ([]byte, error)
+
+	// Set sets the value of the key, it is persisted once Set returns.
+	Set(key string, value []byte) error
+
+	// Delete removes the key, it does nothing if the key is not set.
+	Delete(key string) error
+}
+
+// Storage gives the clients of the component instances, each component
+// instance has its own namespace.
+type Storage interface {
+	// Client returns the client of the namespace, the same client is returned
+	// for the same namespace.
+	Client(namespace string) (Client, error)
+}
+
+// User is implemented by the receivers, processors and exporters persisting
+// state. The service sets their client after creating them, before any data
+// is sent to them or they are started.
+type User interface {
+	// SetStorageClient sets the client of the component instance. It returns
+	// an error if the state stored can't be used.
+	SetStorageClient(client Client) error
+}
@@ -0,0 +1,151 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClient(t *testing.T, client Client) {
+	value, err := client.Get("offset")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, client.Set("offset", []byte("42")))
+	value, err = client.Get("offset")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("42"), value)
+
+	// The stored value can't be modified through the returned one.
+	value[0] = '0'
+	value, err = client.Get("offset")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("42"), value)
+
+	require.NoError(t, client.Delete("offset"))
+	require.NoError(t, client.Delete("offset"))
+	value, err = client.Get("offset")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestMemoryStorage(t *testing.T) {
+	st := NewMemoryStorage()
+	client, err := st.Client("receiver/filelog")
+	require.NoError(t, err)
+	testClient(t, client)
+
+	require.NoError(t, client.Set("offset", []byte("42")))
+	same, err := st.Client("receiver/filelog")
+	require.NoError(t, err)
+	assert.Equal(t, client, same)
+	other, err := st.Client("receiver/filelog/2")
+	require.NoError(t, err)
+	value, err := other.Get("offset")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := NewFileStorage(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	client, err := st.Client("receiver/filelog")
+	require.NoError(t, err)
+	testClient(t, client)
+
+	require.NoError(t, client.Set("offset", []byte("42")))
+	require.NoError(t, client.Set("inode", []byte{0, 1, 2}))
+	other, err := st.Client("receiver/filelog/2")
+	require.NoError(t, err)
+	require.NoError(t, other.Set("offset", []byte("7")))
+
+	// The values are read back by a new storage, e.g. after a restart.
+	st, err = NewFileStorage(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	client, err = st.Client("receiver/filelog")
+	require.NoError(t, err)
+	value, err := client.Get("offset")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("42"), value)
+	value, err = client.Get("inode")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2}, value)
+	other, err = st.Client("receiver/filelog/2")
+	require.NoError(t, err)
+	value, err = other.Get("offset")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("7"), value)
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	assert.Equal(t, []string{"receiver%2Ffilelog%2F2.json", "receiver%2Ffilelog.json"}, names)
+}
+
+func TestFileStorageErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "corrupted.json"), []byte("{"), 0600))
+	st, err := NewFileStorage(dir)
+	require.NoError(t, err)
+	_, err = st.Client("corrupted")
+	assert.Error(t, err)
+
+	_, err = NewFileStorage(filepath.Join(dir, "corrupted.json", "state"))
+	assert.Error(t, err)
+}
+
+func TestFileStorageConcurrency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := NewFileStorage(dir)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := st.Client("processor")
+			assert.NoError(t, err)
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, client.Set("key", []byte{byte(j)}))
+				_, err := client.Get("key")
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "the temporary files must be renamed")
+}
@@ -4,6 +4,9 @@ go 1.12
 
 require (
 	contrib.go.opencensus.io/exporter/jaeger v0.1.1-0.20190430175949-e8b55949d948
+	contrib.go.opencensus.io/exporter/ocagent v0.6.0
+	github.com/census-instrumentation/opencensus-proto v0.2.1
+	github.com/golang/protobuf v1.3.2
 	github.com/open-telemetry/opentelemetry-service v0.0.0-20190625135304-4bd705a25a35
 	github.com/shirou/gopsutil v2.18.12+incompatible
 	github.com/spf13/viper v1.4.0
@@ -18,20 +18,28 @@ import (
 	"encoding/binary"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"contrib.go.opencensus.io/exporter/jaeger"
+	"contrib.go.opencensus.io/exporter/ocagent"
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"go.opencensus.io/trace"
 )
 
 // LoadGenerator is a simple load generator.
 type LoadGenerator struct {
-	exporter *jaeger.Exporter
+	exporter        *jaeger.Exporter
+	metricsExporter *ocagent.Exporter
 
-	tracesSent uint64
-	spansSent  uint64
+	tracesSent     uint64
+	spansSent      uint64
+	metricsSent    uint64
+	dataPointsSent uint64
 
 	stopOnce   sync.Once
 	stopWait   sync.WaitGroup
@@ -51,6 +59,13 @@ type LoadOptions struct {
 
 	// Attributes to add to each generated span. Can be empty.
 	Attributes map[string]interface{}
+
+	// DataPointsPerSecond specifies how many metric data points to generate each second.
+	DataPointsPerSecond uint
+
+	// DataPointsPerMetric specifies how many data points per metric to generate.
+	// The number of metrics generated per second will be DataPointsPerSecond/DataPointsPerMetric.
+	DataPointsPerMetric uint
 }
 
 // NewLoadGenerator creates a load generator.
@@ -72,6 +87,16 @@ func NewLoadGenerator() (*LoadGenerator, error) {
 		return nil, err
 	}
 
+	// The metrics are sent to the OpenCensus receiver of the agent.
+	lg.metricsExporter, err = ocagent.NewExporter(
+		ocagent.WithInsecure(),
+		ocagent.WithAddress("localhost:55678"),
+		ocagent.WithServiceName("load-generator"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return lg, nil
 }
 
@@ -83,14 +108,20 @@ func (lg *LoadGenerator) Start(options LoadOptions) {
 		// 10 spans per trace by default.
 		lg.options.SpansPerTrace = 10
 	}
+	if lg.options.DataPointsPerMetric == 0 {
+		// 10 data points per metric by default.
+		lg.options.DataPointsPerMetric = 10
+	}
 
-	log.Printf("Starting load generator at %d spans/sec.", lg.options.SpansPerSecond)
+	log.Printf("Starting load generator at %d spans/sec, %d data points/sec.",
+		lg.options.SpansPerSecond, lg.options.DataPointsPerSecond)
 
 	// Indicate that generation is in progress.
-	lg.stopWait.Add(1)
+	lg.stopWait.Add(2)
 
 	// Begin generation
 	go lg.generate()
+	go lg.generateMetrics()
 }
 
 // Stop the load.
@@ -102,6 +133,10 @@ func (lg *LoadGenerator) Stop() {
 		// Wait for it to stop.
 		lg.stopWait.Wait()
 
+		if err := lg.metricsExporter.Stop(); err != nil {
+			log.Printf("Cannot stop the metrics exporter: %s", err.Error())
+		}
+
 		// Print stats.
 		log.Printf("Stopped generator. %s", lg.GetStats())
 	})
@@ -109,13 +144,19 @@ func (lg *LoadGenerator) Stop() {
 
 // GetStats returns the stats as a printable string.
 func (lg *LoadGenerator) GetStats() string {
-	return fmt.Sprintf("Sent:%5d spans", atomic.LoadUint64(&lg.spansSent))
+	return fmt.Sprintf("Sent:%5d spans,%5d data points",
+		atomic.LoadUint64(&lg.spansSent), atomic.LoadUint64(&lg.dataPointsSent))
 }
 
 func (lg *LoadGenerator) SpansSent() uint64 {
 	return atomic.LoadUint64(&lg.spansSent)
 }
 
+// DataPointsSent returns the number of metric data points successfully sent.
+func (lg *LoadGenerator) DataPointsSent() uint64 {
+	return atomic.LoadUint64(&lg.dataPointsSent)
+}
+
 func (lg *LoadGenerator) generate() {
 	// Indicate that generation is done at the end
 	defer lg.stopWait.Done()
@@ -174,6 +215,70 @@ func (lg *LoadGenerator) generateTrace() {
 	}
 }
 
+func (lg *LoadGenerator) generateMetrics() {
+	// Indicate that generation is done at the end
+	defer lg.stopWait.Done()
+
+	metricsPerSecond := lg.options.DataPointsPerSecond / lg.options.DataPointsPerMetric
+	if metricsPerSecond == 0 {
+		return
+	}
+
+	t := time.NewTicker(time.Second / time.Duration(metricsPerSecond))
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			lg.generateMetric()
+
+		case <-lg.stopSignal:
+			return
+		}
+	}
+}
+
+func (lg *LoadGenerator) generateMetric() {
+	metricID := atomic.AddUint64(&lg.metricsSent, 1)
+	now := time.Now()
+
+	// Each data point is in its own time series, its value is its sequence
+	// number so that the backend can tell which ones it received.
+	firstPoint := atomic.LoadUint64(&lg.dataPointsSent) + 1
+	timeseries := make([]*metricspb.TimeSeries, 0, lg.options.DataPointsPerMetric)
+	for i := uint64(0); i < uint64(lg.options.DataPointsPerMetric); i++ {
+		timeseries = append(timeseries, &metricspb.TimeSeries{
+			LabelValues: []*metricspb.LabelValue{
+				{Value: strconv.FormatUint(i, 10), HasValue: true},
+			},
+			Points: []*metricspb.Point{
+				{
+					Timestamp: &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())},
+					Value:     &metricspb.Point_Int64Value{Int64Value: int64(firstPoint + i)},
+				},
+			},
+		})
+	}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      "load_generator_metric_" + strconv.FormatUint(metricID, 10),
+			Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys: []*metricspb.LabelKey{{Key: "load_generator.series"}},
+		},
+		Timeseries: timeseries,
+	}
+
+	err := lg.metricsExporter.ExportMetricsServiceRequest(&agentmetricspb.ExportMetricsServiceRequest{
+		Metrics: []*metricspb.Metric{metric},
+	})
+	if err != nil {
+		// Only the data points accepted by the exporter count as sent.
+		log.Printf("Cannot send metric: %s", err.Error())
+		return
+	}
+	atomic.AddUint64(&lg.dataPointsSent, uint64(len(timeseries)))
+}
+
 func generateTraceID(id uint64) trace.TraceID {
 	var traceID trace.TraceID
 	binary.PutUvarint(traceID[:], id)
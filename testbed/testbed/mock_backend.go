@@ -104,6 +104,9 @@ func (mb *MockBackend) Start(backendType BackendType) error {
 		if err != nil {
 			return err
 		}
+		if err := mb.ocReceiver.StartMetricsReception(mb); err != nil {
+			return err
+		}
 
 	case BackendJaeger:
 		jaegerCfg := jaegerreceiver.Configuration{
@@ -146,6 +149,7 @@ func (mb *MockBackend) Stop() {
 
 		if mb.ocReceiver != nil {
 			_ = mb.ocReceiver.StopTraceReception()
+			_ = mb.ocReceiver.StopMetricsReception()
 		}
 
 		if mb.jaegerReceiver != nil {
@@ -161,46 +165,87 @@ func (mb *MockBackend) Stop() {
 }
 
 func (mb *MockBackend) GetStats() string {
-	return fmt.Sprintf("Received:%5d spans", mb.SpansReceived())
+	return fmt.Sprintf("Received:%5d spans,%5d data points", mb.SpansReceived(), mb.DataPointsReceived())
 }
 
 func (mb *MockBackend) SpansReceived() uint64 {
 	return atomic.LoadUint64(&mb.tc.spansReceived)
 }
 
+// DataPointsReceived returns the number of metric data points received.
+func (mb *MockBackend) DataPointsReceived() uint64 {
+	return atomic.LoadUint64(&mb.mc.dataPointsReceived)
+}
+
+// seqnums records the sequence numbers of the spans or data points generated
+// by the load generator that were received.
+type seqnums struct {
+	mutex      sync.Mutex
+	received   map[int64]bool
+	duplicates uint64
+}
+
+func (s *seqnums) add(seqnum int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.received == nil {
+		s.received = make(map[int64]bool)
+	}
+	if s.received[seqnum] {
+		s.duplicates++
+		return
+	}
+	s.received[seqnum] = true
+}
+
+// validate returns an error if the sequence numbers from 1 to sent were not
+// all received exactly once.
+func (s *seqnums) validate(sent uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var missing uint64
+	for seqnum := int64(1); seqnum <= int64(sent); seqnum++ {
+		if !s.received[seqnum] {
+			missing++
+		}
+	}
+	unexpected := uint64(len(s.received)) + missing - sent
+	if missing != 0 || unexpected != 0 || s.duplicates != 0 {
+		return fmt.Errorf("%d missing, %d unexpected and %d duplicate", missing, unexpected, s.duplicates)
+	}
+	return nil
+}
+
 type mockTraceConsumer struct {
 	spansReceived uint64
+	seqnums       seqnums
 }
 
 func (tc *mockTraceConsumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceData) error {
 	atomic.AddUint64(&tc.spansReceived, uint64(len(td.Spans)))
 
 	for _, span := range td.Spans {
-		var spanSeqnum int64
-		var traceSeqnum int64
-
-		seqnumAttr, ok := span.Attributes.AttributeMap["load_generator.span_seq_num"]
-		if ok {
-			spanSeqnum = seqnumAttr.GetIntValue()
+		if seqnumAttr, ok := span.GetAttributes().GetAttributeMap()["load_generator.span_seq_num"]; ok {
+			tc.seqnums.add(seqnumAttr.GetIntValue())
 		}
-
-		seqnumAttr, ok = span.Attributes.AttributeMap["load_generator.trace_seq_num"]
-		if ok {
-			traceSeqnum = seqnumAttr.GetIntValue()
-		}
-
-		// Ignore the seqnums for now. We will use them later.
-		_ = spanSeqnum
-		_ = traceSeqnum
 	}
 
 	return nil
 }
 
 type mockMetricConsumer struct {
+	dataPointsReceived uint64
+	seqnums            seqnums
 }
 
 func (mc *mockMetricConsumer) ConsumeMetricsData(ctx context.Context, md consumerdata.MetricsData) error {
-	// Metrics backend is not implemented yet.
+	for _, metric := range md.Metrics {
+		for _, ts := range metric.GetTimeseries() {
+			atomic.AddUint64(&mc.dataPointsReceived, uint64(len(ts.GetPoints())))
+			for _, point := range ts.GetPoints() {
+				mc.seqnums.add(point.GetInt64Value())
+			}
+		}
+	}
 	return nil
 }
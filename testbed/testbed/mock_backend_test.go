@@ -50,6 +50,21 @@ func TestGeneratorAndBackend(t *testing.T) {
 	assert.Equal(t, lg.SpansSent(), mb.SpansReceived())
 }
 
+func TestSeqnums(t *testing.T) {
+	var s seqnums
+	assert.NoError(t, s.validate(0))
+
+	s.add(1)
+	s.add(2)
+	s.add(3)
+	assert.NoError(t, s.validate(3))
+	assert.EqualError(t, s.validate(4), "1 missing, 0 unexpected and 0 duplicate")
+	assert.EqualError(t, s.validate(2), "0 missing, 1 unexpected and 0 duplicate")
+
+	s.add(2)
+	assert.EqualError(t, s.validate(3), "0 missing, 0 unexpected and 1 duplicate")
+}
+
 // WaitFor the specific condition for up to 10 seconds. Records a test error
 // if condition does not become true.
 func WaitFor(t *testing.T, cond func() bool, errMsg ...interface{}) bool {
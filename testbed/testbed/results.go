@@ -33,15 +33,17 @@ type Results struct {
 var results = Results{}
 
 type TestResult struct {
-	testName          string
-	result            string
-	duration          time.Duration
-	cpuPercentageAvg  float64
-	cpuPercentageMax  float64
-	ramMibAvg         uint32
-	ramMibMax         uint32
-	sentSpanCount     uint64
-	receivedSpanCount uint64
+	testName           string
+	result             string
+	duration           time.Duration
+	cpuPercentageAvg   float64
+	cpuPercentageMax   float64
+	ramMibAvg          uint32
+	ramMibMax          uint32
+	sentSpanCount      uint64
+	receivedSpanCount  uint64
+	sentPointCount     uint64
+	receivedPointCount uint64
 }
 
 func (r *Results) Init(resultsDir string) {
@@ -59,8 +61,8 @@ func (r *Results) Init(resultsDir string) {
 	_, _ = io.WriteString(r.resultsFile,
 		"# Test Results\n"+
 			fmt.Sprintf("Started: %s\n\n", time.Now().Format(time.RFC1123Z))+
-			"Test                                    |Result|Duration|CPU Avg%|CPU Max%|RAM Avg MiB|RAM Max MiB|Sent Spans|Received Spans\n"+
-			"----------------------------------------|------|-------:|-------:|-------:|----------:|----------:|---------:|-------------:\n")
+			"Test                                    |Result|Duration|CPU Avg%|CPU Max%|RAM Avg MiB|RAM Max MiB|Sent Spans|Received Spans|Sent Points|Received Points\n"+
+			"----------------------------------------|------|-------:|-------:|-------:|----------:|----------:|---------:|-------------:|----------:|--------------:\n")
 }
 
 // Save the total results and close the file.
@@ -73,7 +75,7 @@ func (r *Results) Save() {
 // Add results for one test.
 func (r *Results) Add(testName string, result *TestResult) {
 	_, _ = io.WriteString(r.resultsFile,
-		fmt.Sprintf("%-40s|%-6s|%7.0fs|%8.1f|%8.1f|%11d|%11d|%10d|%14d\n",
+		fmt.Sprintf("%-40s|%-6s|%7.0fs|%8.1f|%8.1f|%11d|%11d|%10d|%14d|%11d|%15d\n",
 			result.testName,
 			result.result,
 			result.duration.Seconds(),
@@ -83,6 +85,8 @@ func (r *Results) Add(testName string, result *TestResult) {
 			result.ramMibMax,
 			result.sentSpanCount,
 			result.receivedSpanCount,
+			result.sentPointCount,
+			result.receivedPointCount,
 		),
 	)
 	r.totalDuration = r.totalDuration + result.duration
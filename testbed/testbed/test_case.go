@@ -231,20 +231,23 @@ func (tc *TestCase) Stop() {
 	}
 
 	results.Add(tc.t.Name(), &TestResult{
-		testName:          tc.t.Name(),
-		result:            result,
-		receivedSpanCount: tc.MockBackend.SpansReceived(),
-		sentSpanCount:     tc.LoadGenerator.SpansSent(),
-		duration:          time.Since(tc.startTime),
-		cpuPercentageAvg:  rc.CPUPercentAvg,
-		cpuPercentageMax:  rc.CPUPercentMax,
-		ramMibAvg:         rc.RAMMiBAvg,
-		ramMibMax:         rc.RAMMiBMax,
+		testName:           tc.t.Name(),
+		result:             result,
+		receivedSpanCount:  tc.MockBackend.SpansReceived(),
+		sentSpanCount:      tc.LoadGenerator.SpansSent(),
+		receivedPointCount: tc.MockBackend.DataPointsReceived(),
+		sentPointCount:     tc.LoadGenerator.DataPointsSent(),
+		duration:           time.Since(tc.startTime),
+		cpuPercentageAvg:   rc.CPUPercentAvg,
+		cpuPercentageMax:   rc.CPUPercentMax,
+		ramMibAvg:          rc.RAMMiBAvg,
+		ramMibMax:          rc.RAMMiBMax,
 	})
 }
 
-// ValidateData validates data by comparing the number of spans sent by load generator
-// and number of spans received by mock backend.
+// ValidateData validates data by comparing the number of spans and data points
+// sent by load generator and received by mock backend, and by checking that
+// each of them was received exactly once.
 func (tc *TestCase) ValidateData() {
 	select {
 	case <-tc.ErrorSignal:
@@ -253,8 +256,19 @@ func (tc *TestCase) ValidateData() {
 	default:
 	}
 
-	if assert.EqualValues(tc.t, tc.LoadGenerator.SpansSent(), tc.MockBackend.SpansReceived(),
-		"Received and sent span counters do not match.") {
+	spansOK := assert.EqualValues(tc.t, tc.LoadGenerator.SpansSent(), tc.MockBackend.SpansReceived(),
+		"Received and sent span counters do not match.")
+	pointsOK := assert.EqualValues(tc.t, tc.LoadGenerator.DataPointsSent(), tc.MockBackend.DataPointsReceived(),
+		"Received and sent data point counters do not match.")
+	if err := tc.MockBackend.tc.seqnums.validate(tc.LoadGenerator.SpansSent()); err != nil {
+		tc.t.Errorf("Received spans do not match the sent ones: %s", err.Error())
+		spansOK = false
+	}
+	if err := tc.MockBackend.mc.seqnums.validate(tc.LoadGenerator.DataPointsSent()); err != nil {
+		tc.t.Errorf("Received data points do not match the sent ones: %s", err.Error())
+		pointsOK = false
+	}
+	if spansOK && pointsOK {
 		log.Printf("Sent and received data matches.")
 	}
 }
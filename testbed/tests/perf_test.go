@@ -88,6 +88,29 @@ func Test10kSPS(t *testing.T) {
 	tc.ValidateData()
 }
 
+func TestMetric10kDPS(t *testing.T) {
+	tc := testbed.NewTestCase(t, testbed.WithConfigFile(path.Join("testdata", "metrics-agent-config.yaml")))
+	defer tc.Stop()
+
+	tc.SetExpectedMaxCPU(50)
+	tc.SetExpectedMaxRAM(70)
+
+	tc.StartBackend(testbed.BackendOC)
+	tc.StartAgent()
+	tc.StartLoad(testbed.LoadOptions{DataPointsPerSecond: 10000})
+
+	tc.Sleep(15 * time.Second)
+
+	tc.StopLoad()
+
+	tc.WaitFor(func() bool { return tc.LoadGenerator.DataPointsSent() == tc.MockBackend.DataPointsReceived() },
+		"all data points received")
+
+	tc.StopAgent()
+
+	tc.ValidateData()
+}
+
 func TestNoBackend10kSPS(t *testing.T) {
 	tc := testbed.NewTestCase(t)
 	defer tc.Stop()
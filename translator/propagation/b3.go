@@ -0,0 +1,189 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// The headers of B3, see https://github.com/openzipkin/b3-propagation.
+const (
+	B3SingleHeader       = "b3"
+	B3TraceIDHeader      = "X-B3-TraceId"
+	B3SpanIDHeader       = "X-B3-SpanId"
+	B3ParentSpanIDHeader = "X-B3-ParentSpanId"
+	B3SampledHeader      = "X-B3-Sampled"
+	B3FlagsHeader        = "X-B3-Flags"
+)
+
+var (
+	errInvalidB3         = errors.New("invalid b3 header")
+	errInvalidB3Sampling = errors.New("invalid B3 sampling state")
+)
+
+// ParseB3Single parses the value of a b3 header, i.e.
+// "{TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}" where the last two
+// fields are optional. A value with only the sampling state, e.g. "0", has no
+// span context and is an error.
+func ParseB3Single(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		if len(parts) == 1 && parseB3SamplingState(parts[0], new(SpanContext)) == nil {
+			return SpanContext{}, errNoSpanContext
+		}
+		return SpanContext{}, errInvalidB3
+	}
+
+	var sc SpanContext
+	var err error
+	if len(parts[0]) != 16 && len(parts[0]) != 32 {
+		return SpanContext{}, errInvalidTraceID
+	}
+	if sc.TraceID, err = parseID(parts[0], 16, 8, errInvalidTraceID); err != nil {
+		return SpanContext{}, err
+	}
+	if sc.SpanID, err = parseID(parts[1], 8, 8, errInvalidSpanID); err != nil {
+		return SpanContext{}, err
+	}
+	if len(parts) > 2 {
+		if err := parseB3SamplingState(parts[2], &sc); err != nil {
+			return SpanContext{}, err
+		}
+	}
+	if len(parts) > 3 {
+		if sc.ParentSpanID, err = parseID(parts[3], 8, 8, errInvalidSpanID); err != nil {
+			return SpanContext{}, err
+		}
+	}
+	return sc, nil
+}
+
+// FormatB3Single returns the value of the b3 header of the span context.
+func FormatB3Single(sc SpanContext) string {
+	value := hex.EncodeToString(sc.TraceID) + "-" + hex.EncodeToString(sc.SpanID)
+	switch {
+	case sc.Debug:
+		value += "-d"
+	case sc.Sampled:
+		value += "-1"
+	default:
+		value += "-0"
+	}
+	if len(sc.ParentSpanID) != 0 {
+		value += "-" + hex.EncodeToString(sc.ParentSpanID)
+	}
+	return value
+}
+
+// parseB3SamplingState sets Sampled and Debug from the sampling state of a b3
+// header: "0", "1" or "d".
+func parseB3SamplingState(state string, sc *SpanContext) error {
+	switch state {
+	case "0":
+		sc.Sampled = false
+	case "1":
+		sc.Sampled = true
+	case "d":
+		sc.Sampled = true
+		sc.Debug = true
+	default:
+		return errInvalidB3Sampling
+	}
+	return nil
+}
+
+// ExtractB3 returns the span context of the b3 header if present, or else of
+// the X-B3-* headers.
+func ExtractB3(h http.Header) (SpanContext, error) {
+	if single := h.Get(B3SingleHeader); single != "" {
+		return ParseB3Single(single)
+	}
+
+	traceID := h.Get(B3TraceIDHeader)
+	spanID := h.Get(B3SpanIDHeader)
+	if traceID == "" || spanID == "" {
+		return SpanContext{}, errNoSpanContext
+	}
+
+	var sc SpanContext
+	var err error
+	if len(traceID) != 16 && len(traceID) != 32 {
+		return SpanContext{}, errInvalidTraceID
+	}
+	if sc.TraceID, err = parseID(traceID, 16, 8, errInvalidTraceID); err != nil {
+		return SpanContext{}, err
+	}
+	if sc.SpanID, err = parseID(spanID, 8, 8, errInvalidSpanID); err != nil {
+		return SpanContext{}, err
+	}
+	if parentSpanID := h.Get(B3ParentSpanIDHeader); parentSpanID != "" {
+		if sc.ParentSpanID, err = parseID(parentSpanID, 8, 8, errInvalidSpanID); err != nil {
+			return SpanContext{}, err
+		}
+	}
+	// Older clients send "true" and "false".
+	switch sampled := h.Get(B3SampledHeader); sampled {
+	case "", "0", "false":
+	case "1", "true":
+		sc.Sampled = true
+	default:
+		return SpanContext{}, errInvalidB3Sampling
+	}
+	if h.Get(B3FlagsHeader) == "1" {
+		sc.Sampled = true
+		sc.Debug = true
+	}
+	return sc, nil
+}
+
+// InjectB3 sets the X-B3-* headers of the span context, or the b3 header if
+// single is true.
+func InjectB3(sc SpanContext, h http.Header, single bool) {
+	if single {
+		h.Set(B3SingleHeader, FormatB3Single(sc))
+		return
+	}
+
+	h.Set(B3TraceIDHeader, hex.EncodeToString(sc.TraceID))
+	h.Set(B3SpanIDHeader, hex.EncodeToString(sc.SpanID))
+	if len(sc.ParentSpanID) != 0 {
+		h.Set(B3ParentSpanIDHeader, hex.EncodeToString(sc.ParentSpanID))
+	}
+	if sc.Debug {
+		// Debug implies sampled, X-B3-Sampled must not be sent with it.
+		h.Set(B3FlagsHeader, "1")
+	} else if sc.Sampled {
+		h.Set(B3SampledHeader, "1")
+	} else {
+		h.Set(B3SampledHeader, "0")
+	}
+}
+
+// IsB3Debug returns whether the headers force the sampling of the trace,
+// either with X-B3-Flags or with the "d" sampling state of the b3 header.
+func IsB3Debug(h http.Header) bool {
+	if h.Get(B3FlagsHeader) == "1" {
+		return true
+	}
+	single := strings.TrimSpace(h.Get(B3SingleHeader))
+	if single == "d" {
+		return true
+	}
+	parts := strings.Split(single, "-")
+	return len(parts) > 2 && parts[2] == "d"
+}
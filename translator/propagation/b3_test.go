@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testB3TraceID      = []byte{0x80, 0xf1, 0x98, 0xee, 0x56, 0x34, 0x3b, 0xa8, 0x64, 0xfe, 0x8b, 0x2a, 0x57, 0xd3, 0xef, 0xf7}
+	testB3SpanID       = []byte{0xe4, 0x57, 0xb5, 0xa2, 0xe4, 0xd8, 0x6b, 0xd1}
+	testB3ParentSpanID = []byte{0x05, 0xe3, 0xac, 0x9a, 0x4f, 0x6e, 0x3b, 0x90}
+)
+
+func TestParseB3Single(t *testing.T) {
+	tests := []struct {
+		value string
+		want  SpanContext
+	}{
+		{
+			value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90",
+			want:  SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID, ParentSpanID: testB3ParentSpanID, Sampled: true},
+		},
+		{
+			value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1",
+			want:  SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID},
+		},
+		{
+			value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d",
+			want:  SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID, Sampled: true, Debug: true},
+		},
+		{
+			// 64-bit trace IDs are left-padded.
+			value: "64fe8b2a57d3eff7-e457b5a2e4d86bd1-0",
+			want:  SpanContext{TraceID: append(make([]byte, 8), testB3TraceID[8:]...), SpanID: testB3SpanID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			sc, err := ParseB3Single(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sc)
+		})
+	}
+}
+
+func TestParseB3Single_Invalid(t *testing.T) {
+	_, err := ParseB3Single("1")
+	assert.Equal(t, errNoSpanContext, err)
+
+	tests := []string{
+		"",
+		"80f198ee56343ba864fe8b2a57d3eff7",
+		"80f198ee56343ba864fe8b2a57d3eff-e457b5a2e4d86bd1",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-x",
+		"80f198ee56343ba8-0000000000000000",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b9",
+		"80f198ee56343ba8-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90-extra",
+	}
+	for _, value := range tests {
+		_, err := ParseB3Single(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatB3Single(t *testing.T) {
+	sc := SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID, ParentSpanID: testB3ParentSpanID, Sampled: true}
+	assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90", FormatB3Single(sc))
+
+	sc = SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID, Debug: true}
+	assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-d", FormatB3Single(sc))
+}
+
+func TestExtractInjectB3Multi(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-B3-TraceId", "80f198ee56343ba864fe8b2a57d3eff7")
+	h.Set("X-B3-SpanId", "e457b5a2e4d86bd1")
+	h.Set("X-B3-ParentSpanId", "05e3ac9a4f6e3b90")
+	h.Set("X-B3-Sampled", "1")
+
+	sc, err := ExtractB3(h)
+	require.NoError(t, err)
+	assert.Equal(t, SpanContext{TraceID: testB3TraceID, SpanID: testB3SpanID, ParentSpanID: testB3ParentSpanID, Sampled: true}, sc)
+
+	injected := http.Header{}
+	InjectB3(sc, injected, false)
+	assert.Equal(t, h, injected)
+
+	single := http.Header{}
+	InjectB3(sc, single, true)
+	assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90", single.Get(B3SingleHeader))
+	sc, err = ExtractB3(single)
+	require.NoError(t, err)
+	assert.Equal(t, testB3ParentSpanID, sc.ParentSpanID)
+}
+
+func TestExtractB3Multi_SamplingAndDebug(t *testing.T) {
+	h := http.Header{}
+	h.Set(B3TraceIDHeader, "64fe8b2a57d3eff7")
+	h.Set(B3SpanIDHeader, "e457b5a2e4d86bd1")
+	h.Set(B3SampledHeader, "true")
+	sc, err := ExtractB3(h)
+	require.NoError(t, err)
+	assert.True(t, sc.Sampled)
+	assert.False(t, sc.Debug)
+
+	h.Del(B3SampledHeader)
+	h.Set(B3FlagsHeader, "1")
+	sc, err = ExtractB3(h)
+	require.NoError(t, err)
+	assert.True(t, sc.Sampled)
+	assert.True(t, sc.Debug)
+
+	h.Set(B3SampledHeader, "yes")
+	_, err = ExtractB3(h)
+	assert.Equal(t, errInvalidB3Sampling, err)
+
+	_, err = ExtractB3(http.Header{B3TraceIDHeader: {"64fe8b2a57d3eff7"}})
+	assert.Equal(t, errNoSpanContext, err)
+}
+
+func TestIsB3Debug(t *testing.T) {
+	assert.False(t, IsB3Debug(http.Header{}))
+	assert.True(t, IsB3Debug(http.Header{B3FlagsHeader: {"1"}}))
+	assert.True(t, IsB3Debug(http.Header{"B3": {"d"}}))
+	assert.True(t, IsB3Debug(http.Header{"B3": {"80f198ee56343ba8-e457b5a2e4d86bd1-d"}}))
+	assert.False(t, IsB3Debug(http.Header{"B3": {"80f198ee56343ba8-e457b5a2e4d86bd1-1"}}))
+}
@@ -0,0 +1,82 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package propagation parses and serializes the HTTP headers propagating the
+// identity of a span between services: the W3C Trace Context traceparent and
+// tracestate headers, and the B3 single and multiple headers.
+package propagation
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+var (
+	errNoSpanContext  = errors.New("no span context in the headers")
+	errInvalidTraceID = errors.New("invalid trace ID")
+	errInvalidSpanID  = errors.New("invalid span ID")
+)
+
+// SpanContext is the identity of a span propagated between services.
+type SpanContext struct {
+	// TraceID is the 16 bytes trace ID, the 64-bit trace IDs of B3 are
+	// left-padded with zeros.
+	TraceID []byte
+	// SpanID is the 8 bytes span ID.
+	SpanID []byte
+	// ParentSpanID is the 8 bytes ID of the parent span, only propagated by
+	// B3, nil if not set.
+	ParentSpanID []byte
+	// Sampled is whether the span was sampled by the caller.
+	Sampled bool
+	// Debug is whether the caller forced the sampling of the trace, only
+	// propagated by B3.
+	Debug bool
+	// Tracestate is the vendor specific state of the trace, only propagated
+	// by W3C Trace Context, nil if not set.
+	Tracestate *tracepb.Span_Tracestate
+}
+
+// Extract returns the span context of the headers, from the W3C Trace
+// Context headers if present or else from the B3 ones. It returns an error if
+// none of them is present or if the present ones are invalid.
+func Extract(h http.Header) (SpanContext, error) {
+	if h.Get(TraceparentHeader) != "" {
+		return ExtractW3C(h)
+	}
+	return ExtractB3(h)
+}
+
+// parseID decodes the hex ID of size bytes, left-padding with zeros the IDs
+// of padFrom bytes or more, and checks that it isn't all zeros.
+func parseID(s string, size, padFrom int, errInvalid error) ([]byte, error) {
+	if len(s)%2 != 0 || len(s) < 2*padFrom || len(s) > 2*size {
+		return nil, errInvalid
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errInvalid
+	}
+	id := make([]byte, size)
+	copy(id[size-len(decoded):], decoded)
+	for _, b := range id {
+		if b != 0 {
+			return id, nil
+		}
+	}
+	return nil, errInvalid
+}
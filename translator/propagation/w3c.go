@@ -0,0 +1,166 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+// The headers of W3C Trace Context, see https://www.w3.org/TR/trace-context/.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+const (
+	traceparentVersion = "00"
+	// sampledFlag is the bit of the trace flags set when the span was sampled.
+	sampledFlag = 0x01
+	// maxTracestateEntries is the maximum number of entries of a tracestate.
+	maxTracestateEntries = 32
+)
+
+var (
+	errInvalidTraceparent = errors.New("invalid traceparent header")
+	errInvalidTracestate  = errors.New("invalid tracestate header")
+)
+
+// ParseTraceparent parses the value of a traceparent header, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". Values of
+// future versions are accepted as long as they start with the fields of the
+// version 00.
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[3]) != 2 {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || version[0] == 0xff || (parts[0] == traceparentVersion && len(parts) != 4) {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	traceID, err := parseID(parts[1], 16, 16, errInvalidTraceID)
+	if err != nil {
+		return SpanContext{}, err
+	}
+	spanID, err := parseID(parts[2], 8, 8, errInvalidSpanID)
+	if err != nil {
+		return SpanContext{}, err
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flags[0]&sampledFlag != 0,
+	}, nil
+}
+
+// FormatTraceparent returns the value of the traceparent header of the span
+// context, using the version 00.
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled || sc.Debug {
+		flags = "01"
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", traceparentVersion, hex.EncodeToString(sc.TraceID), hex.EncodeToString(sc.SpanID), flags)
+}
+
+// ParseTracestate parses the value of a tracestate header, e.g.
+// "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE". The entries keep the order of
+// the header, empty entries are ignored.
+func ParseTracestate(value string) (*tracepb.Span_Tracestate, error) {
+	var entries []*tracepb.Span_Tracestate_Entry
+	keys := make(map[string]bool)
+	for _, member := range strings.Split(value, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+		eq := strings.IndexByte(member, '=')
+		if eq <= 0 || eq == len(member)-1 {
+			return nil, errInvalidTracestate
+		}
+		key, val := member[:eq], member[eq+1:]
+		if keys[key] {
+			return nil, fmt.Errorf("%v: duplicate key %q", errInvalidTracestate, key)
+		}
+		keys[key] = true
+		entries = append(entries, &tracepb.Span_Tracestate_Entry{Key: key, Value: val})
+	}
+	if len(entries) > maxTracestateEntries {
+		return nil, fmt.Errorf("%v: more than %d entries", errInvalidTracestate, maxTracestateEntries)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &tracepb.Span_Tracestate{Entries: entries}, nil
+}
+
+// FormatTracestate returns the value of the tracestate header of the
+// tracestate, empty if it has no entries.
+func FormatTracestate(ts *tracepb.Span_Tracestate) string {
+	if ts == nil {
+		return ""
+	}
+	members := make([]string, 0, len(ts.Entries))
+	for _, entry := range ts.Entries {
+		if entry == nil {
+			continue
+		}
+		members = append(members, entry.Key+"="+entry.Value)
+	}
+	return strings.Join(members, ",")
+}
+
+// ExtractW3C returns the span context of the traceparent and tracestate
+// headers. An invalid tracestate is ignored as required by the
+// specification.
+func ExtractW3C(h http.Header) (SpanContext, error) {
+	traceparent := h.Get(TraceparentHeader)
+	if traceparent == "" {
+		return SpanContext{}, errNoSpanContext
+	}
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return SpanContext{}, err
+	}
+	// Multiple tracestate headers are combined as a single one.
+	if ts, err := ParseTracestate(strings.Join(h[http.CanonicalHeaderKey(TracestateHeader)], ",")); err == nil {
+		sc.Tracestate = ts
+	}
+	return sc, nil
+}
+
+// InjectW3C sets the traceparent and, if the span context has a tracestate,
+// the tracestate headers.
+func InjectW3C(sc SpanContext, h http.Header) {
+	h.Set(TraceparentHeader, FormatTraceparent(sc))
+	if ts := FormatTracestate(sc.Tracestate); ts != "" {
+		h.Set(TracestateHeader, ts)
+	} else {
+		h.Del(TracestateHeader)
+	}
+}
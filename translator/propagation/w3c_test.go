@@ -0,0 +1,137 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTraceID = []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	testSpanID  = []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	assert.Equal(t, SpanContext{TraceID: testTraceID, SpanID: testSpanID, Sampled: true}, sc)
+
+	sc, err = ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.NoError(t, err)
+	assert.False(t, sc.Sampled)
+
+	// Future versions can have more fields.
+	sc, err = ParseTraceparent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-09-what-the-future-will-be-like")
+	require.NoError(t, err)
+	assert.Equal(t, SpanContext{TraceID: testTraceID, SpanID: testSpanID, Sampled: true}, sc)
+}
+
+func TestParseTraceparent_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"0-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	}
+	for _, value := range tests {
+		_, err := ParseTraceparent(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFormatTraceparent(t *testing.T) {
+	sc := SpanContext{TraceID: testTraceID, SpanID: testSpanID}
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", FormatTraceparent(sc))
+	sc.Sampled = true
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", FormatTraceparent(sc))
+}
+
+func TestParseTracestate(t *testing.T) {
+	ts, err := ParseTracestate("rojo=00f067aa0ba902b7, ,congo=t61rcWkgMzE")
+	require.NoError(t, err)
+	assert.Equal(t, &tracepb.Span_Tracestate{
+		Entries: []*tracepb.Span_Tracestate_Entry{
+			{Key: "rojo", Value: "00f067aa0ba902b7"},
+			{Key: "congo", Value: "t61rcWkgMzE"},
+		},
+	}, ts)
+	assert.Equal(t, "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", FormatTracestate(ts))
+
+	ts, err = ParseTracestate("")
+	require.NoError(t, err)
+	assert.Nil(t, ts)
+	assert.Equal(t, "", FormatTracestate(ts))
+
+	for _, value := range []string{"rojo", "=value", "rojo=", "rojo=1,rojo=2"} {
+		_, err := ParseTracestate(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestExtractInjectW3C(t *testing.T) {
+	h := http.Header{}
+	h.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Add("Tracestate", "rojo=00f067aa0ba902b7")
+	h.Add("Tracestate", "congo=t61rcWkgMzE")
+
+	sc, err := ExtractW3C(h)
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, sc.TraceID)
+	require.NotNil(t, sc.Tracestate)
+	assert.Len(t, sc.Tracestate.Entries, 2)
+
+	injected := http.Header{}
+	InjectW3C(sc, injected)
+	assert.Equal(t, h.Get(TraceparentHeader), injected.Get(TraceparentHeader))
+	assert.Equal(t, "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", injected.Get(TracestateHeader))
+
+	// An invalid tracestate is ignored.
+	h.Set("Tracestate", "invalid")
+	sc, err = ExtractW3C(h)
+	require.NoError(t, err)
+	assert.Nil(t, sc.Tracestate)
+
+	_, err = ExtractW3C(http.Header{})
+	assert.Equal(t, errNoSpanContext, err)
+}
+
+func TestExtract(t *testing.T) {
+	// W3C Trace Context takes precedence over B3.
+	h := http.Header{}
+	h.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Set(B3SingleHeader, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1")
+	sc, err := Extract(h)
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, sc.TraceID)
+
+	h.Del(TraceparentHeader)
+	sc, err = Extract(h)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xe4, 0x57, 0xb5, 0xa2, 0xe4, 0xd8, 0x6b, 0xd1}, sc.SpanID)
+
+	_, err = Extract(http.Header{})
+	assert.Equal(t, errNoSpanContext, err)
+}
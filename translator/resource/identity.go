@@ -0,0 +1,97 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcetranslator
+
+import (
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+)
+
+// IdentityOverrides rewrites the Node and the Resource reported by the
+// clients, e.g. on a shared gateway whose clients can't be trusted to identify
+// themselves. The Node and the Resource received are never modified, they are
+// copied when they are rewritten.
+type IdentityOverrides struct {
+	// StripNode drops the Node reported by the clients.
+	StripNode bool `mapstructure:"strip-node"`
+
+	// StripNodeIdentifier drops the host name, pid and start time of the Node.
+	StripNodeIdentifier bool `mapstructure:"strip-node-identifier"`
+
+	// StripNodeAttributes drops the attributes of the Node.
+	StripNodeAttributes bool `mapstructure:"strip-node-attributes"`
+
+	// ResourceLabels are set on the Resource, overriding the labels reported
+	// by the clients, e.g. to force deployment.environment. A Resource is
+	// created when the clients don't report one.
+	ResourceLabels map[string]string `mapstructure:"resource-labels"`
+
+	// ResourceType, if not empty, overrides the type of the Resource.
+	ResourceType string `mapstructure:"resource-type"`
+}
+
+// Node returns the Node to use instead of the given one, nil if it is
+// stripped. A nil receiver returns the Node unchanged.
+func (o *IdentityOverrides) Node(node *commonpb.Node) *commonpb.Node {
+	if o == nil || node == nil {
+		return node
+	}
+	if o.StripNode {
+		return nil
+	}
+	if !(o.StripNodeIdentifier && node.Identifier != nil) && !(o.StripNodeAttributes && node.Attributes != nil) {
+		return node
+	}
+	stripped := &commonpb.Node{
+		Identifier:  node.Identifier,
+		LibraryInfo: node.LibraryInfo,
+		ServiceInfo: node.ServiceInfo,
+		Attributes:  node.Attributes,
+	}
+	if o.StripNodeIdentifier {
+		stripped.Identifier = nil
+	}
+	if o.StripNodeAttributes {
+		stripped.Attributes = nil
+	}
+	return stripped
+}
+
+// Resource returns the Resource to use instead of the given one. A nil
+// receiver returns the Resource unchanged.
+func (o *IdentityOverrides) Resource(resource *resourcepb.Resource) *resourcepb.Resource {
+	if o == nil || (len(o.ResourceLabels) == 0 && o.ResourceType == "") {
+		return resource
+	}
+	overridden := &resourcepb.Resource{}
+	if resource != nil {
+		overridden.Type = resource.Type
+		overridden.Labels = resource.Labels
+	}
+	if o.ResourceType != "" {
+		overridden.Type = o.ResourceType
+	}
+	if len(o.ResourceLabels) > 0 {
+		labels := make(map[string]string, len(overridden.Labels)+len(o.ResourceLabels))
+		for k, v := range overridden.Labels {
+			labels[k] = v
+		}
+		for k, v := range o.ResourceLabels {
+			labels[k] = v
+		}
+		overridden.Labels = labels
+	}
+	return overridden
+}
@@ -0,0 +1,111 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcetranslator
+
+import (
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityOverridesNode(t *testing.T) {
+	node := &commonpb.Node{
+		Identifier:  &commonpb.ProcessIdentifier{HostName: "host1", Pid: 123},
+		ServiceInfo: &commonpb.ServiceInfo{Name: "svc"},
+		Attributes:  map[string]string{"attr": "value"},
+	}
+
+	tests := []struct {
+		name      string
+		overrides *IdentityOverrides
+		want      *commonpb.Node
+	}{
+		{
+			name: "nil overrides",
+			want: node,
+		},
+		{
+			name:      "no overrides",
+			overrides: &IdentityOverrides{},
+			want:      node,
+		},
+		{
+			name:      "strip node",
+			overrides: &IdentityOverrides{StripNode: true},
+		},
+		{
+			name:      "strip identifier",
+			overrides: &IdentityOverrides{StripNodeIdentifier: true},
+			want: &commonpb.Node{
+				ServiceInfo: &commonpb.ServiceInfo{Name: "svc"},
+				Attributes:  map[string]string{"attr": "value"},
+			},
+		},
+		{
+			name:      "strip attributes",
+			overrides: &IdentityOverrides{StripNodeAttributes: true},
+			want: &commonpb.Node{
+				Identifier:  &commonpb.ProcessIdentifier{HostName: "host1", Pid: 123},
+				ServiceInfo: &commonpb.ServiceInfo{Name: "svc"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.overrides.Node(node))
+			// The received Node is never modified.
+			assert.NotNil(t, node.Identifier)
+			assert.NotNil(t, node.Attributes)
+		})
+	}
+
+	assert.Nil(t, (&IdentityOverrides{StripNodeIdentifier: true}).Node(nil))
+}
+
+func TestIdentityOverridesResource(t *testing.T) {
+	overrides := &IdentityOverrides{
+		ResourceLabels: map[string]string{"deployment.environment": "prod"},
+		ResourceType:   "service",
+	}
+
+	resource := &resourcepb.Resource{
+		Type: "host",
+		Labels: map[string]string{
+			"deployment.environment": "dev",
+			"service.name":           "svc",
+		},
+	}
+	assert.Equal(t, &resourcepb.Resource{
+		Type: "service",
+		Labels: map[string]string{
+			"deployment.environment": "prod",
+			"service.name":           "svc",
+		},
+	}, overrides.Resource(resource))
+	// The received Resource is never modified.
+	assert.Equal(t, "dev", resource.Labels["deployment.environment"])
+	assert.Equal(t, "host", resource.Type)
+
+	assert.Equal(t, &resourcepb.Resource{
+		Type:   "service",
+		Labels: map[string]string{"deployment.environment": "prod"},
+	}, overrides.Resource(nil))
+
+	assert.Equal(t, resource, (&IdentityOverrides{StripNode: true}).Resource(resource))
+	var nilOverrides *IdentityOverrides
+	assert.Nil(t, nilOverrides.Resource(nil))
+}
@@ -0,0 +1,100 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcetranslator contains helpers to translate between the
+// OpenCensus Node and Resource representations.
+package resourcetranslator
+
+import (
+	"strconv"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Resource label keys synthesized from the OpenCensus Node.
+const (
+	LabelServiceName        = "service.name"
+	LabelHostHostname       = "host.hostname"
+	LabelProcessPID         = "process.pid"
+	LabelProcessStartTime   = "process.start_time"
+	LabelSDKLanguage        = "telemetry.sdk.language"
+	LabelSDKVersion         = "telemetry.sdk.version"
+	LabelSDKExporterVersion = "opencensus.exporterversion"
+)
+
+// sdkLanguages maps the OpenCensus library languages to the values used for
+// the telemetry.sdk.language label.
+var sdkLanguages = map[commonpb.LibraryInfo_Language]string{
+	commonpb.LibraryInfo_CPP:     "cpp",
+	commonpb.LibraryInfo_C_SHARP: "dotnet",
+	commonpb.LibraryInfo_ERLANG:  "erlang",
+	commonpb.LibraryInfo_GO_LANG: "go",
+	commonpb.LibraryInfo_JAVA:    "java",
+	commonpb.LibraryInfo_NODE_JS: "nodejs",
+	commonpb.LibraryInfo_PHP:     "php",
+	commonpb.LibraryInfo_PYTHON:  "python",
+	commonpb.LibraryInfo_RUBY:    "ruby",
+	commonpb.LibraryInfo_WEB_JS:  "webjs",
+}
+
+// NodeToResource synthesizes a Resource from the information available in the
+// given Node: service name, host name, process id and start time, library
+// language and versions. Node attributes are copied as labels too, but never
+// override the labels above. It returns nil if the Node has no information
+// that can be represented as a Resource.
+func NodeToResource(node *commonpb.Node) *resourcepb.Resource {
+	if node == nil {
+		return nil
+	}
+
+	labels := make(map[string]string, len(node.Attributes)+7)
+	for k, v := range node.Attributes {
+		labels[k] = v
+	}
+
+	if node.ServiceInfo != nil && node.ServiceInfo.Name != "" {
+		labels[LabelServiceName] = node.ServiceInfo.Name
+	}
+
+	if node.Identifier != nil {
+		if node.Identifier.HostName != "" {
+			labels[LabelHostHostname] = node.Identifier.HostName
+		}
+		if node.Identifier.Pid != 0 {
+			labels[LabelProcessPID] = strconv.FormatUint(uint64(node.Identifier.Pid), 10)
+		}
+		if node.Identifier.StartTimestamp != nil && node.Identifier.StartTimestamp.Seconds != 0 {
+			labels[LabelProcessStartTime] = ptypes.TimestampString(node.Identifier.StartTimestamp)
+		}
+	}
+
+	if ocLib := node.LibraryInfo; ocLib != nil {
+		if language, ok := sdkLanguages[ocLib.Language]; ok {
+			labels[LabelSDKLanguage] = language
+		}
+		if ocLib.CoreLibraryVersion != "" {
+			labels[LabelSDKVersion] = ocLib.CoreLibraryVersion
+		}
+		if ocLib.ExporterVersion != "" {
+			labels[LabelSDKExporterVersion] = ocLib.ExporterVersion
+		}
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+	return &resourcepb.Resource{Labels: labels}
+}
@@ -0,0 +1,103 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcetranslator
+
+import (
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeToResource(t *testing.T) {
+	tests := []struct {
+		name string
+		node *commonpb.Node
+		want *resourcepb.Resource
+	}{
+		{
+			name: "nil node",
+		},
+		{
+			name: "empty node",
+			node: &commonpb.Node{},
+		},
+		{
+			name: "full node",
+			node: &commonpb.Node{
+				Identifier: &commonpb.ProcessIdentifier{
+					HostName:       "host1",
+					Pid:            123,
+					StartTimestamp: &timestamp.Timestamp{Seconds: 1562000000},
+				},
+				LibraryInfo: &commonpb.LibraryInfo{
+					Language:           commonpb.LibraryInfo_GO_LANG,
+					CoreLibraryVersion: "v0.22.0",
+					ExporterVersion:    "v0.6.0",
+				},
+				ServiceInfo: &commonpb.ServiceInfo{Name: "svc"},
+				Attributes: map[string]string{
+					"attr":           "value",
+					LabelServiceName: "overridden",
+				},
+			},
+			want: &resourcepb.Resource{
+				Labels: map[string]string{
+					"attr":                  "value",
+					LabelServiceName:        "svc",
+					LabelHostHostname:       "host1",
+					LabelProcessPID:         "123",
+					LabelProcessStartTime:   "2019-07-01T16:53:20Z",
+					LabelSDKLanguage:        "go",
+					LabelSDKVersion:         "v0.22.0",
+					LabelSDKExporterVersion: "v0.6.0",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NodeToResource(tt.node))
+		})
+	}
+}
+
+func TestNodeToResource_SDKLanguage(t *testing.T) {
+	tests := []struct {
+		language commonpb.LibraryInfo_Language
+		want     string
+	}{
+		{commonpb.LibraryInfo_CPP, "cpp"},
+		{commonpb.LibraryInfo_C_SHARP, "dotnet"},
+		{commonpb.LibraryInfo_ERLANG, "erlang"},
+		{commonpb.LibraryInfo_GO_LANG, "go"},
+		{commonpb.LibraryInfo_JAVA, "java"},
+		{commonpb.LibraryInfo_NODE_JS, "nodejs"},
+		{commonpb.LibraryInfo_PHP, "php"},
+		{commonpb.LibraryInfo_PYTHON, "python"},
+		{commonpb.LibraryInfo_RUBY, "ruby"},
+		{commonpb.LibraryInfo_WEB_JS, "webjs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language.String(), func(t *testing.T) {
+			res := NodeToResource(&commonpb.Node{LibraryInfo: &commonpb.LibraryInfo{Language: tt.language}})
+			assert.Equal(t, tt.want, res.Labels[LabelSDKLanguage])
+		})
+	}
+
+	assert.Nil(t, NodeToResource(&commonpb.Node{LibraryInfo: &commonpb.LibraryInfo{}}))
+}
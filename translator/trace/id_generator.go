@@ -0,0 +1,201 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetranslator
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+const (
+	// IDGeneratorRandom is the type of the IDGenerator that generates random IDs.
+	IDGeneratorRandom = "random"
+	// IDGeneratorHash is the type of the IDGenerator that derives IDs from the
+	// content of the span, so the same input always gets the same IDs.
+	IDGeneratorHash = "hash"
+)
+
+// IDGenerator generates trace and span IDs for spans that were received
+// without them.
+type IDGenerator interface {
+	// TraceID returns a 16 bytes trace ID for the trace that the given span
+	// belongs to. The span is the topmost span of the trace that is known, its
+	// ParentSpanId may still reference a span that was not received.
+	TraceID(root *tracepb.Span) []byte
+	// SpanID returns an 8 bytes span ID for the given span of the given trace.
+	SpanID(traceID []byte, span *tracepb.Span) []byte
+}
+
+// NewIDGenerator returns the IDGenerator of the given type. An empty type
+// returns a nil IDGenerator, meaning that IDs should not be generated.
+func NewIDGenerator(generatorType string) (IDGenerator, error) {
+	switch generatorType {
+	case "":
+		return nil, nil
+	case IDGeneratorRandom:
+		return randomIDGenerator{}, nil
+	case IDGeneratorHash:
+		return hashIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID generator type %q", generatorType)
+	}
+}
+
+// FillMissingIDs sets the trace ID and span ID of the spans that do not have
+// them using the given IDGenerator. Spans that already have IDs are not modified.
+//
+// Spans are linked through their ParentSpanId within the batch: a span without
+// a trace ID takes the trace ID of its closest ancestor that has one, otherwise
+// all the spans under the same root share a single generated trace ID.
+func FillMissingIDs(gen IDGenerator, spans []*tracepb.Span) {
+	bySpanID := make(map[string]*tracepb.Span, len(spans))
+	for _, span := range spans {
+		if span != nil && !isZeroID(span.SpanId) {
+			bySpanID[string(span.SpanId)] = span
+		}
+	}
+
+	generated := make(map[*tracepb.Span][]byte)
+	for _, span := range spans {
+		if span == nil || !isZeroID(span.TraceId) {
+			continue
+		}
+		root := findRoot(span, bySpanID)
+		if !isZeroID(root.TraceId) {
+			span.TraceId = root.TraceId
+			continue
+		}
+		traceID, ok := generated[root]
+		if !ok {
+			traceID = gen.TraceID(root)
+			generated[root] = traceID
+		}
+		span.TraceId = traceID
+	}
+
+	for _, span := range spans {
+		if span != nil && isZeroID(span.SpanId) {
+			span.SpanId = gen.SpanID(span.TraceId, span)
+		}
+	}
+}
+
+// findRoot walks up the parents of the span that are in the batch and returns
+// the first one that has a trace ID, or the topmost one if none has.
+func findRoot(span *tracepb.Span, bySpanID map[string]*tracepb.Span) *tracepb.Span {
+	visited := map[*tracepb.Span]bool{span: true}
+	for isZeroID(span.TraceId) && !isZeroID(span.ParentSpanId) {
+		parent, ok := bySpanID[string(span.ParentSpanId)]
+		if !ok || visited[parent] {
+			break
+		}
+		visited[parent] = true
+		span = parent
+	}
+	return span
+}
+
+func isZeroID(id []byte) bool {
+	for _, b := range id {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type randomIDGenerator struct{}
+
+var _ IDGenerator = randomIDGenerator{}
+
+func (randomIDGenerator) TraceID(root *tracepb.Span) []byte {
+	return randomBytes(16)
+}
+
+func (randomIDGenerator) SpanID(traceID []byte, span *tracepb.Span) []byte {
+	return randomBytes(8)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	// crypto/rand.Read never returns an error on the supported platforms,
+	// make sure the ID is not all zeros anyway.
+	if _, err := rand.Read(b); err != nil || isZeroID(b) {
+		b[n-1] = 1
+	}
+	return b
+}
+
+type hashIDGenerator struct{}
+
+var _ IDGenerator = hashIDGenerator{}
+
+// TraceID hashes the parent span ID of the root if it has one, so the spans
+// of a trace whose root was not received still share a trace ID, or the fields
+// of the root that identify the operation otherwise.
+func (hashIDGenerator) TraceID(root *tracepb.Span) []byte {
+	h := fnv.New128a()
+	if !isZeroID(root.ParentSpanId) {
+		h.Write(root.ParentSpanId)
+	} else {
+		h.Write(root.SpanId)
+		writeSpanIdentity(h, root)
+	}
+	return nonZero(h.Sum(nil))
+}
+
+// SpanID hashes the trace ID together with the parent span ID and the fields
+// of the span that identify the operation.
+func (hashIDGenerator) SpanID(traceID []byte, span *tracepb.Span) []byte {
+	h := fnv.New64a()
+	h.Write(traceID)
+	h.Write(span.ParentSpanId)
+	writeSpanIdentity(h, span)
+	return nonZero(h.Sum(nil))
+}
+
+type byteWriter interface {
+	Write(p []byte) (int, error)
+}
+
+func writeSpanIdentity(w byteWriter, span *tracepb.Span) {
+	if span.Name != nil {
+		w.Write([]byte(span.Name.Value))
+	}
+	var buf [8]byte
+	if span.StartTime != nil {
+		binary.BigEndian.PutUint64(buf[:], uint64(span.StartTime.Seconds))
+		w.Write(buf[:])
+		binary.BigEndian.PutUint64(buf[:], uint64(span.StartTime.Nanos))
+		w.Write(buf[:])
+	}
+	if span.EndTime != nil {
+		binary.BigEndian.PutUint64(buf[:], uint64(span.EndTime.Seconds))
+		w.Write(buf[:])
+		binary.BigEndian.PutUint64(buf[:], uint64(span.EndTime.Nanos))
+		w.Write(buf[:])
+	}
+}
+
+func nonZero(id []byte) []byte {
+	if isZeroID(id) {
+		id[len(id)-1] = 1
+	}
+	return id
+}
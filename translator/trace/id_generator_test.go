@@ -0,0 +1,158 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetranslator
+
+import (
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIDLessSpan() *tracepb.Span {
+	return &tracepb.Span{
+		Name:      &tracepb.TruncatableString{Value: "operation"},
+		StartTime: &timestamp.Timestamp{Seconds: 1562000000, Nanos: 10},
+		EndTime:   &timestamp.Timestamp{Seconds: 1562000001},
+	}
+}
+
+func TestNewIDGenerator(t *testing.T) {
+	gen, err := NewIDGenerator("")
+	assert.NoError(t, err)
+	assert.Nil(t, gen)
+
+	gen, err = NewIDGenerator(IDGeneratorRandom)
+	assert.NoError(t, err)
+	assert.NotNil(t, gen)
+
+	gen, err = NewIDGenerator(IDGeneratorHash)
+	assert.NoError(t, err)
+	assert.NotNil(t, gen)
+
+	_, err = NewIDGenerator("unknown")
+	assert.Error(t, err)
+}
+
+func TestFillMissingIDs_Hash(t *testing.T) {
+	gen, err := NewIDGenerator(IDGeneratorHash)
+	require.NoError(t, err)
+
+	span1, span2 := newIDLessSpan(), newIDLessSpan()
+	FillMissingIDs(gen, []*tracepb.Span{span1, nil, span2})
+
+	assert.Len(t, span1.TraceId, 16)
+	assert.Len(t, span1.SpanId, 8)
+	assert.Equal(t, span1.TraceId, span2.TraceId)
+	assert.Equal(t, span1.SpanId, span2.SpanId)
+
+	span3 := newIDLessSpan()
+	span3.Name.Value = "other-operation"
+	FillMissingIDs(gen, []*tracepb.Span{span3})
+	assert.NotEqual(t, span1.TraceId, span3.TraceId)
+}
+
+func TestFillMissingIDs_Random(t *testing.T) {
+	gen, err := NewIDGenerator(IDGeneratorRandom)
+	require.NoError(t, err)
+
+	span1, span2 := newIDLessSpan(), newIDLessSpan()
+	FillMissingIDs(gen, []*tracepb.Span{span1, span2})
+
+	assert.Len(t, span1.TraceId, 16)
+	assert.Len(t, span1.SpanId, 8)
+	assert.NotEqual(t, span1.TraceId, span2.TraceId)
+}
+
+func TestFillMissingIDs_KeepsExistingIDs(t *testing.T) {
+	gen, err := NewIDGenerator(IDGeneratorHash)
+	require.NoError(t, err)
+
+	span := newIDLessSpan()
+	span.TraceId = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	span.SpanId = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	FillMissingIDs(gen, []*tracepb.Span{span})
+
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, span.TraceId)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, span.SpanId)
+}
+
+func TestFillMissingIDs_ParentAndChild(t *testing.T) {
+	for _, generatorType := range []string{IDGeneratorHash, IDGeneratorRandom} {
+		t.Run(generatorType, func(t *testing.T) {
+			gen, err := NewIDGenerator(generatorType)
+			require.NoError(t, err)
+
+			parent := newIDLessSpan()
+			parent.SpanId = []byte{1, 1, 1, 1, 1, 1, 1, 1}
+			child := newIDLessSpan()
+			child.Name.Value = "child-operation"
+			child.ParentSpanId = parent.SpanId
+			grandChild := newIDLessSpan()
+			grandChild.Name.Value = "grand-child-operation"
+			grandChild.ParentSpanId = []byte{2, 2, 2, 2, 2, 2, 2, 2}
+			child.SpanId = grandChild.ParentSpanId
+			unrelated := newIDLessSpan()
+			unrelated.Name.Value = "unrelated-operation"
+
+			// The child is listed before its parent on purpose.
+			FillMissingIDs(gen, []*tracepb.Span{grandChild, child, unrelated, parent})
+
+			assert.Len(t, parent.TraceId, 16)
+			assert.Equal(t, parent.TraceId, child.TraceId)
+			assert.Equal(t, parent.TraceId, grandChild.TraceId)
+			assert.NotEqual(t, parent.TraceId, unrelated.TraceId)
+			assert.Equal(t, []byte{1, 1, 1, 1, 1, 1, 1, 1}, parent.SpanId)
+			assert.Len(t, unrelated.SpanId, 8)
+			assert.NotEqual(t, grandChild.SpanId, unrelated.SpanId)
+		})
+	}
+}
+
+func TestFillMissingIDs_ChildInheritsParentTraceID(t *testing.T) {
+	gen, err := NewIDGenerator(IDGeneratorHash)
+	require.NoError(t, err)
+
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	parent := newIDLessSpan()
+	parent.TraceId = traceID
+	parent.SpanId = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	child := newIDLessSpan()
+	child.ParentSpanId = parent.SpanId
+	FillMissingIDs(gen, []*tracepb.Span{child, parent})
+
+	assert.Equal(t, traceID, child.TraceId)
+	assert.Len(t, child.SpanId, 8)
+}
+
+func TestFillMissingIDs_Hash_SameParentAcrossBatches(t *testing.T) {
+	gen, err := NewIDGenerator(IDGeneratorHash)
+	require.NoError(t, err)
+
+	// Both spans are children of a span that is not in either batch.
+	parentSpanID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	span1 := newIDLessSpan()
+	span1.ParentSpanId = parentSpanID
+	span2 := newIDLessSpan()
+	span2.Name.Value = "other-operation"
+	span2.ParentSpanId = parentSpanID
+	FillMissingIDs(gen, []*tracepb.Span{span1})
+	FillMissingIDs(gen, []*tracepb.Span{span2})
+
+	assert.Equal(t, span1.TraceId, span2.TraceId)
+	assert.NotEqual(t, span1.SpanId, span2.SpanId)
+}
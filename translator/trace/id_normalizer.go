@@ -0,0 +1,198 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetranslator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+)
+
+const (
+	// TraceIDPaddingZero extends the 64-bit trace IDs to 128 bits with zeros,
+	// as done by the clients supporting 128-bit trace IDs when they propagate
+	// a 64-bit one.
+	TraceIDPaddingZero = "zero"
+	// TraceIDPaddingHash extends the 64-bit trace IDs to 128 bits with a hash
+	// of them, for the backends expecting the trace IDs to be random over
+	// their 128 bits.
+	TraceIDPaddingHash = "hash"
+)
+
+var (
+	// ErrZeroTraceID error returned when the TraceID is all zeros.
+	ErrZeroTraceID = errors.New("TraceID is zero")
+	// ErrZeroSpanID error returned when the SpanID is all zeros.
+	ErrZeroSpanID = errors.New("SpanID is zero")
+)
+
+// IDNormalization configures how the trace and span IDs of the spans received
+// from legacy Jaeger and Zipkin clients are normalized, for the backends that
+// require 128-bit trace IDs and unique span IDs.
+type IDNormalization struct {
+	// TraceIDPadding extends the 64-bit trace IDs to 128 bits, it is
+	// TraceIDPaddingZero or TraceIDPaddingHash, empty keeps them as is.
+	TraceIDPadding string `mapstructure:"trace-id-padding"`
+	// DropInvalid drops the spans with a missing, zero or wrongly sized trace
+	// or span ID.
+	DropInvalid bool `mapstructure:"drop-invalid"`
+	// RemapCollisions gives a new span ID to the spans sharing their trace and
+	// span IDs with another span, e.g. the server side of a Zipkin span shared
+	// by the client and the server, see RemapSpanID.
+	RemapCollisions bool `mapstructure:"remap-collisions"`
+}
+
+// Validate returns an error if the normalization is invalid.
+func (n *IDNormalization) Validate() error {
+	switch n.TraceIDPadding {
+	case "", TraceIDPaddingZero, TraceIDPaddingHash:
+		return nil
+	default:
+		return fmt.Errorf("unknown trace ID padding %q", n.TraceIDPadding)
+	}
+}
+
+// NormalizeIDs normalizes the IDs of the spans, including the trace IDs of
+// their links, and returns the spans that are kept. The spans are modified in
+// place and the returned slice shares the array of the given one. A nil
+// normalization keeps the spans as is.
+//
+// The span IDs colliding within the spans are remapped, the collisions with
+// spans of other batches can't be detected.
+func NormalizeIDs(n *IDNormalization, spans []*tracepb.Span) []*tracepb.Span {
+	if n == nil {
+		return spans
+	}
+
+	kept := spans[:0]
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		span.TraceId = n.normalizeTraceID(span.TraceId)
+		if span.Links != nil {
+			for _, link := range span.Links.Link {
+				if link != nil {
+					link.TraceId = n.normalizeTraceID(link.TraceId)
+				}
+			}
+		}
+		if n.DropInvalid && ValidateIDs(span) != nil {
+			continue
+		}
+		kept = append(kept, span)
+	}
+
+	if n.RemapCollisions {
+		remapCollisions(kept)
+	}
+	return kept
+}
+
+// normalizeTraceID returns the trace ID extended to 128 bits if it is a 64-bit
+// one, either 8 bytes or 16 bytes whose first 8 are zero.
+func (n *IDNormalization) normalizeTraceID(traceID []byte) []byte {
+	var low []byte
+	switch {
+	case n.TraceIDPadding == "":
+		return traceID
+	case len(traceID) == 8:
+		low = traceID
+	case len(traceID) == 16 && isZeroID(traceID[:8]):
+		low = traceID[8:]
+	default:
+		return traceID
+	}
+	if isZeroID(low) {
+		return traceID
+	}
+
+	// The ID can be shared with other spans, don't modify it.
+	normalized := make([]byte, 16)
+	copy(normalized[8:], low)
+	if n.TraceIDPadding == TraceIDPaddingHash {
+		h := fnv.New64a()
+		h.Write(low)
+		copy(normalized[:8], nonZero(h.Sum(nil)))
+	}
+	return normalized
+}
+
+// ValidateIDs returns an error if the trace or span ID of the span is
+// missing, zero or doesn't have the size of the OpenCensus IDs, 16 and 8
+// bytes, or if its parent span ID is set but doesn't have 8 bytes.
+func ValidateIDs(span *tracepb.Span) error {
+	switch {
+	case span.TraceId == nil:
+		return ErrNilTraceID
+	case len(span.TraceId) != 16:
+		return ErrWrongLenTraceID
+	case isZeroID(span.TraceId):
+		return ErrZeroTraceID
+	case span.SpanId == nil:
+		return ErrNilSpanID
+	case len(span.SpanId) != 8:
+		return ErrWrongLenSpanID
+	case isZeroID(span.SpanId):
+		return ErrZeroSpanID
+	case len(span.ParentSpanId) != 0 && len(span.ParentSpanId) != 8:
+		return fmt.Errorf("ParentSpanID: %v", ErrWrongLenSpanID)
+	}
+	return nil
+}
+
+// remapCollisions remaps the span IDs of the spans sharing their trace and
+// span IDs with another one. A client span keeps its ID, so that the server
+// side of a shared span becomes its child.
+func remapCollisions(spans []*tracepb.Span) {
+	byID := make(map[string]*tracepb.Span, len(spans))
+	for _, span := range spans {
+		key := string(span.TraceId) + string(span.SpanId)
+		first, ok := byID[key]
+		if !ok {
+			byID[key] = span
+			continue
+		}
+		if span.Kind == tracepb.Span_CLIENT && first.Kind != tracepb.Span_CLIENT {
+			byID[key] = span
+			span = first
+		}
+		RemapSpanID(span)
+	}
+}
+
+// RemapSpanID gives the span a new span ID and makes it the child of the span
+// with its original ID. It is meant for the server side of the spans shared by
+// a client and a server, as done by Zipkin, since most backends require unique
+// span IDs. The new ID is derived from the trace ID, span ID and kind of the
+// span, so the same span is remapped the same way whatever the batch it is in.
+func RemapSpanID(span *tracepb.Span) {
+	span.ParentSpanId = span.SpanId
+	span.SpanId = RemappedSpanID(span.TraceId, span.SpanId, span.Kind)
+}
+
+// RemappedSpanID returns the new span ID of a span remapped by RemapSpanID.
+func RemappedSpanID(traceID, spanID []byte, kind tracepb.Span_SpanKind) []byte {
+	h := fnv.New64a()
+	h.Write(traceID)
+	h.Write(spanID)
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(kind))
+	h.Write(buf[:])
+	return nonZero(h.Sum(nil))
+}
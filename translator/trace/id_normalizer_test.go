@@ -0,0 +1,164 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetranslator
+
+import (
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDNormalizationValidate(t *testing.T) {
+	for _, padding := range []string{"", TraceIDPaddingZero, TraceIDPaddingHash} {
+		n := &IDNormalization{TraceIDPadding: padding}
+		assert.NoError(t, n.Validate(), padding)
+	}
+	n := &IDNormalization{TraceIDPadding: "unknown"}
+	assert.Error(t, n.Validate())
+}
+
+func TestNormalizeIDs_Nil(t *testing.T) {
+	spans := []*tracepb.Span{{TraceId: []byte{1}}}
+	assert.Equal(t, spans, NormalizeIDs(nil, spans))
+	assert.Equal(t, []byte{1}, spans[0].TraceId)
+}
+
+func TestNormalizeIDs_TraceIDPadding(t *testing.T) {
+	low := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	padded := append(make([]byte, 8), low...)
+	full := []byte{9, 9, 9, 9, 9, 9, 9, 9, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	newSpans := func() []*tracepb.Span {
+		return []*tracepb.Span{
+			{TraceId: low, SpanId: low},
+			{TraceId: padded, SpanId: low},
+			{TraceId: full, SpanId: low},
+			{
+				TraceId: full,
+				SpanId:  low,
+				Links: &tracepb.Span_Links{
+					Link: []*tracepb.Span_Link{{TraceId: low, SpanId: low}},
+				},
+			},
+		}
+	}
+
+	spans := NormalizeIDs(&IDNormalization{}, newSpans())
+	assert.Equal(t, low, spans[0].TraceId)
+	assert.Equal(t, low, spans[3].Links.Link[0].TraceId)
+
+	spans = NormalizeIDs(&IDNormalization{TraceIDPadding: TraceIDPaddingZero}, newSpans())
+	require.Len(t, spans, 4)
+	assert.Equal(t, padded, spans[0].TraceId)
+	assert.Equal(t, padded, spans[1].TraceId)
+	assert.Equal(t, full, spans[2].TraceId)
+	assert.Equal(t, padded, spans[3].Links.Link[0].TraceId)
+	// The original IDs are not modified.
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, low)
+
+	spans = NormalizeIDs(&IDNormalization{TraceIDPadding: TraceIDPaddingHash}, newSpans())
+	require.Len(t, spans, 4)
+	assert.Len(t, spans[0].TraceId, 16)
+	assert.False(t, isZeroID(spans[0].TraceId[:8]))
+	assert.Equal(t, low, spans[0].TraceId[8:])
+	assert.Equal(t, spans[0].TraceId, spans[1].TraceId)
+	assert.Equal(t, full, spans[2].TraceId)
+	assert.Equal(t, spans[0].TraceId, spans[3].Links.Link[0].TraceId)
+}
+
+func TestNormalizeIDs_DropInvalid(t *testing.T) {
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	spanID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	valid := &tracepb.Span{TraceId: traceID, SpanId: spanID}
+	spans := []*tracepb.Span{
+		valid,
+		nil,
+		{SpanId: spanID},
+		{TraceId: make([]byte, 16), SpanId: spanID},
+		{TraceId: traceID},
+		{TraceId: traceID, SpanId: make([]byte, 8)},
+		{TraceId: traceID, SpanId: []byte{1}},
+		{TraceId: traceID, SpanId: spanID, ParentSpanId: []byte{1}},
+	}
+
+	got := NormalizeIDs(&IDNormalization{}, append([]*tracepb.Span(nil), spans...))
+	assert.Len(t, got, len(spans)-1)
+
+	got = NormalizeIDs(&IDNormalization{DropInvalid: true}, spans)
+	assert.Equal(t, []*tracepb.Span{valid}, got)
+}
+
+func TestValidateIDs(t *testing.T) {
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	spanID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	tests := []struct {
+		span *tracepb.Span
+		err  error
+	}{
+		{&tracepb.Span{TraceId: traceID, SpanId: spanID}, nil},
+		{&tracepb.Span{TraceId: traceID, SpanId: spanID, ParentSpanId: spanID}, nil},
+		{&tracepb.Span{SpanId: spanID}, ErrNilTraceID},
+		{&tracepb.Span{TraceId: spanID, SpanId: spanID}, ErrWrongLenTraceID},
+		{&tracepb.Span{TraceId: make([]byte, 16), SpanId: spanID}, ErrZeroTraceID},
+		{&tracepb.Span{TraceId: traceID}, ErrNilSpanID},
+		{&tracepb.Span{TraceId: traceID, SpanId: traceID}, ErrWrongLenSpanID},
+		{&tracepb.Span{TraceId: traceID, SpanId: make([]byte, 8)}, ErrZeroSpanID},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.err, ValidateIDs(test.span), "test %d", i)
+	}
+	assert.Error(t, ValidateIDs(&tracepb.Span{TraceId: traceID, SpanId: spanID, ParentSpanId: traceID}))
+}
+
+func TestNormalizeIDs_RemapCollisions(t *testing.T) {
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}
+	spanID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	parentID := []byte{8, 7, 6, 5, 4, 3, 2, 1}
+
+	newSpans := func() (server, client, other *tracepb.Span) {
+		server = &tracepb.Span{TraceId: traceID, SpanId: spanID, ParentSpanId: parentID, Kind: tracepb.Span_SERVER}
+		client = &tracepb.Span{TraceId: traceID, SpanId: spanID, ParentSpanId: parentID, Kind: tracepb.Span_CLIENT}
+		other = &tracepb.Span{TraceId: traceID, SpanId: parentID}
+		return server, client, other
+	}
+
+	server, client, other := newSpans()
+	NormalizeIDs(&IDNormalization{}, []*tracepb.Span{server, client, other})
+	assert.Equal(t, server.SpanId, client.SpanId)
+
+	server, client, other = newSpans()
+	NormalizeIDs(&IDNormalization{RemapCollisions: true}, []*tracepb.Span{server, client, other})
+	assert.Equal(t, spanID, client.SpanId)
+	assert.Equal(t, parentID, client.ParentSpanId)
+	assert.Equal(t, spanID, server.ParentSpanId)
+	assert.Len(t, server.SpanId, 8)
+	assert.NotEqual(t, spanID, server.SpanId)
+	assert.Equal(t, parentID, other.SpanId)
+	remapped := server.SpanId
+
+	// The remapping doesn't depend on the order of the spans.
+	server, client, _ = newSpans()
+	NormalizeIDs(&IDNormalization{RemapCollisions: true}, []*tracepb.Span{client, server})
+	assert.Equal(t, spanID, client.SpanId)
+	assert.Equal(t, remapped, server.SpanId)
+
+	// Nor on the batch the span is in.
+	server, _, _ = newSpans()
+	RemapSpanID(server)
+	assert.Equal(t, remapped, server.SpanId)
+	assert.Equal(t, spanID, server.ParentSpanId)
+}
@@ -15,6 +15,7 @@
 package jaeger
 
 import (
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -205,6 +206,43 @@ func ocLinksToJaegerReferencesProto(ocSpanLinks *tracepb.Span_Links) ([]jaeger.S
 	return jRefs, nil
 }
 
+// appendJaegerLogsFromOCLinksProto adds a log, at the start of the span, for
+// every link having attributes since Jaeger references can't have any. The
+// log identifies the link and has its attributes as fields.
+func appendJaegerLogsFromOCLinksProto(jLogs []jaeger.Log, ocSpanLinks *tracepb.Span_Links, startTime time.Time) []jaeger.Log {
+	if ocSpanLinks == nil {
+		return jLogs
+	}
+
+	for _, ocLink := range ocSpanLinks.Link {
+		if ocLink == nil || ocLink.Attributes == nil || len(ocLink.Attributes.AttributeMap) == 0 {
+			continue
+		}
+		jFields := append(ocSpanAttributesToJaegerTagsProto(ocLink.Attributes),
+			jaeger.KeyValue{
+				Key:   tracetranslator.LinkTraceIDKey,
+				VStr:  hex.EncodeToString(ocLink.TraceId),
+				VType: jaeger.ValueType_STRING,
+			},
+			jaeger.KeyValue{
+				Key:   tracetranslator.LinkSpanIDKey,
+				VStr:  hex.EncodeToString(ocLink.SpanId),
+				VType: jaeger.ValueType_STRING,
+			},
+			jaeger.KeyValue{
+				Key:   tracetranslator.LinkTypeKey,
+				VStr:  ocLink.Type.String(),
+				VType: jaeger.ValueType_STRING,
+			})
+		jLogs = append(jLogs, jaeger.Log{
+			Timestamp: startTime,
+			Fields:    jFields,
+		})
+	}
+
+	return jLogs
+}
+
 func timestampToTimeProto(ts *timestamp.Timestamp) (t time.Time) {
 	if ts == nil {
 		return
@@ -502,6 +540,7 @@ func ocSpansToJaegerSpansProto(ocSpans []*tracepb.Span) ([]*jaeger.Span, error)
 			!tracetranslator.OCAttributeKeyExist(ocSpan.Attributes, tracetranslator.TagStatusMsg) {
 			jSpan.Tags = appendJaegerTagFromOCStatusProto(jSpan.Tags, ocSpan.Status)
 		}
+		jSpan.Logs = appendJaegerLogsFromOCLinksProto(jSpan.Logs, ocSpan.Links, startTime)
 		jSpan.Tags = appendJaegerTagFromOCTracestateProto(jSpan.Tags, ocSpan.Tracestate)
 		jSpan.Tags = appendJaegerTagFromOCSameProcessAsParentSpanProto(jSpan.Tags, ocSpan.SameProcessAsParentSpan)
 		jSpan.Tags = appendJaegerTagFromOCChildSpanCountProto(jSpan.Tags, ocSpan.ChildSpanCount)
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
@@ -483,3 +484,117 @@ var ocBatches = []consumerdata.TraceData{
 		},
 	},
 }
+
+func TestOCTimeEventsToJaegerLogsProto(t *testing.T) {
+	ocTimeEvents := &tracepb.Span_TimeEvents{
+		TimeEvent: []*tracepb.Span_TimeEvent{
+			{
+				Time: &timestamp.Timestamp{Seconds: 1485467191, Nanos: 639875000},
+				Value: &tracepb.Span_TimeEvent_Annotation_{
+					Annotation: &tracepb.Span_TimeEvent_Annotation{
+						Description: &tracepb.TruncatableString{Value: "cache miss"},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"key": {Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "k1"}}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Time: &timestamp.Timestamp{Seconds: 1485467192},
+				Value: &tracepb.Span_TimeEvent_MessageEvent_{
+					MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+						Type:             tracepb.Span_TimeEvent_MessageEvent_RECEIVED,
+						Id:               7,
+						UncompressedSize: 1024,
+						CompressedSize:   512,
+					},
+				},
+			},
+		},
+	}
+
+	want := []jaeger.Log{
+		{
+			Timestamp: time.Unix(1485467191, 639875000).UTC(),
+			Fields: []jaeger.KeyValue{
+				{Key: "key", VStr: "k1", VType: jaeger.ValueType_STRING},
+				{Key: ocTimeEventAnnotationDescription, VStr: "cache miss", VType: jaeger.ValueType_STRING},
+			},
+		},
+		{
+			Timestamp: time.Unix(1485467192, 0).UTC(),
+			Fields: []jaeger.KeyValue{
+				{Key: ocTimeEventMessageEventType, VStr: "RECEIVED", VType: jaeger.ValueType_STRING},
+				{Key: ocTimeEventMessageEventID, VInt64: 7, VType: jaeger.ValueType_INT64},
+				{Key: ocTimeEventMessageEventUSize, VInt64: 1024, VType: jaeger.ValueType_INT64},
+				{Key: ocTimeEventMessageEventCSize, VInt64: 512, VType: jaeger.ValueType_INT64},
+			},
+		},
+	}
+
+	if got := ocTimeEventsToJaegerLogsProto(ocTimeEvents); !reflect.DeepEqual(want, got) {
+		t.Fatalf("Unsuccessful conversion\nGot:\n\t%v\nWant:\n\t%v", got, want)
+	}
+}
+
+func TestOCLinksToJaegerProto(t *testing.T) {
+	fakeTraceID := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	fakeSpanID := []byte{0, 1, 2, 3, 4, 5, 6, 7}
+	linkedSpanID := []byte{0, 0, 0, 0, 0, 0, 0, 9}
+	gb, err := OCProtoToJaegerProto(consumerdata.TraceData{
+		Spans: []*tracepb.Span{{
+			TraceId:   fakeTraceID,
+			SpanId:    fakeSpanID,
+			StartTime: &timestamp.Timestamp{Seconds: 1485467191},
+			Links: &tracepb.Span_Links{
+				Link: []*tracepb.Span_Link{
+					{
+						TraceId: fakeTraceID,
+						SpanId:  linkedSpanID,
+						Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
+					},
+					{
+						TraceId: fakeTraceID,
+						SpanId:  linkedSpanID,
+						Type:    tracepb.Span_Link_CHILD_LINKED_SPAN,
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"batch.index": {Value: &tracepb.AttributeValue_IntValue{IntValue: 3}},
+							},
+						},
+					},
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to translate OC batch to Jaeger Proto: %v", err)
+	}
+
+	gs := gb.Spans[0]
+	wantRefs := []jaeger.SpanRef{
+		{TraceID: gs.TraceID, SpanID: jaeger.SpanID(9), RefType: jaeger.SpanRefType_CHILD_OF},
+		{TraceID: gs.TraceID, SpanID: jaeger.SpanID(9), RefType: jaeger.SpanRefType_FOLLOWS_FROM},
+	}
+	if !reflect.DeepEqual(wantRefs, gs.References) {
+		t.Fatalf("Unsuccessful conversion of the links\nGot:\n\t%v\nWant:\n\t%v", gs.References, wantRefs)
+	}
+
+	// Only the link having attributes is kept as a log.
+	wantLogs := []jaeger.Log{
+		{
+			Timestamp: time.Unix(1485467191, 0).UTC(),
+			Fields: []jaeger.KeyValue{
+				{Key: "batch.index", VInt64: 3, VType: jaeger.ValueType_INT64},
+				{Key: tracetranslator.LinkTraceIDKey, VStr: "000102030405060708090a0b0c0d0e0f", VType: jaeger.ValueType_STRING},
+				{Key: tracetranslator.LinkSpanIDKey, VStr: "0000000000000009", VType: jaeger.ValueType_STRING},
+				{Key: tracetranslator.LinkTypeKey, VStr: "CHILD_LINKED_SPAN", VType: jaeger.ValueType_STRING},
+			},
+		},
+	}
+	if !reflect.DeepEqual(wantLogs, gs.Logs) {
+		t.Fatalf("Unsuccessful conversion of the link attributes\nGot:\n\t%v\nWant:\n\t%v", gs.Logs, wantLogs)
+	}
+}
@@ -15,6 +15,7 @@
 package jaeger
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
@@ -203,6 +204,7 @@ func ocSpansToJaegerSpans(ocSpans []*tracepb.Span) ([]*jaeger.Span, error) {
 			!tracetranslator.OCAttributeKeyExist(ocSpan.Attributes, tracetranslator.TagStatusMsg) {
 			jSpan.Tags = appendJaegerThriftTagFromOCStatus(jSpan.Tags, ocSpan.Status)
 		}
+		jSpan.Logs = appendJaegerLogsFromOCLinks(jSpan.Logs, ocSpan.Links, startTime)
 		jSpans = append(jSpans, jSpan)
 	}
 
@@ -249,6 +251,46 @@ func ocLinksToJaegerReferences(ocSpanLinks *tracepb.Span_Links) ([]*jaeger.SpanR
 	return jRefs, nil
 }
 
+// appendJaegerLogsFromOCLinks adds a log, at the start of the span, for every
+// link having attributes since Jaeger references can't have any. The log
+// identifies the link and has its attributes as fields.
+func appendJaegerLogsFromOCLinks(jLogs []*jaeger.Log, ocSpanLinks *tracepb.Span_Links, startTime int64) []*jaeger.Log {
+	if ocSpanLinks == nil {
+		return jLogs
+	}
+
+	for _, ocLink := range ocSpanLinks.Link {
+		if ocLink == nil || ocLink.Attributes == nil || len(ocLink.Attributes.AttributeMap) == 0 {
+			continue
+		}
+		traceID := hex.EncodeToString(ocLink.TraceId)
+		spanID := hex.EncodeToString(ocLink.SpanId)
+		linkType := ocLink.Type.String()
+		jFields := append(ocSpanAttributesToJaegerTags(ocLink.Attributes),
+			&jaeger.Tag{
+				Key:   tracetranslator.LinkTraceIDKey,
+				VStr:  &traceID,
+				VType: jaeger.TagType_STRING,
+			},
+			&jaeger.Tag{
+				Key:   tracetranslator.LinkSpanIDKey,
+				VStr:  &spanID,
+				VType: jaeger.TagType_STRING,
+			},
+			&jaeger.Tag{
+				Key:   tracetranslator.LinkTypeKey,
+				VStr:  &linkType,
+				VType: jaeger.TagType_STRING,
+			})
+		jLogs = append(jLogs, &jaeger.Log{
+			Timestamp: startTime,
+			Fields:    jFields,
+		})
+	}
+
+	return jLogs
+}
+
 func appendJaegerThriftTagFromOCStatus(jTags []*jaeger.Tag, ocStatus *tracepb.Status) []*jaeger.Tag {
 	if ocStatus == nil {
 		return jTags
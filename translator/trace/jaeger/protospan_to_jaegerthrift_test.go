@@ -537,3 +537,138 @@ var tds = []consumerdata.TraceData{
 		},
 	},
 }
+
+func TestOCTimeEventsToJaegerThriftLogs(t *testing.T) {
+	ocTimeEvents := &tracepb.Span_TimeEvents{
+		TimeEvent: []*tracepb.Span_TimeEvent{
+			{
+				Time: &timestamp.Timestamp{Seconds: 1485467191, Nanos: 639875000},
+				Value: &tracepb.Span_TimeEvent_Annotation_{
+					Annotation: &tracepb.Span_TimeEvent_Annotation{
+						Description: &tracepb.TruncatableString{Value: "cache miss"},
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"key": {Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "k1"}}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Time: &timestamp.Timestamp{Seconds: 1485467192},
+				Value: &tracepb.Span_TimeEvent_MessageEvent_{
+					MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+						Type:             tracepb.Span_TimeEvent_MessageEvent_SENT,
+						Id:               7,
+						UncompressedSize: 1024,
+						CompressedSize:   512,
+					},
+				},
+			},
+			{
+				Time: &timestamp.Timestamp{Seconds: 1485467193},
+				Value: &tracepb.Span_TimeEvent_MessageEvent_{
+					MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+						Type: tracepb.Span_TimeEvent_MessageEvent_RECEIVED,
+						Id:   8,
+					},
+				},
+			},
+		},
+	}
+
+	strPtr := func(s string) *string { return &s }
+	int64Ptr := func(i int64) *int64 { return &i }
+	want := []*jaeger.Log{
+		{
+			Timestamp: 1485467191639875,
+			Fields: []*jaeger.Tag{
+				{Key: "key", VStr: strPtr("k1"), VType: jaeger.TagType_STRING},
+				{Key: tracetranslator.AnnotationDescriptionKey, VStr: strPtr("cache miss"), VType: jaeger.TagType_STRING},
+			},
+		},
+		{
+			Timestamp: 1485467192000000,
+			Fields: []*jaeger.Tag{
+				{Key: tracetranslator.MessageEventIDKey, VLong: int64Ptr(7), VType: jaeger.TagType_LONG},
+				{Key: tracetranslator.MessageEventTypeKey, VStr: strPtr("SENT"), VType: jaeger.TagType_STRING},
+				{Key: tracetranslator.MessageEventCompressedSizeKey, VLong: int64Ptr(512), VType: jaeger.TagType_LONG},
+				{Key: tracetranslator.MessageEventUncompressedSizeKey, VLong: int64Ptr(1024), VType: jaeger.TagType_LONG},
+			},
+		},
+		{
+			// The sizes are omitted when both are zero.
+			Timestamp: 1485467193000000,
+			Fields: []*jaeger.Tag{
+				{Key: tracetranslator.MessageEventIDKey, VLong: int64Ptr(8), VType: jaeger.TagType_LONG},
+				{Key: tracetranslator.MessageEventTypeKey, VStr: strPtr("RECEIVED"), VType: jaeger.TagType_STRING},
+			},
+		},
+	}
+
+	if got := ocTimeEventsToJaegerLogs(ocTimeEvents); !reflect.DeepEqual(want, got) {
+		t.Fatalf("Unsuccessful conversion\nGot:\n\t%v\nWant:\n\t%v", got, want)
+	}
+}
+
+func TestOCLinksToJaegerThrift(t *testing.T) {
+	fakeTraceID := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	fakeSpanID := []byte{0, 1, 2, 3, 4, 5, 6, 7}
+	linkedSpanID := []byte{0, 0, 0, 0, 0, 0, 0, 9}
+	gb, err := OCProtoToJaegerThrift(consumerdata.TraceData{
+		Spans: []*tracepb.Span{{
+			TraceId:   fakeTraceID,
+			SpanId:    fakeSpanID,
+			StartTime: &timestamp.Timestamp{Seconds: 1485467191},
+			Links: &tracepb.Span_Links{
+				Link: []*tracepb.Span_Link{
+					{
+						TraceId: fakeTraceID,
+						SpanId:  linkedSpanID,
+						Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
+					},
+					{
+						TraceId: fakeTraceID,
+						SpanId:  linkedSpanID,
+						Type:    tracepb.Span_Link_CHILD_LINKED_SPAN,
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"batch.index": {Value: &tracepb.AttributeValue_IntValue{IntValue: 3}},
+							},
+						},
+					},
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to translate OC batch to Jaeger Thrift: %v", err)
+	}
+
+	gs := gb.Spans[0]
+	wantRefs := []*jaeger.SpanRef{
+		{TraceIdLow: gs.TraceIdLow, TraceIdHigh: gs.TraceIdHigh, SpanId: 9, RefType: jaeger.SpanRefType_CHILD_OF},
+		{TraceIdLow: gs.TraceIdLow, TraceIdHigh: gs.TraceIdHigh, SpanId: 9, RefType: jaeger.SpanRefType_FOLLOWS_FROM},
+	}
+	if !reflect.DeepEqual(wantRefs, gs.References) {
+		t.Fatalf("Unsuccessful conversion of the links\nGot:\n\t%v\nWant:\n\t%v", gs.References, wantRefs)
+	}
+
+	// Only the link having attributes is kept as a log.
+	strPtr := func(s string) *string { return &s }
+	int64Ptr := func(i int64) *int64 { return &i }
+	wantLogs := []*jaeger.Log{
+		{
+			Timestamp: 1485467191000000,
+			Fields: []*jaeger.Tag{
+				{Key: "batch.index", VLong: int64Ptr(3), VType: jaeger.TagType_LONG},
+				{Key: tracetranslator.LinkTraceIDKey, VStr: strPtr("000102030405060708090a0b0c0d0e0f"), VType: jaeger.TagType_STRING},
+				{Key: tracetranslator.LinkSpanIDKey, VStr: strPtr("0000000000000009"), VType: jaeger.TagType_STRING},
+				{Key: tracetranslator.LinkTypeKey, VStr: strPtr("CHILD_LINKED_SPAN"), VType: jaeger.TagType_STRING},
+			},
+		},
+	}
+	if !reflect.DeepEqual(wantLogs, gs.Logs) {
+		t.Fatalf("Unsuccessful conversion of the link attributes\nGot:\n\t%v\nWant:\n\t%v", gs.Logs, wantLogs)
+	}
+}
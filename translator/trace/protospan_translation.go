@@ -23,12 +23,18 @@ const (
 	MessageEventCompressedSizeKey   = "message.compressed_size"
 	MessageEventUncompressedSizeKey = "message.uncompressed_size"
 
+	LinkTraceIDKey = "link.trace_id"
+	LinkSpanIDKey  = "link.span_id"
+	LinkTypeKey    = "link.type"
+
 	TagSpanKind = "span.kind"
 
-	TagStatusCode       = "status.code"
-	TagStatusMsg        = "status.message"
-	TagHTTPStatusCode   = "http.status_code"
-	TagHTTPStatusMsg    = "http.status_message"
-	TagZipkinCensusCode = "census.status_code"
-	TagZipkinCensusMsg  = "census.status_description"
+	TagStatusCode         = "status.code"
+	TagStatusMsg          = "status.message"
+	TagHTTPStatusCode     = "http.status_code"
+	TagHTTPStatusMsg      = "http.status_message"
+	TagZipkinCensusCode   = "census.status_code"
+	TagZipkinCensusMsg    = "census.status_description"
+	TagError              = "error"
+	TagZipkinOCStatusDesc = "opencensus.status_description"
 )
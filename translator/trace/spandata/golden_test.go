@@ -0,0 +1,48 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spandata
+
+import (
+	"path/filepath"
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+
+	"github.com/open-telemetry/opentelemetry-service/translator/trace/tracetranslatortest"
+)
+
+func TestGolden_roundTrip(t *testing.T) {
+	for _, fixture := range tracetranslatortest.TraceFixtures() {
+		t.Run(fixture.Name, func(t *testing.T) {
+			var spanData []*trace.SpanData
+			var protoSpans []*tracepb.Span
+			for _, span := range fixture.Data.Spans {
+				sd, err := ProtoSpanToOCSpanData(span)
+				require.NoError(t, err)
+				spanData = append(spanData, sd)
+
+				protoSpan, err := OCSpanDataToProtoSpan(sd)
+				require.NoError(t, err)
+				protoSpans = append(protoSpans, protoSpan)
+			}
+
+			golden := filepath.Join("testdata", "golden", fixture.Name)
+			tracetranslatortest.AssertGolden(t, golden+"_spandata.json", spanData)
+			tracetranslatortest.AssertGolden(t, golden+"_roundtrip.json", protoSpans)
+		})
+	}
+}
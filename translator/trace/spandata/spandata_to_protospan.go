@@ -0,0 +1,202 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spandata
+
+import (
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/tracestate"
+
+	"github.com/open-telemetry/opentelemetry-service/internal"
+)
+
+// OCSpanDataToProtoSpan transforms a trace.SpanData into the equivalent protobuf span.
+func OCSpanDataToProtoSpan(sd *trace.SpanData) (*tracepb.Span, error) {
+	if sd == nil {
+		return nil, errNilSpan
+	}
+
+	var parentSpanID []byte
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		parentSpanID = sd.ParentSpanID[:]
+	}
+	span := &tracepb.Span{
+		TraceId:                 sd.TraceID[:],
+		SpanId:                  sd.SpanID[:],
+		ParentSpanId:            parentSpanID,
+		Tracestate:              ocTracestateToProtoTracestate(sd.Tracestate),
+		Name:                    truncatableString(sd.Name),
+		Kind:                    ocSpanKindToProtoSpanKind(sd.SpanKind),
+		StartTime:               internal.TimeToTimestamp(sd.StartTime),
+		EndTime:                 internal.TimeToTimestamp(sd.EndTime),
+		Attributes:              ocAttributesToProtoAttributes(sd.Attributes),
+		TimeEvents:              ocEventsToProtoTimeEvents(sd.Annotations, sd.MessageEvents),
+		Links:                   ocLinksToProtoLinks(sd.Links),
+		Status:                  ocStatusToProtoStatus(sd.Status),
+		SameProcessAsParentSpan: &wrappers.BoolValue{Value: !sd.HasRemoteParent},
+	}
+	return span, nil
+}
+
+func truncatableString(s string) *tracepb.TruncatableString {
+	if s == "" {
+		return nil
+	}
+	return &tracepb.TruncatableString{Value: s}
+}
+
+func ocStatusToProtoStatus(s trace.Status) *tracepb.Status {
+	if s.Code == 0 && s.Message == "" {
+		return nil
+	}
+	return &tracepb.Status{
+		Code:    s.Code,
+		Message: s.Message,
+	}
+}
+
+func ocTracestateToProtoTracestate(ts *tracestate.Tracestate) *tracepb.Span_Tracestate {
+	if ts == nil {
+		return nil
+	}
+	entries := ts.Entries()
+	protoEntries := make([]*tracepb.Span_Tracestate_Entry, 0, len(entries))
+	for _, entry := range entries {
+		protoEntries = append(protoEntries, &tracepb.Span_Tracestate_Entry{
+			Key:   entry.Key,
+			Value: entry.Value,
+		})
+	}
+	return &tracepb.Span_Tracestate{Entries: protoEntries}
+}
+
+func ocLinksToProtoLinks(links []trace.Link) *tracepb.Span_Links {
+	if len(links) == 0 {
+		return nil
+	}
+	sls := make([]*tracepb.Span_Link, 0, len(links))
+	for _, link := range links {
+		traceID := link.TraceID
+		spanID := link.SpanID
+		sls = append(sls, &tracepb.Span_Link{
+			TraceId:    traceID[:],
+			SpanId:     spanID[:],
+			Type:       ocLinkTypeToProtoLinkType(link.Type),
+			Attributes: ocAttributesToProtoAttributes(link.Attributes),
+		})
+	}
+	return &tracepb.Span_Links{Link: sls}
+}
+
+func ocLinkTypeToProtoLinkType(lt trace.LinkType) tracepb.Span_Link_Type {
+	switch lt {
+	case trace.LinkTypeChild:
+		return tracepb.Span_Link_CHILD_LINKED_SPAN
+	case trace.LinkTypeParent:
+		return tracepb.Span_Link_PARENT_LINKED_SPAN
+	default:
+		return tracepb.Span_Link_TYPE_UNSPECIFIED
+	}
+}
+
+func ocAttributesToProtoAttributes(attrs map[string]interface{}) *tracepb.Span_Attributes {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	attributeMap := make(map[string]*tracepb.AttributeValue, len(attrs))
+	droppedCount := int32(0)
+	for key, value := range attrs {
+		var attr *tracepb.AttributeValue
+		switch v := value.(type) {
+		case bool:
+			attr = &tracepb.AttributeValue{Value: &tracepb.AttributeValue_BoolValue{BoolValue: v}}
+		case int:
+			attr = &tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: int64(v)}}
+		case int64:
+			attr = &tracepb.AttributeValue{Value: &tracepb.AttributeValue_IntValue{IntValue: v}}
+		case float64:
+			attr = &tracepb.AttributeValue{Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: v}}
+		case string:
+			attr = &tracepb.AttributeValue{Value: &tracepb.AttributeValue_StringValue{
+				StringValue: &tracepb.TruncatableString{Value: v},
+			}}
+		default:
+			droppedCount++
+			continue
+		}
+		attributeMap[key] = attr
+	}
+	return &tracepb.Span_Attributes{
+		AttributeMap:           attributeMap,
+		DroppedAttributesCount: droppedCount,
+	}
+}
+
+func ocEventsToProtoTimeEvents(annotations []trace.Annotation, messageEvents []trace.MessageEvent) *tracepb.Span_TimeEvents {
+	if len(annotations) == 0 && len(messageEvents) == 0 {
+		return nil
+	}
+
+	timeEvents := make([]*tracepb.Span_TimeEvent, 0, len(annotations)+len(messageEvents))
+	for _, ann := range annotations {
+		timeEvents = append(timeEvents, &tracepb.Span_TimeEvent{
+			Time: internal.TimeToTimestamp(ann.Time),
+			Value: &tracepb.Span_TimeEvent_Annotation_{
+				Annotation: &tracepb.Span_TimeEvent_Annotation{
+					Description: truncatableString(ann.Message),
+					Attributes:  ocAttributesToProtoAttributes(ann.Attributes),
+				},
+			},
+		})
+	}
+	for _, me := range messageEvents {
+		timeEvents = append(timeEvents, &tracepb.Span_TimeEvent{
+			Time: internal.TimeToTimestamp(me.Time),
+			Value: &tracepb.Span_TimeEvent_MessageEvent_{
+				MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+					Type:             ocEventTypeToProtoMessageEventType(me.EventType),
+					Id:               uint64(me.MessageID),
+					UncompressedSize: uint64(me.UncompressedByteSize),
+					CompressedSize:   uint64(me.CompressedByteSize),
+				},
+			},
+		})
+	}
+	return &tracepb.Span_TimeEvents{TimeEvent: timeEvents}
+}
+
+func ocEventTypeToProtoMessageEventType(et trace.MessageEventType) tracepb.Span_TimeEvent_MessageEvent_Type {
+	switch et {
+	case trace.MessageEventTypeSent:
+		return tracepb.Span_TimeEvent_MessageEvent_SENT
+	case trace.MessageEventTypeRecv:
+		return tracepb.Span_TimeEvent_MessageEvent_RECEIVED
+	default:
+		return tracepb.Span_TimeEvent_MessageEvent_TYPE_UNSPECIFIED
+	}
+}
+
+func ocSpanKindToProtoSpanKind(kind int) tracepb.Span_SpanKind {
+	switch kind {
+	case trace.SpanKindClient:
+		return tracepb.Span_CLIENT
+	case trace.SpanKindServer:
+		return tracepb.Span_SERVER
+	default:
+		return tracepb.Span_SPAN_KIND_UNSPECIFIED
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spandata
+
+import (
+	"testing"
+	"time"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/tracestate"
+
+	"github.com/open-telemetry/opentelemetry-service/internal"
+)
+
+func TestOCSpanDataToProtoSpan(t *testing.T) {
+	endTime := time.Now().Round(time.Second)
+	startTime := endTime.Add(-90 * time.Second)
+
+	ocTracestate, err := tracestate.New(new(tracestate.Tracestate), tracestate.Entry{Key: "foo", Value: "bar"})
+	if err != nil {
+		t.Fatalf("Failed to create ocTracestate: %v", err)
+	}
+
+	sd := &trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID:    trace.TraceID{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F},
+			SpanID:     trace.SpanID{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8},
+			Tracestate: ocTracestate,
+		},
+		SpanKind:     trace.SpanKindClient,
+		ParentSpanID: trace.SpanID{0xEF, 0xEE, 0xED, 0xEC, 0xEB, 0xEA, 0xE9, 0xE8},
+		Name:         "exporter/jaeger",
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Annotations: []trace.Annotation{
+			{Time: startTime, Message: "queued", Attributes: map[string]interface{}{"size": int64(3)}},
+		},
+		MessageEvents: []trace.MessageEvent{
+			{Time: endTime, EventType: trace.MessageEventTypeSent, MessageID: 1, UncompressedByteSize: 1024, CompressedByteSize: 512},
+		},
+		Links: []trace.Link{
+			{
+				TraceID: trace.TraceID{0xC0, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF},
+				SpanID:  trace.SpanID{0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7},
+				Type:    trace.LinkTypeParent,
+			},
+		},
+		Status: trace.Status{
+			Code:    trace.StatusCodeUnavailable,
+			Message: "connection refused",
+		},
+		Attributes: map[string]interface{}{
+			"num_spans": int64(3),
+			"ratio":     0.5,
+			"exporter":  "jaeger",
+			"retried":   true,
+			"count":     2,
+			"unknown":   []string{"dropped"},
+		},
+	}
+
+	want := &tracepb.Span{
+		TraceId:      []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F},
+		SpanId:       []byte{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8},
+		ParentSpanId: []byte{0xEF, 0xEE, 0xED, 0xEC, 0xEB, 0xEA, 0xE9, 0xE8},
+		Tracestate: &tracepb.Span_Tracestate{
+			Entries: []*tracepb.Span_Tracestate_Entry{{Key: "foo", Value: "bar"}},
+		},
+		Name:      &tracepb.TruncatableString{Value: "exporter/jaeger"},
+		Kind:      tracepb.Span_CLIENT,
+		StartTime: internal.TimeToTimestamp(startTime),
+		EndTime:   internal.TimeToTimestamp(endTime),
+		Attributes: &tracepb.Span_Attributes{
+			AttributeMap: map[string]*tracepb.AttributeValue{
+				"num_spans": {Value: &tracepb.AttributeValue_IntValue{IntValue: 3}},
+				"ratio":     {Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 0.5}},
+				"exporter":  {Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "jaeger"}}},
+				"retried":   {Value: &tracepb.AttributeValue_BoolValue{BoolValue: true}},
+				"count":     {Value: &tracepb.AttributeValue_IntValue{IntValue: 2}},
+			},
+			DroppedAttributesCount: 1,
+		},
+		TimeEvents: &tracepb.Span_TimeEvents{
+			TimeEvent: []*tracepb.Span_TimeEvent{
+				{
+					Time: internal.TimeToTimestamp(startTime),
+					Value: &tracepb.Span_TimeEvent_Annotation_{
+						Annotation: &tracepb.Span_TimeEvent_Annotation{
+							Description: &tracepb.TruncatableString{Value: "queued"},
+							Attributes: &tracepb.Span_Attributes{
+								AttributeMap: map[string]*tracepb.AttributeValue{
+									"size": {Value: &tracepb.AttributeValue_IntValue{IntValue: 3}},
+								},
+							},
+						},
+					},
+				},
+				{
+					Time: internal.TimeToTimestamp(endTime),
+					Value: &tracepb.Span_TimeEvent_MessageEvent_{
+						MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+							Type: tracepb.Span_TimeEvent_MessageEvent_SENT, Id: 1, UncompressedSize: 1024, CompressedSize: 512,
+						},
+					},
+				},
+			},
+		},
+		Links: &tracepb.Span_Links{
+			Link: []*tracepb.Span_Link{
+				{
+					TraceId: []byte{0xC0, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF},
+					SpanId:  []byte{0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7},
+					Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
+				},
+			},
+		},
+		Status: &tracepb.Status{
+			Code:    trace.StatusCodeUnavailable,
+			Message: "connection refused",
+		},
+		SameProcessAsParentSpan: &wrappers.BoolValue{Value: true},
+	}
+
+	got, err := OCSpanDataToProtoSpan(sd)
+	if err != nil {
+		t.Fatalf("Failed to convert from OCSpanData to ProtoSpan: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Fatalf("Transformed span\n\tGot  %+v\n\tWant %+v", got, want)
+	}
+
+	// The translation back gives the original span data, except for the
+	// attributes the protobuf spans do not support.
+	back, err := ProtoSpanToOCSpanData(got)
+	if err != nil {
+		t.Fatalf("Failed to convert back to OCSpanData: %v", err)
+	}
+	if back.Name != sd.Name || back.SpanContext.SpanID != sd.SpanID || back.ParentSpanID != sd.ParentSpanID ||
+		back.HasRemoteParent != sd.HasRemoteParent || back.Status != sd.Status {
+		t.Fatalf("Span translated back\n\tGot  %+v\n\tWant %+v", back, sd)
+	}
+}
+
+func TestOCSpanDataToProtoSpan_nil(t *testing.T) {
+	if _, err := OCSpanDataToProtoSpan(nil); err != errNilSpan {
+		t.Fatalf("Got %v, want %v", err, errNilSpan)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracetranslatortest
+
+import (
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/open-telemetry/opentelemetry-service/consumer/consumerdata"
+	"github.com/open-telemetry/opentelemetry-service/internal"
+)
+
+// TraceFixture is trace data translated by the golden file tests, its name is
+// the prefix of their golden files.
+type TraceFixture struct {
+	Name string
+	Data consumerdata.TraceData
+}
+
+// TraceFixtures returns the trace data covering the edge cases of the
+// translators: links, events, status codes, 128-bit and 64-bit trace IDs,
+// attributes of every type and missing optional fields.
+func TraceFixtures() []TraceFixture {
+	start := time.Date(2019, 7, 1, 12, 30, 0, 123456000, time.UTC)
+	end := start.Add(1500 * time.Millisecond)
+
+	return []TraceFixture{
+		{
+			Name: "all_fields",
+			Data: consumerdata.TraceData{
+				Node: &commonpb.Node{
+					Identifier: &commonpb.ProcessIdentifier{
+						HostName:       "api-1.example.com",
+						Pid:            4242,
+						StartTimestamp: internal.TimeToTimestamp(start.Add(-time.Hour)),
+					},
+					LibraryInfo: &commonpb.LibraryInfo{
+						Language:           commonpb.LibraryInfo_GO_LANG,
+						ExporterVersion:    "0.1.0",
+						CoreLibraryVersion: "0.22.0",
+					},
+					ServiceInfo: &commonpb.ServiceInfo{Name: "api"},
+					Attributes:  map[string]string{"region": "eu-west-1"},
+				},
+				Resource: &resourcepb.Resource{
+					Type:   "k8s",
+					Labels: map[string]string{"k8s.pod.name": "api-1"},
+				},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:      []byte{0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8, 0xF9, 0xFA, 0xFB, 0xFC, 0xFD, 0xFE, 0xFF, 0x80},
+						SpanId:       []byte{0xAF, 0xAE, 0xAD, 0xAC, 0xAB, 0xAA, 0xA9, 0xA8},
+						ParentSpanId: []byte{0x1F, 0x1E, 0x1D, 0x1C, 0x1B, 0x1A, 0x19, 0x18},
+						Tracestate: &tracepb.Span_Tracestate{
+							Entries: []*tracepb.Span_Tracestate_Entry{{Key: "vendor", Value: "opaque"}},
+						},
+						Name:      &tracepb.TruncatableString{Value: "GET /orders"},
+						Kind:      tracepb.Span_SERVER,
+						StartTime: internal.TimeToTimestamp(start),
+						EndTime:   internal.TimeToTimestamp(end),
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"http.method": {
+									Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "GET"}},
+								},
+								"http.status_code": {
+									Value: &tracepb.AttributeValue_IntValue{IntValue: 200},
+								},
+								"cache.hit": {
+									Value: &tracepb.AttributeValue_BoolValue{BoolValue: true},
+								},
+								"load": {
+									Value: &tracepb.AttributeValue_DoubleValue{DoubleValue: 0.75},
+								},
+							},
+							DroppedAttributesCount: 2,
+						},
+						TimeEvents: &tracepb.Span_TimeEvents{
+							TimeEvent: []*tracepb.Span_TimeEvent{
+								{
+									Time: internal.TimeToTimestamp(start.Add(10 * time.Millisecond)),
+									Value: &tracepb.Span_TimeEvent_Annotation_{
+										Annotation: &tracepb.Span_TimeEvent_Annotation{
+											Description: &tracepb.TruncatableString{Value: "cache lookup"},
+											Attributes: &tracepb.Span_Attributes{
+												AttributeMap: map[string]*tracepb.AttributeValue{
+													"key": {
+														Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: "orders:42"}},
+													},
+												},
+											},
+										},
+									},
+								},
+								{
+									Time: internal.TimeToTimestamp(start.Add(20 * time.Millisecond)),
+									Value: &tracepb.Span_TimeEvent_MessageEvent_{
+										MessageEvent: &tracepb.Span_TimeEvent_MessageEvent{
+											Type:             tracepb.Span_TimeEvent_MessageEvent_RECEIVED,
+											Id:               7,
+											UncompressedSize: 1024,
+											CompressedSize:   512,
+										},
+									},
+								},
+							},
+						},
+						Links: &tracepb.Span_Links{
+							Link: []*tracepb.Span_Link{
+								{
+									TraceId: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10},
+									SpanId:  []byte{0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18},
+									Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
+								},
+								{
+									TraceId: []byte{0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F, 0x30},
+									SpanId:  []byte{0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38},
+									Type:    tracepb.Span_Link_CHILD_LINKED_SPAN,
+								},
+							},
+						},
+						Status:                  &tracepb.Status{Code: 0},
+						SameProcessAsParentSpan: &wrappers.BoolValue{Value: false},
+						ChildSpanCount:          &wrappers.UInt32Value{Value: 3},
+					},
+				},
+			},
+		},
+		{
+			Name: "error_status",
+			Data: consumerdata.TraceData{
+				Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "frontend"}},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:   []byte{0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19},
+						SpanId:    []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+						Name:      &tracepb.TruncatableString{Value: "POST /checkout"},
+						Kind:      tracepb.Span_CLIENT,
+						StartTime: internal.TimeToTimestamp(start),
+						EndTime:   internal.TimeToTimestamp(end),
+						Attributes: &tracepb.Span_Attributes{
+							AttributeMap: map[string]*tracepb.AttributeValue{
+								"http.status_code": {
+									Value: &tracepb.AttributeValue_IntValue{IntValue: 503},
+								},
+							},
+						},
+						Status: &tracepb.Status{Code: 14, Message: "upstream unavailable"},
+					},
+				},
+			},
+		},
+		{
+			Name: "64bit_trace_id",
+			Data: consumerdata.TraceData{
+				Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "legacy"}},
+				Spans: []*tracepb.Span{
+					{
+						TraceId:   []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98},
+						SpanId:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Name:      &tracepb.TruncatableString{Value: "poll"},
+						StartTime: internal.TimeToTimestamp(start),
+						EndTime:   internal.TimeToTimestamp(end),
+					},
+				},
+			},
+		},
+		{
+			Name: "minimal",
+			Data: consumerdata.TraceData{
+				Spans: []*tracepb.Span{
+					{
+						TraceId: []byte{0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF, 0xD0},
+						SpanId:  []byte{0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracetranslatortest provides the fixtures and the golden files
+// used to test the trace translators.
+package tracetranslatortest
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "write the golden files of the translator tests instead of comparing to them")
+
+// AssertGolden asserts that the JSON encoding of got is the content of the
+// golden file at path. When the tests run with -update-golden the file is
+// written instead, the changes must then be reviewed before they are
+// committed.
+func AssertGolden(t *testing.T, path string, got interface{}) {
+	blob, err := json.MarshalIndent(got, "", "  ")
+	require.NoError(t, err, "cannot encode the translated data")
+	blob = append(blob, '\n')
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, blob, 0644))
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	require.NoError(t, err, "cannot read the golden file, run the tests with -update-golden to create it")
+	assert.JSONEq(t, string(want), string(blob), "the translated data differs from the golden file %s", path)
+}
@@ -16,12 +16,39 @@ package zipkin
 
 import (
 	"math"
+	"strconv"
 
 	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 
 	tracetranslator "github.com/open-telemetry/opentelemetry-service/translator/trace"
 )
 
+// StatusOptions configure how the tags of the Zipkin spans map to the status
+// of the OC spans, since the Zipkin instrumentations don't agree on the tags
+// recording it. The "census.status_code" and "status.code" tags always map to
+// the status and are removed from the spans.
+type StatusOptions struct {
+	// ErrorTag maps the "error" tag, whose value is a canonical code name
+	// like "NOT_FOUND" or an error message, and the
+	// "opencensus.status_description" tag to the status.
+	ErrorTag bool `mapstructure:"error-tag"`
+	// HTTPStatusCode maps the "http.status_code" and "http.status_message"
+	// tags to the status of the spans with no other status tags.
+	HTTPStatusCode bool `mapstructure:"http-status-code"`
+	// StripTags removes the "error" and HTTP status tags mapped to the
+	// status from the spans.
+	StripTags bool `mapstructure:"strip-tags"`
+}
+
+// DefaultStatusOptions returns the options mapping all the status tags to
+// the status, and keeping them on the spans.
+func DefaultStatusOptions() StatusOptions {
+	return StatusOptions{
+		ErrorTag:       true,
+		HTTPStatusCode: true,
+	}
+}
+
 type status struct {
 	codePtr *int32
 	message string
@@ -29,17 +56,27 @@ type status struct {
 
 // statusMapper contains codes translated from different sources to OC status codes
 type statusMapper struct {
+	opts StatusOptions
 	// oc status code extracted from "status.code" tags
 	fromStatus status
 	// oc status code extracted from "census.status_code" tags
 	fromCensus status
+	// oc status code extracted from "error" tags
+	fromError status
+	// message of the "error" tags that are not canonical code names
+	errorMessage string
 	// oc status code extracted from "http.status_code" tags
 	fromHTTP status
 }
 
+func newStatusMapper(opts StatusOptions) *statusMapper {
+	return &statusMapper{opts: opts}
+}
+
 // ocStatus returns an OC status from the best possible extraction source.
 // It'll first try to return status extracted from "census.status_code" to account for zipkin
 // then fallback on code extracted from "status.code" tags
+// then on code extracted from the "error" tags
 // and finally fallback on code extracted and translated from "http.status_code"
 // ocStatus must be called after all tags/attributes are processed with the `fromAttribute` method.
 func (m *statusMapper) ocStatus() *tracepb.Status {
@@ -49,6 +86,16 @@ func (m *statusMapper) ocStatus() *tracepb.Status {
 		s = m.fromCensus
 	case m.fromStatus.codePtr != nil:
 		s = m.fromStatus
+	case m.fromError.codePtr != nil || m.fromError.message != "":
+		s = m.fromError
+		if s.codePtr == nil {
+			// Only the description was set, the code is unknown.
+			code := int32(tracetranslator.OCUnknown)
+			s.codePtr = &code
+		}
+		if s.message == "" {
+			s.message = m.errorMessage
+		}
 	default:
 		s = m.fromHTTP
 	}
@@ -66,6 +113,8 @@ func (m *statusMapper) ocStatus() *tracepb.Status {
 	return nil
 }
 
+// fromAttribute records the status of the attribute and returns true if the
+// attribute must be dropped from the span.
 func (m *statusMapper) fromAttribute(key string, attrib *tracepb.AttributeValue) bool {
 	switch key {
 	case tracetranslator.TagZipkinCensusCode:
@@ -84,23 +133,70 @@ func (m *statusMapper) fromAttribute(key string, attrib *tracepb.AttributeValue)
 		m.fromStatus.message = attrib.GetStringValue().GetValue()
 		return true
 
+	case tracetranslator.TagError:
+		if !m.opts.ErrorTag {
+			return false
+		}
+		m.fromError.codePtr, m.errorMessage = errorAttribToStatus(attrib)
+		return m.opts.StripTags
+
+	case tracetranslator.TagZipkinOCStatusDesc:
+		if !m.opts.ErrorTag {
+			return false
+		}
+		m.fromError.message = attrib.GetStringValue().GetValue()
+		return m.opts.StripTags
+
 	case tracetranslator.TagHTTPStatusCode:
+		if !m.opts.HTTPStatusCode {
+			return false
+		}
 		codePtr := attribToStatusCode(attrib)
 		if codePtr != nil {
 			*codePtr = tracetranslator.OCStatusCodeFromHTTP(*codePtr)
 			m.fromHTTP.codePtr = codePtr
 		}
+		return m.opts.StripTags
 
 	case tracetranslator.TagHTTPStatusMsg:
+		if !m.opts.HTTPStatusCode {
+			return false
+		}
 		m.fromHTTP.message = attrib.GetStringValue().GetValue()
+		return m.opts.StripTags
 	}
 	return false
 }
 
+// StatusFromAttributes returns the status mapped from the attributes of a
+// span translated from a Zipkin span, and removes the mapped attributes
+// according to the options.
+func StatusFromAttributes(attributes *tracepb.Span_Attributes, opts StatusOptions) *tracepb.Status {
+	if attributes == nil {
+		return nil
+	}
+	m := newStatusMapper(opts)
+	for key, attrib := range attributes.AttributeMap {
+		if drop := m.fromAttribute(key, attrib); drop {
+			delete(attributes.AttributeMap, key)
+		}
+	}
+	return m.ocStatus()
+}
+
 // attribToStatusCode maps an integer attribute value to a status code (int32).
 // The function return nil if the value is not an integer or an integer larger than what
 // can fit in an int32
 func attribToStatusCode(v *tracepb.AttributeValue) *int32 {
+	if s, ok := v.GetValue().(*tracepb.AttributeValue_StringValue); ok {
+		// The Zipkin v2 tags are strings.
+		i, err := strconv.ParseInt(s.StringValue.GetValue(), 10, 32)
+		if err != nil {
+			return nil
+		}
+		j := int32(i)
+		return &j
+	}
 	i := v.GetIntValue()
 	if i <= math.MaxInt32 {
 		j := int32(i)
@@ -108,3 +204,45 @@ func attribToStatusCode(v *tracepb.AttributeValue) *int32 {
 	}
 	return nil
 }
+
+// errorAttribToStatus maps the value of an "error" attribute to a status
+// code, and to a message if it isn't a canonical code name. The function
+// returns a nil code if the value says there is no error.
+func errorAttribToStatus(v *tracepb.AttributeValue) (*int32, string) {
+	var value string
+	switch v := v.GetValue().(type) {
+	case *tracepb.AttributeValue_BoolValue:
+		if !v.BoolValue {
+			return nil, ""
+		}
+	case *tracepb.AttributeValue_StringValue:
+		value = v.StringValue.GetValue()
+	}
+
+	if code, ok := canonicalCodesMap[value]; ok {
+		return &code, ""
+	}
+	code := int32(tracetranslator.OCUnknown)
+	return &code, value
+}
+
+var canonicalCodesMap = map[string]int32{
+	// https://github.com/googleapis/googleapis/blob/bee79fbe03254a35db125dc6d2f1e9b752b390fe/google/rpc/code.proto#L33-L186
+	"OK":                  tracetranslator.OCOK,
+	"CANCELLED":           tracetranslator.OCCancelled,
+	"UNKNOWN":             tracetranslator.OCUnknown,
+	"INVALID_ARGUMENT":    tracetranslator.OCInvalidArgument,
+	"DEADLINE_EXCEEDED":   tracetranslator.OCDeadlineExceeded,
+	"NOT_FOUND":           tracetranslator.OCNotFound,
+	"ALREADY_EXISTS":      tracetranslator.OCAlreadyExists,
+	"PERMISSION_DENIED":   tracetranslator.OCPermissionDenied,
+	"RESOURCE_EXHAUSTED":  tracetranslator.OCResourceExhausted,
+	"FAILED_PRECONDITION": tracetranslator.OCFailedPrecondition,
+	"ABORTED":             tracetranslator.OCAborted,
+	"OUT_OF_RANGE":        tracetranslator.OCOutOfRange,
+	"UNIMPLEMENTED":       tracetranslator.OCUnimplemented,
+	"INTERNAL":            tracetranslator.OCInternal,
+	"UNAVAILABLE":         tracetranslator.OCUnavailable,
+	"DATA_LOSS":           tracetranslator.OCDataLoss,
+	"UNAUTHENTICATED":     tracetranslator.OCUnauthenticated,
+}
@@ -0,0 +1,100 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zipkin
+
+import (
+	"testing"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusFromAttributes(t *testing.T) {
+	stringAttribute := func(value string) *tracepb.AttributeValue {
+		return &tracepb.AttributeValue{
+			Value: &tracepb.AttributeValue_StringValue{
+				StringValue: &tracepb.TruncatableString{Value: value},
+			},
+		}
+	}
+	attributes := func() *tracepb.Span_Attributes {
+		return &tracepb.Span_Attributes{
+			AttributeMap: map[string]*tracepb.AttributeValue{
+				"error":               {Value: &tracepb.AttributeValue_BoolValue{BoolValue: true}},
+				"http.status_code":    stringAttribute("503"),
+				"http.status_message": stringAttribute("Service Unavailable"),
+				"http.url":            stringAttribute("http://localhost/"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     StatusOptions
+		wantKeys []string
+		want     *tracepb.Status
+	}{
+		{
+			name:     "default",
+			opts:     DefaultStatusOptions(),
+			wantKeys: []string{"error", "http.status_code", "http.status_message", "http.url"},
+			want:     &tracepb.Status{Code: 2},
+		},
+		{
+			name:     "http",
+			opts:     StatusOptions{HTTPStatusCode: true},
+			wantKeys: []string{"error", "http.status_code", "http.status_message", "http.url"},
+			want:     &tracepb.Status{Code: 14, Message: "Service Unavailable"},
+		},
+		{
+			name:     "strip",
+			opts:     StatusOptions{ErrorTag: true, HTTPStatusCode: true, StripTags: true},
+			wantKeys: []string{"http.url"},
+			want:     &tracepb.Status{Code: 2},
+		},
+		{
+			name:     "none",
+			opts:     StatusOptions{StripTags: true},
+			wantKeys: []string{"error", "http.status_code", "http.status_message", "http.url"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := attributes()
+			assert.Equal(t, tt.want, StatusFromAttributes(attrs, tt.opts))
+			var keys []string
+			for key := range attrs.AttributeMap {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.wantKeys, keys)
+		})
+	}
+}
+
+func TestErrorAttribToStatus(t *testing.T) {
+	code, message := errorAttribToStatus(&tracepb.AttributeValue{
+		Value: &tracepb.AttributeValue_BoolValue{BoolValue: false},
+	})
+	assert.Nil(t, code)
+	assert.Equal(t, "", message)
+
+	code, message = errorAttribToStatus(&tracepb.AttributeValue{
+		Value: &tracepb.AttributeValue_StringValue{
+			StringValue: &tracepb.TruncatableString{Value: "DEADLINE_EXCEEDED"},
+		},
+	})
+	assert.Equal(t, int32(4), *code)
+	assert.Equal(t, "", message)
+}
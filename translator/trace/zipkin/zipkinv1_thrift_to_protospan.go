@@ -33,9 +33,15 @@ import (
 
 // V1ThriftBatchToOCProto converts Zipkin v1 spans to OC Proto.
 func V1ThriftBatchToOCProto(zSpans []*zipkincore.Span) ([]consumerdata.TraceData, error) {
+	return V1ThriftBatchToOCProtoWithOptions(zSpans, DefaultStatusOptions())
+}
+
+// V1ThriftBatchToOCProtoWithOptions converts Zipkin v1 spans to OC Proto,
+// mapping their tags to the status of the spans according to opts.
+func V1ThriftBatchToOCProtoWithOptions(zSpans []*zipkincore.Span, opts StatusOptions) ([]consumerdata.TraceData, error) {
 	ocSpansAndParsedAnnotations := make([]ocSpanAndParsedAnnotations, 0, len(zSpans))
 	for _, zSpan := range zSpans {
-		ocSpan, parsedAnnotations, err := zipkinV1ThriftToOCSpan(zSpan)
+		ocSpan, parsedAnnotations, err := zipkinV1ThriftToOCSpan(zSpan, opts)
 		if err != nil {
 			// error from internal package function, it already wraps the error to give better context.
 			return nil, err
@@ -49,7 +55,7 @@ func V1ThriftBatchToOCProto(zSpans []*zipkincore.Span) ([]consumerdata.TraceData
 	return zipkinToOCProtoBatch(ocSpansAndParsedAnnotations)
 }
 
-func zipkinV1ThriftToOCSpan(zSpan *zipkincore.Span) (*tracepb.Span, *annotationParseResult, error) {
+func zipkinV1ThriftToOCSpan(zSpan *zipkincore.Span, opts StatusOptions) (*tracepb.Span, *annotationParseResult, error) {
 	traceIDHigh := int64(0)
 	if zSpan.TraceIDHigh != nil {
 		traceIDHigh = *zSpan.TraceIDHigh
@@ -68,7 +74,7 @@ func zipkinV1ThriftToOCSpan(zSpan *zipkincore.Span) (*tracepb.Span, *annotationP
 	}
 
 	parsedAnnotations := parseZipkinV1ThriftAnnotations(zSpan.Annotations)
-	attributes, ocStatus, localComponent := zipkinV1ThriftBinAnnotationsToOCAttributes(zSpan.BinaryAnnotations)
+	attributes, ocStatus, localComponent := zipkinV1ThriftBinAnnotationsToOCAttributes(zSpan.BinaryAnnotations, opts)
 	if parsedAnnotations.Endpoint.ServiceName == unknownServiceName && localComponent != "" {
 		parsedAnnotations.Endpoint.ServiceName = localComponent
 	}
@@ -140,12 +146,12 @@ func toTranslatorEndpoint(e *zipkincore.Endpoint) *endpoint {
 
 var trueByteSlice = []byte{1}
 
-func zipkinV1ThriftBinAnnotationsToOCAttributes(ztBinAnnotations []*zipkincore.BinaryAnnotation) (attributes *tracepb.Span_Attributes, status *tracepb.Status, fallbackServiceName string) {
+func zipkinV1ThriftBinAnnotationsToOCAttributes(ztBinAnnotations []*zipkincore.BinaryAnnotation, opts StatusOptions) (attributes *tracepb.Span_Attributes, status *tracepb.Status, fallbackServiceName string) {
 	if len(ztBinAnnotations) == 0 {
 		return nil, nil, ""
 	}
 
-	sMapper := &statusMapper{}
+	sMapper := newStatusMapper(opts)
 	var localComponent string
 	attributeMap := make(map[string]*tracepb.AttributeValue)
 	for _, binaryAnnotation := range ztBinAnnotations {
@@ -85,6 +85,13 @@ type binaryAnnotation struct {
 
 // V1JSONBatchToOCProto converts a JSON blob with a list of Zipkin v1 spans to OC Proto.
 func V1JSONBatchToOCProto(blob []byte) ([]consumerdata.TraceData, error) {
+	return V1JSONBatchToOCProtoWithOptions(blob, DefaultStatusOptions())
+}
+
+// V1JSONBatchToOCProtoWithOptions converts a JSON blob with a list of Zipkin
+// v1 spans to OC Proto, mapping their tags to the status of the spans
+// according to opts.
+func V1JSONBatchToOCProtoWithOptions(blob []byte, opts StatusOptions) ([]consumerdata.TraceData, error) {
 	var zSpans []*zipkinV1Span
 	if err := json.Unmarshal(blob, &zSpans); err != nil {
 		return nil, errors.WithMessage(err, msgZipkinV1JSONUnmarshalError)
@@ -92,7 +99,7 @@ func V1JSONBatchToOCProto(blob []byte) ([]consumerdata.TraceData, error) {
 
 	ocSpansAndParsedAnnotations := make([]ocSpanAndParsedAnnotations, 0, len(zSpans))
 	for _, zSpan := range zSpans {
-		ocSpan, parsedAnnotations, err := zipkinV1ToOCSpan(zSpan)
+		ocSpan, parsedAnnotations, err := zipkinV1ToOCSpan(zSpan, opts)
 		if err != nil {
 			// error from internal package function, it already wraps the error to give better context.
 			return nil, err
@@ -126,7 +133,7 @@ func zipkinToOCProtoBatch(ocSpansAndParsedAnnotations []ocSpanAndParsedAnnotatio
 	return tds, nil
 }
 
-func zipkinV1ToOCSpan(zSpan *zipkinV1Span) (*tracepb.Span, *annotationParseResult, error) {
+func zipkinV1ToOCSpan(zSpan *zipkinV1Span, opts StatusOptions) (*tracepb.Span, *annotationParseResult, error) {
 	traceID, err := hexTraceIDToOCTraceID(zSpan.TraceID)
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, msgZipkinV1TraceIDError)
@@ -145,7 +152,7 @@ func zipkinV1ToOCSpan(zSpan *zipkinV1Span) (*tracepb.Span, *annotationParseResul
 	}
 
 	parsedAnnotations := parseZipkinV1Annotations(zSpan.Annotations)
-	attributes, ocStatus, localComponent := zipkinV1BinAnnotationsToOCAttributes(zSpan.BinaryAnnotations)
+	attributes, ocStatus, localComponent := zipkinV1BinAnnotationsToOCAttributes(zSpan.BinaryAnnotations, opts)
 	if parsedAnnotations.Endpoint.ServiceName == unknownServiceName && localComponent != "" {
 		parsedAnnotations.Endpoint.ServiceName = localComponent
 	}
@@ -177,12 +184,12 @@ func zipkinV1ToOCSpan(zSpan *zipkinV1Span) (*tracepb.Span, *annotationParseResul
 	return ocSpan, parsedAnnotations, nil
 }
 
-func zipkinV1BinAnnotationsToOCAttributes(binAnnotations []*binaryAnnotation) (attributes *tracepb.Span_Attributes, status *tracepb.Status, fallbackServiceName string) {
+func zipkinV1BinAnnotationsToOCAttributes(binAnnotations []*binaryAnnotation, opts StatusOptions) (attributes *tracepb.Span_Attributes, status *tracepb.Status, fallbackServiceName string) {
 	if len(binAnnotations) == 0 {
 		return nil, nil, ""
 	}
 
-	sMapper := &statusMapper{}
+	sMapper := newStatusMapper(opts)
 	var localComponent string
 	attributeMap := make(map[string]*tracepb.AttributeValue)
 	for _, binAnnotation := range binAnnotations {
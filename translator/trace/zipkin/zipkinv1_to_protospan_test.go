@@ -449,6 +449,70 @@ func TestZipkinAnnotationsToOCStatus(t *testing.T) {
 			},
 		},
 
+		// error tag with a canonical code
+		{
+			haveTags: []*binaryAnnotation{
+				{
+					Key:   "error",
+					Value: "NOT_FOUND",
+				},
+				{
+					Key:   "opencensus.status_description",
+					Value: "Missing",
+				},
+			},
+			wantAttributes: &tracepb.Span_Attributes{
+				AttributeMap: map[string]*tracepb.AttributeValue{
+					tracetranslator.TagError: {
+						Value: &tracepb.AttributeValue_StringValue{
+							StringValue: &tracepb.TruncatableString{Value: "NOT_FOUND"},
+						},
+					},
+					tracetranslator.TagZipkinOCStatusDesc: {
+						Value: &tracepb.AttributeValue_StringValue{
+							StringValue: &tracepb.TruncatableString{Value: "Missing"},
+						},
+					},
+				},
+			},
+			wantStatus: &tracepb.Status{
+				Code:    5,
+				Message: "Missing",
+			},
+		},
+
+		// error tag with a message, priority over http
+		{
+			haveTags: []*binaryAnnotation{
+				{
+					Key:   "error",
+					Value: "connection refused",
+				},
+				{
+					Key:   "http.status_code",
+					Value: "404",
+				},
+			},
+			wantAttributes: &tracepb.Span_Attributes{
+				AttributeMap: map[string]*tracepb.AttributeValue{
+					tracetranslator.TagError: {
+						Value: &tracepb.AttributeValue_StringValue{
+							StringValue: &tracepb.TruncatableString{Value: "connection refused"},
+						},
+					},
+					tracetranslator.TagHTTPStatusCode: {
+						Value: &tracepb.AttributeValue_IntValue{
+							IntValue: 404,
+						},
+					},
+				},
+			},
+			wantStatus: &tracepb.Status{
+				Code:    2,
+				Message: "connection refused",
+			},
+		},
+
 		// census tags priority over others
 		{
 			haveTags: []*binaryAnnotation{